@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gluster/glusterd2/pkg/api"
+	bitrotapi "github.com/gluster/glusterd2/plugins/bitrot/api"
 
 	"github.com/stretchr/testify/require"
 )
@@ -138,6 +139,29 @@ func testbitrot(t *testing.T) {
 	r.Nil(err)
 	r.Equal(scrubStatus.State, "Active (Idle)")
 
+	//configure scrubber
+	err = client.BitrotScrubConfig(volumeName, bitrotapi.ScrubConfigReq{Frequency: "daily", Throttle: "aggressive"})
+	r.Nil(err)
+
+	err = client.BitrotScrubConfig(volumeName, bitrotapi.ScrubConfigReq{Frequency: "yearly"})
+	r.NotNil(err)
+
+	scrubStatus, err = client.BitrotScrubStatus(volumeName)
+	r.Nil(err)
+	r.Equal(scrubStatus.Frequency, "daily")
+	r.Equal(scrubStatus.Throttle, "aggressive")
+
+	//pause and resume scrubber
+	err = client.BitrotScrubPause(volumeName)
+	r.Nil(err)
+
+	scrubStatus, err = client.BitrotScrubStatus(volumeName)
+	r.Nil(err)
+	r.Equal(scrubStatus.State, "Paused")
+
+	err = client.BitrotScrubResume(volumeName)
+	r.Nil(err)
+
 	//disable bitrot on volume
 	err = client.BitrotDisable(volumeName)
 	r.Nil(err)
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/gluster/glusterd2/pkg/api"
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
 
 	"github.com/stretchr/testify/require"
 )
@@ -65,6 +66,7 @@ func TestQuota(t *testing.T) {
 	r.Nil(err)
 
 	// test Quota on dist-rep volume
+	volname = volumeName
 	t.Run("Quota-enable", tc.wrap(testQuotaEnable))
 
 	r.Nil(client.VolumeDelete(volumeName))
@@ -77,55 +79,77 @@ func testQuotaEnable(t *testing.T, tc *testCluster) {
 	// form the pidfile path
 	pidpath := path.Join(tc.gds[0].Rundir, "quotad.pid")
 
-	quotaKey := "quota.enable"
-	var optionReqOff api.VolOptionReq
-	optionReqOff.AllowAdvanced = true
+	// Quota can't be enabled on a volume which isn't started
+	err = client.QuotaEnable(volname)
+	r.Contains(err.Error(), "volume not started")
 
-	optionReqOff.Options = map[string]string{quotaKey: "off"}
+	r.Nil(client.VolumeStart(volname, true), "Volume start failed")
 
 	// Quota not enabled: no quotad should be there
-	err = client.VolumeSet(volname, optionReqOff)
-	r.Contains(err.Error(), "quotad is not enabled")
+	err = client.QuotaDisable(volname)
+	r.Contains(err.Error(), "quota is not enabled")
 
 	// Checking if the quotad is not running
 	r.False(isProcessRunning(pidpath))
 
+	// Quota can't be enabled with volume set
 	var optionReqOn api.VolOptionReq
 	optionReqOn.AllowAdvanced = true
+	optionReqOn.Options = map[string]string{"quota.enable": "on"}
+	r.NotNil(client.VolumeSet(volname, optionReqOn))
 
-	// Enable quota
-	quotaKey = "quota.enable"
-	optionReqOn.Options = map[string]string{quotaKey: "on"}
-	// Quotad should be there
-	r.Nil(client.VolumeSet(volname, optionReqOn))
+	// Enable quota, quotad should be there
+	r.Nil(client.QuotaEnable(volname))
 
 	// Checking if the quotad is running
 	r.True(isProcessRunning(pidpath))
 
 	// check the error for enabling it again
-	err = client.VolumeSet(volname, optionReqOn)
-	r.Contains(err.Error(), "process is already running")
+	err = client.QuotaEnable(volname)
+	r.Contains(err.Error(), "quota is already enabled")
+
+	// Limits can only be set on directories which exist
+	err = client.QuotaLimitSet(volname, quotaapi.SetLimitReq{Path: "/nodir", SizeUsageLimit: 1024})
+	r.NotNil(err)
+
+	// Set limits on the volume root
+	r.Nil(client.QuotaLimitSet(volname, quotaapi.SetLimitReq{
+		Path:             "/",
+		SizeUsageLimit:   1024 * 1024,
+		ObjectCountLimit: 100,
+		SoftLimitPercent: 50,
+	}))
+
+	limits, err := client.QuotaList(volname)
+	r.Nil(err)
+	r.Len(limits, 2)
+	for _, l := range limits {
+		r.Equal("/", l.Path)
+		r.Equal(l.HardLimit/2, l.SoftLimit)
+	}
 
-	// Checking if the quotad is running
-	r.True(isProcessRunning(pidpath))
+	// Remove the limits
+	r.Nil(client.QuotaLimitRemove(volname, "/"))
+	limits, err = client.QuotaList(volname)
+	r.Nil(err)
+	r.Len(limits, 0)
+
+	err = client.QuotaLimitRemove(volname, "/")
+	r.Contains(err.Error(), "quota limit is not set on the directory")
 
 	// Disable quota
-	r.Nil(client.VolumeSet(volname, optionReqOff))
+	r.Nil(client.QuotaDisable(volname))
 
 	// Checking if the quotad is not running
 	r.False(isProcessRunning(pidpath))
 
 	// Check the error for disabling it again.
-	err = client.VolumeSet(volname, optionReqOff)
-	r.Contains(err.Error(), "quotad is not enabled")
+	err = client.QuotaDisable(volname)
+	r.Contains(err.Error(), "quota is not enabled")
 
 	// Checking if the quotad is not running
 	r.False(isProcessRunning(pidpath))
 
 	// Stop Volume
 	r.Nil(client.VolumeStop(volname), "Volume stop failed")
-	// delete volume
-	err = client.VolumeDelete(volname)
-	r.Nil(err)
-
 }
@@ -3,6 +3,10 @@ package cmd
 import (
 	"fmt"
 	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/restclient"
+	bitrotapi "github.com/gluster/glusterd2/plugins/bitrot/api"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -15,6 +19,8 @@ const (
 	helpBitrotScrubThrottleCmd  = "Configure Scrub Throttle"
 	helpBitrotScrubFrequencyCmd = "Configure Scrub Frequency"
 	helpBitrotScrubCmd          = "Bitrot Scrub Command"
+	helpBitrotCorruptedCmd      = "List Corrupted Objects"
+	helpBitrotRepairCmd         = "Repair Corrupted Object From A Good Copy"
 )
 
 const (
@@ -40,6 +46,12 @@ func init() {
 	// Bitrot scrub command
 	bitrotCmd.AddCommand(bitrotScrubCmd)
 
+	// List corrupted objects
+	bitrotCmd.AddCommand(bitrotCorruptedCmd)
+
+	// Repair corrupted object
+	bitrotCmd.AddCommand(bitrotRepairCmd)
+
 }
 
 var bitrotCmd = &cobra.Command{
@@ -88,13 +100,8 @@ var bitrotScrubThrottleCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		volname := args[0]
-		var option []string
-		option = append(option, "bit-rot.scrub-throttle")
-		option = append(option, args[1])
-
-		// Set Option set flag to advanced
-		flagSetAdv = true
-		err := volumeOptionJSONHandler(cmd, volname, option)
+		req := bitrotapi.ScrubConfigReq{Throttle: args[1]}
+		err := client.BitrotScrubConfig(volname, req)
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithFields(log.Fields{
@@ -114,13 +121,8 @@ var bitrotScrubFrequencyCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		volname := args[0]
-		var option []string
-		option = append(option, "bit-rot.scrub-freq")
-		option = append(option, args[1])
-
-		// Set Option set flag to advanced
-		flagSetAdv = true
-		err := volumeOptionJSONHandler(cmd, volname, option)
+		req := bitrotapi.ScrubConfigReq{Frequency: args[1]}
+		err := client.BitrotScrubConfig(volname, req)
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithFields(log.Fields{
@@ -140,16 +142,15 @@ var bitrotScrubCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		volname := args[0]
-		var option []string
 
 		switch scrubCmd := args[1]; scrubCmd {
 		case scrubPause, scrubResume:
-			option = append(option, "bit-rot.scrub-state")
-			option = append(option, args[1])
-
-			// Set Option set flag to advanced
-			flagSetAdv = true
-			err := volumeOptionJSONHandler(cmd, volname, option)
+			var err error
+			if scrubCmd == scrubPause {
+				err = client.BitrotScrubPause(volname)
+			} else {
+				err = client.BitrotScrubResume(volname)
+			}
 			if err != nil {
 				if GlobalFlag.Verbose {
 					log.WithError(err).WithFields(log.Fields{
@@ -208,6 +209,10 @@ var bitrotScrubCmd = &cobra.Command{
 
 		case scrubOndemand:
 			err := client.BitrotScrubOndemand(volname)
+			if err == restclient.ErrIOOpQueued {
+				fmt.Printf("Bitrot scrub on demand queued for volume %s until the conflicting operations on its bricks complete\n", volname)
+				return
+			}
 			if err != nil {
 				if GlobalFlag.Verbose {
 					log.WithError(err).WithField(
@@ -224,3 +229,48 @@ var bitrotScrubCmd = &cobra.Command{
 
 	},
 }
+
+var bitrotCorruptedCmd = &cobra.Command{
+	Use:   "corrupted <volname>",
+	Short: helpBitrotCorruptedCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		objects, err := client.BitrotCorrupted(volname)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("volume", volname).Error("failed to list corrupted objects")
+			}
+			failure(fmt.Sprintf("Failed to list corrupted objects of volume %s\n", volname), err, 1)
+		}
+		if len(objects.Objects) == 0 {
+			fmt.Printf("No corrupted objects found in volume %s\n", volname)
+			return
+		}
+		for _, object := range objects.Objects {
+			// TODO: Convert node id into hostname
+			fmt.Printf("GFID: %s\n", object.GFID)
+			fmt.Printf("Nodes: %s\n\n", strings.Join(object.Nodes, ", "))
+		}
+	},
+}
+
+var bitrotRepairCmd = &cobra.Command{
+	Use:   "repair <volname> <gfid>",
+	Short: helpBitrotRepairCmd,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname, gfid := args[0], args[1]
+		resp, err := client.BitrotRepair(volname, gfid)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithFields(log.Fields{
+					"volume": volname,
+					"gfid":   gfid,
+				}).Error("failed to repair corrupted object")
+			}
+			failure(fmt.Sprintf("Failed to repair corrupted object %s of volume %s\n", gfid, volname), err, 1)
+		}
+		fmt.Printf("Corrupted object %s repaired successfully on bricks: %s\n", gfid, strings.Join(resp.Bricks, ", "))
+	},
+}
@@ -19,13 +19,21 @@ var (
 `
 )
 
-func initRESTClient(hostname, user, secret, cacert string, insecure bool) {
+// initRESTClient sets up the REST client to fail over between the given
+// glusterd2 endpoints
+func initRESTClient(endpoints []string, user, secret, cacert string, insecure bool) {
 	var err error
-	client, err = restclient.New(hostname, user, secret, cacert, insecure)
+	client, err = restclient.NewClientWithOpts(
+		restclient.WithEndpoints(endpoints...),
+		restclient.WithTLSConfig(&restclient.TLSOptions{CaCertFile: cacert, InsecureSkipVerify: insecure}),
+		restclient.WithUsername(user),
+		restclient.WithPassword(secret),
+		restclient.WithTimeOut(time.Duration(GlobalFlag.Timeout)*time.Second),
+		restclient.WithDebugRoundTripper(),
+	)
 	if err != nil {
 		failure("failed to setup client", err, 1)
 	}
-	client.SetTimeout(time.Duration(GlobalFlag.Timeout) * time.Second)
 }
 
 func isConnectionRefusedErr(err error) bool {
@@ -54,7 +62,7 @@ func handleGlusterdConnectFailure(msg, endpoints string, err error, errcode int)
 
 func failure(msg string, err error, errcode int) {
 
-	handleGlusterdConnectFailure(msg, GlobalFlag.Endpoints[0], err, errcode)
+	handleGlusterdConnectFailure(msg, strings.Join(GlobalFlag.Endpoints, ", "), err, errcode)
 
 	w := os.Stderr
 
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	helpCompletionCmd     = "Generate shell completion and command descriptions"
+	helpCompletionBashCmd = "Generate bash completion script"
+	helpCompletionZshCmd  = "Generate zsh completion script"
+	helpCompletionFishCmd = "Generate fish completion script"
+	helpCompletionJSONCmd = "Describe all commands and flags in JSON"
+)
+
+var (
+	flagCompletionJSONServerEndpoints bool
+)
+
+func init() {
+	completionJSONCmd.Flags().BoolVar(&flagCompletionJSONServerEndpoints, "server-endpoints", false,
+		"Include the REST endpoints served by glusterd2")
+
+	completionCmd.AddCommand(completionBashCmd)
+	completionCmd.AddCommand(completionZshCmd)
+	completionCmd.AddCommand(completionFishCmd)
+	completionCmd.AddCommand(completionJSONCmd)
+}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion",
+	Short: helpCompletionCmd,
+}
+
+var completionBashCmd = &cobra.Command{
+	Use:   "bash",
+	Short: helpCompletionBashCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cmd.Root().GenBashCompletion(os.Stdout); err != nil {
+			failure("Failed to generate bash completion", err, 1)
+		}
+	},
+}
+
+var completionZshCmd = &cobra.Command{
+	Use:   "zsh",
+	Short: helpCompletionZshCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cmd.Root().GenZshCompletion(os.Stdout); err != nil {
+			failure("Failed to generate zsh completion", err, 1)
+		}
+	},
+}
+
+var completionFishCmd = &cobra.Command{
+	Use:   "fish",
+	Short: helpCompletionFishCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		genFishCompletion(cmd.Root(), os.Stdout)
+	},
+}
+
+var completionJSONCmd = &cobra.Command{
+	Use:   "json",
+	Short: helpCompletionJSONCmd,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		tree := commandTree{commandDesc: describeCommand(cmd.Root())}
+		if flagCompletionJSONServerEndpoints {
+			endpoints, err := client.ListEndpoints()
+			if err != nil {
+				failure("Failed to get glusterd2 endpoints", err, 1)
+			}
+			tree.ServerEndpoints = endpoints
+		}
+
+		out, err := json.MarshalIndent(tree, "", "  ")
+		if err != nil {
+			failure("Failed to describe commands", err, 1)
+		}
+		fmt.Println(string(out))
+	},
+}
+
+// commandTree is the machine readable description of glustercli
+type commandTree struct {
+	commandDesc
+	// ServerEndpoints are the REST endpoints served by glusterd2, which the
+	// commands are implemented with
+	ServerEndpoints api.ListEndpointsResp `json:"server-endpoints,omitempty"`
+}
+
+type commandDesc struct {
+	Name     string        `json:"name"`
+	Use      string        `json:"use"`
+	Short    string        `json:"short,omitempty"`
+	Aliases  []string      `json:"aliases,omitempty"`
+	Flags    []flagDesc    `json:"flags,omitempty"`
+	Commands []commandDesc `json:"commands,omitempty"`
+}
+
+type flagDesc struct {
+	Name       string `json:"name"`
+	Shorthand  string `json:"shorthand,omitempty"`
+	Type       string `json:"type"`
+	Default    string `json:"default,omitempty"`
+	Usage      string `json:"usage,omitempty"`
+	Persistent bool   `json:"persistent,omitempty"`
+}
+
+// visibleCommands returns the sub-commands of cmd shown to users
+func visibleCommands(cmd *cobra.Command) []*cobra.Command {
+	var cmds []*cobra.Command
+	for _, c := range cmd.Commands() {
+		if c.IsAvailableCommand() {
+			cmds = append(cmds, c)
+		}
+	}
+	return cmds
+}
+
+// visibleFlags returns the flags defined on cmd, excluding inherited flags
+func visibleFlags(cmd *cobra.Command) []flagDesc {
+	var flags []flagDesc
+	persistent := cmd.PersistentFlags()
+	cmd.NonInheritedFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		flags = append(flags, flagDesc{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       f.Value.Type(),
+			Default:    f.DefValue,
+			Usage:      f.Usage,
+			Persistent: persistent.Lookup(f.Name) != nil,
+		})
+	})
+	return flags
+}
+
+func describeCommand(cmd *cobra.Command) commandDesc {
+	d := commandDesc{
+		Name:    cmd.Name(),
+		Use:     cmd.Use,
+		Short:   cmd.Short,
+		Aliases: cmd.Aliases,
+		Flags:   visibleFlags(cmd),
+	}
+	for _, c := range visibleCommands(cmd) {
+		d.Commands = append(d.Commands, describeCommand(c))
+	}
+	return d
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.Replace(strings.Replace(s, `\`, `\\`, -1), "'", `\'`, -1) + "'"
+}
+
+// genFishCompletion writes a fish completion script for the command tree of
+// root. Sub-commands and flags are completed based on the sub-commands already
+// present on the command line.
+func genFishCompletion(root *cobra.Command, w io.Writer) {
+	name := root.Name()
+	fn := "__" + name + "_using_path"
+
+	fmt.Fprintf(w, "# fish completion for %s\n\n", name)
+	fmt.Fprintf(w, "function %s\n", fn)
+	fmt.Fprintf(w, "    set -l words (commandline -opc)\n")
+	fmt.Fprintf(w, "    set -e words[1]\n")
+	fmt.Fprintf(w, "    set -l path\n")
+	fmt.Fprintf(w, "    for word in $words\n")
+	fmt.Fprintf(w, "        if not string match -q -- '-*' $word\n")
+	fmt.Fprintf(w, "            set path $path $word\n")
+	fmt.Fprintf(w, "        end\n")
+	fmt.Fprintf(w, "    end\n")
+	fmt.Fprintf(w, "    test \"$path\" = \"$argv\"\n")
+	fmt.Fprintf(w, "end\n\n")
+	fmt.Fprintf(w, "complete -c %s -f\n", name)
+
+	var walk func(cmd *cobra.Command, path []string)
+	walk = func(cmd *cobra.Command, path []string) {
+		cond := fishQuote(strings.TrimSpace(fn + " " + strings.Join(path, " ")))
+		for _, f := range visibleFlags(cmd) {
+			line := fmt.Sprintf("complete -c %s", name)
+			// Persistent flags of the root command apply everywhere
+			if len(path) > 0 || !f.Persistent {
+				line += " -n " + cond
+			}
+			line += " -l " + f.Name
+			if f.Shorthand != "" {
+				line += " -s " + f.Shorthand
+			}
+			if f.Type != "bool" {
+				line += " -r"
+			}
+			if f.Usage != "" {
+				line += " -d " + fishQuote(f.Usage)
+			}
+			fmt.Fprintln(w, line)
+		}
+		for _, c := range visibleCommands(cmd) {
+			line := fmt.Sprintf("complete -c %s -n %s -a %s", name, cond, c.Name())
+			if c.Short != "" {
+				line += " -d " + fishQuote(c.Short)
+			}
+			fmt.Fprintln(w, line)
+			walk(c, append(append([]string{}, path...), c.Name()))
+		}
+	}
+	walk(root, nil)
+}
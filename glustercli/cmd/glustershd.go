@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/gluster/glusterd2/pkg/restclient"
 	glustershdapi "github.com/gluster/glusterd2/plugins/glustershd/api"
 
 	log "github.com/sirupsen/logrus"
@@ -121,6 +122,10 @@ var selfHealFullCmd = &cobra.Command{
 		var err error
 		volname := args[0]
 		err = client.SelfHeal(volname, "full")
+		if err == restclient.ErrIOOpQueued {
+			fmt.Println("Full heal on volume has been queued until the conflicting operations on its bricks complete")
+			return
+		}
 		if err != nil {
 			failure(fmt.Sprintf("Failed to run heal for volume %s\n", volname), err, 1)
 		}
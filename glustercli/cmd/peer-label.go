@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpPeerLabelCmd       = "manage labels of peers, used to select peers in placement and in commands run on several peers"
+	helpPeerLabelListCmd   = "list the labels of peer specified by <PeerID>"
+	helpPeerLabelSetCmd    = "set labels on peer specified by <PeerID>"
+	helpPeerLabelRemoveCmd = "remove labels from peer specified by <PeerID>"
+)
+
+func init() {
+	peerLabelCmd.AddCommand(peerLabelListCmd)
+	peerLabelCmd.AddCommand(peerLabelSetCmd)
+	peerLabelCmd.AddCommand(peerLabelRemoveCmd)
+	peerCmd.AddCommand(peerLabelCmd)
+}
+
+var peerLabelCmd = &cobra.Command{
+	Use:   "label",
+	Short: helpPeerLabelCmd,
+}
+
+func printPeerLabels(labels map[string]string) {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Label", "Value"})
+	for _, k := range keys {
+		table.Append([]string{k, labels[k]})
+	}
+	table.Render()
+}
+
+var peerLabelListCmd = &cobra.Command{
+	Use:   "list <PeerID>",
+	Short: helpPeerLabelListCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		resp, err := client.PeerLabels(peerID)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("peerID", peerID).Error("failed to get peer labels")
+			}
+			failure("Failed to get peer labels", err, 1)
+		}
+		printPeerLabels(resp.Labels)
+	},
+}
+
+var peerLabelSetCmd = &cobra.Command{
+	Use:   "set <PeerID> <key[=value]>...",
+	Short: helpPeerLabelSetCmd,
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		req := api.PeerLabelsReq{Labels: make(map[string]string)}
+		for _, label := range args[1:] {
+			kv := strings.SplitN(label, "=", 2)
+			if len(kv) == 1 {
+				req.Labels[kv[0]] = ""
+			} else {
+				req.Labels[kv[0]] = kv[1]
+			}
+		}
+
+		resp, err := client.PeerLabelsSet(peerID, req)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("peerID", peerID).Error("failed to set peer labels")
+			}
+			failure("Failed to set peer labels", err, 1)
+		}
+		printPeerLabels(resp.Labels)
+	},
+}
+
+var peerLabelRemoveCmd = &cobra.Command{
+	Use:   "remove <PeerID> <key>...",
+	Short: helpPeerLabelRemoveCmd,
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		for _, label := range args[1:] {
+			if _, err := client.PeerLabelDelete(peerID, label); err != nil {
+				if GlobalFlag.Verbose {
+					log.WithError(err).WithFields(log.Fields{
+						"peerID": peerID,
+						"label":  label,
+					}).Error("failed to remove peer label")
+				}
+				failure(fmt.Sprintf("Failed to remove label %s", label), err, 1)
+			}
+		}
+		fmt.Println("Peer labels removed successfully")
+	},
+}
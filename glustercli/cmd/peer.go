@@ -15,30 +15,48 @@ import (
 )
 
 const (
-	helpPeerCmd       = "Gluster Peer Management"
-	helpPeerAddCmd    = "add peer specified by <HOSTNAME>"
-	helpPeerRemoveCmd = "remove peer specified by <PeerID>"
-	helpPeerStatusCmd = "list status of peers"
-	helpPeerListCmd   = "list all the nodes in the pool (including localhost)"
+	helpPeerCmd          = "Gluster Peer Management"
+	helpPeerAddCmd       = "add peer specified by <HOSTNAME>"
+	helpPeerPreflightCmd = "check if peer specified by <HOSTNAME> can be added"
+	helpPeerRemoveCmd    = "remove peer specified by <PeerID>"
+	helpPeerStatusCmd    = "list status of peers"
+	helpPeerListCmd      = "list all the nodes in the pool (including localhost)"
+	helpPeerPromoteCmd   = "make peer specified by <PeerID> a voting member of the store"
+	helpPeerDemoteCmd    = "make peer specified by <PeerID> a proxy of the store"
 )
 
 var (
+	// Peer Add Command Flags
+	flagPeerAddForce bool
+
 	// Peer Remove Command Flags
 	flagPeerRemoveForce bool
+
+	// Peer Status/List Command Flags
+	flagPeerSelector string
 )
 
 func init() {
+	peerAddCmd.Flags().BoolVarP(&flagPeerAddForce, "force", "f", false, "Add peer even if preflight checks fail")
 	peerCmd.AddCommand(peerAddCmd)
 
-	peerRemoveCmd.Flags().BoolVarP(&flagPeerRemoveForce, "force", "f", false, "Force")
+	peerCmd.AddCommand(peerPreflightCmd)
+
+	peerRemoveCmd.Flags().BoolVarP(&flagPeerRemoveForce, "force", "f", false, "Remove peer even if detach checks fail, like when it hosts bricks or is unreachable")
 
 	peerCmd.AddCommand(peerRemoveCmd)
 
+	peerStatusCmd.Flags().StringVar(&flagPeerSelector, "selector", "", "Filter by label selector, like disk=ssd,rack!=r1")
 	peerCmd.AddCommand(peerStatusCmd)
 
 	peerListCmd.Flags().StringVar(&flagCmdFilterKey, "key", "", "Filter by metadata key")
 	peerListCmd.Flags().StringVar(&flagCmdFilterValue, "value", "", "Filter by metadata value")
+	peerListCmd.Flags().StringVar(&flagPeerSelector, "selector", "", "Filter by label selector, like disk=ssd,rack!=r1")
 	peerCmd.AddCommand(peerListCmd)
+
+	peerCmd.AddCommand(peerPromoteCmd)
+
+	peerCmd.AddCommand(peerDemoteCmd)
 }
 
 var peerCmd = &cobra.Command{
@@ -54,6 +72,7 @@ var peerAddCmd = &cobra.Command{
 		hostname := cmd.Flags().Args()[0]
 		peerAddReq := api.PeerAddReq{
 			Addresses: []string{hostname},
+			Force:     flagPeerAddForce,
 		}
 		peer, err := client.PeerAdd(peerAddReq)
 		if err != nil {
@@ -70,6 +89,31 @@ var peerAddCmd = &cobra.Command{
 	},
 }
 
+var peerPreflightCmd = &cobra.Command{
+	Use:   "preflight <HOSTNAME>",
+	Short: helpPeerPreflightCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		hostname := cmd.Flags().Args()[0]
+		report, err := client.PeerPreflight(api.PeerPreflightReq{Addresses: []string{hostname}})
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("host", hostname).Error("peer preflight failed")
+			}
+			failure("Peer preflight failed", err, 1)
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Check", "From Peer", "Result", "Message"})
+		for _, c := range report.Checks {
+			table.Append([]string{c.Name, c.PeerID, c.Result, c.Message})
+		}
+		table.Render()
+		if !report.Passed {
+			failure(fmt.Sprintf("Preflight checks failed for %s", report.Address), nil, 1)
+		}
+	},
+}
+
 var peerRemoveCmd = &cobra.Command{
 	Use:   "remove <PeerID>",
 	Short: helpPeerRemoveCmd,
@@ -81,7 +125,11 @@ var peerRemoveCmd = &cobra.Command{
 			err = errors.New("failed to parse peerID")
 		}
 		if err == nil {
-			err = client.PeerRemove(peerID)
+			if flagPeerRemoveForce {
+				err = client.PeerRemoveForce(peerID)
+			} else {
+				err = client.PeerRemove(peerID)
+			}
 		}
 		if err != nil {
 			if GlobalFlag.Verbose {
@@ -93,20 +141,57 @@ var peerRemoveCmd = &cobra.Command{
 	},
 }
 
-func peerStatusHandler(cmd *cobra.Command) {
-	var peers api.PeerListResp
+var peerPromoteCmd = &cobra.Command{
+	Use:   "promote <PeerID>",
+	Short: helpPeerPromoteCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerEtcdRoleHandler(cmd.Flags().Args()[0], true)
+	},
+}
+
+var peerDemoteCmd = &cobra.Command{
+	Use:   "demote <PeerID>",
+	Short: helpPeerDemoteCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerEtcdRoleHandler(cmd.Flags().Args()[0], false)
+	},
+}
+
+func peerEtcdRoleHandler(peerID string, promote bool) {
 	var err error
-	if flagCmdFilterKey == "" && flagCmdFilterValue == "" {
-		peers, err = client.Peers()
-	} else if flagCmdFilterKey != "" && flagCmdFilterValue == "" {
-		peers, err = client.Peers(map[string]string{"key": flagCmdFilterKey})
-	} else if flagCmdFilterKey == "" && flagCmdFilterValue != "" {
-		peers, err = client.Peers(map[string]string{"value": flagCmdFilterValue})
-	} else if flagCmdFilterKey != "" && flagCmdFilterValue != "" {
-		peers, err = client.Peers(map[string]string{"key": flagCmdFilterKey,
-			"value": flagCmdFilterValue,
-		})
+	if uuid.Parse(peerID) == nil {
+		err = errors.New("failed to parse peerID")
+	}
+	if err == nil {
+		if promote {
+			_, err = client.PeerPromote(peerID)
+		} else {
+			_, err = client.PeerDemote(peerID)
+		}
 	}
+	if err != nil {
+		if GlobalFlag.Verbose {
+			log.WithError(err).WithField("peerID", peerID).Error("peer etcd role change failed")
+		}
+		failure("Peer etcd role change failed", err, 1)
+	}
+	fmt.Println("Peer etcd role change requested, check the etcd role of the peer in peer status")
+}
+
+func peerStatusHandler(cmd *cobra.Command) {
+	filterParams := make(map[string]string)
+	if flagCmdFilterKey != "" {
+		filterParams["key"] = flagCmdFilterKey
+	}
+	if flagCmdFilterValue != "" {
+		filterParams["value"] = flagCmdFilterValue
+	}
+	if flagPeerSelector != "" {
+		filterParams["selector"] = flagPeerSelector
+	}
+	peers, err := client.Peers(filterParams)
 	if err != nil {
 		if GlobalFlag.Verbose {
 			log.WithError(err).Error("peer status failed")
@@ -114,10 +199,14 @@ func peerStatusHandler(cmd *cobra.Command) {
 		failure("Failed to get Peers list", err, 1)
 	}
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"ID", "Name", "Client Addresses", "Peer Addresses", "Online", "PID"})
+	table.SetHeader([]string{"ID", "Name", "Client Addresses", "Peer Addresses", "Online", "PID", "Etcd Role"})
 
 	for _, peer := range peers {
-		table.Append([]string{peer.ID.String(), peer.Name, strings.Join(peer.ClientAddresses, "\n"), strings.Join(peer.PeerAddresses, "\n"), formatBoolYesNo(peer.Online), formatPID(peer.PID)})
+		role := peer.EtcdRole
+		if peer.EtcdRolePinned != "" {
+			role += " (pinned " + peer.EtcdRolePinned + ")"
+		}
+		table.Append([]string{peer.ID.String(), peer.Name, strings.Join(peer.ClientAddresses, "\n"), strings.Join(peer.PeerAddresses, "\n"), formatBoolYesNo(peer.Online), formatPID(peer.PID), role})
 	}
 	table.Render()
 }
@@ -49,6 +49,7 @@ func NewGlustercliCmd() *cobra.Command {
 func addSubCommands(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(peerCmd)
 	rootCmd.AddCommand(bitrotCmd)
+	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(deviceCmd)
 	rootCmd.AddCommand(eventsCmd)
 	rootCmd.AddCommand(georepCmd)
@@ -111,7 +112,7 @@ func (gOpt *GlustercliOption) Init() {
 	gOpt.SetEndpoints()
 
 	//Initializing Rest Client
-	initRESTClient(gOpt.Endpoints[0], gOpt.User, gOpt.Secret, gOpt.Cacert, gOpt.Insecure)
+	initRESTClient(gOpt.Endpoints, gOpt.User, gOpt.Secret, gOpt.Cacert, gOpt.Insecure)
 
 }
 
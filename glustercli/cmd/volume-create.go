@@ -3,9 +3,11 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/pkg/api"
 
+	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -29,6 +31,7 @@ var (
 	flagCreateDepOpts                 bool
 	flagCreateThinArbiter             string
 	flagCreateVolumeOptions           []string
+	flagCreateAdopt                   bool
 
 	flagCreateVolumeSize            string
 	flagCreateDistributeCount       int
@@ -36,6 +39,8 @@ var (
 	flagCreateLimitZones            []string
 	flagCreateExcludePeers          []string
 	flagCreateExcludeZones          []string
+	flagCreatePeerSelector          string
+	flagCreateProfile               string
 	flagCreateSnapshotEnabled       bool
 	flagCreateSnapshotReserveFactor float64 = 1
 	flagCreateSubvolZoneOverlap     bool
@@ -74,6 +79,8 @@ func init() {
 	volumeCreateCmd.Flags().BoolVar(&flagAllowRootDir, "allow-root-dir", false, "Allow root directory")
 	volumeCreateCmd.Flags().BoolVar(&flagAllowMountAsBrick, "allow-mount-as-brick", false, "Allow mount as bricks")
 	volumeCreateCmd.Flags().BoolVar(&flagCreateBrickDir, "create-brick-dir", false, "Create brick directory")
+	volumeCreateCmd.Flags().StringVar(&flagCreateProfile, "profile", "", "Volume profile whose options and layout are used where not given")
+	volumeCreateCmd.Flags().BoolVar(&flagCreateAdopt, "adopt", false, "Recreate the volume from data already present in the bricks")
 
 	// Smart Volume Flags
 	volumeCreateCmd.Flags().StringVar(&flagCreateVolumeSize, "size", "", "Size of the Volume")
@@ -82,6 +89,7 @@ func init() {
 	volumeCreateCmd.Flags().StringSliceVar(&flagCreateLimitZones, "limit-zones", nil, "Use bricks only from these Zones")
 	volumeCreateCmd.Flags().StringSliceVar(&flagCreateExcludePeers, "exclude-peers", nil, "Do not use bricks from these Peers")
 	volumeCreateCmd.Flags().StringSliceVar(&flagCreateExcludeZones, "exclude-zones", nil, "Do not use bricks from these Zones")
+	volumeCreateCmd.Flags().StringVar(&flagCreatePeerSelector, "peer-selector", "", "Use bricks only from Peers with matching labels, like disk=ssd,rack!=r1")
 	volumeCreateCmd.Flags().BoolVar(&flagCreateSnapshotEnabled, "enable-snapshot", false, "Enable Volume for Gluster Snapshot")
 	volumeCreateCmd.Flags().Float64Var(&flagCreateSnapshotReserveFactor, "snapshot-reserve-factor", 1, "Snapshot Reserve Factor")
 	volumeCreateCmd.Flags().BoolVar(&flagCreateSubvolZoneOverlap, "subvols-zones-overlap", false, "Brick belonging to other Sub volume can be created in the same zone")
@@ -142,11 +150,17 @@ func smartVolumeCreate(cmd *cobra.Command, args []string) {
 		LimitZones:              flagCreateLimitZones,
 		ExcludePeers:            flagCreateExcludePeers,
 		ExcludeZones:            flagCreateExcludeZones,
+		PeerSelector:            flagCreatePeerSelector,
+		Profile:                 flagCreateProfile,
 		SubvolZonesOverlap:      flagCreateSubvolZoneOverlap,
 		Force:                   flagCreateForce,
+		JobID:                   uuid.New(),
 	}
 
+	stop := make(chan struct{})
+	go showCreateProgress(req.JobID, stop)
 	vol, err := client.VolumeCreate(req)
+	close(stop)
 	if err != nil {
 		if GlobalFlag.Verbose {
 			log.WithError(err).WithField(
@@ -158,6 +172,42 @@ func smartVolumeCreate(cmd *cobra.Command, args []string) {
 	fmt.Println("Volume ID: ", vol.ID)
 }
 
+// showCreateProgress prints the number of bricks provisioned for the volume
+// create job, as reported by its progress events, till stop is closed
+func showCreateProgress(jobID string, stop <-chan struct{}) {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	var shown int
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		events, err := client.ListEvents()
+		if err != nil {
+			continue
+		}
+
+		var done int
+		var total, peer string
+		for _, e := range events {
+			if e == nil || e.Name != "volume.create-progress" || e.Data["job.id"] != jobID {
+				continue
+			}
+			done++
+			total = e.Data["bricks.total"]
+			peer = e.Data["peer.id"]
+		}
+		if done > shown {
+			shown = done
+			fmt.Printf("Provisioned %d of %s bricks (last on peer %s)\n", done, total, peer)
+		}
+	}
+}
+
 func volumeCreateCmdRun(cmd *cobra.Command, args []string) {
 	if flagCreateVolumeSize != "" {
 		smartVolumeCreate(cmd, args)
@@ -294,6 +344,8 @@ func volumeCreateCmdRun(cmd *cobra.Command, args []string) {
 		Name:    volname,
 		Subvols: subvols,
 		Force:   flagCreateForce,
+		Adopt:   flagCreateAdopt,
+		Profile: flagCreateProfile,
 		VolOptionReq: api.VolOptionReq{
 			Options: options,
 			VolOptionFlags: api.VolOptionFlags{
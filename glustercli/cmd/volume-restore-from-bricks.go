@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	volumeRestoreFromBricksCmdHelpShort = "Recreate volumes missing from the store from their bricks"
+	volumeRestoreFromBricksCmdHelpLong  = "Scan the bricks of all peers for volumes missing from the store, after the store has been lost, and recreate the volumes by adopting the bricks. Each proposed volume is shown for review, and is recreated only when approved."
+)
+
+var volumeRestoreFromBricksCmd = &cobra.Command{
+	Use:   "restore-from-bricks",
+	Short: volumeRestoreFromBricksCmdHelpShort,
+	Long:  volumeRestoreFromBricksCmdHelpLong,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.VolumeRestoreFromBricks()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("scanning bricks failed")
+			}
+			failure("Scanning bricks failed", err, 1)
+		}
+
+		for _, w := range resp.Warnings {
+			fmt.Println("Warning:", w)
+		}
+		if len(resp.Volumes) == 0 {
+			fmt.Println("No volumes to restore")
+			return
+		}
+
+		for _, p := range resp.Volumes {
+			fmt.Printf("\nVolume: %s\nID: %s\n", p.Name, p.ID)
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Subvol", "Type", "Peer", "Path"})
+			for i, s := range p.Req.Subvols {
+				for _, b := range s.Bricks {
+					table.Append([]string{fmt.Sprintf("%d", i), s.Type, b.PeerID, b.Path})
+				}
+			}
+			table.Render()
+			for _, w := range p.Warnings {
+				fmt.Println("Warning:", w)
+			}
+
+			if len(p.Req.Subvols) == 0 {
+				fmt.Printf("Volume %s cannot be restored\n", p.Name)
+				continue
+			}
+			if !GlobalFlag.ScriptMode {
+				if ok := PromptConfirm("Restore volume %s [yes/no]? ", p.Name); !ok {
+					continue
+				}
+			}
+
+			if _, err := client.VolumeCreate(p.Req); err != nil {
+				if GlobalFlag.Verbose {
+					log.WithError(err).WithField("volume", p.Name).Error("volume restore failed")
+				}
+				failure(fmt.Sprintf("Restoring volume %s failed", p.Name), err, 1)
+			}
+			fmt.Printf("Volume %s restored successfully\n", p.Name)
+		}
+	},
+}
+
+func init() {
+	volumeCmd.AddCommand(volumeRestoreFromBricksCmd)
+}
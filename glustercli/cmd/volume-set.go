@@ -3,9 +3,13 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/gluster/glusterd2/pkg/api"
 
+	"github.com/olekukonko/tablewriter"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -16,7 +20,7 @@ const (
 )
 
 var (
-	flagSetAdv, flagSetExp, flagSetDep bool
+	flagSetAdv, flagSetExp, flagSetDep, flagSetDryRun bool
 
 	volumeSetCmd = &cobra.Command{
 		Use:   "set <volname> <option> <value> [<option> <value>]...",
@@ -31,6 +35,7 @@ func init() {
 	volumeSetCmd.Flags().BoolVar(&flagSetAdv, "advanced", false, "Allow setting advanced options")
 	volumeSetCmd.Flags().BoolVar(&flagSetExp, "experimental", false, "Allow setting experimental options")
 	volumeSetCmd.Flags().BoolVar(&flagSetDep, "deprecated", false, "Allow setting deprecated options")
+	volumeSetCmd.Flags().BoolVar(&flagSetDryRun, "dry-run", false, "Show the impact of setting the options without setting them")
 	volumeCmd.AddCommand(volumeSetCmd)
 }
 
@@ -51,6 +56,18 @@ func volumeSetCmdArgs(cmd *cobra.Command, args []string) error {
 func volumeSetCmdRun(cmd *cobra.Command, args []string) {
 	volname := args[0]
 	options := args[1:]
+	if flagSetDryRun {
+		resp, err := volumeOptionImpact(volname, options)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField(
+					"volume", volname).Error("volume option impact analysis failed")
+			}
+			failure("Volume option impact analysis failed", err, 1)
+		}
+		printOptionImpact(resp)
+		return
+	}
 	if err := volumeOptionJSONHandler(cmd, volname, options); err != nil {
 		if GlobalFlag.Verbose {
 			log.WithError(err).WithField(
@@ -62,13 +79,19 @@ func volumeSetCmdRun(cmd *cobra.Command, args []string) {
 	}
 }
 
-func volumeOptionJSONHandler(cmd *cobra.Command, volname string, options []string) error {
+// optionPairs returns the options given as '<option> <value>' pairs
+func optionPairs(options []string) map[string]string {
 	vopt := make(map[string]string)
 	for op, val := range options {
 		if op%2 == 0 {
 			vopt[val] = options[op+1]
 		}
 	}
+	return vopt
+}
+
+func volumeOptionJSONHandler(cmd *cobra.Command, volname string, options []string) error {
+	vopt := optionPairs(options)
 
 	if volname == "all" {
 		err := client.ClusterOptionSet(api.ClusterOptionReq{
@@ -88,3 +111,48 @@ func volumeOptionJSONHandler(cmd *cobra.Command, volname string, options []strin
 
 	return err
 }
+
+func volumeOptionImpact(volname string, options []string) (api.OptionImpactResp, error) {
+	vopt := optionPairs(options)
+
+	if volname == "all" {
+		return client.ClusterOptionSetImpact(api.ClusterOptionReq{
+			Options: vopt,
+		})
+	}
+
+	return client.VolumeSetImpact(volname, api.VolOptionReq{
+		Options: vopt,
+		VolOptionFlags: api.VolOptionFlags{
+			AllowAdvanced:     flagSetAdv,
+			AllowExperimental: flagSetExp,
+			AllowDeprecated:   flagSetDep,
+		},
+	})
+}
+
+func printOptionImpact(resp api.OptionImpactResp) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Option", "Value", "Volfiles", "Reconfigurable"})
+	for _, o := range resp.Options {
+		table.Append([]string{o.Option, o.Value, strings.Join(o.Graphs, ", "), strconv.FormatBool(o.Reconfigurable)})
+	}
+	table.Render()
+
+	if len(resp.Processes) > 0 {
+		fmt.Println("Affected processes:")
+		table = tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Peer ID", "Process", "Name", "Volume", "Action"})
+		for _, p := range resp.Processes {
+			table.Append([]string{p.PeerID, p.Process, p.Name, p.Volume, p.Action})
+		}
+		table.Render()
+	}
+
+	fmt.Printf("Client graph change: %t\n", resp.ClientGraphChange)
+	fmt.Printf("Quorum affected: %t\n", resp.QuorumAffected)
+	fmt.Printf("Heal affected: %t\n", resp.HealAffected)
+	for _, w := range resp.Warnings {
+		fmt.Println("Warning:", w)
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	volumeTrashCmdHelpShort   = "List the deleted volumes kept in the trash"
+	volumeRestoreCmdHelpShort = "Restore a deleted volume from the trash"
+)
+
+var volumeTrashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: volumeTrashCmdHelpShort,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		vols, err := client.DeletedVolumes()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to get deleted volumes")
+			}
+			failure("Failed to get deleted volumes", err, 1)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "ID", "Type", "Deleted At", "Purge At", "Wipe"})
+		for _, v := range vols {
+			table.Append([]string{v.Name, v.ID.String(), v.Type.String(),
+				v.DeletedAt.Format(time.RFC3339), v.PurgeAt.Format(time.RFC3339), formatBoolYesNo(v.Wipe)})
+		}
+		table.Render()
+	},
+}
+
+var volumeRestoreCmd = &cobra.Command{
+	Use:   "restore <volname>",
+	Short: volumeRestoreCmdHelpShort,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		if _, err := client.VolumeRestore(volname); err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("volume", volname).Error("volume restore failed")
+			}
+			failure("Volume restore failed", err, 1)
+		}
+		fmt.Printf("Volume %s restored successfully\n", volname)
+	},
+}
+
+func init() {
+	volumeCmd.AddCommand(volumeTrashCmd)
+	volumeCmd.AddCommand(volumeRestoreCmd)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const volumeWipeStatusCmdHelpShort = "Show the progress of wiping the bricks of deleted volumes"
+
+var volumeWipeStatusCmd = &cobra.Command{
+	Use:   "wipe-status [<volname>]",
+	Short: volumeWipeStatusCmdHelpShort,
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jobs, err := client.BrickWipeJobs()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("failed to get brick wipe jobs")
+			}
+			failure("Failed to get brick wipe jobs", err, 1)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Volume", "Peer", "Path", "Method", "State", "Progress", "Error"})
+		for _, j := range jobs {
+			if len(args) == 1 && j.VolumeName != args[0] {
+				continue
+			}
+			table.Append([]string{j.VolumeName, j.PeerID, j.Path, j.Method, j.State, wipeProgress(j), j.Error})
+		}
+		table.Render()
+	},
+}
+
+func wipeProgress(j api.BrickWipeJob) string {
+	if j.Total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", j.Done*100/j.Total)
+}
+
+func init() {
+	volumeCmd.AddCommand(volumeWipeStatusCmd)
+}
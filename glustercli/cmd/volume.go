@@ -28,6 +28,7 @@ const (
 	helpVolumeSizeCmd   = "Get Gluster Volume Size Usage"
 	helpVolumeExpandCmd = "Expand a Gluster Volume"
 	helpVolumeEditCmd   = "Edit metadata (key-value pairs) of a volume. Glusterd2 will not interpret these key and value in any way"
+	helpVolumeRenameCmd = "Rename a stopped Gluster Volume"
 )
 
 var (
@@ -37,15 +38,23 @@ var (
 	// Stop Command Flags
 	flagStopCmdForce bool
 
+	// Status Command Flags
+	flagStatusCmdDetail bool
+
 	// Expand Command Flags
 	flagExpandCmdReplicaCount    int
 	flagExpandCmdForce           bool
 	flagExpandCmdDistributeCount int
 	flagExpandCmdSize            string
+	flagExpandCmdRebalance       bool
 
 	// Filter Volume Info/List command flags
-	flagCmdFilterKey   string
-	flagCmdFilterValue string
+	flagCmdFilterKey       string
+	flagCmdFilterValue     string
+	flagCmdFilterState     string
+	flagCmdFilterType      string
+	flagCmdFilterName      string
+	flagCmdFilterNameRegex string
 
 	//Filter Volume Get command flags
 	flagGetAdv bool
@@ -56,6 +65,13 @@ var (
 	flagCmdMetadataKey    string
 	flagCmdMetadataValue  string
 	flagCmdDeleteMetadata bool
+
+	// Delete Command Flags
+	flagDeleteCmdWipe  bool
+	flagDeleteCmdPurge bool
+
+	// Rename Command Flags
+	flagRenameCmdForce bool
 	//volume expand flags
 	flagReuseBricks, flagAllowRootDir, flagAllowMountAsBrick, flagCreateBrickDir bool
 )
@@ -70,6 +86,8 @@ func init() {
 	volumeCmd.AddCommand(volumeStopCmd)
 
 	// Volume Delete
+	volumeDeleteCmd.Flags().BoolVar(&flagDeleteCmdWipe, "wipe", false, "Wipe the data of the bricks in the background")
+	volumeDeleteCmd.Flags().BoolVar(&flagDeleteCmdPurge, "purge", false, "Delete the volume without keeping it in the trash")
 	volumeCmd.AddCommand(volumeDeleteCmd)
 
 	volumeGetCmd.Flags().BoolVar(&flagGetAdv, "advanced", false, "Get advanced options")
@@ -81,12 +99,21 @@ func init() {
 
 	volumeInfoCmd.Flags().StringVar(&flagCmdFilterKey, "key", "", "Filter by metadata key")
 	volumeInfoCmd.Flags().StringVar(&flagCmdFilterValue, "value", "", "Filter by metadata value")
+	volumeInfoCmd.Flags().StringVar(&flagCmdFilterState, "state", "", "Filter by volume state")
+	volumeInfoCmd.Flags().StringVar(&flagCmdFilterType, "type", "", "Filter by volume type")
+	volumeInfoCmd.Flags().StringVar(&flagCmdFilterName, "name", "", "Filter by volume name glob")
+	volumeInfoCmd.Flags().StringVar(&flagCmdFilterNameRegex, "name-regex", "", "Filter by volume name regex")
 	volumeCmd.AddCommand(volumeInfoCmd)
 
+	volumeStatusCmd.Flags().BoolVar(&flagStatusCmdDetail, "detail", false, "Show filesystem details of bricks")
 	volumeCmd.AddCommand(volumeStatusCmd)
 
 	volumeListCmd.Flags().StringVar(&flagCmdFilterKey, "key", "", "Filter by metadata Key")
 	volumeListCmd.Flags().StringVar(&flagCmdFilterValue, "value", "", "Filter by metadata value")
+	volumeListCmd.Flags().StringVar(&flagCmdFilterState, "state", "", "Filter by volume state")
+	volumeListCmd.Flags().StringVar(&flagCmdFilterType, "type", "", "Filter by volume type")
+	volumeListCmd.Flags().StringVar(&flagCmdFilterName, "name", "", "Filter by volume name glob")
+	volumeListCmd.Flags().StringVar(&flagCmdFilterNameRegex, "name-regex", "", "Filter by volume name regex")
 	volumeCmd.AddCommand(volumeListCmd)
 
 	// Volume Expand
@@ -94,6 +121,7 @@ func init() {
 	volumeExpandCmd.Flags().IntVar(&flagExpandCmdDistributeCount, "distribute", 0, "Distribute Count")
 	volumeExpandCmd.Flags().StringVar(&flagExpandCmdSize, "size", "", "Size by which volume needs to be expanded.")
 	volumeExpandCmd.Flags().BoolVarP(&flagExpandCmdForce, "force", "f", false, "Force")
+	volumeExpandCmd.Flags().BoolVar(&flagExpandCmdRebalance, "rebalance", false, "Start rebalance once the volume is expanded")
 	volumeExpandCmd.Flags().BoolVar(&flagReuseBricks, "reuse-bricks", false, "Reuse Bricks")
 	volumeExpandCmd.Flags().BoolVar(&flagAllowRootDir, "allow-root-dir", false, "Allow Root Directory")
 	volumeExpandCmd.Flags().BoolVar(&flagAllowMountAsBrick, "allow-mount-as-brick", false, "Allow Mount as Bricks")
@@ -107,6 +135,10 @@ func init() {
 	volumeEditCmd.MarkFlagRequired("key")
 	volumeEditCmd.MarkFlagRequired("value")
 	volumeCmd.AddCommand(volumeEditCmd)
+
+	// Volume Rename
+	volumeRenameCmd.Flags().BoolVarP(&flagRenameCmdForce, "force", "f", false, "Rename replicate volumes, losing their pending self-heals")
+	volumeCmd.AddCommand(volumeRenameCmd)
 }
 
 var volumeCmd = &cobra.Command{
@@ -221,7 +253,18 @@ var volumeDeleteCmd = &cobra.Command{
 				return
 			}
 		}
-		err := client.VolumeDelete(volname)
+		var err error
+		var jobs api.BrickWipeJobsResp
+		switch {
+		case flagDeleteCmdWipe && flagDeleteCmdPurge:
+			jobs, err = client.VolumePurgeWipe(volname)
+		case flagDeleteCmdWipe:
+			jobs, err = client.VolumeDeleteWipe(volname)
+		case flagDeleteCmdPurge:
+			err = client.VolumePurge(volname)
+		default:
+			err = client.VolumeDelete(volname)
+		}
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithField("volume", volname).Error("volume deletion failed")
@@ -229,6 +272,11 @@ var volumeDeleteCmd = &cobra.Command{
 			failure("Volume deletion failed", err, 1)
 		}
 		fmt.Printf("Volume %s deleted successfully\n", volname)
+		if flagDeleteCmdWipe && len(jobs) == 0 {
+			fmt.Println("The bricks will be wiped when the volume is purged from the trash")
+		} else if flagDeleteCmdWipe {
+			fmt.Printf("Wiping %d bricks in the background, see \"volume wipe-status\" for progress\n", len(jobs))
+		}
 	},
 }
 
@@ -319,6 +367,26 @@ func volumeInfoDisplay(vol api.VolumeGetResp) {
 	}
 	return
 }
+
+// volumeFilterParams returns the filters given to the volume info and list
+// commands
+func volumeFilterParams() map[string]string {
+	filterParams := make(map[string]string)
+	for param, value := range map[string]string{
+		"key":        flagCmdFilterKey,
+		"value":      flagCmdFilterValue,
+		"state":      flagCmdFilterState,
+		"type":       flagCmdFilterType,
+		"name":       flagCmdFilterName,
+		"name-regex": flagCmdFilterNameRegex,
+	} {
+		if value != "" {
+			filterParams[param] = value
+		}
+	}
+	return filterParams
+}
+
 func volumeInfoHandler2(cmd *cobra.Command, isInfo bool) error {
 	var vols api.VolumeListResp
 	var err error
@@ -326,20 +394,11 @@ func volumeInfoHandler2(cmd *cobra.Command, isInfo bool) error {
 	if len(cmd.Flags().Args()) > 0 {
 		volname = cmd.Flags().Args()[0]
 	}
+	filterParams := volumeFilterParams()
 	if volname == "" {
-		if flagCmdFilterKey == "" && flagCmdFilterValue == "" {
-			vols, err = client.Volumes("")
-		} else if flagCmdFilterKey != "" && flagCmdFilterValue == "" {
-			vols, err = client.Volumes("", map[string]string{"key": flagCmdFilterKey})
-		} else if flagCmdFilterKey == "" && flagCmdFilterValue != "" {
-			vols, err = client.Volumes("", map[string]string{"value": flagCmdFilterValue})
-		} else if flagCmdFilterKey != "" && flagCmdFilterValue != "" {
-			vols, err = client.Volumes("", map[string]string{"key": flagCmdFilterKey,
-				"value": flagCmdFilterValue,
-			})
-		}
+		vols, err = client.Volumes("", filterParams)
 	} else {
-		if flagCmdFilterKey != "" || flagCmdFilterValue != "" {
+		if len(filterParams) != 0 {
 			return errors.New("invalid command. Cannot give filter arguments when providing volname")
 		}
 		vols, err = client.Volumes(volname)
@@ -408,7 +467,33 @@ var volumeListCmd = &cobra.Command{
 	},
 }
 
+// volumeStatusDetailDisplay prints the status along with the filesystem
+// details of every brick, in the format of `gluster volume status detail`
+func volumeStatusDetailDisplay(vol api.BricksStatusResp) {
+	for _, b := range vol {
+		fmt.Println("------------------------------------------------------------------------------")
+		fmt.Printf("%-20s : %s:%s\n", "Brick", b.Info.Hostname, b.Info.Path)
+		fmt.Printf("%-20s : %s\n", "Brick ID", b.Info.ID)
+		fmt.Printf("%-20s : %d\n", "TCP Port", b.Port)
+		fmt.Printf("%-20s : %t\n", "Online", b.Online)
+		fmt.Printf("%-20s : %d\n", "Pid", b.Pid)
+		fmt.Printf("%-20s : %s\n", "File System", b.FS)
+		fmt.Printf("%-20s : %s\n", "Device", b.Device)
+		fmt.Printf("%-20s : %s\n", "Mount Options", b.MountOpts)
+		fmt.Printf("%-20s : %s\n", "Block Size", humanReadable(b.Size.BlockSize))
+		fmt.Printf("%-20s : %s\n", "Disk Space Free", humanReadable(b.Size.Free))
+		fmt.Printf("%-20s : %s\n", "Total Disk Space", humanReadable(b.Size.Capacity))
+		fmt.Printf("%-20s : %d\n", "Inode Count", b.Size.InodesTotal)
+		fmt.Printf("%-20s : %d\n", "Free Inodes", b.Size.InodesFree)
+	}
+}
+
 func volumeStatusDisplay(vol api.BricksStatusResp) {
+	if flagStatusCmdDetail {
+		volumeStatusDetailDisplay(vol)
+		return
+	}
+
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetHeader([]string{"Brick ID", "Host", "Path", "Online", "Port", "Pid"})
 	for _, b := range vol {
@@ -528,6 +613,7 @@ var volumeExpandCmd = &cobra.Command{
 			Flags:           flags,
 			DistributeCount: flagExpandCmdDistributeCount,
 			Size:            uint64(size),
+			Rebalance:       flagExpandCmdRebalance,
 		})
 		if err != nil {
 			if GlobalFlag.Verbose {
@@ -561,3 +647,24 @@ var volumeEditCmd = &cobra.Command{
 		fmt.Printf("Metadata edit successful\n")
 	},
 }
+
+var volumeRenameCmd = &cobra.Command{
+	Use:   "rename <volname> <newname> [--force]",
+	Short: helpVolumeRenameCmd,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname, newname := args[0], args[1]
+		req := api.VolRenameReq{
+			NewName: newname,
+			Force:   flagRenameCmdForce,
+		}
+		_, err := client.VolumeRename(volname, req)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("volume", volname).Error("volume rename failed")
+			}
+			failure("Volume rename failed", err, 1)
+		}
+		fmt.Printf("Volume %s renamed to %s\n", volname, newname)
+	},
+}
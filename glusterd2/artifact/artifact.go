@@ -0,0 +1,138 @@
+// Package artifact distributes small files, like CA bundles and
+// configuration files, from the originator to other peers as part of
+// transactions.
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// MaxSize is the maximum size of an artifact. Artifacts are carried in the
+// transaction context, which is kept in the store, so they must be small.
+const MaxSize = 512 * 1024
+
+var (
+	// ErrChecksumMismatch is returned when the content of an artifact does
+	// not match its checksum
+	ErrChecksumMismatch = errors.New("artifact checksum mismatch")
+	// ErrTooLarge is returned when an artifact is larger than MaxSize
+	ErrTooLarge = errors.New("artifact is too large")
+)
+
+// Artifact is a file to be placed on peers
+type Artifact struct {
+	Path     string      `json:"path"`
+	Mode     os.FileMode `json:"mode"`
+	Content  []byte      `json:"content"`
+	Checksum string      `json:"checksum"`
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// New returns an Artifact to be placed at the given absolute path with the
+// given content and permissions
+func New(path string, content []byte, mode os.FileMode) (*Artifact, error) {
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("artifact path %s is not absolute", path)
+	}
+	if len(content) > MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	return &Artifact{
+		Path:     filepath.Clean(path),
+		Mode:     mode.Perm(),
+		Content:  content,
+		Checksum: checksum(content),
+	}, nil
+}
+
+// Verify checks the content of the artifact against its checksum
+func (a *Artifact) Verify() error {
+	if checksum(a.Content) != a.Checksum {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+// Placed returns true if the file at the artifact path already has the
+// content and permissions of the artifact
+func (a *Artifact) Placed() bool {
+	fi, err := os.Stat(a.Path)
+	if err != nil || fi.Mode().Perm() != a.Mode {
+		return false
+	}
+	content, err := ioutil.ReadFile(a.Path)
+	return err == nil && checksum(content) == a.Checksum
+}
+
+// Place verifies the checksum of the artifact and atomically places it at its
+// path. The content is written to a temporary file in the same directory and
+// renamed to the artifact path, so readers never see a partially written
+// file.
+func (a *Artifact) Place() error {
+	if err := a.Verify(); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(a.Path)
+	if err := os.MkdirAll(dir, os.ModeDir|os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, "."+filepath.Base(a.Path))
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if _, err := f.Write(a.Content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(a.Mode); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, a.Path)
+}
+
+// current returns an Artifact with the current content of the file at the
+// given path, or nil if the file doesn't exist
+func current(path string) (*Artifact, error) {
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Artifact{
+		Path:     path,
+		Mode:     fi.Mode().Perm(),
+		Content:  content,
+		Checksum: checksum(content),
+	}, nil
+}
@@ -0,0 +1,49 @@
+package artifact
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artifact")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = New("relative/path", []byte("content"), 0600)
+	assert.Error(t, err)
+
+	path := filepath.Join(dir, "sub", "ca.pem")
+	a, err := New(path, []byte("content"), 0600)
+	assert.NoError(t, err)
+	assert.False(t, a.Placed())
+
+	assert.NoError(t, a.Place())
+	assert.True(t, a.Placed())
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	// No temporary files are left behind
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	prev, err := current(path)
+	assert.NoError(t, err)
+	assert.Equal(t, a.Checksum, prev.Checksum)
+
+	a.Content = []byte("tampered")
+	assert.Equal(t, ErrChecksumMismatch, a.Place())
+	content, err = ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	missing, err := current(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,114 @@
+package artifact
+
+import (
+	"os"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	artifactsTxnKey = "artifacts"
+	// replacedTxnKey is the node result recording the files replaced by
+	// artifacts on a node, which are restored on undo
+	replacedTxnKey = "artifacts.replaced"
+)
+
+// replaced is the file found at the path of an artifact before the artifact
+// was placed. File is nil if no file existed.
+type replaced struct {
+	Path string    `json:"path"`
+	File *Artifact `json:"file"`
+}
+
+// Step stores the given artifacts in the transaction context and returns a
+// transaction step placing them on the given nodes. Files replaced by the
+// artifacts are restored if the transaction fails. Only one artifact step can
+// be added to a transaction.
+func Step(c transaction.TxnCtx, nodes []uuid.UUID, artifacts ...*Artifact) (*transaction.Step, error) {
+	if err := c.Set(artifactsTxnKey, artifacts); err != nil {
+		return nil, err
+	}
+
+	return &transaction.Step{
+		DoFunc:   "artifact.Place",
+		UndoFunc: "artifact.Restore",
+		Nodes:    nodes,
+	}, nil
+}
+
+func txnPlaceArtifacts(c transaction.TxnCtx) error {
+	var artifacts []*Artifact
+	if err := c.Get(artifactsTxnKey, &artifacts); err != nil {
+		return err
+	}
+
+	// Verify all artifacts before placing any of them
+	for _, a := range artifacts {
+		if err := a.Verify(); err != nil {
+			c.Logger().WithError(err).WithField("path", a.Path).Error("artifact verification failed")
+			return err
+		}
+	}
+
+	var replacedFiles []replaced
+	for _, a := range artifacts {
+		if a.Placed() {
+			continue
+		}
+
+		file, err := current(a.Path)
+		if err != nil {
+			return err
+		}
+		replacedFiles = append(replacedFiles, replaced{Path: a.Path, File: file})
+		// Record replaced files before every placement so that undo
+		// restores all files replaced so far
+		if err := c.SetNodeResult(gdctx.MyUUID, replacedTxnKey, replacedFiles); err != nil {
+			return err
+		}
+
+		if err := a.Place(); err != nil {
+			c.Logger().WithError(err).WithField("path", a.Path).Error("failed to place artifact")
+			return err
+		}
+		c.Logger().WithFields(log.Fields{
+			"path":     a.Path,
+			"checksum": a.Checksum,
+		}).Debug("placed artifact")
+	}
+	return nil
+}
+
+func txnRestoreArtifacts(c transaction.TxnCtx) error {
+	var replacedFiles []replaced
+	if err := c.GetNodeResult(gdctx.MyUUID, replacedTxnKey, &replacedFiles); err != nil {
+		// No files were replaced on this node
+		return nil
+	}
+
+	for _, r := range replacedFiles {
+		var err error
+		if r.File == nil {
+			if err = os.Remove(r.Path); os.IsNotExist(err) {
+				err = nil
+			}
+		} else {
+			err = r.File.Place()
+		}
+		if err != nil {
+			c.Logger().WithError(err).WithField("path", r.Path).Error("failed to restore file replaced by artifact")
+		}
+	}
+	return nil
+}
+
+// RegisterStepFuncs registers the transaction step functions used to place
+// artifacts
+func RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnPlaceArtifacts, "artifact.Place")
+	transaction.RegisterStepFunc(txnRestoreArtifacts, "artifact.Restore")
+}
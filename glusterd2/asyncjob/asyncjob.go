@@ -0,0 +1,274 @@
+// Package asyncjob runs long running operations in the background on behalf
+// of REST requests, recording their outcome in the store so that clients
+// can poll for it instead of holding the request open
+package asyncjob
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// jobPrefix holds the records of the jobs till they are pruned
+	jobPrefix = "async-jobs/"
+	// runningPrefix holds a key for each running job, attached to the
+	// store session of the peer running it, so that the jobs of a peer
+	// which goes away or restarts aren't reported as running forever
+	runningPrefix = "async-jobs-running/"
+
+	// Retention is how long the records of the finished jobs are kept
+	Retention = 24 * time.Hour
+
+	pruneJobName     = "async-jobs.prune"
+	pruneJobSchedule = "@every 1h"
+
+	errInterrupted = "job was interrupted, the peer running it went away or restarted"
+)
+
+// Events broadcast when a job finishes
+const (
+	EventJobCompleted = "job.completed"
+	EventJobFailed    = "job.failed"
+)
+
+// Func is the operation run by a job. The result is the response the
+// operation would have been answered with if run synchronously.
+type Func func(ctx context.Context) (interface{}, error)
+
+func jobKey(id string) string {
+	return jobPrefix + id
+}
+
+func save(job *api.AsyncJob) error {
+	b, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), jobKey(job.ID.String()), string(b))
+	return err
+}
+
+// Start runs f in the background as a job, and returns the job right away.
+// f gets a context which outlives the request ctx, carrying its request ID,
+// user and logger. errStatus gives the HTTP status of the errors returned by
+// f, to be recorded along with them.
+func Start(ctx context.Context, op, target string, f Func, errStatus func(error) int) (*api.AsyncJob, error) {
+	job := &api.AsyncJob{
+		ID:         uuid.NewRandom(),
+		Op:         op,
+		Target:     target,
+		State:      api.AsyncJobRunning,
+		Originator: gdctx.MyUUID,
+		ReqID:      gdctx.GetReqID(ctx),
+		User:       gdctx.GetReqUser(ctx),
+		StartedAt:  time.Now(),
+	}
+
+	b, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	_, err = store.Txn(context.TODO()).Then(
+		clientv3.OpPut(jobKey(job.ID.String()), string(b)),
+		clientv3.OpPut(runningPrefix+job.ID.String(), "", clientv3.WithLease(store.Store.Session.Lease())),
+	).Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	// The job outlives the request. Only the request ID and user are
+	// carried over for the transactions.
+	jobCtx := gdctx.WithReqID(context.Background(), job.ReqID)
+	jobCtx = gdctx.WithReqUser(jobCtx, job.User)
+	go run(jobCtx, *job, f, errStatus)
+
+	gdctx.Logger(ctx).WithFields(log.Fields{
+		"job-id": job.ID.String(),
+		"op":     op,
+	}).Info("async job started")
+	return job, nil
+}
+
+func run(ctx context.Context, job api.AsyncJob, f Func, errStatus func(error) int) {
+	logger := gdctx.Logger(ctx).WithFields(log.Fields{
+		"job-id": job.ID.String(),
+		"op":     job.Op,
+	})
+
+	result, err := f(ctx)
+	if err == nil && result != nil {
+		var b []byte
+		if b, err = json.Marshal(result); err == nil {
+			job.Result = b
+		}
+	}
+
+	job.FinishedAt = time.Now()
+	event := EventJobCompleted
+	if err != nil {
+		logger.WithError(err).Error("async job failed")
+		job.State = api.AsyncJobFailed
+		job.Error = err.Error()
+		job.ErrorStatus = http.StatusInternalServerError
+		if errStatus != nil {
+			job.ErrorStatus = errStatus(err)
+		}
+		event = EventJobFailed
+	} else {
+		logger.Info("async job completed")
+		job.State = api.AsyncJobSucceeded
+	}
+
+	if err := save(&job); err != nil {
+		logger.WithError(err).Error("failed to save the result of async job")
+	}
+	if _, err := store.Delete(context.TODO(), runningPrefix+job.ID.String()); err != nil {
+		logger.WithError(err).Warn("failed to remove running key of async job")
+	}
+
+	events.Broadcast(newJobEvent(event, &job))
+}
+
+func newJobEvent(name string, job *api.AsyncJob) *api.Event {
+	data := map[string]string{
+		"job.id":     job.ID.String(),
+		"job.op":     job.Op,
+		"job.target": job.Target,
+		"job.state":  job.State,
+	}
+	if job.Error != "" {
+		data["error"] = job.Error
+	}
+	return events.New(name, data, true)
+}
+
+// running returns the IDs of the jobs running on the peers which are up
+func running() (map[string]bool, error) {
+	resp, err := store.Get(context.TODO(), runningPrefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		ids[string(kv.Key)[len(runningPrefix):]] = true
+	}
+	return ids, nil
+}
+
+// checkInterrupted marks the job failed if it is recorded as running, but is
+// not running on any peer
+func checkInterrupted(job *api.AsyncJob, running map[string]bool) bool {
+	if job.State != api.AsyncJobRunning || running[job.ID.String()] {
+		return false
+	}
+	job.State = api.AsyncJobFailed
+	job.Error = errInterrupted
+	job.ErrorStatus = http.StatusInternalServerError
+	return true
+}
+
+// Get returns the job
+func Get(id string) (*api.AsyncJob, error) {
+	resp, err := store.Get(context.TODO(), jobKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrAsyncJobNotFound
+	}
+
+	var job api.AsyncJob
+	if err := json.Unmarshal(resp.Kvs[0].Value, &job); err != nil {
+		return nil, err
+	}
+
+	r, err := running()
+	if err != nil {
+		return nil, err
+	}
+	checkInterrupted(&job, r)
+	return &job, nil
+}
+
+// List returns the jobs, newest first
+func List() ([]*api.AsyncJob, error) {
+	resp, err := store.Get(context.TODO(), jobPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	r, err := running()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*api.AsyncJob, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var job api.AsyncJob
+		if err := json.Unmarshal(kv.Value, &job); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal async job")
+			continue
+		}
+		checkInterrupted(&job, r)
+		jobs = append(jobs, &job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].StartedAt.After(jobs[j].StartedAt) })
+	return jobs, nil
+}
+
+// RegisterJob registers the scheduled job recording the interrupted jobs
+// and pruning the records of the jobs finished before the retention period
+func RegisterJob() {
+	err := scheduler.Register(&scheduler.Job{
+		Name:        pruneJobName,
+		Description: "Records the interrupted async jobs, and prunes the async jobs finished before the retention period",
+		Schedule:    pruneJobSchedule,
+		Enabled:     true,
+		Func:        prune,
+	})
+	if err != nil {
+		log.WithError(err).WithField("job", pruneJobName).Error("failed to register scheduled job")
+	}
+}
+
+func prune(ctx context.Context) error {
+	jobs, err := List()
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		switch {
+		case job.State == api.AsyncJobRunning:
+			continue
+		case job.Error == errInterrupted && job.FinishedAt.IsZero():
+			// interrupted, recorded as failed from now on
+			job.FinishedAt = time.Now()
+			if err := save(job); err != nil {
+				return err
+			}
+			events.Broadcast(newJobEvent(EventJobFailed, job))
+		case time.Since(job.FinishedAt) > Retention:
+			if _, err := store.Delete(context.TODO(), jobKey(job.ID.String())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package asyncjob
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckInterrupted(t *testing.T) {
+	running := &api.AsyncJob{ID: uuid.NewRandom(), State: api.AsyncJobRunning}
+	lost := &api.AsyncJob{ID: uuid.NewRandom(), State: api.AsyncJobRunning}
+	done := &api.AsyncJob{ID: uuid.NewRandom(), State: api.AsyncJobSucceeded}
+	ids := map[string]bool{running.ID.String(): true}
+
+	assert.False(t, checkInterrupted(running, ids))
+	assert.Equal(t, api.AsyncJobRunning, running.State)
+
+	assert.True(t, checkInterrupted(lost, ids))
+	assert.Equal(t, api.AsyncJobFailed, lost.State)
+	assert.Equal(t, errInterrupted, lost.Error)
+
+	assert.False(t, checkInterrupted(done, ids))
+	assert.Equal(t, api.AsyncJobSucceeded, done.State)
+}
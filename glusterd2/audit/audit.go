@@ -0,0 +1,159 @@
+// Package audit records the REST requests which may change the state of the
+// cluster to an append-only log file of each peer, and to the store if
+// enabled with the cluster.audit-store cluster option
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	logFileOpt     = "audit-log-file"
+	defaultLogFile = "audit.log"
+
+	// StoreOpt enables saving the audit records in the store
+	StoreOpt = "cluster.audit-store"
+	// StoreRetentionOpt is the number of hours the audit records are
+	// kept in the store
+	StoreRetentionOpt = "cluster.audit-store-retention"
+
+	auditPrefix = "audit/"
+)
+
+var (
+	fileMu sync.Mutex
+	file   *os.File
+)
+
+// InitFlags intializes the command line options of the audit log
+func InitFlags() {
+	flag.String(logFileOpt, "", "File the REST requests which may change the cluster are recorded to, one JSON record per line. (default \"logdir/audit.log\")")
+}
+
+func logFilePath() string {
+	if p := config.GetString(logFileOpt); p != "" {
+		return p
+	}
+	return path.Join(config.GetString("logdir"), defaultLogFile)
+}
+
+// openFile opens the log file for appending, if not open yet. fileMu must be
+// held.
+func openFile() error {
+	if file != nil {
+		return nil
+	}
+	f, err := os.OpenFile(logFilePath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	file = f
+	return nil
+}
+
+// Reopen reopens the log file, so that a rotated log file is released
+func Reopen() {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	if file != nil {
+		file.Close()
+		file = nil
+	}
+	if err := openFile(); err != nil {
+		log.WithError(err).WithField("file", logFilePath()).Error("failed to reopen audit log")
+	}
+}
+
+// storeEnabled returns true if the audit records are to be saved in the store
+func storeEnabled() bool {
+	value, err := options.GetClusterOption(StoreOpt)
+	if err != nil {
+		return false
+	}
+	enabled, err := options.StringToBoolean(value)
+	return err == nil && enabled
+}
+
+// recordKey returns the store key of the record. Keys are zero padded so that
+// they sort by time.
+func recordKey(rec *api.AuditRecord) string {
+	return fmt.Sprintf("%s%020d-%s", auditPrefix, rec.Time.UnixNano(), rec.ReqID)
+}
+
+// Record appends the record to the log file, and saves it in the store if
+// enabled. Failures are logged, and don't fail the request.
+func Record(rec *api.AuditRecord) {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		log.WithError(err).Error("failed to marshal audit record")
+		return
+	}
+
+	fileMu.Lock()
+	if err = openFile(); err == nil {
+		_, err = file.Write(append(b, '\n'))
+	}
+	fileMu.Unlock()
+	if err != nil {
+		log.WithError(err).WithField("file", logFilePath()).Error("failed to write audit record")
+	}
+
+	if !storeEnabled() {
+		return
+	}
+	if _, err := store.Put(context.TODO(), recordKey(rec), string(b)); err != nil {
+		log.WithError(err).WithField("reqid", rec.ReqID.String()).Error("failed to save audit record in store")
+	}
+}
+
+// List returns the records saved in the store since the given time, oldest
+// first, of the given user if not empty. At most limit records are returned
+// if limit is not zero.
+func List(since time.Time, user string, limit int) ([]api.AuditRecord, error) {
+	resp, err := store.Get(context.TODO(), fmt.Sprintf("%s%020d", auditPrefix, since.UnixNano()),
+		clientv3.WithRange(clientv3.GetPrefixRangeEnd(auditPrefix)),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]api.AuditRecord, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var rec api.AuditRecord
+		if err := json.Unmarshal(kv.Value, &rec); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal audit record")
+			continue
+		}
+		if user != "" && rec.User != user {
+			continue
+		}
+		records = append(records, rec)
+		if limit != 0 && len(records) == limit {
+			break
+		}
+	}
+	return records, nil
+}
+
+// Prune deletes the records saved in the store before the given time
+func Prune(before time.Time) error {
+	_, err := store.Delete(context.TODO(), auditPrefix,
+		clientv3.WithRange(fmt.Sprintf("%s%020d", auditPrefix, before.UnixNano())))
+	return err
+}
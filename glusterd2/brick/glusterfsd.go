@@ -86,6 +86,12 @@ func (b *Glusterfsd) Args() []string {
 	return b.args
 }
 
+// SpawnEnv returns the environment the brick process is spawned in, as set
+// by the cluster options
+func (b *Glusterfsd) SpawnEnv() (*daemon.SpawnEnv, error) {
+	return getSpawnEnv()
+}
+
 // SocketFile returns path to the brick socket file used for IPC.
 func (b *Glusterfsd) SocketFile() string {
 
@@ -190,6 +196,12 @@ func (b Brickinfo) StartBrick(logger log.FieldLogger) error {
 		}
 	}
 
+	// The brick is not failed for want of I/O throttling, which is not
+	// possible on all systems
+	if err := applyIOLimits(b); err != nil {
+		logger.WithError(err).WithField("brick", b.String()).Warn("failed to set I/O limits of brick")
+	}
+
 	return nil
 }
 
@@ -251,9 +263,12 @@ func (b Brickinfo) StopBrick(logger log.FieldLogger) error {
 //CreateBrickSizeInfo parses size information for response
 func CreateBrickSizeInfo(size *SizeInfo) api.SizeInfo {
 	return api.SizeInfo{
-		Used:     size.Used,
-		Free:     size.Free,
-		Capacity: size.Capacity,
+		Used:        size.Used,
+		Free:        size.Free,
+		Capacity:    size.Capacity,
+		BlockSize:   size.BlockSize,
+		InodesTotal: size.InodesTotal,
+		InodesFree:  size.InodesFree,
 	}
 }
 
@@ -277,6 +292,9 @@ func CreateSizeInfo(fstat *syscall.Statfs_t) *SizeInfo {
 		s.Capacity = fstat.Blocks * uint64(fstat.Bsize)
 		s.Free = fstat.Bfree * uint64(fstat.Bsize)
 		s.Used = s.Capacity - s.Free
+		s.BlockSize = uint64(fstat.Bsize)
+		s.InodesTotal = fstat.Files
+		s.InodesFree = fstat.Ffree
 	}
 	return &s
 }
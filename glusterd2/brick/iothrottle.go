@@ -0,0 +1,144 @@
+package brick
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	blkioCgroupRoot = "/sys/fs/cgroup/blkio"
+	// ioThrottleCgroupParent is the blkio cgroup, relative to the root,
+	// under which brick processes with I/O limits are placed
+	ioThrottleCgroupParent = "/glusterd2"
+)
+
+// GetIOLimitsFunc returns the I/O limits of the brick, or nil if the I/O of
+// the brick is not limited. It is set by the volume package, which stores the
+// limits.
+var GetIOLimitsFunc func(b Brickinfo) (*api.IOLimits, error)
+
+// ioThrottleFiles maps the blkio throttle files to the limits they set
+var ioThrottleFiles = map[string]func(l api.IOLimits) uint64{
+	"blkio.throttle.read_iops_device":  func(l api.IOLimits) uint64 { return l.ReadIOPS },
+	"blkio.throttle.write_iops_device": func(l api.IOLimits) uint64 { return l.WriteIOPS },
+	"blkio.throttle.read_bps_device":   func(l api.IOLimits) uint64 { return l.ReadBPS },
+	"blkio.throttle.write_bps_device":  func(l api.IOLimits) uint64 { return l.WriteBPS },
+}
+
+// brickDevice returns the major:minor number of the block device the brick
+// is on. The throttling is done on whole disks, so for a brick on a partition
+// the disk of the partition is returned.
+func brickDevice(brickPath string) (string, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(brickPath, &st); err != nil {
+		return "", err
+	}
+	dev := fmt.Sprintf("%d:%d", unix.Major(uint64(st.Dev)), unix.Minor(uint64(st.Dev)))
+
+	sysDir, err := filepath.EvalSymlinks(path.Join("/sys/dev/block", dev))
+	if err != nil {
+		return "", fmt.Errorf("brick %s is not on a block device", brickPath)
+	}
+	if _, err := os.Stat(path.Join(sysDir, "partition")); err != nil {
+		return dev, nil
+	}
+
+	disk, err := ioutil.ReadFile(path.Join(path.Dir(sysDir), "dev"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(disk)), nil
+}
+
+// processCgroup returns the blkio cgroup of the process
+func processCgroup(pid int) (string, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// Lines are of the form <id>:<controllers>:<cgroup>
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		for _, c := range strings.Split(fields[1], ",") {
+			if c == "blkio" {
+				return fields[2], nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("process %d is not in a blkio cgroup, cgroup v1 blkio controller is required for I/O throttling", pid)
+}
+
+// SetIOLimits sets the I/O limits of the running brick process on the device
+// of the brick. The process is moved into a blkio cgroup of its own. With
+// brick multiplexing, the bricks attached to a process share its cgroup and
+// the limits are set on the device of each brick, so bricks of the process on
+// the same device share the limits set last.
+func SetIOLimits(b Brickinfo, limits api.IOLimits) error {
+	dev, err := brickDevice(b.Path)
+	if err != nil {
+		return err
+	}
+
+	running, pid := daemon.IsRunning(&Glusterfsd{brickinfo: b})
+	if !running {
+		// The limits are set when the brick is started
+		return nil
+	}
+
+	cgroup, err := processCgroup(pid)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(cgroup, ioThrottleCgroupParent+"/") {
+		cgroup = path.Join(ioThrottleCgroupParent, b.ID.String())
+	}
+
+	dir := path.Join(blkioCgroupRoot, cgroup)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// A zero limit removes the limit on the device
+	for file, limit := range ioThrottleFiles {
+		rule := dev + " " + strconv.FormatUint(limit(limits), 10)
+		if err := ioutil.WriteFile(path.Join(dir, file), []byte(rule), 0644); err != nil {
+			return fmt.Errorf("failed to set %s: %s", file, err)
+		}
+	}
+
+	return ioutil.WriteFile(path.Join(dir, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0644)
+}
+
+// applyIOLimits sets the I/O limits of the brick, if its volume has any
+func applyIOLimits(b Brickinfo) error {
+	if GetIOLimitsFunc == nil {
+		return nil
+	}
+
+	limits, err := GetIOLimitsFunc(b)
+	if err != nil || limits == nil {
+		return err
+	}
+
+	return SetIOLimits(b, *limits)
+}
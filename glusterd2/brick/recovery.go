@@ -0,0 +1,55 @@
+package brick
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	dhtLayoutXattrKey  = "trusted.glusterfs.dht"
+	dhtLayoutXattrSize = 16
+	afrXattrPrefix     = "trusted.afr."
+	ecXattrPrefix      = "trusted.ec."
+)
+
+// ReadLayout returns the DHT hash range assigned to the brick, from the layout
+// xattr on the brick root. The bricks of a subvolume have the same range.
+func ReadLayout(brickPath string) (start, stop uint32, ok bool) {
+	layout := make([]byte, dhtLayoutXattrSize)
+	size, err := unix.Getxattr(brickPath, dhtLayoutXattrKey, layout)
+	if err != nil || size != dhtLayoutXattrSize {
+		return 0, 0, false
+	}
+
+	// The layout is made of the commit hash, the layout type, and the
+	// start and stop of the hash range, in network byte order
+	return binary.BigEndian.Uint32(layout[8:12]), binary.BigEndian.Uint32(layout[12:16]), true
+}
+
+// ReadSubvolType guesses the type of the subvolume of the brick, replicate or
+// disperse, from the xattrs left by the replicate and disperse xlators on the
+// brick root. An empty string is returned if neither is found.
+func ReadSubvolType(brickPath string) string {
+	size, err := unix.Listxattr(brickPath, nil)
+	if err != nil || size <= 0 {
+		return ""
+	}
+	buf := make([]byte, size)
+	size, err = unix.Listxattr(brickPath, buf)
+	if err != nil {
+		return ""
+	}
+
+	for _, key := range bytes.Split(buf[:size], []byte{0}) {
+		switch {
+		case strings.HasPrefix(string(key), afrXattrPrefix):
+			return "replicate"
+		case strings.HasPrefix(string(key), ecXattrPrefix):
+			return "disperse"
+		}
+	}
+	return ""
+}
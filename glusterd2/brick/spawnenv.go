@@ -0,0 +1,119 @@
+package brick
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+// Cluster options controlling the environment brick processes are spawned in
+const (
+	umaskOpKey       = "cluster.brick-umask"
+	niceOpKey        = "cluster.brick-nice"
+	ioniceClassOpKey = "cluster.brick-ionice-class"
+	ioniceLevelOpKey = "cluster.brick-ionice-level"
+	oomScoreAdjOpKey = "cluster.brick-oom-score-adj"
+	noFileOpKey      = "cluster.brick-nofile"
+	userOpKey        = "cluster.brick-user"
+	groupOpKey       = "cluster.brick-group"
+)
+
+var spawnEnvOpKeys = []string{
+	umaskOpKey, niceOpKey, ioniceClassOpKey, ioniceLevelOpKey,
+	oomScoreAdjOpKey, noFileOpKey, userOpKey, groupOpKey,
+}
+
+// setSpawnEnvOption sets the field of the spawn environment corresponding to
+// the cluster option
+func setSpawnEnvOption(env *daemon.SpawnEnv, key, value string) error {
+	var err error
+
+	switch key {
+	case umaskOpKey:
+		env.Umask = value
+	case niceOpKey:
+		env.Nice, err = strconv.Atoi(value)
+	case ioniceClassOpKey:
+		env.IONiceClass = value
+	case ioniceLevelOpKey:
+		env.IONiceLevel, err = strconv.Atoi(value)
+	case oomScoreAdjOpKey:
+		env.OOMScoreAdj, err = strconv.Atoi(value)
+	case noFileOpKey:
+		env.NoFile, err = strconv.ParseUint(value, 10, 64)
+	case userOpKey:
+		env.User = value
+	case groupOpKey:
+		env.Group = value
+	}
+
+	if err != nil {
+		return errors.ErrInvalidIntValue
+	}
+	return nil
+}
+
+// getSpawnEnv returns the environment brick processes are spawned in as set
+// by the cluster options, along with the overrides for this peer
+func getSpawnEnv() (*daemon.SpawnEnv, error) {
+	c, err := options.GetLocalClusterOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	env := new(daemon.SpawnEnv)
+	for _, key := range spawnEnvOpKeys {
+		value := options.ClusterOptMap[key].DefaultValue
+		if v, ok := c.Options[key]; ok {
+			value = v
+		}
+		if err := setSpawnEnvOption(env, key, value); err != nil {
+			return nil, fmt.Errorf("%s: %s", key, err)
+		}
+	}
+
+	// The I/O priority level is meaningful only along with a class
+	if env.IONiceClass == "" {
+		env.IONiceLevel = 0
+	}
+
+	return env, nil
+}
+
+// validateSpawnEnvOption validates the brick spawn environment options
+func validateSpawnEnvOption(option, value string) error {
+	env := new(daemon.SpawnEnv)
+	if err := setSpawnEnvOption(env, option, value); err != nil {
+		return err
+	}
+	if err := env.Validate(); err != nil {
+		return err
+	}
+
+	switch option {
+	case userOpKey:
+		if value != "" {
+			if _, err := user.Lookup(value); err != nil {
+				return err
+			}
+		}
+	case groupOpKey:
+		if value != "" {
+			if _, err := user.LookupGroup(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	for _, key := range spawnEnvOpKeys {
+		options.RegisterClusterOpValidationFunc(key, validateSpawnEnvOption)
+	}
+}
@@ -51,9 +51,12 @@ type Brickinfo struct {
 
 // SizeInfo represents sizing information.
 type SizeInfo struct {
-	Capacity uint64
-	Used     uint64
-	Free     uint64
+	Capacity    uint64
+	Used        uint64
+	Free        uint64
+	BlockSize   uint64
+	InodesTotal uint64
+	InodesFree  uint64
 }
 
 //Brickstatus gives status of brick
@@ -160,7 +163,11 @@ func (b *Brickinfo) Validate(check InitChecks, allLocalBricks []Brickinfo) error
 		return err
 	}
 
-	if check.WasInUse {
+	if check.Adopt {
+		if err = validateBrickBelongsTo(b.Path, b.VolumeID); err != nil {
+			return err
+		}
+	} else if check.WasInUse {
 		if err = validateBrickWasUsed(b.Path); err != nil {
 			return err
 		}
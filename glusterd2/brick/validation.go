@@ -27,6 +27,10 @@ type InitChecks struct {
 	IsMount        bool
 	IsOnRoot       bool
 	CreateBrickDir bool
+	// Adopt requires the brick to already belong to the volume being
+	// created, as is the case when a volume is recreated from existing
+	// brick data.
+	Adopt bool
 }
 
 // PrepareChecks initializes InitChecks based on req
@@ -131,6 +135,40 @@ func validateBrickWasUsed(brickPath string) error {
 	return nil
 }
 
+// ReadVolumeID returns the volume ID stored in the volume-id xattr of the
+// given brick path
+func ReadVolumeID(brickPath string) (uuid.UUID, error) {
+	volumeIDBytes := make([]byte, volumeIDXattrSize)
+	size, err := unix.Getxattr(brickPath, volumeIDXattrKey, volumeIDBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xattr %s on %s: %s", volumeIDXattrKey, brickPath, err)
+	}
+	if size != volumeIDXattrSize {
+		return nil, fmt.Errorf("invalid xattr %s on %s", volumeIDXattrKey, brickPath)
+	}
+
+	return uuid.UUID(volumeIDBytes), nil
+}
+
+// validateBrickBelongsTo checks if the brick path holds data of the volume
+// with the given ID
+func validateBrickBelongsTo(brickPath string, volumeID uuid.UUID) error {
+	id, err := ReadVolumeID(brickPath)
+	if err != nil {
+		return err
+	}
+
+	if !uuid.Equal(id, volumeID) {
+		return fmt.Errorf("brick path %s belongs to volume %s, not %s", brickPath, id, volumeID)
+	}
+
+	if _, err := os.Stat(filepath.Join(brickPath, ".glusterfs")); err != nil {
+		return fmt.Errorf("brick path %s does not contain gluster data: %s", brickPath, err)
+	}
+
+	return nil
+}
+
 // isBrickInActiveUse checks if the path belongs to another active brick
 // belonging to an active volume currently present in this cluster.
 func isBrickInActiveUse(brickPath string, allLocalBricks []Brickinfo) error {
@@ -2,7 +2,6 @@ package bricksplanner
 
 import (
 	"errors"
-	"fmt"
 	"path"
 	"strconv"
 
@@ -11,6 +10,7 @@ import (
 	"github.com/gluster/glusterd2/pkg/lvmutils"
 	gutils "github.com/gluster/glusterd2/pkg/utils"
 
+	"github.com/pborman/uuid"
 	config "github.com/spf13/viper"
 )
 
@@ -67,8 +67,9 @@ func handleDisperseSubvolReq(req *api.VolCreateReq) error {
 
 // Based on the provided values like replica count, distribute count etc,
 // brick layout will be created. Peer and device information for bricks are
-// not available with the layout
-func getBricksLayout(req *api.VolCreateReq) ([]api.SubvolReq, error) {
+// not available with the layout. Bricks are named after the ID of the volume
+// and their subvolume index, starting at firstSubvol.
+func getBricksLayout(req *api.VolCreateReq, volID uuid.UUID, firstSubvol int) ([]api.SubvolReq, error) {
 	var err error
 	bricksMountRoot := path.Join(config.GetString("rundir"), "/bricks")
 
@@ -140,13 +141,14 @@ func getBricksLayout(req *api.VolCreateReq) ([]api.SubvolReq, error) {
 				return nil, errors.New("brick size is too small")
 			}
 			eachBrickTpSize := uint64(float64(eachBrickSize) * req.SnapshotReserveFactor)
+			tpName, lvName := volume.NewBrickLvNames(volID, firstSubvol+i+1, j+1)
 
 			bricks = append(bricks, api.BrickReq{
 				Type:           brickType,
-				Path:           fmt.Sprintf("%s/%s/subvol%d/brick%d/brick", bricksMountRoot, req.Name, i+1, j+1),
+				Path:           volume.NewBrickMountDir(bricksMountRoot, volID, firstSubvol+i+1, j+1) + "/brick",
 				BrickDirSuffix: "/brick",
-				TpName:         fmt.Sprintf("tp_%s_s%d_b%d", req.Name, i+1, j+1),
-				LvName:         fmt.Sprintf("brick_%s_s%d_b%d", req.Name, i+1, j+1),
+				TpName:         tpName,
+				LvName:         lvName,
 				Size:           lvmutils.NormalizeSize(eachBrickSize),
 				TpSize:         lvmutils.NormalizeSize(eachBrickTpSize),
 				TpMetadataSize: lvmutils.GetPoolMetadataSize(eachBrickTpSize),
@@ -167,8 +169,21 @@ func getBricksLayout(req *api.VolCreateReq) ([]api.SubvolReq, error) {
 	return subvols, nil
 }
 
-// PlanBricks creates the brick layout with chosen device and size information
-func PlanBricks(req *api.VolCreateReq) error {
+// PlanBricks creates the brick layout with chosen device and size information,
+// for the volume to be created with the ID
+func PlanBricks(req *api.VolCreateReq, volID uuid.UUID) error {
+	return planBricks(req, volID, 0)
+}
+
+// PlanExpansionBricks creates the brick layout of the subvolumes added to the
+// volume with the ID, which has numSubvols subvolumes, when expanding it by
+// size. The bricks of each new subvolume are placed in different zones.
+func PlanExpansionBricks(req *api.VolCreateReq, volID uuid.UUID, numSubvols int) error {
+	req.SubvolZonesOverlap = true
+	return planBricks(req, volID, numSubvols)
+}
+
+func planBricks(req *api.VolCreateReq, volID uuid.UUID, firstSubvol int) error {
 	availableVgs, err := GetAvailableVgs(req)
 	if err != nil {
 		return err
@@ -178,7 +193,7 @@ func PlanBricks(req *api.VolCreateReq) error {
 		return errors.New("no devices registered or available for allocating bricks")
 	}
 
-	subvols, err := getBricksLayout(req)
+	subvols, err := getBricksLayout(req, volID, firstSubvol)
 	if err != nil {
 		return err
 	}
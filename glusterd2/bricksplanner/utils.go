@@ -1,9 +1,7 @@
 package bricksplanner
 
 import (
-	"fmt"
 	"sort"
-	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/peer"
@@ -40,6 +38,11 @@ type Vg struct {
 // GetAvailableVgs returns VG list that can be used to create bricks
 func GetAvailableVgs(req *api.VolCreateReq) ([]Vg, error) {
 	var vgs []Vg
+	sel, err := peer.ParseSelector(req.PeerSelector)
+	if err != nil {
+		return nil, err
+	}
+
 	peers, err := peer.GetPeers()
 	if err != nil {
 		return nil, err
@@ -51,11 +54,13 @@ func GetAvailableVgs(req *api.VolCreateReq) ([]Vg, error) {
 			continue
 		}
 
-		peerzone, exists := p.Metadata["_zone"]
-		if !exists || strings.TrimSpace(peerzone) == "" {
-			peerzone = p.ID.String()
+		// Management nodes don't host bricks
+		if p.IsManagementOnly() {
+			continue
 		}
 
+		peerzone := p.Zone()
+
 		// If List of Peer IDs specified to limit choosing the bricks from
 		if len(req.LimitPeers) > 0 && !utils.StringInSlice(p.ID.String(), req.LimitPeers) {
 			continue
@@ -76,6 +81,11 @@ func GetAvailableVgs(req *api.VolCreateReq) ([]Vg, error) {
 			continue
 		}
 
+		// If peers are selected by their labels
+		if !sel.Matches(p.Labels) {
+			continue
+		}
+
 		deviceInfo, err := deviceutils.GetDevices(p.ID.String())
 		if err != nil {
 			return nil, err
@@ -115,7 +125,7 @@ func GetAvailableVgs(req *api.VolCreateReq) ([]Vg, error) {
 func GetNewBrick(availableVgs []Vg, brickInfo brick.Brickstatus, vol *volume.Volinfo, subVolIndex, brickIndex int) api.BrickReq {
 	var newBrick api.BrickReq
 	brickSize := brickInfo.Size.Capacity
-	lvName := fmt.Sprintf("brick_%s_s%d_b%d", vol.Name, subVolIndex, brickIndex)
+	tpName, lvName := vol.BrickLvNames(subVolIndex, brickIndex)
 	brickTpSize := uint64(float64(brickSize) * vol.SnapshotReserveFactor)
 	for _, vg := range availableVgs {
 		if vg.AvailableSize >= brickTpSize {
@@ -123,7 +133,7 @@ func GetNewBrick(availableVgs []Vg, brickInfo brick.Brickstatus, vol *volume.Vol
 				Type:           "brick",
 				Path:           brickInfo.Info.Path,
 				BrickDirSuffix: "/brick",
-				TpName:         fmt.Sprintf("tp_%s_s%d_b%d", vol.Name, subVolIndex, brickIndex),
+				TpName:         tpName,
 				LvName:         lvName,
 				Size:           brickSize,
 				TpSize:         brickTpSize,
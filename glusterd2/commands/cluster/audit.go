@@ -0,0 +1,84 @@
+package clustercommands
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/audit"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	auditPruneJobName     = "audit.prune"
+	auditPruneJobSchedule = "@every 1h"
+)
+
+func registerAuditJob() {
+	options.RegisterClusterOpValidationFunc(audit.StoreRetentionOpt, validateNonNegativeInt)
+
+	err := scheduler.Register(&scheduler.Job{
+		Name:        auditPruneJobName,
+		Description: "Prunes the audit records saved in the store past the retention period",
+		Schedule:    auditPruneJobSchedule,
+		Enabled:     true,
+		Func:        pruneAudit,
+	})
+	if err != nil {
+		log.WithError(err).WithField("job", auditPruneJobName).Error("failed to register scheduled job")
+	}
+}
+
+func pruneAudit(ctx context.Context) error {
+	hours, err := getIntClusterOption(audit.StoreRetentionOpt)
+	if err != nil {
+		return err
+	}
+	// The records are kept forever with a retention of 0
+	if hours == 0 {
+		return nil
+	}
+	return audit.Prune(time.Now().Add(-time.Duration(hours) * time.Hour))
+}
+
+// auditListHandler lists the audit records saved in the store, oldest first.
+// The records can be filtered with the since (RFC 3339 time), user and limit
+// query parameters.
+func auditListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	var since time.Time
+	if s := query.Get("since"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "since must be a time in RFC 3339 format")
+			return
+		}
+		since = t
+	}
+
+	var limit int
+	if l := query.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 0 {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "limit must be a non-negative number")
+			return
+		}
+		limit = n
+	}
+
+	records, err := audit.List(since, query.Get("user"), limit)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.AuditListResp(records))
+}
@@ -0,0 +1,167 @@
+package clustercommands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/coreos/etcd/clientv3"
+	config "github.com/spf13/viper"
+)
+
+// volatilePrefixes are the store prefixes holding the runtime state of the
+// peers, which must neither be backed up nor restored
+var volatilePrefixes = []string{
+	store.LivenessKeyPrefix,
+	"locks/",
+	"transaction/",
+	"pending-transaction/",
+	"ops/",
+	"transaction-history/",
+	"debug/failpoints/",
+	"events/",
+	"heavy-op-slots/",
+}
+
+var (
+	errBackupNotFound    = errors.New("backup not found")
+	errInvalidBackupName = errors.New("invalid backup name")
+	errNoBackupGiven     = errors.New("either a backup or the name of a saved backup is required")
+	errClusterIDMismatch = errors.New("backup was taken from a different cluster, use force to restore it")
+	errRestoreInProgress = errors.New("another restore is in progress")
+	errVolumesStarted    = errors.New("stop all volumes before restoring the cluster configuration")
+)
+
+const backupTimestampFormat = "20060102T150405.000Z"
+
+var (
+	// backupNameRE matches the names of saved backups, so that only files
+	// in the backup dir can be restored
+	backupNameRE = regexp.MustCompile(`^backup-[0-9TZ.]+\.json$`)
+	restoreMu    = &utils.MutexWithTry{}
+)
+
+func backupDir() string {
+	return config.GetString("backupdir")
+}
+
+func isVolatile(key string) bool {
+	for _, p := range volatilePrefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// createBackup reads the cluster configuration from the store
+func createBackup(ctx context.Context) (*api.ClusterBackup, error) {
+	resp, err := store.Get(ctx, "", clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+
+	backup := &api.ClusterBackup{
+		ClusterID: gdctx.MyClusterID.String(),
+		OpVersion: gdctx.OpVersion,
+		CreatedAt: time.Now().UTC(),
+		KVs:       []api.ClusterBackupKV{},
+	}
+	for _, kv := range resp.Kvs {
+		key := string(kv.Key)
+		if isVolatile(key) {
+			continue
+		}
+		backup.KVs = append(backup.KVs, api.ClusterBackupKV{Key: key, Value: kv.Value})
+	}
+
+	return backup, nil
+}
+
+// saveBackup saves the backup in the backup dir, readable only by the owner
+// as it may contain secrets
+func saveBackup(backup *api.ClusterBackup) (*api.ClusterBackupResp, error) {
+	if err := utils.InitDir(backupDir()); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(backup)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "backup-" + backup.CreatedAt.Format(backupTimestampFormat) + ".json"
+	p := path.Join(backupDir(), name)
+	if err := ioutil.WriteFile(p, data, 0600); err != nil {
+		return nil, err
+	}
+
+	return &api.ClusterBackupResp{
+		Name:      name,
+		Path:      p,
+		Keys:      len(backup.KVs),
+		CreatedAt: backup.CreatedAt,
+	}, nil
+}
+
+// loadBackup reads the named backup from the backup dir
+func loadBackup(name string) (*api.ClusterBackup, error) {
+	if !backupNameRE.MatchString(name) {
+		return nil, errInvalidBackupName
+	}
+
+	data, err := ioutil.ReadFile(path.Join(backupDir(), name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errBackupNotFound
+		}
+		return nil, err
+	}
+
+	var backup api.ClusterBackup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+// restoreBackup replaces the cluster configuration in the store with the one
+// in the backup. The runtime state of the peers in the store is left as is.
+func restoreBackup(ctx context.Context, backup *api.ClusterBackup) error {
+	resp, err := store.Get(ctx, "", clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return err
+	}
+
+	for _, kv := range resp.Kvs {
+		key := string(kv.Key)
+		if isVolatile(key) {
+			continue
+		}
+		if _, err := store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	for _, kv := range backup.KVs {
+		if isVolatile(kv.Key) {
+			continue
+		}
+		if _, err := store.Put(ctx, kv.Key, string(kv.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package clustercommands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsVolatile(t *testing.T) {
+	assert.True(t, isVolatile("alive/6d1d8d2f-0d6d-4f5a-8a2c-8d7d1d0c7a2e"))
+	assert.True(t, isVolatile("locks/testvol"))
+	assert.True(t, isVolatile("pending-transaction/abc"))
+	assert.False(t, isVolatile("volumes/testvol"))
+	assert.False(t, isVolatile("peers/6d1d8d2f-0d6d-4f5a-8a2c-8d7d1d0c7a2e"))
+	assert.False(t, isVolatile("config/events/webhooks/x"))
+}
+
+func TestBackupName(t *testing.T) {
+	name := "backup-" + time.Now().UTC().Format(backupTimestampFormat) + ".json"
+	assert.True(t, backupNameRE.MatchString(name))
+	assert.False(t, backupNameRE.MatchString("../store.toml"))
+	assert.False(t, backupNameRE.MatchString("backup-../../etc.json"))
+}
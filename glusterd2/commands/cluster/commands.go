@@ -0,0 +1,103 @@
+// Package clustercommands implements the commands which back up and restore
+// the cluster configuration held in the store, and manage the cluster wide
+// policies
+package clustercommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "ClusterBackup",
+			Description:  "Back up the cluster configuration held in the store",
+			Method:       "POST",
+			Pattern:      "/cluster/backup",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.ClusterBackupReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.ClusterBackup)(nil)),
+			HandlerFunc:  clusterBackupHandler,
+		},
+		route.Route{
+			Name:         "ClusterRestore",
+			Description:  "Restore the cluster configuration from a backup",
+			Method:       "POST",
+			Pattern:      "/cluster/restore",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.ClusterRestoreReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.ClusterRestoreResp)(nil)),
+			HandlerFunc:  clusterRestoreHandler,
+		},
+		route.Route{
+			Name:         "GetHealthPolicy",
+			Description:  "Get the intervals and thresholds of the health checks run by the peers",
+			Method:       "GET",
+			Pattern:      "/cluster/health-policy",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.HealthPolicy)(nil)),
+			HandlerFunc:  getHealthPolicyHandler,
+		},
+		route.Route{
+			Name:         "SetHealthPolicy",
+			Description:  "Set the intervals and thresholds of the health checks run by the peers, which is applied right away",
+			Method:       "POST",
+			Pattern:      "/cluster/health-policy",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.HealthPolicy)(nil)),
+			ResponseType: utils.GetTypeString((*api.HealthPolicy)(nil)),
+			HandlerFunc:  setHealthPolicyHandler,
+		},
+		route.Route{
+			Name:         "OpsList",
+			Description:  "List the transactions in progress in the cluster, with the step each one is running",
+			Method:       "GET",
+			Pattern:      "/ops",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.OpListResp)(nil)),
+			HandlerFunc:  opsListHandler,
+		},
+		route.Route{
+			Name:         "TxnList",
+			Description:  "List the transactions in progress and the recently completed ones, with the state of their steps on each node",
+			Method:       "GET",
+			Pattern:      "/transactions",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.TxnListResp)(nil)),
+			HandlerFunc:  txnListHandler,
+		},
+		route.Route{
+			Name:         "TxnGet",
+			Description:  "Get a transaction in progress or recently completed, with the state of its steps on each node",
+			Method:       "GET",
+			Pattern:      "/transactions/{txnid}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.Txn)(nil)),
+			HandlerFunc:  txnGetHandler,
+		},
+		route.Route{
+			Name:         "AuditList",
+			Description:  "List the audit records of the requests which may have changed the cluster, saved in the store if enabled by the cluster.audit-store option",
+			Method:       "GET",
+			Pattern:      "/audit",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.AuditListResp)(nil)),
+			Permission:   route.PermAdmin,
+			HandlerFunc:  auditListHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// the Glusterd Transaction framework. Required for the Command interface.
+func (c *Command) RegisterStepFuncs() {
+	registerTxnHistoryJob()
+	registerAuditJob()
+}
@@ -0,0 +1,49 @@
+package clustercommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/healthpolicy"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+func getHealthPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	policy, err := healthpolicy.Get()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, policy)
+}
+
+// setHealthPolicyHandler replaces the health policy. The peers apply it as
+// they see it changed in the store.
+func setHealthPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	var req api.HealthPolicy
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := healthpolicy.Validate(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := healthpolicy.Set(&req); err != nil {
+		logger.WithError(err).Error("failed to save health policy")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("policy", req).Info("health policy changed")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, &req)
+}
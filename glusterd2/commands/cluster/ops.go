@@ -0,0 +1,66 @@
+package clustercommands
+
+import (
+	"net/http"
+	"time"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// opsListHandler lists the transactions in progress on all the peers, which
+// include the ones run by scheduled jobs
+func opsListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ops, err := transaction.GetOps()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	now := time.Now()
+	resp := make(api.OpListResp, 0, len(ops))
+	for _, o := range ops {
+		resp = append(resp, createOpResp(o, now))
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// createOpResp returns the API representation of the transaction record. The
+// elapsed time of the transactions in progress is counted till now.
+func createOpResp(o *transaction.Op, now time.Time) api.Op {
+	targets := o.Locks
+	if targets == nil {
+		targets = []string{}
+	}
+	rollback := make([]api.OpRollbackStep, 0, len(o.Rollback))
+	for _, r := range o.Rollback {
+		rollback = append(rollback, api.OpRollbackStep{
+			UndoFunc: r.UndoFunc,
+			Node:     r.Node,
+			Error:    r.Error,
+		})
+	}
+	if !o.FinishedAt.IsZero() {
+		now = o.FinishedAt
+	}
+
+	return api.Op{
+		TxnID:       o.TxnID,
+		ReqID:       o.ReqID,
+		Operation:   o.Operation,
+		Targets:     targets,
+		Originator:  o.Originator,
+		User:        o.User,
+		Step:        o.Step,
+		StepIndex:   o.StepIndex,
+		StepCount:   o.StepCount,
+		StartedAt:   o.StartedAt,
+		Elapsed:     now.Sub(o.StartedAt).Round(time.Second).String(),
+		RollingBack: o.RollingBack,
+		Rollback:    rollback,
+	}
+}
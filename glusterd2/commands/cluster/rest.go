@@ -0,0 +1,112 @@
+package clustercommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+func clusterBackupHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	var req api.ClusterBackupReq
+	if r.ContentLength != 0 {
+		if err := restutils.UnmarshalRequest(r, &req); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+			return
+		}
+	}
+
+	backup, err := createBackup(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to read cluster configuration from store")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !req.Save {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, backup)
+		return
+	}
+
+	resp, err := saveBackup(backup)
+	if err != nil {
+		logger.WithError(err).Error("failed to save cluster backup")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("path", resp.Path).Info("saved cluster backup")
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+}
+
+// clusterRestoreHandler restores the cluster configuration from a backup.
+// The daemons of the peers are not reconciled with the restored
+// configuration, glusterd2 needs to be restarted on all peers for that.
+func clusterRestoreHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	var req api.ClusterRestoreReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	backup := req.Backup
+	if req.Name != "" {
+		var err error
+		if backup, err = loadBackup(req.Name); err != nil {
+			switch err {
+			case errInvalidBackupName:
+				restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			case errBackupNotFound:
+				restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+			default:
+				restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			}
+			return
+		}
+	}
+	if backup == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errNoBackupGiven)
+		return
+	}
+
+	if backup.ClusterID != gdctx.MyClusterID.String() && !req.Force {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errClusterIDMismatch)
+		return
+	}
+
+	if !restoreMu.TryLock() {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, errRestoreInProgress)
+		return
+	}
+	defer restoreMu.Unlock()
+
+	vols, err := volume.GetVolumes(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	for _, v := range vols {
+		if v.State == volume.VolStarted {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, errVolumesStarted)
+			return
+		}
+	}
+
+	if err := restoreBackup(ctx, backup); err != nil {
+		logger.WithError(err).Error("failed to restore cluster configuration")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("created-at", backup.CreatedAt).Info("restored cluster configuration from backup")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.ClusterRestoreResp{Keys: len(backup.KVs)})
+}
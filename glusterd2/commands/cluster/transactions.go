@@ -0,0 +1,160 @@
+package clustercommands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// txnHistoryRetentionOpt is the number of hours the records of the
+	// completed transactions are kept
+	txnHistoryRetentionOpt = "cluster.txn-history-retention"
+	// txnHistoryMaxOpt is the number of records of the completed
+	// transactions kept, the oldest being pruned first
+	txnHistoryMaxOpt = "cluster.txn-history-max"
+
+	txnHistoryPruneJobName     = "transaction.history-prune"
+	txnHistoryPruneJobSchedule = "@every 10m"
+)
+
+func validateNonNegativeInt(key, value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return errors.New(key + " must be a non-negative number")
+	}
+	return nil
+}
+
+func registerTxnHistoryJob() {
+	options.RegisterClusterOpValidationFunc(txnHistoryRetentionOpt, validateNonNegativeInt)
+	options.RegisterClusterOpValidationFunc(txnHistoryMaxOpt, validateNonNegativeInt)
+
+	err := scheduler.Register(&scheduler.Job{
+		Name:        txnHistoryPruneJobName,
+		Description: "Prunes the records of the completed transactions past the retention period or the maximum number of records",
+		Schedule:    txnHistoryPruneJobSchedule,
+		Enabled:     true,
+		Func:        pruneTxnHistory,
+	})
+	if err != nil {
+		log.WithError(err).WithField("job", txnHistoryPruneJobName).Error("failed to register scheduled job")
+	}
+}
+
+func getIntClusterOption(name string) (int, error) {
+	value, err := options.GetClusterOption(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+func pruneTxnHistory(ctx context.Context) error {
+	hours, err := getIntClusterOption(txnHistoryRetentionOpt)
+	if err != nil {
+		return err
+	}
+	max, err := getIntClusterOption(txnHistoryMaxOpt)
+	if err != nil {
+		return err
+	}
+
+	pruned, err := transaction.PruneHistory(time.Now().Add(-time.Duration(hours)*time.Hour), max)
+	if pruned != 0 {
+		log.WithField("records", pruned).Debug("pruned transaction history")
+	}
+	return err
+}
+
+// createTxnResp returns the API representation of the transaction record
+// with the state of its steps
+func createTxnResp(o *transaction.Op, now time.Time) api.Txn {
+	resp := api.Txn{
+		Op:         createOpResp(o, now),
+		State:      o.State,
+		Error:      o.Error,
+		FinishedAt: o.FinishedAt,
+		Steps:      make([]api.TxnStep, 0, len(o.Steps)),
+	}
+
+	for _, s := range o.Steps {
+		step := api.TxnStep{
+			Step:       s.DoFunc,
+			Skipped:    s.Skipped,
+			Nodes:      make([]api.TxnStepNode, 0, len(s.Nodes)),
+			StartedAt:  s.StartedAt,
+			FinishedAt: s.FinishedAt,
+		}
+		for _, n := range s.Nodes {
+			step.Nodes = append(step.Nodes, api.TxnStepNode{
+				Node:  n.Node,
+				State: n.State,
+				Error: n.Error,
+			})
+		}
+		resp.Steps = append(resp.Steps, step)
+	}
+	return resp
+}
+
+// txnListHandler lists the transactions in progress, oldest first, followed
+// by the completed transactions kept in the history, newest first. The list
+// can be filtered by state with the state query parameter.
+func txnListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := r.URL.Query().Get("state")
+
+	ops, err := transaction.GetOps()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	history, err := transaction.GetHistory()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	now := time.Now()
+	resp := make(api.TxnListResp, 0, len(ops)+len(history))
+	for _, o := range append(ops, history...) {
+		if state != "" && o.State != state {
+			continue
+		}
+		resp = append(resp, createTxnResp(o, now))
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func txnGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	txnID := uuid.Parse(mux.Vars(r)["txnid"])
+	if txnID == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid transaction ID")
+		return
+	}
+
+	o, err := transaction.GetOp(txnID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createTxnResp(o, time.Now()))
+}
@@ -2,9 +2,18 @@
 package commands
 
 import (
+	"github.com/gluster/glusterd2/glusterd2/commands/cluster"
+	"github.com/gluster/glusterd2/glusterd2/commands/debug"
+	"github.com/gluster/glusterd2/glusterd2/commands/exporters"
+	"github.com/gluster/glusterd2/glusterd2/commands/federation"
+	"github.com/gluster/glusterd2/glusterd2/commands/ioops"
+	"github.com/gluster/glusterd2/glusterd2/commands/jobs"
 	"github.com/gluster/glusterd2/glusterd2/commands/options"
 	"github.com/gluster/glusterd2/glusterd2/commands/peers"
+	"github.com/gluster/glusterd2/glusterd2/commands/scheduledjobs"
 	"github.com/gluster/glusterd2/glusterd2/commands/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/commands/supportbundle"
+	"github.com/gluster/glusterd2/glusterd2/commands/users"
 	"github.com/gluster/glusterd2/glusterd2/commands/version"
 	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
@@ -25,4 +34,13 @@ var Commands = []Command{
 	&snapshotcommands.Command{},
 	&peercommands.Command{},
 	&optionscommands.Command{},
+	&debugcommands.Command{},
+	&supportbundlecommands.Command{},
+	&scheduledjobscommands.Command{},
+	&clustercommands.Command{},
+	&exporterscommands.Command{},
+	&federationcommands.Command{},
+	&ioopscommands.Command{},
+	&jobscommands.Command{},
+	&userscommands.Command{},
 }
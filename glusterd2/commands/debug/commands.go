@@ -0,0 +1,69 @@
+// Package debugcommands implements the developer mode debug commands
+package debugcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+// Failure injection routes are only available when developer mode is
+// enabled.
+func (c *Command) Routes() route.Routes {
+	routes := route.Routes{
+		route.Route{
+			Name:         "MsgBusSubscriptionList",
+			Description:  "List the message bus subscriptions of the peer",
+			Method:       "GET",
+			Pattern:      "/debug/msgbus/subscriptions",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.MsgBusSubscriptionsResp)(nil)),
+			HandlerFunc:  msgBusSubscriptionListHandler,
+		},
+	}
+
+	if !transaction.FailPointsEnabled() {
+		return routes
+	}
+
+	return append(routes, route.Routes{
+		route.Route{
+			Name:         "FailPointArm",
+			Description:  "Arm a failure injection point in the transaction framework",
+			Method:       "POST",
+			Pattern:      "/debug/failpoints",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.FailPoint)(nil)),
+			ResponseType: utils.GetTypeString((*api.FailPoint)(nil)),
+			HandlerFunc:  failPointArmHandler,
+		},
+		route.Route{
+			Name:         "FailPointList",
+			Description:  "List armed failure injection points",
+			Method:       "GET",
+			Pattern:      "/debug/failpoints",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.FailPointListResp)(nil)),
+			HandlerFunc:  failPointListHandler,
+		},
+		route.Route{
+			Name:        "FailPointDisarm",
+			Description: "Disarm a failure injection point",
+			Method:      "DELETE",
+			Pattern:     "/debug/failpoints/{name}",
+			Version:     1,
+			HandlerFunc: failPointDisarmHandler,
+		},
+	}...)
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,62 @@
+package debugcommands
+
+import (
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func failPointArmHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.FailPoint
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := transaction.ValidateFailPoint(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := transaction.ArmFailPoint(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, req)
+}
+
+func failPointListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	fps, err := transaction.GetFailPoints()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.FailPointListResp(fps))
+}
+
+func failPointDisarmHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := mux.Vars(r)["name"]
+
+	if err := transaction.DisarmFailPoint(name); err != nil {
+		if err == transaction.ErrFailPointNotFound {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		} else {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,14 @@
+package debugcommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/msgbus"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+func msgBusSubscriptionListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.MsgBusSubscriptionsResp(msgbus.Subscriptions()))
+}
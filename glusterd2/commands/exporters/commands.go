@@ -0,0 +1,52 @@
+// Package exporterscommands implements the REST endpoints to configure the
+// exporters shipping the cluster events and the audit log to external
+// collectors
+package exporterscommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "ExporterSet",
+			Description:  "Add or update an exporter of the cluster events and the audit log",
+			Method:       "POST",
+			Pattern:      "/exporters",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.Exporter)(nil)),
+			ResponseType: utils.GetTypeString((*api.Exporter)(nil)),
+			HandlerFunc:  exporterSetHandler,
+		},
+		route.Route{
+			Name:         "ExporterList",
+			Description:  "List the exporters along with their statistics on this peer",
+			Method:       "GET",
+			Pattern:      "/exporters",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ExporterListResp)(nil)),
+			HandlerFunc:  exporterListHandler,
+		},
+		route.Route{
+			Name:        "ExporterDelete",
+			Description: "Delete an exporter",
+			Method:      "DELETE",
+			Pattern:     "/exporters/{name}",
+			Version:     1,
+			HandlerFunc: exporterDeleteHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
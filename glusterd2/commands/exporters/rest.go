@@ -0,0 +1,87 @@
+package exporterscommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/exporter"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func exporterSetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.Exporter
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := exporter.Validate(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := exporter.Add(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, redact(req))
+}
+
+func exporterListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	confs, err := exporter.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := api.ExporterListResp{}
+	for _, conf := range confs {
+		resp = append(resp, api.ExporterInfo{
+			Exporter: redact(*conf),
+			Stats:    exporter.Stats(conf.Name),
+		})
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func exporterDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := exporter.Delete(mux.Vars(r)["name"])
+	if err == exporter.ErrExporterNotFound {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// redact returns the exporter without the credentials of the collector
+func redact(e api.Exporter) api.Exporter {
+	if e.HTTP != nil {
+		conf := *e.HTTP
+		if conf.Token != "" {
+			conf.Token = "<redacted>"
+		}
+		// Headers may carry API keys too
+		headers := make(map[string]string, len(conf.Headers))
+		for k := range conf.Headers {
+			headers[k] = "<redacted>"
+		}
+		conf.Headers = headers
+		e.HTTP = &conf
+	}
+	return e
+}
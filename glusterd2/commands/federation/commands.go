@@ -0,0 +1,60 @@
+// Package federationcommands implements the REST endpoints to register other
+// glusterd2 clusters and to get the federated view of their volumes
+package federationcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "FederatedClusterAdd",
+			Description:  "Register or update another cluster in the federated view",
+			Method:       "POST",
+			Pattern:      "/federation/clusters",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.FederatedCluster)(nil)),
+			ResponseType: utils.GetTypeString((*api.FederatedCluster)(nil)),
+			HandlerFunc:  federatedClusterAddHandler,
+		},
+		route.Route{
+			Name:         "FederatedClusterList",
+			Description:  "List the clusters registered in the federated view",
+			Method:       "GET",
+			Pattern:      "/federation/clusters",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.FederatedClusterListResp)(nil)),
+			HandlerFunc:  federatedClusterListHandler,
+		},
+		route.Route{
+			Name:        "FederatedClusterDelete",
+			Description: "Remove a cluster from the federated view",
+			Method:      "DELETE",
+			Pattern:     "/federation/clusters/{name}",
+			Version:     1,
+			HandlerFunc: federatedClusterDeleteHandler,
+		},
+		route.Route{
+			Name:         "FederatedVolumes",
+			Description:  "List the volumes and their health across the registered clusters",
+			Method:       "GET",
+			Pattern:      "/federation/volumes",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.FederatedVolumesResp)(nil)),
+			HandlerFunc:  federatedVolumesHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,89 @@
+package federationcommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/federation"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func federatedClusterAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.FederatedCluster
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := federation.Validate(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := federation.Add(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, redact(req))
+}
+
+func federatedClusterListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clusters, err := federation.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := api.FederatedClusterListResp{}
+	for _, c := range clusters {
+		resp = append(resp, redact(*c))
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func federatedClusterDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := federation.Delete(mux.Vars(r)["name"])
+	if err == federation.ErrClusterNotFound {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+func federatedVolumesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp, err := federation.View(r.URL.Query()["cluster"]...)
+	if err == federation.ErrClusterNotFound {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// redact returns the cluster without the secret used to authenticate to it
+func redact(c api.FederatedCluster) api.FederatedCluster {
+	if c.Secret != "" {
+		c.Secret = "<redacted>"
+	}
+	return c
+}
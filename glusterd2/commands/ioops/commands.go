@@ -0,0 +1,42 @@
+// Package ioopscommands implements the REST endpoints to list and cancel the
+// coordinated IO intensive operations
+package ioopscommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/ioops"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "IOOpsList",
+			Description:  "List the running, throttled and queued scrub, rebalance and full heal operations",
+			Method:       "GET",
+			Pattern:      "/io-ops",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.IOOpListResp)(nil)),
+			HandlerFunc:  ioOpsListHandler,
+		},
+		route.Route{
+			Name:        "IOOpCancel",
+			Description: "Remove a queued operation from the queue",
+			Method:      "DELETE",
+			Pattern:     "/io-ops/{op}/{volname}",
+			Version:     1,
+			HandlerFunc: ioOpCancelHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	ioops.RegisterJob()
+}
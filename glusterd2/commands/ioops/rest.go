@@ -0,0 +1,35 @@
+package ioopscommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/ioops"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+
+	"github.com/gorilla/mux"
+)
+
+func ioOpsListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp, err := ioops.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func ioOpCancelHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	if err := ioops.Cancel(ctx, ioops.Op(vars["op"]), vars["volname"]); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
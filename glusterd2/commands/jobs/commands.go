@@ -0,0 +1,43 @@
+// Package jobscommands implements the REST endpoints to poll the long running
+// operations run as async jobs
+package jobscommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/asyncjob"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "AsyncJobList",
+			Description:  "List the operations run as async jobs",
+			Method:       "GET",
+			Pattern:      "/jobs",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.AsyncJobListResp)(nil)),
+			HandlerFunc:  jobListHandler,
+		},
+		route.Route{
+			Name:         "AsyncJobStatus",
+			Description:  "Get the state of an async job, and its result once finished",
+			Method:       "GET",
+			Pattern:      "/jobs/{jobid}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.AsyncJob)(nil)),
+			HandlerFunc:  jobStatusHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	asyncjob.RegisterJob()
+}
@@ -0,0 +1,47 @@
+package jobscommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/asyncjob"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+func jobListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	jobs, err := asyncjob.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.AsyncJobListResp, 0, len(jobs))
+	for _, job := range jobs {
+		resp = append(resp, *job)
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func jobStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	jobid := mux.Vars(r)["jobid"]
+	if uuid.Parse(jobid) == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	job, err := asyncjob.Get(jobid)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, job)
+}
@@ -3,6 +3,8 @@ package optionscommands
 
 import (
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
 )
 
 // Command is a holding struct used to implement the GlusterD Command interface
@@ -19,6 +21,17 @@ func (c *Command) Routes() route.Routes {
 			Version:     1,
 			HandlerFunc: setClusterOptionsHandler,
 		},
+		route.Route{
+			Name:         "ClusterOptionsImpact",
+			Method:       "POST",
+			Pattern:      "/cluster/options/impact",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.ClusterOptionReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.OptionImpactResp)(nil)),
+			HandlerFunc:  clusterOptionsImpactHandler,
+			// Dry run, nothing is changed
+			AllowReadOnly: true,
+		},
 		route.Route{
 			Name:        "GetClusterOptions",
 			Method:      "GET",
@@ -26,6 +39,41 @@ func (c *Command) Routes() route.Routes {
 			Version:     1,
 			HandlerFunc: getClusterOptionsHandler,
 		},
+		route.Route{
+			Name:         "GetReadOnlyMode",
+			Method:       "GET",
+			Pattern:      "/cluster/readonly",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ReadOnlyModeResp)(nil)),
+			HandlerFunc:  getReadOnlyModeHandler,
+		},
+		route.Route{
+			Name:         "SetReadOnlyMode",
+			Method:       "PUT",
+			Pattern:      "/cluster/readonly",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.ReadOnlyModeReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.ReadOnlyModeResp)(nil)),
+			HandlerFunc:  setReadOnlyModeHandler,
+			// Always allowed so that the read-only mode can be disabled
+			AllowReadOnly: true,
+		},
+		route.Route{
+			Name:         "GetOptionDocs",
+			Method:       "GET",
+			Pattern:      "/options",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.OptionDocListResp)(nil)),
+			HandlerFunc:  getOptionDocsHandler,
+		},
+		route.Route{
+			Name:         "GetOptionDoc",
+			Method:       "GET",
+			Pattern:      "/options/{optname:.*}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.OptionDoc)(nil)),
+			HandlerFunc:  getOptionDocHandler,
+		},
 	}
 }
 
@@ -0,0 +1,168 @@
+package optionscommands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+// clusterOptionProcesses maps the cluster options to the kind of process they
+// apply to. The options are applied when the processes are next started. The
+// options not listed don't affect running processes.
+var clusterOptionProcesses = map[string]string{
+	"cluster.brick-multiplex":        "brick",
+	"cluster.max-bricks-per-process": "brick",
+	"cluster.localtime-logging":      "brick",
+	"cluster.brick-umask":            "brick",
+	"cluster.brick-nice":             "brick",
+	"cluster.brick-ionice-class":     "brick",
+	"cluster.brick-ionice-level":     "brick",
+	"cluster.brick-oom-score-adj":    "brick",
+	"cluster.brick-nofile":           "brick",
+	"cluster.brick-user":             "brick",
+	"cluster.brick-group":            "brick",
+	"cluster.glustershd-systemd":     "glustershd",
+	"cluster.scrubd-systemd":         "scrubd",
+	"cluster.gsyncd-systemd":         "gsyncd",
+}
+
+// overriddenPeers returns the peers which override the cluster option, and
+// aren't affected by changing it
+func overriddenPeers(key string, peerIDs []string) (map[string]bool, error) {
+	overridden := make(map[string]bool)
+	if !options.PeerOverridableOptions[key] {
+		return overridden, nil
+	}
+	for _, id := range peerIDs {
+		opts, err := options.GetPeerOptions(id)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := opts[key]; ok {
+			overridden[id] = true
+		}
+	}
+	return overridden, nil
+}
+
+// clusterOptionImpact analyses the impact of changing the cluster options
+func clusterOptionImpact(ctx context.Context, opts map[string]string) (*api.OptionImpactResp, error) {
+	peers, err := peer.GetPeerIDs()
+	if err != nil {
+		return nil, err
+	}
+	peerIDs := make([]string, 0, len(peers))
+	for _, p := range peers {
+		peerIDs = append(peerIDs, p.String())
+	}
+
+	vols, err := volume.GetVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.OptionImpactResp{Options: []api.OptionImpact{}}
+
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// Processes affected by several options are reported once
+	seen := make(map[api.ProcessImpact]bool)
+	report := func(p api.ProcessImpact) {
+		if !seen[p] {
+			seen[p] = true
+			resp.Processes = append(resp.Processes, p)
+		}
+	}
+
+	for _, k := range keys {
+		resp.Options = append(resp.Options, api.OptionImpact{Option: k, Value: opts[k]})
+
+		process, ok := clusterOptionProcesses[k]
+		if !ok {
+			continue
+		}
+
+		overridden, err := overriddenPeers(k, peerIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range peerIDs {
+			if overridden[id] {
+				resp.Warnings = append(resp.Warnings,
+					fmt.Sprintf("%s is overridden on peer %s, which is not affected", k, id))
+			} else if process != "brick" {
+				report(api.ProcessImpact{PeerID: id, Process: process, Action: api.ImpactNextStart})
+			}
+		}
+		if process != "brick" {
+			continue
+		}
+
+		for _, v := range vols {
+			if v.State != volume.VolStarted {
+				continue
+			}
+			for _, b := range v.GetBricks() {
+				if overridden[b.PeerID.String()] {
+					continue
+				}
+				report(api.ProcessImpact{
+					PeerID:  b.PeerID.String(),
+					Process: process,
+					Name:    b.Path,
+					Volume:  v.Name,
+					Action:  api.ImpactNextStart,
+				})
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// clusterOptionsImpactHandler reports the impact of setting the cluster
+// options, without setting them
+func clusterOptionsImpactHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.ClusterOptionReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	for k, v := range req.Options {
+		opt, found := options.ClusterOptMap[k]
+		if !found {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Sprintf("Invalid global option: %s", k))
+			return
+		}
+		if opt.ValidateFunc != nil {
+			if err := opt.ValidateFunc(k, v); err != nil {
+				restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+					fmt.Sprintf("%s failed validation: %s", k, err))
+				return
+			}
+		}
+	}
+
+	resp, err := clusterOptionImpact(ctx, req.Options)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
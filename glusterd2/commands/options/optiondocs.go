@@ -0,0 +1,32 @@
+package optionscommands
+
+import (
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+
+	"github.com/gorilla/mux"
+)
+
+func getOptionDocsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, xlator.OptionDocs(false))
+}
+
+func getOptionDocHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	optname := mux.Vars(r)["optname"]
+	opt, err := xlator.FindOption(optname)
+	if err != nil {
+		if _, ok := err.(xlator.OptionNotFoundError); ok {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+			return
+		}
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, xlator.CreateOptionDoc(optname, opt))
+}
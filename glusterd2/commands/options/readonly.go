@@ -0,0 +1,58 @@
+package optionscommands
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+func getReadOnlyModeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	m, err := options.GetReadOnlyMode()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, m)
+}
+
+func setReadOnlyModeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	var req api.ReadOnlyModeReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if req.Enabled && req.Reason == "" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "a reason is required to enable the read-only mode")
+		return
+	}
+
+	m := &api.ReadOnlyModeResp{}
+	if req.Enabled {
+		m = &api.ReadOnlyModeResp{
+			Enabled: true,
+			Reason:  req.Reason,
+			User:    gdctx.GetReqUser(ctx),
+			Since:   time.Now(),
+		}
+	}
+
+	if err := options.SetReadOnlyMode(m); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("enabled", m.Enabled).WithField("reason", m.Reason).Info("cluster read-only mode changed")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, m)
+}
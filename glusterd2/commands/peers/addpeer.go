@@ -23,7 +23,7 @@ const (
 func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	var req api.PeerAddReq
 	if err := restutils.UnmarshalRequest(r, &req); err != nil {
@@ -70,6 +70,21 @@ func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	report, err := runPreflight(ctx, remotePeerAddress)
+	if err != nil {
+		logger.WithError(err).WithField("address", remotePeerAddress).Error("failed to run preflight checks")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !report.Passed {
+		if !req.Force {
+			restutils.SendHTTPError(ctx, w, http.StatusPreconditionFailed, &preflightError{report})
+			return
+		}
+		logger.WithField("address", remotePeerAddress).Warn("preflight checks failed, adding peer as forced")
+	}
+
 	// TODO: Try all addresses till the first one connects
 	client, err := getPeerServiceClient(remotePeerAddress)
 	if err != nil {
@@ -91,7 +106,7 @@ func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 	} else if Error(rsp.Err) != ErrNone {
 		err = Error(rsp.Err)
 		logger.WithError(err).Error("join request failed")
-		if rsp.Err == int32(ErrAnotherReqInProgress) {
+		if rsp.Err == int32(ErrAnotherReqInProgress) || rsp.Err == int32(ErrPeerIDCollision) {
 			restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
 		} else {
 			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
@@ -110,7 +125,7 @@ func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.Zone != "" {
-		newpeer.Metadata["_zone"] = req.Zone
+		newpeer.Metadata[peer.ZoneKey] = req.Zone
 	}
 
 	for key, value := range req.Metadata {
@@ -151,5 +166,6 @@ func createPeerAddResp(p *peer.Peer) *api.PeerAddResp {
 		PeerAddresses:   p.PeerAddresses,
 		ClientAddresses: p.ClientAddresses,
 		Metadata:        p.Metadata,
+		Labels:          p.Labels,
 	}
 }
@@ -0,0 +1,32 @@
+package peercommands
+
+import (
+	"os"
+	"path"
+
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// cleanupLocalState removes the state left behind on this peer by the cluster
+// it has left, the generated volfiles and the pidfiles of daemons which are no
+// longer running, so that the peer can later join a cluster afresh.
+//
+// The daemons of the old cluster need to be stopped with
+// daemon.StopAllDaemons before the store of the old cluster is gone.
+func cleanupLocalState(logger log.FieldLogger) {
+	volfilesDir := path.Join(config.GetString("localstatedir"), "volfiles")
+	if err := os.RemoveAll(volfilesDir); err != nil {
+		logger.WithError(err).WithField("dir", volfilesDir).Warn("failed to remove generated volfiles")
+	}
+
+	removed, err := daemon.RemoveStalePidFiles(config.GetString("rundir"))
+	if err != nil {
+		logger.WithError(err).Warn("failed to remove stale pidfiles")
+	}
+	for _, f := range removed {
+		logger.WithField("pidfile", f).Debug("removed stale pidfile")
+	}
+}
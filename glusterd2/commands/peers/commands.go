@@ -46,6 +46,17 @@ func (c *Command) Routes() route.Routes {
 			ResponseType: utils.GetTypeString((*api.PeerAddResp)(nil)),
 			HandlerFunc:  addPeerHandler,
 		},
+		route.Route{
+			Name:         "PeerPreflight",
+			Method:       "POST",
+			Pattern:      "/peers/preflight",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.PeerPreflightReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.PeerPreflightReport)(nil)),
+			HandlerFunc:  peerPreflightHandler,
+			// Only checks the peer, nothing is changed
+			AllowReadOnly: true,
+		},
 		route.Route{
 			Name:         "EditPeer",
 			Method:       "POST",
@@ -55,10 +66,167 @@ func (c *Command) Routes() route.Routes {
 			ResponseType: utils.GetTypeString((*api.PeerEditResp)(nil)),
 			HandlerFunc:  editPeer,
 		},
+		route.Route{
+			Name:         "PeerMetadata",
+			Method:       "PUT",
+			Pattern:      "/peers/{peerid}/metadata",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.PeerMetadataReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.PeerEditResp)(nil)),
+			HandlerFunc:  peerMetadataHandler,
+		},
+		route.Route{
+			Name:        "DecommissionPeer",
+			Method:      "POST",
+			Pattern:     "/peers/{peerid}/decommission",
+			Version:     1,
+			HandlerFunc: decommissionPeerHandler,
+		},
+		route.Route{
+			Name:         "PromotePeer",
+			Method:       "POST",
+			Pattern:      "/peers/{peerid}/promote",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerGetResp)(nil)),
+			HandlerFunc:  promotePeerHandler,
+		},
+		route.Route{
+			Name:         "DemotePeer",
+			Method:       "POST",
+			Pattern:      "/peers/{peerid}/demote",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerGetResp)(nil)),
+			HandlerFunc:  demotePeerHandler,
+		},
+		route.Route{
+			Name:         "ResetPeerEtcdRole",
+			Method:       "DELETE",
+			Pattern:      "/peers/{peerid}/etcd-role",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerGetResp)(nil)),
+			HandlerFunc:  resetPeerEtcdRoleHandler,
+		},
+		route.Route{
+			Name:         "GetEtcdSize",
+			Method:       "GET",
+			Pattern:      "/etcd/size",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.EtcdSizeResp)(nil)),
+			HandlerFunc:  getEtcdSizeHandler,
+		},
+		route.Route{
+			Name:         "SetEtcdSize",
+			Method:       "POST",
+			Pattern:      "/etcd/size",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.EtcdSizeReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.EtcdSizeResp)(nil)),
+			HandlerFunc:  setEtcdSizeHandler,
+		},
+		route.Route{
+			Name:         "GetPeerOptions",
+			Method:       "GET",
+			Pattern:      "/peers/{peerid}/options",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerOptionsResp)(nil)),
+			HandlerFunc:  getPeerOptionsHandler,
+		},
+		route.Route{
+			Name:         "SetPeerOptions",
+			Method:       "POST",
+			Pattern:      "/peers/{peerid}/options",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.PeerOptionsReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.PeerOptionsResp)(nil)),
+			HandlerFunc:  setPeerOptionsHandler,
+		},
+		route.Route{
+			Name:         "ResetPeerOption",
+			Method:       "DELETE",
+			Pattern:      "/peers/{peerid}/options/{optname}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerOptionsResp)(nil)),
+			HandlerFunc:  resetPeerOptionHandler,
+		},
+		route.Route{
+			Name:         "GetPeerLabels",
+			Method:       "GET",
+			Pattern:      "/peers/{peerid}/labels",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerLabelsResp)(nil)),
+			HandlerFunc:  getPeerLabelsHandler,
+		},
+		route.Route{
+			Name:         "SetPeerLabels",
+			Method:       "POST",
+			Pattern:      "/peers/{peerid}/labels",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.PeerLabelsReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.PeerLabelsResp)(nil)),
+			HandlerFunc:  setPeerLabelsHandler,
+		},
+		route.Route{
+			Name:         "DeletePeerLabel",
+			Method:       "DELETE",
+			Pattern:      "/peers/{peerid}/labels/{label}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerLabelsResp)(nil)),
+			HandlerFunc:  deletePeerLabelHandler,
+		},
+		route.Route{
+			Name:         "GetPeerBricks",
+			Method:       "GET",
+			Pattern:      "/peers/{peerid}/bricks",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerBricksResp)(nil)),
+			HandlerFunc:  getPeerBricksHandler,
+		},
+		route.Route{
+			Name:         "EvacuatePeer",
+			Method:       "POST",
+			Pattern:      "/peers/{peerid}/evacuate",
+			Version:      1,
+			RequestBody:  (*api.PeerEvacuateReq)(nil),
+			ResponseType: utils.GetTypeString((*api.PeerEvacuationResp)(nil)),
+			HandlerFunc:  evacuatePeerHandler,
+		},
+		route.Route{
+			Name:         "GetPeerEvacuation",
+			Method:       "GET",
+			Pattern:      "/peers/{peerid}/evacuate",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerEvacuationResp)(nil)),
+			HandlerFunc:  getPeerEvacuationHandler,
+		},
+		route.Route{
+			Name:         "PausePeerEvacuation",
+			Method:       "POST",
+			Pattern:      "/peers/{peerid}/evacuate/pause",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerEvacuationResp)(nil)),
+			HandlerFunc:  pausePeerEvacuationHandler,
+		},
+		route.Route{
+			Name:         "ResumePeerEvacuation",
+			Method:       "POST",
+			Pattern:      "/peers/{peerid}/evacuate/resume",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerEvacuationResp)(nil)),
+			HandlerFunc:  resumePeerEvacuationHandler,
+		},
+		route.Route{
+			Name:        "CancelPeerEvacuation",
+			Method:      "DELETE",
+			Pattern:     "/peers/{peerid}/evacuate",
+			Version:     1,
+			HandlerFunc: cancelPeerEvacuationHandler,
+		},
 	}
 }
 
 // RegisterStepFuncs implements a required function for the Command interface
 func (c *Command) RegisterStepFuncs() {
 	registerPeerEditStepFuncs()
+	registerPeerPreflightStepFuncs()
+	registerEvacuationJob()
 }
@@ -0,0 +1,79 @@
+package peercommands
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	"golang.org/x/sys/unix"
+)
+
+// decommissionPeerHandler retires this peer permanently. The peer is marked
+// offline in the store and leaves the etcd cluster membership, after which
+// GD2 is stopped. The local state of the cluster is cleaned up, so that GD2
+// starts as a fresh single node cluster when restarted.
+func decommissionPeerHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	id := mux.Vars(r)["peerid"]
+	logger = logger.WithField("peerid", id)
+
+	// Only the peer receiving the request can be decommissioned, as the
+	// peer has to leave the etcd cluster on its own
+	if id != gdctx.MyUUID.String() {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "only the peer receiving the request can be decommissioned")
+		return
+	}
+
+	p, err := peer.GetPeerF(id)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// Check if any volumes exist with bricks on this peer
+	if exists, err := bricksExist(id); err != nil {
+		logger.WithError(err).Error("failed to check if bricks exist on peer")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "could not validate decommission request")
+		return
+	} else if exists {
+		logger.Debug("request denied, peer has bricks")
+		restutils.SendHTTPError(ctx, w, http.StatusForbidden, "cannot decommission peer, peer has bricks")
+		return
+	}
+
+	daemon.StopAllDaemons(logger)
+
+	if err := peer.Retire(); err != nil {
+		logger.WithError(err).Error("failed to decommission peer")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	cleanupLocalState(logger)
+	if err := gdctx.UpdateClusterID(uuid.New()); err != nil {
+		logger.WithError(err).Warn("failed to reset cluster ID")
+	}
+	// The store can only be purged after it has been closed during shutdown
+	store.PurgeOnClose()
+	logger.Info("peer decommissioned, stopping glusterd")
+
+	events.Broadcast(newPeerEvent(eventPeerDecommissioned, p))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+
+	// Stop GD2 the same way as on receiving SIGTERM
+	if err := unix.Kill(os.Getpid(), unix.SIGTERM); err != nil {
+		logger.WithError(err).Error("failed to stop glusterd after decommission")
+	}
+}
@@ -2,14 +2,21 @@ package peercommands
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"strings"
 
+	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/utils"
 
 	"github.com/gorilla/mux"
@@ -19,7 +26,7 @@ import (
 func deletePeerHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	id := mux.Vars(r)["peerid"]
 	if uuid.Parse(id) == nil {
@@ -52,35 +59,86 @@ func deletePeerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	//Check if peer liveness key is present in store
-	if _, alive := store.Store.IsNodeAlive(id); !alive {
-		logger.Error("can not delete peer, peer is not alive")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "peer is not alive")
+	report, err := runDetachChecks(p)
+	if err != nil {
+		logger.WithError(err).Error("failed to check if peer can be removed")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "could not validate delete request")
 		return
 	}
+	force := r.URL.Query().Get("force") == "true"
+	if !report.Passed {
+		if !force {
+			restutils.SendHTTPError(ctx, w, http.StatusPreconditionFailed, &preflightError{report})
+			return
+		}
+		logger.Warn("detach checks failed, removing peer as forced")
+	}
 
-	// Check if any volumes exist with bricks on this peer
-	if exists, err := bricksExist(id); err != nil {
-		logger.WithError(err).Error("failed to check if bricks exist on peer")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "could not validate delete request")
-		return
-	} else if exists {
-		logger.Debug("request denied, peer has bricks")
-		restutils.SendHTTPError(ctx, w, http.StatusForbidden, "cannot delete peer, peer has bricks")
+	// A peer which can't be reached is only removed from the cluster, and
+	// has to be cleaned up by the admin
+	leave := detachCheckPassed(report, "peer-online") && detachCheckPassed(report, "port-peer-rpc")
+	if err := removePeer(ctx, p, leave); err != nil {
+		if err == ErrAnotherReqInProgress {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+		} else {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		}
 		return
 	}
 
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// removePeer sends the Leave request to the peer if leave is true, and
+// removes it from the store and the etcd cluster membership
+func removePeer(ctx context.Context, p *peer.Peer, leave bool) error {
+	logger := gdctx.Logger(ctx).WithField("peerid", p.ID.String())
+	id := p.ID.String()
+
+	if leave {
+		if err := leaveCluster(p); err != nil {
+			logger.WithError(err).Error("leave request failed")
+			return err
+		}
+		logger.Debug("peer left cluster")
+	} else {
+		logger.Warn("removing peer without sending leave request")
+	}
+
+	// Remove the peer details from the store
+	if err := peer.DeletePeer(id); err != nil {
+		logger.WithError(err).WithField("peer", id).Error("failed to remove peer from the store")
+		return err
+	}
+
+	if err := options.UpdatePeerOptions(id, nil); err != nil {
+		logger.WithError(err).Warn("failed to remove options overridden for peer")
+	}
+
+	// The removed peer stops its store when leaving, and the elastic leader
+	// eventually removes its etcd membership. Remove it right away instead,
+	// so that the etcd quorum isn't affected by the removed peer meanwhile.
+	if err := store.Store.RemoveEtcdMember(id); err != nil {
+		logger.WithError(err).Warn("failed to remove peer from etcd cluster membership")
+	}
+
+	// Save updated store endpoints for restarts
+	store.Store.UpdateEndpoints()
+
+	events.Broadcast(newPeerEvent(eventPeerRemoved, p))
+	return nil
+}
+
+// leaveCluster sends the Leave request to the peer
+func leaveCluster(p *peer.Peer) error {
 	remotePeerAddress, err := utils.FormRemotePeerAddress(p.PeerAddresses[0])
 	if err != nil {
-		logger.WithError(err).WithField("address", p.PeerAddresses[0]).Error("failed to parse peer address")
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "failed to parse remote address")
-		return
+		return errors.New("failed to parse remote address")
 	}
 
 	client, err := getPeerServiceClient(remotePeerAddress)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return err
 	}
 	defer client.conn.Close()
 
@@ -89,52 +147,81 @@ func deletePeerHandler(w http.ResponseWriter, r *http.Request) {
 	// potentially still send commands to the cluster
 	rsp, err := client.LeaveCluster()
 	if err != nil {
-		logger.WithError(err).Error("client.LeaveCluster() failed")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return err
 	} else if Error(rsp.Err) != ErrNone {
-		err = Error(rsp.Err)
-		logger.WithError(err).Error("leave request failed")
-		if rsp.Err == int32(ErrAnotherReqInProgress) {
-			restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
-		} else {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		}
-		return
+		return Error(rsp.Err)
 	}
-	logger.Debug("peer left cluster")
+	return nil
+}
 
-	// Remove the peer details from the store
-	if err := peer.DeletePeer(id); err != nil {
-		logger.WithError(err).WithField("peer", id).Error("failed to remove peer from the store")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+// runDetachChecks checks whether the peer can be removed from the cluster.
+// The peer has to be online and reachable to be told to leave the cluster, and
+// must not host bricks, as the volumes with bricks on it would be left broken.
+func runDetachChecks(p *peer.Peer) (*api.PeerPreflightReport, error) {
+	id := p.ID.String()
+	report := &api.PeerPreflightReport{Address: p.PeerAddresses[0]}
+
+	online := api.PreflightCheck{Name: "peer-online", Result: api.PreflightPass, Message: "peer is online"}
+	if _, alive := store.Store.IsNodeAlive(id); !alive {
+		online.Result = api.PreflightFail
+		online.Message = "peer is not online"
 	}
+	report.Checks = append(report.Checks, online)
 
-	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+	address, err := utils.FormRemotePeerAddress(p.PeerAddresses[0])
+	if err != nil {
+		return nil, err
+	}
+	host, port, _ := net.SplitHostPort(address)
+	report.Checks = append(report.Checks, checkPort(host, preflightPort{name: "peer-rpc", port: port, required: true}))
 
-	// Save updated store endpoints for restarts
-	store.Store.UpdateEndpoints()
+	bricks, err := volume.GetBricksByPeer(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	report.Checks = append(report.Checks, bricksResult(bricks))
 
-	events.Broadcast(newPeerEvent(eventPeerRemoved, p))
+	for i := range report.Checks {
+		report.Checks[i].PeerID = gdctx.MyUUID.String()
+	}
+	report.Passed = len(report.Failures()) == 0
+	return report, nil
+}
+
+// bricksResult fails the detach of a peer hosting bricks
+func bricksResult(bricks []brick.Brickinfo) api.PreflightCheck {
+	check := api.PreflightCheck{Name: "bricks", Result: api.PreflightPass, Message: "peer hosts no bricks"}
+	if len(bricks) == 0 {
+		return check
+	}
+
+	var volumes []string
+	for _, b := range bricks {
+		if !utils.StringInSlice(b.VolumeName, volumes) {
+			volumes = append(volumes, b.VolumeName)
+		}
+	}
+	check.Result = api.PreflightFail
+	check.Message = fmt.Sprintf("peer hosts %d bricks of the volumes %s, which would be left broken", len(bricks), strings.Join(volumes, ", "))
+	return check
+}
+
+// detachCheckPassed returns true if the named check of the report passed
+func detachCheckPassed(report *api.PeerPreflightReport, name string) bool {
+	for _, c := range report.Checks {
+		if c.Name == name {
+			return c.Result == api.PreflightPass
+		}
+	}
+	return false
 }
 
 // bricksExist checks if the given peer has any bricks on it
 // TODO: Move this to a more appropriate place
 func bricksExist(id string) (bool, error) {
-	pid := uuid.Parse(id)
-
-	vols, err := volume.GetVolumes(context.TODO())
+	bricks, err := volume.GetBricksByPeer(uuid.Parse(id))
 	if err != nil {
 		return true, err
 	}
-
-	for _, v := range vols {
-		for _, b := range v.GetBricks() {
-			if uuid.Equal(pid, b.PeerID) {
-				return true, nil
-			}
-		}
-	}
-	return false, nil
+	return len(bricks) != 0, nil
 }
@@ -19,7 +19,6 @@ import (
 func editPeer(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
 
 	var req api.PeerEditReq
 	if err := restutils.UnmarshalRequest(r, &req); err != nil {
@@ -27,6 +26,32 @@ func editPeer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	updatePeer(w, r, req, false)
+}
+
+// peerMetadataHandler replaces the metadata of the peer, and its zone if one
+// is given. Reserved metadata is kept.
+func peerMetadataHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	var req api.PeerMetadataReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	updatePeer(w, r, api.PeerEditReq{Zone: req.Zone, Metadata: req.Metadata}, true)
+}
+
+// updatePeer updates the metadata of the peer with that of the request. The
+// metadata which isn't reserved is replaced if replace is true, or else
+// merged.
+func updatePeer(w http.ResponseWriter, r *http.Request, req api.PeerEditReq, replace bool) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
 	peerID := mux.Vars(r)["peerid"]
 	if uuid.Parse(peerID) == nil {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid peerID passed in url")
@@ -68,6 +93,11 @@ func editPeer(w http.ResponseWriter, r *http.Request) {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
+	if err := txn.Ctx.Set("replace", replace); err != nil {
+		logger.WithError(err).WithField("key", "replace").Error("Failed to set key in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
 	err = txn.Do()
 	if err != nil {
 		logger.WithError(err).Error("Transaction to update peer failed")
@@ -107,6 +137,15 @@ func txnPeerEdit(c transaction.TxnCtx) error {
 		return gderrors.ErrMetadataSizeOutOfBounds
 	}
 
+	var replace bool
+	if err := c.Get("replace", &replace); err == nil && replace {
+		for k := range peerInfo.Metadata {
+			if !strings.HasPrefix(k, "_") {
+				delete(peerInfo.Metadata, k)
+			}
+		}
+	}
+
 	for k, v := range req.Metadata {
 		peerInfo.Metadata[k] = v
 	}
@@ -116,7 +155,7 @@ func txnPeerEdit(c transaction.TxnCtx) error {
 	}
 
 	if req.Zone != "" {
-		peerInfo.Metadata["_zone"] = req.Zone
+		peerInfo.Metadata[peer.ZoneKey] = req.Zone
 	}
 	err = peer.AddOrUpdatePeer(peerInfo)
 	if err != nil {
@@ -150,5 +189,6 @@ func createPeerEditResp(p *peer.Peer) *api.PeerEditResp {
 		PeerAddresses:   p.PeerAddresses,
 		ClientAddresses: p.ClientAddresses,
 		Metadata:        p.Metadata,
+		Labels:          p.Labels,
 	}
 }
@@ -14,6 +14,7 @@ const (
 	ErrClusterIDUpdateFailed
 	ErrAnotherReqInProgress
 	ErrFailedToConnectToStore
+	ErrPeerIDCollision
 	ErrMax
 )
 
@@ -28,6 +29,7 @@ func init() {
 	errorStrings[ErrClusterIDUpdateFailed] = "failed to set and store new cluster ID"
 	errorStrings[ErrAnotherReqInProgress] = "already processing another join/leave request"
 	errorStrings[ErrFailedToConnectToStore] = "failed to connect to store"
+	errorStrings[ErrPeerIDCollision] = "peer has the same ID as a peer of the cluster"
 }
 
 func (e Error) String() string {
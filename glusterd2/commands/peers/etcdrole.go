@@ -0,0 +1,154 @@
+package peercommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/elasticetcd"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// getEtcdRoles returns the etcd roles of the peers mapped by the peer ID. An
+// empty map is returned if the roles cannot be found, as with a remote store.
+func getEtcdRoles() map[string]elasticetcd.MemberRole {
+	roles := make(map[string]elasticetcd.MemberRole)
+
+	list, err := store.Store.EtcdRoles()
+	if err != nil {
+		if err != store.ErrEtcdRolesUnsupported {
+			log.WithError(err).Warn("failed to get etcd roles of peers")
+		}
+		return roles
+	}
+
+	for _, r := range list {
+		roles[r.Name] = r
+	}
+	return roles
+}
+
+func sendEtcdRoleError(w http.ResponseWriter, r *http.Request, err error) {
+	ctx := r.Context()
+	switch err {
+	case store.ErrEtcdRolesUnsupported, elasticetcd.ErrInvalidRole, elasticetcd.ErrInvalidIdealSize:
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+	case elasticetcd.ErrLastVoter:
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+	default:
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+	}
+}
+
+// pinEtcdRole pins the role of the peer in the etcd cluster of the store. The
+// elastic leader of the etcd cluster applies the role asynchronously, the
+// response has the role of the peer at the time of pinning.
+func pinEtcdRole(w http.ResponseWriter, r *http.Request, role elasticetcd.Role) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	id := mux.Vars(r)["peerid"]
+	p, err := peer.GetPeerF(id)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if role == "" {
+		err = store.Store.UnpinEtcdRole(id)
+	} else {
+		err = store.Store.PinEtcdRole(id, role)
+	}
+	if err != nil {
+		logger.WithError(err).WithField("peerid", id).Error("failed to pin etcd role of peer")
+		sendEtcdRoleError(w, r, err)
+		return
+	}
+
+	logger.WithField("peerid", id).WithField("role", role).Info("pinned etcd role of peer")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createPeerGetResp(p))
+}
+
+func promotePeerHandler(w http.ResponseWriter, r *http.Request) {
+	pinEtcdRole(w, r, elasticetcd.RoleVoter)
+}
+
+func demotePeerHandler(w http.ResponseWriter, r *http.Request) {
+	pinEtcdRole(w, r, elasticetcd.RoleProxy)
+}
+
+func resetPeerEtcdRoleHandler(w http.ResponseWriter, r *http.Request) {
+	pinEtcdRole(w, r, "")
+}
+
+func getEtcdSize() (*api.EtcdSizeResp, error) {
+	size, err := store.Store.EtcdIdealSize()
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := store.Store.EtcdRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.EtcdSizeResp{IdealSize: size}
+	for _, r := range roles {
+		if r.Role == elasticetcd.RoleVoter {
+			resp.Voters++
+		}
+	}
+	return resp, nil
+}
+
+func getEtcdSizeHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	resp, err := getEtcdSize()
+	if err != nil {
+		sendEtcdRoleError(w, r, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// setEtcdSizeHandler sets the number of peers running etcd voting members.
+// Like pinned roles, the elastic leader applies the size asynchronously by
+// promoting or demoting unpinned peers. Peers are promoted automatically when
+// a voting member leaves, to keep the size.
+func setEtcdSizeHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	var req api.EtcdSizeReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := store.Store.SetEtcdIdealSize(req.IdealSize); err != nil {
+		logger.WithError(err).WithField("idealsize", req.IdealSize).Error("failed to set etcd cluster size")
+		sendEtcdRoleError(w, r, err)
+		return
+	}
+	logger.WithField("idealsize", req.IdealSize).Info("set etcd cluster size")
+
+	resp, err := getEtcdSize()
+	if err != nil {
+		sendEtcdRoleError(w, r, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,276 @@
+package peercommands
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	volumecommands "github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/ioops"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/plugins/glustershd"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	evacuationJobName     = "peer.evacuate"
+	evacuationJobSchedule = "@every 1m"
+)
+
+func registerEvacuationJob() {
+	err := scheduler.Register(&scheduler.Job{
+		Name:        evacuationJobName,
+		Description: "Replaces the bricks of the peers being evacuated and detaches them once the new bricks are healed",
+		Schedule:    evacuationJobSchedule,
+		Enabled:     true,
+		Func:        evacuatePeers,
+	})
+	if err != nil {
+		log.WithError(err).WithField("job", evacuationJobName).Error("failed to register scheduled job")
+	}
+}
+
+func evacuatePeers(ctx context.Context) error {
+	evacuations, err := peer.GetEvacuations()
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, ev := range evacuations {
+		if ev.State != api.EvacuationRunning {
+			continue
+		}
+		if err := evacuatePeer(ctx, ev); err != nil {
+			log.WithError(err).WithField("peerid", ev.PeerID).Warn("peer evacuation failed")
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		return errors.New("evacuation failed for " + strconv.Itoa(failed) + " peer(s)")
+	}
+	return nil
+}
+
+// evacuatePeer advances the evacuation of the peer by one step. The bricks
+// whose replacements have been healed are marked healed, and the next brick
+// of each volume is replaced. The peer is detached once all its bricks have
+// been healed.
+func evacuatePeer(ctx context.Context, ev *api.PeerEvacuationResp) error {
+	ctx = gdctx.WithReqID(ctx, uuid.NewRandom())
+	logger := gdctx.Logger(ctx).WithField("peerid", ev.PeerID)
+	ctx = gdctx.WithReqLogger(ctx, logger)
+
+	rebalancing, err := rebalancingVolumes()
+	if err != nil {
+		return err
+	}
+
+	bricks := ev.Bricks
+	for i := range bricks {
+		if bricks[i].State != api.EvacBrickReplaced {
+			continue
+		}
+		healed, err := brickHealed(bricks[i].Volume, rebalancing)
+		if err != nil {
+			logger.WithError(err).WithField("volume", bricks[i].Volume).Warn("failed to check heal of replaced brick")
+			continue
+		}
+		if healed {
+			bricks[i].State = api.EvacBrickHealed
+		}
+	}
+
+	for _, i := range nextEvacuationBricks(bricks) {
+		replaceEvacuatedBrick(ctx, ev, &bricks[i])
+	}
+
+	ev, err = updateEvacuation(ctx, ev.PeerID, func(cur *api.PeerEvacuationResp) error {
+		cur.Bricks = bricks
+		if cur.State != api.EvacuationRunning {
+			// paused meanwhile
+			return nil
+		}
+		cur.State = evacuationState(bricks)
+		// The evacuation completes after the peer is detached
+		if cur.State == api.EvacuationCompleted && cur.Detach && !cur.Detached {
+			cur.State = api.EvacuationRunning
+		}
+		return nil
+	})
+	if err == gderrors.ErrPeerEvacuationNotFound {
+		// cancelled meanwhile
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if ev.State == api.EvacuationRunning && ev.Detach && evacuationState(ev.Bricks) == api.EvacuationCompleted {
+		derr := detachEvacuatedPeer(ctx, ev.PeerID)
+		ev, err = updateEvacuation(ctx, ev.PeerID, func(cur *api.PeerEvacuationResp) error {
+			if derr != nil {
+				cur.State = api.EvacuationFailed
+				cur.Error = "failed to detach peer: " + derr.Error()
+				return nil
+			}
+			cur.Detached = true
+			cur.State = api.EvacuationCompleted
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	switch ev.State {
+	case api.EvacuationCompleted:
+		logger.Info("peer evacuation completed")
+		events.Broadcast(newEvacuationEvent(eventPeerEvacuated, ev))
+	case api.EvacuationFailed:
+		events.Broadcast(newEvacuationEvent(eventPeerEvacuationFailed, ev))
+	}
+	return nil
+}
+
+// rebalancingVolumes returns the volumes on which a rebalance is running or
+// queued
+func rebalancingVolumes() (map[string]bool, error) {
+	ops, err := ioops.List()
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[string]bool)
+	for _, op := range ops {
+		if op.Op == string(ioops.OpRebalance) {
+			volumes[op.Volume] = true
+		}
+	}
+	return volumes, nil
+}
+
+// brickHealed returns true if no entries are pending heal on the volume and
+// no rebalance is running on it
+func brickHealed(volname string, rebalancing map[string]bool) (bool, error) {
+	if rebalancing[volname] {
+		return false, nil
+	}
+
+	vol, err := volume.GetVolume(volname)
+	if err == gderrors.ErrVolNotFound {
+		// nothing left to heal
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	backlog, ok, err := glustershd.HealBacklog(vol)
+	if err != nil || !ok {
+		// Heal can't be checked while the volume is stopped
+		return false, err
+	}
+	return backlog == 0, nil
+}
+
+// replaceEvacuatedBrick replaces the brick with a new brick chosen by the
+// bricks planner, and records the result in the brick. Bricks which can't be
+// replaced due to a conflicting transaction are retried in the next run.
+func replaceEvacuatedBrick(ctx context.Context, ev *api.PeerEvacuationResp, b *api.EvacuatedBrick) {
+	logger := gdctx.Logger(ctx).WithField("volume", b.Volume).WithField("brick", b.Path)
+
+	vol, err := volume.GetVolume(b.Volume)
+	if err == gderrors.ErrVolNotFound {
+		// volume was deleted meanwhile
+		b.State = api.EvacBrickHealed
+		return
+	} else if err != nil {
+		logger.WithError(err).Warn("failed to get volume of the brick to be evacuated")
+		return
+	}
+
+	subvol, index := brickPosition(vol, ev.PeerID, b.Path)
+	if subvol < 0 {
+		// brick was replaced meanwhile, its replacement may yet have
+		// to be healed
+		b.State = api.EvacBrickReplaced
+		return
+	}
+
+	req := api.ReplaceBrickReq{
+		SrcPeerID:    ev.PeerID,
+		SrcBrickPath: b.Path,
+		LimitPeers:   ev.LimitPeers,
+		LimitZones:   ev.LimitZones,
+		ExcludePeers: append([]string{ev.PeerID}, ev.ExcludePeers...),
+		ExcludeZones: ev.ExcludeZones,
+		PeerSelector: ev.PeerSelector,
+	}
+	if _, err := volumecommands.ReplaceBrick(ctx, b.Volume, req); err != nil {
+		if err == transaction.ErrLockTimeout {
+			return
+		}
+		logger.WithError(err).Warn("failed to replace brick of evacuated peer")
+		b.State = api.EvacBrickFailed
+		b.Error = err.Error()
+		return
+	}
+
+	b.State = api.EvacBrickReplaced
+	if vol, err = volume.GetVolume(b.Volume); err == nil {
+		newBrick := vol.Subvols[subvol].Bricks[index]
+		b.NewPeerID = newBrick.PeerID.String()
+		b.NewPath = newBrick.Path
+	}
+	logger.WithField("new-peer", b.NewPeerID).WithField("new-brick", b.NewPath).Info("replaced brick of evacuated peer")
+}
+
+// brickPosition returns the indices of the subvolume and of the brick within
+// it, or -1 if the brick is not part of the volume
+func brickPosition(vol *volume.Volinfo, peerID, path string) (int, int) {
+	for s, sv := range vol.Subvols {
+		for i, b := range sv.Bricks {
+			if b.PeerID.String() == peerID && b.Path == path {
+				return s, i
+			}
+		}
+	}
+	return -1, -1
+}
+
+// detachEvacuatedPeer removes the evacuated peer from the cluster, like a
+// delete peer request
+func detachEvacuatedPeer(ctx context.Context, id string) error {
+	p, err := peer.GetPeerF(id)
+	if err == gderrors.ErrPeerNotFound {
+		// peer was deleted meanwhile
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// The job runs on the leader, which can't remove itself
+	if id == gdctx.MyUUID.String() {
+		return errors.New("peer is the leader of the cluster, resume the evacuation after another peer becomes the leader")
+	}
+	if _, alive := store.Store.IsNodeAlive(id); !alive {
+		return errors.New("peer is not alive")
+	}
+	if exists, err := bricksExist(id); err != nil {
+		return err
+	} else if exists {
+		return errors.New("peer has bricks")
+	}
+
+	return removePeer(ctx, p, true)
+}
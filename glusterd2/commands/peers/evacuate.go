@@ -0,0 +1,301 @@
+package peercommands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+// evacuationLockID returns the lock serializing the updates to the state of
+// the evacuation of the peer
+func evacuationLockID(id string) string {
+	return "peer-evacuation/" + id
+}
+
+// isRedundant returns true if the bricks of the volume type have replicas or
+// fragments on other bricks from which a replaced brick can be healed
+func isRedundant(vType volume.VolType) bool {
+	switch vType {
+	case volume.Replicate, volume.Disperse, volume.DistReplicate, volume.DistDisperse:
+		return true
+	}
+	return false
+}
+
+// evacuationBricks returns the bricks to be evacuated from the peer. All the
+// volumes with bricks on the peer have to be started and redundant, as the
+// new bricks are populated by self-heal.
+func evacuationBricks(bricks []brick.Brickinfo) ([]api.EvacuatedBrick, error) {
+	volumes := make(map[string]*volume.Volinfo)
+	evBricks := make([]api.EvacuatedBrick, 0, len(bricks))
+	for _, b := range bricks {
+		vol, ok := volumes[b.VolumeName]
+		if !ok {
+			var err error
+			if vol, err = volume.GetVolume(b.VolumeName); err != nil {
+				return nil, err
+			}
+			volumes[b.VolumeName] = vol
+		}
+
+		if !isRedundant(vol.Type) {
+			return nil, fmt.Errorf("volume %s is not replicated or dispersed, its bricks can't be evacuated", vol.Name)
+		}
+		if vol.State != volume.VolStarted {
+			return nil, fmt.Errorf("volume %s is not started, its bricks can't be healed", vol.Name)
+		}
+
+		evBricks = append(evBricks, api.EvacuatedBrick{
+			Volume: b.VolumeName,
+			Path:   b.Path,
+			State:  api.EvacBrickPending,
+		})
+	}
+	return evBricks, nil
+}
+
+// nextEvacuationBricks returns the indices of the pending bricks to be
+// replaced next. Only one brick of a volume is replaced at a time, after the
+// previously replaced brick of the volume has been healed.
+func nextEvacuationBricks(bricks []api.EvacuatedBrick) []int {
+	busy := make(map[string]bool)
+	for _, b := range bricks {
+		if b.State == api.EvacBrickReplaced {
+			busy[b.Volume] = true
+		}
+	}
+
+	var next []int
+	for i, b := range bricks {
+		if b.State == api.EvacBrickPending && !busy[b.Volume] {
+			next = append(next, i)
+			busy[b.Volume] = true
+		}
+	}
+	return next
+}
+
+// evacuationState returns the state of the evacuation of the bricks. The
+// evacuation runs until no brick is pending or healing, and fails if any
+// brick could not be replaced.
+func evacuationState(bricks []api.EvacuatedBrick) string {
+	state := api.EvacuationCompleted
+	for _, b := range bricks {
+		switch b.State {
+		case api.EvacBrickPending, api.EvacBrickReplaced:
+			return api.EvacuationRunning
+		case api.EvacBrickFailed:
+			state = api.EvacuationFailed
+		}
+	}
+	return state
+}
+
+// updateEvacuation applies f to the state of the evacuation of the peer and
+// saves it
+func updateEvacuation(ctx context.Context, id string, f func(*api.PeerEvacuationResp) error) (*api.PeerEvacuationResp, error) {
+	txn, err := transaction.NewTxnWithLocks(ctx, evacuationLockID(id))
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Done()
+
+	ev, err := peer.GetEvacuation(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := f(ev); err != nil {
+		return nil, err
+	}
+	ev.UpdatedAt = time.Now()
+
+	if err := peer.SetEvacuation(ev); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// evacuatePeerHandler starts the evacuation of all the bricks of the peer.
+// The bricks are replaced one volume at a time by the peer evacuation job,
+// which waits for the new bricks to be healed before replacing the next
+// brick of the volume, and detaches the peer at the end if requested.
+func evacuatePeerHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+	req := restutils.RequestBody(ctx).(*api.PeerEvacuateReq)
+
+	id := mux.Vars(r)["peerid"]
+	if uuid.Parse(id) == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid peer id passed")
+		return
+	}
+	logger = logger.WithField("peerid", id)
+
+	if _, err := peer.GetPeerF(id); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if req.Detach && id == gdctx.MyUUID.String() {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "removing self is disallowed.")
+		return
+	}
+
+	bricks, err := volume.GetBricksByPeer(uuid.Parse(id))
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	evBricks, err := evacuationBricks(bricks)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, evacuationLockID(id))
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	if ev, err := peer.GetEvacuation(id); err == nil {
+		if ev.State == api.EvacuationRunning || ev.State == api.EvacuationPaused {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, gderrors.ErrPeerEvacuationExists)
+			return
+		}
+	} else if err != gderrors.ErrPeerEvacuationNotFound {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	now := time.Now()
+	ev := &api.PeerEvacuationResp{
+		PeerEvacuateReq: *req,
+		PeerID:          id,
+		State:           api.EvacuationRunning,
+		Bricks:          evBricks,
+		StartedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := peer.SetEvacuation(ev); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("bricks", len(evBricks)).Info("peer evacuation started")
+	restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, ev)
+}
+
+func getPeerEvacuationHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	id := mux.Vars(r)["peerid"]
+
+	ev, err := peer.GetEvacuation(id)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, ev)
+}
+
+// pausePeerEvacuationHandler stops replacing the bricks of the peer. The
+// bricks already replaced continue to be healed.
+func pausePeerEvacuationHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	id := mux.Vars(r)["peerid"]
+
+	ev, err := updateEvacuation(ctx, id, func(ev *api.PeerEvacuationResp) error {
+		if ev.State != api.EvacuationRunning {
+			return gderrors.ErrPeerEvacuationNotRunning
+		}
+		ev.State = api.EvacuationPaused
+		return nil
+	})
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, ev)
+}
+
+// resumePeerEvacuationHandler resumes a paused or failed evacuation. The
+// bricks which could not be replaced are retried.
+func resumePeerEvacuationHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	id := mux.Vars(r)["peerid"]
+
+	ev, err := updateEvacuation(ctx, id, func(ev *api.PeerEvacuationResp) error {
+		if ev.State != api.EvacuationPaused && ev.State != api.EvacuationFailed {
+			return gderrors.ErrPeerEvacuationNotResumable
+		}
+		for i := range ev.Bricks {
+			if ev.Bricks[i].State == api.EvacBrickFailed {
+				ev.Bricks[i].State = api.EvacBrickPending
+				ev.Bricks[i].Error = ""
+			}
+		}
+		ev.State = api.EvacuationRunning
+		ev.Error = ""
+		return nil
+	})
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, ev)
+}
+
+// cancelPeerEvacuationHandler stops the evacuation and deletes its state.
+// The bricks already replaced are not restored.
+func cancelPeerEvacuationHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	id := mux.Vars(r)["peerid"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, evacuationLockID(id))
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	if _, err := peer.GetEvacuation(id); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := peer.DeleteEvacuation(id); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,44 @@
+package peercommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextEvacuationBricks(t *testing.T) {
+	bricks := []api.EvacuatedBrick{
+		{Volume: "v1", Path: "/b1", State: api.EvacBrickPending},
+		{Volume: "v1", Path: "/b2", State: api.EvacBrickPending},
+		{Volume: "v2", Path: "/b3", State: api.EvacBrickReplaced},
+		{Volume: "v2", Path: "/b4", State: api.EvacBrickPending},
+		{Volume: "v3", Path: "/b5", State: api.EvacBrickFailed},
+		{Volume: "v3", Path: "/b6", State: api.EvacBrickPending},
+	}
+
+	// One brick per volume, none of a volume still healing
+	assert.Equal(t, []int{0, 5}, nextEvacuationBricks(bricks))
+
+	bricks[0].State = api.EvacBrickReplaced
+	bricks[2].State = api.EvacBrickHealed
+	assert.Equal(t, []int{3, 5}, nextEvacuationBricks(bricks))
+}
+
+func TestEvacuationState(t *testing.T) {
+	bricks := []api.EvacuatedBrick{
+		{Volume: "v1", State: api.EvacBrickHealed},
+		{Volume: "v1", State: api.EvacBrickReplaced},
+		{Volume: "v2", State: api.EvacBrickFailed},
+	}
+	assert.Equal(t, api.EvacuationRunning, evacuationState(bricks))
+
+	bricks[1].State = api.EvacBrickHealed
+	assert.Equal(t, api.EvacuationFailed, evacuationState(bricks))
+
+	bricks[2].State = api.EvacBrickHealed
+	assert.Equal(t, api.EvacuationCompleted, evacuationState(bricks))
+
+	assert.Equal(t, api.EvacuationCompleted, evacuationState(nil))
+}
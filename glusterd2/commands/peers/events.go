@@ -1,6 +1,8 @@
 package peercommands
 
 import (
+	"strconv"
+
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	"github.com/gluster/glusterd2/pkg/api"
@@ -9,8 +11,11 @@ import (
 type peerEvent string
 
 const (
-	eventPeerAdded   peerEvent = "peer.added"
-	eventPeerRemoved           = "peer.removed"
+	eventPeerAdded            peerEvent = "peer.added"
+	eventPeerRemoved                    = "peer.removed"
+	eventPeerDecommissioned             = "peer.decommissioned"
+	eventPeerEvacuated                  = "peer.evacuated"
+	eventPeerEvacuationFailed           = "peer.evacuation-failed"
 )
 
 func newPeerEvent(e peerEvent, p *peer.Peer) *api.Event {
@@ -21,3 +26,16 @@ func newPeerEvent(e peerEvent, p *peer.Peer) *api.Event {
 
 	return events.New(string(e), data, true)
 }
+
+func newEvacuationEvent(e peerEvent, ev *api.PeerEvacuationResp) *api.Event {
+	data := map[string]string{
+		"peer.id":       ev.PeerID,
+		"peer.bricks":   strconv.Itoa(len(ev.Bricks)),
+		"peer.detached": strconv.FormatBool(ev.Detached),
+	}
+	if ev.Error != "" {
+		data["error"] = ev.Error
+	}
+
+	return events.New(string(e), data, true)
+}
@@ -33,6 +33,7 @@ func getPeerHandler(w http.ResponseWriter, r *http.Request) {
 
 func createPeerGetResp(p *peer.Peer) *api.PeerGetResp {
 	pid, online := store.Store.IsNodeAlive(p.ID)
+	role := getEtcdRoles()[p.ID.String()]
 	return &api.PeerGetResp{
 		ID:              p.ID,
 		Name:            p.Name,
@@ -41,5 +42,9 @@ func createPeerGetResp(p *peer.Peer) *api.PeerGetResp {
 		Online:          online,
 		PID:             pid,
 		Metadata:        p.Metadata,
+		Labels:          p.Labels,
+		EtcdRole:        string(role.Role),
+		EtcdRolePinned:  string(role.Pinned),
+		ManagementOnly:  p.IsManagementOnly(),
 	}
 }
@@ -0,0 +1,43 @@
+package peercommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+func getPeerBricksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := uuid.Parse(mux.Vars(r)["peerid"])
+	if id == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid peer id passed")
+		return
+	}
+
+	var (
+		bricks []brick.Brickinfo
+		err    error
+	)
+	if prefix := r.URL.Query().Get("path-prefix"); prefix != "" {
+		bricks, err = volume.GetBricksByPathPrefix(id, prefix)
+	} else {
+		bricks, err = volume.GetBricksByPeer(id)
+	}
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.PeerBricksResp, 0, len(bricks))
+	for i := range bricks {
+		resp = append(resp, brick.CreateBrickInfo(&bricks[i]))
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -21,11 +21,26 @@ func getPeersHandler(w http.ResponseWriter, r *http.Request) {
 	if valueFound {
 		filterParams["value"] = values[0]
 	}
+	sel, err := peer.ParseSelector(r.URL.Query().Get("selector"))
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
 	peers, err := peer.GetPeersF(filterParams)
 	if err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
+	if !sel.Empty() {
+		selected := peers[:0]
+		for _, p := range peers {
+			if sel.Matches(p.Labels) {
+				selected = append(selected, p)
+			}
+		}
+		peers = selected
+	}
 
 	resp := createPeerListResp(peers)
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
@@ -34,8 +49,10 @@ func getPeersHandler(w http.ResponseWriter, r *http.Request) {
 func createPeerListResp(peers []*peer.Peer) *api.PeerListResp {
 	var resp api.PeerListResp
 
+	roles := getEtcdRoles()
 	for _, p := range peers {
 		pid, online := store.Store.IsNodeAlive(p.ID)
+		role := roles[p.ID.String()]
 		resp = append(resp, api.PeerGetResp{
 			ID:              p.ID,
 			Name:            p.Name,
@@ -44,6 +61,10 @@ func createPeerListResp(peers []*peer.Peer) *api.PeerListResp {
 			Online:          online,
 			PID:             pid,
 			Metadata:        p.Metadata,
+			Labels:          p.Labels,
+			EtcdRole:        string(role.Role),
+			EtcdRolePinned:  string(role.Pinned),
+			ManagementOnly:  p.IsManagementOnly(),
 		})
 	}
 
@@ -0,0 +1,103 @@
+package peercommands
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func getPeerLabelsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, err := peer.GetPeerF(mux.Vars(r)["peerid"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.PeerLabelsResp{Labels: p.Labels})
+}
+
+// updatePeerLabels updates the labels of the peer with fn, holding the lock
+// on the peer
+func updatePeerLabels(w http.ResponseWriter, r *http.Request, fn func(map[string]string) (int, error)) {
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	id := mux.Vars(r)["peerid"]
+	txn, err := transaction.NewTxnWithLocks(ctx, id)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	p, err := peer.GetPeerF(id)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if p.Labels == nil {
+		p.Labels = make(map[string]string)
+	}
+	if status, err := fn(p.Labels); err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := peer.AddOrUpdatePeer(p); err != nil {
+		logger.WithError(err).WithField("peerid", id).Error("failed to update peer labels")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.PeerLabelsResp{Labels: p.Labels})
+}
+
+func setPeerLabelsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.PeerLabelsReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	for k, v := range req.Labels {
+		if err := peer.ValidateLabel(k, v); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	updatePeerLabels(w, r, func(labels map[string]string) (int, error) {
+		for k, v := range req.Labels {
+			labels[k] = v
+		}
+		return http.StatusOK, nil
+	})
+}
+
+func deletePeerLabelHandler(w http.ResponseWriter, r *http.Request) {
+	label := mux.Vars(r)["label"]
+
+	updatePeerLabels(w, r, func(labels map[string]string) (int, error) {
+		if _, ok := labels[label]; !ok {
+			return http.StatusNotFound, fmt.Errorf("label %s is not set on the peer", label)
+		}
+		delete(labels, label)
+		return http.StatusOK, nil
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/servers/peerrpc"
 
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
@@ -22,7 +23,12 @@ type peerSvcClnt struct { // this is not really a good name as it can be confuse
 
 // getPeerServiceClient returns a PeerServiceClient for the given address and the underlying grpc.ClientConn
 func getPeerServiceClient(address string) (*peerSvcClnt, error) {
-	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	security, err := peerrpc.WithTransportSecurity()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(address, security, peerrpc.WithIdentity())
 	if err != nil {
 		return nil, err
 	}
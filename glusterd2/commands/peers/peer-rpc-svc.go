@@ -3,14 +3,17 @@ package peercommands
 import (
 	"context"
 
+	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/msgbus"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	"github.com/gluster/glusterd2/glusterd2/servers/peerrpc"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/glusterd2/transactionv2"
 	"github.com/gluster/glusterd2/glusterd2/transactionv2/cleanuphandler"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/utils"
 
 	"github.com/pborman/uuid"
@@ -25,6 +28,8 @@ type PeerService int
 
 func init() {
 	peerrpc.Register(new(PeerService))
+	// Peers are asked to join the cluster of the requester
+	peerrpc.AllowCrossCluster("/peercommands.PeerService/Join")
 }
 
 // RegisterService registers a service
@@ -56,6 +61,13 @@ func (p *PeerService) Join(ctx context.Context, req *JoinReq) (*JoinRsp, error)
 
 	// TODO: Ensure no other operations are happening
 
+	// A peer cloned from the requester along with its local state has
+	// the same ID, and would be taken to be the requester
+	if uuid.Equal(uuid.Parse(req.PeerID), gdctx.MyUUID) {
+		logger.Info("rejecting join, requester has the same peer ID")
+		return &JoinRsp{PeerID: "", Err: int32(ErrPeerIDCollision)}, nil
+	}
+
 	peers, err := peer.GetPeersF()
 	if err != nil {
 		logger.WithError(err).Error("failed to connect to store")
@@ -92,7 +104,10 @@ func (p *PeerService) Join(ctx context.Context, req *JoinReq) (*JoinRsp, error)
 		return &JoinRsp{PeerID: "", Err: int32(ErrClusterIDUpdateFailed)}, nil
 	}
 
-	if err := ReconfigureStore(req.Config); err != nil {
+	if err := ReconfigureStore(req.Config, true); err == ErrPeerIDCollision {
+		logger.Info("rejecting join, a peer of the cluster has the same peer ID")
+		return &JoinRsp{PeerID: "", Err: int32(ErrPeerIDCollision)}, nil
+	} else if err != nil {
 		logger.WithError(err).Error("reconfigure store failed, failed to join new cluster")
 		return &JoinRsp{PeerID: "", Err: int32(ErrStoreReconfigFailed)}, nil
 	}
@@ -121,7 +136,9 @@ func (p *PeerService) Leave(ctx context.Context, req *LeaveReq) (*LeaveRsp, erro
 	// 	are happening
 	// 	- Check if the request came from a known peer
 	// 	- TODO: Check if you can leave the cluster
+	// 	- Stop the daemons started for the cluster
 	// 	- Reconfigure the store with you defaults
+	// 	- Cleanup the remaining local state of the cluster
 
 	// TODO: Ensure no other operations are happening
 
@@ -140,6 +157,10 @@ func (p *PeerService) Leave(ctx context.Context, req *LeaveReq) (*LeaveRsp, erro
 
 	logger.Debug("all checks passed, leaving cluster")
 
+	// The daemons are saved in the store of the cluster being left, so they
+	// need to be stopped before the store is reconfigured
+	daemon.StopAllDaemons(logger)
+
 	// Reset the cluster ID: This will reset the global variable
 	// gdctx.MyClusterID which will be used during store reconfiguration.
 	// If reconfiguring store fails, restore the old cluster ID.
@@ -154,22 +175,27 @@ func (p *PeerService) Leave(ctx context.Context, req *LeaveReq) (*LeaveRsp, erro
 	}
 
 	logger.Debug("reconfiguring store with defaults")
-	if err := ReconfigureStore(&StoreConfig{Endpoints: store.NewConfig().Endpoints}); err != nil {
+	if err := ReconfigureStore(&StoreConfig{Endpoints: store.NewConfig().Endpoints}, false); err != nil {
 		logger.WithError(err).Warn("failed to reconfigure store with defaults")
 		// XXX: We should probably keep retrying here?
 	}
 	success = true
+
+	cleanupLocalState(logger)
+	logger.Info("left cluster")
 	return &LeaveRsp{Err: int32(ErrNone)}, nil
 }
 
 // ReconfigureStore reconfigures the store with the given store config, if no
-// store config is given uses the default
-func ReconfigureStore(c *StoreConfig) error {
+// store config is given uses the default. When joining a cluster,
+// ErrPeerIDCollision is returned if the cluster has a peer with our ID.
+func ReconfigureStore(c *StoreConfig, joining bool) error {
 
 	// Destroy the current store first
 	log.Debug("destroying current store")
 
-	// Stop events framework
+	// Stop events framework and message bus
+	msgbus.Stop()
 	events.Stop()
 	transaction.StopTxnEngine()
 	cleanuphandler.StopCleanupLeader()
@@ -201,6 +227,21 @@ func ReconfigureStore(c *StoreConfig) error {
 	}
 	log.WithField("endpoints", cfg.Endpoints).Debug("store restarted with new endpoints")
 
+	// A peer cloned from a peer of the cluster along with its local state
+	// has the same ID, and would take the place of that peer. This is
+	// checked in the store of the cluster, before adding ourself to it.
+	if joining {
+		_, err := peer.GetPeer(gdctx.MyUUID.String())
+		if err == nil {
+			err = ErrPeerIDCollision
+		}
+		if err != errors.ErrPeerNotFound {
+			// Destroy newly started store and restart with default config
+			defer restartDefaultStore(true, deleteNamespace)
+			return err
+		}
+	}
+
 	// Save the new config if you successfully start the new store
 	if err := cfg.Save(); err != nil {
 		log.WithError(err).Error("failed to save new store configs")
@@ -219,8 +260,11 @@ func ReconfigureStore(c *StoreConfig) error {
 	}
 	log.Debug("added details of self to store")
 
-	// Now that new store is up, start events framework
+	// Now that new store is up, start events framework and message bus
 	events.Start()
+	if err := msgbus.Start(); err != nil {
+		log.WithError(err).Warn("failed to start message bus")
+	}
 	transaction.StartTxnEngine()
 	cleanuphandler.StartCleanupLeader()
 	return nil
@@ -233,4 +277,5 @@ func restartDefaultStore(destroy bool, deleteNamespace bool) {
 	store.Init(nil)
 	peer.AddSelfDetails()
 	events.StartGlobal()
+	msgbus.Start()
 }
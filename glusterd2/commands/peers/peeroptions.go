@@ -0,0 +1,143 @@
+package peercommands
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func peerOptionsLockKey(id string) string {
+	return "peeroptions." + id
+}
+
+// validatePeerOption checks if the cluster option can be overridden for a
+// peer, with the given value
+func validatePeerOption(key, value string) error {
+	if !options.PeerOverridableOptions[key] {
+		return fmt.Errorf("option %s cannot be overridden for a peer", key)
+	}
+	opt, found := options.ClusterOptMap[key]
+	if !found {
+		return fmt.Errorf("invalid cluster option: %s", key)
+	}
+	if opt.ValidateFunc != nil {
+		if err := opt.ValidateFunc(key, value); err != nil {
+			return fmt.Errorf("%s failed validation: %s", key, err)
+		}
+	}
+	return nil
+}
+
+func getPeerOptionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := mux.Vars(r)["peerid"]
+	if _, err := peer.GetPeerF(id); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	opts, err := options.GetPeerOptions(id)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.PeerOptionsResp{Options: opts})
+}
+
+func setPeerOptionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	id := mux.Vars(r)["peerid"]
+	if _, err := peer.GetPeerF(id); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	var req api.PeerOptionsReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	for k, v := range req.Options {
+		if err := validatePeerOption(k, v); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, peerOptionsLockKey(id))
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	opts, err := options.GetPeerOptions(id)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	for k, v := range req.Options {
+		opts[k] = v
+	}
+
+	if err := options.UpdatePeerOptions(id, opts); err != nil {
+		logger.WithError(err).WithField("peerid", id).Error("failed to update peer options")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.PeerOptionsResp{Options: opts})
+}
+
+func resetPeerOptionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	id := mux.Vars(r)["peerid"]
+	optname := mux.Vars(r)["optname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, peerOptionsLockKey(id))
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	opts, err := options.GetPeerOptions(id)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if _, ok := opts[optname]; !ok {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound,
+			fmt.Sprintf("option %s is not overridden for the peer", optname))
+		return
+	}
+	delete(opts, optname)
+
+	if err := options.UpdatePeerOptions(id, opts); err != nil {
+		logger.WithError(err).WithField("peerid", id).Error("failed to update peer options")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.PeerOptionsResp{Options: opts})
+}
@@ -0,0 +1,354 @@
+package peercommands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+	"github.com/gluster/glusterd2/version"
+
+	config "github.com/spf13/viper"
+)
+
+const (
+	preflightTxnKey = "preflight-checks"
+
+	preflightDialTimeout = 3 * time.Second
+	preflightHTTPTimeout = 5 * time.Second
+
+	// Clocks are compared using the Date header of HTTP responses, which
+	// has a resolution of a second
+	maxTimeSkewWarn = 2 * time.Second
+	maxTimeSkew     = 10 * time.Second
+
+	// preflightBrickPort is the first port given to bricks by glusterfs,
+	// checked as a sample of the brick port range
+	preflightBrickPort = "49152"
+)
+
+// preflightPort is a port of the new peer checked for reachability.
+// Required ports must be listening. Nothing may be listening on the other
+// ports until the peer joins, so connections refused on them pass.
+type preflightPort struct {
+	name     string
+	port     string
+	required bool
+}
+
+// preflightError is returned when preflight checks fail for a peer being
+// added, or detach checks fail for a peer being removed
+type preflightError struct {
+	report *api.PeerPreflightReport
+}
+
+func (e *preflightError) Error() string {
+	var msgs []string
+	for _, c := range e.report.Failures() {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", c.Name, c.Message))
+	}
+	return fmt.Sprintf("preflight checks failed for peer %s: %s", e.report.Address, strings.Join(msgs, "; "))
+}
+
+// Response implements api.ErrorResponse
+func (e *preflightError) Response() api.ErrorResp {
+	var resp api.ErrorResp
+	for _, c := range e.report.Failures() {
+		resp.Errors = append(resp.Errors, api.HTTPError{
+			Code:    int(api.ErrCodePreflightFailed),
+			Message: c.Message,
+			Fields: map[string]string{
+				"check":   c.Name,
+				"peer-id": c.PeerID,
+				"address": e.report.Address,
+			},
+		})
+	}
+	return resp
+}
+
+// Status implements api.ErrorResponse
+func (e *preflightError) Status() int {
+	return http.StatusPreconditionFailed
+}
+
+func registerPeerPreflightStepFuncs() {
+	transaction.RegisterStepFunc(txnPeerPreflight, "peer-add.Preflight")
+}
+
+// txnPeerPreflight runs the preflight checks from this peer against the peer
+// being added, and saves them as the result of this node
+func txnPeerPreflight(c transaction.TxnCtx) error {
+	var address string
+	if err := c.Get("address", &address); err != nil {
+		c.Logger().WithError(err).WithField("key", "address").Error("failed to get key from transaction context")
+		return err
+	}
+
+	checks := runPreflightChecks(address)
+	return c.SetNodeResult(gdctx.MyUUID, preflightTxnKey, checks)
+}
+
+// runPreflightChecks checks the peer at address, which is of the form
+// <host:port> with the port of the peer RPC service
+func runPreflightChecks(address string) []api.PreflightCheck {
+	host, peerPort, _ := net.SplitHostPort(address)
+	_, restPort, _ := net.SplitHostPort(config.GetString("clientaddress"))
+
+	ports := []preflightPort{
+		{name: "peer-rpc", port: peerPort, required: true},
+		{name: "rest", port: restPort, required: true},
+		{name: "etcd-client", port: "2379"},
+		{name: "etcd-peer", port: "2380"},
+		{name: "brick", port: preflightBrickPort},
+	}
+
+	var (
+		wg     sync.WaitGroup
+		checks = make([]api.PreflightCheck, len(ports))
+	)
+	for i, p := range ports {
+		wg.Add(1)
+		go func(i int, p preflightPort) {
+			defer wg.Done()
+			checks[i] = checkPort(host, p)
+		}(i, p)
+	}
+	wg.Wait()
+
+	checks = append(checks, checkReverseDNS(host))
+	checks = append(checks, checkVersionAndTime(net.JoinHostPort(host, restPort))...)
+
+	for i := range checks {
+		checks[i].PeerID = gdctx.MyUUID.String()
+	}
+	return checks
+}
+
+func checkPort(host string, p preflightPort) api.PreflightCheck {
+	check := api.PreflightCheck{Name: "port-" + p.name, Result: api.PreflightPass}
+	addr := net.JoinHostPort(host, p.port)
+
+	conn, err := net.DialTimeout("tcp", addr, preflightDialTimeout)
+	if err == nil {
+		conn.Close()
+		check.Message = fmt.Sprintf("%s is reachable", addr)
+		return check
+	}
+
+	// A refused connection means that the host is reachable, but nothing
+	// is listening on the port
+	if !p.required && isConnRefused(err) {
+		check.Message = fmt.Sprintf("%s is reachable, nothing is listening yet", addr)
+		return check
+	}
+
+	check.Result = api.PreflightFail
+	check.Message = fmt.Sprintf("%s is not reachable: %s", addr, err)
+	return check
+}
+
+func isConnRefused(err error) bool {
+	if opErr, ok := err.(*net.OpError); ok {
+		if sysErr, ok := opErr.Err.(*os.SyscallError); ok {
+			return sysErr.Err == syscall.ECONNREFUSED
+		}
+	}
+	return false
+}
+
+// checkReverseDNS checks that the addresses of the host resolve back to a
+// name. It only warns, as peers can be added by their IP addresses.
+func checkReverseDNS(host string) api.PreflightCheck {
+	check := api.PreflightCheck{Name: "reverse-dns", Result: api.PreflightWarn}
+
+	addrs, err := net.LookupHost(host)
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to resolve %s: %s", host, err)
+		return check
+	}
+
+	var names []string
+	for _, addr := range addrs {
+		if n, err := net.LookupAddr(addr); err == nil {
+			names = append(names, n...)
+		}
+	}
+	if len(names) == 0 {
+		check.Message = fmt.Sprintf("no names found for the addresses %s of %s", strings.Join(addrs, ", "), host)
+		return check
+	}
+
+	if net.ParseIP(host) == nil && !utils.StringInSlice(strings.ToLower(host), trimDots(names)) {
+		check.Message = fmt.Sprintf("addresses of %s resolve back to %s", host, strings.Join(trimDots(names), ", "))
+		return check
+	}
+
+	check.Result = api.PreflightPass
+	check.Message = fmt.Sprintf("%s resolves to %s", host, strings.Join(trimDots(names), ", "))
+	return check
+}
+
+func trimDots(names []string) []string {
+	trimmed := make([]string, 0, len(names))
+	for _, n := range names {
+		trimmed = append(trimmed, strings.ToLower(strings.TrimSuffix(n, ".")))
+	}
+	return trimmed
+}
+
+// checkVersionAndTime gets the version of the glusterd2 at address, and
+// compares its clock with ours using the Date header of the response
+func checkVersionAndTime(address string) []api.PreflightCheck {
+	versionCheck := api.PreflightCheck{Name: "version", Result: api.PreflightWarn}
+	timeCheck := api.PreflightCheck{Name: "time-skew", Result: api.PreflightWarn}
+
+	client := &http.Client{Timeout: preflightHTTPTimeout}
+	start := time.Now()
+	resp, err := client.Get("http://" + address + "/version")
+	if err != nil {
+		versionCheck.Message = fmt.Sprintf("failed to get version: %s", err)
+		timeCheck.Message = fmt.Sprintf("failed to get time: %s", err)
+		return []api.PreflightCheck{versionCheck, timeCheck}
+	}
+	defer resp.Body.Close()
+	rtt := time.Since(start)
+
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err != nil {
+		timeCheck.Message = "response has no valid Date header"
+	} else {
+		skew := date.Sub(start.Add(rtt / 2))
+		timeCheck.Result, timeCheck.Message = timeSkewResult(skew)
+	}
+
+	var v api.VersionResp
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		versionCheck.Message = "version could not be got, REST API authentication is enabled on the peer"
+	case resp.StatusCode != http.StatusOK:
+		versionCheck.Message = fmt.Sprintf("failed to get version: %s", resp.Status)
+	case json.NewDecoder(resp.Body).Decode(&v) != nil:
+		versionCheck.Message = "failed to parse version"
+	default:
+		versionCheck.Result, versionCheck.Message = versionResult(v)
+	}
+
+	return []api.PreflightCheck{versionCheck, timeCheck}
+}
+
+func timeSkewResult(skew time.Duration) (string, string) {
+	if skew < 0 {
+		skew = -skew
+	}
+	msg := fmt.Sprintf("clock differs by about %s", skew.Round(time.Second))
+	switch {
+	case skew > maxTimeSkew:
+		return api.PreflightFail, msg
+	case skew > maxTimeSkewWarn:
+		return api.PreflightWarn, msg
+	default:
+		return api.PreflightPass, msg
+	}
+}
+
+func versionResult(v api.VersionResp) (string, string) {
+	if v.APIVersion != version.APIVersion {
+		return api.PreflightFail, fmt.Sprintf("API version %d differs from %d", v.APIVersion, version.APIVersion)
+	}
+	if v.GlusterdVersion != version.GlusterdVersion {
+		return api.PreflightFail, fmt.Sprintf("glusterd2 version %s differs from %s", v.GlusterdVersion, version.GlusterdVersion)
+	}
+	return api.PreflightPass, fmt.Sprintf("glusterd2 version %s", v.GlusterdVersion)
+}
+
+// runPreflight runs the preflight checks from all the peers against the peer
+// at address. Peers which are down are reported with a warning.
+func runPreflight(ctx context.Context, address string) (*api.PeerPreflightReport, error) {
+	peers, err := peer.GetPeerIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "peer-add.Preflight",
+			Nodes:  peers,
+		},
+	}
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Ctx.Set("address", address); err != nil {
+		return nil, err
+	}
+	if err := txn.Do(); err != nil {
+		return nil, err
+	}
+
+	report := &api.PeerPreflightReport{Address: address, Checks: []api.PreflightCheck{}}
+	for _, node := range peers {
+		var checks []api.PreflightCheck
+		if err := txn.Ctx.GetNodeResult(node, preflightTxnKey, &checks); err != nil {
+			checks = []api.PreflightCheck{{
+				Name:    "peer-online",
+				PeerID:  node.String(),
+				Result:  api.PreflightWarn,
+				Message: "could not run checks from peer",
+			}}
+		}
+		report.Checks = append(report.Checks, checks...)
+	}
+	report.Passed = len(report.Failures()) == 0
+
+	return report, nil
+}
+
+// peerPreflightHandler runs the preflight checks for adding a peer, without
+// adding it
+func peerPreflightHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	var req api.PeerPreflightReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if len(req.Addresses) < 1 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrNoHostnamesPresent)
+		return
+	}
+
+	address, err := utils.FormRemotePeerAddress(req.Addresses[0])
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "failed to parse remote address")
+		return
+	}
+
+	report, err := runPreflight(ctx, address)
+	if err != nil {
+		logger.WithError(err).WithField("address", address).Error("failed to run preflight checks")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, report)
+}
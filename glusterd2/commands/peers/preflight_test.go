@@ -0,0 +1,82 @@
+package peercommands
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/version"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPort(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	check := checkPort("127.0.0.1", preflightPort{name: "rest", port: port, required: true})
+	assert.Equal(t, api.PreflightPass, check.Result)
+
+	// Nothing listens on the port after the listener is closed
+	l.Close()
+	check = checkPort("127.0.0.1", preflightPort{name: "rest", port: port, required: true})
+	assert.Equal(t, api.PreflightFail, check.Result)
+	check = checkPort("127.0.0.1", preflightPort{name: "brick", port: port})
+	assert.Equal(t, api.PreflightPass, check.Result)
+}
+
+func TestTimeSkewResult(t *testing.T) {
+	result, _ := timeSkewResult(time.Second)
+	assert.Equal(t, api.PreflightPass, result)
+	result, _ = timeSkewResult(-5 * time.Second)
+	assert.Equal(t, api.PreflightWarn, result)
+	result, _ = timeSkewResult(time.Minute)
+	assert.Equal(t, api.PreflightFail, result)
+}
+
+func TestVersionResult(t *testing.T) {
+	v := api.VersionResp{GlusterdVersion: version.GlusterdVersion, APIVersion: version.APIVersion}
+	result, _ := versionResult(v)
+	assert.Equal(t, api.PreflightPass, result)
+
+	v.GlusterdVersion = version.GlusterdVersion + "-other"
+	result, _ = versionResult(v)
+	assert.Equal(t, api.PreflightFail, result)
+}
+
+func TestPreflightError(t *testing.T) {
+	report := &api.PeerPreflightReport{
+		Address: "host:24008",
+		Checks: []api.PreflightCheck{
+			{Name: "port-rest", PeerID: "p1", Result: api.PreflightFail, Message: "not reachable"},
+			{Name: "reverse-dns", PeerID: "p1", Result: api.PreflightWarn},
+			{Name: "version", PeerID: "p2", Result: api.PreflightPass},
+		},
+	}
+
+	resp := (&preflightError{report}).Response()
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, int(api.ErrCodePreflightFailed), resp.Errors[0].Code)
+	assert.Equal(t, "port-rest", resp.Errors[0].Fields["check"])
+	assert.Equal(t, "p1", resp.Errors[0].Fields["peer-id"])
+}
+
+func TestBricksResult(t *testing.T) {
+	check := bricksResult(nil)
+	assert.Equal(t, api.PreflightPass, check.Result)
+
+	check = bricksResult([]brick.Brickinfo{
+		{VolumeName: "vol1", Path: "/b1"},
+		{VolumeName: "vol1", Path: "/b2"},
+		{VolumeName: "vol2", Path: "/b3"},
+	})
+	assert.Equal(t, api.PreflightFail, check.Result)
+	assert.Contains(t, check.Message, "vol1, vol2")
+
+	report := &api.PeerPreflightReport{Checks: []api.PreflightCheck{check}}
+	assert.False(t, detachCheckPassed(report, "bricks"))
+	assert.False(t, detachCheckPassed(report, "peer-online"))
+}
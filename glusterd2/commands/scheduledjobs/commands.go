@@ -0,0 +1,70 @@
+// Package scheduledjobscommands implements the REST endpoints to manage
+// periodic jobs run by the scheduler
+package scheduledjobscommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "ScheduledJobList",
+			Description:  "List periodic jobs registered with the scheduler",
+			Method:       "GET",
+			Pattern:      "/scheduled-jobs",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ScheduledJobListResp)(nil)),
+			HandlerFunc:  scheduledJobListHandler,
+		},
+		route.Route{
+			Name:         "ScheduledJobInfo",
+			Description:  "Get the state of a periodic job",
+			Method:       "GET",
+			Pattern:      "/scheduled-jobs/{name}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ScheduledJob)(nil)),
+			HandlerFunc:  scheduledJobInfoHandler,
+		},
+		route.Route{
+			Name:         "ScheduledJobHistory",
+			Description:  "Get the recent runs of a periodic job",
+			Method:       "GET",
+			Pattern:      "/scheduled-jobs/{name}/history",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ScheduledJobHistoryResp)(nil)),
+			HandlerFunc:  scheduledJobHistoryHandler,
+		},
+		route.Route{
+			Name:         "ScheduledJobEnable",
+			Description:  "Enable a periodic job, optionally changing its schedule",
+			Method:       "POST",
+			Pattern:      "/scheduled-jobs/{name}/enable",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.ScheduledJobEnableReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.ScheduledJob)(nil)),
+			HandlerFunc:  scheduledJobEnableHandler,
+		},
+		route.Route{
+			Name:         "ScheduledJobDisable",
+			Description:  "Disable a periodic job",
+			Method:       "POST",
+			Pattern:      "/scheduled-jobs/{name}/disable",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ScheduledJob)(nil)),
+			HandlerFunc:  scheduledJobDisableHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,98 @@
+package scheduledjobscommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func sendJobError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == scheduler.ErrJobNotFound {
+		restutils.SendHTTPError(r.Context(), w, http.StatusNotFound, err)
+		return
+	}
+	restutils.SendHTTPError(r.Context(), w, http.StatusInternalServerError, err)
+}
+
+func scheduledJobListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	jobs, err := scheduler.GetJobs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.ScheduledJobListResp(jobs))
+}
+
+func scheduledJobInfoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	job, err := scheduler.GetJob(mux.Vars(r)["name"])
+	if err != nil {
+		sendJobError(w, r, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, job)
+}
+
+func scheduledJobHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := mux.Vars(r)["name"]
+
+	if _, err := scheduler.GetJob(name); err != nil {
+		sendJobError(w, r, err)
+		return
+	}
+
+	runs, err := scheduler.GetJobHistory(name)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.ScheduledJobHistoryResp(runs))
+}
+
+func scheduledJobEnableHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := mux.Vars(r)["name"]
+
+	var req api.ScheduledJobEnableReq
+	if r.ContentLength != 0 {
+		if err := restutils.UnmarshalRequest(r, &req); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+			return
+		}
+	}
+
+	if req.Schedule != "" {
+		if _, err := scheduler.ParseSchedule(req.Schedule); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if err := scheduler.EnableJob(name, req.Schedule); err != nil {
+		sendJobError(w, r, err)
+		return
+	}
+
+	scheduledJobInfoHandler(w, r)
+}
+
+func scheduledJobDisableHandler(w http.ResponseWriter, r *http.Request) {
+	if err := scheduler.DisableJob(mux.Vars(r)["name"]); err != nil {
+		sendJobError(w, r, err)
+		return
+	}
+
+	scheduledJobInfoHandler(w, r)
+}
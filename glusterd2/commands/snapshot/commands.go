@@ -23,7 +23,30 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			RequestType:  utils.GetTypeString((*api.SnapCreateReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.SnapCreateResp)(nil)),
+			Permission:   route.PermOperate,
+			Heavy:        true,
 			HandlerFunc:  snapshotCreateHandler},
+		route.Route{
+			Name:         "SnapshotHookAdd",
+			Method:       "POST",
+			Pattern:      "/snapshot-hooks",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.SnapHookReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.SnapHook)(nil)),
+			HandlerFunc:  snapshotHookAddHandler},
+		route.Route{
+			Name:         "SnapshotHookList",
+			Method:       "GET",
+			Pattern:      "/snapshot-hooks",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.SnapHookListResp)(nil)),
+			HandlerFunc:  snapshotHookListHandler},
+		route.Route{
+			Name:        "SnapshotHookDelete",
+			Method:      "DELETE",
+			Pattern:     "/snapshot-hooks/{hookname}",
+			Version:     1,
+			HandlerFunc: snapshotHookDeleteHandler},
 		route.Route{
 			Name:         "SnapshotActivate",
 			Method:       "POST",
@@ -31,6 +54,7 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			RequestType:  utils.GetTypeString((*api.SnapActivateReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.SnapshotActivateResp)(nil)),
+			Permission:   route.PermOperate,
 			HandlerFunc:  snapshotActivateHandler},
 		route.Route{
 			Name:         "SnapshotDeactivate",
@@ -38,6 +62,7 @@ func (c *Command) Routes() route.Routes {
 			Pattern:      "/snapshots/{snapname}/deactivate",
 			Version:      1,
 			ResponseType: utils.GetTypeString((*api.SnapshotDeactivateResp)(nil)),
+			Permission:   route.PermOperate,
 			HandlerFunc:  snapshotDeactivateHandler},
 		route.Route{
 			Name:         "SnapshotClone",
@@ -46,12 +71,14 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			RequestType:  utils.GetTypeString((*api.SnapCloneReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.SnapshotCloneResp)(nil)),
+			Heavy:        true,
 			HandlerFunc:  snapshotCloneHandler},
 		route.Route{
 			Name:        "SnapshotRestore",
 			Method:      "POST",
 			Pattern:     "/snapshots/{snapname}/restore",
 			Version:     1,
+			Heavy:       true,
 			HandlerFunc: snapshotRestoreHandler},
 		route.Route{
 			Name:         "SnapshotInfo",
@@ -0,0 +1,24 @@
+package snapshotcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+type snapshotEvent string
+
+const (
+	eventSnapshotCreated snapshotEvent = "snapshot.created"
+)
+
+// newSnapshotEvent returns a lifecycle event carrying the full snapshot info
+func newSnapshotEvent(e snapshotEvent, snap *snapshot.Snapinfo) *api.Event {
+	data := map[string]string{
+		"snapshot.name": snap.SnapVolinfo.Name,
+		"snapshot.id":   snap.SnapVolinfo.ID.String(),
+		"volume.name":   snap.ParentVolume,
+	}
+
+	return events.NewLifecycle(string(e), data, api.EventResourceSnapshot, createSnapInfoResp(snap))
+}
@@ -1,6 +1,7 @@
 package snapshotcommands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -269,7 +270,6 @@ func registerSnapCloneStepFuncs() {
 func snapshotCloneHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	req := new(api.SnapCloneReq)
-	logger := gdctx.GetReqLogger(ctx)
 
 	snapname := mux.Vars(r)["snapname"]
 	if snapname == "" {
@@ -282,37 +282,61 @@ func snapshotCloneHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !volume.IsValidName(req.CloneName) {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidVolName)
+	if err := volume.ValidateName(req.CloneName); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
 		return
 	}
 
-	txn, err := transaction.NewTxnWithLocks(ctx, req.CloneName, snapname)
+	if restutils.AsyncRequested(r) {
+		restutils.SendAsyncJob(ctx, w, "snapshot-clone", req.CloneName, func(ctx context.Context) (interface{}, error) {
+			vol, err := cloneSnapshot(ctx, snapname, req.CloneName)
+			if err != nil {
+				return nil, err
+			}
+			return createSnapshotCloneResp(vol), nil
+		}, nil)
+		return
+	}
+
+	vol, err := cloneSnapshot(ctx, snapname, req.CloneName)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+
+	resp := createSnapshotCloneResp(vol)
+	restutils.SetLocationHeader(r, w, vol.Name)
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+
+}
+
+// cloneSnapshot creates the volume clonename from the snapshot
+func cloneSnapshot(ctx context.Context, snapname, clonename string) (*volume.Volinfo, error) {
+	logger := gdctx.Logger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, clonename, snapname)
+	if err != nil {
+		return nil, err
+	}
 	defer txn.Done()
 
 	snapinfo, err := snapshot.GetSnapshot(snapname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, err
 	}
 	snapVol := &snapinfo.SnapVolinfo
 
-	if volume.Exists(req.CloneName) {
-		errMsg := "A volume with the same clone name exist."
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-		return
+	release, err := volume.ReserveName(clonename)
+	if err == gderrors.ErrVolExists {
+		return nil, transaction.NewValidationError(errors.New("a volume with the same clone name exists"))
+	} else if err != nil {
+		return nil, err
 	}
+	defer release()
 
 	if snapVol.State != volume.VolStarted {
-		errMsg := "Snapshot must be in started state before cloning."
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-		return
+		return nil, transaction.NewValidationError(errors.New("snapshot must be in started state before cloning"))
 	}
 	txn.Nodes = snapVol.Nodes()
 	txn.Steps = []*transaction.Step{
@@ -339,37 +363,24 @@ func snapshotCloneHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	if err = txn.Ctx.Set("snapname", &snapname); err != nil {
 		logger.WithError(err).Error("failed to set request in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
-	if err = txn.Ctx.Set("clonename", &req.CloneName); err != nil {
+	if err = txn.Ctx.Set("clonename", &clonename); err != nil {
 		logger.WithError(err).Error("failed to set request in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
 	if err = txn.Do(); err != nil {
 		logger.WithError(err).Error("snapshot clone transaction failed")
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, err
 	}
 
-	txn.Ctx.Logger().WithField("CloneName", req.CloneName).Info("new volume cloned from snapshot")
-
-	vol, err := volume.GetVolume(req.CloneName)
-	if err != nil {
-		// FIXME: If volume was created successfully in the txn above and
-		// then the store goes down by the time we reach here, what do
-		// we return to the client ?
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
-	}
-
-	resp := createSnapshotCloneResp(vol)
-	restutils.SetLocationHeader(r, w, vol.Name)
-	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+	txn.Ctx.Logger().WithField("CloneName", clonename).Info("new volume cloned from snapshot")
 
+	// FIXME: If volume was created successfully in the txn above and
+	// then the store goes down by the time we reach here, what do
+	// we return to the client ?
+	return volume.GetVolume(clonename)
 }
 
 func createSnapshotCloneResp(v *volume.Volinfo) *api.SnapshotCloneResp {
@@ -20,6 +20,7 @@ import (
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
@@ -50,7 +51,11 @@ func barrierActivateDeactivateFunc(volinfo *volume.Volinfo, option string, origi
 
 	volinfo.Options["features/barrier"] = option
 	if bytes.Equal(originUUID, gdctx.MyUUID) {
-		if err = volume.AddOrUpdateVolumeFunc(volinfo); err != nil {
+		_, err = volume.UpdateVolume(volinfo.Name, 0, func(v *volume.Volinfo) error {
+			v.Options["features/barrier"] = option
+			return nil
+		})
+		if err != nil {
 			log.WithError(err).WithField(
 				"volume", volinfo.Name).Debug("failed to store volume info")
 			return err
@@ -216,17 +221,13 @@ func storeSnapshotCreate(c transaction.TxnCtx) error {
 	}
 	volinfo := &snapInfo.SnapVolinfo
 
-	vol, err := volume.GetVolume(snapInfo.ParentVolume)
+	_, err := volume.UpdateVolume(snapInfo.ParentVolume, 0, func(v *volume.Volinfo) error {
+		v.SnapList = append(v.SnapList, volinfo.Name)
+		return nil
+	})
 	if err != nil {
 		c.Logger().WithError(err).WithField(
-			"volume", snapInfo.ParentVolume).Debug("storeVolume: failed to fetch Volinfo from store")
-		return err
-	}
-
-	vol.SnapList = append(vol.SnapList, volinfo.Name)
-	if err := volume.AddOrUpdateVolumeFunc(vol); err != nil {
-		c.Logger().WithError(err).WithField(
-			"volume", vol.Name).Debug("storeVolume: failed to store Volinfo")
+			"volume", snapInfo.ParentVolume).Debug("storeVolume: failed to store Volinfo")
 		return err
 	}
 
@@ -521,6 +522,9 @@ func createSnapinfo(c transaction.TxnCtx) error {
 		"features/inode-quota":       "off",
 		"feature/deem-statfs":        "off",
 		"features/quota-deem-statfs": "off",
+		"quota.server-quota":         "off",
+		"marker.quota":               "off",
+		"marker.inode-quota":         "off",
 		"bitrot-stub.bitrot":         "off",
 		"replicate.self-heal-daemon": "off",
 		"features/read-only":         "on",
@@ -697,6 +701,9 @@ func registerSnapCreateStepFuncs() {
 		{"snap-create.DeactivateBarrier", deactivateBarrier},
 		{"snap-create.StoreSnapshot", storeSnapshotCreate},
 		{"snap-create.UndoStoreSnapshotOnCreate", undoStoreSnapshotOnCreate},
+		{"snap-create.PreHooks", runPreSnapHooks},
+		{"snap-create.UndoPreHooks", undoPreSnapHooks},
+		{"snap-create.PostHooks", runPostSnapHooks},
 	}
 	for _, sf := range sfs {
 		transaction.RegisterStepFunc(sf.sf, sf.name)
@@ -708,7 +715,7 @@ func snapshotCreateHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, span := trace.StartSpan(ctx, "/snapshotCreateHandler")
 	defer span.End()
 
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	var snapInfo snapshot.Snapinfo
 	var data txnData
 	req := &data.Req
@@ -765,6 +772,14 @@ func snapshotCreateHandler(w http.ResponseWriter, r *http.Request) {
 			Nodes:  []uuid.UUID{gdctx.MyUUID},
 			Sync:   true,
 		},
+		{
+			// Applications are quiesced by the pre hooks before the
+			// bricks are barriered
+			DoFunc:   "snap-create.PreHooks",
+			UndoFunc: "snap-create.UndoPreHooks",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
 		{
 			DoFunc:   "snap-create.ActivateBarrier",
 			UndoFunc: "snap-create.DeactivateBarrier",
@@ -782,6 +797,11 @@ func snapshotCreateHandler(w http.ResponseWriter, r *http.Request) {
 			DoFunc: "snap-create.DeactivateBarrier",
 			Nodes:  txn.Nodes,
 		},
+		{
+			DoFunc: "snap-create.PostHooks",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+			Sync:   true,
+		},
 
 		{
 			DoFunc:   "snap-create.StoreSnapshot",
@@ -815,6 +835,8 @@ func snapshotCreateHandler(w http.ResponseWriter, r *http.Request) {
 	resp := createSnapCreateResp(&snapInfo)
 	restutils.SetLocationHeader(r, w, snapInfo.SnapVolinfo.Name)
 	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+
+	events.Broadcast(newSnapshotEvent(eventSnapshotCreated, &snapInfo))
 }
 
 // createSnapCreateResp functions create resnse for rest utils
@@ -830,5 +852,6 @@ func createSnapInfoResp(snap *snapshot.Snapinfo) *api.SnapInfo {
 		ParentVolName: snap.ParentVolume,
 		Description:   snap.Description,
 		CreatedAt:     snap.CreatedAt,
+		HookResults:   snap.HookResults,
 	}
 }
@@ -126,7 +126,7 @@ func snapshotDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, span := trace.StartSpan(ctx, "/snapshotDeleteHandler")
 	defer span.End()
 
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	snapname := mux.Vars(r)["snapname"]
 	//Fetching snapinfo to get the parent volume name. Parent volume has to be locked
 	snapinfo, err := snapshot.GetSnapshot(snapname)
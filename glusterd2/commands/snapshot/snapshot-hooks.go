@@ -0,0 +1,146 @@
+package snapshotcommands
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// runSnapHooks runs the hooks of the phase for the snapshot one after the
+// other, recording their outcomes in the snapinfo. The first mandatory hook
+// to fail stops the hooks after it from being run.
+func runSnapHooks(c transaction.TxnCtx, snapInfo *snapshot.Snapinfo, phase string) error {
+	hooks, err := snapshot.GetVolumeHooks(snapInfo.ParentVolume, phase)
+	if err != nil {
+		return err
+	}
+
+	snapname := snapInfo.SnapVolinfo.Name
+	for _, hook := range hooks {
+		result := snapshot.RunHook(hook, snapInfo.ParentVolume, snapname)
+		snapInfo.HookResults = append(snapInfo.HookResults, result)
+
+		logger := c.Logger().WithFields(log.Fields{
+			"hook":     hook.Name,
+			"phase":    phase,
+			"snapshot": snapname,
+		})
+		if result.Success {
+			logger.Info("snapshot hook succeeded")
+			continue
+		}
+		logger.WithField("error", result.Error).Warn("snapshot hook failed")
+		if hook.Mandatory {
+			return fmt.Errorf("mandatory snapshot hook %s failed: %s", hook.Name, result.Error)
+		}
+	}
+	return nil
+}
+
+func runPreSnapHooks(c transaction.TxnCtx) error {
+	var snapInfo snapshot.Snapinfo
+	if err := c.Get("snapinfo", &snapInfo); err != nil {
+		return err
+	}
+
+	hookErr := runSnapHooks(c, &snapInfo, api.SnapHookPre)
+	if err := c.Set("snapinfo", &snapInfo); err != nil {
+		return err
+	}
+	return hookErr
+}
+
+func runPostSnapHooks(c transaction.TxnCtx) error {
+	var snapInfo snapshot.Snapinfo
+	if err := c.Get("snapinfo", &snapInfo); err != nil {
+		return err
+	}
+
+	// The post hooks are run only once, even if the snapshot fails after
+	// they are run
+	if err := c.Set("post-hooks-run", true); err != nil {
+		return err
+	}
+
+	hookErr := runSnapHooks(c, &snapInfo, api.SnapHookPost)
+	if err := c.Set("snapinfo", &snapInfo); err != nil {
+		return err
+	}
+	return hookErr
+}
+
+// undoPreSnapHooks runs the post hooks when the snapshot fails, so that
+// applications quiesced by the pre hooks are resumed
+func undoPreSnapHooks(c transaction.TxnCtx) error {
+	var postHooksRun bool
+	if err := c.Get("post-hooks-run", &postHooksRun); err == nil && postHooksRun {
+		return nil
+	}
+
+	var snapInfo snapshot.Snapinfo
+	if err := c.Get("snapinfo", &snapInfo); err != nil {
+		return err
+	}
+	return runSnapHooks(c, &snapInfo, api.SnapHookPost)
+}
+
+func snapshotHookAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	var req api.SnapHookReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	hook := api.SnapHook(req)
+	if err := snapshot.ValidateHook(&hook); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := snapshot.AddOrUpdateHook(&hook); err != nil {
+		logger.WithError(err).WithField("hook", hook.Name).Error("failed to store snapshot hook")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, hook)
+}
+
+func snapshotHookListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	hooks, err := snapshot.GetHooks()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.SnapHookListResp(hooks))
+}
+
+func snapshotHookDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := snapshot.DeleteHook(mux.Vars(r)["hookname"])
+	if err == snapshot.ErrSnapHookNotFound {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
@@ -314,7 +314,7 @@ func registerSnapRestoreStepFuncs() {
 
 func snapshotRestoreHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	snapname := mux.Vars(r)["snapname"]
 
 	snapinfo, err := snapshot.GetSnapshot(snapname)
@@ -353,6 +353,15 @@ func snapshotRestoreHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := volume.CheckAdvisoryLocks(vol.Name); err != nil {
+		if _, ok := err.(*volume.AdvisoryLockHeldError); ok {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+			return
+		}
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
 	bricksAutoProvisioned := vol.IsAutoProvisioned() || vol.IsSnapshotProvisioned()
 	txn.Steps = []*transaction.Step{
 		{
@@ -83,7 +83,7 @@ func registerSnapshotStatusStepFuncs() {
 func snapshotStatusHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	snapname := mux.Vars(r)["snapname"]
 	snap, err := snapshot.GetSnapshot(snapname)
@@ -0,0 +1,399 @@
+package supportbundlecommands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/logging"
+	"github.com/gluster/glusterd2/version"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	config "github.com/spf13/viper"
+)
+
+const (
+	nodeDataTxnKey = "support-bundle-data"
+	// The collected data of every peer is saved in the store as a
+	// transaction node result, so the size of the log collected from each
+	// peer has to be limited.
+	defaultLogSize = 256 * 1024
+	maxLogSize     = 512 * 1024
+)
+
+// nodeData is the diagnostic data collected from a single peer
+type nodeData struct {
+	Version   map[string]string `json:"version"`
+	Statedump []byte            `json:"statedump"`
+	Log       []byte            `json:"log,omitempty"`
+}
+
+func bundlesDir() string {
+	return path.Join(config.GetString("localstatedir"), "support-bundles")
+}
+
+// statedump returns the exported expvar variables of this peer in the same
+// format as the /statedump endpoint
+func statedump() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	first := true
+	expvar.Do(func(kv expvar.KeyValue) {
+		if !first {
+			buf.WriteString(",\n")
+		}
+		first = false
+		fmt.Fprintf(&buf, "%q: %s", kv.Key, kv.Value)
+	})
+	buf.WriteString("\n}\n")
+	return buf.Bytes()
+}
+
+// tailLog returns at most size bytes from the end of the glusterd2 log file
+func tailLog(size int64) ([]byte, error) {
+	logFile := config.GetString(logging.FileFlag)
+	if logFile == "" || logFile == "-" || logFile == "STDOUT" || logFile == "STDERR" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path.Join(config.GetString(logging.DirFlag), logFile))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := st.Size() - size
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	_, err = io.CopyN(&buf, f, size)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// txnCollectNodeData collects the diagnostic data of this peer and saves it
+// as the result of this node
+func txnCollectNodeData(c transaction.TxnCtx) error {
+
+	var req api.SupportBundleReq
+	if err := c.Get("req", &req); err != nil {
+		return err
+	}
+
+	data := nodeData{
+		Version: map[string]string{
+			"glusterd-version": version.GlusterdVersion,
+			"git-sha":          version.GitSHA,
+			"go-version":       runtime.Version(),
+			"api-version":      fmt.Sprintf("%d", version.APIVersion),
+		},
+		Statedump: statedump(),
+	}
+
+	l, err := tailLog(req.LogSize)
+	if err != nil {
+		// Not having logs shouldn't prevent collecting rest of the data
+		c.Logger().WithError(err).Warn("failed to read glusterd2 log")
+	}
+	data.Log = l
+
+	return c.SetNodeResult(gdctx.MyUUID, nodeDataTxnKey, &data)
+}
+
+// storeDumpPrefixes are the prefixes of the store keys included in the store
+// dump of a bundle. Only cluster state is dumped; keys under config/, like
+// the user secrets and the forward secret, and the volfiles, which have the
+// volume passwords filled in, are left out.
+var storeDumpPrefixes = []string{
+	"alive/",
+	"async-jobs/",
+	"async-jobs-running/",
+	"brick-wipe/",
+	"bricks/",
+	"clusteroptions",
+	"daemons/",
+	"devices/",
+	"heavy-op-slots/",
+	"locks/",
+	"ops/",
+	"peer-evacuation/",
+	"peer-options/",
+	"peers/",
+	"pending-transaction/",
+	"rebalance/",
+	"scheduled-jobs/",
+	"scheduled-jobs-history/",
+	"snaps/",
+	"transaction-history/",
+	"volinfos/",
+	"volume-",
+}
+
+// inStoreDump returns true if key is to be included in the store dump
+func inStoreDump(key string) bool {
+	for _, prefix := range storeDumpPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+const redacted = "REDACTED"
+
+// secretFields are the words in the names of JSON fields whose values are
+// redacted in the store dump, like the Password of volume auth
+var secretFields = []string{"password", "secret", "token"}
+
+func isSecretField(name string) bool {
+	name = strings.ToLower(name)
+	for _, f := range secretFields {
+		if strings.Contains(name, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue replaces the values of the secret fields in v, decoded from
+// JSON, and returns true if any were replaced
+func redactValue(v interface{}) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for name, value := range v {
+			if _, ok := value.(string); ok && isSecretField(name) {
+				v[name] = redacted
+				found = true
+			} else if redactValue(value) {
+				found = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if redactValue(value) {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// redactSecrets returns value, a JSON document or otherwise, with the values
+// of the secret fields redacted
+func redactSecrets(value []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil || !redactValue(v) {
+		return string(value)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return redacted
+	}
+	return string(b)
+}
+
+// storeDump returns the keys and values in the store describing the state of
+// the cluster, with the secrets in them redacted
+func storeDump() ([]byte, error) {
+	resp, err := store.Get(context.TODO(), "", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	kvs := make(map[string]string)
+	for _, kv := range resp.Kvs {
+		if key := string(kv.Key); inStoreDump(key) {
+			kvs[key] = redactSecrets(kv.Value)
+		}
+	}
+
+	return json.MarshalIndent(kvs, "", "  ")
+}
+
+// recentEvents returns the global events still present in the store
+func recentEvents() ([]byte, error) {
+	resp, err := store.Get(context.TODO(), "events/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*api.Event
+	for _, kv := range resp.Kvs {
+		var ev api.Event
+		if err := json.Unmarshal(kv.Value, &ev); err != nil {
+			continue
+		}
+		events = append(events, &ev)
+	}
+
+	return json.MarshalIndent(events, "", "  ")
+}
+
+// bundleFile is a file to be added to the support bundle archive
+type bundleFile struct {
+	name string
+	data []byte
+}
+
+// bundleFiles returns the data collected from all the peers, along with a
+// dump of the store and the recent events, as the files of the bundle
+func bundleFiles(id string, nodes []uuid.UUID, c transaction.TxnCtx) ([]bundleFile, error) {
+	prefix := "support-bundle-" + id + "/"
+
+	var files []bundleFile
+	for _, node := range nodes {
+		var data nodeData
+		if err := c.GetNodeResult(node, nodeDataTxnKey, &data); err != nil {
+			return nil, err
+		}
+
+		v, err := json.MarshalIndent(data.Version, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		dir := prefix + node.String() + "/"
+		files = append(files,
+			bundleFile{dir + "version.json", v},
+			bundleFile{dir + "statedump.json", data.Statedump},
+		)
+		if data.Log != nil {
+			files = append(files, bundleFile{dir + "glusterd2.log", data.Log})
+		}
+	}
+
+	s, err := storeDump()
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, bundleFile{prefix + "store.json", s})
+
+	e, err := recentEvents()
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, bundleFile{prefix + "events.json", e})
+
+	return files, nil
+}
+
+func writeTar(w io.Writer, files []bundleFile, modTime time.Time) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, file := range files {
+		hdr := &tar.Header{
+			Name:    file.name,
+			Mode:    0600,
+			Size:    int64(len(file.data)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return err
+		}
+	}
+
+	// The archive is complete only once the tar and gzip footers have been
+	// written out
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// writeArchive writes the files into a compressed tar archive at
+// archivePath. The archive is removed if it couldn't be written completely.
+func writeArchive(archivePath string, files []bundleFile) error {
+	if err := os.MkdirAll(path.Dir(archivePath), os.ModeDir|os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(archivePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = writeTar(f, files, time.Now())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(archivePath)
+	}
+	return err
+}
+
+// generateBundle runs a transaction to collect data from the given peers and
+// then archives it on this peer. It is run as the support-bundle async job.
+func generateBundle(ctx context.Context, req *api.SupportBundleReq, nodes []uuid.UUID) (*api.SupportBundleResp, error) {
+	txn := transactionv2.NewTxn(ctx)
+	defer txn.Done()
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "support-bundle.CollectNodeData",
+			Nodes:  nodes,
+		},
+	}
+
+	if err := txn.Ctx.Set("req", req); err != nil {
+		return nil, err
+	}
+
+	if err := txn.Do(); err != nil {
+		return nil, err
+	}
+
+	id := uuid.NewRandom().String()
+	files, err := bundleFiles(id, nodes, txn.Ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	archivePath := path.Join(bundlesDir(), id+".tar.gz")
+	if err := writeArchive(archivePath, files); err != nil {
+		return nil, err
+	}
+
+	bundle := &api.SupportBundleResp{Path: archivePath}
+	for _, n := range nodes {
+		bundle.Peers = append(bundle.Peers, n.String())
+	}
+
+	gdctx.Logger(ctx).WithField("path", archivePath).Info("support bundle generated")
+	return bundle, nil
+}
@@ -0,0 +1,116 @@
+package supportbundlecommands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "support-bundle")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := []bundleFile{
+		{"support-bundle-1/peer1/version.json", []byte(`{"go-version": "go1.10"}`)},
+		{"support-bundle-1/peer1/glusterd2.log", []byte("log line\n")},
+		{"support-bundle-1/store.json", []byte("{}")},
+		{"support-bundle-1/events.json", []byte("null")},
+	}
+
+	archivePath := path.Join(dir, "bundles", "1.tar.gz")
+	require.NoError(t, writeArchive(archivePath, files))
+
+	f, err := os.Open(archivePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	for _, file := range files {
+		hdr, err := tr.Next()
+		require.NoError(t, err)
+		assert.Equal(t, file.name, hdr.Name)
+
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		assert.Equal(t, file.data, data)
+	}
+	_, err = tr.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestInStoreDump(t *testing.T) {
+	for _, key := range []string{
+		"peers/2a6b8d5d-1b1c-4d12-9c6c-0a8d4c0a2d8b",
+		"volinfos/2a6b8d5d-1b1c-4d12-9c6c-0a8d4c0a2d8b",
+		"volume-index/vol1",
+		"snaps/snap1",
+		"clusteroptions",
+	} {
+		assert.True(t, inStoreDump(key), key)
+	}
+
+	for _, key := range []string{
+		"config/users/admin",
+		"config/forward-secret",
+		"config/federation/clusters/remote",
+		"config/exporters/prometheus",
+		"georeplication-ssh-keys/vol1",
+		"volfiles/vol1.tcp-fuse",
+	} {
+		assert.False(t, inStoreDump(key), key)
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	volinfo := `{"Name":"vol1","Auth":{"Username":"u1","Password":"p1"},"Bricks":[{"token":"t1","Path":"/b1"}]}`
+	assert.Equal(t,
+		`{"Auth":{"Password":"REDACTED","Username":"u1"},"Bricks":[{"Path":"/b1","token":"REDACTED"}],"Name":"vol1"}`,
+		redactSecrets([]byte(volinfo)))
+
+	// Values without secrets are left as is
+	assert.Equal(t, `{"Name": "vol1"}`, redactSecrets([]byte(`{"Name": "vol1"}`)))
+	assert.Equal(t, "not json", redactSecrets([]byte("not json")))
+}
+
+func TestBundleFromJob(t *testing.T) {
+	job := &api.AsyncJob{
+		ID:        uuid.NewRandom(),
+		Op:        bundleJobOp,
+		State:     api.AsyncJobRunning,
+		StartedAt: time.Now(),
+	}
+
+	b, err := bundleFromJob(job)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID.String(), b.ID)
+	assert.Equal(t, api.AsyncJobRunning, b.State)
+	assert.Empty(t, b.Path)
+
+	job.State = api.AsyncJobSucceeded
+	job.FinishedAt = job.StartedAt.Add(time.Minute)
+	job.Result = json.RawMessage(`{"peers": ["peer1"], "path": "/var/lib/glusterd2/support-bundles/1.tar.gz"}`)
+
+	b, err = bundleFromJob(job)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID.String(), b.ID)
+	assert.Equal(t, api.AsyncJobSucceeded, b.State)
+	assert.Equal(t, []string{"peer1"}, b.Peers)
+	assert.Equal(t, "/var/lib/glusterd2/support-bundles/1.tar.gz", b.Path)
+	assert.Equal(t, job.FinishedAt, b.EndTime)
+}
@@ -0,0 +1,63 @@
+// Package supportbundlecommands implements the support bundle commands which
+// gather diagnostic data from all peers into a single archive
+package supportbundlecommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "SupportBundleCreate",
+			Description:  "Gather diagnostic data from all peers into a compressed archive",
+			Method:       "POST",
+			Pattern:      "/support-bundle",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.SupportBundleReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.AsyncJob)(nil)),
+			HandlerFunc:  supportBundleCreateHandler,
+		},
+		route.Route{
+			Name:         "SupportBundleList",
+			Description:  "List support bundles generated in the cluster",
+			Method:       "GET",
+			Pattern:      "/support-bundle",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.SupportBundleListResp)(nil)),
+			HandlerFunc:  supportBundleListHandler,
+		},
+		route.Route{
+			Name:         "SupportBundleStatus",
+			Description:  "Get the progress of a support bundle",
+			Method:       "GET",
+			Pattern:      "/support-bundle/{id}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.SupportBundleResp)(nil)),
+			HandlerFunc:  supportBundleStatusHandler,
+		},
+		route.Route{
+			Name:        "SupportBundleDownload",
+			Description: "Download a support bundle generated on this peer",
+			Method:      "GET",
+			Pattern:     "/support-bundle/{id}/archive",
+			Version:     1,
+			Permission:  route.PermAdmin,
+			HandlerFunc: supportBundleDownloadHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// the Glusterd Transaction framework
+func (c *Command) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnCollectNodeData, "support-bundle.CollectNodeData")
+}
@@ -0,0 +1,207 @@
+package supportbundlecommands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+
+	"github.com/gluster/glusterd2/glusterd2/asyncjob"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+// bundleJobOp is the op of the async jobs generating support bundles
+const bundleJobOp = "support-bundle"
+
+var errBundleNotFound = errors.New("support bundle not found")
+
+// bundleNodes returns the peers from which data is to be collected
+func bundleNodes(req *api.SupportBundleReq) ([]uuid.UUID, error) {
+	if len(req.Peers) != 0 && req.PeerSelector != "" {
+		return nil, errors.New("peers and peer selector can't be used together")
+	}
+
+	if len(req.Peers) == 0 {
+		peers, err := peer.GetPeersBySelector(req.PeerSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		var nodes []uuid.UUID
+		for _, p := range peers {
+			if _, alive := store.Store.IsNodeAlive(p.ID); alive {
+				nodes = append(nodes, p.ID)
+			}
+		}
+		if len(nodes) == 0 && req.PeerSelector != "" {
+			return nil, fmt.Errorf("no online peers match the selector %s", req.PeerSelector)
+		}
+		return nodes, nil
+	}
+
+	var nodes []uuid.UUID
+	for _, p := range req.Peers {
+		id := uuid.Parse(p)
+		if id == nil {
+			return nil, fmt.Errorf("invalid peer ID: %s", p)
+		}
+		if !peer.Exists(p) {
+			return nil, fmt.Errorf("peer %s not found", p)
+		}
+		nodes = append(nodes, id)
+	}
+	return nodes, nil
+}
+
+func supportBundleCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.SupportBundleReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if req.LogSize == 0 {
+		req.LogSize = defaultLogSize
+	}
+	if req.LogSize < 0 || req.LogSize > maxLogSize {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+			fmt.Sprintf("log size must be between 1 and %d bytes", maxLogSize))
+		return
+	}
+
+	nodes, err := bundleNodes(&req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	restutils.SendAsyncJob(ctx, w, bundleJobOp, "", func(ctx context.Context) (interface{}, error) {
+		return generateBundle(ctx, &req, nodes)
+	}, nil)
+}
+
+// bundleFromJob returns the support bundle generated by job
+func bundleFromJob(job *api.AsyncJob) (api.SupportBundleResp, error) {
+	var b api.SupportBundleResp
+	if job.Result != nil {
+		if err := json.Unmarshal(job.Result, &b); err != nil {
+			return b, err
+		}
+	}
+
+	b.ID = job.ID.String()
+	b.State = job.State
+	b.Error = job.Error
+	b.StartTime = job.StartedAt
+	b.EndTime = job.FinishedAt
+	return b, nil
+}
+
+// getBundle returns the support bundle with the given ID, and its job
+func getBundle(id string) (api.SupportBundleResp, *api.AsyncJob, error) {
+	if uuid.Parse(id) == nil {
+		return api.SupportBundleResp{}, nil, errBundleNotFound
+	}
+
+	job, err := asyncjob.Get(id)
+	if err == gderrors.ErrAsyncJobNotFound || (err == nil && job.Op != bundleJobOp) {
+		return api.SupportBundleResp{}, nil, errBundleNotFound
+	}
+	if err != nil {
+		return api.SupportBundleResp{}, nil, err
+	}
+
+	b, err := bundleFromJob(job)
+	return b, job, err
+}
+
+func supportBundleListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	jobs, err := asyncjob.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.SupportBundleListResp, 0)
+	for _, job := range jobs {
+		if job.Op != bundleJobOp {
+			continue
+		}
+		b, err := bundleFromJob(job)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		resp = append(resp, b)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func supportBundleStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	b, _, err := getBundle(mux.Vars(r)["id"])
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == errBundleNotFound {
+			status = http.StatusNotFound
+		}
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, b)
+}
+
+func supportBundleDownloadHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	b, job, err := getBundle(mux.Vars(r)["id"])
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == errBundleNotFound {
+			status = http.StatusNotFound
+		}
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if b.State != api.AsyncJobSucceeded {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict,
+			fmt.Sprintf("support bundle is not complete (state: %s)", b.State))
+		return
+	}
+	// The archive is only on the peer which generated the bundle
+	if !uuid.Equal(job.Originator, gdctx.MyUUID) {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict,
+			fmt.Sprintf("support bundle was generated on peer %s", job.Originator))
+		return
+	}
+
+	f, err := os.Open(b.Path)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(b.Path)))
+	http.ServeContent(w, r, path.Base(b.Path), b.EndTime, f)
+}
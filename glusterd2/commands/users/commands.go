@@ -0,0 +1,62 @@
+// Package userscommands implements the REST endpoints to manage the users of
+// the REST API and their roles
+package userscommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "UserAdd",
+			Description:  "Add or update a user of the REST API",
+			Method:       "POST",
+			Pattern:      "/users",
+			Version:      1,
+			RequestBody:  (*api.User)(nil),
+			ResponseType: utils.GetTypeString((*api.User)(nil)),
+			HandlerFunc:  userAddHandler,
+		},
+		route.Route{
+			Name:         "UserList",
+			Description:  "List the users of the REST API",
+			Method:       "GET",
+			Pattern:      "/users",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.UserListResp)(nil)),
+			Permission:   route.PermAdmin,
+			HandlerFunc:  userListHandler,
+		},
+		route.Route{
+			Name:         "UserGet",
+			Description:  "Get a user of the REST API",
+			Method:       "GET",
+			Pattern:      "/users/{username}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.User)(nil)),
+			Permission:   route.PermAdmin,
+			HandlerFunc:  userGetHandler,
+		},
+		route.Route{
+			Name:        "UserDelete",
+			Description: "Delete a user of the REST API",
+			Method:      "DELETE",
+			Pattern:     "/users/{username}",
+			Version:     1,
+			HandlerFunc: userDeleteHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,76 @@
+package userscommands
+
+import (
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/user"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+)
+
+// redact removes the secret of the user, which is never sent in responses
+func redact(u api.User) api.User {
+	u.Secret = ""
+	return u
+}
+
+func userAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := restutils.RequestBody(ctx).(*api.User)
+
+	if err := user.Validate(req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := user.Add(req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, redact(*req))
+}
+
+func userListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	users, err := user.List()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := api.UserListResp{}
+	for _, u := range users {
+		resp = append(resp, redact(*u))
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func userGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	u, err := user.Get(mux.Vars(r)["username"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, redact(*u))
+}
+
+func userDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := user.Delete(mux.Vars(r)["username"]); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
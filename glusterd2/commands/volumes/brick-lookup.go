@@ -0,0 +1,60 @@
+package volumecommands
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+)
+
+// brickLookupHandler looks up the brick at a path on a peer, or the auto
+// provisioned brick on an LV, returning the volume it is of and its position
+// in the volume
+func brickLookupHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	var peerID uuid.UUID
+	if id := query.Get("peer-id"); id != "" {
+		if peerID = uuid.Parse(id); peerID == nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid peer id passed")
+			return
+		}
+	}
+
+	var (
+		pos *volume.BrickPosition
+		err error
+	)
+	switch {
+	case query.Get("path") != "":
+		if peerID == nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.New("peer-id is required to look up a brick path"))
+			return
+		}
+		pos, err = volume.FindBrickByPath(peerID, query.Get("path"))
+	case query.Get("lv") != "":
+		pos, err = volume.FindBrickByLv(peerID, query.Get("vg"), query.Get("lv"))
+	default:
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.New("either path or lv must be given"))
+		return
+	}
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := &api.BrickLookupResp{
+		BrickInfo:   brick.CreateBrickInfo(&pos.Brick),
+		SubvolIndex: pos.Subvol,
+		BrickIndex:  pos.Index,
+		DevicePath:  pos.Brick.DevicePath,
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -16,6 +16,15 @@ func prepareBricks(c transaction.TxnCtx) error {
 	return err
 }
 
+func undoPrepareBricks(c transaction.TxnCtx) error {
+	var req api.BrickReq
+	if err := c.Get("newBrick", &req); err != nil {
+		return err
+	}
+	UndoPrepareBrick(req, c)
+	return nil
+}
+
 func replaceVolinfo(c transaction.TxnCtx) error {
 	var newBrick api.BrickReq
 	var err error
@@ -67,15 +76,8 @@ func replaceVolinfo(c transaction.TxnCtx) error {
 		return err
 	}
 
-	allBricks, err := volume.GetAllBricksInCluster()
-	if err != nil {
-		return err
-	}
-
-	// Used by other peers to check if proposed bricks are already in use.
-	// This check is however still prone to races. See issue #314
-	if err = c.Set("all-bricks-in-cluster", allBricks); err != nil {
-		return err
+	if err = volume.CheckBrickPathConflicts(newBrickInfos); err != nil {
+		return transaction.NewValidationError(err)
 	}
 
 	// Setting volume Info in transaction context
@@ -1,6 +1,8 @@
 package volumecommands
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
@@ -23,17 +25,19 @@ func registerReplaceBrickStepFuncs() {
 		sf   transaction.StepFunc
 	}{
 		{"brick-replace.PrepareBricks", prepareBricks},
+		{"brick-replace.UndoPrepareBricks", undoPrepareBricks},
 		{"brick-replace.ReplaceVolinfo", replaceVolinfo},
 		{"brick-replace.StartBrick", startBrick},
 	}
 	for _, sf := range sfs {
 		transaction.RegisterStepFunc(sf.sf, sf.name)
 	}
+	transaction.RegisterUndoFunc("brick-replace.PrepareBricks", "brick-replace.UndoPrepareBricks")
 }
 
 func replaceBrickHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	volname := mux.Vars(r)["volname"]
 
 	if !volume.IsValidName(volname) {
@@ -58,17 +62,32 @@ func replaceBrickHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get Volume Info
-	vol, err := volume.GetVolume(volname)
+	vol, err := ReplaceBrick(ctx, volname, req)
 	if err != nil {
+		logger.WithError(err).WithField("volume-name", volname).Error("replace brick transaction failed")
 		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+	resp := createReplaceBrickResp(vol)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// ReplaceBrick replaces the source brick of the volume given in the request
+// with a new brick chosen by the bricks planner, in the same position in the
+// volume. The new brick is populated by self-heal. The updated volume is
+// returned.
+func ReplaceBrick(ctx context.Context, volname string, req api.ReplaceBrickReq) (*volume.Volinfo, error) {
+	// Get Volume Info
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		return nil, err
+	}
 
 	subVols := vol.Subvols
 
 	var srcBrickInfo brick.Brickinfo
+	found := false
 	subVolIndex := 0
 	brickIndex := 0
 LOOP:
@@ -79,10 +98,14 @@ LOOP:
 				subVolIndex = index
 				brickIndex = i
 				srcBrickInfo = brick
+				found = true
 				break LOOP
 			}
 		}
 	}
+	if !found {
+		return nil, gderrors.ErrBrickNotFound
+	}
 
 	excludeZones := make([]string, 0)
 	for svIndex, sv := range subVols {
@@ -94,10 +117,9 @@ LOOP:
 		for _, b := range sv.Bricks {
 			p, err := peer.GetPeer(b.PeerID.String())
 			if err != nil {
-				restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-				return
+				return nil, err
 			}
-			excludeZones = append(excludeZones, p.Metadata["_zone"])
+			excludeZones = append(excludeZones, p.Zone())
 		}
 	}
 	req.ExcludeZones = append(req.ExcludeZones, excludeZones...)
@@ -110,29 +132,26 @@ LOOP:
 		LimitZones:   req.LimitZones,
 		ExcludePeers: req.ExcludePeers,
 		ExcludeZones: req.ExcludeZones,
+		PeerSelector: req.PeerSelector,
 	}
 	availableVgs, err := bricksplanner.GetAvailableVgs(&volreq)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 	// TODO: check for available vgs in zones already being used in volume.
 	if len(availableVgs) == 0 {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "No volume groups are available")
-		return
+		return nil, errors.New("No volume groups are available")
 	}
 
 	mtabEntries, err := volume.GetMounts()
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
 	// Get source brick information like size etc
 	brickInfo, err := volume.BrickStatus(srcBrickInfo, mtabEntries)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
 	// Get new brick from the available vgs
@@ -140,16 +159,13 @@ LOOP:
 
 	peerID := uuid.Parse(newBrick.PeerID)
 	if peerID == nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "peer id of new brick could not be parsed")
-		return
+		return nil, errors.New("peer id of new brick could not be parsed")
 	}
 	allPeerIDs := vol.Nodes()
 	nodes := []uuid.UUID{peerID}
 	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, err
 	}
 	defer txn.Done()
 
@@ -185,36 +201,25 @@ LOOP:
 	}
 
 	if err = txn.Ctx.Set("newBrick", &newBrick); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 	if err = txn.Ctx.Set("srcBrickInfo", &srcBrickInfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 	if err = txn.Ctx.Set("subVolIndex", &subVolIndex); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 	if err = txn.Ctx.Set("brickIndex", &brickIndex); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 	if err = txn.Ctx.Set("volinfo", &vol); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
 	if err = txn.Do(); err != nil {
-		logger.WithError(err).WithField("volume-name", volname).Error("replace brick transaction failed")
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, err
 	}
-	resp := createReplaceBrickResp(vol)
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
-
-	return
+	return vol, nil
 }
 
 // Replace brick resp
@@ -0,0 +1,76 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func brickWipeJobsListHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	jobs, err := volume.GetBrickWipeJobs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if volname := r.URL.Query().Get("volume"); volname != "" {
+		filtered := make([]api.BrickWipeJob, 0, len(jobs))
+		for _, j := range jobs {
+			if j.VolumeName == volname {
+				filtered = append(filtered, j)
+			}
+		}
+		jobs = filtered
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.BrickWipeJobsResp(jobs))
+}
+
+func brickWipeJobGetHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	job, err := volume.GetBrickWipeJob(id)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, job)
+}
+
+// brickWipeJobDeleteHandler removes a finished brick wipe job
+func brickWipeJobDeleteHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	job, err := volume.GetBrickWipeJob(id)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if job.State == api.BrickWipePending || job.State == api.BrickWipeRunning {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, gderrors.ErrBrickWipeJobRunning)
+		return
+	}
+
+	if err := volume.DeleteBrickWipeJob(job); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
@@ -1,6 +1,7 @@
 package volumecommands
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
@@ -47,7 +48,7 @@ func bricksStatus(ctx transaction.TxnCtx) error {
 func volumeBricksStatusHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	volname := mux.Vars(r)["volname"]
 	vol, err := volume.GetVolume(volname)
@@ -57,6 +58,19 @@ func volumeBricksStatusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	result, err := getBricksStatus(ctx, vol)
+	if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("Failed to get volume status")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, result)
+}
+
+// getBricksStatus collects the status of the bricks of the volume from the
+// nodes hosting them
+func getBricksStatus(ctx context.Context, vol *volume.Volinfo) (*api.BricksStatusResp, error) {
 	txn := transaction.NewTxn(ctx)
 	defer txn.Done()
 	txn.Steps = []*transaction.Step{
@@ -65,28 +79,19 @@ func volumeBricksStatusHandler(w http.ResponseWriter, r *http.Request) {
 			Nodes:  vol.Nodes(),
 		},
 	}
-	txn.Ctx.Set("volname", volname)
+	if err := txn.Ctx.Set("volname", vol.Name); err != nil {
+		return nil, err
+	}
 
 	// Some nodes may not be up, which is okay.
 	txn.DontCheckAlive = true
 	txn.DisableRollback = true
 
-	err = txn.Do()
-	if err != nil {
-		logger.WithError(err).WithField("volume", volname).Error("Failed to get volume status")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+	if err := txn.Do(); err != nil {
+		return nil, err
 	}
 
-	result, err := createBricksStatusResp(txn.Ctx, vol)
-	if err != nil {
-		errMsg := "Failed to aggregate brick status results from multiple nodes."
-		logger.WithError(err).Error("volumeStatusHandler:" + errMsg)
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, errMsg)
-		return
-	}
-
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, result)
+	return createBricksStatusResp(txn.Ctx, vol)
 }
 
 func createBricksStatusResp(ctx transaction.TxnCtx, vol *volume.Volinfo) (*api.BricksStatusResp, error) {
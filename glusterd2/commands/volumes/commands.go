@@ -21,7 +21,15 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			RequestType:  utils.GetTypeString((*api.VolCreateReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeCreateResp)(nil)),
+			Heavy:        true,
 			HandlerFunc:  volumeCreateHandler},
+		route.Route{
+			Name:         "VolumeRestoreFromBricks",
+			Method:       "POST",
+			Pattern:      "/volumes/restore-from-bricks",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.RestoreFromBricksResp)(nil)),
+			HandlerFunc:  restoreFromBricksHandler},
 		route.Route{
 			Name:         "VolumeExpand",
 			Method:       "POST",
@@ -30,6 +38,179 @@ func (c *Command) Routes() route.Routes {
 			RequestType:  utils.GetTypeString((*api.VolExpandReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeExpandResp)(nil)),
 			HandlerFunc:  volumeExpandHandler},
+		route.Route{
+			Name:         "VolumeRefreshSize",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/refresh-size",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeRefreshSizeResp)(nil)),
+			HandlerFunc:  volumeRefreshSizeHandler},
+		route.Route{
+			Name:         "VolumeAutoExpandSet",
+			Method:       "PUT",
+			Pattern:      "/volumes/{volname}/autoexpand",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolAutoExpandReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolAutoExpandResp)(nil)),
+			HandlerFunc:  volumeAutoExpandSetHandler},
+		route.Route{
+			Name:         "VolumeAutoExpandGet",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/autoexpand",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolAutoExpandResp)(nil)),
+			HandlerFunc:  volumeAutoExpandGetHandler},
+		route.Route{
+			Name:        "VolumeAutoExpandDelete",
+			Method:      "DELETE",
+			Pattern:     "/volumes/{volname}/autoexpand",
+			Version:     1,
+			HandlerFunc: volumeAutoExpandDeleteHandler},
+		route.Route{
+			Name:         "VolumeUsageProtectSet",
+			Method:       "PUT",
+			Pattern:      "/volumes/{volname}/usage-protect",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolUsageProtectReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolUsageProtectResp)(nil)),
+			HandlerFunc:  volumeUsageProtectSetHandler},
+		route.Route{
+			Name:         "VolumeUsageProtectGet",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/usage-protect",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolUsageProtectResp)(nil)),
+			HandlerFunc:  volumeUsageProtectGetHandler},
+		route.Route{
+			Name:        "VolumeUsageProtectDelete",
+			Method:      "DELETE",
+			Pattern:     "/volumes/{volname}/usage-protect",
+			Version:     1,
+			HandlerFunc: volumeUsageProtectDeleteHandler},
+		route.Route{
+			Name:         "VolumeIOThrottleSet",
+			Method:       "PUT",
+			Pattern:      "/volumes/{volname}/io-throttle",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolIOThrottleReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolIOThrottleResp)(nil)),
+			HandlerFunc:  volumeIOThrottleSetHandler},
+		route.Route{
+			Name:         "VolumeIOThrottleGet",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/io-throttle",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolIOThrottleResp)(nil)),
+			HandlerFunc:  volumeIOThrottleGetHandler},
+		route.Route{
+			Name:        "VolumeIOThrottleDelete",
+			Method:      "DELETE",
+			Pattern:     "/volumes/{volname}/io-throttle",
+			Version:     1,
+			HandlerFunc: volumeIOThrottleDeleteHandler},
+		route.Route{
+			Name:         "VolumeAdvisoryLockAcquire",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/locks",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolAdvisoryLockReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolAdvisoryLock)(nil)),
+			HandlerFunc:  volumeAdvisoryLockAcquireHandler},
+		route.Route{
+			Name:         "VolumeAdvisoryLockList",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/locks",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolAdvisoryLockListResp)(nil)),
+			HandlerFunc:  volumeAdvisoryLockListHandler},
+		route.Route{
+			Name:        "VolumeAdvisoryLockRelease",
+			Method:      "DELETE",
+			Pattern:     "/volumes/{volname}/locks/{lockname}",
+			Version:     1,
+			HandlerFunc: volumeAdvisoryLockReleaseHandler},
+		route.Route{
+			Name:         "VolumeACLSet",
+			Method:       "PUT",
+			Pattern:      "/volumes/{volname}/acl",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolACLReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolACLResp)(nil)),
+			HandlerFunc:  volumeACLSetHandler},
+		route.Route{
+			Name:         "VolumeACLGet",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/acl",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolACLResp)(nil)),
+			HandlerFunc:  volumeACLGetHandler},
+		route.Route{
+			Name:         "VolumeACLReset",
+			Method:       "DELETE",
+			Pattern:      "/volumes/{volname}/acl",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolACLResp)(nil)),
+			HandlerFunc:  volumeACLResetHandler},
+		route.Route{
+			Name:         "VolumeSubdirExport",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/subdirs",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.SubdirExportReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.SubdirExportResp)(nil)),
+			HandlerFunc:  volumeSubdirExportHandler},
+		route.Route{
+			Name:         "VolumeSubdirList",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/subdirs",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.SubdirExportListResp)(nil)),
+			HandlerFunc:  volumeSubdirListHandler},
+		route.Route{
+			Name:        "VolumeSubdirUnexport",
+			Method:      "DELETE",
+			Pattern:     "/volumes/{volname}/subdirs/{subdir:.*}",
+			Version:     1,
+			HandlerFunc: volumeSubdirUnexportHandler},
+		route.Route{
+			Name:         "VolumeMetrics",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/metrics",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolMetricsResp)(nil)),
+			HandlerFunc:  volumeMetricsHandler},
+		route.Route{
+			Name:         "VolumeOptionsHistory",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/options/history",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolOptionsHistoryResp)(nil)),
+			HandlerFunc:  volumeOptionsHistoryHandler},
+		route.Route{
+			Name:         "VolumeDiff",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/diff",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolDiffResp)(nil)),
+			HandlerFunc:  volumeDiffHandler},
+		route.Route{
+			Name:         "VolumeOptionsRollback",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/options/rollback",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolOptionsRollbackReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeOptionResp)(nil)),
+			HandlerFunc:  volumeOptionsRollbackHandler},
+		route.Route{
+			Name:         "VolumeOptionsImpact",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/options/impact",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolOptionReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.OptionImpactResp)(nil)),
+			HandlerFunc:  volumeOptionsImpactHandler,
+			// Dry run, nothing is changed
+			AllowReadOnly: true},
 		route.Route{
 			Name:         "VolumeOptionGet",
 			Method:       "GET",
@@ -60,6 +241,13 @@ func (c *Command) Routes() route.Routes {
 			RequestType:  utils.GetTypeString((*api.VolOptionResetReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeOptionResp)(nil)),
 			HandlerFunc:  volumeResetHandler},
+		route.Route{
+			Name:         "VolumeOptionsSchema",
+			Method:       "GET",
+			Pattern:      "/volumes/options",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.OptionDocListResp)(nil)),
+			HandlerFunc:  volumeOptionsSchemaHandler},
 		route.Route{
 			Name:         "OptionGroupList",
 			Method:       "GET",
@@ -81,11 +269,67 @@ func (c *Command) Routes() route.Routes {
 			Version:     1,
 			HandlerFunc: optionGroupDeleteHandler},
 		route.Route{
-			Name:        "VolumeDelete",
+			Name:         "VolumeDelete",
+			Method:       "DELETE",
+			Pattern:      "/volumes/{volname}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.BrickWipeJobsResp)(nil)),
+			HandlerFunc:  volumeDeleteHandler},
+		route.Route{
+			Name:         "BrickWipeJobsList",
+			Method:       "GET",
+			Pattern:      "/brick-wipe-jobs",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.BrickWipeJobsResp)(nil)),
+			HandlerFunc:  brickWipeJobsListHandler},
+		route.Route{
+			Name:         "BrickWipeJobGet",
+			Method:       "GET",
+			Pattern:      "/brick-wipe-jobs/{id}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.BrickWipeJob)(nil)),
+			HandlerFunc:  brickWipeJobGetHandler},
+		route.Route{
+			Name:        "BrickWipeJobDelete",
 			Method:      "DELETE",
-			Pattern:     "/volumes/{volname}",
+			Pattern:     "/brick-wipe-jobs/{id}",
 			Version:     1,
-			HandlerFunc: volumeDeleteHandler},
+			HandlerFunc: brickWipeJobDeleteHandler},
+		route.Route{
+			Name:         "VolumeProfileCreate",
+			Method:       "POST",
+			Pattern:      "/volume-profiles",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeProfile)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeProfile)(nil)),
+			HandlerFunc:  volumeProfileCreateHandler},
+		route.Route{
+			Name:         "VolumeProfileList",
+			Method:       "GET",
+			Pattern:      "/volume-profiles",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeProfileListResp)(nil)),
+			HandlerFunc:  volumeProfileListHandler},
+		route.Route{
+			Name:         "VolumeProfileGet",
+			Method:       "GET",
+			Pattern:      "/volume-profiles/{profilename}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeProfile)(nil)),
+			HandlerFunc:  volumeProfileGetHandler},
+		route.Route{
+			Name:        "VolumeProfileDelete",
+			Method:      "DELETE",
+			Pattern:     "/volume-profiles/{profilename}",
+			Version:     1,
+			HandlerFunc: volumeProfileDeleteHandler},
+		route.Route{
+			Name:         "BrickLookup",
+			Method:       "GET",
+			Pattern:      "/bricks/lookup",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.BrickLookupResp)(nil)),
+			HandlerFunc:  brickLookupHandler},
 		route.Route{
 			Name:         "VolumeInfo",
 			Method:       "GET",
@@ -114,6 +358,27 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			ResponseType: utils.GetTypeString((*api.VolumeListResp)(nil)),
 			HandlerFunc:  volumeListHandler},
+		route.Route{
+			Name:         "VolumeBulkDelete",
+			Method:       "DELETE",
+			Pattern:      "/volumes",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeBulkDeleteJob)(nil)),
+			HandlerFunc:  volumeBulkDeleteHandler},
+		route.Route{
+			Name:         "VolumeBulkDeleteJobList",
+			Method:       "GET",
+			Pattern:      "/volume-delete-jobs",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeBulkDeleteJobListResp)(nil)),
+			HandlerFunc:  volumeBulkDeleteJobListHandler},
+		route.Route{
+			Name:         "VolumeBulkDeleteJobGet",
+			Method:       "GET",
+			Pattern:      "/volume-delete-jobs/{id}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeBulkDeleteJob)(nil)),
+			HandlerFunc:  volumeBulkDeleteJobGetHandler},
 		route.Route{
 			Name:         "VolumeStart",
 			Method:       "POST",
@@ -121,6 +386,7 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			RequestType:  utils.GetTypeString((*api.VolumeStartReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeStartResp)(nil)),
+			Permission:   route.PermOperate,
 			HandlerFunc:  volumeStartHandler},
 		route.Route{
 			Name:         "VolumeStop",
@@ -128,6 +394,7 @@ func (c *Command) Routes() route.Routes {
 			Pattern:      "/volumes/{volname}/stop",
 			Version:      1,
 			ResponseType: utils.GetTypeString((*api.VolumeStopResp)(nil)),
+			Permission:   route.PermOperate,
 			HandlerFunc:  volumeStopHandler},
 		route.Route{
 			Name:        "Statedump",
@@ -152,6 +419,21 @@ func (c *Command) Routes() route.Routes {
 			RequestType:  utils.GetTypeString((*api.VolEditReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeEditResp)(nil)),
 			HandlerFunc:  volumeEditHandler},
+		route.Route{
+			Name:         "VolumeRename",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/rename",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolRenameReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeRenameResp)(nil)),
+			HandlerFunc:  volumeRenameHandler},
+		route.Route{
+			Name:         "VolumeRestore",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/restore",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeRestoreResp)(nil)),
+			HandlerFunc:  volumeRestoreHandler},
 		route.Route{
 			Name:         "ProfileVolume",
 			Method:       "GET",
@@ -165,14 +447,22 @@ func (c *Command) Routes() route.Routes {
 // RegisterStepFuncs implements a required function for the Command interface
 func (c *Command) RegisterStepFuncs() {
 	registerVolCreateStepFuncs()
+	registerVolRestoreFromBricksStepFuncs()
 	registerVolDeleteStepFuncs()
+	registerVolRenameStepFuncs()
 	registerVolStartStepFuncs()
 	registerVolStopStepFuncs()
 	registerBricksStatusStepFuncs()
 	registerVolExpandStepFuncs()
+	registerVolRefreshSizeStepFuncs()
 	registerVolOptionStepFuncs()
 	registerVolOptionResetStepFuncs()
+	registerVolIOThrottleStepFuncs()
 	registerVolStatedumpFuncs()
 	registerReplaceBrickStepFuncs()
 	registerVolProfileStepFuncs()
+	registerAutoExpandJob()
+	registerUsageProtectJob()
+	registerMetricsJob()
+	registerVolTrashJob()
 }
@@ -200,18 +200,11 @@ func validateBricks(c transaction.TxnCtx) error {
 		return err
 	}
 
-	var allBricks []brick.Brickinfo
-	if err = c.Get("all-bricks-in-cluster", &allBricks); err != nil {
+	allLocalBricks, err := volume.GetBricksByPeer(gdctx.MyUUID)
+	if err != nil {
 		return err
 	}
 
-	var allLocalBricks []brick.Brickinfo
-	for _, b := range allBricks {
-		if uuid.Equal(gdctx.MyUUID, b.PeerID) {
-			allLocalBricks = append(allLocalBricks, b)
-		}
-	}
-
 	for _, b := range bricks {
 		if !uuid.Equal(b.PeerID, gdctx.MyUUID) {
 			continue
@@ -297,30 +290,68 @@ func undoInitBricks(c transaction.TxnCtx) error {
 
 // StoreVolume uses to store the volinfo and to generate client volfile
 func storeVolume(c transaction.TxnCtx) error {
-	return storeVolInfo(c, "volinfo")
-}
-
-// storeVolInfo uses to store the volinfo based on key and to generate client volfile
-func storeVolInfo(c transaction.TxnCtx, key string) error {
 	var volinfo volume.Volinfo
-	if err := c.Get(key, &volinfo); err != nil {
+	if err := c.Get("volinfo", &volinfo); err != nil {
 		c.Logger().WithError(err).WithField(
 			"key", "volinfo").Debug("failed to get key from store")
 		return err
 	}
 
-	if err := volume.AddOrUpdateVolumeFunc(&volinfo); err != nil {
+	if err := storeVolInfo(c, &volinfo); err != nil {
+		return err
+	}
+
+	// Save the new revision of the volinfo for later steps, and for undoing
+	// the update
+	return c.Set("volinfo", &volinfo)
+}
+
+// storeVolInfo stores the volinfo. A new volinfo is added to the store. An
+// existing volinfo is only updated if it hasn't been modified after the
+// revision the volinfo was got at, so that concurrent updates aren't lost.
+func storeVolInfo(c transaction.TxnCtx, volinfo *volume.Volinfo) error {
+	if volinfo.ModRevision == 0 {
+		if err := volume.AddOrUpdateVolumeFunc(volinfo); err != nil {
+			c.Logger().WithError(err).WithField(
+				"volume", volinfo.Name).Debug("failed to store volume info")
+			return err
+		}
+		return nil
+	}
+
+	newVolinfo := *volinfo
+	updated, err := volume.UpdateVolume(volinfo.Name, volinfo.ModRevision, func(v *volume.Volinfo) error {
+		*v = newVolinfo
+		return nil
+	})
+	if err != nil {
 		c.Logger().WithError(err).WithField(
 			"volume", volinfo.Name).Debug("failed to store volume info")
 		return err
 	}
+	*volinfo = *updated
 
 	return nil
 }
 
 // undoStoreVolume revert back volinfo and to generate client volfile
 func undoStoreVolume(c transaction.TxnCtx) error {
-	return storeVolInfo(c, "oldvolinfo")
+	var volinfo, oldvolinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		c.Logger().WithError(err).WithField(
+			"key", "volinfo").Debug("failed to get key from store")
+		return err
+	}
+	if err := c.Get("oldvolinfo", &oldvolinfo); err != nil {
+		c.Logger().WithError(err).WithField(
+			"key", "oldvolinfo").Debug("failed to get key from store")
+		return err
+	}
+
+	// The old volinfo is only restored over the volinfo stored by
+	// storeVolume, and not over a concurrent update
+	oldvolinfo.ModRevision = volinfo.ModRevision
+	return storeVolInfo(c, &oldvolinfo)
 }
 
 func loadDefaultGroupOptions() error {
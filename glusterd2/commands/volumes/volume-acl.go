@@ -0,0 +1,147 @@
+package volumecommands
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+func createVolumeACLResp(acl *volume.VolACL) *api.VolACLResp {
+	return &api.VolACLResp{
+		Allow:    acl.Allow,
+		Reject:   acl.Reject,
+		SSLAllow: acl.SSLAllow,
+	}
+}
+
+func volumeACLGetHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createVolumeACLResp(&volinfo.ACL))
+}
+
+func volumeACLSetHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	var req api.VolACLReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	acl := volume.VolACL{
+		Allow:    req.Allow,
+		Reject:   req.Reject,
+		SSLAllow: req.SSLAllow,
+	}
+	if err := acl.Validate(); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	updateVolumeACL(w, r, acl)
+}
+
+func volumeACLResetHandler(w http.ResponseWriter, r *http.Request) {
+	updateVolumeACL(w, r, volume.VolACL{})
+}
+
+func updateVolumeACL(w http.ResponseWriter, r *http.Request, acl volume.VolACL) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := updateServerAuth(ctx, volname, func(v *volume.Volinfo) error {
+		v.ACL = acl
+		return nil
+	})
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	gdctx.Logger(ctx).Info("volume ACL updated")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createVolumeACLResp(&volinfo.ACL))
+}
+
+// updateServerAuth applies modify to the volinfo and saves it. The client
+// authentication options of the running bricks are updated by regenerating
+// the brick volfiles.
+func updateServerAuth(ctx context.Context, volname string, modify func(*volume.Volinfo) error) (*volume.Volinfo, error) {
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return nil, err
+	}
+
+	//save volume information for transaction failure scenario
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		return nil, err
+	}
+
+	if err := modify(volinfo); err != nil {
+		return nil, err
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return nil, err
+	}
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+		},
+		{
+			DoFunc:   "vol-option.GenerateBrickVolfiles",
+			UndoFunc: "vol-option.GenerateBrickvolfiles.Undo",
+			Nodes:    volinfo.Nodes(),
+		},
+		{
+			// Running bricks fetch the regenerated volfiles and
+			// apply the new auth options immediately
+			DoFunc: "vol-option.NotifyVolfileChange",
+			Nodes:  allNodes,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		txn.Ctx.Logger().WithError(err).Error("failed to update client auth of volume")
+		return nil, err
+	}
+
+	return volinfo, nil
+}
@@ -0,0 +1,120 @@
+package volumecommands
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	minAdvisoryLockTTL = 5
+	maxAdvisoryLockTTL = 24 * 60 * 60
+)
+
+func validateVolAdvisoryLockReq(req *api.VolAdvisoryLockReq) error {
+	// Lock names are used in store keys and URLs, same as volume names
+	if !volume.IsValidName(req.Name) {
+		return errors.New("invalid lock name")
+	}
+
+	if req.Holder == "" {
+		return errors.New("lock holder is required")
+	}
+
+	if req.TTL < minAdvisoryLockTTL || req.TTL > maxAdvisoryLockTTL {
+		return errors.New("ttl must be between 5 seconds and a day")
+	}
+
+	return nil
+}
+
+// sendAdvisoryLockError sends Conflict along with the lock holder if the
+// error is due to an advisory lock held on the volume
+func sendAdvisoryLockError(w http.ResponseWriter, r *http.Request, err error) {
+	ctx := r.Context()
+	if _, ok := err.(*volume.AdvisoryLockHeldError); ok {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+		return
+	}
+	status, err := restutils.ErrToStatusCode(err)
+	restutils.SendHTTPError(ctx, w, status, err)
+}
+
+func volumeAdvisoryLockAcquireHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolAdvisoryLockReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := validateVolAdvisoryLockReq(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := volume.GetVolume(volname); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	lock, err := volume.AcquireAdvisoryLock(volname, req.Name, req.Holder, time.Duration(req.TTL)*time.Second)
+	if err != nil {
+		sendAdvisoryLockError(w, r, err)
+		return
+	}
+
+	logger.WithField("volume", volname).WithField("lock", req.Name).
+		WithField("holder", req.Holder).Info("volume advisory lock taken")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, lock)
+}
+
+func volumeAdvisoryLockListHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	if _, err := volume.GetVolume(volname); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	locks, err := volume.GetAdvisoryLocks(volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.VolAdvisoryLockListResp(locks))
+}
+
+func volumeAdvisoryLockReleaseHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+	volname := mux.Vars(r)["volname"]
+	lockname := mux.Vars(r)["lockname"]
+	holder := r.URL.Query().Get("holder")
+
+	if err := volume.ReleaseAdvisoryLock(volname, lockname, holder); err != nil {
+		sendAdvisoryLockError(w, r, err)
+		return
+	}
+
+	logger.WithField("volume", volname).WithField("lock", lockname).Info("volume advisory lock released")
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,286 @@
+package volumecommands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/ioops"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/plugins/rebalance"
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	autoExpandJobName     = "volume.autoexpand"
+	autoExpandJobSchedule = "@every 5m"
+)
+
+var errMaxSizeReached = errors.New("volume has reached the maximum size allowed by the auto expansion policy")
+
+func registerAutoExpandJob() {
+	err := scheduler.Register(&scheduler.Job{
+		Name:        autoExpandJobName,
+		Description: "Records volume usage and expands volumes crossing the threshold of their auto expansion policy",
+		Schedule:    autoExpandJobSchedule,
+		Enabled:     true,
+		Func:        autoExpandVolumes,
+	})
+	if err != nil {
+		log.WithError(err).WithField("job", autoExpandJobName).Error("failed to register scheduled job")
+	}
+}
+
+func validateVolAutoExpandReq(req *api.VolAutoExpandReq, volinfo *volume.Volinfo) error {
+	if req.Threshold <= 0 || req.Threshold > 100 {
+		return errors.New("threshold must be a percentage between 1 and 100")
+	}
+
+	if req.Increment < minVolumeSize {
+		return errors.New("invalid increment size, minimum size required is " + strconv.Itoa(minVolumeSize))
+	}
+
+	if req.MaxSize != 0 && req.MaxSize < volinfo.Capacity {
+		return errors.New("maximum size is less than the current size of the volume")
+	}
+
+	if volinfo.GetProvisionType() != brick.AutoProvisioned {
+		return errors.New("auto expansion is supported only for volumes with auto provisioned bricks")
+	}
+
+	return nil
+}
+
+// autoExpansionSize returns the size by which the volume is to be expanded as
+// per the policy, based on the latest usage sample recorded. A size of 0 is
+// returned if the volume need not be expanded.
+func autoExpansionSize(policy *api.VolAutoExpandResp, capacity uint64) (uint64, error) {
+	if len(policy.Samples) == 0 {
+		return 0, nil
+	}
+
+	sample := policy.Samples[len(policy.Samples)-1]
+	if sample.Capacity == 0 || sample.Used*100 < uint64(policy.Threshold)*sample.Capacity {
+		return 0, nil
+	}
+
+	size := policy.Increment
+	if policy.MaxSize != 0 {
+		if capacity >= policy.MaxSize {
+			return 0, errMaxSizeReached
+		}
+		if capacity+size > policy.MaxSize {
+			size = policy.MaxSize - capacity
+		}
+	}
+
+	return size, nil
+}
+
+func autoExpandVolumes(ctx context.Context) error {
+	policies, err := volume.GetAutoExpandPolicies()
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for volname, policy := range policies {
+		if err := autoExpandVolume(ctx, volname, policy); err != nil {
+			log.WithError(err).WithField("volume", volname).Warn("volume auto expansion failed")
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		return errors.New("auto expansion failed for " + strconv.Itoa(failed) + " volume(s)")
+	}
+	return nil
+}
+
+func autoExpandVolume(ctx context.Context, volname string, policy *api.VolAutoExpandResp) error {
+	ctx = gdctx.WithVolName(gdctx.WithReqID(ctx, uuid.NewRandom()), volname)
+	logger := gdctx.Logger(ctx)
+	ctx = gdctx.WithReqLogger(ctx, logger)
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return err
+	}
+
+	if volinfo.State != volume.VolStarted {
+		return nil
+	}
+
+	usage, err := volume.UsageInfo(volname)
+	if err != nil {
+		return err
+	}
+
+	volume.AddUsageSample(policy, api.VolUsageSample{
+		Time:     time.Now(),
+		Capacity: usage.Capacity,
+		Used:     usage.Used,
+	})
+
+	size, err := autoExpansionSize(policy, volinfo.Capacity)
+	if err == nil && size != 0 {
+		logger.WithField("size", size).Info("volume usage crossed the auto expansion threshold, expanding volume")
+		req := api.VolExpandReq{
+			Size:            size,
+			DistributeCount: len(volinfo.Subvols),
+		}
+		var expanded *volume.Volinfo
+		if expanded, _, err = expandVolume(ctx, volname, req); err == nil {
+			volinfo = expanded
+		}
+	}
+
+	// Reaching the maximum size is reported only once
+	if err != nil && !(err == errMaxSizeReached && policy.LastError == err.Error()) {
+		e := volume.NewEvent(volume.EventVolumeAutoExpandFailed, volinfo)
+		e.Data["error"] = err.Error()
+		events.Broadcast(e)
+	}
+
+	if err == nil && size != 0 {
+		policy.Expansions++
+		policy.LastExpanded = time.Now()
+
+		e := volume.NewEvent(volume.EventVolumeAutoExpanded, volinfo)
+		e.Data["volume.size"] = strconv.FormatUint(volinfo.Capacity, 10)
+		e.Data["volume.expanded-by"] = strconv.FormatUint(size, 10)
+		events.Broadcast(e)
+
+		// The bricks are grown in place, fix the layout so that it
+		// reflects the new brick sizes
+		if volinfo.DistCount > 1 {
+			rebalReq := rebalanceapi.StartReq{Option: "fix-layout"}
+			_, rerr := rebalance.StartRebalance(ctx, volname, &rebalReq)
+			if rerr == ioops.ErrQueued {
+				logger.Info("rebalance after auto expansion queued behind conflicting operations")
+			} else if rerr != nil {
+				logger.WithError(rerr).Warn("failed to start rebalance after auto expansion")
+			}
+		}
+	}
+
+	if serr := saveAutoExpandState(volname, policy, err); serr != nil {
+		logger.WithError(serr).Warn("failed to save volume auto expansion state")
+	}
+
+	return err
+}
+
+// saveAutoExpandState saves the usage samples and the expansion results to
+// the policy in the store, preserving any change made to the policy since it
+// was read.
+func saveAutoExpandState(volname string, state *api.VolAutoExpandResp, expandErr error) error {
+	policy, err := volume.GetAutoExpandPolicy(volname)
+	if err != nil {
+		if err == gderrors.ErrAutoExpandPolicyNotFound {
+			// policy was deleted meanwhile
+			return nil
+		}
+		return err
+	}
+
+	policy.Samples = state.Samples
+	policy.Expansions = state.Expansions
+	policy.LastExpanded = state.LastExpanded
+	policy.LastError = ""
+	if expandErr != nil {
+		policy.LastError = expandErr.Error()
+	}
+
+	return volume.SetAutoExpandPolicy(volname, policy)
+}
+
+func volumeAutoExpandSetHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolAutoExpandReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := validateVolAutoExpandReq(&req, volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Retain the usage samples and results of the existing policy
+	policy, err := volume.GetAutoExpandPolicy(volname)
+	if err == gderrors.ErrAutoExpandPolicyNotFound {
+		policy = &api.VolAutoExpandResp{Samples: []api.VolUsageSample{}}
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	policy.VolAutoExpandReq = req
+
+	if err := volume.SetAutoExpandPolicy(volname, policy); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("volume", volname).Info("volume auto expansion policy set")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, policy)
+}
+
+func volumeAutoExpandGetHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	policy, err := volume.GetAutoExpandPolicy(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, policy)
+}
+
+func volumeAutoExpandDeleteHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	if _, err := volume.GetAutoExpandPolicy(volname); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := volume.DeleteAutoExpandPolicy(volname); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
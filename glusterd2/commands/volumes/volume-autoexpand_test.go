@@ -0,0 +1,51 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoExpansionSize(t *testing.T) {
+	policy := &api.VolAutoExpandResp{
+		VolAutoExpandReq: api.VolAutoExpandReq{
+			Threshold: 80,
+			Increment: 100,
+			MaxSize:   1150,
+		},
+	}
+
+	// No samples recorded yet
+	size, err := autoExpansionSize(policy, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), size)
+
+	// Below threshold
+	policy.Samples = []api.VolUsageSample{{Capacity: 1000, Used: 799}}
+	size, err = autoExpansionSize(policy, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), size)
+
+	// Threshold crossed
+	policy.Samples = append(policy.Samples, api.VolUsageSample{Capacity: 1000, Used: 800})
+	size, err = autoExpansionSize(policy, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), size)
+
+	// Expansion limited by the maximum size
+	size, err = autoExpansionSize(policy, 1100)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(50), size)
+
+	size, err = autoExpansionSize(policy, 1150)
+	assert.Equal(t, errMaxSizeReached, err)
+	assert.Equal(t, uint64(0), size)
+
+	// No maximum size
+	policy.MaxSize = 0
+	size, err = autoExpansionSize(policy, 5000)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), size)
+}
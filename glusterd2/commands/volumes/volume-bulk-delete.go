@@ -0,0 +1,284 @@
+package volumecommands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultBulkDeleteConcurrency = 4
+	maxBulkDeleteConcurrency     = 32
+	// Deleting a volume is retried when it fails on contention with other
+	// transactions, backing off for longer after every attempt
+	bulkDeleteAttempts = 3
+	bulkDeleteBackoff  = 2 * time.Second
+)
+
+var errBulkDeleteJobNotFound = errors.New("bulk volume delete job not found")
+
+var (
+	bulkDeleteJobsMu sync.Mutex
+	bulkDeleteJobs   = make(map[string]*api.VolumeBulkDeleteJob)
+)
+
+func getBulkDeleteJob(id string) (api.VolumeBulkDeleteJob, bool) {
+	bulkDeleteJobsMu.Lock()
+	defer bulkDeleteJobsMu.Unlock()
+
+	j, ok := bulkDeleteJobs[id]
+	if !ok {
+		return api.VolumeBulkDeleteJob{}, false
+	}
+	resp := *j
+	resp.Volumes = append([]api.VolumeDeleteResult(nil), j.Volumes...)
+	return resp, true
+}
+
+// updateBulkDeleteJob runs f on the job with the jobs locked
+func updateBulkDeleteJob(id string, f func(*api.VolumeBulkDeleteJob)) {
+	bulkDeleteJobsMu.Lock()
+	defer bulkDeleteJobsMu.Unlock()
+
+	if j, ok := bulkDeleteJobs[id]; ok {
+		f(j)
+	}
+}
+
+// bulkDeleteVolumes returns the names of the volumes to be deleted, given
+// either as a comma separated list of names, or as a selector matching the
+// metadata of the volumes. Selectors are of the same form as peer selectors.
+func bulkDeleteVolumes(ctx context.Context, names, selector string) ([]string, error) {
+	if names != "" && selector != "" {
+		return nil, errors.New("volumes and selector can't be used together")
+	}
+
+	if names != "" {
+		var volnames []string
+		seen := make(map[string]bool)
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			volnames = append(volnames, name)
+		}
+		return volnames, nil
+	}
+
+	// An empty selector would select all volumes
+	if strings.TrimSpace(selector) == "" {
+		return nil, errors.New("either volumes or a selector is required")
+	}
+	sel, err := peer.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var volnames []string
+	for _, v := range volumes {
+		if sel.Matches(v.Metadata) {
+			volnames = append(volnames, v.Name)
+		}
+	}
+	sort.Strings(volnames)
+	return volnames, nil
+}
+
+// volumeBulkDeleteHandler starts a job deleting the volumes selected by the
+// request, which is run in the background by this peer
+func volumeBulkDeleteHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+	query := r.URL.Query()
+
+	concurrency := defaultBulkDeleteConcurrency
+	if c := query.Get("concurrency"); c != "" {
+		n, err := strconv.Atoi(c)
+		if err != nil || n <= 0 || n > maxBulkDeleteConcurrency {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+				fmt.Sprintf("concurrency must be between 1 and %d", maxBulkDeleteConcurrency))
+			return
+		}
+		concurrency = n
+	}
+
+	selector := query.Get("selector")
+	volnames, err := bulkDeleteVolumes(ctx, query.Get("volumes"), selector)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	job := &api.VolumeBulkDeleteJob{
+		ID:          uuid.NewRandom().String(),
+		State:       api.VolumeDeleteRunning,
+		Selector:    selector,
+		Wipe:        query.Get("wipe") == "true",
+		Purge:       query.Get("purge") == "true",
+		Concurrency: concurrency,
+		Volumes:     make([]api.VolumeDeleteResult, 0, len(volnames)),
+		StartTime:   time.Now(),
+	}
+	for _, name := range volnames {
+		job.Volumes = append(job.Volumes, api.VolumeDeleteResult{
+			Name:  name,
+			State: api.VolumeDeletePending,
+		})
+	}
+
+	bulkDeleteJobsMu.Lock()
+	bulkDeleteJobs[job.ID] = job
+	bulkDeleteJobsMu.Unlock()
+	resp, _ := getBulkDeleteJob(job.ID)
+
+	logger.WithFields(log.Fields{
+		"job-id":  job.ID,
+		"volumes": len(volnames),
+	}).Info("starting bulk volume delete")
+
+	// The job outlives this request. Only the request ID and user are
+	// carried over for the transactions.
+	bgCtx := gdctx.WithReqID(context.Background(), gdctx.GetReqID(ctx))
+	bgCtx = gdctx.WithReqUser(bgCtx, gdctx.GetReqUser(ctx))
+	go runBulkDelete(bgCtx, job.ID, volnames, job.Wipe, job.Purge, concurrency)
+
+	w.Header().Set("Location", "/v1/volume-delete-jobs/"+job.ID)
+	restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, resp)
+}
+
+// retryVolumeDelete returns true if deleting a volume failed on contention
+// with other transactions, and can be retried
+func retryVolumeDelete(err error) bool {
+	return err == transaction.ErrLockTimeout || err == gderrors.ErrVolinfoConflict
+}
+
+// runBulkDelete deletes the volumes, running at most concurrency deletes at
+// a time. Failing to delete a volume doesn't stop the others from being
+// deleted.
+func runBulkDelete(ctx context.Context, id string, volnames []string, wipe, purge bool, concurrency int) {
+	logger := log.WithField("job-id", id)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, name := range volnames {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			updateBulkDeleteJob(id, func(j *api.VolumeBulkDeleteJob) {
+				j.Volumes[i].State = api.VolumeDeleteRunning
+			})
+
+			var (
+				result  *volumeDeleteResult
+				status  int
+				err     error
+				attempt int
+			)
+			for attempt = 1; attempt <= bulkDeleteAttempts; attempt++ {
+				result, status, err = volumeDelete(gdctx.WithVolName(ctx, name), name, wipe, purge)
+				if err == nil || !retryVolumeDelete(err) || attempt == bulkDeleteAttempts {
+					break
+				}
+				time.Sleep(bulkDeleteBackoff * time.Duration(1<<uint(attempt-1)))
+			}
+
+			updateBulkDeleteJob(id, func(j *api.VolumeBulkDeleteJob) {
+				v := &j.Volumes[i]
+				v.Attempts = attempt
+				if err != nil {
+					v.State = api.VolumeDeleteFailed
+					v.StatusCode = status
+					v.Error = err.Error()
+					j.Failed++
+					return
+				}
+				v.State = api.VolumeDeleteDone
+				v.Trashed = result.trashed
+				for _, job := range result.wipeJobs {
+					v.BrickWipeJobs = append(v.BrickWipeJobs, job.ID)
+				}
+				j.Deleted++
+			})
+			if err != nil {
+				logger.WithError(err).WithField("volume", name).Warn("failed to delete volume")
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	var deleted, failed int
+	updateBulkDeleteJob(id, func(j *api.VolumeBulkDeleteJob) {
+		j.State = api.VolumeDeleteComplete
+		j.EndTime = time.Now()
+		deleted, failed = j.Deleted, j.Failed
+	})
+	logger.WithFields(log.Fields{
+		"deleted": deleted,
+		"failed":  failed,
+	}).Info("bulk volume delete complete")
+}
+
+func volumeBulkDeleteJobListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bulkDeleteJobsMu.Lock()
+	ids := make([]string, 0, len(bulkDeleteJobs))
+	for id := range bulkDeleteJobs {
+		ids = append(ids, id)
+	}
+	bulkDeleteJobsMu.Unlock()
+
+	resp := make(api.VolumeBulkDeleteJobListResp, 0, len(ids))
+	for _, id := range ids {
+		if j, ok := getBulkDeleteJob(id); ok {
+			resp = append(resp, j)
+		}
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].StartTime.Before(resp[j].StartTime)
+	})
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeBulkDeleteJobGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	j, ok := getBulkDeleteJob(mux.Vars(r)["id"])
+	if !ok {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errBulkDeleteJobNotFound)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, j)
+}
@@ -0,0 +1,85 @@
+package volumecommands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+)
+
+const adoptVolumeIDTxnKey = "adopt-volume-id"
+
+// txnReadBrickVolumeIDs reads the volume ID from the local bricks of a volume
+// being adopted and saves it as the result of this node. All the local bricks
+// are required to belong to the same volume.
+func txnReadBrickVolumeIDs(c transaction.TxnCtx) error {
+
+	var req api.VolCreateReq
+	if err := c.Get("req", &req); err != nil {
+		return err
+	}
+
+	var volumeID uuid.UUID
+	for _, subvol := range req.Subvols {
+		for _, b := range subvol.Bricks {
+			if !uuid.Equal(uuid.Parse(b.PeerID), gdctx.MyUUID) {
+				continue
+			}
+
+			id, err := brick.ReadVolumeID(b.Path)
+			if err != nil {
+				c.Logger().WithError(err).WithField("brick", b.Path).Error("failed to read volume ID of brick")
+				return err
+			}
+
+			if volumeID == nil {
+				volumeID = id
+			} else if !uuid.Equal(volumeID, id) {
+				return fmt.Errorf("bricks belong to different volumes (%s and %s)", volumeID, id)
+			}
+		}
+	}
+
+	return c.SetNodeResult(gdctx.MyUUID, adoptVolumeIDTxnKey, volumeID.String())
+}
+
+// adoptedVolumeID returns the volume ID read from the bricks on all the nodes
+// by txnReadBrickVolumeIDs, ensuring that all the bricks belong to the same
+// volume.
+func adoptedVolumeID(c transaction.TxnCtx, req *api.VolCreateReq) (uuid.UUID, error) {
+
+	nodes, err := req.Nodes()
+	if err != nil {
+		return nil, err
+	}
+
+	var volumeID uuid.UUID
+	for _, node := range nodes {
+		var id string
+		if err := c.GetNodeResult(node, adoptVolumeIDTxnKey, &id); err != nil {
+			return nil, err
+		}
+
+		nodeVolumeID := uuid.Parse(id)
+		if nodeVolumeID == nil {
+			return nil, fmt.Errorf("invalid volume ID %q read from bricks on peer %s", id, node)
+		}
+
+		if volumeID == nil {
+			volumeID = nodeVolumeID
+		} else if !uuid.Equal(volumeID, nodeVolumeID) {
+			return nil, fmt.Errorf("bricks on peer %s belong to volume %s, expected %s", node, nodeVolumeID, volumeID)
+		}
+	}
+
+	if volumeID == nil {
+		return nil, errors.New("could not determine volume ID from bricks")
+	}
+
+	return volumeID, nil
+}
@@ -197,30 +197,54 @@ func createVolinfo(c transaction.TxnCtx) error {
 		return err
 	}
 
+	switch {
+	case req.Adopt:
+		// Reconstruct the volinfo with the volume ID found on the bricks
+		volinfo.ID, err = adoptedVolumeID(c, &req)
+		if err != nil {
+			return err
+		}
+	case req.Size > 0:
+		// Auto provisioned bricks are named after the volume ID they
+		// were planned with
+		if err := c.Get("volume-id", &volinfo.ID); err != nil {
+			return err
+		}
+		volinfo.BrickNaming = volume.CurrentBrickNaming
+	}
+	for _, subvol := range volinfo.Subvols {
+		for idx := range subvol.Bricks {
+			subvol.Bricks[idx].VolumeID = volinfo.ID
+		}
+	}
+
 	if err := validateXlatorOptions(req.Options, volinfo); err != nil {
-		return err
+		return transaction.NewValidationError(err)
 	}
 
-	if err := c.Set("volinfo", volinfo); err != nil {
-		return err
+	if err := volume.RunValidators(volume.ValidateCreate, volinfo); err != nil {
+		return transaction.NewValidationError(err)
 	}
 
-	if err := c.Set("bricks", volinfo.GetBricks()); err != nil {
+	if err := c.Set("volinfo", volinfo); err != nil {
 		return err
 	}
 
-	allBricks, err := volume.GetAllBricksInCluster()
-	if err != nil {
+	if err := c.Set("bricks", volinfo.GetBricks()); err != nil {
 		return err
 	}
 
-	// Used by other peers to check if proposed bricks are already in use.
-	// This check is however still prone to races. See issue #314
-	if err := c.Set("all-bricks-in-cluster", allBricks); err != nil {
-		return err
+	// Bricks taken by other volumes concurrently are caught when the
+	// volinfo is stored, as the brick index is updated along with it
+	if err := volume.CheckBrickPathConflicts(volinfo.GetBricks()); err != nil {
+		return transaction.NewValidationError(err)
 	}
 
 	checks := brick.PrepareChecks(req.Force, req.Flags)
+	if req.Adopt {
+		checks.Adopt = true
+		checks.CreateBrickDir = false
+	}
 	err = c.Set("brick-checks", checks)
 
 	return err
@@ -9,6 +9,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/bricksplanner"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
@@ -38,8 +39,8 @@ func applyDefaults(req *api.VolCreateReq) {
 }
 
 func validateVolCreateReq(req *api.VolCreateReq) error {
-	if !volume.IsValidName(req.Name) {
-		return gderrors.ErrInvalidVolName
+	if err := volume.ValidateName(req.Name); err != nil {
+		return err
 	}
 
 	if req.Transport != "" && req.Transport != "tcp" && req.Transport != "rdma" {
@@ -50,6 +51,10 @@ func validateVolCreateReq(req *api.VolCreateReq) error {
 		return errors.New("invalid Volume Size, Minimum size required is " + strconv.Itoa(minVolumeSize))
 	}
 
+	if req.Adopt && req.Size > 0 {
+		return errors.New("bricks cannot be adopted for a volume with auto provisioned bricks")
+	}
+
 	if req.Size == 0 && len(req.Subvols) <= 0 {
 		return gderrors.ErrEmptyBrickList
 	}
@@ -63,6 +68,10 @@ func validateVolCreateReq(req *api.VolCreateReq) error {
 		return gderrors.ErrMetadataSizeOutOfBounds
 	}
 
+	if _, err := peer.ParseSelector(req.PeerSelector); err != nil {
+		return err
+	}
+
 	return validateVolumeFlags(req.Flags)
 }
 
@@ -85,6 +94,7 @@ func registerVolCreateStepFuncs() {
 		name string
 		sf   transaction.StepFunc
 	}{
+		{"vol-create.ReadBrickVolumeIDs", txnReadBrickVolumeIDs},
 		{"vol-create.CreateVolinfo", createVolinfo},
 		{"vol-create.ValidateBricks", validateBricks},
 		{"vol-create.InitBricks", initBricks},
@@ -97,6 +107,8 @@ func registerVolCreateStepFuncs() {
 	for _, sf := range sfs {
 		transaction.RegisterStepFunc(sf.sf, sf.name)
 	}
+	transaction.RegisterUndoFunc("vol-create.InitBricks", "vol-create.UndoInitBricks")
+	transaction.RegisterUndoFunc("vol-create.PrepareBricks", "vol-create.UndoPrepareBricks")
 }
 
 func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
@@ -105,7 +117,7 @@ func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, span := trace.StartSpan(ctx, "/volumeCreateHandler")
 	defer span.End()
 
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	var err error
 
 	var req api.VolCreateReq
@@ -114,6 +126,16 @@ func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Profile != "" {
+		profile, err := volume.GetVolumeProfile(req.Profile)
+		if err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		volume.ApplyVolumeProfile(&req, profile)
+	}
+
 	if err := validateVolCreateReq(&req); err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
 		return
@@ -124,6 +146,9 @@ func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Auto provisioned bricks are named after the volume ID, so the ID is
+	// chosen before the bricks are planned
+	volID := uuid.NewRandom()
 	if req.Size > 0 {
 		applyDefaults(&req)
 
@@ -133,7 +158,7 @@ func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := bricksplanner.PlanBricks(&req); err != nil {
+		if err := bricksplanner.PlanBricks(&req, volID); err != nil {
 			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
@@ -183,10 +208,16 @@ func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer txn.Done()
 
-	if volume.Exists(req.Name) {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrVolExists)
+	release, err := volume.ReserveName(req.Name)
+	if err == gderrors.ErrVolExists {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+	defer release()
 
 	txn.Steps = []*transaction.Step{
 		{
@@ -195,9 +226,16 @@ func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
 			Nodes:    nodes,
 			Skip:     (req.Size == 0),
 		},
+		{
+			DoFunc: "vol-create.ReadBrickVolumeIDs",
+			Nodes:  nodes,
+			Skip:   !req.Adopt,
+		},
 		{
 			DoFunc: "vol-create.CreateVolinfo",
 			Nodes:  []uuid.UUID{gdctx.MyUUID},
+			// Adopting bricks requires volume IDs read from all nodes
+			Sync: req.Adopt,
 		},
 		{
 			DoFunc: "vol-create.ValidateBricks",
@@ -209,6 +247,8 @@ func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
 			DoFunc:   "vol-create.InitBricks",
 			UndoFunc: "vol-create.UndoInitBricks",
 			Nodes:    nodes,
+			// Adopted bricks are already initialized
+			Skip: req.Adopt,
 		},
 		{
 			DoFunc:   "vol-create.StoreVolume",
@@ -218,11 +258,20 @@ func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	if req.JobID == "" {
+		req.JobID = txn.Ctx.GetTxnReqID()
+	}
+
 	if err := txn.Ctx.Set("req", &req); err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
 
+	if err := txn.Ctx.Set("volume-id", volID); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
 	// Add attributes to the span with info that can be viewed along with traces.
 	// The attributes can also be used to filter traces on the tracing UI.
 	span.AddAttributes(
@@ -249,10 +298,55 @@ func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
 	events.Broadcast(volume.NewEvent(volume.EventVolumeCreated, volinfo))
 
 	resp := createVolumeCreateResp(volinfo)
+	if resp.Placement, err = getVolumePlacement(volinfo, req.Size > 0); err != nil {
+		logger.WithError(err).WithField("volume-name", volinfo.Name).Warn("failed to get placement of bricks")
+	} else if !resp.Placement.ZonesSpread {
+		logger.WithField("volume-name", volinfo.Name).Warn("bricks of a subvolume share zones")
+	}
 	restutils.SetLocationHeader(r, w, volinfo.Name)
 	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
 }
 
 func createVolumeCreateResp(v *volume.Volinfo) *api.VolumeCreateResp {
-	return (*api.VolumeCreateResp)(volume.CreateVolumeInfoResp(v))
+	return &api.VolumeCreateResp{VolumeInfo: *volume.CreateVolumeInfoResp(v)}
+}
+
+// getVolumePlacement returns the placement of the bricks of the volume
+func getVolumePlacement(v *volume.Volinfo, planned bool) (*api.VolumePlacement, error) {
+	peers, err := peer.GetPeersF()
+	if err != nil {
+		return nil, err
+	}
+	zones := make(map[string]string, len(peers))
+	for _, p := range peers {
+		zones[p.ID.String()] = p.Zone()
+	}
+	return newVolumePlacement(v, zones, planned), nil
+}
+
+// newVolumePlacement returns the placement of the bricks of the volume, given
+// the zones of the peers by their IDs
+func newVolumePlacement(v *volume.Volinfo, zones map[string]string, planned bool) *api.VolumePlacement {
+	placement := &api.VolumePlacement{Planned: planned, ZonesSpread: true}
+	for _, sv := range v.Subvols {
+		used := make(map[string]bool)
+		for _, b := range sv.Bricks {
+			zone, ok := zones[b.PeerID.String()]
+			if !ok {
+				zone = b.PeerID.String()
+			}
+			placement.Bricks = append(placement.Bricks, api.BrickPlacement{
+				Subvol: sv.Name,
+				Path:   b.Path,
+				PeerID: b.PeerID,
+				Zone:   zone,
+			})
+
+			if sv.Type != volume.SubvolDistribute && used[zone] {
+				placement.ZonesSpread = false
+			}
+			used[zone] = true
+		}
+	}
+	return placement
 }
@@ -43,3 +43,30 @@ func TestCreateVolinfo(t *testing.T) {
 	_, e = newVolinfo(msg)
 	assert.Equal(t, errBad, e)
 }
+
+func TestNewVolumePlacement(t *testing.T) {
+	p1, p2, p3 := uuid.NewRandom(), uuid.NewRandom(), uuid.NewRandom()
+	zones := map[string]string{p1.String(): "rack1", p2.String(): "rack2", p3.String(): "rack1"}
+
+	v := &volume.Volinfo{Subvols: []volume.Subvol{{
+		Name: "vol-replicate-0",
+		Type: volume.SubvolReplicate,
+		Bricks: []brick.Brickinfo{
+			{PeerID: p1, Path: "/b1"},
+			{PeerID: p2, Path: "/b2"},
+		},
+	}}}
+	placement := newVolumePlacement(v, zones, true)
+	assert.True(t, placement.Planned)
+	assert.True(t, placement.ZonesSpread)
+	assert.Len(t, placement.Bricks, 2)
+	assert.Equal(t, "rack2", placement.Bricks[1].Zone)
+
+	// Replicas in the same zone aren't spread
+	v.Subvols[0].Bricks = append(v.Subvols[0].Bricks, brick.Brickinfo{PeerID: p3, Path: "/b3"})
+	assert.False(t, newVolumePlacement(v, zones, true).ZonesSpread)
+
+	// Bricks of distribute subvolumes can share zones
+	v.Subvols[0].Type = volume.SubvolDistribute
+	assert.True(t, newVolumePlacement(v, zones, false).ZonesSpread)
+}
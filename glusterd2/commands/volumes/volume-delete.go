@@ -1,8 +1,11 @@
 package volumecommands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
@@ -10,6 +13,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
 
 	"github.com/gorilla/mux"
 	"github.com/pborman/uuid"
@@ -33,62 +37,126 @@ func deleteVolume(c transaction.TxnCtx) error {
 
 func registerVolDeleteStepFuncs() {
 	transaction.RegisterStepFunc(deleteVolume, "vol-delete.Store")
+	transaction.RegisterStepFunc(trashVolume, "vol-delete.Trash")
 	transaction.RegisterStepFunc(txnCleanBricks, "vol-delete.CleanBricks")
 }
 
 func volumeDeleteHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
 	volname := mux.Vars(r)["volname"]
 
 	ctx, span := trace.StartSpan(ctx, "/volumeDeleteHandler")
 	defer span.End()
 
-	txn, err := transactionv2.NewTxnWithLocks(ctx, volname)
+	// With wipe, the LVs of the bricks are cleaned up by the brick wipe
+	// jobs after discarding them
+	wipe := r.URL.Query().Get("wipe") == "true"
+	purge := r.URL.Query().Get("purge") == "true"
+
+	result, status, err := volumeDelete(ctx, volname, wipe, purge)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+
+	// The bricks of a volume in the trash are wiped when it is purged
+	if wipe {
+		jobs := api.BrickWipeJobsResp{}
+		jobs = append(jobs, result.wipeJobs...)
+		restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, jobs)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// volumeDeleteResult is the outcome of deleting a volume
+type volumeDeleteResult struct {
+	// trashed is set if the volume was moved to the trash instead of
+	// being deleted
+	trashed bool
+	// wipeJobs are the brick wipe jobs scheduled for the bricks of the
+	// volume, when it is deleted with wipe
+	wipeJobs []api.BrickWipeJob
+}
+
+// volumeDelete deletes the volume, or moves it to the trash unless it is
+// purged. On failure, the HTTP status code for the error is returned along
+// with it.
+func volumeDelete(ctx context.Context, volname string, wipe, purge bool) (*volumeDeleteResult, int, error) {
+	logger := gdctx.Logger(ctx)
+
+	ctx, span := trace.StartSpan(ctx, "volumeDelete")
+	defer span.End()
+
+	txn, err := transactionv2.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
+	}
 	defer txn.Done()
 
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
 
 	if volinfo.State == volume.VolStarted {
-		errMsg := "Volume must be in stopped state before deleting."
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-		return
+		return nil, http.StatusBadRequest, errors.New("Volume must be in stopped state before deleting.")
+	}
+
+	if err := volume.CheckAdvisoryLocks(volname); err != nil {
+		if _, ok := err.(*volume.AdvisoryLockHeldError); ok {
+			return nil, http.StatusConflict, err
+		}
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
 	}
 
 	if len(volinfo.SnapList) > 0 {
-		errMsg := fmt.Sprintf("Cannot delete Volume %s ,as it has %d snapshots.", volname, len(volinfo.SnapList))
-		restutils.SendHTTPError(ctx, w, http.StatusFailedDependency, errMsg)
-		return
+		return nil, http.StatusFailedDependency,
+			fmt.Errorf("Cannot delete Volume %s ,as it has %d snapshots.", volname, len(volinfo.SnapList))
 	}
 
+	// The volume is kept in the trash for the retention period, with its
+	// bricks untouched, unless it is purged right away
+	retention, err := trashRetention()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	trash := retention > 0 && !purge
+
 	bricksAutoProvisioned := volinfo.IsAutoProvisioned() || volinfo.IsSnapshotProvisioned()
 	txn.Steps = []*transaction.Step{
 		{
 			DoFunc: "vol-delete.CleanBricks",
 			Nodes:  volinfo.Nodes(),
-			Skip:   !bricksAutoProvisioned,
+			Skip:   !bricksAutoProvisioned || wipe || trash,
 		},
 		{
 			DoFunc: "vol-delete.Store",
 			Nodes:  []uuid.UUID{gdctx.MyUUID},
 			Sync:   true,
+			Skip:   trash,
+		},
+		{
+			DoFunc: "vol-delete.Trash",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+			Sync:   true,
+			Skip:   !trash,
 		},
 	}
 
 	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
+	}
+	if err := txn.Ctx.Set("retention", retention); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if err := txn.Ctx.Set("wipe", wipe); err != nil {
+		return nil, http.StatusInternalServerError, err
 	}
 
 	span.AddAttributes(
@@ -99,11 +167,29 @@ func volumeDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	if err := txn.Do(); err != nil {
 		logger.WithError(err).WithField(
 			"volume", volname).Error("transaction to delete volume failed")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
-	events.Broadcast(volume.NewEvent(volume.EventVolumeDeleted, volinfo))
+	if err := volume.DeleteMetricsSamples(volname); err != nil {
+		logger.WithError(err).WithField("volume", volname).Warn("failed to delete volume metrics")
+	}
 
-	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+	e := volume.NewEvent(volume.EventVolumeDeleted, volinfo)
+	if trash {
+		e.Data["volume.purge-at"] = time.Now().Add(retention).Format(time.RFC3339)
+	}
+	events.Broadcast(e)
+
+	result := &volumeDeleteResult{trashed: trash}
+	if wipe && !trash {
+		jobs, err := volume.ScheduleBrickWipes(volinfo)
+		if err != nil {
+			logger.WithError(err).WithField("volume", volname).Error("failed to schedule brick wipe jobs")
+			return nil, http.StatusInternalServerError,
+				fmt.Errorf("volume deleted, but failed to schedule wiping all its bricks: %s", err)
+		}
+		result.wipeJobs = jobs
+	}
+
+	return result, 0, nil
 }
@@ -0,0 +1,94 @@
+package volumecommands
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/gorilla/mux"
+)
+
+// diffPoint is a point in the history of a volume, either a store revision
+// or a time
+type diffPoint struct {
+	rev int64
+	t   time.Time
+}
+
+func parseDiffPoint(s string) (*diffPoint, error) {
+	if rev, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if rev <= 0 {
+			return nil, fmt.Errorf("invalid revision %d", rev)
+		}
+		return &diffPoint{rev: rev}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is neither a revision nor a RFC3339 timestamp", s)
+	}
+	return &diffPoint{t: t}, nil
+}
+
+// getVolumeAt returns the volinfo of the volume at the point in its history
+func getVolumeAt(volname string, p *diffPoint) (*volume.Volinfo, error) {
+	rev := p.rev
+	if rev == 0 {
+		var err error
+		if rev, err = volume.RevisionAt(volname, p.t); err != nil {
+			return nil, err
+		}
+	}
+	return volume.GetVolumeAtRevision(volname, rev)
+}
+
+func volumeDiffHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	query := r.URL.Query()
+	if query.Get("from") == "" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "from query parameter is required")
+		return
+	}
+	from, err := parseDiffPoint(query.Get("from"))
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	// The current volinfo is compared to if to isn't given
+	var to *diffPoint
+	if query.Get("to") != "" {
+		if to, err = parseDiffPoint(query.Get("to")); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	oldVolinfo, err := getVolumeAt(volname, from)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	var newVolinfo *volume.Volinfo
+	if to != nil {
+		newVolinfo, err = getVolumeAt(volname, to)
+	} else {
+		newVolinfo, err = volume.GetVolume(volname)
+	}
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := volume.Diff(oldVolinfo, newVolinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, &resp)
+}
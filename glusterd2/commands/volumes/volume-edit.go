@@ -20,7 +20,7 @@ func volumeEditHandler(w http.ResponseWriter, r *http.Request) {
 	volname := p["volname"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	var req api.VolEditReq
 	if err := restutils.UnmarshalRequest(r, &req); err != nil {
@@ -38,42 +38,43 @@ func volumeEditHandler(w http.ResponseWriter, r *http.Request) {
 
 	defer txn.Done()
 
-	//validate volume name
-	volinfo, err := volume.GetVolume(volname)
-	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
-	}
-
 	reqMetadataSize := req.MetadataSize()
 	if reqMetadataSize > maxMetadataSizeLimit {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrMetadataSizeOutOfBounds)
 		return
 	}
-	for key, value := range req.Metadata {
+	for key := range req.Metadata {
 		if strings.HasPrefix(key, "_") {
 			logger.WithField("key", key).Error(errors.ErrRestrictedKeyFound)
 			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrRestrictedKeyFound)
 			return
 		}
-		if req.DeleteMetadata {
-			delete(volinfo.Metadata, key)
-		} else {
-			volinfo.Metadata[key] = value
-		}
 	}
 
-	if volinfo.MetadataSize() > maxMetadataSizeLimit {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrMetadataSizeOutOfBounds)
+	volinfo, err := volume.UpdateVolume(volname, 0, func(v *volume.Volinfo) error {
+		for key, value := range req.Metadata {
+			if req.DeleteMetadata {
+				delete(v.Metadata, key)
+			} else {
+				v.Metadata[key] = value
+			}
+		}
+		if v.MetadataSize() > maxMetadataSizeLimit {
+			return errors.ErrMetadataSizeOutOfBounds
+		}
+		return nil
+	})
+	if err == errors.ErrMetadataSizeOutOfBounds {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
 		return
-	}
-	if err := volume.AddOrUpdateVolumeFunc(volinfo); err != nil {
+	} else if err != nil {
 		logger.WithError(err).WithField(
-			"volume", volinfo.Name).Debug("failed to store volume info")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "failed to store volume info")
+			"volume", volname).Debug("failed to store volume info")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+
 	resp := createEditVolumeResp(volinfo)
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
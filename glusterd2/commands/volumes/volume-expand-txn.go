@@ -31,8 +31,12 @@ func expandValidatePrepare(c transaction.TxnCtx) error {
 		return err
 	}
 
-	//As of now volume expand doesn't support auto provisioned bricks
+	// Bricks are auto provisioned when expanding the volume by size
 	provisionType := brick.ManuallyProvisioned
+	var provision bool
+	if err := c.Get("provision-bricks", &provision); err == nil && provision {
+		provisionType = brick.AutoProvisioned
+	}
 
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
@@ -68,15 +72,8 @@ func expandValidatePrepare(c transaction.TxnCtx) error {
 		return err
 	}
 
-	allBricks, err := volume.GetAllBricksInCluster()
-	if err != nil {
-		return err
-	}
-
-	// Used by other peers to check if proposed bricks are already in use.
-	// This check is however still prone to races. See issue #314
-	if err := c.Set("all-bricks-in-cluster", allBricks); err != nil {
-		return err
+	if err := volume.CheckBrickPathConflicts(newBricks); err != nil {
+		return transaction.NewValidationError(err)
 	}
 
 	checks := brick.PrepareChecks(req.Force, req.Flags)
@@ -209,6 +206,15 @@ func updateVolinfoOnExpand(c transaction.TxnCtx) error {
 		return err
 	}
 
+	var provision bool
+	if err := c.Get("provision-bricks", &provision); err == nil && provision {
+		var req api.VolExpandReq
+		if err := c.Get("req", &req); err != nil {
+			return err
+		}
+		volinfo.Capacity += req.Size
+	}
+
 	// TODO: Assumption, all subvols are same
 	// If New Replica count is different than existing then add one brick to each subvolume
 	// Or if the Volume consists of only one subvolume.
@@ -275,6 +281,10 @@ func updateVolinfoOnExpand(c transaction.TxnCtx) error {
 
 	volinfo.DistCount = len(volinfo.Subvols)
 
+	if err := volume.RunValidators(volume.ValidateExpand, &volinfo); err != nil {
+		return transaction.NewValidationError(err)
+	}
+
 	// update new volinfo in txn ctx
 	if err := c.Set("volinfo", volinfo); err != nil {
 		return err
@@ -337,8 +347,7 @@ func expandLocalBricks(volinfo *volume.Volinfo, expansionTpSizePerBrick uint64,
 	for i, sv := range volinfo.Subvols {
 		for j, b := range sv.Bricks {
 			if uuid.Equal(b.PeerID, gdctx.MyUUID) {
-				tpName := fmt.Sprintf("tp_%s_s%d_b%d", volinfo.Name, i+1, j+1)
-				lvName := fmt.Sprintf("brick_%s_s%d_b%d", volinfo.Name, i+1, j+1)
+				tpName, lvName := volinfo.BrickLvNames(i+1, j+1)
 				totalExpansionSizePerBrick := expansionTpSizePerBrick + expansionMetadataSizePerBrick
 
 				// extend thinpool
@@ -368,3 +377,33 @@ func expandLocalBricks(volinfo *volume.Volinfo, expansionTpSizePerBrick uint64,
 	}
 	return nil
 }
+
+// txnPrepareExpansionBricks provisions the local bricks chosen for expanding
+// the volume by size
+func txnPrepareExpansionBricks(c transaction.TxnCtx) error {
+	var req api.VolExpandReq
+	if err := c.Get("req", &req); err != nil {
+		return err
+	}
+
+	for _, b := range req.Bricks {
+		if err := PrepareBrick(b, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func txnUndoPrepareExpansionBricks(c transaction.TxnCtx) error {
+	var req api.VolExpandReq
+	if err := c.Get("req", &req); err != nil {
+		return err
+	}
+
+	for _, b := range req.Bricks {
+		if b.PeerID == gdctx.MyUUID.String() {
+			UndoPrepareBrick(b, c)
+		}
+	}
+	return nil
+}
@@ -1,11 +1,15 @@
 package volumecommands
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"path/filepath"
 
+	"github.com/gluster/glusterd2/glusterd2/bricksplanner"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/ioops"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
@@ -14,6 +18,8 @@ import (
 	"github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/lvmutils"
 	"github.com/gluster/glusterd2/plugins/device/deviceutils"
+	"github.com/gluster/glusterd2/plugins/rebalance"
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
 
 	"github.com/gorilla/mux"
 	"github.com/pborman/uuid"
@@ -36,10 +42,13 @@ func registerVolExpandStepFuncs() {
 		{"vol-expand.UpdateVolinfo", updateVolinfoOnExpand},
 		{"vol-expand.NotifyClients", notifyVolfileChange},
 		{"vol-expand.LvmResize", resizeLVM},
+		{"vol-expand.PrepareBricks", txnPrepareExpansionBricks},
+		{"vol-expand.UndoPrepareBricks", txnUndoPrepareExpansionBricks},
 	}
 	for _, sf := range sfs {
 		transaction.RegisterStepFunc(sf.sf, sf.name)
 	}
+	transaction.RegisterUndoFunc("vol-expand.PrepareBricks", "vol-expand.UndoPrepareBricks")
 }
 
 func validateVolumeExpandReq(req api.VolExpandReq) error {
@@ -65,12 +74,69 @@ func checkForLvmResize(req api.VolExpandReq, volinfo *volume.Volinfo) bool {
 	return false
 }
 
+// checkForPlannedExpansion returns true if the bricks of the subvolumes added
+// to the volume have to be chosen and provisioned
+func checkForPlannedExpansion(req api.VolExpandReq, volinfo *volume.Volinfo) bool {
+	return req.Size != 0 && len(req.Bricks) == 0 && req.DistributeCount != len(volinfo.Subvols)
+}
+
+// expansionCreateReq returns the request with which the bricks planner
+// chooses the bricks of the subvolumes added to the volume, which are like
+// its existing subvolumes
+func expansionCreateReq(req api.VolExpandReq, volinfo *volume.Volinfo) (*api.VolCreateReq, error) {
+	newSubvols := 1
+	if req.DistributeCount != 0 {
+		newSubvols = req.DistributeCount - len(volinfo.Subvols)
+	}
+	if newSubvols < 1 {
+		return nil, fmt.Errorf("distribute count must be more than the %d subvolumes of the volume", len(volinfo.Subvols))
+	}
+
+	createReq := &api.VolCreateReq{
+		Name:                  volinfo.Name,
+		Size:                  req.Size,
+		DistributeCount:       newSubvols,
+		SnapshotReserveFactor: volinfo.SnapshotReserveFactor,
+	}
+	if createReq.SnapshotReserveFactor < 1 {
+		createReq.SnapshotReserveFactor = 1
+	}
+
+	sv := volinfo.Subvols[0]
+	switch sv.Type {
+	case volume.SubvolReplicate:
+		createReq.ReplicaCount = sv.ReplicaCount
+		createReq.ArbiterCount = sv.ArbiterCount
+	case volume.SubvolDisperse:
+		createReq.DisperseCount = sv.DisperseCount
+		createReq.DisperseRedundancyCount = sv.RedundancyCount
+	}
+	return createReq, nil
+}
+
+// planExpansionBricks sets the bricks of the request to those chosen by the
+// bricks planner for the subvolumes added to the volume
+func planExpansionBricks(req *api.VolExpandReq, volinfo *volume.Volinfo) error {
+	createReq, err := expansionCreateReq(*req, volinfo)
+	if err != nil {
+		return transaction.NewValidationError(err)
+	}
+	if err := bricksplanner.PlanExpansionBricks(createReq, volinfo.ID, len(volinfo.Subvols)); err != nil {
+		return err
+	}
+
+	for _, sv := range createReq.Subvols {
+		req.Bricks = append(req.Bricks, sv.Bricks...)
+	}
+	return nil
+}
+
 func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 	ctx, span := trace.StartSpan(ctx, "/volumeExpandHandler")
 	defer span.End()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	volname := mux.Vars(r)["volname"]
 
 	var req api.VolExpandReq
@@ -84,18 +150,46 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	volinfo, status, err := expandVolume(ctx, volname, req)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+
+	logger.WithField("volume-name", volinfo.Name).Info("volume expanded")
+	events.Broadcast(volume.NewEvent(volume.EventVolumeExpanded, volinfo))
+
+	if req.Rebalance {
+		_, err := rebalance.StartRebalance(ctx, volname, &rebalanceapi.StartReq{})
+		if err == ioops.ErrQueued {
+			logger.Info("rebalance after expansion queued behind conflicting operations")
+		} else if err != nil {
+			logger.WithError(err).Warn("failed to start rebalance after expansion")
+		}
+	}
+
+	resp := createVolumeExpandResp(volinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// expandVolume expands the volume as described by req. On failure, the HTTP
+// status code to be sent for the error is returned along with the error.
+func expandVolume(ctx context.Context, volname string, req api.VolExpandReq) (*volume.Volinfo, int, error) {
+
+	ctx, span := trace.StartSpan(ctx, "expandVolume")
+	defer span.End()
+	logger := gdctx.Logger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
+	}
 	defer txn.Done()
 
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	var expansionSizePerBrick uint64
@@ -104,6 +198,13 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 	var brickVgMapping map[string]string
 	var ok bool
 	lvmResizeOp := checkForLvmResize(req, volinfo)
+	plannedOp := checkForPlannedExpansion(req, volinfo)
+	if plannedOp {
+		if err := planExpansionBricks(&req, volinfo); err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			return nil, status, err
+		}
+	}
 	// continue normal volume expand by adding new bricks or subvols
 	if !lvmResizeOp {
 		for index := range volinfo.Subvols {
@@ -112,8 +213,7 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 				for _, b := range req.Bricks {
 
 					if brick.PeerID.String() == b.PeerID && brick.Path == filepath.Clean(b.Path) {
-						restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrDuplicateBrickPath)
-						return
+						return nil, http.StatusBadRequest, errors.ErrDuplicateBrickPath
 					}
 				}
 
@@ -138,13 +238,11 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 		bricksInfo := volinfo.GetBricks()
 		brickVgMapping, ok, err = deviceutils.CheckForAvailableVgSize(totalExpansionSizePerBrick, bricksInfo)
 		if !ok && err == nil {
-			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Space not sufficient on device")
-			return
+			return nil, http.StatusBadRequest, errors.ErrNotEnoughDeviceSpace
 		}
 
 		if err != nil {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-			return
+			return nil, http.StatusInternalServerError, err
 		}
 
 	}
@@ -152,14 +250,12 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 	nodes, err := req.Nodes()
 	if err != nil {
 		logger.WithError(err).Error("could not prepare node list")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	allNodes, err := peer.GetPeerIDs()
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	txn.Nodes = allNodes
@@ -167,6 +263,12 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 		// TODO: This is a lot of steps. We can combine a few if we
 		// do not re-use the same step functions across multiple
 		// volume operations.
+		{
+			DoFunc:   "vol-expand.PrepareBricks",
+			UndoFunc: "vol-expand.UndoPrepareBricks",
+			Nodes:    nodes,
+			Skip:     !plannedOp,
+		},
 		{
 			DoFunc: "vol-expand.ValidateAndPrepare",
 			Nodes:  []uuid.UUID{gdctx.MyUUID},
@@ -223,28 +325,27 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := txn.Ctx.Set("req", &req); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("volname", volname); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err := txn.Ctx.Set("provision-bricks", plannedOp); err != nil {
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("expansionTpSizePerBrick", expansionTpSizePerBrick); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("expansionMetadataSizePerBrick", expansionMetadataSizePerBrick); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("brickVgMapping", brickVgMapping); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	// Add relevant attributes to the root span
@@ -262,21 +363,15 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 	if err = txn.Do(); err != nil {
 		logger.WithError(err).WithField("volume-name", volname).Error("volume expand transaction failed")
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
 
 	volinfo, err = volume.GetVolume(volname)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
-	logger.WithField("volume-name", volinfo.Name).Info("volume expanded")
-	events.Broadcast(volume.NewEvent(volume.EventVolumeExpanded, volinfo))
-
-	resp := createVolumeExpandResp(volinfo)
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+	return volinfo, http.StatusOK, nil
 }
 
 func createVolumeExpandResp(v *volume.Volinfo) *api.VolumeExpandResp {
@@ -0,0 +1,38 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpansionCreateReq(t *testing.T) {
+	v := &volume.Volinfo{
+		Name: "vol",
+		Subvols: []volume.Subvol{
+			{Type: volume.SubvolReplicate, ReplicaCount: 2, ArbiterCount: 1},
+			{Type: volume.SubvolReplicate, ReplicaCount: 2, ArbiterCount: 1},
+		},
+	}
+
+	assert.False(t, checkForPlannedExpansion(api.VolExpandReq{Size: 1024, DistributeCount: 2}, v))
+	assert.False(t, checkForPlannedExpansion(api.VolExpandReq{Size: 1024, Bricks: []api.BrickReq{{}}}, v))
+	assert.True(t, checkForPlannedExpansion(api.VolExpandReq{Size: 1024}, v))
+
+	req, err := expansionCreateReq(api.VolExpandReq{Size: 1024}, v)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, req.DistributeCount)
+	assert.Equal(t, 2, req.ReplicaCount)
+	assert.Equal(t, 1, req.ArbiterCount)
+	assert.Equal(t, float64(1), req.SnapshotReserveFactor)
+
+	req, err = expansionCreateReq(api.VolExpandReq{Size: 1024, DistributeCount: 5}, v)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, req.DistributeCount)
+
+	_, err = expansionCreateReq(api.VolExpandReq{Size: 1024, DistributeCount: 1}, v)
+	assert.NotNil(t, err)
+}
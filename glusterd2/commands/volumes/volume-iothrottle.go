@@ -0,0 +1,233 @@
+package volumecommands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+// Limits lower than these would all but stall the bricks, and are most
+// likely mistakes in the units
+const (
+	minIOPSLimit = 10
+	minBPSLimit  = 1024 * 1024
+)
+
+func registerVolIOThrottleStepFuncs() {
+	transaction.RegisterStepFunc(txnSetIOLimits, "vol-iothrottle.SetIOLimits")
+	transaction.RegisterStepFunc(txnUndoSetIOLimits, "vol-iothrottle.SetIOLimits.Undo")
+}
+
+func txnSetIOLimits(c transaction.TxnCtx) error {
+	return setLocalBricksIOLimits(c, "policy")
+}
+
+func txnUndoSetIOLimits(c transaction.TxnCtx) error {
+	return setLocalBricksIOLimits(c, "oldpolicy")
+}
+
+// setLocalBricksIOLimits sets the I/O limits of the local bricks of the
+// volume as per the policy saved in the transaction context under key
+func setLocalBricksIOLimits(c transaction.TxnCtx, key string) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var policy api.VolIOThrottleResp
+	if err := c.Get(key, &policy); err != nil {
+		return err
+	}
+
+	for _, b := range volinfo.GetLocalBricks() {
+		if err := brick.SetIOLimits(b, volume.BrickIOLimits(&policy, b.ID.String())); err != nil {
+			c.Logger().WithError(err).WithField("brick", b.String()).Error("failed to set I/O limits of brick")
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateIOLimits(l api.IOLimits) error {
+	for _, iops := range []uint64{l.ReadIOPS, l.WriteIOPS} {
+		if iops != 0 && iops < minIOPSLimit {
+			return fmt.Errorf("IOPS limits should be at least %d", minIOPSLimit)
+		}
+	}
+	for _, bps := range []uint64{l.ReadBPS, l.WriteBPS} {
+		if bps != 0 && bps < minBPSLimit {
+			return fmt.Errorf("bytes per second limits should be at least %d", minBPSLimit)
+		}
+	}
+	return nil
+}
+
+func validateVolIOThrottleReq(req *api.VolIOThrottleReq, volinfo *volume.Volinfo) error {
+	if err := validateIOLimits(req.IOLimits); err != nil {
+		return err
+	}
+
+	bricks := make(map[string]bool)
+	for _, b := range volinfo.GetBricks() {
+		bricks[b.ID.String()] = true
+	}
+	for id, l := range req.Bricks {
+		if !bricks[id] {
+			return fmt.Errorf("brick %s is not a brick of volume %s", id, volinfo.Name)
+		}
+		if err := validateIOLimits(l); err != nil {
+			return fmt.Errorf("brick %s: %s", id, err)
+		}
+	}
+
+	return nil
+}
+
+// setVolumeIOLimits sets the I/O limits of the running bricks of the volume
+// as per the policy, and saves the policy. A nil policy removes the limits
+// and the saved policy.
+func setVolumeIOLimits(ctx context.Context, volname string, policy *api.VolIOThrottleResp) error {
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return err
+	}
+
+	oldPolicy, err := volume.GetIOThrottlePolicy(volname)
+	hadPolicy := err == nil
+	if err == gderrors.ErrIOThrottlePolicyNotFound {
+		oldPolicy = &api.VolIOThrottleResp{}
+	} else if err != nil {
+		return err
+	}
+
+	if policy == nil {
+		policy = &api.VolIOThrottleResp{}
+		err = volume.DeleteIOThrottlePolicy(volname)
+	} else {
+		err = volume.SetIOThrottlePolicy(volname, policy)
+	}
+	if err != nil {
+		return err
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "vol-iothrottle.SetIOLimits",
+			UndoFunc: "vol-iothrottle.SetIOLimits.Undo",
+			Nodes:    volinfo.Nodes(),
+		},
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return err
+	}
+	if err := txn.Ctx.Set("policy", policy); err != nil {
+		return err
+	}
+	if err := txn.Ctx.Set("oldpolicy", oldPolicy); err != nil {
+		return err
+	}
+
+	if err := txn.Do(); err != nil {
+		txn.Ctx.Logger().WithError(err).Error("failed to set I/O limits of volume")
+		// Bricks started meanwhile read the policy from the store, so
+		// the old policy is saved back only once the limits are undone
+		serr := volume.DeleteIOThrottlePolicy(volname)
+		if hadPolicy {
+			serr = volume.SetIOThrottlePolicy(volname, oldPolicy)
+		}
+		if serr != nil {
+			txn.Ctx.Logger().WithError(serr).Error("failed to restore I/O throttling policy of volume")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func volumeIOThrottleSetHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolIOThrottleReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := validateVolIOThrottleReq(&req, volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	policy := api.VolIOThrottleResp(req)
+	if err := setVolumeIOLimits(ctx, volname, &policy); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("volume", volname).Info("volume I/O throttling policy set")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, policy)
+}
+
+func volumeIOThrottleGetHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	policy, err := volume.GetIOThrottlePolicy(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, policy)
+}
+
+func volumeIOThrottleDeleteHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	if _, err := volume.GetIOThrottlePolicy(volname); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := setVolumeIOLimits(ctx, volname, nil); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,51 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateVolIOThrottleReq(t *testing.T) {
+	id := uuid.NewRandom()
+	volinfo := &volume.Volinfo{
+		Name: "vol1",
+		Subvols: []volume.Subvol{
+			{Bricks: []brick.Brickinfo{{ID: id, Path: "/b1"}}},
+		},
+	}
+
+	req := &api.VolIOThrottleReq{IOLimits: api.IOLimits{ReadIOPS: 100, WriteBPS: minBPSLimit}}
+	assert.Nil(t, validateVolIOThrottleReq(req, volinfo))
+
+	req.Bricks = map[string]api.IOLimits{id.String(): {WriteIOPS: 50}}
+	assert.Nil(t, validateVolIOThrottleReq(req, volinfo))
+
+	// Limits too low
+	req.Bricks[id.String()] = api.IOLimits{WriteIOPS: 1}
+	assert.NotNil(t, validateVolIOThrottleReq(req, volinfo))
+	req.Bricks = nil
+	req.ReadBPS = 512
+	assert.NotNil(t, validateVolIOThrottleReq(req, volinfo))
+	req.ReadBPS = 0
+
+	// Not a brick of the volume
+	req.Bricks = map[string]api.IOLimits{uuid.NewRandom().String(): {}}
+	assert.NotNil(t, validateVolIOThrottleReq(req, volinfo))
+}
+
+func TestBrickIOLimits(t *testing.T) {
+	policy := &api.VolIOThrottleResp{
+		IOLimits: api.IOLimits{ReadIOPS: 100},
+		Bricks:   map[string]api.IOLimits{"b2": {WriteIOPS: 200}},
+	}
+
+	assert.Equal(t, api.IOLimits{ReadIOPS: 100}, volume.BrickIOLimits(policy, "b1"))
+	assert.Equal(t, api.IOLimits{WriteIOPS: 200}, volume.BrickIOLimits(policy, "b2"))
+	assert.Equal(t, api.IOLimits{}, volume.BrickIOLimits(nil, "b1"))
+}
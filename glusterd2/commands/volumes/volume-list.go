@@ -2,8 +2,11 @@ package volumecommands
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/volume"
@@ -18,17 +21,68 @@ func volumeListHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, span := trace.StartSpan(ctx, "/volumeListHandler")
 	defer span.End()
 
-	keys, keyFound := r.URL.Query()["key"]
-	values, valueFound := r.URL.Query()["value"]
+	if r.URL.Query().Get("watch") == "true" {
+		volumeWatchHandler(w, r)
+		return
+	}
+
+	if r.URL.Query().Get("deleted") == "true" {
+		volumeTrashListHandler(w, r)
+		return
+	}
+
 	filterParams := make(map[string]string)
+	for _, param := range volume.FilterParams {
+		if values, found := r.URL.Query()[param]; found {
+			filterParams[param] = values[0]
+		}
+	}
+	if err := volume.ValidateFilter(filterParams); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
 
-	if keyFound {
-		filterParams["key"] = keys[0]
+	// The revision is read before listing, so that watching from it
+	// doesn't miss any change made after the listing
+	rev, err := volume.CurrentRevision(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
 	}
-	if valueFound {
-		filterParams["value"] = values[0]
+
+	opts := volume.ListOptions{
+		Continue: r.URL.Query().Get("continue"),
+		Filter:   filterParams,
+	}
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err := strconv.ParseInt(l, 10, 64)
+		if err != nil || limit <= 0 {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.New("invalid limit "+l))
+			return
+		}
+		opts.Limit = limit
+	}
+
+	fields, err := parseListFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	var (
+		resp interface{}
+		next string
+		n    int
+	)
+	if len(fields) != 0 {
+		var volumes []*volume.VolumeSummary
+		volumes, next, err = volume.GetVolumeSummaries(ctx, opts)
+		resp, n = createVolumeListFieldsResp(volumes, fields), len(volumes)
+	} else {
+		var volumes []*volume.Volinfo
+		volumes, next, err = volume.GetVolumesPage(ctx, opts)
+		resp, n = createVolumeListResp(ctx, volumes), len(volumes)
 	}
-	volumes, err := volume.GetVolumes(ctx, filterParams)
 	if err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
@@ -36,10 +90,134 @@ func volumeListHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Add the count of volumes being listed as an attribute in the span
 	span.AddAttributes(
-		trace.StringAttribute("numVols", strconv.Itoa(len(volumes))),
+		trace.StringAttribute("numVols", strconv.Itoa(n)),
 	)
 
-	resp := createVolumeListResp(ctx, volumes)
+	w.Header().Set(revisionHeader, strconv.FormatInt(rev, 10))
+	if next != "" {
+		w.Header().Set(continueHeader, next)
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// listFields are the fields of volumes which can be selected when listing
+// volumes, as they are available in volume summaries
+var listFields = map[string]bool{
+	"id":       true,
+	"name":     true,
+	"type":     true,
+	"state":    true,
+	"metadata": true,
+}
+
+// parseListFields parses the comma separated list of fields selected when
+// listing volumes
+func parseListFields(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	fields := strings.Split(s, ",")
+	for _, f := range fields {
+		if !listFields[f] {
+			return nil, errors.New("invalid field " + f)
+		}
+	}
+	return fields, nil
+}
+
+func createVolumeListFieldsResp(volumes []*volume.VolumeSummary, fields []string) *api.VolumeListFieldsResp {
+	resp := make(api.VolumeListFieldsResp, len(volumes))
+
+	for index, v := range volumes {
+		m := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			switch f {
+			case "id":
+				m[f] = v.ID
+			case "name":
+				m[f] = v.Name
+			case "type":
+				m[f] = api.VolType(v.Type)
+			case "state":
+				m[f] = api.VolState(v.State)
+			case "metadata":
+				m[f] = v.Metadata
+			}
+		}
+		resp[index] = m
+	}
+
+	return &resp
+}
+
+const (
+	// revisionHeader is the response header carrying the store revision
+	// of a volume listing, to watch for changes made after the listing
+	revisionHeader = "X-Gluster-Store-Revision"
+	// continueHeader is the response header carrying the continue token
+	// for the next page of a volume listing
+	continueHeader = "X-Gluster-Continue"
+
+	defaultWatchTimeout = 20 * time.Second
+	// maxWatchTimeout must be within the write timeout of the REST server
+	maxWatchTimeout = 25 * time.Second
+)
+
+// volumeWatchHandler waits for changes to volumes after the revision given
+// by the from-revision query parameter and sends them as they happen. If no
+// change happens within the timeout, an empty list of changes is sent.
+func volumeWatchHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	fromRev, err := strconv.ParseInt(query.Get("from-revision"), 10, 64)
+	if err != nil || fromRev < 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.New("a valid from-revision is required to watch volumes"))
+		return
+	}
+
+	timeout := defaultWatchTimeout
+	if t := query.Get("timeout"); t != "" {
+		secs, err := strconv.Atoi(t)
+		if err != nil || secs <= 0 {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.New("invalid timeout "+t))
+			return
+		}
+		timeout = time.Duration(secs) * time.Second
+		if timeout > maxWatchTimeout {
+			timeout = maxWatchTimeout
+		}
+	}
+
+	wctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	changes, rev, err := volume.WatchVolumes(wctx, fromRev)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := api.VolumeWatchResp{
+		Revision: rev,
+		Events:   make([]api.VolumeWatchEvent, 0, len(changes)),
+	}
+	for _, c := range changes {
+		ev := api.VolumeWatchEvent{
+			Type:     api.VolumeWatchDelete,
+			Name:     c.Name,
+			Revision: c.Revision,
+		}
+		if c.Volinfo != nil {
+			ev.Type = api.VolumeWatchPut
+			ev.Volume = createVolumeGetResp(c.Volinfo)
+		}
+		resp.Events = append(resp.Events, ev)
+	}
+
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
 
@@ -0,0 +1,170 @@
+package volumecommands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/plugins/glustershd"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	metricsJobName     = "volume.metrics"
+	metricsJobSchedule = "@every 5m"
+
+	defaultMetricsRange = 24 * time.Hour
+)
+
+func registerMetricsJob() {
+	err := scheduler.Register(&scheduler.Job{
+		Name:        metricsJobName,
+		Description: "Records the capacity, brick up counts and heal backlog of volumes",
+		Schedule:    metricsJobSchedule,
+		Enabled:     true,
+		Func:        sampleVolumesMetrics,
+	})
+	if err != nil {
+		log.WithError(err).WithField("job", metricsJobName).Error("failed to register scheduled job")
+	}
+}
+
+func sampleVolumesMetrics(ctx context.Context) error {
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, v := range volumes {
+		if err := sampleVolumeMetrics(ctx, v); err != nil {
+			log.WithError(err).WithField("volume", v.Name).Warn("failed to record volume metrics")
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		return errors.New("failed to record metrics of " + strconv.Itoa(failed) + " volume(s)")
+	}
+	return nil
+}
+
+// sampleVolumeMetrics records a metrics sample of the volume and prunes the
+// samples older than the retention period. Metrics which cannot be collected
+// are left unset in the sample.
+func sampleVolumeMetrics(ctx context.Context, volinfo *volume.Volinfo) error {
+	ctx = gdctx.WithVolName(gdctx.WithReqID(ctx, uuid.NewRandom()), volinfo.Name)
+	logger := gdctx.Logger(ctx)
+
+	now := time.Now()
+	sample := &api.VolMetricsSample{
+		Time:        now,
+		BricksTotal: len(volinfo.GetBricks()),
+	}
+
+	if volinfo.State == volume.VolStarted {
+		if usage, err := volume.UsageInfo(volinfo.Name); err != nil {
+			logger.WithError(err).Debug("failed to get volume usage")
+		} else {
+			sample.Capacity = usage.Capacity
+			sample.Used = usage.Used
+		}
+
+		if statuses, err := getBricksStatus(ctx, volinfo); err != nil {
+			logger.WithError(err).Debug("failed to get brick statuses")
+		} else {
+			for _, s := range *statuses {
+				if s.Online {
+					sample.BricksOnline++
+				}
+			}
+		}
+
+		if backlog, ok, err := glustershd.HealBacklog(volinfo); err != nil {
+			logger.WithError(err).Debug("failed to get heal backlog")
+		} else if ok {
+			sample.HealBacklog = &backlog
+		}
+	}
+
+	if err := volume.AddMetricsSample(volinfo.Name, sample); err != nil {
+		return err
+	}
+
+	return volume.PruneMetricsSamples(volinfo.Name, now.Add(-volume.MetricsRetention))
+}
+
+// parseMetricsRange parses the range of a metrics request. In addition to
+// the durations understood by time.ParseDuration, a number of days like 7d
+// is accepted.
+func parseMetricsRange(s string) (time.Duration, error) {
+	if s == "" {
+		return defaultMetricsRange, nil
+	}
+
+	var d time.Duration
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, errors.New("invalid range " + s)
+		}
+		d = time.Duration(days) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, errors.New("invalid range " + s)
+		}
+	}
+
+	if d <= 0 {
+		return 0, errors.New("range must be positive")
+	}
+	if d > volume.MetricsRetention {
+		d = volume.MetricsRetention
+	}
+
+	return d, nil
+}
+
+func volumeMetricsHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	d, err := parseMetricsRange(r.URL.Query().Get("range"))
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := volume.GetVolume(volname); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	from := time.Now().Add(-d)
+	samples, err := volume.GetMetricsSamples(volname, from)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := &api.VolMetricsResp{
+		Volume:  volname,
+		From:    from,
+		Samples: samples,
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,34 @@
+package volumecommands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMetricsRange(t *testing.T) {
+	d, err := parseMetricsRange("")
+	assert.Nil(t, err)
+	assert.Equal(t, defaultMetricsRange, d)
+
+	d, err = parseMetricsRange("90m")
+	assert.Nil(t, err)
+	assert.Equal(t, 90*time.Minute, d)
+
+	d, err = parseMetricsRange("2d")
+	assert.Nil(t, err)
+	assert.Equal(t, 48*time.Hour, d)
+
+	// Capped at the retention period
+	d, err = parseMetricsRange("30d")
+	assert.Nil(t, err)
+	assert.Equal(t, volume.MetricsRetention, d)
+
+	for _, r := range []string{"abc", "xd", "-1h", "0s"} {
+		_, err = parseMetricsRange(r)
+		assert.NotNil(t, err, r)
+	}
+}
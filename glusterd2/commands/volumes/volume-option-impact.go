@@ -0,0 +1,250 @@
+package volumecommands
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// xlatorAliases maps the names xlators are also loaded as to the names used in
+// the volfile templates
+var xlatorAliases = map[string]string{
+	"afr":           "replicate",
+	"ec":            "disperse",
+	"dht":           "distribute",
+	"stat-prefetch": "md-cache",
+	"posix-locks":   "locks",
+	"posix-acl":     "access-control",
+}
+
+// impactDaemons maps the volfiles of the daemons run for a volume to the
+// names of the daemons, and the checks for whether they run for the volume
+var impactDaemons = []struct {
+	graph   string
+	process string
+	running func(*volume.Volinfo) bool
+}{
+	{utils.SelfHealVolfile, "glustershd", func(v *volume.Volinfo) bool {
+		switch v.Type {
+		case volume.Replicate, volume.Disperse, volume.DistReplicate, volume.DistDisperse:
+			return v.Options["cluster/replicate.self-heal-daemon"] == "on"
+		}
+		return false
+	}},
+	{utils.BitdVolfile, "bitd", isBitrotOn},
+	{utils.ScrubdVolfile, "scrubd", isBitrotOn},
+	{utils.GfProxyVolfile, "gfproxyd", func(v *volume.Volinfo) bool {
+		return v.Metadata["_gfproxy"] == "on"
+	}},
+}
+
+func isBitrotOn(v *volume.Volinfo) bool {
+	return v.Options["features/bit-rot"] == "on"
+}
+
+// impactXlatorID returns the name of the xlator of the option key as used in
+// the volfile templates
+func impactXlatorID(xl string) string {
+	id := path.Base(xl)
+	if alias, ok := xlatorAliases[id]; ok {
+		return alias
+	}
+	return id
+}
+
+// isQuorumOption returns true if the option of the xlator decides client
+// quorum, and isHealOption if it controls self-heal
+func isQuorumOption(xl, key string) bool {
+	switch impactXlatorID(xl) {
+	case "replicate", "disperse":
+		return strings.Contains(key, "quorum")
+	}
+	return false
+}
+
+func isHealOption(xl, key string) bool {
+	switch impactXlatorID(xl) {
+	case "replicate", "disperse":
+		return strings.Contains(key, "heal")
+	}
+	return false
+}
+
+// graphXlators returns the xlators, by volfile, in the volfiles generated for
+// the volume
+func graphXlators(volinfo *volume.Volinfo) (map[string][]string, error) {
+	graphs := make(map[string][]string)
+	for _, name := range utils.ValidVolfiles {
+		tmpl, err := volgen.GetTemplateFromVolinfo(volinfo, name)
+		if err != nil {
+			// No volfile is generated from missing templates
+			continue
+		}
+		types, err := tmpl.EnabledXlatorTypes(volinfo)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range types {
+			graphs[name] = append(graphs[name], impactXlatorID(t))
+		}
+	}
+	return graphs, nil
+}
+
+// optionImpact analyses the impact of changing the options of the volume from
+// those in volinfo to those in newvolinfo. The xlators enabled in either are
+// considered, so that enabling or disabling xlators is accounted for.
+func optionImpact(volinfo, newvolinfo *volume.Volinfo, opts map[string]string) (*api.OptionImpactResp, error) {
+	before, err := graphXlators(volinfo)
+	if err != nil {
+		return nil, err
+	}
+	after, err := graphXlators(newvolinfo)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.OptionImpactResp{Volume: volinfo.Name, Options: []api.OptionImpact{}}
+	affected := make(map[string][]string)
+
+	keys := optionKeys(opts)
+	sort.Strings(keys)
+	for _, k := range keys {
+		graphName, xl, key := options.SplitKey(k)
+		id := impactXlatorID(xl)
+
+		impact := api.OptionImpact{
+			Option:         k,
+			Value:          opts[k],
+			Reconfigurable: xlator.AllReconfigurable([]string{k}),
+		}
+		for _, name := range utils.ValidVolfiles {
+			if graphName != "" && graphName != name {
+				continue
+			}
+			if utils.StringInSlice(id, before[name]) || utils.StringInSlice(id, after[name]) {
+				impact.Graphs = append(impact.Graphs, name)
+				affected[name] = append(affected[name], k)
+			}
+		}
+		resp.Options = append(resp.Options, impact)
+
+		resp.QuorumAffected = resp.QuorumAffected || isQuorumOption(xl, key)
+		resp.HealAffected = resp.HealAffected || isHealOption(xl, key)
+
+		if xltr, err := xlator.Find(xl); err == nil && xltr.Actor != nil && volinfo.State == volume.VolStarted {
+			resp.Warnings = append(resp.Warnings,
+				fmt.Sprintf("%s is acted upon by %s, which may start or stop daemons", k, xltr.ID))
+		}
+	}
+
+	resp.ClientGraphChange = len(affected[utils.ClientVolfile]) > 0
+
+	if volinfo.State != volume.VolStarted {
+		resp.Warnings = append(resp.Warnings, "volume is not started, the options are applied when it is started")
+		return resp, nil
+	}
+
+	if keys := affected[utils.BrickVolfile]; len(keys) > 0 {
+		action := api.ImpactReconfigure
+		if !xlator.AllReconfigurable(keys) {
+			action = api.ImpactRestart
+		}
+		for _, b := range volinfo.GetBricks() {
+			resp.Processes = append(resp.Processes, api.ProcessImpact{
+				PeerID:  b.PeerID.String(),
+				Process: "brick",
+				Name:    b.Path,
+				Volume:  volinfo.Name,
+				Action:  action,
+			})
+		}
+	}
+
+	for _, d := range impactDaemons {
+		if len(affected[d.graph]) == 0 || !(d.running(volinfo) || d.running(newvolinfo)) {
+			continue
+		}
+		for _, node := range volinfo.Nodes() {
+			resp.Processes = append(resp.Processes, api.ProcessImpact{
+				PeerID:  node.String(),
+				Process: d.process,
+				Volume:  volinfo.Name,
+				Action:  api.ImpactReconfigure,
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+// volumeOptionsImpactHandler reports the impact of setting the options of the
+// volume, without setting them
+func volumeOptionsImpactHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolOptionReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if containsReservedGroupProfile(req.Options) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrReservedGroupProfile)
+		return
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	opts, err := expandGroupOptions(req.Options)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	if err := validateOptions(opts, req.VolOptionFlags); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Sprintf("volume option: %s", err))
+		return
+	}
+	if err := validateXlatorOptions(opts, volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Sprintf("volume option: %s", err))
+		return
+	}
+
+	newvolinfo := *volinfo
+	newvolinfo.Options = copyOptions(volinfo.Options)
+	for k, v := range opts {
+		newvolinfo.Options[k] = v
+	}
+	if err := volume.RunValidators(volume.ValidateOptionSet, &newvolinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := optionImpact(volinfo, &newvolinfo, opts)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,25 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpactXlatorID(t *testing.T) {
+	assert.Equal(t, "replicate", impactXlatorID("cluster/replicate"))
+	assert.Equal(t, "replicate", impactXlatorID("afr"))
+	assert.Equal(t, "md-cache", impactXlatorID("performance/stat-prefetch"))
+	assert.Equal(t, "io-threads", impactXlatorID("io-threads"))
+}
+
+func TestQuorumAndHealOptions(t *testing.T) {
+	assert.True(t, isQuorumOption("cluster/replicate", "quorum-type"))
+	assert.True(t, isQuorumOption("ec", "quorum-count"))
+	assert.False(t, isQuorumOption("io-threads", "quorum-type"))
+	assert.False(t, isQuorumOption("afr", "eager-lock"))
+
+	assert.True(t, isHealOption("afr", "self-heal-daemon"))
+	assert.True(t, isHealOption("disperse", "heal-wait-qlength"))
+	assert.False(t, isHealOption("replicate", "quorum-type"))
+}
@@ -36,7 +36,7 @@ func optionSetValidate(c transaction.TxnCtx) error {
 	// validateOptions.
 
 	if err := validateOptions(options, req.VolOptionFlags); err != nil {
-		return fmt.Errorf("validation failed for volume option: %s", err.Error())
+		return transaction.NewValidationError(fmt.Errorf("volume option: %s", err.Error()))
 	}
 
 	var volinfo volume.Volinfo
@@ -45,7 +45,7 @@ func optionSetValidate(c transaction.TxnCtx) error {
 	}
 
 	if err := validateXlatorOptions(options, &volinfo); err != nil {
-		return fmt.Errorf("validation failed for volume option:: %s", err.Error())
+		return transaction.NewValidationError(fmt.Errorf("volume option: %s", err.Error()))
 	}
 
 	for k, v := range options {
@@ -57,6 +57,10 @@ func optionSetValidate(c transaction.TxnCtx) error {
 		volinfo.Options[k] = v
 	}
 
+	if err := volume.RunValidators(volume.ValidateOptionSet, &volinfo); err != nil {
+		return transaction.NewValidationError(err)
+	}
+
 	err = c.Set("volinfo", volinfo)
 
 	return err
@@ -186,6 +190,7 @@ func registerVolOptionStepFuncs() {
 		{"vol-option.NotifyVolfileChange", notifyVolfileChange},
 		{"vol-option.GenerateBrickVolfiles", txnGenerateBrickVolfiles},
 		{"vol-option.GenerateBrickvolfiles.Undo", txnDeleteBrickVolfiles},
+		{"vol-option.ReconfigureBricks", txnReconfigureBricks},
 	}
 	for _, sf := range sfs {
 		transaction.RegisterStepFunc(sf.sf, sf.name)
@@ -197,7 +202,7 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	ctx, span := trace.StartSpan(ctx, "/volumeOptionsHandler")
 	defer span.End()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	volname := mux.Vars(r)["volname"]
 
 	var req api.VolOptionReq
@@ -226,6 +231,8 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	oldOptions := copyOptions(volinfo.Options)
+
 	//save volume information for transaction failure scenario
 	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
@@ -259,6 +266,10 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 			UndoFunc: "vol-option.GenerateBrickvolfiles.Undo",
 			Nodes:    volinfo.Nodes(),
 		},
+		{
+			DoFunc: "vol-option.ReconfigureBricks",
+			Nodes:  volinfo.Nodes(),
+		},
 		{
 			DoFunc: "vol-option.NotifyVolfileChange",
 			Nodes:  allNodes,
@@ -270,6 +281,16 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	changedOpts, err := expandGroupOptions(req.Options)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	if err := txn.Ctx.Set(reconfigureKeysTxnKey, optionKeys(changedOpts)); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
 	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
@@ -301,10 +322,25 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := volume.AddOptionsHistory(volname, volume.OptionsSet, gdctx.GetReqUser(ctx),
+		txn.Ctx.GetTxnReqID(), oldOptions, volinfo.Options); err != nil {
+		logger.WithError(err).WithField("volume", volname).Warn("failed to record volume options history")
+	}
+
 	resp := createVolumeOptionResp(volinfo)
+	resp.Reconfigure = collectBrickReconfigureStatus(txn.Ctx, volinfo)
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
 
 func createVolumeOptionResp(v *volume.Volinfo) *api.VolumeOptionResp {
-	return (*api.VolumeOptionResp)(volume.CreateVolumeInfoResp(v))
+	return &api.VolumeOptionResp{VolumeInfo: *volume.CreateVolumeInfoResp(v)}
+}
+
+// optionKeys returns the keys of the options map
+func optionKeys(opts map[string]string) []string {
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	return keys
 }
@@ -0,0 +1,183 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+func copyOptions(opts map[string]string) map[string]string {
+	c := make(map[string]string, len(opts))
+	for k, v := range opts {
+		c[k] = v
+	}
+	return c
+}
+
+func volumeOptionsHistoryHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	if _, err := volume.GetVolume(volname); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	entries, err := volume.GetOptionsHistory(volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := api.VolOptionsHistoryResp(entries)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeOptionsRollbackHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolOptionsRollbackReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	entry, err := volume.GetOptionsHistoryEntry(volname, req.ID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// Options that differ from the ones being rolled back to are applied
+	// as a volume set, removed options are set back to their defaults.
+	optReq := api.VolOptionReq{Options: make(map[string]string)}
+	for k, change := range volume.OptionsDiff(volinfo.Options, entry.Options) {
+		if _, ok := entry.Options[k]; ok {
+			optReq.Options[k] = change.New
+			continue
+		}
+		op, err := xlator.FindOption(k)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		optReq.Options[k] = op.DefaultValue
+	}
+
+	if len(optReq.Options) == 0 {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, createVolumeOptionResp(volinfo))
+		return
+	}
+
+	//save volume information for transaction failure scenario
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	oldOptions := volinfo.Options
+	volinfo.Options = copyOptions(entry.Options)
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+		},
+		{
+			DoFunc:   "vol-option.XlatorActionDoSet",
+			UndoFunc: "vol-option.XlatorActionUndoSet",
+			Nodes:    volinfo.Nodes(),
+			Skip:     !isActionStepRequired(optReq.Options, volinfo),
+		},
+		{
+			DoFunc:   "vol-option.GenerateBrickVolfiles",
+			UndoFunc: "vol-option.GenerateBrickvolfiles.Undo",
+			Nodes:    volinfo.Nodes(),
+		},
+		{
+			DoFunc: "vol-option.ReconfigureBricks",
+			Nodes:  volinfo.Nodes(),
+		},
+		{
+			DoFunc: "vol-option.NotifyVolfileChange",
+			Nodes:  allNodes,
+		},
+	}
+
+	if err := txn.Ctx.Set("req", &optReq); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Ctx.Set(reconfigureKeysTxnKey, optionKeys(optReq.Options)); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("volume options rollback transaction failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	volinfo, err = volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := volume.AddOptionsHistory(volname, volume.OptionsRollback, gdctx.GetReqUser(ctx),
+		txn.Ctx.GetTxnReqID(), oldOptions, volinfo.Options); err != nil {
+		logger.WithError(err).WithField("volume", volname).Warn("failed to record volume options history")
+	}
+
+	logger.WithField("volume", volname).WithField("id", req.ID).Info("volume options rolled back")
+	resp := createVolumeOptionResp(volinfo)
+	resp.Reconfigure = collectBrickReconfigureStatus(txn.Ctx, volinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,16 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+)
+
+// volumeOptionsSchemaHandler lists the options which can be set on volumes,
+// with the types, ranges, defaults and allowed values they are validated
+// against on volume set
+func volumeOptionsSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, xlator.OptionDocs(true))
+}
@@ -31,7 +31,7 @@ func registerVolProfileStepFuncs() {
 
 func volumeProfileHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	volname := mux.Vars(r)["volname"]
 	option := mux.Vars(r)["option"]
 
@@ -0,0 +1,97 @@
+package volumecommands
+
+import (
+	"errors"
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func validateVolumeProfile(p *api.VolumeProfile) error {
+	if !volume.IsValidName(p.Name) {
+		return errors.New("invalid volume profile name")
+	}
+
+	if p.ReplicaCount < 0 || p.ArbiterCount < 0 || p.DisperseCount < 0 || p.DisperseRedundancyCount < 0 {
+		return errors.New("subvolume counts cannot be negative")
+	}
+	if p.SnapshotReserveFactor != 0 && p.SnapshotReserveFactor < 1 {
+		return errors.New("invalid snapshot reserve factor")
+	}
+
+	if containsReservedGroupProfile(p.Options) {
+		return gderrors.ErrReservedGroupProfile
+	}
+	opts, err := expandGroupOptions(p.Options)
+	if err != nil {
+		return err
+	}
+	return validateOptions(opts, p.VolOptionFlags)
+}
+
+func volumeProfileCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.VolumeProfile
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := validateVolumeProfile(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := volume.AddVolumeProfile(&req); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, req)
+}
+
+func volumeProfileListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	profiles, err := volume.GetVolumeProfiles()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.VolumeProfileListResp, 0, len(profiles))
+	for _, p := range profiles {
+		resp = append(resp, *p)
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeProfileGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, err := volume.GetVolumeProfile(mux.Vars(r)["profilename"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, p)
+}
+
+func volumeProfileDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := volume.DeleteVolumeProfile(mux.Vars(r)["profilename"]); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
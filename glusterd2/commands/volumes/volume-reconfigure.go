@@ -0,0 +1,102 @@
+package volumecommands
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	brickReconfigureTxnKey = "brickreconfigure"
+	// reconfigureKeysTxnKey holds the keys of the options changed by the
+	// transaction
+	reconfigureKeysTxnKey = "reconfigurekeys"
+
+	// brickReconfigureTimeout is the time to wait for a brick to fetch
+	// its new volfile
+	brickReconfigureTimeout = 10 * time.Second
+)
+
+// txnReconfigureBricks pushes the changed options to the running local bricks
+// of the volume. The bricks fetch the regenerated volfile and apply the
+// options without being restarted if all the changed options are
+// reconfigurable. The outcome for every brick is reported as the node result.
+func txnReconfigureBricks(c transaction.TxnCtx) error {
+
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	if volinfo.State != volume.VolStarted {
+		return nil
+	}
+
+	var keys []string
+	if err := c.Get(reconfigureKeysTxnKey, &keys); err != nil {
+		return err
+	}
+
+	bricks := volinfo.GetLocalBricks()
+	statuses := make([]api.BrickReconfigureStatus, len(bricks))
+
+	if !xlator.AllReconfigurable(keys) {
+		for i := range bricks {
+			statuses[i] = api.BrickReconfigureStatus{
+				Info:   brick.CreateBrickInfo(&bricks[i]),
+				Status: api.BrickRestartRequired,
+			}
+		}
+		return c.SetNodeResult(gdctx.MyUUID, brickReconfigureTxnKey, statuses)
+	}
+
+	var wg sync.WaitGroup
+	for i := range bricks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b := &bricks[i]
+			statuses[i] = api.BrickReconfigureStatus{
+				Info:   brick.CreateBrickInfo(b),
+				Status: api.BrickReconfigured,
+			}
+
+			volfileID := brick.GetVolfileID(b.VolumeName, b.Path)
+			if err := sunrpc.ReconfigureClient(volfileID, brickReconfigureTimeout); err != nil {
+				c.Logger().WithError(err).WithFields(log.Fields{
+					"brick":      b.String(),
+					"volfile-id": volfileID,
+				}).Warn("failed to reconfigure brick")
+				statuses[i].Status = api.BrickReconfigureFailed
+				statuses[i].Error = err.Error()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return c.SetNodeResult(gdctx.MyUUID, brickReconfigureTxnKey, statuses)
+}
+
+// collectBrickReconfigureStatus aggregates the brick reconfigure results
+// reported by the nodes of the volume
+func collectBrickReconfigureStatus(c transaction.TxnCtx, volinfo *volume.Volinfo) []api.BrickReconfigureStatus {
+	var statuses []api.BrickReconfigureStatus
+	for _, node := range volinfo.Nodes() {
+		var tmp []api.BrickReconfigureStatus
+		if err := c.GetNodeResult(node, brickReconfigureTxnKey, &tmp); err != nil {
+			// skip if we do not have information
+			continue
+		}
+		statuses = append(statuses, tmp...)
+	}
+	return statuses
+}
@@ -0,0 +1,144 @@
+package volumecommands
+
+import (
+	"net/http"
+	"strconv"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/plugins/device/deviceutils"
+
+	"github.com/gorilla/mux"
+)
+
+const brickCapacityTxnKey = "brickcapacity"
+
+// txnBrickCapacity records the capacity of the filesystems of the local
+// bricks and refreshes the free size of the devices of auto provisioned
+// bricks, which changes when their logical volumes are grown.
+func txnBrickCapacity(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var capacities []api.BrickCapacity
+	devices := make(map[string]bool)
+	for _, b := range volinfo.GetLocalBricks() {
+		var fstat syscall.Statfs_t
+		if err := syscall.Statfs(b.Path, &fstat); err != nil {
+			c.Logger().WithError(err).WithField("brick", b.Path).Error("failed to get brick capacity")
+			return err
+		}
+
+		capacities = append(capacities, api.BrickCapacity{
+			ID:       b.ID,
+			Hostname: b.Hostname,
+			Path:     b.Path,
+			Capacity: brick.CreateSizeInfo(&fstat).Capacity,
+		})
+
+		if b.PType.IsAutoProvisioned() && b.RootDevice != "" && !devices[b.RootDevice] {
+			devices[b.RootDevice] = true
+			if err := deviceutils.UpdateDeviceFreeSize(gdctx.MyUUID.String(), b.RootDevice); err != nil {
+				c.Logger().WithError(err).WithField("device", b.RootDevice).Warn("failed to refresh device free size")
+			}
+		}
+	}
+
+	return c.SetNodeResult(gdctx.MyUUID, brickCapacityTxnKey, capacities)
+}
+
+func registerVolRefreshSizeStepFuncs() {
+	transaction.RegisterStepFunc(txnBrickCapacity, "vol-refresh-size.BrickCapacity")
+}
+
+func volumeRefreshSizeHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "vol-refresh-size.BrickCapacity",
+			Nodes:  volinfo.Nodes(),
+		},
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to get brick capacities")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := api.VolumeRefreshSizeResp{
+		Volume:      volname,
+		OldCapacity: volinfo.Capacity,
+		Bricks:      []api.BrickCapacity{},
+	}
+	brickCapacity := make(map[string]uint64)
+	for _, node := range volinfo.Nodes() {
+		var tmp []api.BrickCapacity
+		if err := txn.Ctx.GetNodeResult(node, brickCapacityTxnKey, &tmp); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		for _, b := range tmp {
+			brickCapacity[b.ID.String()] = b.Capacity
+		}
+		resp.Bricks = append(resp.Bricks, tmp...)
+	}
+	resp.Capacity = volume.CalculateCapacity(volinfo, brickCapacity)
+
+	if resp.Capacity != volinfo.Capacity {
+		// The volume may have been changed while the capacities of the
+		// bricks were got, so the capacity is calculated again for the
+		// latest volinfo
+		volinfo, err = volume.UpdateVolume(volname, 0, func(v *volume.Volinfo) error {
+			v.Capacity = volume.CalculateCapacity(v, brickCapacity)
+			return nil
+		})
+		if err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		resp.Capacity = volinfo.Capacity
+
+		logger.WithField("volume", volname).WithField("capacity", resp.Capacity).Info("volume capacity changed")
+		e := volume.NewEvent(volume.EventVolumeCapacityChanged, volinfo)
+		e.Data["volume.old-capacity"] = strconv.FormatUint(resp.OldCapacity, 10)
+		e.Data["volume.capacity"] = strconv.FormatUint(resp.Capacity, 10)
+		events.Broadcast(e)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
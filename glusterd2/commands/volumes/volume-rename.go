@@ -0,0 +1,232 @@
+package volumecommands
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// renameVolume moves the volinfo in the store to the new name. The volinfo
+// with its new store revision is saved for the undo.
+func renameVolume(c transaction.TxnCtx) error {
+	var volinfo, newvolinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+	if err := c.Get("newvolinfo", &newvolinfo); err != nil {
+		return err
+	}
+
+	if err := volume.RenameVolume(volinfo.Name, &newvolinfo); err != nil {
+		return err
+	}
+	return c.Set("newvolinfo", &newvolinfo)
+}
+
+func undoRenameVolume(c transaction.TxnCtx) error {
+	var volinfo, newvolinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+	if err := c.Get("newvolinfo", &newvolinfo); err != nil {
+		return err
+	}
+
+	// Not renamed if the store revision wasn't updated
+	if newvolinfo.ModRevision == volinfo.ModRevision {
+		return nil
+	}
+	volinfo.ModRevision = newvolinfo.ModRevision
+	return volume.RenameVolume(newvolinfo.Name, &volinfo)
+}
+
+// txnGenerateRenamedBrickVolfiles generates the brick volfiles with the
+// volfile IDs of the renamed volume
+func txnGenerateRenamedBrickVolfiles(c transaction.TxnCtx) error {
+	var newvolinfo volume.Volinfo
+	if err := c.Get("newvolinfo", &newvolinfo); err != nil {
+		return err
+	}
+
+	err := volgen.GenerateBricksVolfiles(&newvolinfo, newvolinfo.GetLocalBricks())
+	if err != nil {
+		c.Logger().WithError(err).WithFields(log.Fields{
+			"template": "brick",
+			"volume":   newvolinfo.Name,
+		}).Error("failed to generate volfile")
+		return err
+	}
+	return nil
+}
+
+func txnDeleteRenamedBrickVolfiles(c transaction.TxnCtx) error {
+	var newvolinfo volume.Volinfo
+	if err := c.Get("newvolinfo", &newvolinfo); err != nil {
+		return err
+	}
+	return volgen.DeleteBricksVolfiles(newvolinfo.GetLocalBricks())
+}
+
+func registerVolRenameStepFuncs() {
+	var sfs = []struct {
+		name string
+		sf   transaction.StepFunc
+	}{
+		{"vol-rename.DeleteBrickVolfiles", txnDeleteBrickVolfiles},
+		{"vol-rename.DeleteBrickVolfiles.Undo", txnGenerateBrickVolfiles},
+		{"vol-rename.Store", renameVolume},
+		{"vol-rename.Store.Undo", undoRenameVolume},
+		{"vol-rename.GenerateBrickVolfiles", txnGenerateRenamedBrickVolfiles},
+		{"vol-rename.GenerateBrickVolfiles.Undo", txnDeleteRenamedBrickVolfiles},
+	}
+	for _, sf := range sfs {
+		transaction.RegisterStepFunc(sf.sf, sf.name)
+	}
+}
+
+func volumeRenameHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolRenameReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := volume.ValidateName(req.NewName); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	if req.NewName == volname {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "new name is the same as the current name")
+		return
+	}
+
+	// Both names are locked, so that the volume isn't created or operated
+	// upon under either name during the rename
+	txn, err := transaction.NewTxnWithLocks(ctx, volname, req.NewName)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if volinfo.State == volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Volume must be in stopped state before renaming.")
+		return
+	}
+
+	if err := volume.CheckAdvisoryLocks(volname); err != nil {
+		sendAdvisoryLockError(w, r, err)
+		return
+	}
+
+	// Snapshots refer to their parent volume by its name
+	if len(volinfo.SnapList) > 0 {
+		errMsg := fmt.Sprintf("Cannot rename Volume %s, as it has %d snapshots.", volname, len(volinfo.SnapList))
+		restutils.SendHTTPError(ctx, w, http.StatusFailedDependency, errMsg)
+		return
+	}
+
+	// The pending self-heals recorded on the bricks of replicate volumes are
+	// named after the volume, and are not healed once it is renamed
+	if (volinfo.Type == volume.Replicate || volinfo.Type == volume.DistReplicate) && !req.Force {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+			"pending self-heals of replicate volumes are lost on rename, ensure there are none and use force to rename")
+		return
+	}
+
+	newvolinfo := volume.RenamedVolinfo(volinfo, req.NewName)
+	if err := volume.RunValidators(volume.ValidateRename, newvolinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	release, err := volume.ReserveName(req.NewName)
+	if err == gderrors.ErrVolExists {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer release()
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "vol-rename.DeleteBrickVolfiles",
+			UndoFunc: "vol-rename.DeleteBrickVolfiles.Undo",
+			Nodes:    volinfo.Nodes(),
+		},
+		{
+			DoFunc:   "vol-rename.Store",
+			UndoFunc: "vol-rename.Store.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+		{
+			DoFunc:   "vol-rename.GenerateBrickVolfiles",
+			UndoFunc: "vol-rename.GenerateBrickVolfiles.Undo",
+			Nodes:    volinfo.Nodes(),
+		},
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("newvolinfo", newvolinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithFields(log.Fields{
+			"volume":   volname,
+			"new-name": req.NewName,
+		}).Error("transaction to rename volume failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// The metrics samples are not moved, as there can be too many of them
+	// to be moved in a single store transaction
+	if err := volume.DeleteMetricsSamples(volname); err != nil {
+		logger.WithError(err).WithField("volume", volname).Warn("failed to delete volume metrics")
+	}
+
+	if err := txn.Ctx.Get("newvolinfo", newvolinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	events.Broadcast(volume.NewEvent(volume.EventVolumeRenamed, newvolinfo))
+
+	resp := (*api.VolumeRenameResp)(volume.CreateVolumeInfoResp(newvolinfo))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
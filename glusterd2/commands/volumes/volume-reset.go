@@ -33,7 +33,7 @@ func registerVolOptionResetStepFuncs() {
 func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	var err error
 
 	var req api.VolOptionResetReq
@@ -70,6 +70,9 @@ func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// volinfo.Options is modified in place below
+	oldOptions := copyOptions(volinfo.Options)
+
 	req.Options, err = expandGroupOptionsReset(req.Options)
 	if err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
@@ -198,5 +201,10 @@ func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := volume.AddOptionsHistory(volname, volume.OptionsReset, gdctx.GetReqUser(ctx),
+		txn.Ctx.GetTxnReqID(), oldOptions, volinfo.Options); err != nil {
+		logger.WithError(err).WithField("volume", volname).Warn("failed to record volume options history")
+	}
+
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, volinfo)
 }
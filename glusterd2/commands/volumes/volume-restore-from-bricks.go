@@ -0,0 +1,332 @@
+package volumecommands
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	config "github.com/spf13/viper"
+)
+
+const recoveredBricksTxnKey = "recovered-bricks"
+
+func registerVolRestoreFromBricksStepFuncs() {
+	transaction.RegisterStepFunc(txnScanBricks, "vol-restore-from-bricks.ScanBricks")
+}
+
+// txnScanBricks finds the bricks of this peer from the brick volfiles left in
+// the volfiles dir, and saves them as the result of this node
+func txnScanBricks(c transaction.TxnCtx) error {
+	bricks, err := scanLocalBricks()
+	if err != nil {
+		c.Logger().WithError(err).Error("failed to scan bricks")
+		return err
+	}
+
+	return c.SetNodeResult(gdctx.MyUUID, recoveredBricksTxnKey, bricks)
+}
+
+// scanLocalBricks reads the brick volfiles of this peer, which are named
+// <volname>.<peer ID>.<brick path>.vol, and reads the volume ID and layout of
+// the bricks. Snapshot bricks are skipped.
+func scanLocalBricks() ([]api.RecoveredBrick, error) {
+	dir := path.Join(config.GetString("localstatedir"), "volfiles")
+	files, err := ioutil.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	snapsDir := path.Join(config.GetString("rundir"), "snaps") + "/"
+	infix := "." + gdctx.MyUUID.String() + "."
+
+	bricks := []api.RecoveredBrick{}
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Name(), ".vol")
+		i := strings.Index(name, infix)
+		if f.IsDir() || name == f.Name() || i <= 0 {
+			continue
+		}
+
+		brickPath, arbiter, err := parseBrickVolfile(path.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if brickPath == "" || strings.HasPrefix(brickPath, snapsDir) {
+			continue
+		}
+
+		b := api.RecoveredBrick{
+			PeerID:     gdctx.MyUUID.String(),
+			Path:       brickPath,
+			VolumeName: name[:i],
+			Arbiter:    arbiter,
+			SubvolType: brick.ReadSubvolType(brickPath),
+		}
+		if id, err := brick.ReadVolumeID(brickPath); err != nil {
+			b.Error = err.Error()
+		} else {
+			b.VolumeID = id.String()
+		}
+		if start, stop, ok := brick.ReadLayout(brickPath); ok {
+			b.Layout = fmt.Sprintf("%08x-%08x", start, stop)
+		}
+		bricks = append(bricks, b)
+	}
+
+	return bricks, nil
+}
+
+// parseBrickVolfile returns the brick path from a brick volfile, which is the
+// name of the io-stats xlator or the directory of the posix xlator, and if
+// the brick is an arbiter
+func parseBrickVolfile(file string) (string, bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	var (
+		name, xltype string
+		brickPath    string
+		directory    string
+		arbiter      bool
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "volume":
+			name = fields[1]
+		case "type":
+			xltype = fields[1]
+			switch xltype {
+			case "debug/io-stats":
+				brickPath = name
+			case "features/arbiter":
+				arbiter = true
+			}
+		case "option":
+			if xltype == "storage/posix" && fields[1] == "directory" && len(fields) > 2 {
+				directory = fields[2]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", false, err
+	}
+
+	if directory != "" {
+		brickPath = directory
+	}
+	return brickPath, arbiter, nil
+}
+
+// buildRecoveryProposals groups the recovered bricks into volumes, and the
+// bricks of each volume into subvolumes by their DHT hash range. The order of
+// the bricks within a subvolume cannot be found from the bricks, so they are
+// sorted by peer and path.
+func buildRecoveryProposals(bricks []api.RecoveredBrick) []api.VolRecoveryProposal {
+	byVolume := make(map[string]*api.VolRecoveryProposal)
+	var keys []string
+	for _, b := range bricks {
+		key := b.VolumeName + "/" + b.VolumeID
+		p, ok := byVolume[key]
+		if !ok {
+			p = &api.VolRecoveryProposal{Name: b.VolumeName, ID: b.VolumeID}
+			byVolume[key] = p
+			keys = append(keys, key)
+		}
+		p.Bricks = append(p.Bricks, b)
+	}
+	sort.Strings(keys)
+
+	names := make(map[string]int)
+	for _, p := range byVolume {
+		names[p.Name]++
+	}
+
+	proposals := make([]api.VolRecoveryProposal, 0, len(keys))
+	for _, key := range keys {
+		p := byVolume[key]
+		if names[p.Name] > 1 {
+			addRecoveryWarning(p, fmt.Sprintf("bricks of volume %s belong to %d different volume IDs", p.Name, names[p.Name]))
+		}
+		buildRecoveryRequest(p)
+		proposals = append(proposals, *p)
+	}
+	return proposals
+}
+
+// addRecoveryWarning adds the warning to the proposal, once
+func addRecoveryWarning(p *api.VolRecoveryProposal, warning string) {
+	for _, w := range p.Warnings {
+		if w == warning {
+			return
+		}
+	}
+	p.Warnings = append(p.Warnings, warning)
+}
+
+func buildRecoveryRequest(p *api.VolRecoveryProposal) {
+	sort.Slice(p.Bricks, func(i, j int) bool {
+		if p.Bricks[i].PeerID != p.Bricks[j].PeerID {
+			return p.Bricks[i].PeerID < p.Bricks[j].PeerID
+		}
+		return p.Bricks[i].Path < p.Bricks[j].Path
+	})
+
+	p.Req = api.VolCreateReq{
+		Name:    p.Name,
+		Adopt:   true,
+		Subvols: []api.SubvolReq{},
+	}
+	if p.ID == "" {
+		addRecoveryWarning(p, "volume ID could not be read from the bricks, the volume cannot be adopted")
+		return
+	}
+
+	groups := make(map[string][]api.RecoveredBrick)
+	var layouts []string
+	for _, b := range p.Bricks {
+		layout := b.Layout
+		if layout == "" {
+			// Without a layout, each brick is taken as a subvolume
+			addRecoveryWarning(p, fmt.Sprintf("brick %s:%s has no layout, taken as a distribute subvolume", b.PeerID, b.Path))
+			layout = "~" + b.PeerID + ":" + b.Path
+		}
+		if _, ok := groups[layout]; !ok {
+			layouts = append(layouts, layout)
+		}
+		groups[layout] = append(groups[layout], b)
+	}
+	sort.Strings(layouts)
+
+	for _, layout := range layouts {
+		p.Req.Subvols = append(p.Req.Subvols, recoverySubvol(p, groups[layout]))
+	}
+
+	for _, s := range p.Req.Subvols[1:] {
+		if len(s.Bricks) != len(p.Req.Subvols[0].Bricks) || s.Type != p.Req.Subvols[0].Type {
+			addRecoveryWarning(p, "subvolumes differ in type or size, bricks may be missing")
+			break
+		}
+	}
+	if len(p.Req.Subvols[0].Bricks) > 1 {
+		addRecoveryWarning(p, "order of bricks within subvolumes could not be determined, verify it before recreating the volume")
+	}
+}
+
+func recoverySubvol(p *api.VolRecoveryProposal, bricks []api.RecoveredBrick) api.SubvolReq {
+	if len(bricks) == 1 {
+		return api.SubvolReq{
+			Type:   "distribute",
+			Bricks: []api.BrickReq{{PeerID: bricks[0].PeerID, Path: bricks[0].Path}},
+		}
+	}
+
+	var arbiter *api.RecoveredBrick
+	s := api.SubvolReq{}
+	for i, b := range bricks {
+		if b.Arbiter {
+			arbiter = &bricks[i]
+			continue
+		}
+		s.Bricks = append(s.Bricks, api.BrickReq{PeerID: b.PeerID, Path: b.Path})
+	}
+
+	subvolType := bricks[0].SubvolType
+	switch {
+	case arbiter != nil:
+		// The arbiter is always the last brick of the subvolume
+		s.Type = "replicate"
+		s.ReplicaCount = len(s.Bricks)
+		s.ArbiterCount = 1
+		s.Bricks = append(s.Bricks, api.BrickReq{Type: "arbiter", PeerID: arbiter.PeerID, Path: arbiter.Path})
+	case subvolType == "disperse":
+		s.Type = "disperse"
+		s.DisperseCount = len(s.Bricks)
+		addRecoveryWarning(p, "disperse redundancy could not be determined, the default redundancy is used")
+	default:
+		if subvolType == "" {
+			addRecoveryWarning(p, "subvolume type could not be determined, taken as replicate")
+		}
+		s.Type = "replicate"
+		s.ReplicaCount = len(s.Bricks)
+	}
+	return s
+}
+
+// restoreFromBricksHandler scans the bricks of all peers for volumes missing
+// from the store, after the store has been lost, and proposes volume create
+// requests adopting the bricks to recreate them. Nothing is changed by this.
+// The proposals must be reviewed, and approved by sending the requests to
+// create the volumes.
+func restoreFromBricksHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	peers, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "vol-restore-from-bricks.ScanBricks",
+			Nodes:  peers,
+		},
+	}
+
+	// Peers which are down are reported, and their bricks are missing
+	// from the proposals
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to scan bricks")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := api.RestoreFromBricksResp{Volumes: []api.VolRecoveryProposal{}}
+	var bricks []api.RecoveredBrick
+	for _, node := range peers {
+		var tmp []api.RecoveredBrick
+		if err := txn.Ctx.GetNodeResult(node, recoveredBricksTxnKey, &tmp); err != nil {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf("could not scan bricks on peer %s", node))
+			continue
+		}
+		bricks = append(bricks, tmp...)
+	}
+
+	for _, p := range buildRecoveryProposals(bricks) {
+		if volume.Exists(p.Name) {
+			continue
+		}
+		resp.Volumes = append(resp.Volumes, p)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,94 @@
+package volumecommands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBrickVolfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volfiles")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	volfile := `volume vol1-server
+    type protocol/server
+    subvolumes /bricks/b1
+end-volume
+
+volume /bricks/b1
+    type debug/io-stats
+    subvolumes vol1-arbiter
+end-volume
+
+volume vol1-arbiter
+    type features/arbiter
+    subvolumes vol1-posix
+end-volume
+
+volume vol1-posix
+    type storage/posix
+    option directory /bricks/b1
+end-volume
+`
+	file := path.Join(dir, "vol1.vol")
+	assert.Nil(t, ioutil.WriteFile(file, []byte(volfile), 0600))
+
+	brickPath, arbiter, err := parseBrickVolfile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "/bricks/b1", brickPath)
+	assert.True(t, arbiter)
+}
+
+func TestBuildRecoveryProposals(t *testing.T) {
+	bricks := []api.RecoveredBrick{
+		{PeerID: "p2", Path: "/b2", VolumeName: "rep", VolumeID: "id1", Layout: "00000000-7fffffff", SubvolType: "replicate"},
+		{PeerID: "p1", Path: "/b1", VolumeName: "rep", VolumeID: "id1", Layout: "00000000-7fffffff", SubvolType: "replicate"},
+		{PeerID: "p1", Path: "/b3", VolumeName: "rep", VolumeID: "id1", Layout: "80000000-ffffffff", SubvolType: "replicate"},
+		{PeerID: "p2", Path: "/b4", VolumeName: "rep", VolumeID: "id1", Layout: "80000000-ffffffff", SubvolType: "replicate"},
+		{PeerID: "p1", Path: "/d1", VolumeName: "dist", VolumeID: "id2", Layout: "00000000-ffffffff"},
+		{PeerID: "p1", Path: "/x1", VolumeName: "bad", Error: "no volume-id"},
+	}
+
+	proposals := buildRecoveryProposals(bricks)
+	assert.Len(t, proposals, 3)
+
+	// Proposals are sorted by name
+	bad, dist, rep := proposals[0], proposals[1], proposals[2]
+
+	assert.Empty(t, bad.Req.Subvols)
+	assert.NotEmpty(t, bad.Warnings)
+
+	assert.Len(t, dist.Req.Subvols, 1)
+	assert.Equal(t, "distribute", dist.Req.Subvols[0].Type)
+	assert.True(t, dist.Req.Adopt)
+
+	assert.Len(t, rep.Req.Subvols, 2)
+	for _, s := range rep.Req.Subvols {
+		assert.Equal(t, "replicate", s.Type)
+		assert.Equal(t, 2, s.ReplicaCount)
+	}
+	assert.Equal(t, []api.BrickReq{{PeerID: "p1", Path: "/b1"}, {PeerID: "p2", Path: "/b2"}}, rep.Req.Subvols[0].Bricks)
+	assert.Equal(t, []api.BrickReq{{PeerID: "p1", Path: "/b3"}, {PeerID: "p2", Path: "/b4"}}, rep.Req.Subvols[1].Bricks)
+}
+
+func TestBuildRecoveryProposalsArbiter(t *testing.T) {
+	bricks := []api.RecoveredBrick{
+		{PeerID: "p1", Path: "/a", VolumeName: "arb", VolumeID: "id", Layout: "00000000-ffffffff", Arbiter: true},
+		{PeerID: "p2", Path: "/b", VolumeName: "arb", VolumeID: "id", Layout: "00000000-ffffffff"},
+		{PeerID: "p3", Path: "/c", VolumeName: "arb", VolumeID: "id", Layout: "00000000-ffffffff"},
+	}
+
+	proposals := buildRecoveryProposals(bricks)
+	assert.Len(t, proposals, 1)
+
+	s := proposals[0].Req.Subvols[0]
+	assert.Equal(t, 2, s.ReplicaCount)
+	assert.Equal(t, 1, s.ArbiterCount)
+	assert.Equal(t, api.BrickReq{Type: "arbiter", PeerID: "p1", Path: "/a"}, s.Bricks[2])
+}
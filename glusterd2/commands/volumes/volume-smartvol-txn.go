@@ -2,8 +2,10 @@ package volumecommands
 
 import (
 	"os"
+	"strconv"
 	"strings"
 
+	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volume"
@@ -22,18 +24,49 @@ func txnPrepareBricks(c transaction.TxnCtx) error {
 		return err
 	}
 
+	var total, localTotal, done int
 	for _, sv := range req.Subvols {
 		for _, b := range sv.Bricks {
+			total++
+			if b.PeerID == gdctx.MyUUID.String() {
+				localTotal++
+			}
+		}
+	}
+
+	for _, sv := range req.Subvols {
+		for _, b := range sv.Bricks {
+			if b.PeerID != gdctx.MyUUID.String() {
+				continue
+			}
 			err := PrepareBrick(b, c)
 			if err != nil {
 				return err
 			}
+			done++
+			events.Broadcast(newCreateProgressEvent(&req, b, done, localTotal, total))
 		}
 	}
 
 	return nil
 }
 
+// newCreateProgressEvent returns an event reporting the provisioning of a
+// brick of a volume being created. Each event accounts for one brick, so
+// clients count the events of the job to know the overall progress.
+func newCreateProgressEvent(req *api.VolCreateReq, b api.BrickReq, done, localTotal, total int) *api.Event {
+	data := map[string]string{
+		"volume.name":        req.Name,
+		"job.id":             req.JobID,
+		"peer.id":            b.PeerID,
+		"brick.path":         b.Path,
+		"bricks.provisioned": strconv.Itoa(done),
+		"bricks.local-total": strconv.Itoa(localTotal),
+		"bricks.total":       strconv.Itoa(total),
+	}
+	return events.New(string(volume.EventVolumeCreateProgress), data, true)
+}
+
 // PrepareBrick prepares(Creates thin pool, creates LV, mounts etc.) a single brick
 func PrepareBrick(b api.BrickReq, c transaction.TxnCtx) error {
 	if b.PeerID != gdctx.MyUUID.String() {
@@ -128,37 +161,44 @@ func txnUndoPrepareBricks(c transaction.TxnCtx) error {
 				continue
 			}
 
-			// UnMount the Brick
-			mountRoot := strings.TrimSuffix(b.Path, b.BrickDirSuffix)
-			err := lvmutils.UnmountLV(mountRoot)
-			if err != nil {
-				c.Logger().WithError(err).WithField("path", mountRoot).Error("brick unmount failed")
-			}
+			UndoPrepareBrick(b, c)
+		}
+	}
 
-			// Remove LV
-			err = lvmutils.RemoveLV(b.VgName, b.LvName)
-			if err != nil {
-				c.Logger().WithError(err).WithFields(log.Fields{
-					"vg-name": b.VgName,
-					"lv-name": b.LvName,
-				}).Error("lv remove failed")
-			}
+	return nil
+}
 
-			// Remove Thin Pool
-			err = lvmutils.RemoveLV(b.VgName, b.TpName)
-			if err != nil {
-				c.Logger().WithError(err).WithFields(log.Fields{
-					"vg-name": b.VgName,
-					"tp-name": b.TpName,
-				}).Error("thinpool remove failed")
-			}
+// UndoPrepareBrick unmounts and removes the LV and the thin pool created for
+// the brick by PrepareBrick. Failures are only logged, so that as much as
+// possible is cleaned up.
+func UndoPrepareBrick(b api.BrickReq, c transaction.TxnCtx) {
+	// UnMount the Brick
+	mountRoot := strings.TrimSuffix(b.Path, b.BrickDirSuffix)
+	err := lvmutils.UnmountLV(mountRoot)
+	if err != nil {
+		c.Logger().WithError(err).WithField("path", mountRoot).Error("brick unmount failed")
+	}
 
-			// Update current Vg free size
-			deviceutils.UpdateDeviceFreeSize(gdctx.MyUUID.String(), b.RootDevice)
-		}
+	// Remove LV
+	err = lvmutils.RemoveLV(b.VgName, b.LvName)
+	if err != nil {
+		c.Logger().WithError(err).WithFields(log.Fields{
+			"vg-name": b.VgName,
+			"lv-name": b.LvName,
+		}).Error("lv remove failed")
 	}
 
-	return nil
+	// Remove Thin Pool
+	err = lvmutils.RemoveLV(b.VgName, b.TpName)
+	if err != nil {
+		c.Logger().WithError(err).WithFields(log.Fields{
+			"vg-name": b.VgName,
+			"tp-name": b.TpName,
+		}).Error("thinpool remove failed")
+	}
+
+	// Update current Vg free size
+	deviceutils.UpdateDeviceFreeSize(gdctx.MyUUID.String(), b.RootDevice)
 }
 
 func txnCleanBricks(c transaction.TxnCtx) error {
@@ -132,7 +132,7 @@ func volumeStartHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, span := trace.StartSpan(ctx, "/volumeStartHandler")
 	defer span.End()
 
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	volname := mux.Vars(r)["volname"]
 	var req api.VolumeStartReq
 
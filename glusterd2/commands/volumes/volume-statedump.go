@@ -89,7 +89,7 @@ func registerVolStatedumpFuncs() {
 func volumeStatedumpHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	volname := mux.Vars(r)["volname"]
 
 	var req api.VolStatedumpReq
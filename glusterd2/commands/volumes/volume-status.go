@@ -15,7 +15,7 @@ import (
 func volumeStatusHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	volname := mux.Vars(r)["volname"]
 
 	volinfo, err := volume.GetVolume(volname)
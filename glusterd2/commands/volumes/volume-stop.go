@@ -121,7 +121,7 @@ func volumeStopHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, span := trace.StartSpan(ctx, "/volumeStopHandler")
 	defer span.End()
 
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	volname := mux.Vars(r)["volname"]
 
 	txn, err := transactionv2.NewTxnWithLocks(ctx, volname)
@@ -0,0 +1,93 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func createSubdirExportInfo(s *volume.SubdirExport) api.SubdirExportInfo {
+	return api.SubdirExportInfo{
+		Path:    s.Path,
+		Clients: s.Clients,
+	}
+}
+
+func volumeSubdirListHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := make(api.SubdirExportListResp, 0, len(volinfo.SubdirExports))
+	for i := range volinfo.SubdirExports {
+		resp = append(resp, createSubdirExportInfo(&volinfo.SubdirExports[i]))
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeSubdirExportHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	var req api.SubdirExportReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	export := volume.SubdirExport{
+		Path:    volume.CleanSubdirPath(req.Path),
+		Clients: req.Clients,
+	}
+	if err := export.Validate(); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	_, err := updateServerAuth(ctx, volname, func(v *volume.Volinfo) error {
+		return v.AddSubdirExport(export)
+	})
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	gdctx.Logger(ctx).WithField("subdir", export.Path).Info("subdirectory exported")
+	resp := api.SubdirExportResp(createSubdirExportInfo(&export))
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, &resp)
+}
+
+func volumeSubdirUnexportHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+	subdir := volume.CleanSubdirPath(mux.Vars(r)["subdir"])
+
+	_, err := updateServerAuth(ctx, volname, func(v *volume.Volinfo) error {
+		return v.RemoveSubdirExport(subdir)
+	})
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	gdctx.Logger(ctx).WithField("subdir", subdir).Info("subdirectory unexported")
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
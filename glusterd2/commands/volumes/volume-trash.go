@@ -0,0 +1,220 @@
+package volumecommands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// trashRetentionOpt is the number of hours deleted volumes are kept in
+	// the trash before they are purged. Volumes are purged right away when
+	// it is 0.
+	trashRetentionOpt = "cluster.volume-trash-retention"
+
+	trashPurgeJobName     = "volume.trash-purge"
+	trashPurgeJobSchedule = "@every 10m"
+)
+
+func validateTrashRetention(key, value string) error {
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours < 0 {
+		return errors.New("retention must be a non-negative number of hours")
+	}
+	return nil
+}
+
+// trashRetention returns how long deleted volumes are kept in the trash
+func trashRetention() (time.Duration, error) {
+	value, err := options.GetClusterOption(trashRetentionOpt)
+	if err != nil {
+		return 0, err
+	}
+	hours, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(hours) * time.Hour, nil
+}
+
+func registerVolTrashJob() {
+	options.RegisterClusterOpValidationFunc(trashRetentionOpt, validateTrashRetention)
+
+	err := scheduler.Register(&scheduler.Job{
+		Name:        trashPurgeJobName,
+		Description: "Purges deleted volumes kept in the trash past the retention period, cleaning up their bricks",
+		Schedule:    trashPurgeJobSchedule,
+		Enabled:     true,
+		Func:        purgeTrashedVolumes,
+	})
+	if err != nil {
+		log.WithError(err).WithField("job", trashPurgeJobName).Error("failed to register scheduled job")
+	}
+}
+
+func trashVolume(c transaction.TxnCtx) error {
+	var (
+		volinfo   volume.Volinfo
+		retention time.Duration
+		wipe      bool
+	)
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+	if err := c.Get("retention", &retention); err != nil {
+		return err
+	}
+	if err := c.Get("wipe", &wipe); err != nil {
+		return err
+	}
+
+	_, err := volume.TrashVolume(&volinfo, retention, wipe)
+	return err
+}
+
+func purgeTrashedVolumes(ctx context.Context) error {
+	trashed, err := volume.GetTrashedVolumes()
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, t := range trashed {
+		if time.Now().Before(t.PurgeAt) {
+			continue
+		}
+		if err := purgeTrashedVolume(ctx, t); err != nil {
+			log.WithError(err).WithField("volume", t.Volinfo.Name).Warn("failed to purge deleted volume")
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		return errors.New("purge failed for " + strconv.Itoa(failed) + " deleted volume(s)")
+	}
+	return nil
+}
+
+// purgeTrashedVolume cleans up the bricks of the deleted volume as a delete
+// without the trash would have, and removes it from the trash. Purges which
+// fail are retried on the next run of the purge job.
+func purgeTrashedVolume(ctx context.Context, t *volume.TrashedVolume) error {
+	volinfo := t.Volinfo
+	ctx = gdctx.WithVolName(gdctx.WithReqID(ctx, uuid.NewRandom()), volinfo.Name)
+	logger := gdctx.Logger(ctx)
+	ctx = gdctx.WithReqLogger(ctx, logger)
+
+	// The lock on the name keeps a restore of the volume from racing with
+	// the purge
+	txn, err := transaction.NewTxnWithLocks(ctx, volinfo.Name)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	// With wipe, the LVs of the bricks are cleaned up by the brick wipe
+	// jobs after discarding them
+	if (volinfo.IsAutoProvisioned() || volinfo.IsSnapshotProvisioned()) && !t.Wipe {
+		txn.Steps = []*transaction.Step{
+			{
+				DoFunc: "vol-delete.CleanBricks",
+				Nodes:  volinfo.Nodes(),
+			},
+		}
+		if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+			return err
+		}
+		if err := txn.Do(); err != nil {
+			return err
+		}
+	}
+
+	if err := volume.PurgeTrashedVolume(t); err != nil {
+		return err
+	}
+	logger.Info("purged deleted volume")
+	events.Broadcast(volume.NewEvent(volume.EventVolumePurged, volinfo))
+
+	if t.Wipe {
+		if _, err := volume.ScheduleBrickWipes(volinfo); err != nil {
+			return fmt.Errorf("volume purged, but failed to schedule wiping all its bricks: %s", err)
+		}
+	}
+	return nil
+}
+
+func volumeTrashListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	trashed, err := volume.GetTrashedVolumes()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.DeletedVolumeListResp, 0, len(trashed))
+	for _, t := range trashed {
+		resp = append(resp, *volume.CreateDeletedVolumeResp(t))
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeRestoreHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	t, err := volume.GetTrashedVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := volume.CheckBricksNotInUse(t.Volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+		return
+	}
+
+	volinfo, err := volume.RestoreVolume(t)
+	if err == gderrors.ErrVolExists {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to restore deleted volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("volume", volname).Info("deleted volume restored")
+	events.Broadcast(volume.NewEvent(volume.EventVolumeRestored, volinfo))
+
+	resp := (*api.VolumeRestoreResp)(volume.CreateVolumeInfoResp(volinfo))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,361 @@
+package volumecommands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	usageProtectJobName     = "volume.usage-protect"
+	usageProtectJobSchedule = "@every 1m"
+
+	// readOnlyOption enables the read-only xlator of the volume
+	readOnlyOption = "read-only.read-only"
+)
+
+func registerUsageProtectJob() {
+	err := scheduler.Register(&scheduler.Job{
+		Name:        usageProtectJobName,
+		Description: "Makes volumes read-only when their usage crosses the critical threshold of their usage protection policy",
+		Schedule:    usageProtectJobSchedule,
+		Enabled:     true,
+		Func:        protectVolumesUsage,
+	})
+	if err != nil {
+		log.WithError(err).WithField("job", usageProtectJobName).Error("failed to register scheduled job")
+	}
+}
+
+func validateVolUsageProtectReq(req *api.VolUsageProtectReq) error {
+	if req.CriticalThreshold <= 0 || req.CriticalThreshold > 100 {
+		return errors.New("critical threshold must be a percentage between 1 and 100")
+	}
+
+	if req.ResumeThreshold <= 0 || req.ResumeThreshold >= req.CriticalThreshold {
+		return errors.New("resume threshold must be a percentage less than the critical threshold")
+	}
+
+	return nil
+}
+
+// usageProtectTransition returns the read-only state the volume must be put
+// in as per the policy and the usage sample, and whether the state changes.
+// Between the resume and critical thresholds the current state is retained,
+// so that the volume doesn't flip back and forth around a single threshold.
+func usageProtectTransition(policy *api.VolUsageProtectResp, sample api.VolUsageSample) (engage bool, changed bool) {
+	if sample.Capacity == 0 {
+		return policy.Engaged, false
+	}
+
+	used := sample.Used * 100
+	switch {
+	case !policy.Engaged && used >= uint64(policy.CriticalThreshold)*sample.Capacity:
+		return true, true
+	case policy.Engaged && used < uint64(policy.ResumeThreshold)*sample.Capacity:
+		return false, true
+	}
+
+	return policy.Engaged, false
+}
+
+func protectVolumesUsage(ctx context.Context) error {
+	policies, err := volume.GetUsageProtectPolicies()
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for volname, policy := range policies {
+		if err := protectVolumeUsage(ctx, volname, policy); err != nil {
+			log.WithError(err).WithField("volume", volname).Warn("volume usage protection failed")
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		return errors.New("usage protection failed for " + strconv.Itoa(failed) + " volume(s)")
+	}
+	return nil
+}
+
+func protectVolumeUsage(ctx context.Context, volname string, policy *api.VolUsageProtectResp) error {
+	ctx = gdctx.WithVolName(gdctx.WithReqID(ctx, uuid.NewRandom()), volname)
+	ctx = gdctx.WithReqUser(ctx, usageProtectJobName)
+	logger := gdctx.Logger(ctx)
+	ctx = gdctx.WithReqLogger(ctx, logger)
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return err
+	}
+
+	if volinfo.State != volume.VolStarted {
+		return nil
+	}
+
+	usage, err := volume.UsageInfo(volname)
+	if err != nil {
+		return err
+	}
+
+	sample := api.VolUsageSample{
+		Time:     time.Now(),
+		Capacity: usage.Capacity,
+		Used:     usage.Used,
+	}
+	policy.LastSample = &sample
+
+	engage, changed := usageProtectTransition(policy, sample)
+	if changed && engage && volinfo.Options[readOnlyOption] == "on" {
+		// The volume was made read-only by the admin, leave it to the
+		// admin to make it writable again
+		logger.Debug("volume is already read-only, not engaging usage protection")
+		changed = false
+	}
+	if changed {
+		logger.WithField("used", usage.Used).WithField("capacity", usage.Capacity).
+			WithField("read-only", engage).Info("volume usage crossed the usage protection threshold")
+		err = setVolumeReadOnly(ctx, volname, engage)
+	}
+
+	if err == nil && changed {
+		policy.Engaged = engage
+		policy.LastTransition = sample.Time
+
+		ev := volume.EventVolumeUsageProtectReleased
+		if engage {
+			ev = volume.EventVolumeUsageProtectEngaged
+		}
+		e := volume.NewEvent(ev, volinfo)
+		e.Data["volume.capacity"] = strconv.FormatUint(usage.Capacity, 10)
+		e.Data["volume.used"] = strconv.FormatUint(usage.Used, 10)
+		events.Broadcast(e)
+	}
+
+	if serr := saveUsageProtectState(volname, policy, err); serr != nil {
+		logger.WithError(serr).Warn("failed to save volume usage protection state")
+	}
+
+	return err
+}
+
+// saveUsageProtectState saves the usage sample and the transition results to
+// the policy in the store, preserving any change made to the thresholds since
+// the policy was read.
+func saveUsageProtectState(volname string, state *api.VolUsageProtectResp, protectErr error) error {
+	policy, err := volume.GetUsageProtectPolicy(volname)
+	if err != nil {
+		if err == gderrors.ErrUsageProtectPolicyNotFound {
+			// policy was deleted meanwhile
+			return nil
+		}
+		return err
+	}
+
+	policy.Engaged = state.Engaged
+	policy.LastSample = state.LastSample
+	policy.LastTransition = state.LastTransition
+	policy.LastError = ""
+	if protectErr != nil {
+		policy.LastError = protectErr.Error()
+	}
+
+	return volume.SetUsageProtectPolicy(volname, policy)
+}
+
+// setVolumeReadOnly sets the read-only option of the volume and applies it to
+// the running bricks. The change is recorded in the options history of the
+// volume.
+func setVolumeReadOnly(ctx context.Context, volname string, readOnly bool) error {
+	value := "off"
+	if readOnly {
+		value = "on"
+	}
+	req := api.VolOptionReq{Options: map[string]string{readOnlyOption: value}}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return err
+	}
+	oldOptions := copyOptions(volinfo.Options)
+
+	//save volume information for transaction failure scenario
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		return err
+	}
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		return err
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "vol-option.Validate",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+		},
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+		{
+			DoFunc:   "vol-option.XlatorActionDoSet",
+			UndoFunc: "vol-option.XlatorActionUndoSet",
+			Nodes:    volinfo.Nodes(),
+			Skip:     !isActionStepRequired(req.Options, volinfo),
+		},
+		{
+			DoFunc:   "vol-option.GenerateBrickVolfiles",
+			UndoFunc: "vol-option.GenerateBrickvolfiles.Undo",
+			Nodes:    volinfo.Nodes(),
+		},
+		{
+			DoFunc: "vol-option.ReconfigureBricks",
+			Nodes:  volinfo.Nodes(),
+		},
+		{
+			DoFunc: "vol-option.NotifyVolfileChange",
+			Nodes:  allNodes,
+		},
+	}
+
+	if err := txn.Ctx.Set("req", &req); err != nil {
+		return err
+	}
+
+	if err := txn.Ctx.Set(reconfigureKeysTxnKey, optionKeys(req.Options)); err != nil {
+		return err
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return err
+	}
+
+	if err := txn.Do(); err != nil {
+		txn.Ctx.Logger().WithError(err).Error("failed to set read-only option of volume")
+		return err
+	}
+
+	if volinfo, err = volume.GetVolume(volname); err != nil {
+		return err
+	}
+
+	if err := volume.AddOptionsHistory(volname, volume.OptionsSet, gdctx.GetReqUser(ctx),
+		txn.Ctx.GetTxnReqID(), oldOptions, volinfo.Options); err != nil {
+		txn.Ctx.Logger().WithError(err).Warn("failed to record volume options history")
+	}
+
+	return nil
+}
+
+func volumeUsageProtectSetHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolUsageProtectReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if _, err := volume.GetVolume(volname); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := validateVolUsageProtectReq(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Retain the state of the existing policy
+	policy, err := volume.GetUsageProtectPolicy(volname)
+	if err == gderrors.ErrUsageProtectPolicyNotFound {
+		policy = &api.VolUsageProtectResp{}
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	policy.VolUsageProtectReq = req
+
+	if err := volume.SetUsageProtectPolicy(volname, policy); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("volume", volname).Info("volume usage protection policy set")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, policy)
+}
+
+func volumeUsageProtectGetHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	policy, err := volume.GetUsageProtectPolicy(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, policy)
+}
+
+func volumeUsageProtectDeleteHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	policy, err := volume.GetUsageProtectPolicy(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// The volume must not be left read-only by a policy that no longer
+	// exists
+	if policy.Engaged {
+		if err := setVolumeReadOnly(ctx, volname, false); err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+	}
+
+	if err := volume.DeleteUsageProtectPolicy(volname); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,48 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageProtectTransition(t *testing.T) {
+	policy := &api.VolUsageProtectResp{
+		VolUsageProtectReq: api.VolUsageProtectReq{
+			CriticalThreshold: 95,
+			ResumeThreshold:   85,
+		},
+	}
+
+	// Unknown capacity
+	engage, changed := usageProtectTransition(policy, api.VolUsageSample{})
+	assert.False(t, engage)
+	assert.False(t, changed)
+
+	// Below critical threshold
+	engage, changed = usageProtectTransition(policy, api.VolUsageSample{Capacity: 100, Used: 94})
+	assert.False(t, engage)
+	assert.False(t, changed)
+
+	// Critical threshold crossed
+	engage, changed = usageProtectTransition(policy, api.VolUsageSample{Capacity: 100, Used: 95})
+	assert.True(t, engage)
+	assert.True(t, changed)
+
+	// Between the thresholds, volume stays read-only
+	policy.Engaged = true
+	engage, changed = usageProtectTransition(policy, api.VolUsageSample{Capacity: 100, Used: 90})
+	assert.True(t, engage)
+	assert.False(t, changed)
+
+	// Below resume threshold
+	engage, changed = usageProtectTransition(policy, api.VolUsageSample{Capacity: 100, Used: 84})
+	assert.False(t, engage)
+	assert.True(t, changed)
+
+	assert.Nil(t, validateVolUsageProtectReq(&policy.VolUsageProtectReq))
+	assert.NotNil(t, validateVolUsageProtectReq(&api.VolUsageProtectReq{CriticalThreshold: 80, ResumeThreshold: 90}))
+	assert.NotNil(t, validateVolUsageProtectReq(&api.VolUsageProtectReq{CriticalThreshold: 101, ResumeThreshold: 90}))
+}
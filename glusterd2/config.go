@@ -4,12 +4,18 @@ import (
 	"encoding/json"
 	"errors"
 	"expvar"
+	"fmt"
 	"net"
+	"os"
 	"path"
 	"strings"
 
+	"github.com/gluster/glusterd2/glusterd2/audit"
+	"github.com/gluster/glusterd2/glusterd2/discovery"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/logging"
 	"github.com/gluster/glusterd2/pkg/tracing"
 
@@ -53,18 +59,37 @@ func initFlags() {
 	// TODO: Change default to false (disabled) in future.
 	flag.Bool("statedump", true, "Enable /statedump endpoint for metrics.")
 
+	flag.Bool("validate-config", false, "Validate the configuration of GlusterD and the store, and exit.")
+
+	flag.Bool("devmode", false, "Enable developer mode. Exposes debug endpoints like failure injection. Do not use in production.")
+
 	flag.String("clientaddress", defaultclientaddress, "Address to bind the REST service.")
 	flag.String("peeraddress", defaultpeeraddress, "Address to bind the inter glusterd2 RPC service.")
 
-	// TODO: SSL/TLS is currently only implemented for REST interface
 	flag.String("cert-file", "", "Certificate used for SSL/TLS connections from clients to glusterd2.")
 	flag.String("key-file", "", "Private key for the SSL/TLS certificate.")
+	flag.String("client-ca-file", "", "CA verifying the certificates of REST clients. Clients with a certificate signed by it are authenticated as the user named by the common name of the certificate.")
+
+	flag.Float64("rest-rate-limit", 0, "Requests per second allowed to each REST client, identified by its user, or its IP if REST authentication is disabled. Rate limiting is disabled if 0.")
+	flag.Int("rest-rate-burst", 20, "Requests each REST client can make at once over rest-rate-limit.")
+	flag.Int("max-heavy-ops", 0, "Heavyweight operations, like volume create, snapshot and rebalance, allowed to run at once in the cluster. Set the same value on all peers. No limit if 0.")
+
+	flag.String("peer-cert-file", "", "Certificate used for SSL/TLS connections between glusterd2 peers, as server and client. It must be valid for the peer address.")
+	flag.String("peer-key-file", "", "Private key for the peer SSL/TLS certificate.")
+	flag.String("peer-ca-file", "", "CA verifying the certificates of the other peers. The system CAs are used if not set.")
+	flag.Bool("peer-verify-client-cert", false, "Require peers connecting to this peer to present a certificate signed by the peer CA.")
 
 	// PID file
 	flag.String("pidfile", "", "PID file path. (default \"rundir/glusterd2.pid)\"")
 
+	flag.String("backupdir", "", "Directory to save cluster backups in. (default \"localstatedir/backups\")")
+
 	store.InitFlags()
+	transaction.InitFlags()
 	tracing.InitFlags()
+	audit.InitFlags()
+	discovery.InitFlags()
+	volume.InitFlags()
 
 	flag.Parse()
 }
@@ -79,6 +104,10 @@ func setDefaults() error {
 		config.SetDefault("pidfile", path.Join(config.GetString("rundir"), "glusterd2.pid"))
 	}
 
+	if config.GetString("backupdir") == "" {
+		config.SetDefault("backupdir", path.Join(config.GetString("localstatedir"), "backups"))
+	}
+
 	// Set peer address.
 	host, port, err := net.SplitHostPort(config.GetString("peeraddress"))
 	if err != nil {
@@ -97,6 +126,42 @@ func setDefaults() error {
 	return nil
 }
 
+// validateConfig checks the GD2 configuration and the store configuration for
+// errors which would prevent GD2 from starting
+func validateConfig() error {
+	if _, _, err := net.SplitHostPort(config.GetString("clientaddress")); err != nil {
+		return fmt.Errorf("invalid client address: %s", err)
+	}
+
+	certFile := config.GetString("cert-file")
+	keyFile := config.GetString("key-file")
+	if (certFile == "") != (keyFile == "") {
+		return errors.New("cert-file and key-file must be given together")
+	}
+	peerCertFile := config.GetString("peer-cert-file")
+	peerKeyFile := config.GetString("peer-key-file")
+	if (peerCertFile == "") != (peerKeyFile == "") {
+		return errors.New("peer-cert-file and peer-key-file must be given together")
+	}
+	if config.GetBool("peer-verify-client-cert") && (peerCertFile == "" || config.GetString("peer-ca-file") == "") {
+		return errors.New("peer-verify-client-cert needs peer-cert-file, peer-key-file and peer-ca-file")
+	}
+	if config.GetString("client-ca-file") != "" && certFile == "" {
+		return errors.New("client-ca-file needs cert-file and key-file")
+	}
+	for _, f := range []string{certFile, keyFile, config.GetString("client-ca-file"),
+		peerCertFile, peerKeyFile, config.GetString("peer-ca-file")} {
+		if f == "" {
+			continue
+		}
+		if _, err := os.Stat(f); err != nil {
+			return err
+		}
+	}
+
+	return store.CheckConfig()
+}
+
 type valueType struct {
 	v interface{}
 }
@@ -2,7 +2,6 @@ package daemon
 
 import (
 	"os"
-	"os/exec"
 	"strings"
 	"syscall"
 
@@ -51,6 +50,9 @@ type Daemon interface {
 // is already running, errors.ErrProcessAlreadyRunning is returned.
 // When wait == true, this function can be used to spawn short term processes
 // which will be waited on for completion before this function returns.
+// Daemons whose class has been selected to run under systemd by the cluster
+// options are started as generated systemd units instead, which restarts them
+// if they crash and collects their output in the journal.
 func Start(d Daemon, wait bool, logger log.FieldLogger) error {
 
 	logger.WithFields(log.Fields{
@@ -63,15 +65,45 @@ func Start(d Daemon, wait bool, logger log.FieldLogger) error {
 	// Check if pidfile exists
 	pid, err := ReadPidFromFile(d.PidFile())
 	if err == nil {
-		// Check if process is running
-		_, err := GetProcess(pid)
-		if err == nil {
+		// Check if process is running, and is not an unrelated
+		// process which has reused the pid
+		if IsProcessOf(pid, d.Path()) {
 			events.Broadcast(newEvent(d, daemonStarted, pid))
 			return errors.ErrProcessAlreadyRunning
 		}
 	}
 
-	cmd := exec.Command(d.Path(), d.Args()...)
+	if useSystemd(d) {
+		if err := startUnit(d); err != nil {
+			logger.WithError(err).WithField("unit", unitName(d)).Error("Starting systemd unit of daemon failed")
+			events.Broadcast(newEvent(d, daemonStartFailed, 0))
+			return err
+		}
+
+		pid, err = ReadPidFromFile(d.PidFile())
+		if err != nil {
+			logger.WithError(err).WithField("pidfile", d.PidFile()).Error("Could not read pidfile")
+			events.Broadcast(newEvent(d, daemonStartFailed, 0))
+			return err
+		}
+
+		logger.WithFields(log.Fields{
+			"name": d.Name(),
+			"pid":  pid,
+			"unit": unitName(d),
+		}).Debug("Started daemon successfully as systemd unit")
+		events.Broadcast(newEvent(d, daemonStarted, pid))
+
+		saveDaemonInfo(d, logger)
+		return nil
+	}
+
+	cmd, err := command(d)
+	if err != nil {
+		events.Broadcast(newEvent(d, daemonStartFailed, 0))
+		return err
+	}
+
 	err = cmd.Start()
 	if err != nil {
 		events.Broadcast(newEvent(d, daemonStartFailed, 0))
@@ -121,12 +153,15 @@ func Start(d Daemon, wait bool, logger log.FieldLogger) error {
 		}()
 	}
 
-	// Save daemon information in the store so it can be restarted
+	saveDaemonInfo(d, logger)
+	return nil
+}
+
+// saveDaemonInfo saves daemon information in the store so it can be restarted
+func saveDaemonInfo(d Daemon, logger log.FieldLogger) {
 	if err := saveDaemon(d); err != nil {
 		logger.WithError(err).WithField("name", d.Name()).Warn("failed to save daemon information into store, daemon may not be restarted on GlusterD restart")
 	}
-
-	return nil
 }
 
 // Kill function terminate the process gracefully or forcefully.
@@ -152,11 +187,15 @@ func Kill(pid int, force bool) error {
 // terminate the process gracefully or forcefully.
 // When force == false, a SIGTERM signal is sent to the daemon.
 // When force == true, a SIGKILL signal is sent to the daemon.
+// Daemons started as systemd units are stopped through systemd, and their
+// units are removed.
 func Stop(d Daemon, force bool, logger log.FieldLogger) error {
 
+	systemd := hasUnit(d)
+
 	// It is assumed that the process d has written to pidfile
 	pid, err := ReadPidFromFile(d.PidFile())
-	if err != nil {
+	if err != nil && !systemd {
 		return errors.ErrPidFileNotFound
 	}
 
@@ -166,7 +205,11 @@ func Stop(d Daemon, force bool, logger log.FieldLogger) error {
 	}).Debug("Stopping daemon.")
 	events.Broadcast(newEvent(d, daemonStopping, pid))
 
-	err = Kill(pid, force)
+	if systemd {
+		err = stopUnit(d, force)
+	} else {
+		err = Kill(pid, force)
+	}
 
 	// TODO: Do this under some lock ?
 	_ = os.Remove(d.PidFile())
@@ -211,6 +254,30 @@ func StartAllDaemons() {
 	events.Broadcast(events.New(daemonStartedAll, nil, false))
 }
 
+// StopAllDaemons stops all daemons saved in the store for this peer and
+// removes them from the store, so that they aren't restarted when GlusterD
+// restarts
+func StopAllDaemons(logger log.FieldLogger) {
+	logger.Debug("stopping all daemons")
+	events.Broadcast(events.New(daemonStoppingAll, nil, false))
+
+	ds, err := getDaemons()
+	if err != nil {
+		logger.WithError(err).Warn("failed to get saved daemons, no daemons were stopped")
+		return
+	}
+
+	for _, d := range ds {
+		if err := Stop(d, true, logger); err != nil {
+			logger.WithError(err).WithField("name", d.Name()).Warn("failed to stop daemon")
+			if err := DelDaemon(d); err != nil {
+				logger.WithError(err).WithField("name", d.Name()).Warn("failed to delete daemon from store")
+			}
+		}
+	}
+	events.Broadcast(events.New(daemonStoppedAll, nil, false))
+}
+
 // Signal function reads the PID from path returned by PidFile() and
 // sends the signal to that PID
 func Signal(d Daemon, sig syscall.Signal, logger log.FieldLogger) error {
@@ -0,0 +1,188 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// I/O scheduling classes supported by ionice
+const (
+	IONiceClassRealtime   = "realtime"
+	IONiceClassBestEffort = "best-effort"
+	IONiceClassIdle       = "idle"
+)
+
+var ioniceClasses = map[string]int{
+	IONiceClassRealtime:   1,
+	IONiceClassBestEffort: 2,
+	IONiceClassIdle:       3,
+}
+
+var umaskRE = regexp.MustCompile(`^0?[0-7]{3}$`)
+
+// SpawnEnv describes the environment in which a daemon is spawned. Zero
+// values leave the corresponding attribute inherited from GlusterD.
+type SpawnEnv struct {
+	// Umask is the file mode creation mask in octal, eg. 0022
+	Umask string
+	// Nice is the niceness adjustment, between -20 and 19
+	Nice int
+	// IONiceClass is the I/O scheduling class and IONiceLevel the
+	// priority within the class, between 0 (highest) and 7. The level is
+	// ignored for the idle class.
+	IONiceClass string
+	IONiceLevel int
+	// OOMScoreAdj is the OOM killer score adjustment, between -1000 and
+	// 1000
+	OOMScoreAdj int
+	// NoFile is the limit on the number of open files
+	NoFile uint64
+	// User and Group the daemon is run as
+	User  string
+	Group string
+}
+
+// SpawnEnver is optionally implemented by daemons which have to be spawned
+// in a specific environment
+type SpawnEnver interface {
+	SpawnEnv() (*SpawnEnv, error)
+}
+
+// IsEmpty returns true if the daemon inherits the environment of GlusterD
+func (e *SpawnEnv) IsEmpty() bool {
+	return e == nil || *e == SpawnEnv{}
+}
+
+// Validate validates the spawn environment
+func (e *SpawnEnv) Validate() error {
+	if e.Umask != "" && !umaskRE.MatchString(e.Umask) {
+		return fmt.Errorf("invalid umask %q", e.Umask)
+	}
+	if e.Nice < -20 || e.Nice > 19 {
+		return errors.New("nice value should be between -20 and 19")
+	}
+	if e.IONiceClass != "" {
+		if _, ok := ioniceClasses[e.IONiceClass]; !ok {
+			return fmt.Errorf("invalid I/O scheduling class %q", e.IONiceClass)
+		}
+	}
+	if e.IONiceLevel < 0 || e.IONiceLevel > 7 {
+		return errors.New("I/O priority should be between 0 and 7")
+	}
+	if e.OOMScoreAdj < -1000 || e.OOMScoreAdj > 1000 {
+		return errors.New("OOM score adjustment should be between -1000 and 1000")
+	}
+	return nil
+}
+
+// credential returns the credential of the user and group the daemon is
+// run as
+func (e *SpawnEnv) credential() (*syscall.Credential, error) {
+	cred := &syscall.Credential{
+		Uid: uint32(os.Getuid()),
+		Gid: uint32(os.Getgid()),
+	}
+
+	if e.User != "" {
+		u, err := user.Lookup(e.User)
+		if err != nil {
+			return nil, err
+		}
+		uid, err := strconv.ParseUint(u.Uid, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		gid, err := strconv.ParseUint(u.Gid, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		cred.Uid, cred.Gid = uint32(uid), uint32(gid)
+	}
+
+	if e.Group != "" {
+		g, err := user.LookupGroup(e.Group)
+		if err != nil {
+			return nil, err
+		}
+		gid, err := strconv.ParseUint(g.Gid, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		cred.Gid = uint32(gid)
+	}
+
+	return cred, nil
+}
+
+// script returns the shell script which sets up the environment and execs
+// the daemon, passed as the positional parameters of the script
+func (e *SpawnEnv) script() string {
+	var cmds []string
+
+	if e.Umask != "" {
+		cmds = append(cmds, "umask "+e.Umask)
+	}
+	if e.NoFile != 0 {
+		cmds = append(cmds, fmt.Sprintf("ulimit -n %d", e.NoFile))
+	}
+	if e.OOMScoreAdj != 0 {
+		cmds = append(cmds, fmt.Sprintf("echo %d > /proc/self/oom_score_adj", e.OOMScoreAdj))
+	}
+
+	// nice and ionice exec the command they are given, so the daemon
+	// ends up with the same pid
+	execCmd := "exec"
+	if e.Nice != 0 {
+		execCmd += fmt.Sprintf(" nice -n %d", e.Nice)
+	}
+	if e.IONiceClass != "" {
+		execCmd += fmt.Sprintf(" ionice -c %d", ioniceClasses[e.IONiceClass])
+		if e.IONiceClass != IONiceClassIdle {
+			execCmd += fmt.Sprintf(" -n %d", e.IONiceLevel)
+		}
+	}
+	cmds = append(cmds, execCmd+` "$0" "$@"`)
+
+	return strings.Join(cmds, " && ")
+}
+
+// command returns the command to spawn the daemon with. Daemons with a spawn
+// environment are started through a shell which sets up the environment
+// before exec'ing the daemon.
+func command(d Daemon) (*exec.Cmd, error) {
+	s, ok := d.(SpawnEnver)
+	if !ok {
+		return exec.Command(d.Path(), d.Args()...), nil
+	}
+
+	env, err := s.SpawnEnv()
+	if err != nil {
+		return nil, err
+	}
+	if env.IsEmpty() {
+		return exec.Command(d.Path(), d.Args()...), nil
+	}
+	if err := env.Validate(); err != nil {
+		return nil, err
+	}
+
+	args := append([]string{"-c", env.script(), d.Path()}, d.Args()...)
+	cmd := exec.Command("/bin/sh", args...)
+
+	if env.User != "" || env.Group != "" {
+		cred, err := env.credential()
+		if err != nil {
+			return nil, err
+		}
+		cmd.SysProcAttr = &syscall.SysProcAttr{Credential: cred}
+	}
+
+	return cmd, nil
+}
@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDaemon struct {
+	env *SpawnEnv
+}
+
+func (d *testDaemon) Name() string       { return "test" }
+func (d *testDaemon) Path() string       { return "/usr/sbin/glusterfsd" }
+func (d *testDaemon) Args() []string     { return []string{"-s", "localhost"} }
+func (d *testDaemon) SocketFile() string { return "" }
+func (d *testDaemon) PidFile() string    { return "" }
+func (d *testDaemon) ID() string         { return "test" }
+
+func (d *testDaemon) SpawnEnv() (*SpawnEnv, error) { return d.env, nil }
+
+func TestSpawnEnvValidate(t *testing.T) {
+	assert.Nil(t, (&SpawnEnv{Umask: "0022", Nice: -5, IONiceClass: IONiceClassIdle, OOMScoreAdj: -500}).Validate())
+	assert.NotNil(t, (&SpawnEnv{Umask: "0999"}).Validate())
+	assert.NotNil(t, (&SpawnEnv{Umask: "022; reboot"}).Validate())
+	assert.NotNil(t, (&SpawnEnv{Nice: 20}).Validate())
+	assert.NotNil(t, (&SpawnEnv{IONiceClass: "fast"}).Validate())
+	assert.NotNil(t, (&SpawnEnv{IONiceLevel: 8}).Validate())
+	assert.NotNil(t, (&SpawnEnv{OOMScoreAdj: -1001}).Validate())
+}
+
+func TestSpawnCommand(t *testing.T) {
+	d := &testDaemon{}
+	cmd, err := command(d)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/usr/sbin/glusterfsd", "-s", "localhost"}, cmd.Args)
+
+	d.env = &SpawnEnv{}
+	cmd, err = command(d)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/usr/sbin/glusterfsd", "-s", "localhost"}, cmd.Args)
+
+	d.env = &SpawnEnv{
+		Umask:       "0027",
+		Nice:        5,
+		IONiceClass: IONiceClassBestEffort,
+		IONiceLevel: 6,
+		OOMScoreAdj: -500,
+		NoFile:      65536,
+	}
+	cmd, err = command(d)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"/bin/sh", "-c",
+		`umask 0027 && ulimit -n 65536 && echo -500 > /proc/self/oom_score_adj && exec nice -n 5 ionice -c 2 -n 6 "$0" "$@"`,
+		"/usr/sbin/glusterfsd", "-s", "localhost",
+	}, cmd.Args)
+	assert.Nil(t, cmd.SysProcAttr)
+
+	d.env = &SpawnEnv{IONiceClass: IONiceClassIdle, IONiceLevel: 4}
+	assert.Equal(t, `exec ionice -c 3 "$0" "$@"`, d.env.script())
+
+	d.env = &SpawnEnv{Nice: 30}
+	_, err = command(d)
+	assert.NotNil(t, err)
+}
@@ -6,10 +6,13 @@ type storedDaemon struct {
 	DName, DPath, DSocketFile, DPidFile, DID string
 
 	DArgs []string
+
+	// DSpawnEnv is the spawn environment the daemon was started in
+	DSpawnEnv *SpawnEnv `json:",omitempty"`
 }
 
 func newStoredDaemon(d Daemon) *storedDaemon {
-	return &storedDaemon{
+	sd := &storedDaemon{
 		DName:       d.Name(),
 		DPath:       d.Path(),
 		DArgs:       d.Args(),
@@ -17,6 +20,12 @@ func newStoredDaemon(d Daemon) *storedDaemon {
 		DPidFile:    d.PidFile(),
 		DID:         d.ID(),
 	}
+	if s, ok := d.(SpawnEnver); ok {
+		if env, err := s.SpawnEnv(); err == nil && !env.IsEmpty() {
+			sd.DSpawnEnv = env
+		}
+	}
+	return sd
 }
 
 func (s *storedDaemon) Name() string {
@@ -42,3 +51,7 @@ func (s *storedDaemon) PidFile() string {
 func (s *storedDaemon) ID() string {
 	return s.DID
 }
+
+func (s *storedDaemon) SpawnEnv() (*SpawnEnv, error) {
+	return s.DSpawnEnv, nil
+}
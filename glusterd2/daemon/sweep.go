@@ -0,0 +1,146 @@
+package daemon
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// IsProcessOf returns true if the process with the given pid is running the
+// binary at binPath. A pid recorded in a pidfile can have been reused by an
+// unrelated process after the daemon died. The binary is matched by the
+// argv[0] of the process, as the gluster daemons are symlinks to the same
+// binary, or by the resolved executable of the process. If the process
+// information cannot be read, the process is assumed to be running the binary.
+func IsProcessOf(pid int, binPath string) bool {
+	if _, err := GetProcess(pid); err != nil {
+		return false
+	}
+
+	procDir := filepath.Join("/proc", strconv.Itoa(pid))
+
+	cmdline, err := ioutil.ReadFile(filepath.Join(procDir, "cmdline"))
+	if err != nil {
+		return true
+	}
+	argv0 := string(bytes.SplitN(cmdline, []byte{0}, 2)[0])
+	if filepath.Base(argv0) == filepath.Base(binPath) {
+		return true
+	}
+
+	exe, err := os.Readlink(filepath.Join(procDir, "exe"))
+	if err != nil {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(binPath); err == nil {
+		binPath = resolved
+	}
+	return exe == binPath || filepath.Base(exe) == filepath.Base(binPath)
+}
+
+// RemoveStaleSockets removes unix socket files under dir which no process is
+// listening on. The names of the removed files are returned.
+func RemoveStaleSockets(dir string) ([]string, error) {
+	var removed []string
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.Mode()&os.ModeSocket == 0 {
+			continue
+		}
+		p := filepath.Join(dir, f.Name())
+		if !isStaleSocket(p) {
+			continue
+		}
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		removed = append(removed, p)
+	}
+
+	return removed, nil
+}
+
+// isStaleSocket returns true if connecting to the unix socket is refused,
+// which happens only when no process is listening on it
+func isStaleSocket(p string) bool {
+	conn, err := net.DialTimeout("unix", p, time.Second)
+	if err == nil {
+		conn.Close()
+		return false
+	}
+	if opErr, ok := err.(*net.OpError); ok {
+		if sysErr, ok := opErr.Err.(*os.SyscallError); ok {
+			return sysErr.Err == syscall.ECONNREFUSED
+		}
+	}
+	return false
+}
+
+// SweepStaleFiles removes the pidfiles and socket files left behind by
+// daemons which are no longer running, so that they are started afresh
+// instead of being taken to be running. Pidfiles of the daemons saved in the
+// store are also removed if their pid has been reused by another binary.
+// It must be called before StartAllDaemons when GlusterD starts.
+func SweepStaleFiles(rundir string, logger log.FieldLogger) {
+	ds, err := getDaemons()
+	if err != nil {
+		logger.WithError(err).Warn("failed to get saved daemons, skipping check of their pidfiles")
+	}
+
+	for _, d := range ds {
+		pid, err := ReadPidFromFile(d.PidFile())
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err == nil && IsProcessOf(pid, d.Path()) {
+			continue
+		}
+
+		dlogger := logger.WithFields(log.Fields{
+			"name":    d.Name(),
+			"pidfile": d.PidFile(),
+		})
+		if err := os.Remove(d.PidFile()); err != nil && !os.IsNotExist(err) {
+			dlogger.WithError(err).Warn("failed to remove stale pidfile of daemon")
+			continue
+		}
+		dlogger.Info("removed stale pidfile of daemon")
+
+		if sock := d.SocketFile(); sock != "" {
+			if err := os.Remove(sock); err != nil && !os.IsNotExist(err) {
+				dlogger.WithError(err).WithField("socket", sock).Warn("failed to remove socket file of daemon")
+			}
+		}
+	}
+
+	removed, err := RemoveStalePidFiles(rundir)
+	if err != nil {
+		logger.WithError(err).Warn("failed to remove stale pidfiles")
+	}
+	for _, f := range removed {
+		logger.WithField("pidfile", f).Info("removed stale pidfile")
+	}
+
+	removed, err = RemoveStaleSockets(rundir)
+	if err != nil {
+		logger.WithError(err).Warn("failed to remove stale sockets")
+	}
+	for _, f := range removed {
+		logger.WithField("socket", f).Info("removed stale socket")
+	}
+}
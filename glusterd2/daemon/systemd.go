@@ -0,0 +1,236 @@
+package daemon
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// systemdRuntimeDir exists only when the host was booted with systemd
+	systemdRuntimeDir = "/run/systemd/system"
+
+	// systemdUnitDir is where units of daemons are generated. Units in
+	// /run are not persisted across reboots, and daemons are started again
+	// by GlusterD anyway.
+	systemdUnitDir = "/run/systemd/system"
+
+	systemdUnitPrefix = "glusterd2-"
+)
+
+// systemdOpKeys maps the classes of daemons which can be run as systemd
+// units to the cluster options selecting it. Daemons are classed by their
+// names.
+var systemdOpKeys = map[string]string{
+	"glustershd": "cluster.glustershd-systemd",
+	"scrubd":     "cluster.scrubd-systemd",
+	"gsyncd":     "cluster.gsyncd-systemd",
+}
+
+// The daemons fork to the background once they are up and write their pid to
+// the pidfile, like they do when spawned by GlusterD
+var unitTemplate = template.Must(template.New("unit").Funcs(template.FuncMap{
+	"quote": quoteUnitArgs,
+}).Parse(`# Generated by GlusterD2, do not edit
+[Unit]
+Description=GlusterD2 managed {{.Name}} ({{.ID}})
+After=network.target
+
+[Service]
+Type=forking
+PIDFile={{.PidFile}}
+ExecStart={{quote .Path .Args}}
+Restart=on-failure
+RestartSec=5
+SyslogIdentifier={{.Identifier}}
+{{- with .Env}}
+{{- if .Umask}}
+UMask={{.Umask}}
+{{- end}}
+{{- if .Nice}}
+Nice={{.Nice}}
+{{- end}}
+{{- if .IONiceClass}}
+IOSchedulingClass={{.IONiceClass}}
+{{- if ne .IONiceClass "idle"}}
+IOSchedulingPriority={{.IONiceLevel}}
+{{- end}}
+{{- end}}
+{{- if .OOMScoreAdj}}
+OOMScoreAdjust={{.OOMScoreAdj}}
+{{- end}}
+{{- if .NoFile}}
+LimitNOFILE={{.NoFile}}
+{{- end}}
+{{- if .User}}
+User={{.User}}
+{{- end}}
+{{- if .Group}}
+Group={{.Group}}
+{{- end}}
+{{- end}}
+`))
+
+type unitParams struct {
+	Name       string
+	ID         string
+	Identifier string
+	Path       string
+	Args       []string
+	PidFile    string
+	Env        *SpawnEnv
+}
+
+// useSystemd returns true if the daemon has to be run as a systemd unit, as
+// selected by the cluster option for its class. Daemons are always spawned
+// directly on hosts not booted with systemd.
+func useSystemd(d Daemon) bool {
+	key, ok := systemdOpKeys[d.Name()]
+	if !ok {
+		return false
+	}
+
+	value, err := options.GetClusterOption(key)
+	if err != nil {
+		log.WithError(err).WithField("option", key).Warn("failed to get cluster option, spawning daemon directly")
+		return false
+	}
+	if on, err := options.StringToBoolean(value); err != nil || !on {
+		return false
+	}
+
+	if fi, err := os.Stat(systemdRuntimeDir); err != nil || !fi.IsDir() {
+		log.WithField("name", d.Name()).Warn("systemd is not running, spawning daemon directly")
+		return false
+	}
+	return true
+}
+
+// unitName returns the name of the systemd unit of the daemon
+func unitName(d Daemon) string {
+	name := d.Name()
+	if id := d.ID(); id != "" && id != name {
+		name += "-" + id
+	}
+	return systemdUnitPrefix + escapeUnitName(name) + ".service"
+}
+
+func unitFile(d Daemon) string {
+	return path.Join(systemdUnitDir, unitName(d))
+}
+
+// hasUnit returns true if the daemon was started as a systemd unit
+func hasUnit(d Daemon) bool {
+	_, err := os.Stat(unitFile(d))
+	return err == nil
+}
+
+// escapeUnitName escapes the characters not allowed in unit names as \xNN,
+// like systemd-escape does
+func escapeUnitName(s string) string {
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i == 0, !isUnitNameChar(c):
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func isUnitNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == ':' || c == '_' || c == '.' || c == '-'
+}
+
+// quoteUnitArgs quotes the command line for use in ExecStart, escaping the
+// characters which systemd would otherwise interpret
+func quoteUnitArgs(path string, args []string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `%`, `%%`, `$`, `$$`)
+
+	quoted := make([]string, 0, len(args)+1)
+	for _, a := range append([]string{path}, args...) {
+		quoted = append(quoted, `"`+r.Replace(a)+`"`)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// renderUnit returns the systemd unit of the daemon
+func renderUnit(d Daemon) ([]byte, error) {
+	params := unitParams{
+		Name:       d.Name(),
+		ID:         d.ID(),
+		Identifier: strings.TrimSuffix(unitName(d), ".service"),
+		Path:       d.Path(),
+		Args:       d.Args(),
+		PidFile:    d.PidFile(),
+	}
+
+	if s, ok := d.(SpawnEnver); ok {
+		env, err := s.SpawnEnv()
+		if err != nil {
+			return nil, err
+		}
+		if !env.IsEmpty() {
+			if err := env.Validate(); err != nil {
+				return nil, err
+			}
+			params.Env = env
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := unitTemplate.Execute(&buf, params); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// startUnit generates the systemd unit of the daemon and starts it. systemctl
+// returns once the daemon has forked to the background.
+func startUnit(d Daemon) error {
+	unit, err := renderUnit(d)
+	if err != nil {
+		return err
+	}
+
+	if old, err := ioutil.ReadFile(unitFile(d)); err != nil || !bytes.Equal(old, unit) {
+		if err := ioutil.WriteFile(unitFile(d), unit, 0644); err != nil {
+			return err
+		}
+		if err := utils.ExecuteCommandRun("systemctl", "daemon-reload"); err != nil {
+			return err
+		}
+	}
+
+	return utils.ExecuteCommandRun("systemctl", "start", unitName(d))
+}
+
+// stopUnit stops the systemd unit of the daemon and removes it. When force is
+// true, the daemon is sent a SIGKILL before the unit is stopped.
+func stopUnit(d Daemon, force bool) error {
+	if force {
+		// The unit is stopped below even if the daemon can't be killed
+		_ = utils.ExecuteCommandRun("systemctl", "kill", "--signal=SIGKILL", unitName(d))
+	}
+
+	err := utils.ExecuteCommandRun("systemctl", "stop", unitName(d))
+
+	if rerr := os.Remove(unitFile(d)); rerr == nil {
+		_ = utils.ExecuteCommandRun("systemctl", "daemon-reload")
+	}
+
+	return err
+}
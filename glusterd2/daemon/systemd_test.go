@@ -0,0 +1,43 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeUnitName(t *testing.T) {
+	assert.Equal(t, "glustershd", escapeUnitName("glustershd"))
+	assert.Equal(t, "gsyncd-a1b2-c3d4", escapeUnitName("gsyncd-a1b2-c3d4"))
+	assert.Equal(t, `\x2evol\x2fbrick\x20a`, escapeUnitName(".vol/brick a"))
+}
+
+func TestQuoteUnitArgs(t *testing.T) {
+	assert.Equal(t, `"/usr/sbin/glusterfs" "-s" "localhost"`, quoteUnitArgs("/usr/sbin/glusterfs", []string{"-s", "localhost"}))
+	assert.Equal(t, `"/bin/x" "100%%" "$$HOME" "a\"b\\c"`, quoteUnitArgs("/bin/x", []string{"100%", "$HOME", `a"b\c`}))
+}
+
+func TestRenderUnit(t *testing.T) {
+	d := &testDaemon{}
+	assert.Equal(t, "glusterd2-test.service", unitName(d))
+
+	unit, err := renderUnit(d)
+	assert.Nil(t, err)
+	assert.Contains(t, string(unit), `ExecStart="/usr/sbin/glusterfsd" "-s" "localhost"`)
+	assert.Contains(t, string(unit), "Restart=on-failure")
+	assert.Contains(t, string(unit), "SyslogIdentifier=glusterd2-test\n")
+	assert.NotContains(t, string(unit), "Nice=")
+
+	d.env = &SpawnEnv{Nice: 5, IONiceClass: IONiceClassIdle, IONiceLevel: 3, NoFile: 4096, User: "gluster"}
+	unit, err = renderUnit(d)
+	assert.Nil(t, err)
+	assert.Contains(t, string(unit), "Nice=5\n")
+	assert.Contains(t, string(unit), "IOSchedulingClass=idle\n")
+	assert.NotContains(t, string(unit), "IOSchedulingPriority=")
+	assert.Contains(t, string(unit), "LimitNOFILE=4096\n")
+	assert.Contains(t, string(unit), "User=gluster\n")
+
+	d.env = &SpawnEnv{Nice: 20}
+	_, err = renderUnit(d)
+	assert.NotNil(t, err)
+}
@@ -69,6 +69,56 @@ func GetProcess(pid int) (*os.Process, error) {
 	return process, nil
 }
 
+// RemoveStalePidFiles removes pidfiles under dir, and its immediate
+// sub-directories, which do not point to a running process. Sub-directories on
+// a different filesystem, like brick and volume mounts, are not looked into.
+// The names of the removed files are returned.
+func RemoveStalePidFiles(dir string) ([]string, error) {
+	var (
+		removed []string
+		rootDev uint64
+	)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if info.IsDir() {
+			st, ok := info.Sys().(*syscall.Stat_t)
+			if path == dir {
+				if ok {
+					rootDev = uint64(st.Dev)
+				}
+				return nil
+			}
+			if filepath.Dir(path) != filepath.Clean(dir) || (ok && uint64(st.Dev) != rootDev) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".pid" {
+			return nil
+		}
+
+		if pid, err := ReadPidFromFile(path); err == nil {
+			if _, err := GetProcess(pid); err == nil {
+				return nil
+			}
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		removed = append(removed, path)
+		return nil
+	})
+
+	return removed, err
+}
+
 // IsRunning returns true if the specified daemon is running and returns
 // false otherwise.
 func IsRunning(d Daemon) (bool, int) {
@@ -78,7 +128,7 @@ func IsRunning(d Daemon) (bool, int) {
 		return false, -1
 	}
 
-	if _, err := GetProcess(pid); err != nil {
+	if !IsProcessOf(pid, d.Path()) {
 		return false, -1
 	}
 
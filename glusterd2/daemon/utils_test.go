@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveStalePidFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gd2-pidfiles")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	running := filepath.Join(dir, "running.pid")
+	stale := filepath.Join(dir, "stale.pid")
+	garbage := filepath.Join(dir, "garbage.pid")
+	other := filepath.Join(dir, "other.txt")
+	sub := filepath.Join(dir, "sub", "stale.pid")
+	nested := filepath.Join(dir, "sub", "deeper", "stale.pid")
+
+	assert.NoError(t, WritePidToFile(os.Getpid(), running))
+	assert.NoError(t, WritePidToFile(1<<30, stale))
+	assert.NoError(t, ioutil.WriteFile(garbage, []byte("garbage"), 0644))
+	assert.NoError(t, ioutil.WriteFile(other, []byte("1073741824"), 0644))
+	assert.NoError(t, WritePidToFile(1<<30, sub))
+	assert.NoError(t, WritePidToFile(1<<30, nested))
+
+	removed, err := RemoveStalePidFiles(dir)
+	assert.NoError(t, err)
+	assert.Len(t, removed, 3)
+
+	for _, f := range []string{running, other, nested} {
+		_, err := os.Stat(f)
+		assert.NoError(t, err)
+	}
+	for _, f := range []string{stale, garbage, sub} {
+		_, err := os.Stat(f)
+		assert.True(t, os.IsNotExist(err))
+	}
+
+	removed, err = RemoveStalePidFiles(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, removed)
+}
+
+func TestIsProcessOf(t *testing.T) {
+	assert.True(t, IsProcessOf(os.Getpid(), os.Args[0]))
+	assert.False(t, IsProcessOf(os.Getpid(), "/nonexistent/gd2-test-binary"))
+	assert.False(t, IsProcessOf(1<<30, os.Args[0]))
+}
+
+func TestRemoveStaleSockets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gd2-sockets")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	live := filepath.Join(dir, "live.socket")
+	stale := filepath.Join(dir, "stale.socket")
+	other := filepath.Join(dir, "other.socket")
+
+	l, err := net.Listen("unix", live)
+	assert.NoError(t, err)
+	defer l.Close()
+
+	s, err := net.Listen("unix", stale)
+	assert.NoError(t, err)
+	s.(*net.UnixListener).SetUnlinkOnClose(false)
+	s.Close()
+
+	assert.NoError(t, ioutil.WriteFile(other, nil, 0644))
+
+	removed, err := RemoveStaleSockets(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{stale}, removed)
+
+	for _, f := range []string{live, other} {
+		_, err := os.Stat(f)
+		assert.NoError(t, err)
+	}
+	_, err = os.Stat(stale)
+	assert.True(t, os.IsNotExist(err))
+}
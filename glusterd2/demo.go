@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// Demo mode runs GD2 as an unprivileged, throwaway single node cluster for
+// trying out the REST API and the CLI. All state is kept in a temporary dir,
+// the REST and store endpoints only listen on localhost, and the cluster is
+// seeded with simulated peers and brick dirs.
+const (
+	demoCmd            = "demo"
+	demoSimulatedPeers = 2
+	demoBricksPerPeer  = 2
+	demoClientAddress  = "127.0.0.1:24007"
+	demoPeerAddress    = "127.0.0.1:24008"
+	demoStoreCURL      = "http://127.0.0.1:2379"
+	demoStorePURL      = "http://127.0.0.1:2380"
+
+	// demoPeerKey marks the simulated peers in the peer metadata
+	demoPeerKey = "_demo"
+)
+
+var (
+	// demoMode is true when GD2 is started as `glusterd2 demo`
+	demoMode bool
+	// demoDir is the temporary dir holding all the state of the demo
+	demoDir string
+)
+
+// parseDemoCmd checks if GD2 was started in demo mode, and removes the demo
+// command from the arguments so that the flags are parsed as usual
+func parseDemoCmd() {
+	if len(os.Args) > 1 && os.Args[1] == demoCmd {
+		demoMode = true
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+}
+
+// initDemo creates the temporary dir for the demo and points the GD2 config
+// to it. Options set here take precedence over flags and the config file.
+func initDemo() error {
+	dir, err := ioutil.TempDir("", "glusterd2-demo-")
+	if err != nil {
+		return err
+	}
+	demoDir = dir
+
+	config.Set("localstatedir", path.Join(dir, "state"))
+	config.Set("rundir", path.Join(dir, "run"))
+	config.Set("logdir", path.Join(dir, "log"))
+	config.Set("clientaddress", demoClientAddress)
+	config.Set("peeraddress", demoPeerAddress)
+	config.Set("restauth", false)
+	config.Set("etcdcurls", []string{demoStoreCURL})
+	config.Set("etcdpurls", []string{demoStorePURL})
+	config.Set("etcdendpoints", []string{demoStoreCURL})
+	config.Set("noembed", false)
+
+	return utils.InitDir(config.GetString("localstatedir"))
+}
+
+// cleanupDemo deletes the temporary dir of the demo
+func cleanupDemo() {
+	if demoDir == "" {
+		return
+	}
+	if err := os.RemoveAll(demoDir); err != nil {
+		log.WithError(err).WithField("dir", demoDir).Warn("failed to remove demo dir")
+	}
+}
+
+// seedDemo adds the simulated peers to the store and creates brick dirs for
+// all the peers. Simulated peers are marked online, but they are not backed by
+// a running GD2, so operations which need to run on them will fail. Their
+// brick dirs are created locally, under the demo dir, like those of this GD2.
+func seedDemo() error {
+	peers := []*peer.Peer{}
+
+	self, err := peer.GetPeer(gdctx.MyUUID.String())
+	if err != nil {
+		return err
+	}
+	peers = append(peers, self)
+
+	for i := 1; i <= demoSimulatedPeers; i++ {
+		name := fmt.Sprintf("demo-peer-%d", i)
+		p, err := peer.GetPeerByName(name)
+		if err != nil && err != gderrors.ErrPeerNotFound {
+			return err
+		}
+		if p == nil {
+			// Use distinct loopback addresses, so that the simulated
+			// peers can be told apart by their address
+			addr := fmt.Sprintf("127.0.0.%d", i+1)
+			p = &peer.Peer{
+				ID:              uuid.NewRandom(),
+				Name:            name,
+				PeerAddresses:   []string{addr + ":24008"},
+				ClientAddresses: []string{addr + ":24007"},
+				Metadata:        map[string]string{demoPeerKey: "true"},
+			}
+			if err := peer.AddOrUpdatePeer(p); err != nil {
+				return err
+			}
+		}
+		if err := publishDemoLiveness(p); err != nil {
+			return err
+		}
+		peers = append(peers, p)
+	}
+
+	for _, p := range peers {
+		for j := 1; j <= demoBricksPerPeer; j++ {
+			if err := utils.InitDir(demoBrickPath(p, j)); err != nil {
+				return err
+			}
+		}
+	}
+
+	printDemoBanner(peers)
+	return nil
+}
+
+func demoBrickPath(p *peer.Peer, n int) string {
+	return path.Join(demoDir, "bricks", p.Name, "brick"+strconv.Itoa(n))
+}
+
+// publishDemoLiveness marks the simulated peer online for the lifetime of the
+// store session of this GD2
+func publishDemoLiveness(p *peer.Peer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := store.LivenessKeyPrefix + p.ID.String()
+	_, err := store.Store.Put(ctx, key, strconv.Itoa(os.Getpid()), clientv3.WithLease(store.Store.Session.Lease()))
+	return err
+}
+
+func printDemoBanner(peers []*peer.Peer) {
+	endpoint := "http://" + demoClientAddress
+
+	fmt.Printf("\nglusterd2 is running in demo mode, with all state in %s\n", demoDir)
+	fmt.Printf("REST API: %s\n\nPeers and bricks:\n", endpoint)
+	for _, p := range peers {
+		fmt.Printf("  %s (%s)\n", p.Name, p.ID)
+		for j := 1; j <= demoBricksPerPeer; j++ {
+			fmt.Printf("    %s:%s\n", p.ID, demoBrickPath(p, j))
+		}
+	}
+	fmt.Printf("\nTry:\n  glustercli --endpoints %s peer status\n", endpoint)
+	fmt.Printf("  curl %s/v1/peers\n\nThe demo dir is removed when glusterd2 is stopped.\n\n", endpoint)
+}
@@ -0,0 +1,270 @@
+// Package discovery implements bootstrapping of new peers into an existing
+// cluster using a DNS SRV record or a discovery URL.
+//
+// A new peer configured with either of them looks up the REST endpoints of
+// existing glusterd2 instances and requests one of them to add itself to the
+// cluster. This allows provisioning new nodes without knowing the address of
+// a specific peer.
+package discovery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/restclient"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	srvOpt        = "discovery-srv"
+	urlOpt        = "discovery-url"
+	secretFileOpt = "discovery-secret-file"
+	caFileOpt     = "discovery-ca-file"
+
+	// authUser is the user used by glusterd2 REST clients authenticated
+	// with the secret from the local auth file
+	authUser = "glustercli"
+
+	retryInterval  = time.Second * 10
+	maxAttempts    = 30
+	requestTimeout = time.Second * 60
+)
+
+// ErrNoEndpoints is returned when no endpoints could be discovered
+var ErrNoEndpoints = errors.New("no glusterd2 endpoints discovered")
+
+// InitFlags initializes the command line options for cluster discovery
+func InitFlags() {
+	flag.String(srvOpt, "", "DNS SRV record (eg. _glusterd2._tcp.example.com) listing the REST endpoints of an existing cluster to join.")
+	flag.String(urlOpt, "", "URL returning the list of REST endpoints of an existing cluster to join.")
+	flag.String(secretFileOpt, "", "File containing the REST auth secret of the cluster to join.")
+	flag.String(caFileOpt, "", "CA certificate to verify the REST endpoints of the cluster to join.")
+}
+
+// Enabled returns true if discovery of an existing cluster is configured
+func Enabled() bool {
+	return config.GetString(srvOpt) != "" || config.GetString(urlOpt) != ""
+}
+
+// Bootstrap joins this peer to the cluster found using the configured
+// discovery mechanism. Nothing is done if discovery is not configured or if
+// this peer is already part of a cluster with other peers. The join is
+// retried in the background until it succeeds or the attempts are exhausted.
+func Bootstrap() {
+	if !Enabled() {
+		return
+	}
+
+	peers, err := peer.GetPeerIDs()
+	if err == nil && len(peers) > 1 {
+		log.Debug("peer is already part of a cluster, skipping discovery")
+		return
+	}
+
+	go func() {
+		for attempt := 1; attempt <= maxAttempts; attempt++ {
+			err := joinCluster()
+			if err == nil {
+				return
+			}
+			log.WithError(err).WithField("attempt", attempt).Warn("failed to join cluster using discovery, will retry")
+			time.Sleep(retryInterval)
+		}
+		log.Error("giving up on joining cluster using discovery")
+	}()
+}
+
+// joinCluster discovers the endpoints of the cluster and requests the first
+// reachable one to add this peer
+func joinCluster() error {
+	endpoints, err := lookupEndpoints()
+	if err != nil {
+		return err
+	}
+
+	var secret string
+	if f := config.GetString(secretFileOpt); f != "" {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		secret = strings.TrimSpace(string(b))
+	}
+
+	req := api.PeerAddReq{
+		Addresses: []string{config.GetString("peeraddress")},
+	}
+
+	for _, endpoint := range endpoints {
+		logger := log.WithField("endpoint", endpoint)
+
+		if isSelf(endpoint) {
+			logger.Debug("skipping own endpoint")
+			continue
+		}
+
+		client, err := restclient.NewClientWithOpts(
+			restclient.WithBaseURL(endpoint),
+			restclient.WithUsername(authUser),
+			restclient.WithPassword(secret),
+			restclient.WithTLSConfig(&restclient.TLSOptions{CaCertFile: config.GetString(caFileOpt)}),
+			restclient.WithTimeOut(requestTimeout),
+		)
+		if err != nil {
+			logger.WithError(err).Warn("failed to create client for discovered endpoint")
+			continue
+		}
+
+		resp, err := client.PeerAdd(req)
+		if err != nil {
+			logger.WithError(err).Warn("discovered endpoint failed to add this peer")
+			continue
+		}
+
+		logger.WithField("peerid", resp.ID).Info("joined cluster using discovery")
+		return nil
+	}
+
+	return fmt.Errorf("none of the discovered endpoints %v added this peer", endpoints)
+}
+
+// isSelf returns true if the endpoint is the REST endpoint of this peer
+func isSelf(endpoint string) bool {
+	host := endpoint
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	host = strings.SplitN(host, "/", 2)[0]
+
+	local, err := utils.IsLocalAddress(host)
+	if err != nil || !local {
+		return false
+	}
+
+	_, port, err := net.SplitHostPort(host)
+	if err != nil {
+		return true
+	}
+	_, myPort, _ := net.SplitHostPort(config.GetString("clientaddress"))
+	return port == myPort
+}
+
+// lookupEndpoints returns the REST endpoints of the cluster using the
+// configured SRV record or discovery URL
+func lookupEndpoints() ([]string, error) {
+	var (
+		endpoints []string
+		err       error
+	)
+
+	if name := config.GetString(srvOpt); name != "" {
+		endpoints, err = lookupSRV(name)
+	} else {
+		endpoints, err = fetchURL(config.GetString(urlOpt))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	return endpoints, nil
+}
+
+// lookupSRV returns the endpoints listed by the SRV record, ordered by their
+// priority and weight
+func lookupSRV(name string) ([]string, error) {
+	_, addrs, err := net.LookupSRV("", "", name)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "http"
+	if config.GetString(caFileOpt) != "" {
+		scheme = "https"
+	}
+
+	endpoints := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		host := strings.TrimSuffix(addr.Target, ".")
+		endpoints = append(endpoints, fmt.Sprintf("%s://%s", scheme,
+			net.JoinHostPort(host, strconv.Itoa(int(addr.Port)))))
+	}
+	return endpoints, nil
+}
+
+// fetchURL returns the endpoints listed by the discovery URL
+func fetchURL(url string) ([]string, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery URL returned %s", resp.Status)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseEndpoints(b)
+}
+
+// parseEndpoints parses the response of a discovery URL. The response can
+// either be a JSON list of endpoints, a JSON object with an "endpoints" list
+// or plain text with one endpoint per line.
+func parseEndpoints(b []byte) ([]string, error) {
+	var (
+		endpoints []string
+		data      = strings.TrimSpace(string(b))
+	)
+
+	switch {
+	case strings.HasPrefix(data, "["):
+		if err := json.Unmarshal([]byte(data), &endpoints); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(data, "{"):
+		var obj struct {
+			Endpoints []string `json:"endpoints"`
+		}
+		if err := json.Unmarshal([]byte(data), &obj); err != nil {
+			return nil, err
+		}
+		endpoints = obj.Endpoints
+	default:
+		for _, line := range strings.Split(data, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			endpoints = append(endpoints, line)
+		}
+	}
+
+	for i, e := range endpoints {
+		if !strings.Contains(e, "://") {
+			endpoints[i] = "http://" + e
+		}
+	}
+
+	return endpoints, nil
+}
@@ -0,0 +1,24 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEndpoints(t *testing.T) {
+	expected := []string{"http://10.0.0.1:24007", "https://node2:24007"}
+
+	for _, input := range []string{
+		`["10.0.0.1:24007", "https://node2:24007"]`,
+		`{"endpoints": ["http://10.0.0.1:24007", "https://node2:24007"]}`,
+		"# glusterd2 endpoints\n10.0.0.1:24007\n\nhttps://node2:24007\n",
+	} {
+		endpoints, err := parseEndpoints([]byte(input))
+		assert.Nil(t, err)
+		assert.Equal(t, expected, endpoints)
+	}
+
+	_, err := parseEndpoints([]byte(`["10.0.0.1:24007"`))
+	assert.NotNil(t, err)
+}
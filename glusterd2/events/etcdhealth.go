@@ -0,0 +1,48 @@
+package events
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/elasticetcd"
+)
+
+// Events broadcast locally when the health of the embedded etcd server changes
+const (
+	eventEtcdDegraded      = "store.etcd-degraded"
+	eventEtcdRecovered     = "store.etcd-recovered"
+	eventEtcdRestarted     = "store.etcd-restarted"
+	eventEtcdRestartFailed = "store.etcd-restart-failed"
+)
+
+var etcdHealthEvents = map[elasticetcd.HealthState]string{
+	elasticetcd.HealthDegraded:      eventEtcdDegraded,
+	elasticetcd.HealthRecovered:     eventEtcdRecovered,
+	elasticetcd.HealthRestarted:     eventEtcdRestarted,
+	elasticetcd.HealthRestartFailed: eventEtcdRestartFailed,
+}
+
+// etcdHealthChanged broadcasts the change in health of the embedded etcd
+// server. The events are only broadcast locally, as the store may not be
+// usable to send them across the cluster.
+func etcdHealthChanged(state elasticetcd.HealthState, err error) {
+	name, ok := etcdHealthEvents[state]
+	if !ok {
+		return
+	}
+
+	data := map[string]string{
+		"peer.id": gdctx.MyUUID.String(),
+	}
+	if err != nil {
+		data["error"] = err.Error()
+	}
+	Broadcast(New(name, data, false))
+}
+
+func startEtcdHealthEvents() {
+	store.Store.SetEtcdHealthHandler(etcdHealthChanged)
+}
+
+func stopEtcdHealthEvents() {
+	store.Store.SetEtcdHealthHandler(nil)
+}
@@ -1,6 +1,7 @@
 package events
 
 import (
+	"encoding/json"
 	"strings"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/gluster/glusterd2/pkg/api"
 
 	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
 )
 
 // New returns a new Event with given information
@@ -23,6 +25,22 @@ func New(name string, data map[string]string, global bool) *api.Event {
 	}
 }
 
+// NewLifecycle returns a new global Event of the lifecycle of a resource,
+// carrying the full state of the resource
+func NewLifecycle(name string, data map[string]string, resourceType string, resource interface{}) *api.Event {
+	e := New(name, data, true)
+
+	b, err := json.Marshal(resource)
+	if err != nil {
+		log.WithError(err).WithField("event.name", name).Error("failed to marshal resource of lifecycle event")
+		return e
+	}
+	e.ResourceType = resourceType
+	e.Resource = b
+	e.SchemaVersion = api.EventSchemaVersion
+	return e
+}
+
 // Broadcast broadcasts events to all registered event handlers
 func Broadcast(e *api.Event) error {
 	handlers.RLock()
@@ -43,11 +61,13 @@ func Start() error {
 	registerGaneshaHandler()
 	registerHooksHandler()
 	startLivenessWatcher()
+	startEtcdHealthEvents()
 	return nil
 }
 
 // Stop stops the events framework, events will no longer be broadcast
 func Stop() error {
+	stopEtcdHealthEvents()
 	stopLivenessWatcher()
 	stopEventLogger()
 	StopGlobal()
@@ -0,0 +1,146 @@
+// Package exporter ships records of the cluster events and the audit log to
+// external collectors, like syslog servers and HTTP bulk endpoints, for
+// environments which require off-box audit trails.
+//
+// Exporters are configured cluster wide in the store. Every peer runs every
+// exporter, and exports the records produced on it. Records are batched and
+// sent in the background, and sending a batch is retried with a backoff when
+// it fails. Records are dropped, and counted in the statistics of the
+// exporter, when an exporter falls behind or runs out of retries.
+package exporter
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Record severities
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = 5
+	defaultMaxRetries    = 5
+
+	// queueSize is the number of records an exporter queues while sending
+	// is slow or failing, after which records are dropped
+	queueSize = 10000
+
+	minRetryInterval = time.Second
+	maxRetryInterval = time.Minute
+)
+
+var pid = os.Getpid()
+
+// Record is a record exported to the collectors
+type Record struct {
+	// Source is the source of the record, one of api.ExportSourceEvents
+	// or api.ExportSourceAudit
+	Source   string    `json:"source"`
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Severity string    `json:"severity"`
+	Time     time.Time `json:"timestamp"`
+	// Origin is the ID of the peer which produced the record
+	Origin uuid.UUID         `json:"origin"`
+	Host   string            `json:"host,omitempty"`
+	Data   map[string]string `json:"data,omitempty"`
+}
+
+var runners = struct {
+	sync.RWMutex
+	m map[string]*runner
+}{m: make(map[string]*runner)}
+
+// Export queues the record to be sent by the exporters of its source. It
+// doesn't block, records are dropped if the exporters have fallen behind.
+func Export(r *Record) {
+	runners.RLock()
+	defer runners.RUnlock()
+
+	for _, rn := range runners.m {
+		if rn.wants(r) {
+			rn.enqueue(r)
+		}
+	}
+}
+
+// Stats returns the statistics of the exporter on this peer, or nil if it
+// isn't running
+func Stats(name string) *api.ExporterStats {
+	runners.RLock()
+	defer runners.RUnlock()
+
+	rn, ok := runners.m[name]
+	if !ok {
+		return nil
+	}
+	return rn.stats()
+}
+
+// reload starts the exporters configured, restarting those whose
+// configuration has changed, and stops those no longer configured. Exporters
+// are stopped after they are removed, so that records aren't held up while
+// they send the records queued.
+func reload(confs []*api.Exporter) {
+	var stopped []*runner
+	defer func() {
+		for _, rn := range stopped {
+			rn.stop()
+		}
+	}()
+
+	runners.Lock()
+	defer runners.Unlock()
+
+	configured := make(map[string]bool, len(confs))
+	for _, conf := range confs {
+		configured[conf.Name] = true
+
+		if rn, ok := runners.m[conf.Name]; ok {
+			if sameConfig(rn.conf, conf) {
+				continue
+			}
+			stopped = append(stopped, rn)
+		}
+
+		log.WithField("exporter", conf.Name).Info("starting exporter")
+		runners.m[conf.Name] = newRunner(conf)
+	}
+
+	for name, rn := range runners.m {
+		if !configured[name] {
+			log.WithField("exporter", name).Info("stopping exporter")
+			stopped = append(stopped, rn)
+			delete(runners.m, name)
+		}
+	}
+}
+
+func sameConfig(a, b *api.Exporter) bool {
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(x) == string(y)
+}
+
+// stopAll stops all the exporters, sending the records they have queued
+func stopAll() {
+	reload(nil)
+}
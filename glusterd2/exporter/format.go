@@ -0,0 +1,171 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/version"
+)
+
+// CEF severities of the record severities
+var cefSeverities = map[string]int{
+	SeverityInfo:     3,
+	SeverityWarning:  6,
+	SeverityCritical: 9,
+}
+
+// Syslog severities of the record severities, as defined in RFC 5424
+var syslogSeverities = map[string]int{
+	SeverityInfo:     6,
+	SeverityWarning:  4,
+	SeverityCritical: 2,
+}
+
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"authpriv": 10,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// rfc5424Time is the timestamp format of RFC 5424, which allows at most 6
+// digits of fractional seconds
+const rfc5424Time = "2006-01-02T15:04:05.000000Z07:00"
+
+// formatRecord returns the record in the format
+func formatRecord(format string, r *Record) ([]byte, error) {
+	switch format {
+	case api.ExportFormatJSON:
+		return json.Marshal(r)
+	case api.ExportFormatCEF:
+		return []byte(formatCEF(r)), nil
+	}
+	return nil, fmt.Errorf("unsupported export format %s", format)
+}
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
+
+// formatCEF returns the record in the ArcSight Common Event Format. The data
+// of the record is added to the extension with keys prefixed with gd2.
+func formatCEF(r *Record) string {
+	severity, ok := cefSeverities[r.Severity]
+	if !ok {
+		severity = cefSeverities[SeverityInfo]
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "CEF:0|Gluster|GlusterD2|%s|%s|%s|%d|",
+		cefHeaderEscaper.Replace(version.GlusterdVersion),
+		cefHeaderEscaper.Replace(r.Name),
+		cefHeaderEscaper.Replace(r.Name),
+		severity)
+
+	ext := []string{
+		"rt=" + fmt.Sprint(r.Time.UnixNano()/1e6),
+		"cat=" + cefExtensionEscaper.Replace(r.Source),
+		"externalId=" + cefExtensionEscaper.Replace(r.ID),
+		"deviceExternalId=" + cefExtensionEscaper.Replace(r.Origin.String()),
+	}
+	if r.Host != "" {
+		ext = append(ext, "dvchost="+cefExtensionEscaper.Replace(r.Host))
+	}
+
+	keys := make([]string, 0, len(r.Data))
+	for k := range r.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		ext = append(ext, cefKey(k)+"="+cefExtensionEscaper.Replace(r.Data[k]))
+	}
+
+	b.WriteString(strings.Join(ext, " "))
+	return b.String()
+}
+
+// cefKey returns the key of the data in the CEF extension. Extension keys
+// can only have letters and digits, so the data keys are camel cased, like
+// gd2VolumeName for volume.name.
+func cefKey(key string) string {
+	var b bytes.Buffer
+	b.WriteString("gd2")
+	upper := true
+	for _, c := range key {
+		if c > unicode.MaxASCII || !(unicode.IsLetter(c) || unicode.IsDigit(c)) {
+			upper = true
+			continue
+		}
+		if upper {
+			c = unicode.ToUpper(c)
+			upper = false
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// formatSyslog returns the formatted record as an RFC 5424 message. The
+// source of the record is used as the MSGID.
+func formatSyslog(conf *api.ExporterSyslogConfig, r *Record, msg []byte) []byte {
+	facility, ok := syslogFacilities[conf.Facility]
+	if !ok {
+		facility = syslogFacilities["daemon"]
+	}
+	severity, ok := syslogSeverities[r.Severity]
+	if !ok {
+		severity = syslogSeverities[SeverityInfo]
+	}
+
+	appName := conf.AppName
+	if appName == "" {
+		appName = "glusterd2"
+	}
+	host := r.Host
+	if host == "" {
+		host = "-"
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %d %s - ",
+		facility*8+severity, r.Time.UTC().Format(rfc5424Time),
+		syslogHeaderField(host, 255), syslogHeaderField(appName, 48), pid,
+		syslogHeaderField(r.Source, 32))
+	b.Write(msg)
+	return b.Bytes()
+}
+
+// syslogHeaderField returns the value for a header field of an RFC 5424
+// message, which can only have printable ASCII characters other than space
+func syslogHeaderField(s string, max int) string {
+	f := strings.Map(func(c rune) rune {
+		if c <= ' ' || c > '~' {
+			return '_'
+		}
+		return c
+	}, s)
+	if len(f) > max {
+		f = f[:max]
+	}
+	if f == "" {
+		return "-"
+	}
+	return f
+}
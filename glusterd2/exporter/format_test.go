@@ -0,0 +1,92 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRecord() *Record {
+	return &Record{
+		Source:   api.ExportSourceEvents,
+		ID:       "id1",
+		Name:     "volume.deleted",
+		Severity: SeverityWarning,
+		Time:     time.Date(2018, 5, 1, 10, 0, 0, 0, time.UTC),
+		Origin:   uuid.Parse("4e2a2bcb-3a5b-4d5e-9f26-1b1dd3c0b0a0"),
+		Host:     "host1",
+		Data:     map[string]string{"volume.name": "vol|1=a\\b"},
+	}
+}
+
+func TestCEFKey(t *testing.T) {
+	assert.Equal(t, "gd2VolumeName", cefKey("volume.name"))
+	assert.Equal(t, "gd2BrickPeerId", cefKey("brick.peer-id"))
+	assert.Equal(t, "gd2Name", cefKey("name"))
+}
+
+func TestFormatCEF(t *testing.T) {
+	r := testRecord()
+	r.Name = "vol|ume"
+	cef := formatCEF(r)
+
+	assert.True(t, strings.HasPrefix(cef, "CEF:0|Gluster|GlusterD2|"))
+	assert.Contains(t, cef, `|vol\|ume|vol\|ume|6|`)
+	assert.Contains(t, cef, "rt=1525168800000")
+	assert.Contains(t, cef, "cat=events")
+	assert.Contains(t, cef, "dvchost=host1")
+	assert.Contains(t, cef, `gd2VolumeName=vol|1\=a\\b`)
+}
+
+func TestFormatSyslog(t *testing.T) {
+	r := testRecord()
+	conf := &api.ExporterSyslogConfig{Facility: "local0"}
+
+	msg := string(formatSyslog(conf, r, []byte("msg")))
+	// local0 (16) * 8 + warning (4)
+	assert.True(t, strings.HasPrefix(msg, "<132>1 2018-05-01T10:00:00.000000Z host1 glusterd2 "))
+	assert.True(t, strings.HasSuffix(msg, " events - msg"))
+
+	conf = &api.ExporterSyslogConfig{AppName: "gd2 app"}
+	msg = string(formatSyslog(conf, r, []byte("msg")))
+	// daemon (3) * 8 + warning (4)
+	assert.True(t, strings.HasPrefix(msg, "<28>1 "))
+	assert.Contains(t, msg, " gd2_app ")
+}
+
+func TestValidate(t *testing.T) {
+	e := &api.Exporter{
+		Name:   "siem",
+		Type:   api.ExporterHTTP,
+		Format: api.ExportFormatCEF,
+		HTTP:   &api.ExporterHTTPConfig{URL: "https://collector:8088/bulk"},
+	}
+	assert.Nil(t, Validate(e))
+
+	e.Sources = []string{"logs"}
+	assert.NotNil(t, Validate(e))
+	e.Sources = []string{api.ExportSourceAudit}
+
+	e.HTTP.URL = "ftp://collector"
+	assert.NotNil(t, Validate(e))
+
+	e = &api.Exporter{
+		Name:   "syslog",
+		Type:   api.ExporterSyslog,
+		Format: api.ExportFormatJSON,
+		Syslog: &api.ExporterSyslogConfig{Network: "tls", Address: "collector:6514"},
+	}
+	assert.Nil(t, Validate(e))
+
+	e.Syslog.Facility = "mail2"
+	assert.NotNil(t, Validate(e))
+	e.Syslog.Facility = ""
+
+	e.Syslog.Address = "collector"
+	assert.NotNil(t, Validate(e))
+}
@@ -0,0 +1,286 @@
+package exporter
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const sendTimeout = 30 * time.Second
+
+// permanentError is returned when sending a batch fails in a way retrying
+// won't fix, like the collector rejecting the request
+type permanentError struct {
+	error
+}
+
+// runner runs an exporter, sending the records queued in batches
+type runner struct {
+	conf  *api.Exporter
+	queue chan *Record
+	done  chan struct{}
+	wg    sync.WaitGroup
+
+	sent    uint64
+	dropped uint64
+
+	errMu   sync.Mutex
+	lastErr string
+}
+
+func newRunner(conf *api.Exporter) *runner {
+	rn := &runner{
+		conf:  conf,
+		queue: make(chan *Record, queueSize),
+		done:  make(chan struct{}),
+	}
+	rn.wg.Add(1)
+	go rn.run()
+	return rn
+}
+
+func (rn *runner) wants(r *Record) bool {
+	if len(rn.conf.Sources) == 0 {
+		return true
+	}
+	for _, s := range rn.conf.Sources {
+		if s == r.Source {
+			return true
+		}
+	}
+	return false
+}
+
+func (rn *runner) enqueue(r *Record) {
+	select {
+	case rn.queue <- r:
+	default:
+		atomic.AddUint64(&rn.dropped, 1)
+	}
+}
+
+func (rn *runner) stats() *api.ExporterStats {
+	rn.errMu.Lock()
+	defer rn.errMu.Unlock()
+
+	return &api.ExporterStats{
+		Sent:      atomic.LoadUint64(&rn.sent),
+		Dropped:   atomic.LoadUint64(&rn.dropped),
+		Queued:    len(rn.queue),
+		LastError: rn.lastErr,
+	}
+}
+
+// stop stops the runner once the records queued have been sent, without
+// retrying
+func (rn *runner) stop() {
+	close(rn.done)
+	rn.wg.Wait()
+}
+
+func (rn *runner) run() {
+	defer rn.wg.Done()
+
+	batchSize := rn.conf.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultBatchSize
+	}
+	interval := time.Duration(rn.conf.FlushInterval) * time.Second
+	if interval == 0 {
+		interval = defaultFlushInterval * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	batch := make([]*Record, 0, batchSize)
+	for {
+		select {
+		case r := <-rn.queue:
+			batch = append(batch, r)
+			if len(batch) < batchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-rn.done:
+			for len(rn.queue) > 0 && len(batch) < queueSize {
+				batch = append(batch, <-rn.queue)
+			}
+			for len(batch) > 0 {
+				n := len(batch)
+				if n > batchSize {
+					n = batchSize
+				}
+				rn.sendBatch(batch[:n], 0)
+				batch = batch[n:]
+			}
+			return
+		}
+
+		rn.sendBatch(batch, rn.maxRetries())
+		batch = batch[:0]
+	}
+}
+
+func (rn *runner) maxRetries() int {
+	if rn.conf.MaxRetries == 0 {
+		return defaultMaxRetries
+	}
+	return rn.conf.MaxRetries
+}
+
+// sendBatch sends the batch, retrying with an exponential backoff. Records
+// which can't be sent are dropped.
+func (rn *runner) sendBatch(batch []*Record, retries int) {
+	wait := minRetryInterval
+	for i := 0; ; i++ {
+		err := rn.send(batch)
+		if err == nil {
+			atomic.AddUint64(&rn.sent, uint64(len(batch)))
+			return
+		}
+
+		rn.errMu.Lock()
+		rn.lastErr = err.Error()
+		rn.errMu.Unlock()
+
+		_, permanent := err.(permanentError)
+		if permanent || i >= retries {
+			log.WithError(err).WithFields(log.Fields{
+				"exporter": rn.conf.Name,
+				"records":  len(batch),
+			}).Error("failed to export records, dropping them")
+			atomic.AddUint64(&rn.dropped, uint64(len(batch)))
+			return
+		}
+
+		log.WithError(err).WithField("exporter", rn.conf.Name).Warn("failed to export records, retrying")
+		select {
+		case <-rn.done:
+			// Retried at most once more on stopping
+			if retries > i+1 {
+				retries = i + 1
+			}
+		case <-time.After(wait):
+		}
+		if wait *= 2; wait > maxRetryInterval {
+			wait = maxRetryInterval
+		}
+	}
+}
+
+func (rn *runner) send(batch []*Record) error {
+	msgs := make([][]byte, 0, len(batch))
+	for _, r := range batch {
+		msg, err := formatRecord(rn.conf.Format, r)
+		if err != nil {
+			return permanentError{err}
+		}
+		msgs = append(msgs, msg)
+	}
+
+	switch rn.conf.Type {
+	case api.ExporterSyslog:
+		return sendSyslog(rn.conf.Syslog, batch, msgs)
+	case api.ExporterHTTP:
+		return sendHTTP(rn.conf.HTTP, rn.conf.Format, msgs)
+	}
+	return permanentError{fmt.Errorf("unsupported exporter type %s", rn.conf.Type)}
+}
+
+// sendSyslog sends the records as RFC 5424 messages. Messages are sent as
+// datagrams over UDP, and with octet counting framing, as described in RFC
+// 6587, over TCP and TLS.
+func sendSyslog(conf *api.ExporterSyslogConfig, batch []*Record, msgs [][]byte) error {
+	var (
+		conn net.Conn
+		err  error
+	)
+	switch conf.Network {
+	case "", "udp":
+		conn, err = net.DialTimeout("udp", conf.Address, sendTimeout)
+	case "tcp":
+		conn, err = net.DialTimeout("tcp", conf.Address, sendTimeout)
+	case "tls":
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: sendTimeout}, "tcp", conf.Address, nil)
+	default:
+		return permanentError{fmt.Errorf("unsupported syslog network %s", conf.Network)}
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(sendTimeout))
+	framed := conf.Network == "tcp" || conf.Network == "tls"
+	for i, r := range batch {
+		msg := formatSyslog(conf, r, msgs[i])
+		if framed {
+			msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+		}
+		if _, err := conn.Write(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendHTTP posts the records to the bulk endpoint, as a JSON array in the JSON
+// format, or as newline separated lines in the CEF format
+func sendHTTP(conf *api.ExporterHTTPConfig, format string, msgs [][]byte) error {
+	var body bytes.Buffer
+	contentType := "text/plain"
+	if format == api.ExportFormatJSON {
+		contentType = "application/json"
+		body.WriteByte('[')
+		body.Write(bytes.Join(msgs, []byte(",")))
+		body.WriteByte(']')
+	} else {
+		for _, m := range msgs {
+			body.Write(m)
+			body.WriteByte('\n')
+		}
+	}
+
+	req, err := http.NewRequest("POST", conf.URL, &body)
+	if err != nil {
+		return permanentError{err}
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range conf.Headers {
+		req.Header.Set(k, v)
+	}
+	if conf.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+conf.Token)
+	}
+
+	client := &http.Client{Timeout: sendTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return permanentError{fmt.Errorf("collector rejected records: %s", resp.Status)}
+}
@@ -0,0 +1,229 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	exporterPrefix = "config/exporters/"
+
+	// watchRetryInterval is the wait before reloading the exporters when
+	// the watch fails
+	watchRetryInterval = 5 * time.Second
+)
+
+var (
+	// ErrExporterNotFound is returned for exporters which are not configured
+	ErrExporterNotFound = errors.New("exporter not found")
+
+	// exporterNameRE matches valid exporter names, which are used in store
+	// keys
+	exporterNameRE = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+)
+
+// Validate checks the configuration of the exporter
+func Validate(e *api.Exporter) error {
+	if !exporterNameRE.MatchString(e.Name) {
+		return errors.New("invalid exporter name")
+	}
+
+	switch e.Format {
+	case api.ExportFormatJSON, api.ExportFormatCEF:
+	default:
+		return fmt.Errorf("unsupported export format %s", e.Format)
+	}
+
+	for _, s := range e.Sources {
+		if s != api.ExportSourceEvents && s != api.ExportSourceAudit {
+			return fmt.Errorf("invalid export source %s", s)
+		}
+	}
+
+	if e.BatchSize < 0 || e.FlushInterval < 0 || e.MaxRetries < 0 {
+		return errors.New("batch size, flush interval and max retries can't be negative")
+	}
+
+	switch e.Type {
+	case api.ExporterSyslog:
+		if e.Syslog == nil || e.Syslog.Address == "" {
+			return errors.New("syslog address is required")
+		}
+		switch e.Syslog.Network {
+		case "", "udp", "tcp", "tls":
+		default:
+			return fmt.Errorf("unsupported syslog network %s", e.Syslog.Network)
+		}
+		if _, _, err := net.SplitHostPort(e.Syslog.Address); err != nil {
+			return fmt.Errorf("invalid syslog address: %s", err)
+		}
+		if _, ok := syslogFacilities[e.Syslog.Facility]; e.Syslog.Facility != "" && !ok {
+			return fmt.Errorf("invalid syslog facility %s", e.Syslog.Facility)
+		}
+	case api.ExporterHTTP:
+		if e.HTTP == nil || e.HTTP.URL == "" {
+			return errors.New("HTTP URL is required")
+		}
+		u, err := url.Parse(e.HTTP.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid HTTP URL %s", e.HTTP.URL)
+		}
+	default:
+		return fmt.Errorf("unsupported exporter type %s", e.Type)
+	}
+
+	return nil
+}
+
+// Exists returns true if the exporter is configured
+func Exists(name string) (bool, error) {
+	resp, err := store.Get(context.TODO(), exporterPrefix+name, clientv3.WithCountOnly())
+	if err != nil {
+		return false, err
+	}
+	return resp.Count == 1, nil
+}
+
+// Add adds the exporter to the store, replacing an existing exporter with the
+// same name. The exporter is started on every peer.
+func Add(e *api.Exporter) error {
+	data, err := store.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), exporterPrefix+e.Name, string(data))
+	return err
+}
+
+// Delete deletes the exporter from the store. The exporter is stopped on
+// every peer.
+func Delete(name string) error {
+	resp, err := store.Delete(context.TODO(), exporterPrefix+name)
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return ErrExporterNotFound
+	}
+	return nil
+}
+
+// List returns the exporters configured
+func List() ([]*api.Exporter, error) {
+	confs, _, err := list(context.TODO())
+	return confs, err
+}
+
+func list(ctx context.Context) ([]*api.Exporter, int64, error) {
+	resp, err := store.Get(ctx, exporterPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	confs := make([]*api.Exporter, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var e api.Exporter
+		if err := store.Unmarshal(kv.Value, &e); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal exporter")
+			continue
+		}
+		confs = append(confs, &e)
+	}
+	return confs, resp.Header.Revision, nil
+}
+
+var watcher struct {
+	sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Start starts the exporters configured, and watches the store to start and
+// stop them as they are configured. Should only be called after the store is
+// up.
+func Start() error {
+	watcher.Lock()
+	defer watcher.Unlock()
+
+	if watcher.cancel != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	confs, rev, err := list(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	reload(confs)
+
+	watcher.cancel = cancel
+	watcher.wg.Add(1)
+	go watch(ctx, rev)
+	return nil
+}
+
+// Stop stops the exporters, sending the records they have queued
+func Stop() {
+	watcher.Lock()
+	defer watcher.Unlock()
+
+	if watcher.cancel == nil {
+		return
+	}
+	watcher.cancel()
+	watcher.wg.Wait()
+	watcher.cancel = nil
+
+	stopAll()
+}
+
+// watch reloads the exporters when they are changed in the store after rev
+func watch(ctx context.Context, rev int64) {
+	defer watcher.wg.Done()
+
+	for {
+		wch := store.Store.Watch(ctx, exporterPrefix, clientv3.WithPrefix(), clientv3.WithRev(rev+1))
+		for wresp := range wch {
+			if wresp.Canceled || wresp.Err() != nil {
+				break
+			}
+			confs, r, err := list(ctx)
+			if err != nil {
+				log.WithError(err).Warn("failed to reload exporters")
+				continue
+			}
+			reload(confs)
+			rev = r
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchRetryInterval):
+			}
+
+			// Changes may have been missed, so start over
+			confs, r, err := list(ctx)
+			if err == nil {
+				reload(confs)
+				rev = r
+				break
+			}
+			log.WithError(err).Warn("failed to reload exporters")
+		}
+	}
+}
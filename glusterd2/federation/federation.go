@@ -0,0 +1,283 @@
+// Package federation maintains the other glusterd2 clusters registered with
+// this cluster, and builds a read-only view of the volumes across them
+package federation
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/restclient"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	clusterPrefix = "config/federation/clusters/"
+
+	// requestTimeout bounds each request sent to a federated cluster, so
+	// that an unreachable cluster doesn't hold up the view of the others
+	requestTimeout = 10 * time.Second
+)
+
+var (
+	// ErrClusterNotFound is returned for clusters which are not registered
+	ErrClusterNotFound = errors.New("federated cluster not found")
+
+	// clusterNameRE matches valid cluster names, which are used in store
+	// keys
+	clusterNameRE = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+)
+
+// Validate checks the registration of the cluster
+func Validate(c *api.FederatedCluster) error {
+	if !clusterNameRE.MatchString(c.Name) {
+		return errors.New("invalid cluster name")
+	}
+
+	if len(c.Endpoints) == 0 {
+		return errors.New("at least one endpoint is required")
+	}
+	for _, e := range c.Endpoints {
+		u, err := url.Parse(e)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid endpoint %s", e)
+		}
+	}
+
+	if c.CACert != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(c.CACert)) {
+		return errors.New("invalid CA certificate")
+	}
+
+	return nil
+}
+
+// Add adds the cluster to the store, replacing an existing cluster with the
+// same name
+func Add(c *api.FederatedCluster) error {
+	data, err := store.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), clusterPrefix+c.Name, string(data))
+	return err
+}
+
+// Delete deletes the cluster from the store
+func Delete(name string) error {
+	resp, err := store.Delete(context.TODO(), clusterPrefix+name)
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return ErrClusterNotFound
+	}
+	return nil
+}
+
+// List returns the registered clusters sorted by name
+func List() ([]*api.FederatedCluster, error) {
+	resp, err := store.Get(context.TODO(), clusterPrefix, clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]*api.FederatedCluster, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var c api.FederatedCluster
+		if err := store.Unmarshal(kv.Value, &c); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal federated cluster")
+			continue
+		}
+		clusters = append(clusters, &c)
+	}
+	return clusters, nil
+}
+
+// newClient returns a REST client of the cluster. The client has its own
+// transport, as the clusters can be verified by different CAs.
+func newClient(c *api.FederatedCluster) (*restclient.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.Insecure}
+	if c.CACert != "" && !c.Insecure {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.CACert)) {
+			return nil, errors.New("invalid CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+
+	return restclient.NewClientWithOpts(
+		restclient.WithHTTPClient(httpClient),
+		restclient.WithEndpoints(c.Endpoints...),
+		restclient.WithUsername(c.Username),
+		restclient.WithPassword(c.Secret),
+		restclient.WithTimeOut(requestTimeout),
+	)
+}
+
+// View returns the volumes of the registered clusters along with the status
+// of the clusters. If names are given, only those clusters are queried. The
+// clusters are queried concurrently, and the clusters which can't be reached
+// are reported as such.
+func View(names ...string) (*api.FederatedVolumesResp, error) {
+	clusters, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) > 0 {
+		wanted := make(map[string]bool)
+		for _, n := range names {
+			wanted[n] = true
+		}
+		var selected []*api.FederatedCluster
+		for _, c := range clusters {
+			if wanted[c.Name] {
+				selected = append(selected, c)
+				delete(wanted, c.Name)
+			}
+		}
+		if len(wanted) > 0 {
+			return nil, ErrClusterNotFound
+		}
+		clusters = selected
+	}
+
+	var (
+		wg       sync.WaitGroup
+		statuses = make([]api.FederatedClusterStatus, len(clusters))
+		volumes  = make([][]api.FederatedVolume, len(clusters))
+	)
+	for i, c := range clusters {
+		wg.Add(1)
+		go func(i int, c *api.FederatedCluster) {
+			defer wg.Done()
+			statuses[i], volumes[i] = clusterView(c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	resp := &api.FederatedVolumesResp{
+		Clusters: statuses,
+		Volumes:  []api.FederatedVolume{},
+	}
+	for _, v := range volumes {
+		resp.Volumes = append(resp.Volumes, v...)
+	}
+	return resp, nil
+}
+
+// clusterView gets the peers and volumes of the cluster, and the status of
+// the bricks of its started volumes
+func clusterView(c *api.FederatedCluster) (api.FederatedClusterStatus, []api.FederatedVolume) {
+	status := api.FederatedClusterStatus{Name: c.Name}
+	logger := log.WithField("cluster", c.Name)
+
+	client, err := newClient(c)
+	if err != nil {
+		status.Error = err.Error()
+		return status, nil
+	}
+
+	peers, err := client.Peers()
+	if err != nil {
+		logger.WithError(err).Warn("failed to get peers of federated cluster")
+		status.Error = err.Error()
+		return status, nil
+	}
+	status.Reachable = true
+	status.PeersTotal = len(peers)
+	for _, p := range peers {
+		if p.Online {
+			status.PeersOnline++
+		}
+	}
+
+	vols, err := client.Volumes("")
+	if err != nil {
+		logger.WithError(err).Warn("failed to get volumes of federated cluster")
+		status.Error = err.Error()
+		return status, nil
+	}
+	status.Volumes = len(vols)
+
+	volumes := make([]api.FederatedVolume, 0, len(vols))
+	for _, v := range vols {
+		fv := api.FederatedVolume{
+			Cluster:     c.Name,
+			ID:          v.ID,
+			Name:        v.Name,
+			Type:        v.Type,
+			State:       v.State,
+			Capacity:    v.Capacity,
+			BricksTotal: countBricks(v.Subvols),
+		}
+
+		if v.State != api.VolStarted {
+			fv.Health = api.FederatedVolStopped
+			volumes = append(volumes, fv)
+			continue
+		}
+
+		bricks, err := client.BricksStatus(v.Name)
+		if err != nil {
+			logger.WithError(err).WithField("volume", v.Name).Warn("failed to get status of bricks of federated volume")
+			status.Error = fmt.Sprintf("failed to get status of bricks of volume %s: %s", v.Name, err)
+			fv.Health = api.FederatedVolUnknown
+			volumes = append(volumes, fv)
+			continue
+		}
+		for _, b := range bricks {
+			if b.Online {
+				fv.BricksOnline++
+			}
+		}
+		fv.Health = volumeHealth(fv.BricksTotal, fv.BricksOnline)
+		volumes = append(volumes, fv)
+	}
+
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Name < volumes[j].Name
+	})
+	return status, volumes
+}
+
+func countBricks(subvols []api.Subvol) int {
+	var count int
+	for _, s := range subvols {
+		count += len(s.Bricks) + countBricks(s.Subvols)
+	}
+	return count
+}
+
+// volumeHealth returns the health of a started volume from the number of its
+// bricks which are online
+func volumeHealth(total, online int) string {
+	switch {
+	case online == 0:
+		return api.FederatedVolDown
+	case online < total:
+		return api.FederatedVolDegraded
+	default:
+		return api.FederatedVolUp
+	}
+}
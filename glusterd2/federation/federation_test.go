@@ -0,0 +1,45 @@
+package federation
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	c := &api.FederatedCluster{
+		Name:      "east-1",
+		Endpoints: []string{"http://gd2-east-1:24007", "https://gd2-east-2:24007"},
+	}
+	assert.NoError(t, Validate(c))
+
+	c.Name = "east/1"
+	assert.Error(t, Validate(c))
+	c.Name = "east-1"
+
+	c.Endpoints = nil
+	assert.Error(t, Validate(c))
+
+	c.Endpoints = []string{"gd2-east-1:24007"}
+	assert.Error(t, Validate(c))
+
+	c.Endpoints = []string{"http://gd2-east-1:24007"}
+	c.CACert = "not a certificate"
+	assert.Error(t, Validate(c))
+}
+
+func TestVolumeHealth(t *testing.T) {
+	assert.Equal(t, api.FederatedVolUp, volumeHealth(6, 6))
+	assert.Equal(t, api.FederatedVolDegraded, volumeHealth(6, 4))
+	assert.Equal(t, api.FederatedVolDown, volumeHealth(6, 0))
+}
+
+func TestCountBricks(t *testing.T) {
+	subvols := []api.Subvol{
+		{Bricks: make([]api.BrickInfo, 3)},
+		{Bricks: make([]api.BrickInfo, 2), Subvols: []api.Subvol{{Bricks: make([]api.BrickInfo, 2)}}},
+	}
+	assert.Equal(t, 7, countBricks(subvols))
+}
@@ -2,6 +2,7 @@ package gdctx
 
 import (
 	"context"
+	"sync"
 
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
@@ -12,6 +13,10 @@ type ctxKeyType int
 const (
 	reqIDKey ctxKeyType = iota
 	reqLoggerKey
+	reqUserKey
+	txnIDKey
+	volNameKey
+	txnRecorderKey
 )
 
 // WithReqID returns a new context with provided request id set as a value in the context.
@@ -41,3 +46,107 @@ func GetReqLogger(ctx context.Context) log.FieldLogger {
 	}
 	return reqLogger
 }
+
+// WithReqUser returns a new context with the authenticated user set as a value in the context.
+func WithReqUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, reqUserKey, user)
+}
+
+// GetReqUser returns the authenticated user stored in the context provided.
+func GetReqUser(ctx context.Context) string {
+	user, ok := ctx.Value(reqUserKey).(string)
+	if !ok {
+		return ""
+	}
+	return user
+}
+
+// WithTxnID returns a new context with provided transaction id set as a value in the context.
+func WithTxnID(ctx context.Context, txnid uuid.UUID) context.Context {
+	return context.WithValue(ctx, txnIDKey, txnid)
+}
+
+// GetTxnID returns transaction ID stored in the context provided.
+func GetTxnID(ctx context.Context) uuid.UUID {
+	txnid, ok := ctx.Value(txnIDKey).(uuid.UUID)
+	if !ok {
+		return nil
+	}
+	return txnid
+}
+
+// txnRecorder collects the IDs of the transactions run for a request
+type txnRecorder struct {
+	mu  sync.Mutex
+	ids []uuid.UUID
+}
+
+// WithTxnRecorder returns a new context recording the IDs of the transactions
+// started with it, which are returned by RecordedTxnIDs.
+func WithTxnRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, txnRecorderKey, &txnRecorder{})
+}
+
+// RecordTxnID records the ID of a transaction started with the context, if
+// the context records them.
+func RecordTxnID(ctx context.Context, txnid uuid.UUID) {
+	if r, ok := ctx.Value(txnRecorderKey).(*txnRecorder); ok {
+		r.mu.Lock()
+		r.ids = append(r.ids, txnid)
+		r.mu.Unlock()
+	}
+}
+
+// RecordedTxnIDs returns the IDs of the transactions started with the context.
+func RecordedTxnIDs(ctx context.Context) []uuid.UUID {
+	r, ok := ctx.Value(txnRecorderKey).(*txnRecorder)
+	if !ok {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]uuid.UUID(nil), r.ids...)
+}
+
+// WithVolName returns a new context with the name of the volume being operated upon set as a value in the context.
+func WithVolName(ctx context.Context, volname string) context.Context {
+	return context.WithValue(ctx, volNameKey, volname)
+}
+
+// GetVolName returns the volume name stored in the context provided.
+func GetVolName(ctx context.Context) string {
+	volname, ok := ctx.Value(volNameKey).(string)
+	if !ok {
+		return ""
+	}
+	return volname
+}
+
+// LogFields returns the log fields identifying the request, transaction and
+// volume the context is associated with. These fields are carried over to
+// the other peers taking part in a transaction, so that the logs of an
+// operation can be correlated across the cluster.
+func LogFields(ctx context.Context) log.Fields {
+	fields := log.Fields{}
+	if reqid := GetReqID(ctx); reqid != nil {
+		fields["reqid"] = reqid.String()
+	}
+	if txnid := GetTxnID(ctx); txnid != nil {
+		fields["txnid"] = txnid.String()
+	}
+	if volname := GetVolName(ctx); volname != "" {
+		fields["volume"] = volname
+	}
+	return fields
+}
+
+// Logger returns a logger with the fields returned by LogFields and the ID of
+// this peer. The request scoped logger stored in the context is used as the
+// base logger if present.
+func Logger(ctx context.Context) log.FieldLogger {
+	logger := GetReqLogger(ctx)
+	if logger == nil {
+		logger = log.StandardLogger()
+	}
+	return logger.WithFields(LogFields(ctx)).WithField("peerid", MyUUID.String())
+}
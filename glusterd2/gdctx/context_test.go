@@ -30,3 +30,23 @@ func TestWithReqLogger(t *testing.T) {
 	assert.NotNil(t, newlog)
 
 }
+
+func TestLogFields(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, LogFields(ctx))
+
+	reqID := uuid.NewRandom()
+	txnID := uuid.NewRandom()
+	ctx = WithVolName(WithTxnID(WithReqID(ctx, reqID), txnID), "vol1")
+
+	assert.Equal(t, log.Fields{
+		"reqid":  reqID.String(),
+		"txnid":  txnID.String(),
+		"volume": "vol1",
+	}, LogFields(ctx))
+
+	entry, ok := Logger(ctx).(*log.Entry)
+	assert.True(t, ok)
+	assert.Equal(t, "vol1", entry.Data["volume"])
+	assert.Contains(t, entry.Data, "peerid")
+}
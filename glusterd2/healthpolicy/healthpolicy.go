@@ -0,0 +1,174 @@
+// Package healthpolicy manages the cluster wide policy of the periodic health
+// checks run by the peers, applying it on every peer as it is changed
+package healthpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/elasticetcd"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	policyKey = "config/health-policy"
+
+	// maxInterval is the longest interval allowed between health checks,
+	// in seconds
+	maxInterval = 3600
+	// maxUnresponsiveThreshold is the largest number of failed health
+	// checks allowed before acting on them
+	maxUnresponsiveThreshold = 100
+
+	// watchRetryInterval is the wait before reloading the policy when the
+	// watch fails
+	watchRetryInterval = 5 * time.Second
+)
+
+// Validate checks the health policy
+func Validate(p *api.HealthPolicy) error {
+	for _, interval := range []int{p.EtcdHealthInterval, p.EtcdHealthTimeout, p.StoreHealthInterval} {
+		if interval < 0 || interval > maxInterval {
+			return fmt.Errorf("intervals and timeouts must be between 0 and %d seconds", maxInterval)
+		}
+	}
+	if p.EtcdUnresponsiveThreshold < 0 || p.EtcdUnresponsiveThreshold > maxUnresponsiveThreshold {
+		return fmt.Errorf("unresponsive threshold must be between 0 and %d", maxUnresponsiveThreshold)
+	}
+	return nil
+}
+
+// Get returns the health policy of the cluster. The policy is empty if it
+// has not been set.
+func Get() (*api.HealthPolicy, error) {
+	p, _, err := get(context.TODO())
+	return p, err
+}
+
+func get(ctx context.Context) (*api.HealthPolicy, int64, error) {
+	resp, err := store.Get(ctx, policyKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var p api.HealthPolicy
+	if resp.Count == 1 {
+		if err := json.Unmarshal(resp.Kvs[0].Value, &p); err != nil {
+			return nil, 0, err
+		}
+	}
+	return &p, resp.Header.Revision, nil
+}
+
+// Set saves the health policy of the cluster, which is applied on every peer
+func Set(p *api.HealthPolicy) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), policyKey, string(data))
+	return err
+}
+
+func seconds(s int) time.Duration {
+	return time.Duration(s) * time.Second
+}
+
+// apply applies the health policy to the health checks of this peer
+func apply(p *api.HealthPolicy) {
+	store.Store.SetEtcdHealthPolicy(elasticetcd.HealthPolicy{
+		Interval:              seconds(p.EtcdHealthInterval),
+		Timeout:               seconds(p.EtcdHealthTimeout),
+		UnresponsiveThreshold: p.EtcdUnresponsiveThreshold,
+	})
+	store.Store.SetSessionCheckInterval(seconds(p.StoreHealthInterval))
+	log.WithField("policy", *p).Debug("applied health policy")
+}
+
+var watcher struct {
+	sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Start applies the health policy, and watches the store to apply it again
+// whenever it is changed. Should only be called after the store is up.
+func Start() error {
+	watcher.Lock()
+	defer watcher.Unlock()
+
+	if watcher.cancel != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p, rev, err := get(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	apply(p)
+
+	watcher.cancel = cancel
+	watcher.wg.Add(1)
+	go watch(ctx, rev)
+	return nil
+}
+
+// Stop stops watching the health policy
+func Stop() {
+	watcher.Lock()
+	defer watcher.Unlock()
+
+	if watcher.cancel == nil {
+		return
+	}
+	watcher.cancel()
+	watcher.wg.Wait()
+	watcher.cancel = nil
+}
+
+// watch applies the health policy when it is changed in the store after rev
+func watch(ctx context.Context, rev int64) {
+	defer watcher.wg.Done()
+
+	for {
+		wch := store.Store.Watch(ctx, policyKey, clientv3.WithRev(rev+1))
+		for wresp := range wch {
+			if wresp.Canceled || wresp.Err() != nil {
+				break
+			}
+			p, r, err := get(ctx)
+			if err != nil {
+				log.WithError(err).Warn("failed to reload health policy")
+				continue
+			}
+			apply(p)
+			rev = r
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchRetryInterval):
+			}
+
+			// Changes may have been missed, so start over
+			p, r, err := get(ctx)
+			if err == nil {
+				apply(p)
+				rev = r
+				break
+			}
+			log.WithError(err).Warn("failed to reload health policy")
+		}
+	}
+}
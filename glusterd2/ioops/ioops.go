@@ -0,0 +1,466 @@
+// Package ioops coordinates the IO intensive operations, scrub, rebalance
+// and full heal, across the cluster so that they don't run together on the
+// same bricks. An operation conflicting with the operations running on its
+// bricks is queued, or rejected, and is started once they complete. An
+// operation of higher priority throttles the conflicting operations of lower
+// priority instead of waiting for them.
+package ioops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	claimPrefix = "io-ops/"
+	// lockID serializes the decisions taken on the claims across the
+	// cluster
+	lockID = "io-ops"
+
+	// priorityOpt orders the operations by priority, highest first
+	priorityOpt = "cluster.io-ops-priority"
+	// policyOpt is what is done with an operation conflicting with the
+	// operations running on its bricks: "queue" it, "reject" it, or "off"
+	// to not coordinate the operations at all
+	policyOpt    = "cluster.io-ops-policy"
+	policyQueue  = "queue"
+	policyReject = "reject"
+	policyOff    = "off"
+
+	reconcileJobName     = "io-ops.reconcile"
+	reconcileJobSchedule = "@every 1m"
+
+	// throttleRetries is the number of attempts made to throttle an
+	// operation, as its volume can be locked by the transaction which
+	// acquired the conflicting claim
+	throttleRetries = 5
+)
+
+// ErrQueued is returned by Acquire when the operation has been queued, to be
+// started once the conflicting operations on its bricks complete
+var ErrQueued = errors.New("operation queued until the conflicting operations on its bricks complete")
+
+// Handler is registered by the components running an operation
+type Handler struct {
+	// Start starts the queued operation with the arguments it was
+	// requested with. It is expected to call Acquire again.
+	Start func(ctx context.Context, c *Claim) error
+	// Running returns true if the operation is still running on the
+	// volume
+	Running func(ctx context.Context, c *Claim) (bool, error)
+	// Throttle throttles or restores the running operation. It is nil for
+	// operations which can't be throttled.
+	Throttle func(ctx context.Context, c *Claim, throttled bool) error
+}
+
+var (
+	handlersMu sync.RWMutex
+	handlers   = make(map[Op]*Handler)
+)
+
+// Register registers the handler of the operation
+func Register(op Op, h *Handler) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	handlers[op] = h
+}
+
+func getHandler(op Op) (*Handler, bool) {
+	handlersMu.RLock()
+	defer handlersMu.RUnlock()
+	h, ok := handlers[op]
+	return h, ok
+}
+
+func canThrottle(op Op) bool {
+	h, ok := getHandler(op)
+	return ok && h.Throttle != nil
+}
+
+func validateOption(key, value string) error {
+	switch key {
+	case priorityOpt:
+		_, err := parsePriorities(value)
+		return err
+	case policyOpt:
+		switch value {
+		case policyQueue, policyReject, policyOff:
+			return nil
+		}
+		return fmt.Errorf("policy must be one of %s, %s or %s", policyQueue, policyReject, policyOff)
+	}
+	return nil
+}
+
+func init() {
+	options.RegisterClusterOpValidationFunc(priorityOpt, validateOption)
+	options.RegisterClusterOpValidationFunc(policyOpt, validateOption)
+}
+
+// RegisterJob registers the scheduled job releasing the claims of the
+// operations which have completed, and starting the queued operations
+func RegisterJob() {
+	err := scheduler.Register(&scheduler.Job{
+		Name:        reconcileJobName,
+		Description: "Releases the bricks of completed scrub, rebalance and full heal operations, and starts the operations queued on them",
+		Schedule:    reconcileJobSchedule,
+		Enabled:     true,
+		Func:        reconcile,
+	})
+	if err != nil {
+		log.WithError(err).WithField("job", reconcileJobName).Error("failed to register scheduled job")
+	}
+}
+
+// settings returns the policy and the priorities of the operations
+func settings() (string, priorities, error) {
+	policy, err := options.GetClusterOption(policyOpt)
+	if err != nil {
+		return "", nil, err
+	}
+	value, err := options.GetClusterOption(priorityOpt)
+	if err != nil {
+		return "", nil, err
+	}
+	p, err := parsePriorities(value)
+	if err != nil {
+		return "", nil, err
+	}
+	return policy, p, nil
+}
+
+// resources returns the bricks of the volume as claimed by the operations.
+// Bricks provisioned from a device are claimed by the device, which is
+// shared with the bricks of other volumes.
+func resources(volinfo *volume.Volinfo) []string {
+	var result []string
+	for _, b := range volinfo.GetBricks() {
+		if b.RootDevice != "" {
+			result = append(result, b.PeerID.String()+":"+b.RootDevice)
+		} else {
+			result = append(result, b.PeerID.String()+":"+b.Path)
+		}
+	}
+	return result
+}
+
+func claimKey(op Op, volname string) string {
+	return claimPrefix + string(op) + "/" + volname
+}
+
+func getClaims() ([]*Claim, error) {
+	resp, err := store.Get(context.TODO(), claimPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	claims := make([]*Claim, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var c Claim
+		if err := json.Unmarshal(kv.Value, &c); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal io-op claim")
+			continue
+		}
+		claims = append(claims, &c)
+	}
+	return claims, nil
+}
+
+func putClaim(c *Claim) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), claimKey(c.Op, c.Volume), string(data))
+	return err
+}
+
+func deleteClaim(op Op, volname string) error {
+	_, err := store.Delete(context.TODO(), claimKey(op, volname))
+	return err
+}
+
+// Acquire claims the bricks of the volume for the operation, which is to be
+// started by the caller if nil is returned. ErrQueued is returned if the
+// operation has been queued behind conflicting operations, and
+// ErrIOOpConflict if it has been rejected instead. The conflicting operations
+// of lower priority which can be throttled are throttled in the background,
+// as their volumes can be locked by the caller. args are saved to start the
+// queued operation with. The claim has to be released with Release if the
+// operation fails to start.
+func Acquire(ctx context.Context, op Op, volinfo *volume.Volinfo, args interface{}) error {
+	logger := gdctx.Logger(ctx).WithFields(log.Fields{
+		"op":     op,
+		"volume": volinfo.Name,
+	})
+
+	policy, p, err := settings()
+	if err != nil {
+		return err
+	}
+	if policy == policyOff {
+		return nil
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, lockID)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	claims, err := getClaims()
+	if err != nil {
+		return err
+	}
+
+	c := &Claim{
+		Op:        op,
+		Volume:    volinfo.Name,
+		Resources: resources(volinfo),
+		State:     api.IOOpRunning,
+		Since:     time.Now(),
+	}
+	if args != nil {
+		if c.Args, err = json.Marshal(args); err != nil {
+			return err
+		}
+	}
+	for _, o := range claims {
+		// A queued operation keeps its place in the queue
+		if o.Op == op && o.Volume == volinfo.Name && o.State == api.IOOpQueued {
+			c.Since = o.Since
+		}
+	}
+
+	queue, throttle := decide(c, claims, p, canThrottle)
+	if queue {
+		if policy == policyReject {
+			return gderrors.ErrIOOpConflict
+		}
+		c.State = api.IOOpQueued
+		if err := putClaim(c); err != nil {
+			return err
+		}
+		logger.Info("operation queued behind conflicting operations on its bricks")
+		return ErrQueued
+	}
+
+	for _, t := range throttle {
+		t.State = api.IOOpThrottled
+		if err := putClaim(t); err != nil {
+			return err
+		}
+		go setThrottled(t, true)
+	}
+
+	c.Since = time.Now()
+	if err := putClaim(c); err != nil {
+		return err
+	}
+	logger.WithField("throttled", len(throttle)).Debug("operation claimed its bricks")
+	return nil
+}
+
+// Release releases the bricks claimed by the operation, or removes it from
+// the queue. The throttled and queued operations are resumed and started by
+// the reconcile job.
+func Release(op Op, volname string) error {
+	return deleteClaim(op, volname)
+}
+
+// Cancel removes the queued operation from the queue. ErrIOOpNotQueued is
+// returned if the operation is not queued.
+func Cancel(ctx context.Context, op Op, volname string) error {
+	txn, err := transaction.NewTxnWithLocks(ctx, lockID)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	claims, err := getClaims()
+	if err != nil {
+		return err
+	}
+	for _, c := range claims {
+		if c.Op == op && c.Volume == volname && c.State == api.IOOpQueued {
+			return deleteClaim(op, volname)
+		}
+	}
+	return gderrors.ErrIOOpNotQueued
+}
+
+// List returns the running, throttled and queued operations
+func List() (api.IOOpListResp, error) {
+	_, p, err := settings()
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := getClaims()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make(api.IOOpListResp, 0, len(claims))
+	for _, c := range claims {
+		resp = append(resp, api.IOOp{
+			Op:        string(c.Op),
+			Volume:    c.Volume,
+			State:     c.State,
+			Priority:  p[c.Op],
+			Resources: c.Resources,
+			Since:     c.Since,
+		})
+	}
+	return resp, nil
+}
+
+// setThrottled throttles or restores the operation, retrying while the lock
+// on its volume can't be obtained
+func setThrottled(c *Claim, throttled bool) error {
+	logger := log.WithFields(log.Fields{
+		"op":        c.Op,
+		"volume":    c.Volume,
+		"throttled": throttled,
+	})
+
+	h, ok := getHandler(c.Op)
+	if !ok || h.Throttle == nil {
+		return nil
+	}
+
+	var err error
+	for i := 0; i < throttleRetries; i++ {
+		if err = h.Throttle(context.Background(), c, throttled); err != transaction.ErrLockTimeout {
+			break
+		}
+	}
+	if err != nil {
+		logger.WithError(err).Warn("failed to set throttle of operation")
+		return err
+	}
+	logger.Info("throttle of operation set")
+	return nil
+}
+
+// reconcile releases the claims of the operations which have completed,
+// starts the queued operations in the order of their priority, and restores
+// the throttled operations which no longer conflict with an operation of
+// higher priority
+func reconcile(ctx context.Context) error {
+	logger := gdctx.Logger(ctx)
+
+	_, p, err := settings()
+	if err != nil {
+		return err
+	}
+
+	claims, err := getClaims()
+	if err != nil {
+		return err
+	}
+
+	var remaining []*Claim
+	for _, c := range claims {
+		clogger := logger.WithFields(log.Fields{
+			"op":     c.Op,
+			"volume": c.Volume,
+		})
+
+		done := !volume.Exists(c.Volume)
+		// Throttled operations can be reported as not running while
+		// they are paused
+		if !done && c.State == api.IOOpRunning {
+			if h, ok := getHandler(c.Op); ok {
+				running, err := h.Running(ctx, c)
+				if err != nil {
+					clogger.WithError(err).Warn("failed to check if operation is running")
+					running = true
+				}
+				done = !running
+			}
+		}
+
+		if !done {
+			remaining = append(remaining, c)
+			continue
+		}
+		if err := deleteClaim(c.Op, c.Volume); err != nil {
+			return err
+		}
+		clogger.Info("operation completed, released its bricks")
+	}
+
+	for _, c := range queuedInOrder(remaining, p) {
+		clogger := logger.WithFields(log.Fields{
+			"op":     c.Op,
+			"volume": c.Volume,
+		})
+
+		h, ok := getHandler(c.Op)
+		if !ok {
+			continue
+		}
+		switch err := h.Start(ctx, c); err {
+		case nil:
+			clogger.Info("started queued operation")
+		case ErrQueued:
+		default:
+			// The operation can no longer be started, for example
+			// as its volume has been stopped
+			clogger.WithError(err).Error("failed to start queued operation, removing it from the queue")
+			if err := deleteClaim(c.Op, c.Volume); err != nil {
+				return err
+			}
+		}
+	}
+
+	return restore(ctx, p)
+}
+
+// restore restores the throttled operations which no running operation of
+// higher priority conflicts with. The operations are restored after the lock
+// is released, as restoring them locks their volumes.
+func restore(ctx context.Context, p priorities) error {
+	txn, err := transaction.NewTxnWithLocks(ctx, lockID)
+	if err != nil {
+		return err
+	}
+
+	claims, err := getClaims()
+	if err != nil {
+		txn.Done()
+		return err
+	}
+
+	toRestore := restorable(claims, p)
+	for _, c := range toRestore {
+		c.State = api.IOOpRunning
+		if err := putClaim(c); err != nil {
+			txn.Done()
+			return err
+		}
+	}
+	txn.Done()
+
+	for _, c := range toRestore {
+		setThrottled(c, false)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package ioops
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Op is an IO intensive operation run on the bricks of a volume
+type Op string
+
+// The coordinated operations
+const (
+	OpScrub     Op = "scrub"
+	OpRebalance Op = "rebalance"
+	OpFullHeal  Op = "full-heal"
+)
+
+var knownOps = []Op{OpFullHeal, OpRebalance, OpScrub}
+
+// Claim is an operation running, throttled or queued on the bricks of a
+// volume
+type Claim struct {
+	Op     Op     `json:"op"`
+	Volume string `json:"volume"`
+	// Resources are the bricks, or the devices of the bricks, the
+	// operation runs on
+	Resources []string  `json:"resources"`
+	State     string    `json:"state"`
+	Since     time.Time `json:"since"`
+	// Args are the arguments the operation was requested with, with
+	// which it is started if it has been queued
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+// conflicts returns true if the claims are of different operations sharing
+// any of their bricks
+func (c *Claim) conflicts(o *Claim) bool {
+	if c.Op == o.Op && c.Volume == o.Volume {
+		return false
+	}
+	resources := make(map[string]bool)
+	for _, r := range c.Resources {
+		resources[r] = true
+	}
+	for _, r := range o.Resources {
+		if resources[r] {
+			return true
+		}
+	}
+	return false
+}
+
+// priorities maps the operations to their position in the priority order, 0
+// being the highest
+type priorities map[Op]int
+
+// parsePriorities parses a comma separated list of all the operations in
+// the order of their priority
+func parsePriorities(value string) (priorities, error) {
+	p := make(priorities)
+	for i, name := range strings.Split(value, ",") {
+		op := Op(strings.TrimSpace(name))
+		known := false
+		for _, k := range knownOps {
+			if op == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown operation %q", op)
+		}
+		if _, ok := p[op]; ok {
+			return nil, fmt.Errorf("operation %q is listed more than once", op)
+		}
+		p[op] = i
+	}
+
+	if len(p) != len(knownOps) {
+		names := make([]string, len(knownOps))
+		for i, op := range knownOps {
+			names[i] = string(op)
+		}
+		return nil, fmt.Errorf("all of the operations %s must be ordered", strings.Join(names, ", "))
+	}
+	return p, nil
+}
+
+// higher returns true if the operation a has a higher priority than b
+func (p priorities) higher(a, b Op) bool {
+	return p[a] < p[b]
+}
+
+// decide returns whether the claim c has to be queued behind the conflicting
+// claims, and if not, the running claims which have to be throttled for it.
+// Operations of lower priority are throttled if canThrottle returns true for
+// them; operations which can't be throttled, and those of the same or higher
+// priority, have to complete first. Queued operations of higher priority, or
+// of the same priority queued earlier, are started first.
+func decide(c *Claim, claims []*Claim, p priorities, canThrottle func(Op) bool) (queue bool, throttle []*Claim) {
+	for _, o := range claims {
+		if !c.conflicts(o) {
+			continue
+		}
+		switch o.State {
+		case api.IOOpQueued:
+			if p.higher(o.Op, c.Op) || (p[o.Op] == p[c.Op] && o.Since.Before(c.Since)) {
+				queue = true
+			}
+		case api.IOOpThrottled:
+			if !p.higher(c.Op, o.Op) {
+				queue = true
+			}
+		default:
+			if p.higher(c.Op, o.Op) && canThrottle(o.Op) {
+				throttle = append(throttle, o)
+			} else {
+				queue = true
+			}
+		}
+	}
+
+	if queue {
+		return true, nil
+	}
+	return false, throttle
+}
+
+// restorable returns the throttled claims which no running claim of higher
+// priority conflicts with any more
+func restorable(claims []*Claim, p priorities) []*Claim {
+	var result []*Claim
+	for _, t := range claims {
+		if t.State != api.IOOpThrottled {
+			continue
+		}
+		blocked := false
+		for _, o := range claims {
+			if o.State == api.IOOpRunning && p.higher(o.Op, t.Op) && o.conflicts(t) {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// queuedInOrder returns the queued claims in the order they are to be
+// started, by priority and then by the time they were queued
+func queuedInOrder(claims []*Claim, p priorities) []*Claim {
+	var queued []*Claim
+	for _, c := range claims {
+		if c.State == api.IOOpQueued {
+			queued = append(queued, c)
+		}
+	}
+	sort.SliceStable(queued, func(i, j int) bool {
+		if p[queued[i].Op] != p[queued[j].Op] {
+			return p.higher(queued[i].Op, queued[j].Op)
+		}
+		return queued[i].Since.Before(queued[j].Since)
+	})
+	return queued
+}
@@ -0,0 +1,89 @@
+package ioops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func alwaysThrottle(Op) bool { return true }
+
+func TestParsePriorities(t *testing.T) {
+	p, err := parsePriorities("full-heal, rebalance,scrub")
+	assert.Nil(t, err)
+	assert.True(t, p.higher(OpFullHeal, OpRebalance))
+	assert.True(t, p.higher(OpRebalance, OpScrub))
+
+	for _, value := range []string{"", "scrub,rebalance", "scrub,rebalance,scrub", "scrub,rebalance,index-heal"} {
+		_, err := parsePriorities(value)
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestDecide(t *testing.T) {
+	p, _ := parsePriorities("full-heal,rebalance,scrub")
+	now := time.Now()
+
+	scrub := &Claim{Op: OpScrub, Volume: "v1", Resources: []string{"a", "b"}, State: api.IOOpRunning}
+	heal := &Claim{Op: OpFullHeal, Volume: "v2", Resources: []string{"c"}, State: api.IOOpRunning}
+
+	// No conflicting bricks
+	c := &Claim{Op: OpRebalance, Volume: "v3", Resources: []string{"d"}, Since: now}
+	queue, throttle := decide(c, []*Claim{scrub, heal}, p, alwaysThrottle)
+	assert.False(t, queue)
+	assert.Empty(t, throttle)
+
+	// Lower priority operation is throttled
+	c = &Claim{Op: OpRebalance, Volume: "v1", Resources: []string{"a", "b"}, Since: now}
+	queue, throttle = decide(c, []*Claim{scrub, heal}, p, alwaysThrottle)
+	assert.False(t, queue)
+	assert.Equal(t, []*Claim{scrub}, throttle)
+
+	// unless it can't be throttled
+	queue, throttle = decide(c, []*Claim{scrub, heal}, p, func(Op) bool { return false })
+	assert.True(t, queue)
+	assert.Empty(t, throttle)
+
+	// Higher priority operation has to complete first
+	c = &Claim{Op: OpRebalance, Volume: "v2", Resources: []string{"c"}, Since: now}
+	queue, throttle = decide(c, []*Claim{scrub, heal}, p, alwaysThrottle)
+	assert.True(t, queue)
+	assert.Empty(t, throttle)
+
+	// Queued operations of higher priority, or queued earlier, go first
+	queued := &Claim{Op: OpRebalance, Volume: "v1", Resources: []string{"b"}, State: api.IOOpQueued, Since: now.Add(-time.Minute)}
+	c = &Claim{Op: OpScrub, Volume: "v4", Resources: []string{"b"}, Since: now}
+	queue, _ = decide(c, []*Claim{queued}, p, alwaysThrottle)
+	assert.True(t, queue)
+	c = &Claim{Op: OpRebalance, Volume: "v4", Resources: []string{"b"}, Since: now}
+	queue, _ = decide(c, []*Claim{queued}, p, alwaysThrottle)
+	assert.True(t, queue)
+	c = &Claim{Op: OpFullHeal, Volume: "v4", Resources: []string{"b"}, Since: now}
+	queue, _ = decide(c, []*Claim{queued}, p, alwaysThrottle)
+	assert.False(t, queue)
+
+	// The queued operation itself isn't a conflict
+	c = &Claim{Op: OpRebalance, Volume: "v1", Resources: []string{"b"}, Since: queued.Since}
+	queue, _ = decide(c, []*Claim{queued}, p, alwaysThrottle)
+	assert.False(t, queue)
+}
+
+func TestRestorableAndQueuedInOrder(t *testing.T) {
+	p, _ := parsePriorities("full-heal,rebalance,scrub")
+	now := time.Now()
+
+	claims := []*Claim{
+		{Op: OpScrub, Volume: "v1", Resources: []string{"a"}, State: api.IOOpThrottled},
+		{Op: OpScrub, Volume: "v2", Resources: []string{"b"}, State: api.IOOpThrottled},
+		{Op: OpRebalance, Volume: "v1", Resources: []string{"a"}, State: api.IOOpRunning},
+		{Op: OpScrub, Volume: "v3", Resources: []string{"c"}, State: api.IOOpQueued, Since: now.Add(-2 * time.Minute)},
+		{Op: OpFullHeal, Volume: "v4", Resources: []string{"c"}, State: api.IOOpQueued, Since: now},
+		{Op: OpScrub, Volume: "v5", Resources: []string{"c"}, State: api.IOOpQueued, Since: now.Add(-time.Minute)},
+	}
+
+	assert.Equal(t, []*Claim{claims[1]}, restorable(claims, p))
+	assert.Equal(t, []*Claim{claims[4], claims[3], claims[5]}, queuedInOrder(claims, p))
+}
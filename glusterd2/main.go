@@ -7,23 +7,15 @@ import (
 	"strings"
 	"time"
 
-	"github.com/gluster/glusterd2/glusterd2/brickmux"
-	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/audit"
 	"github.com/gluster/glusterd2/glusterd2/daemon"
-	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
-	"github.com/gluster/glusterd2/glusterd2/peer"
-	"github.com/gluster/glusterd2/glusterd2/pmap"
-	"github.com/gluster/glusterd2/glusterd2/servers"
-	"github.com/gluster/glusterd2/glusterd2/store"
-	"github.com/gluster/glusterd2/glusterd2/transactionv2"
-	"github.com/gluster/glusterd2/glusterd2/transactionv2/cleanuphandler"
-	gdutils "github.com/gluster/glusterd2/glusterd2/utils"
+	"github.com/gluster/glusterd2/glusterd2/startup"
 	"github.com/gluster/glusterd2/glusterd2/volgen"
 	"github.com/gluster/glusterd2/glusterd2/xlator"
 	"github.com/gluster/glusterd2/pkg/errors"
-	"github.com/gluster/glusterd2/pkg/firewalld"
 	"github.com/gluster/glusterd2/pkg/logging"
+	"github.com/gluster/glusterd2/pkg/tlsreload"
 	"github.com/gluster/glusterd2/pkg/tracing"
 	"github.com/gluster/glusterd2/pkg/utils"
 	"github.com/gluster/glusterd2/version"
@@ -40,6 +32,9 @@ func main() {
 		log.WithError(err).Fatal("Failed to get and set hostname or IP")
 	}
 
+	// Check for demo mode before the flags are parsed
+	parseDemoCmd()
+
 	// Initialize and parse CLI flags
 	initFlags()
 
@@ -56,11 +51,25 @@ func main() {
 		log.WithError(err).Fatal("Failed to initialize logging")
 	}
 
+	if demoMode {
+		if err := initDemo(); err != nil {
+			log.WithError(err).Fatal("Failed to initialize demo mode")
+		}
+	}
+
 	// Initialize GD2 config
 	if err := initConfig(); err != nil {
 		log.WithError(err).Fatal("Failed to initialize config")
 	}
 
+	if validate, _ := flag.CommandLine.GetBool("validate-config"); validate {
+		if err := validateConfig(); err != nil {
+			log.WithError(err).Fatal("Invalid configuration")
+		}
+		log.Info("Configuration is valid")
+		return
+	}
+
 	logLevel2 := config.GetString("loglevel")
 	logdir2 := config.GetString("logdir")
 	logFileName2 := config.GetString("logfile")
@@ -102,63 +111,28 @@ func main() {
 		log.WithError(err).Fatal("Failed to load xlator options")
 	}
 
-	// Initialize etcd store (etcd client connection)
-	if err := store.Init(nil); err != nil {
-		log.WithError(err).Fatal("Failed to initialize store (etcd client)")
-	}
-
-	transaction.StartTxnEngine()
-	cleanuphandler.StartCleanupLeader()
-	// Start the events framework after store is up
-	if err := events.Start(); err != nil {
-		log.WithError(err).Fatal("Failed to start internal events framework")
-	}
-
-	if err := peer.AddSelfDetails(); err != nil {
-		log.WithError(err).Fatal("Could not add self details into etcd")
-	}
-
-	// Load the default group option map into the store
-	if err := volumecommands.InitDefaultGroupOptions(); err != nil {
-		log.WithError(err).Fatal("Failed to load the default group options")
-	}
-
 	// If REST API Auth is enabled, Generate Auth file with random secret in localstatedir
 	if err := gdctx.GenerateLocalAuthToken(); err != nil {
 		log.WithError(err).Fatal("Failed to generate local auth token")
 	}
 
-	// Create the Opencensus Jaeger exporter
-	if exporter := tracing.InitJaegerExporter(); exporter != nil {
-		defer exporter.Flush()
-	}
+	// Register the Opencensus exporters of the tracing endpoints
+	flushTraces := tracing.Init()
+	defer flushTraces()
 
 	// Load default volfile templates
 	if err := volgen.LoadDefaultTemplates(); err != nil {
 		log.WithError(err).Fatal("failed to load volgen templates")
 	}
 
-	// Start all servers (rest, peerrpc, sunrpc) managed by suture supervisor
+	// Start the subsystems in dependency order. Failed subsystems are
+	// retried in the background, signals are handled meanwhile.
 	super := initGD2Supervisor()
-	super.ServeBackground()
-	super.Add(servers.New())
-
-	// Start dbus connection (optional for notifying firewalld)
-	if err := firewalld.Init(); err != nil {
-		log.WithError(err).Warn("firewalld.Init() failed")
+	if err := registerSubsystems(super); err != nil {
+		log.WithError(err).Fatal("Failed to register subsystems")
 	}
-
-	pmap.Init()
-
-	// Mount all Local Bricks
-	gdutils.MountLocalBricks()
-
-	// Restart previously running daemons
-	daemon.StartAllDaemons()
-
-	// Reconcile multiplexed bricks
-	if err := brickmux.Reconcile(); err != nil {
-		log.WithError(err).Fatal("bmux.Reconcile() failed")
+	if err := startup.Start(); err != nil {
+		log.WithError(err).Fatal("Failed to start subsystems")
 	}
 
 	// Use the main goroutine as signal handling loop
@@ -172,12 +146,11 @@ func main() {
 		case unix.SIGINT:
 			log.Info("Received SIGTERM. Stopping GlusterD")
 			gdctx.IsTerminating = true
-			transaction.StopTxnEngine()
-			cleanuphandler.StopCleanupLeader()
-			super.Stop()
-			events.Stop()
-			store.Close()
+			startup.Stop()
 			_ = os.Remove(config.GetString("pidfile"))
+			if demoMode {
+				cleanupDemo()
+			}
 			log.Info("Stopped GlusterD")
 			return
 		case unix.SIGHUP:
@@ -189,6 +162,9 @@ func main() {
 					log.WithError(err).Fatal("Could not re-initialize logging")
 				}
 			}
+			audit.Reopen()
+			// Certificates renewed on disk are reloaded without a restart
+			tlsreload.ReloadAll()
 		case unix.SIGUSR1:
 			log.Info("Received SIGUSR1. Dumping statedump")
 			utils.WriteStatedump(config.GetString("rundir"))
@@ -217,8 +193,12 @@ func createDirectories() error {
 		path.Join(config.GetString("hooksdir"), "delete/post"),
 		path.Join(config.GetString("hooksdir"), "add-brick/post"),
 		path.Join(config.GetString("hooksdir"), "remove-brick/post"),
+		path.Join(config.GetString("hooksdir"), "snapshot"),
 		path.Join(config.GetString("localstatedir"), "vols"),
-		"/var/run/gluster", // issue #476
+	}
+	// Demo mode runs unprivileged and keeps everything in the demo dir
+	if !demoMode {
+		dirs = append(dirs, "/var/run/gluster") // issue #476
 	}
 	for _, dirpath := range dirs {
 		if err := utils.InitDir(dirpath); err != nil {
@@ -234,9 +214,8 @@ func createPidFile() error {
 	// Check if pidfile exists and already running
 	pid, err := daemon.ReadPidFromFile(pidfile)
 	if err == nil {
-		// Check if process is running
-		_, err := daemon.GetProcess(pid)
-		if err == nil {
+		// Check if another glusterd2 is running with the pid
+		if daemon.IsProcessOf(pid, os.Args[0]) {
 			return errors.ErrProcessAlreadyRunning
 		}
 	}
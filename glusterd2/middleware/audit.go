@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/audit"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+)
+
+// sourceIP returns the IP of the client of the request. The client of a
+// request forwarded by another peer is the first address of the
+// X-Forwarded-For header set by the forwarding peer. The header is only
+// trusted for requests authenticated as forwarded, as any client can set it.
+func sourceIP(r *http.Request) string {
+	if isAuthenticatedForward(r) {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			return strings.TrimSpace(strings.Split(xff, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Audit is a middleware which records the requests which may change the state
+// of the cluster with the audit package, along with their result and the
+// transactions run for them. It has to be used as a router middleware after
+// Forward, so that requests are recorded by the peer serving them.
+func Audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		rec := &api.AuditRecord{
+			Time:     time.Now(),
+			Peer:     gdctx.MyUUID,
+			ReqID:    gdctx.GetReqID(ctx),
+			User:     gdctx.GetReqUser(ctx),
+			SourceIP: sourceIP(r),
+			Method:   r.Method,
+			Path:     r.URL.Path,
+		}
+		if route := mux.CurrentRoute(r); route != nil {
+			rec.Route = route.GetName()
+		}
+
+		if r.Body != nil {
+			body, err := ioutil.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+				return
+			}
+			if len(body) != 0 {
+				digest := sha256.Sum256(body)
+				rec.BodyDigest = hex.EncodeToString(digest[:])
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		ctx = gdctx.WithTxnRecorder(ctx)
+		cr := &codeRecorder{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(cr, r.WithContext(ctx))
+
+		rec.Status = cr.code
+		rec.TxnIDs = gdctx.RecordedTxnIDs(ctx)
+		audit.Record(rec)
+	})
+}
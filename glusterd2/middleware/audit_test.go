@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceIP(t *testing.T) {
+	r := httptest.NewRequest("POST", "/v1/volumes", nil)
+	r.RemoteAddr = "192.0.2.1:41234"
+	assert.Equal(t, "192.0.2.1", sourceIP(r))
+
+	// X-Forwarded-For is only trusted for requests forwarded by peers
+	r.Header.Set("X-Forwarded-For", "198.51.100.7")
+	assert.Equal(t, "192.0.2.1", sourceIP(r))
+
+	// nor for requests only claiming to be, as with auth disabled
+	r.Header.Set(forwardedByHeader, "peer")
+	r.Header.Set("X-Forwarded-For", "198.51.100.7, 192.0.2.1")
+	assert.Equal(t, "192.0.2.1", sourceIP(r))
+
+	r = r.WithContext(context.WithValue(r.Context(), forwardedKey{}, true))
+	assert.Equal(t, "198.51.100.7", sourceIP(r))
+}
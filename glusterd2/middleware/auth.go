@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/user"
 	"github.com/gluster/glusterd2/pkg/utils"
 
 	"github.com/dgrijalva/jwt-go"
@@ -26,9 +29,41 @@ func getAuthSecret(issuer string) string {
 		return gdctx.LocalAuthToken
 	}
 
-	// TODO: Look for issuer secret in etcd if not internal user, this depends on User management feature
+	// Requests forwarded by other peers are signed with a cluster wide
+	// secret, which is only generated by the forwarding peers
+	if issuer == forwardIssuer {
+		secret, err := getForwardSecret()
+		if err != nil {
+			return ""
+		}
+		return secret
+	}
 
-	return ""
+	// Users are looked up in the store, which may not be up yet
+	if store.Store == nil {
+		return ""
+	}
+	u, err := user.Get(issuer)
+	if err != nil {
+		return ""
+	}
+	return u.Secret
+}
+
+// certUser returns the user named by the common name of the verified client
+// certificate of the request, if any
+func certUser(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	name := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	if name == "" || store.Store == nil {
+		return ""
+	}
+	if _, err := user.Get(name); err != nil {
+		return ""
+	}
+	return name
 }
 
 //isRestAuthRequired return false for few URL which doesn't require authentication
@@ -36,7 +71,12 @@ func isRestAuthRequired(url string) bool {
 	switch url {
 	case "/ping":
 		fallthrough
+	case "/ready":
+		fallthrough
 	case "/endpoints":
+		fallthrough
+	case "/metrics":
+		// Metrics scrapers can't sign requests
 		return false
 	default:
 		return true
@@ -44,11 +84,30 @@ func isRestAuthRequired(url string) bool {
 
 }
 
+// forwardedKey is the key of the request context value set for requests
+// authenticated as forwarded by a peer
+type forwardedKey struct{}
+
+// isAuthenticatedForward returns true if the request was authenticated as
+// forwarded by a peer
+func isAuthenticatedForward(r *http.Request) bool {
+	forwarded, _ := r.Context().Value(forwardedKey{}).(bool)
+	return forwarded
+}
+
 // Auth is a middleware which authenticates HTTP requests
 func Auth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// If Auth disabled Return as is
-		if !gdctx.RESTAPIAuthEnabled || !isRestAuthRequired(r.URL.String()) {
+		if !gdctx.RESTAPIAuthEnabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Any client can claim its request was forwarded by a peer. The
+		// claim is kept only for requests authenticated as forwarded.
+		forwardedBy := r.Header.Get(forwardedByHeader)
+		r.Header.Del(forwardedByHeader)
+		if !isRestAuthRequired(r.URL.String()) {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -56,6 +115,11 @@ func Auth(next http.Handler) http.Handler {
 		// Verify if Authorization header exists or not
 		authHeader := strings.TrimSpace(r.Header.Get("Authorization"))
 		if authHeader == "" {
+			// Clients may authenticate with a certificate instead
+			if name := certUser(r); name != "" {
+				next.ServeHTTP(w, r.WithContext(gdctx.WithReqUser(ctx, name)))
+				return
+			}
 			restutils.SendHTTPError(ctx, w, http.StatusUnauthorized, errors.New("'Authorization' header is required"))
 			return
 		}
@@ -108,9 +172,23 @@ func Auth(next http.Handler) http.Handler {
 			return
 		}
 
-		// TODO: Filter URLs here if any role based control of APIs, this depends on User management feature
+		// The role of the user is checked for each route by Authorize
 
 		// Authentication is successful, continue serving the request
+		// with the authenticated user saved in the request context
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			if user, ok := claims["iss"].(string); ok {
+				// Forwarded requests carry the user of the original request
+				if user == forwardIssuer {
+					user, _ = claims["sub"].(string)
+					if forwardedBy != "" {
+						r.Header.Set(forwardedByHeader, forwardedBy)
+					}
+					ctx = context.WithValue(ctx, forwardedKey{}, true)
+				}
+				r = r.WithContext(gdctx.WithReqUser(ctx, user))
+			}
+		}
 		next.ServeHTTP(w, r)
 	})
 }
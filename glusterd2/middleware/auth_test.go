@@ -29,6 +29,9 @@ func TestGetAuthSecret(t *testing.T) {
 
 	secret = getAuthSecret("glustercli")
 	assert.NotNil(t, secret)
+
+	// Tokens claiming to be forwarded don't generate the forward secret
+	assert.Empty(t, getAuthSecret(forwardIssuer))
 }
 
 func getAuthToken(username string, password string, r *http.Request) {
@@ -96,6 +99,40 @@ func TestAuth(t *testing.T) {
 	os.Remove("auth")
 }
 
+func TestAuthForwardedBy(t *testing.T) {
+	var forwardedBy string
+	ts := httptest.NewServer(Auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwardedBy = r.Header.Get(forwardedByHeader)
+	})))
+	defer ts.Close()
+
+	gdctx.RESTAPIAuthEnabled = true
+	defer func() { gdctx.RESTAPIAuthEnabled = false }()
+	generateLocalauthtoken()
+	defer os.Remove("auth")
+	secret, err := ioutil.ReadFile("auth")
+	assert.Nil(t, err)
+
+	// Requests of clients can't claim to be forwarded by a peer
+	req, err := http.NewRequest("GET", ts.URL, nil)
+	assert.Nil(t, err)
+	req.Header.Set(forwardedByHeader, "peer")
+	getAuthToken("glustercli", string(secret), req)
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, forwardedBy)
+
+	// nor can unauthenticated requests
+	req, err = http.NewRequest("GET", ts.URL+"/ping", nil)
+	assert.Nil(t, err)
+	req.Header.Set(forwardedByHeader, "peer")
+	resp, err = http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, forwardedBy)
+}
+
 func GetTestHandler() http.HandlerFunc {
 	fn := func(rw http.ResponseWriter, req *http.Request) {
 
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/user"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+// rolePermissions are the permissions granted by the roles
+var rolePermissions = map[string]route.Permission{
+	api.RoleAdmin:    route.PermAdmin,
+	api.RoleOperator: route.PermOperate,
+	api.RoleReadOnly: route.PermRead,
+}
+
+// authorized checks that the user may call a route needing perm, for the
+// volume volname if the route is for a volume
+func authorized(u *api.User, perm route.Permission, volname string) error {
+	if rolePermissions[u.Role] < perm {
+		return fmt.Errorf("user %s with role %s is not permitted to make this request", u.Name, u.Role)
+	}
+	if len(u.Volumes) == 0 {
+		return nil
+	}
+	if volname == "" {
+		return fmt.Errorf("user %s is limited to volumes %v, and can only make requests for these volumes", u.Name, u.Volumes)
+	}
+	if !user.CanAccessVolume(u, volname) {
+		return fmt.Errorf("user %s is not permitted to access volume %s", u.Name, volname)
+	}
+	return nil
+}
+
+// Authorize returns a handler which allows the request only if the role of
+// the authenticated user grants perm, and the user isn't limited to other
+// volumes than the volume of the request. It is set up by the REST server
+// for each route with the permission declared by the route.
+func Authorize(perm route.Permission, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !gdctx.RESTAPIAuthEnabled || perm == route.PermNone {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		name := gdctx.GetReqUser(ctx)
+		// The internal user has the local auth token of the peer
+		if name == internalUser {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		u, err := user.Get(name)
+		if err == gderrors.ErrUserNotFound {
+			restutils.SendHTTPError(ctx, w, http.StatusForbidden, fmt.Sprintf("user %s is not permitted to make this request", name))
+			return
+		} else if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := authorized(u, perm, mux.Vars(r)["volname"]); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusForbidden, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorized(t *testing.T) {
+	admin := &api.User{Name: "a", Role: api.RoleAdmin}
+	operator := &api.User{Name: "o", Role: api.RoleOperator}
+	reader := &api.User{Name: "r", Role: api.RoleReadOnly}
+	scoped := &api.User{Name: "s", Role: api.RoleOperator, Volumes: []string{"vol1"}}
+
+	assert.Nil(t, authorized(admin, route.PermAdmin, ""))
+	assert.Nil(t, authorized(operator, route.PermOperate, "vol2"))
+	assert.NotNil(t, authorized(operator, route.PermAdmin, ""))
+	assert.Nil(t, authorized(reader, route.PermRead, ""))
+	assert.NotNil(t, authorized(reader, route.PermOperate, "vol1"))
+
+	assert.Nil(t, authorized(scoped, route.PermOperate, "vol1"))
+	assert.NotNil(t, authorized(scoped, route.PermRead, "vol2"))
+	assert.NotNil(t, authorized(scoped, route.PermRead, ""))
+	assert.NotNil(t, authorized(scoped, route.PermAdmin, "vol1"))
+
+	unknown := &api.User{Name: "u", Role: "unknown"}
+	assert.NotNil(t, authorized(unknown, route.PermRead, ""))
+}
@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// forwardIssuer is the issuer of the auth tokens of forwarded requests
+	forwardIssuer = "glusterd2-forward"
+	// forwardedByHeader is set to the ID of the peer forwarding a request.
+	// Forwarded requests are never forwarded again. With REST auth enabled,
+	// Auth removes it from the requests not signed by a forwarding peer.
+	forwardedByHeader = "X-Gluster-Forwarded-By"
+	forwardSecretKey  = "config/forward-secret"
+	forwardOptKey     = "cluster.volume-request-forwarding"
+	forwardTokenTTL   = 120 * time.Second
+)
+
+var errNoForwardAddress = errors.New("no usable client address for volume owner")
+
+// forwardingEnabled returns true if mutating volume requests are to be
+// forwarded to the owner of the volume
+func forwardingEnabled() bool {
+	value, err := options.GetClusterOption(forwardOptKey)
+	if err != nil {
+		return false
+	}
+	enabled, err := options.StringToBoolean(value)
+	return err == nil && enabled
+}
+
+// getForwardSecret returns the cluster wide secret used to sign the auth
+// tokens of forwarded requests, or "" if no request has been forwarded yet
+func getForwardSecret() (string, error) {
+	if store.Store == nil {
+		return "", nil
+	}
+
+	resp, err := store.Get(context.TODO(), forwardSecretKey)
+	if err != nil {
+		return "", err
+	}
+	if resp.Count != 1 {
+		return "", nil
+	}
+	return store.Decrypt(string(resp.Kvs[0].Value))
+}
+
+// forwardSecret returns the cluster wide secret used to sign the auth tokens
+// of forwarded requests, generating it if it does not exist yet. Only peers
+// forwarding a request generate it.
+func forwardSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	encrypted, err := store.Encrypt(hex.EncodeToString(b))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := store.Store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(forwardSecretKey), "=", 0)).
+		Then(clientv3.OpPut(forwardSecretKey, encrypted)).
+		Else(clientv3.OpGet(forwardSecretKey)).
+		Commit()
+	if err != nil {
+		return "", err
+	}
+	if resp.Succeeded {
+		return hex.EncodeToString(b), nil
+	}
+
+	kvs := resp.Responses[0].GetResponseRange().Kvs
+	if len(kvs) == 0 {
+		return "", errors.New("forward secret not found")
+	}
+	return store.Decrypt(string(kvs[0].Value))
+}
+
+// forwardToken returns an auth token for the forwarded request, carrying the
+// authenticated user of the original request
+func forwardToken(r *http.Request) (string, error) {
+	secret, err := forwardSecret()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": forwardIssuer,
+		"sub": gdctx.GetReqUser(r.Context()),
+		"exp": time.Now().Add(forwardTokenTTL).Unix(),
+		"qsh": utils.GenerateQsh(r),
+	})
+	return token.SignedString([]byte(secret))
+}
+
+// isForwardable returns true for the requests which modify a volume
+func isForwardable(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return false
+	}
+	return r.Header.Get(forwardedByHeader) == ""
+}
+
+// requestVolname returns the name of the volume the request is for. The name
+// of a volume being created is read from the request body.
+func requestVolname(r *http.Request) string {
+	if volname := mux.Vars(r)["volname"]; volname != "" {
+		return volname
+	}
+
+	route := mux.CurrentRoute(r)
+	if route == nil || route.GetName() != "VolumeCreate" || r.Body == nil {
+		return ""
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	return req.Name
+}
+
+// ownerURL returns the URL of the REST server of the given peer, preferring a
+// non-loopback client address
+func ownerURL(p *peer.Peer) (*url.URL, error) {
+	scheme := "http"
+	if config.GetString("cert-file") != "" {
+		scheme = "https"
+	}
+
+	for _, addr := range p.ClientAddresses {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil || host == "" {
+			continue
+		}
+		if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+			continue
+		}
+		return &url.URL{Scheme: scheme, Host: addr}, nil
+	}
+	return nil, errNoForwardAddress
+}
+
+// Forward is a middleware which proxies requests modifying a volume to the
+// peer owning the volume, when enabled by the
+// cluster.volume-request-forwarding cluster option. Volume owners are chosen
+// by consistent hashing over the peers which are alive, so operations on a
+// volume are serialized on a single peer. Requests are served locally if the
+// owner can't be found.
+func Forward(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isForwardable(r) || !forwardingEnabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		volname := requestVolname(r)
+		if volname == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger := gdctx.Logger(r.Context()).WithField("volume", volname)
+
+		owner, err := peer.VolumeOwner(volname)
+		if err != nil {
+			logger.WithError(err).Warn("could not find volume owner, serving request locally")
+			next.ServeHTTP(w, r)
+			return
+		}
+		if uuid.Equal(owner.ID, gdctx.MyUUID) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		target, err := ownerURL(owner)
+		if err != nil {
+			logger.WithError(err).WithField("owner", owner.ID).Warn("could not forward request, serving request locally")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r.Header.Set(forwardedByHeader, gdctx.MyUUID.String())
+		if gdctx.RESTAPIAuthEnabled {
+			token, err := forwardToken(r)
+			if err != nil {
+				logger.WithError(err).Warn("could not sign forwarded request, serving request locally")
+				r.Header.Del(forwardedByHeader)
+				next.ServeHTTP(w, r)
+				return
+			}
+			r.Header.Set("Authorization", "bearer "+token)
+		}
+
+		logger.WithFields(log.Fields{
+			"owner":   owner.ID,
+			"address": target.Host,
+		}).Debug("forwarding request to volume owner")
+		httputil.NewSingleHostReverseProxy(target).ServeHTTP(w, r)
+	})
+}
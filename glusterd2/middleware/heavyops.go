@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+	config "github.com/spf13/viper"
+)
+
+const (
+	maxHeavyOpsOpt = "max-heavy-ops"
+	// heavyOpSlotPrefix holds a key for each heavyweight operation being
+	// served in the cluster, attached to the store session of the peer
+	// serving it
+	heavyOpSlotPrefix = "heavy-op-slots/"
+	// heavyOpRetryAfter is the number of seconds after which a rejected
+	// heavyweight operation may be retried
+	heavyOpRetryAfter = "10"
+)
+
+var errTooManyHeavyOps = errors.New("too many heavyweight operations are running in the cluster, try again later")
+
+// acquireHeavyOpSlot takes one of the max slots for heavyweight operations,
+// and returns the function releasing it, or nil if all slots are taken
+func acquireHeavyOpSlot(max int, holder string) (func(), error) {
+	for i := 0; i < max; i++ {
+		key := fmt.Sprintf("%s%d", heavyOpSlotPrefix, i)
+		resp, err := store.Txn(context.TODO()).
+			If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+			Then(clientv3.OpPut(key, holder, clientv3.WithLease(store.Store.Session.Lease()))).
+			Commit()
+		if err != nil {
+			return nil, err
+		}
+		if resp.Succeeded {
+			return func() {
+				store.Delete(context.TODO(), key)
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// LimitHeavyOps returns a handler which serves at most max-heavy-ops requests
+// of heavyweight operations, like volume create, snapshot and rebalance, at
+// once in the cluster. Other requests are rejected with Conflict and the time
+// after which they may be retried. The slots of the requests being served by
+// a peer are freed if the peer goes down. It is set up by the REST server for
+// the routes marked heavy. Requests run as async jobs hold their slot until
+// the job finishes.
+func LimitHeavyOps(next http.HandlerFunc) http.HandlerFunc {
+	return limitHeavyOps(next, acquireHeavyOpSlot)
+}
+
+func limitHeavyOps(next http.HandlerFunc, acquire func(max int, holder string) (func(), error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		max := config.GetInt(maxHeavyOpsOpt)
+		if max <= 0 {
+			next(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		release, err := acquire(max, gdctx.GetReqID(ctx).String())
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		if release == nil {
+			w.Header().Set("Retry-After", heavyOpRetryAfter)
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, errTooManyHeavyOps)
+			return
+		}
+		ctx, done := restutils.HoldUntilDone(ctx, release)
+		defer done()
+
+		next(w, r.WithContext(ctx))
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// testSlots is an in-memory stand-in for the heavy-op-slots in the store
+type testSlots struct {
+	mu    sync.Mutex
+	taken int
+}
+
+func (s *testSlots) acquire(max int, holder string) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.taken >= max {
+		return nil, nil
+	}
+	s.taken++
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.taken--
+	}, nil
+}
+
+func serveHeavyOp(h http.HandlerFunc) int {
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest("POST", "/v1/volumes", nil))
+	return w.Code
+}
+
+func TestLimitHeavyOps(t *testing.T) {
+	config.Set(maxHeavyOpsOpt, 1)
+	defer config.Set(maxHeavyOpsOpt, 0)
+
+	slots := &testSlots{}
+	h := limitHeavyOps(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}, slots.acquire)
+
+	assert.Equal(t, http.StatusCreated, serveHeavyOp(h))
+	assert.Equal(t, 0, slots.taken)
+	assert.Equal(t, http.StatusCreated, serveHeavyOp(h))
+}
+
+func TestLimitHeavyOpsAsync(t *testing.T) {
+	config.Set(maxHeavyOpsOpt, 1)
+	defer config.Set(maxHeavyOpsOpt, 0)
+
+	slots := &testSlots{}
+	finish := make(chan struct{})
+	finished := make(chan struct{})
+	// The handler runs the operation in the background, as SendAsyncJob
+	// does, and answers right away
+	async := limitHeavyOps(func(w http.ResponseWriter, r *http.Request) {
+		release := restutils.TakeHeld(r.Context())
+		go func() {
+			<-finish
+			release()
+			close(finished)
+		}()
+		w.WriteHeader(http.StatusAccepted)
+	}, slots.acquire)
+
+	assert.Equal(t, http.StatusAccepted, serveHeavyOp(async))
+
+	// The slot is held by the job after the request has been served
+	assert.Equal(t, 1, slots.taken)
+	assert.Equal(t, http.StatusConflict, serveHeavyOp(async))
+
+	close(finish)
+	<-finished
+	assert.Equal(t, 0, slots.taken)
+}
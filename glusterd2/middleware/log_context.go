@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	"github.com/gorilla/mux"
+)
+
+// LogContext is a middleware which adds the name of the volume being operated
+// upon to the request context and the request scoped logger. It has to be
+// used as a router middleware as the route variables are available only
+// after the route has been matched.
+func LogContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		volname, ok := mux.Vars(r)["volname"]
+		if !ok || volname == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := gdctx.WithVolName(r.Context(), volname)
+		ctx = gdctx.WithReqLogger(ctx, gdctx.Logger(ctx))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
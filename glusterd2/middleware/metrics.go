@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// reqDuration records the time taken to serve the REST requests
+var reqDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "glusterd2",
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to serve REST requests.",
+		// 1ms to ~30s
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+	},
+	[]string{"route", "method", "code"},
+)
+
+func init() {
+	prometheus.MustRegister(reqDuration)
+}
+
+type codeRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (rec *codeRecorder) WriteHeader(code int) {
+	rec.code = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// Metrics is a middleware which records the duration of requests by the name
+// of the route, so that requests to the same endpoint with different route
+// variables are counted together. It has to be used as a router middleware
+// as the route is available only after it has been matched.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unknown"
+		if cr := mux.CurrentRoute(r); cr != nil {
+			route = cr.GetName()
+		}
+
+		start := time.Now()
+		rec := &codeRecorder{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		reqDuration.WithLabelValues(route, r.Method, strconv.Itoa(rec.code)).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+
+	config "github.com/spf13/viper"
+	"golang.org/x/time/rate"
+)
+
+const (
+	rateLimitOpt = "rest-rate-limit"
+	rateBurstOpt = "rest-rate-burst"
+
+	// limiterIdleTimeout is the time after which the limiter of a client
+	// which hasn't made requests is dropped
+	limiterIdleTimeout = 10 * time.Minute
+)
+
+var errRateLimited = errors.New("too many requests, try again later")
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// clientLimiters are the rate limiters of the REST clients, keyed by the user
+// of the client, or its IP if REST authentication is disabled
+type clientLimiters struct {
+	mu        sync.Mutex
+	limiters  map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+var limiters = &clientLimiters{limiters: make(map[string]*clientLimiter)}
+
+// get returns the limiter of the client, creating it with the given limit and
+// burst if it doesn't exist or has a different limit
+func (l *clientLimiters) get(client string, limit rate.Limit, burst int, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > limiterIdleTimeout {
+		for c, cl := range l.limiters {
+			if now.Sub(cl.lastSeen) > limiterIdleTimeout {
+				delete(l.limiters, c)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	cl, ok := l.limiters[client]
+	if !ok || cl.limiter.Limit() != limit || cl.limiter.Burst() != burst {
+		cl = &clientLimiter{limiter: rate.NewLimiter(limit, burst)}
+		l.limiters[client] = cl
+	}
+	cl.lastSeen = now
+	return cl.limiter
+}
+
+// allow returns 0 if the request of the client is allowed, or else the time
+// after which it may be retried
+func (l *clientLimiters) allow(client string, limit rate.Limit, burst int, now time.Time) time.Duration {
+	r := l.get(client, limit, burst, now).ReserveN(now, 1)
+	if !r.OK() {
+		return time.Second
+	}
+	if d := r.DelayFrom(now); d > 0 {
+		r.CancelAt(now)
+		return d
+	}
+	return 0
+}
+
+// rateLimitClient returns the key identifying the client of the request for
+// rate limiting
+func rateLimitClient(r *http.Request) string {
+	if user := gdctx.GetReqUser(r.Context()); user != "" {
+		return "user:" + user
+	}
+	return "ip:" + sourceIP(r)
+}
+
+// RateLimit is a middleware which limits the rate of requests of each client
+// to rest-rate-limit requests per second, with bursts of up to
+// rest-rate-burst requests. Clients are identified by their authenticated
+// user, or by their IP if REST authentication is disabled. Requests over the
+// limit are rejected with Too Many Requests and the time after which they may
+// be retried.
+func RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := config.GetFloat64(rateLimitOpt)
+		if limit <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		burst := config.GetInt(rateBurstOpt)
+		if burst < 1 {
+			burst = 1
+		}
+
+		if d := limiters.allow(rateLimitClient(r), rate.Limit(limit), burst, time.Now()); d > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+			restutils.SendHTTPError(r.Context(), w, http.StatusTooManyRequests, errRateLimited)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestClientLimitersAllow(t *testing.T) {
+	l := &clientLimiters{limiters: make(map[string]*clientLimiter)}
+	now := time.Now()
+
+	assert.Equal(t, time.Duration(0), l.allow("user:a", rate.Limit(1), 2, now))
+	assert.Equal(t, time.Duration(0), l.allow("user:a", rate.Limit(1), 2, now))
+	d := l.allow("user:a", rate.Limit(1), 2, now)
+	assert.True(t, d > 0 && d <= time.Second)
+
+	// Other clients have their own limiter
+	assert.Equal(t, time.Duration(0), l.allow("user:b", rate.Limit(1), 2, now))
+
+	// Rejected requests don't use up tokens
+	assert.Equal(t, time.Duration(0), l.allow("user:a", rate.Limit(1), 2, now.Add(time.Second)))
+
+	// Idle limiters are dropped
+	l.allow("user:c", rate.Limit(1), 2, now.Add(2*limiterIdleTimeout))
+	assert.Len(t, l.limiters, 1)
+}
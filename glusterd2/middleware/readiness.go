@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/startup"
+)
+
+var errNotReady = errors.New("glusterd2 is starting up, try again later")
+
+// isReadinessRequired returns false for the few URLs which are served while
+// GD2 is still starting up
+func isReadinessRequired(url string) bool {
+	switch url {
+	case "/ping", "/ready", "/endpoints", "/version", "/metrics":
+		return false
+	default:
+		return true
+	}
+}
+
+// ReadinessGate is a middleware which rejects HTTP requests with Service
+// Unavailable till the REST subsystem has been started. The REST server
+// itself is up early as it shares the listener with the sunrpc server used
+// by the daemons.
+func ReadinessGate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isReadinessRequired(r.URL.Path) && !startup.IsReady(startup.REST) {
+			w.Header().Set("Retry-After", "5")
+			restutils.SendHTTPError(r.Context(), w, http.StatusServiceUnavailable, errNotReady)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+)
+
+// ReadOnlyGate returns a handler which rejects requests with Service
+// Unavailable while the read-only mode of the cluster is enabled. It is set
+// up by the REST server for the routes which may modify the cluster.
+func ReadOnlyGate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m, err := options.GetReadOnlyMode()
+		if err != nil {
+			restutils.SendHTTPError(r.Context(), w, http.StatusInternalServerError,
+				fmt.Sprintf("could not check the cluster read-only mode: %s", err))
+			return
+		}
+		if m.Enabled {
+			restutils.SendHTTPError(r.Context(), w, http.StatusServiceUnavailable,
+				fmt.Sprintf("cluster is in read-only mode: %s", m.Reason))
+			return
+		}
+		next(w, r)
+	}
+}
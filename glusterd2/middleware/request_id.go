@@ -24,7 +24,10 @@ func ReqIDGenerator(next http.Handler) http.Handler {
 		w.Header().Set("X-Gluster-Cluster-Id", gdctx.MyClusterID.String())
 
 		// Create request-scoped logger and set in request context
-		reqLoggerEntry := log.WithField("reqid", reqID.String())
+		reqLoggerEntry := log.WithFields(log.Fields{
+			"reqid":  reqID.String(),
+			"peerid": gdctx.MyUUID.String(),
+		})
 		ctx = gdctx.WithReqLogger(ctx, reqLoggerEntry)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
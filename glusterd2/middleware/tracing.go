@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	"github.com/gorilla/mux"
+	"go.opencensus.io/trace"
+)
+
+// Trace is a middleware which tags the span of the request, started by the
+// opencensus HTTP handler, with the route, the volume being operated upon
+// and the IDs of the request and this peer. It has to be used as a router
+// middleware after LogContext as the route variables are available only
+// after the route has been matched.
+func Trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.FromContext(ctx)
+		if span == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		attrs := []trace.Attribute{
+			trace.StringAttribute("peerID", gdctx.MyUUID.String()),
+		}
+		if route := mux.CurrentRoute(r); route != nil {
+			attrs = append(attrs, trace.StringAttribute("route", route.GetName()))
+		}
+		if reqID := gdctx.GetReqID(ctx); reqID != nil {
+			attrs = append(attrs, trace.StringAttribute("reqID", reqID.String()))
+		}
+		if volname := gdctx.GetVolName(ctx); volname != "" {
+			attrs = append(attrs, trace.StringAttribute("volume", volname))
+		}
+		span.AddAttributes(attrs...)
+
+		next.ServeHTTP(w, r)
+	})
+}
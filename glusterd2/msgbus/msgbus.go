@@ -0,0 +1,204 @@
+// Package msgbus implements a lightweight publish/subscribe message bus
+// between the peers of the cluster. Plugins can use it to broadcast messages
+// on their own topics, like the state changes of geo-replication workers, and
+// to subscribe to them on every peer.
+//
+// Messages are published by saving them in the store with a TTL, and are
+// delivered by watching the store. The delivery guarantees are,
+//
+//   - A message is delivered to every subscription of its topic on every
+//     peer, including the peer which published it, at most once.
+//   - Messages are delivered to a subscription in the order they were
+//     published in.
+//   - Messages published while a peer is disconnected from the store are
+//     delivered once it reconnects, unless they have expired or have been
+//     compacted from the store in the meantime.
+//   - Messages are only delivered to the subscriptions present when they are
+//     received. They are not delivered to peers which join the cluster later.
+//   - Every subscription has a queue of messages waiting to be delivered.
+//     Messages are dropped for a subscription whose queue is full, so that a
+//     slow subscriber doesn't hold up the others. Dropped messages are counted
+//     in the subscription.
+//
+// Subscribers which need every message must not rely on the bus alone, and
+// should get the current state from the store when they miss messages.
+package msgbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	msgbusPrefix = "msgbus/"
+
+	// messageTTL is the time messages are kept in the store for peers to
+	// receive them
+	messageTTL int64 = 300
+
+	// maxMessageSize is the maximum size of the marshalled data of a
+	// message
+	maxMessageSize = 64 * 1024
+
+	// watchRetryInterval is the wait before watching the store again when
+	// the watch fails
+	watchRetryInterval = 5 * time.Second
+)
+
+var (
+	// ErrInvalidTopic is returned for topic names which are not valid
+	ErrInvalidTopic = errors.New("invalid message bus topic")
+	// ErrMessageTooLarge is returned when publishing messages larger than
+	// the maximum message size
+	ErrMessageTooLarge = fmt.Errorf("message data is larger than %d bytes", maxMessageSize)
+
+	validTopic = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]{0,127}$`)
+)
+
+// Message is a message published on the bus
+type Message struct {
+	ID    uuid.UUID `json:"id"`
+	Topic string    `json:"topic"`
+	// Origin is the ID of the peer which published the message
+	Origin    uuid.UUID       `json:"origin"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// Decode unmarshals the data of the message into v
+func (m *Message) Decode(v interface{}) error {
+	return json.Unmarshal(m.Data, v)
+}
+
+// Publish publishes a message on the topic to all the peers. The data is
+// marshalled as JSON.
+func Publish(topic string, data interface{}) error {
+	if !validTopic.MatchString(topic) {
+		return ErrInvalidTopic
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if len(b) > maxMessageSize {
+		return ErrMessageTooLarge
+	}
+
+	msg := &Message{
+		ID:        uuid.NewRandom(),
+		Topic:     topic,
+		Origin:    gdctx.MyUUID,
+		Timestamp: time.Now(),
+		Data:      b,
+	}
+	v, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	// Messages are put with a TTL, so that they don't linger in the store
+	// after every peer had the chance to receive them
+	l, err := store.Store.Grant(store.Store.Ctx(), messageTTL)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(store.Store.Ctx(), msgbusPrefix+msg.ID.String(), string(v), clientv3.WithLease(l.ID))
+	return err
+}
+
+type watcher struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var (
+	watcherMu sync.Mutex
+	bw        *watcher
+)
+
+// Start starts delivering messages published on the bus to the local
+// subscriptions. Should only be called after the store is up.
+func Start() error {
+	watcherMu.Lock()
+	defer watcherMu.Unlock()
+
+	if bw != nil {
+		return nil
+	}
+
+	// Only the messages published from now on are delivered
+	resp, err := store.Get(context.TODO(), msgbusPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bw = &watcher{cancel: cancel}
+	bw.wg.Add(1)
+	go bw.watch(ctx, resp.Header.Revision)
+
+	return nil
+}
+
+// Stop stops delivering messages. The subscriptions are kept, and messages
+// are delivered to them again once the bus is started.
+func Stop() {
+	watcherMu.Lock()
+	defer watcherMu.Unlock()
+
+	if bw == nil {
+		return
+	}
+	bw.cancel()
+	bw.wg.Wait()
+	bw = nil
+}
+
+// watch delivers the messages put in the store after rev, watching the
+// store again from the last message delivered if the watch fails
+func (w *watcher) watch(ctx context.Context, rev int64) {
+	defer w.wg.Done()
+
+	for {
+		wch := store.Store.Watch(ctx, msgbusPrefix, clientv3.WithPrefix(),
+			clientv3.WithFilterDelete(), clientv3.WithRev(rev+1))
+		for wresp := range wch {
+			if err := wresp.Err(); err != nil {
+				if wresp.CompactRevision != 0 {
+					log.WithField("revision", wresp.CompactRevision).Warn("message bus fell behind store compaction, messages may have been missed")
+					rev = wresp.CompactRevision - 1
+				}
+				break
+			}
+
+			for _, ev := range wresp.Events {
+				var msg Message
+				if err := json.Unmarshal(ev.Kv.Value, &msg); err != nil {
+					log.WithError(err).WithField("key", string(ev.Kv.Key)).Error("could not unmarshal message")
+					continue
+				}
+				subs.dispatch(&msg)
+			}
+			rev = wresp.Header.Revision
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+	}
+}
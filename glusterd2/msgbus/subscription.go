@@ -0,0 +1,160 @@
+package msgbus
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// subscriptionQueueLen is the number of messages which can wait to be
+// delivered to a subscription before messages are dropped for it
+const subscriptionQueueLen = 256
+
+// Handler is called with the messages delivered to a subscription. The
+// messages of a subscription are delivered one at a time.
+type Handler func(*Message)
+
+type subscription struct {
+	// Accessed atomically, kept first for 64-bit alignment
+	delivered uint64
+	dropped   uint64
+
+	id         string
+	topic      string
+	subscriber string
+	created    time.Time
+	handler    Handler
+
+	queue chan *Message
+	done  chan struct{}
+}
+
+type subscriptions struct {
+	sync.RWMutex
+	subs map[string]*subscription
+}
+
+var subs = &subscriptions{subs: make(map[string]*subscription)}
+
+// Subscribe subscribes the handler to the messages published on the topic.
+// The subscriber is the name of the component subscribing, like the name of
+// a plugin, and is used when listing subscriptions. The ID of the
+// subscription is returned, to unsubscribe with.
+func Subscribe(topic, subscriber string, handler Handler) (string, error) {
+	if !validTopic.MatchString(topic) {
+		return "", ErrInvalidTopic
+	}
+
+	s := &subscription{
+		id:         uuid.NewRandom().String(),
+		topic:      topic,
+		subscriber: subscriber,
+		created:    time.Now(),
+		handler:    handler,
+		queue:      make(chan *Message, subscriptionQueueLen),
+		done:       make(chan struct{}),
+	}
+	go s.run()
+
+	subs.Lock()
+	subs.subs[s.id] = s
+	subs.Unlock()
+
+	return s.id, nil
+}
+
+// Unsubscribe removes the subscription with the given ID. Messages waiting to
+// be delivered to it are dropped.
+func Unsubscribe(id string) {
+	subs.Lock()
+	s, ok := subs.subs[id]
+	delete(subs.subs, id)
+	subs.Unlock()
+
+	if ok {
+		close(s.done)
+	}
+}
+
+// Subscriptions returns the subscriptions of this peer, sorted by topic
+func Subscriptions() []api.MsgBusSubscription {
+	subs.RLock()
+	defer subs.RUnlock()
+
+	list := make([]api.MsgBusSubscription, 0, len(subs.subs))
+	for _, s := range subs.subs {
+		list = append(list, api.MsgBusSubscription{
+			ID:         s.id,
+			Topic:      s.topic,
+			Subscriber: s.subscriber,
+			Created:    s.created,
+			Delivered:  atomic.LoadUint64(&s.delivered),
+			Dropped:    atomic.LoadUint64(&s.dropped),
+			Queued:     len(s.queue),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Topic != list[j].Topic {
+			return list[i].Topic < list[j].Topic
+		}
+		return list[i].Created.Before(list[j].Created)
+	})
+
+	return list
+}
+
+// dispatch queues the message for the subscriptions of its topic
+func (ss *subscriptions) dispatch(msg *Message) {
+	ss.RLock()
+	defer ss.RUnlock()
+
+	for _, s := range ss.subs {
+		if s.topic != msg.Topic {
+			continue
+		}
+		select {
+		case s.queue <- msg:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+			log.WithFields(log.Fields{
+				"topic":      s.topic,
+				"subscriber": s.subscriber,
+				"message":    msg.ID.String(),
+			}).Warn("message bus subscriber is falling behind, dropped message")
+		}
+	}
+}
+
+// run delivers the queued messages to the handler of the subscription till
+// it is unsubscribed
+func (s *subscription) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case msg := <-s.queue:
+			s.deliver(msg)
+		}
+	}
+}
+
+func (s *subscription) deliver(msg *Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.WithFields(log.Fields{
+				"topic":      s.topic,
+				"subscriber": s.subscriber,
+				"panic":      r,
+			}).Error("message bus subscriber panicked handling message")
+		}
+	}()
+
+	s.handler(msg)
+	atomic.AddUint64(&s.delivered, 1)
+}
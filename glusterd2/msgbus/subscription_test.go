@@ -0,0 +1,62 @@
+package msgbus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeDispatch(t *testing.T) {
+	_, err := Subscribe("", "test", func(*Message) {})
+	assert.Equal(t, ErrInvalidTopic, err)
+
+	received := make(chan *Message, 1)
+	id, err := Subscribe("georep/worker-state", "test", func(m *Message) {
+		received <- m
+	})
+	assert.Nil(t, err)
+
+	subs.dispatch(&Message{ID: uuid.NewRandom(), Topic: "other", Data: []byte(`1`)})
+	subs.dispatch(&Message{ID: uuid.NewRandom(), Topic: "georep/worker-state", Data: []byte(`{"state":"active"}`)})
+
+	select {
+	case m := <-received:
+		var data map[string]string
+		assert.Nil(t, m.Decode(&data))
+		assert.Equal(t, "active", data["state"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not delivered")
+	}
+
+	list := Subscriptions()
+	assert.Len(t, list, 1)
+	assert.Equal(t, id, list[0].ID)
+	assert.Equal(t, "test", list[0].Subscriber)
+
+	Unsubscribe(id)
+	assert.Empty(t, Subscriptions())
+}
+
+func TestSlowSubscriberDropsMessages(t *testing.T) {
+	block := make(chan struct{})
+	id, err := Subscribe("slow", "test", func(*Message) {
+		<-block
+	})
+	assert.Nil(t, err)
+	defer Unsubscribe(id)
+
+	// One message is taken by the blocked handler, the rest fill the queue
+	for i := 0; i < subscriptionQueueLen+10; i++ {
+		subs.dispatch(&Message{ID: uuid.NewRandom(), Topic: "slow"})
+		if i == 0 {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	s := Subscriptions()[0]
+	assert.Equal(t, uint64(9), s.Dropped)
+	assert.Equal(t, subscriptionQueueLen, s.Queued)
+	close(block)
+}
@@ -27,12 +27,31 @@ type validateFunc func(string, string) error
 
 // ClusterOptMap contains list of supported cluster-wide options, default values and value types
 var ClusterOptMap = map[string]*ClusterOption{
-	"cluster.shared-storage":         {"cluster.shared-storage", "off", OptionTypeBool, nil},
-	"cluster.op-version":             {"cluster.op-version", strconv.Itoa(gdctx.OpVersion), OptionTypeInt, nil},
-	"cluster.max-op-version":         {"cluster.max-op-version", strconv.Itoa(gdctx.OpVersion), OptionTypeInt, nil},
-	"cluster.brick-multiplex":        {"cluster.brick-multiplex", "off", OptionTypeBool, nil},
-	"cluster.max-bricks-per-process": {"cluster.max-bricks-per-process", "250", OptionTypeInt, nil},
-	"cluster.localtime-logging":      {"cluster.localtime-logging", "off", OptionTypeBool, nil},
+	"cluster.shared-storage":            {"cluster.shared-storage", "off", OptionTypeBool, nil},
+	"cluster.op-version":                {"cluster.op-version", strconv.Itoa(gdctx.OpVersion), OptionTypeInt, nil},
+	"cluster.max-op-version":            {"cluster.max-op-version", strconv.Itoa(gdctx.OpVersion), OptionTypeInt, nil},
+	"cluster.brick-multiplex":           {"cluster.brick-multiplex", "off", OptionTypeBool, nil},
+	"cluster.max-bricks-per-process":    {"cluster.max-bricks-per-process", "250", OptionTypeInt, nil},
+	"cluster.localtime-logging":         {"cluster.localtime-logging", "off", OptionTypeBool, nil},
+	"cluster.brick-umask":               {"cluster.brick-umask", "", OptionTypeStr, nil},
+	"cluster.brick-nice":                {"cluster.brick-nice", "0", OptionTypeInt, nil},
+	"cluster.brick-ionice-class":        {"cluster.brick-ionice-class", "", OptionTypeStr, nil},
+	"cluster.brick-ionice-level":        {"cluster.brick-ionice-level", "4", OptionTypeInt, nil},
+	"cluster.brick-oom-score-adj":       {"cluster.brick-oom-score-adj", "0", OptionTypeInt, nil},
+	"cluster.brick-nofile":              {"cluster.brick-nofile", "0", OptionTypeInt, nil},
+	"cluster.brick-user":                {"cluster.brick-user", "", OptionTypeStr, nil},
+	"cluster.brick-group":               {"cluster.brick-group", "", OptionTypeStr, nil},
+	"cluster.volume-request-forwarding": {"cluster.volume-request-forwarding", "off", OptionTypeBool, nil},
+	"cluster.glustershd-systemd":        {"cluster.glustershd-systemd", "off", OptionTypeBool, nil},
+	"cluster.scrubd-systemd":            {"cluster.scrubd-systemd", "off", OptionTypeBool, nil},
+	"cluster.gsyncd-systemd":            {"cluster.gsyncd-systemd", "off", OptionTypeBool, nil},
+	"cluster.volume-trash-retention":    {"cluster.volume-trash-retention", "0", OptionTypeInt, nil},
+	"cluster.io-ops-priority":           {"cluster.io-ops-priority", "full-heal,rebalance,scrub", OptionTypeStr, nil},
+	"cluster.io-ops-policy":             {"cluster.io-ops-policy", "queue", OptionTypeStr, nil},
+	"cluster.txn-history-retention":     {"cluster.txn-history-retention", "24", OptionTypeInt, nil},
+	"cluster.txn-history-max":           {"cluster.txn-history-max", "1000", OptionTypeInt, nil},
+	"cluster.audit-store":               {"cluster.audit-store", "off", OptionTypeBool, nil},
+	"cluster.audit-store-retention":     {"cluster.audit-store-retention", "720", OptionTypeInt, nil},
 }
 
 // RegisterClusterOpValidationFunc registers a validation function for provided
@@ -68,7 +87,7 @@ func GetClusterOptions() (*ClusterOptions, error) {
 
 // GetClusterOption returns the value set for the cluster option specified. If
 // the value is not set for the key, it returns the default value for the
-// option.
+// option. The value overridden for this peer, if any, takes precedence.
 func GetClusterOption(key string) (string, error) {
 	globalopt, found := ClusterOptMap[key]
 	if !found {
@@ -87,6 +106,14 @@ func GetClusterOption(key string) (string, error) {
 		}
 	}
 
+	value, ok, err := getLocalOverride(key)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		result = value
+	}
+
 	return result, nil
 }
 
@@ -34,6 +34,32 @@ const (
 	OptionTypeClientAuthAddr
 )
 
+var optionTypeNames = map[OptionType]string{
+	OptionTypeAny:                 "any",
+	OptionTypeStr:                 "str",
+	OptionTypeInt:                 "int",
+	OptionTypeSizet:               "size_t",
+	OptionTypePercent:             "percent",
+	OptionTypePercentOrSizet:      "percent-or-size_t",
+	OptionTypeBool:                "bool",
+	OptionTypeXlator:              "xlator",
+	OptionTypePath:                "path",
+	OptionTypeTime:                "time",
+	OptionTypeDouble:              "double",
+	OptionTypeInternetAddress:     "internet-address",
+	OptionTypeInternetAddressList: "internet-address-list",
+	OptionTypePriorityList:        "priority-list",
+	OptionTypeSizeList:            "size-list",
+	OptionTypeClientAuthAddr:      "client-auth-addr",
+}
+
+func (t OptionType) String() string {
+	if name, ok := optionTypeNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
 // OptionValidateType is a type which represents how the value of xlator
 // option should be validated.
 type OptionValidateType int
@@ -62,6 +88,11 @@ const (
 	OptionFlagNone = 0
 )
 
+// OptionFlagReconfigurable is set by GD2 on options which can be changed on
+// running brick processes without restarting them. It is not loaded from the
+// xlators and uses a bit not used by glusterfs.
+const OptionFlagReconfigurable OptionFlag = 1 << 31
+
 // OptionLevel is the level at which option is visible to users
 type OptionLevel uint
 
@@ -134,6 +165,18 @@ func (o *Option) IsForceRequired() bool {
 	return (o.Flags & OptionFlagForce) == OptionFlagForce
 }
 
+// IsClientOpt returns true if the option is of an xlator in the client graph,
+// returns false otherwise.
+func (o *Option) IsClientOpt() bool {
+	return (o.Flags & OptionFlagClientOpt) == OptionFlagClientOpt
+}
+
+// IsReconfigurable returns true if the option can be changed on running
+// brick processes without restarting them, returns false otherwise.
+func (o *Option) IsReconfigurable() bool {
+	return (o.Flags & OptionFlagReconfigurable) == OptionFlagReconfigurable
+}
+
 // IsAdvanced returns true if the option is an advanced option
 func (o *Option) IsAdvanced() bool {
 	return o.Level == OptionStatusAdvanced
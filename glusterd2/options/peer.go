@@ -0,0 +1,108 @@
+package options
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+const peerOptionsPrefix = "peer-options/"
+
+// PeerOverridableOptions lists the cluster options which can be overridden
+// for individual peers. These options only affect the processes on the peer
+// where they are applied.
+var PeerOverridableOptions = map[string]bool{
+	"cluster.max-bricks-per-process": true,
+	"cluster.brick-umask":            true,
+	"cluster.brick-nice":             true,
+	"cluster.brick-ionice-class":     true,
+	"cluster.brick-ionice-level":     true,
+	"cluster.brick-oom-score-adj":    true,
+	"cluster.brick-nofile":           true,
+	"cluster.brick-user":             true,
+	"cluster.brick-group":            true,
+	"cluster.glustershd-systemd":     true,
+	"cluster.scrubd-systemd":         true,
+	"cluster.gsyncd-systemd":         true,
+}
+
+// GetPeerOptions returns the cluster options overridden for the given peer
+func GetPeerOptions(peerID string) (map[string]string, error) {
+	resp, err := store.Get(context.TODO(), peerOptionsPrefix+peerID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := make(map[string]string)
+	if resp.Count != 1 {
+		return opts, nil
+	}
+
+	if err := json.Unmarshal(resp.Kvs[0].Value, &opts); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// UpdatePeerOptions stores the cluster options overridden for the given peer.
+// The overrides are removed if no options are given.
+func UpdatePeerOptions(peerID string, opts map[string]string) error {
+	if len(opts) == 0 {
+		_, err := store.Delete(context.TODO(), peerOptionsPrefix+peerID)
+		return err
+	}
+
+	b, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), peerOptionsPrefix+peerID, string(b))
+	return err
+}
+
+// getLocalOverride returns the value of the option overridden for this peer,
+// if the option can be overridden and has been
+func getLocalOverride(key string) (string, bool, error) {
+	if !PeerOverridableOptions[key] {
+		return "", false, nil
+	}
+
+	opts, err := GetPeerOptions(gdctx.MyUUID.String())
+	if err != nil {
+		return "", false, err
+	}
+
+	value, ok := opts[key]
+	return value, ok, nil
+}
+
+// GetLocalClusterOptions returns the cluster options in effect on this peer,
+// which are the cluster options with the overrides for this peer applied
+func GetLocalClusterOptions() (*ClusterOptions, error) {
+	c, err := GetClusterOptions()
+	if err != nil && err != errors.ErrClusterOptionsNotFound {
+		return nil, err
+	}
+	if c == nil {
+		c = new(ClusterOptions)
+	}
+	if c.Options == nil {
+		c.Options = make(map[string]string)
+	}
+
+	opts, err := GetPeerOptions(gdctx.MyUUID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range opts {
+		if PeerOverridableOptions[k] {
+			c.Options[k] = v
+		}
+	}
+	return c, nil
+}
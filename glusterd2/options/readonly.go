@@ -0,0 +1,43 @@
+package options
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+const (
+	readOnlyModeKey string = "readonlymode"
+)
+
+// GetReadOnlyMode returns the read-only mode of the cluster
+func GetReadOnlyMode() (*api.ReadOnlyModeResp, error) {
+	resp, err := store.Get(context.TODO(), readOnlyModeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var m api.ReadOnlyModeResp
+	if resp.Count != 1 {
+		return &m, nil
+	}
+
+	if err = json.Unmarshal(resp.Kvs[0].Value, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// SetReadOnlyMode stores the read-only mode of the cluster
+func SetReadOnlyMode(m *api.ReadOnlyModeResp) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), readOnlyModeKey, string(b))
+	return err
+}
@@ -0,0 +1,71 @@
+package peer
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// evacuationPrefix must not be under peerPrefix, as everything under
+// peerPrefix is expected to be a peer
+const evacuationPrefix = "peer-evacuation/"
+
+// SetEvacuation saves the state of the evacuation of the peer
+func SetEvacuation(ev *api.PeerEvacuationResp) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), evacuationPrefix+ev.PeerID, string(b))
+	return err
+}
+
+// GetEvacuation returns the state of the evacuation of the peer
+func GetEvacuation(id string) (*api.PeerEvacuationResp, error) {
+	resp, err := store.Get(context.TODO(), evacuationPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, errors.ErrPeerEvacuationNotFound
+	}
+
+	var ev api.PeerEvacuationResp
+	if err := json.Unmarshal(resp.Kvs[0].Value, &ev); err != nil {
+		return nil, err
+	}
+
+	return &ev, nil
+}
+
+// GetEvacuations returns the state of all peer evacuations
+func GetEvacuations() ([]*api.PeerEvacuationResp, error) {
+	resp, err := store.Get(context.TODO(), evacuationPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	evacuations := make([]*api.PeerEvacuationResp, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var ev api.PeerEvacuationResp
+		if err := json.Unmarshal(kv.Value, &ev); err != nil {
+			return nil, err
+		}
+		evacuations = append(evacuations, &ev)
+	}
+
+	return evacuations, nil
+}
+
+// DeleteEvacuation deletes the state of the evacuation of the peer
+func DeleteEvacuation(id string) error {
+	_, err := store.Delete(context.TODO(), evacuationPrefix+id)
+	return err
+}
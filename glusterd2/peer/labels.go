@@ -0,0 +1,134 @@
+package peer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const maxLabelLength = 63
+
+// labelRE matches valid label keys and values. Keys and values start and end
+// with letters or digits, and may have '-', '_', '.' and '/' in between.
+var labelRE = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._/-]*[a-zA-Z0-9])?$`)
+
+// ValidateLabel checks if the label key and value are valid. Values may be
+// empty, for labels used as tags.
+func ValidateLabel(key, value string) error {
+	if len(key) > maxLabelLength || !labelRE.MatchString(key) {
+		return fmt.Errorf("invalid label key %q", key)
+	}
+	if value != "" && (len(value) > maxLabelLength || !labelRE.MatchString(value)) {
+		return fmt.Errorf("invalid value %q for label %s", value, key)
+	}
+	return nil
+}
+
+type selectorOp int
+
+const (
+	opExists selectorOp = iota
+	opNotExists
+	opEquals
+	opNotEquals
+)
+
+type requirement struct {
+	key   string
+	op    selectorOp
+	value string
+}
+
+func (r requirement) matches(labels map[string]string) bool {
+	value, found := labels[r.key]
+	switch r.op {
+	case opExists:
+		return found
+	case opNotExists:
+		return !found
+	case opEquals:
+		return found && value == r.value
+	case opNotEquals:
+		return !found || value != r.value
+	}
+	return false
+}
+
+// Selector selects peers by their labels. A selector is a comma separated
+// list of requirements, all of which must be met by the labels of a peer.
+// Requirements are of the forms key=value, key!=value, key, and !key for
+// labels which must be set, or must not be. An empty selector selects all
+// peers.
+type Selector []requirement
+
+// ParseSelector parses a selector like "disk=ssd,rack!=r1,!draining"
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	if strings.TrimSpace(s) == "" {
+		return sel, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+
+		var r requirement
+		switch {
+		case strings.Contains(part, "!="):
+			kv := strings.SplitN(part, "!=", 2)
+			r = requirement{key: strings.TrimSpace(kv[0]), op: opNotEquals, value: strings.TrimSpace(kv[1])}
+		case strings.Contains(part, "="):
+			kv := strings.SplitN(part, "=", 2)
+			r = requirement{key: strings.TrimSpace(kv[0]), op: opEquals, value: strings.TrimSpace(kv[1])}
+		case strings.HasPrefix(part, "!"):
+			r = requirement{key: strings.TrimSpace(part[1:]), op: opNotExists}
+		default:
+			r = requirement{key: part, op: opExists}
+		}
+
+		if err := ValidateLabel(r.key, r.value); err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %s", s, err)
+		}
+		sel = append(sel, r)
+	}
+	return sel, nil
+}
+
+// Empty returns true if the selector selects all peers
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+// Matches returns true if the labels meet all the requirements of the
+// selector
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetPeersBySelector returns the peers whose labels match the selector
+func GetPeersBySelector(selector string) ([]*Peer, error) {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := GetPeers()
+	if err != nil {
+		return nil, err
+	}
+	if sel.Empty() {
+		return peers, nil
+	}
+
+	var selected []*Peer
+	for _, p := range peers {
+		if sel.Matches(p.Labels) {
+			selected = append(selected, p)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,47 @@
+package peer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLabel(t *testing.T) {
+	assert.NoError(t, ValidateLabel("disk", "ssd"))
+	assert.NoError(t, ValidateLabel("example.com/rack", "r1"))
+	assert.NoError(t, ValidateLabel("gpu", ""))
+
+	assert.Error(t, ValidateLabel("", "ssd"))
+	assert.Error(t, ValidateLabel("-disk", "ssd"))
+	assert.Error(t, ValidateLabel("disk", "ssd,nvme"))
+	assert.Error(t, ValidateLabel("disk type", "ssd"))
+}
+
+func TestSelector(t *testing.T) {
+	labels := map[string]string{"disk": "ssd", "rack": "r1", "gpu": ""}
+
+	for s, expected := range map[string]bool{
+		"":                   true,
+		"disk=ssd":           true,
+		"disk = ssd, gpu":    true,
+		"disk=hdd":           false,
+		"rack!=r2":           true,
+		"rack!=r1":           false,
+		"zone!=z1":           true,
+		"gpu":                true,
+		"gpu=":               true,
+		"!gpu":               false,
+		"!draining":          true,
+		"disk=ssd,rack=r2":   false,
+		"disk=ssd,!zone,gpu": true,
+	} {
+		sel, err := ParseSelector(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, sel.Matches(labels), s)
+	}
+
+	for _, s := range []string{"=ssd", "disk=ssd,", "!", "disk==ssd"} {
+		_, err := ParseSelector(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,84 @@
+package peer
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+)
+
+// ownerVnodes is the number of points each peer gets on the hash ring. More
+// points spread the keys more evenly among the peers.
+const ownerVnodes = 64
+
+// ErrNoOwner is returned when no peer is alive to own a key
+var ErrNoOwner = errors.New("no peer is alive to own the key")
+
+type ringPoint struct {
+	hash uint64
+	id   string
+}
+
+func ringHash(s string) uint64 {
+	sum := md5.Sum([]byte(s))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// OwnerOf returns the ID, among the given peer IDs, of the owner of key using
+// consistent hashing. When a peer is added or removed, only the keys owned by
+// that peer change their owner. An empty string is returned if no IDs are
+// given.
+func OwnerOf(key string, ids []string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+
+	ring := make([]ringPoint, 0, len(ids)*ownerVnodes)
+	for _, id := range ids {
+		for i := 0; i < ownerVnodes; i++ {
+			ring = append(ring, ringPoint{ringHash(id + "-" + strconv.Itoa(i)), id})
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool {
+		if ring[i].hash == ring[j].hash {
+			return ring[i].id < ring[j].id
+		}
+		return ring[i].hash < ring[j].hash
+	})
+
+	h := ringHash(key)
+	idx := sort.Search(len(ring), func(i int) bool { return ring[i].hash >= h })
+	if idx == len(ring) {
+		idx = 0
+	}
+	return ring[idx].id
+}
+
+// VolumeOwner returns the peer owning the given volume among the peers which
+// are currently alive. Ownership moves automatically to another peer when the
+// owner goes down or peers are added or removed.
+func VolumeOwner(volname string) (*Peer, error) {
+	peers, err := GetPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	alive := make(map[string]*Peer)
+	var ids []string
+	for _, p := range peers {
+		if _, ok := store.Store.IsNodeAlive(p.ID); !ok {
+			continue
+		}
+		alive[p.ID.String()] = p
+		ids = append(ids, p.ID.String())
+	}
+
+	owner := OwnerOf(volname, ids)
+	if owner == "" {
+		return nil, ErrNoOwner
+	}
+	return alive[owner], nil
+}
@@ -0,0 +1,38 @@
+package peer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnerOf(t *testing.T) {
+	assert.Equal(t, "", OwnerOf("vol", nil))
+	assert.Equal(t, "a", OwnerOf("vol", []string{"a"}))
+
+	ids := []string{"a", "b", "c", "d"}
+	owners := make(map[string]string)
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		vol := fmt.Sprintf("vol%d", i)
+		owners[vol] = OwnerOf(vol, ids)
+		counts[owners[vol]]++
+
+		// Ownership must not depend on the order of the peers
+		assert.Equal(t, owners[vol], OwnerOf(vol, []string{"d", "c", "b", "a"}))
+	}
+	for _, id := range ids {
+		assert.True(t, counts[id] > 100, "peer %s owns too few volumes", id)
+	}
+
+	// Removing a peer must only move the volumes owned by it
+	for vol, owner := range owners {
+		newOwner := OwnerOf(vol, []string{"a", "b", "d"})
+		if owner != "c" {
+			assert.Equal(t, owner, newOwner)
+		} else {
+			assert.NotEqual(t, "c", newOwner)
+		}
+	}
+}
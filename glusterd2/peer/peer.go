@@ -14,6 +14,9 @@ type Peer struct {
 	PeerAddresses   []string
 	ClientAddresses []string
 	Metadata        map[string]string
+	// Labels are set by the admin to select peers, in placement and in
+	// commands run on several peers
+	Labels map[string]string
 }
 
 // ETCDConfig represents the structure which holds the ETCD env variables &
@@ -27,6 +30,26 @@ type ETCDConfig struct {
 	DeletePeer     bool
 }
 
+// IsManagementOnly returns true if the peer runs as a management node, which
+// serves the REST API and originates transactions but doesn't host bricks
+func (p *Peer) IsManagementOnly() bool {
+	return p.Metadata[roleKey] == roleManagement
+}
+
+// ZoneKey is the metadata key holding the zone of the peer
+const ZoneKey = "_zone"
+
+// Zone returns the zone of the peer, the failure domain across which the
+// bricks of replicate and disperse subvolumes are spread. Peers without a
+// zone are each in their own zone.
+func (p *Peer) Zone() string {
+	zone := strings.TrimSpace(p.Metadata[ZoneKey])
+	if zone == "" {
+		return p.ID.String()
+	}
+	return zone
+}
+
 // MetadataSize returns the size of metadata from peer info
 func (p *Peer) MetadataSize() int {
 	size := 0
@@ -5,12 +5,25 @@ import (
 	"net"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/utils"
 
 	config "github.com/spf13/viper"
 )
 
+const (
+	// stateKey is the peer metadata key recording the state of a peer
+	// which has been retired
+	stateKey     = "_state"
+	stateOffline = "offline"
+
+	// roleKey is the peer metadata key recording the role of a peer which
+	// runs as a management node
+	roleKey        = "_role"
+	roleManagement = "management"
+)
+
 func normalizeAddrs() ([]string, error) {
 
 	shost, sport, err := net.SplitHostPort(config.GetString("clientaddress"))
@@ -57,10 +70,13 @@ func AddSelfDetails() error {
 	peerInfo, err := GetPeer(gdctx.MyUUID.String())
 	if err == errors.ErrPeerNotFound {
 		p.Metadata = make(map[string]string)
-		p.Metadata["_zone"] = p.ID.String()
+		p.Metadata[ZoneKey] = p.ID.String()
 
 	} else if err == nil && peerInfo != nil {
 		p.Metadata = peerInfo.Metadata
+		p.Labels = peerInfo.Labels
+		// The peer is back, it is no longer retired
+		delete(p.Metadata, stateKey)
 
 		found := utils.StringInSlice(p.PeerAddresses[0], peerInfo.PeerAddresses)
 		if !found {
@@ -73,5 +89,34 @@ func AddSelfDetails() error {
 		return err
 	}
 
+	if store.ManagementOnly() {
+		if p.Metadata == nil {
+			p.Metadata = make(map[string]string)
+		}
+		p.Metadata[roleKey] = roleManagement
+	} else {
+		delete(p.Metadata, roleKey)
+	}
+
 	return AddOrUpdatePeer(p)
 }
+
+// Retire marks this peer offline in the store and removes it from the etcd
+// cluster membership, so that a permanently retired peer doesn't degrade the
+// etcd quorum
+func Retire() error {
+	p, err := GetPeer(gdctx.MyUUID.String())
+	if err != nil {
+		return err
+	}
+
+	if p.Metadata == nil {
+		p.Metadata = make(map[string]string)
+	}
+	p.Metadata[stateKey] = stateOffline
+	if err := AddOrUpdatePeer(p); err != nil {
+		return err
+	}
+
+	return store.Store.Leave()
+}
@@ -7,9 +7,11 @@ import (
 	"github.com/gluster/glusterd2/plugins/device"
 	"github.com/gluster/glusterd2/plugins/events"
 	"github.com/gluster/glusterd2/plugins/georeplication"
+	"github.com/gluster/glusterd2/plugins/gfproxy"
 	"github.com/gluster/glusterd2/plugins/glustershd"
 	"github.com/gluster/glusterd2/plugins/quota"
 	"github.com/gluster/glusterd2/plugins/rebalance"
+	"github.com/gluster/glusterd2/plugins/validation"
 
 	// ensure init() of non-plugins also gets executed
 	_ "github.com/gluster/glusterd2/plugins/afr"
@@ -25,4 +27,6 @@ var PluginsList = []GlusterdPlugin{
 	&glustershd.Plugin{},
 	&device.Plugin{},
 	&rebalance.Plugin{},
+	&gfproxy.Plugin{},
+	&validation.Plugin{},
 }
@@ -0,0 +1,252 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule determines when a job has to be run next
+type Schedule interface {
+	// Next returns the next activation time, later than the given time.
+	// A zero time is returned if the schedule can never be satisfied.
+	Next(time.Time) time.Time
+}
+
+// ErrInvalidSchedule is returned when a schedule expression cannot be parsed
+var ErrInvalidSchedule = errors.New("invalid schedule expression")
+
+// bounds of each field in a cron expression
+type bounds struct {
+	min, max uint
+}
+
+var (
+	minuteBounds = bounds{0, 59}
+	hourBounds   = bounds{0, 23}
+	domBounds    = bounds{1, 31}
+	monthBounds  = bounds{1, 12}
+	dowBounds    = bounds{0, 7}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSchedule is a schedule represented by a standard 5 field cron
+// expression. Each field is stored as a bitset of the matching values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// cron matches either of day of month or day of week when both are
+	// restricted
+	domStar, dowStar bool
+}
+
+// everySchedule runs a job at a fixed interval
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Next implements the Schedule interface
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval - time.Duration(t.Nanosecond()))
+}
+
+// ParseSchedule parses a schedule expression. The expression can be a
+// standard 5 field cron expression ("minute hour day-of-month month
+// day-of-week"), one of the descriptors @yearly, @monthly, @weekly, @daily
+// and @hourly or "@every <duration>".
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", ErrInvalidSchedule, err)
+		}
+		if d < time.Second {
+			return nil, fmt.Errorf("%s: interval must be at least 1s", ErrInvalidSchedule)
+		}
+		return everySchedule{d}, nil
+	}
+
+	if expr, ok := descriptors[spec]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%s: expected 5 fields, found %d", ErrInvalidSchedule, len(fields))
+	}
+
+	var (
+		s   cronSchedule
+		err error
+	)
+
+	if s.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, err
+	}
+
+	// Both 0 and 7 represent Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	s.domStar = fields[2] == "*"
+	s.dowStar = fields[4] == "*"
+
+	return &s, nil
+}
+
+// parseField parses a comma separated list of ranges into a bitset
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, r := range strings.Split(field, ",") {
+		rbits, err := parseRange(r, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= rbits
+	}
+	return bits, nil
+}
+
+// parseRange parses a single range of the form "*", "n", "n-m" with an
+// optional step "/s"
+func parseRange(r string, b bounds) (uint64, error) {
+	var (
+		start, end, step uint = b.min, b.max, 1
+		err              error
+	)
+
+	rangeAndStep := strings.Split(r, "/")
+	if len(rangeAndStep) > 2 {
+		return 0, fmt.Errorf("%s: %q", ErrInvalidSchedule, r)
+	}
+
+	lowAndHigh := strings.Split(rangeAndStep[0], "-")
+	switch {
+	case len(lowAndHigh) == 1 && lowAndHigh[0] == "*":
+	case len(lowAndHigh) == 1:
+		if start, err = parseUint(lowAndHigh[0]); err != nil {
+			return 0, err
+		}
+		end = start
+	case len(lowAndHigh) == 2:
+		if start, err = parseUint(lowAndHigh[0]); err != nil {
+			return 0, err
+		}
+		if end, err = parseUint(lowAndHigh[1]); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("%s: %q", ErrInvalidSchedule, r)
+	}
+
+	if len(rangeAndStep) == 2 {
+		if step, err = parseUint(rangeAndStep[1]); err != nil {
+			return 0, err
+		}
+		if step == 0 {
+			return 0, fmt.Errorf("%s: step cannot be zero in %q", ErrInvalidSchedule, r)
+		}
+		// "n/s" means from n till the end of the range
+		if len(lowAndHigh) == 1 && lowAndHigh[0] != "*" {
+			end = b.max
+		}
+	}
+
+	if start < b.min || end > b.max || start > end {
+		return 0, fmt.Errorf("%s: %q is out of range [%d-%d]", ErrInvalidSchedule, r, b.min, b.max)
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << i
+	}
+	return bits, nil
+}
+
+func parseUint(s string) (uint, error) {
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %q is not a valid number", ErrInvalidSchedule, s)
+	}
+	return uint(n), nil
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next implements the Schedule interface
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	// Start from the beginning of the next minute
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Give up if no matching time is found within 5 years, which can
+	// happen for impossible dates like 30th of February
+	yearLimit := t.Year() + 5
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for s.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every 1ms",
+		"@every foo",
+	} {
+		_, err := ParseSchedule(spec)
+		assert.NotNil(t, err, spec)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	from := time.Date(2018, time.March, 14, 10, 25, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2018, time.March, 14, 10, 26, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2018, time.March, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2018, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2018, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2018, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2018, time.March, 15, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2018, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2018, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2018, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * 1-5", time.Date(2018, time.March, 14, 13, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// day of month or day of week when both are restricted
+		{"0 0 20 * 5", time.Date(2018, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2018, time.March, 14, 10, 27, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		assert.Nil(t, err, tt.spec)
+		assert.Equal(t, tt.next, s.Next(from), tt.spec)
+	}
+}
+
+func TestScheduleNextImpossible(t *testing.T) {
+	s, err := ParseSchedule("0 0 30 2 *")
+	assert.Nil(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
@@ -0,0 +1,439 @@
+// Package scheduler implements a cluster wide scheduler for periodic jobs.
+//
+// Jobs are registered with a cron like schedule by GD2 components and
+// plugins. A leader is elected among the peers in the cluster and only the
+// leader runs the jobs. The enabled state and schedule of every job can be
+// changed cluster wide through the store, and the recent runs of every job
+// are recorded in the store as the job history.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/clientv3/concurrency"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	leaderKey    = "scheduler-leader"
+	tickInterval = time.Second * 15
+	// electionRetryInterval is the interval between failed campaigns, as
+	// while the session of the store is being replaced
+	electionRetryInterval = time.Second * 5
+)
+
+var (
+	// ErrJobNotFound is returned when a job with the given name is not registered
+	ErrJobNotFound = errors.New("scheduled job not found")
+	// ErrJobExists is returned when a job with the same name is already registered
+	ErrJobExists = errors.New("scheduled job already registered")
+)
+
+// JobFunc is the function that is run when a job is triggered
+type JobFunc func(ctx context.Context) error
+
+// Job is a periodic task run by the scheduler
+type Job struct {
+	// Name uniquely identifies the job, eg. "bitrot.scrub"
+	Name        string
+	Description string
+	// Schedule is the default schedule of the job. See ParseSchedule for
+	// the supported expressions.
+	Schedule string
+	// Enabled is true if the job is to be run by default
+	Enabled bool
+	Func    JobFunc
+}
+
+var (
+	jobsMu sync.RWMutex
+	jobs   = make(map[string]*Job)
+)
+
+// Register registers a job with the scheduler. Jobs are expected to be
+// registered before the scheduler is started, for example by plugins in
+// RegisterStepFuncs.
+func Register(j *Job) error {
+	if j.Name == "" || j.Func == nil {
+		return errors.New("scheduled job must have a name and a function")
+	}
+
+	if _, err := ParseSchedule(j.Schedule); err != nil {
+		return err
+	}
+
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+
+	if _, ok := jobs[j.Name]; ok {
+		return ErrJobExists
+	}
+	jobs[j.Name] = j
+
+	return nil
+}
+
+func getJob(name string) (*Job, bool) {
+	jobsMu.RLock()
+	defer jobsMu.RUnlock()
+
+	j, ok := jobs[name]
+	return j, ok
+}
+
+// effectiveState returns the state of the job saved in the store, falling
+// back to the defaults of the job
+func effectiveState(j *Job) (*jobState, error) {
+	s, err := getJobState(j.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		s = &jobState{Enabled: j.Enabled, Schedule: j.Schedule}
+	}
+	if s.Schedule == "" {
+		s.Schedule = j.Schedule
+	}
+
+	return s, nil
+}
+
+// GetJob returns the current state of the named job
+func GetJob(name string) (*api.ScheduledJob, error) {
+	j, ok := getJob(name)
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	s, err := effectiveState(j)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ScheduledJob{
+		Name:        j.Name,
+		Description: j.Description,
+		Schedule:    s.Schedule,
+		Enabled:     s.Enabled,
+	}
+
+	if s.Enabled {
+		if sched, err := ParseSchedule(s.Schedule); err == nil {
+			resp.NextRun = sched.Next(time.Now())
+		}
+	}
+
+	runs, err := GetJobHistory(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) > 0 {
+		resp.LastRun = &runs[0]
+	}
+
+	return resp, nil
+}
+
+// GetJobs returns the current state of all the registered jobs
+func GetJobs() ([]api.ScheduledJob, error) {
+	jobsMu.RLock()
+	names := make([]string, 0, len(jobs))
+	for name := range jobs {
+		names = append(names, name)
+	}
+	jobsMu.RUnlock()
+
+	sort.Strings(names)
+
+	resp := make([]api.ScheduledJob, 0, len(names))
+	for _, name := range names {
+		j, err := GetJob(name)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, *j)
+	}
+
+	return resp, nil
+}
+
+// EnableJob enables the named job cluster wide, optionally overriding its
+// schedule
+func EnableJob(name, schedule string) error {
+	j, ok := getJob(name)
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	s, err := effectiveState(j)
+	if err != nil {
+		return err
+	}
+
+	if schedule != "" {
+		if _, err := ParseSchedule(schedule); err != nil {
+			return err
+		}
+		s.Schedule = schedule
+	}
+	s.Enabled = true
+
+	return setJobState(name, s)
+}
+
+// DisableJob disables the named job cluster wide
+func DisableJob(name string) error {
+	j, ok := getJob(name)
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	s, err := effectiveState(j)
+	if err != nil {
+		return err
+	}
+	s.Enabled = false
+
+	return setJobState(name, s)
+}
+
+// scheduler runs the registered jobs when this peer is the leader
+type scheduler struct {
+	sync.Mutex
+	isLeader bool
+	election *concurrency.Election
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopOnce sync.Once
+
+	// next activation time and the schedule it was computed from for
+	// every job
+	next     map[string]time.Time
+	schedule map[string]string
+	running  map[string]bool
+}
+
+var sched *scheduler
+
+// Start starts the scheduler. The peer contests the scheduler leader
+// election and starts running jobs once elected.
+func Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	sched = &scheduler{
+		ctx:      ctx,
+		cancel:   cancel,
+		next:     make(map[string]time.Time),
+		schedule: make(map[string]string),
+		running:  make(map[string]bool),
+	}
+
+	go sched.elect()
+	go sched.run()
+}
+
+// Stop stops the scheduler, resigning leadership if held
+func Stop() {
+	if sched == nil {
+		return
+	}
+
+	sched.stopOnce.Do(func() {
+		sched.cancel()
+		sched.Lock()
+		election := sched.election
+		sched.Unlock()
+		if election != nil {
+			election.Resign(context.Background())
+		}
+	})
+}
+
+// elect contests the scheduler leader election, and campaigns again whenever
+// the leadership is lost
+func (s *scheduler) elect() {
+	for s.ctx.Err() == nil {
+		// The store replaces its session once it has expired
+		session := store.Store.Session
+		election := concurrency.NewElection(session, leaderKey)
+		s.Lock()
+		s.election = election
+		s.Unlock()
+
+		if err := election.Campaign(s.ctx, gdctx.MyUUID.String()); err != nil {
+			if s.ctx.Err() != nil {
+				return
+			}
+			log.WithError(err).Error("failed in campaign for scheduler leader election")
+			select {
+			case <-s.ctx.Done():
+			case <-time.After(electionRetryInterval):
+			}
+			continue
+		}
+
+		log.Info("node got elected as scheduler leader")
+		s.Lock()
+		s.isLeader = true
+		// Activations are computed afresh, so jobs run by the previous
+		// leader are not run again
+		s.next = make(map[string]time.Time)
+		s.schedule = make(map[string]string)
+		s.Unlock()
+
+		s.watchLeadership(election, session)
+
+		s.Lock()
+		s.isLeader = false
+		s.Unlock()
+		if s.ctx.Err() == nil {
+			log.Warn("node lost scheduler leadership, campaigning again")
+		}
+	}
+}
+
+// watchLeadership returns once this peer is no longer the leader of the
+// election, as its session has expired or its leader key has been removed,
+// or the scheduler is stopped
+func (s *scheduler) watchLeadership(election *concurrency.Election, session *concurrency.Session) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	// The leader key is watched from its creation, so that it is seen being
+	// removed even before the watch is set up
+	wch := session.Client().Watch(ctx, election.Key(), clientv3.WithRev(election.Rev()+1))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-session.Done():
+			return
+		case wresp, ok := <-wch:
+			if !ok || wresp.Err() != nil {
+				return
+			}
+			for _, ev := range wresp.Events {
+				if ev.Type == mvccpb.DELETE {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *scheduler) run() {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Lock()
+			isLeader := s.isLeader
+			s.Unlock()
+
+			if isLeader {
+				s.tick(now)
+			}
+		}
+	}
+}
+
+// tick runs all the enabled jobs whose activation time has passed
+func (s *scheduler) tick(now time.Time) {
+	jobsMu.RLock()
+	all := make([]*Job, 0, len(jobs))
+	for _, j := range jobs {
+		all = append(all, j)
+	}
+	jobsMu.RUnlock()
+
+	for _, j := range all {
+		state, err := effectiveState(j)
+		if err != nil {
+			log.WithError(err).WithField("job", j.Name).Error("failed to get scheduled job state")
+			continue
+		}
+
+		s.Lock()
+		if !state.Enabled {
+			delete(s.next, j.Name)
+			s.Unlock()
+			continue
+		}
+
+		// (re)compute the next activation when the job is seen for the
+		// first time or its schedule was changed
+		next, ok := s.next[j.Name]
+		if !ok || s.schedule[j.Name] != state.Schedule {
+			sch, err := ParseSchedule(state.Schedule)
+			if err != nil {
+				s.Unlock()
+				log.WithError(err).WithField("job", j.Name).Error("invalid schedule for job")
+				continue
+			}
+			s.next[j.Name] = sch.Next(now)
+			s.schedule[j.Name] = state.Schedule
+			s.Unlock()
+			continue
+		}
+
+		if next.IsZero() || now.Before(next) || s.running[j.Name] {
+			s.Unlock()
+			continue
+		}
+
+		sch, _ := ParseSchedule(state.Schedule)
+		s.next[j.Name] = sch.Next(now)
+		s.running[j.Name] = true
+		s.Unlock()
+
+		go s.runJob(j)
+	}
+}
+
+func (s *scheduler) runJob(j *Job) {
+	logger := log.WithField("job", j.Name)
+	logger.Debug("running scheduled job")
+
+	run := &api.ScheduledJobRun{
+		StartTime: time.Now(),
+		PeerID:    gdctx.MyUUID.String(),
+	}
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("job panicked: %v", r)
+			}
+		}()
+		return j.Func(s.ctx)
+	}()
+
+	run.EndTime = time.Now()
+	if err != nil {
+		run.Error = err.Error()
+		logger.WithError(err).Error("scheduled job failed")
+	}
+
+	if err := addJobRun(j.Name, run); err != nil {
+		logger.WithError(err).Warn("failed to record scheduled job history")
+	}
+
+	s.Lock()
+	delete(s.running, j.Name)
+	s.Unlock()
+}
@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+const (
+	jobsPrefix       = "scheduled-jobs/"
+	jobHistoryPrefix = "scheduled-jobs-history/"
+	// maxJobHistory is the number of runs retained in the history of a job
+	maxJobHistory = 20
+)
+
+// jobState is the cluster wide state of a job saved in the store. It
+// overrides the defaults the job was registered with.
+type jobState struct {
+	Enabled  bool   `json:"enabled"`
+	Schedule string `json:"schedule"`
+}
+
+func getJobState(name string) (*jobState, error) {
+	resp, err := store.Get(context.TODO(), jobsPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, nil
+	}
+
+	var s jobState
+	if err := json.Unmarshal(resp.Kvs[0].Value, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func setJobState(name string, s *jobState) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), jobsPrefix+name, string(b))
+	return err
+}
+
+func historyKey(name string, run *api.ScheduledJobRun) string {
+	// zero padded so that keys sort in order of time
+	return fmt.Sprintf("%s%s/%020d", jobHistoryPrefix, name, run.StartTime.UnixNano())
+}
+
+// addJobRun saves a run of the job, trimming the history of the job to
+// maxJobHistory entries
+func addJobRun(name string, run *api.ScheduledJobRun) error {
+	b, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	if _, err := store.Put(context.TODO(), historyKey(name, run), string(b)); err != nil {
+		return err
+	}
+
+	resp, err := store.Get(context.TODO(), jobHistoryPrefix+name+"/",
+		clientv3.WithPrefix(), clientv3.WithKeysOnly(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(resp.Kvs)-maxJobHistory; i++ {
+		if _, err := store.Delete(context.TODO(), string(resp.Kvs[i].Key)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetJobHistory returns the recent runs of the job, the latest one first
+func GetJobHistory(name string) ([]api.ScheduledJobRun, error) {
+	resp, err := store.Get(context.TODO(), jobHistoryPrefix+name+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]api.ScheduledJobRun, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var run api.ScheduledJobRun
+		if err := json.Unmarshal(kv.Value, &run); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartTime.After(runs[j].StartTime)
+	})
+
+	return runs, nil
+}
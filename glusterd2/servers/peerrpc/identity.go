@@ -0,0 +1,95 @@
+package peerrpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// The IDs of the requesting peer and its cluster are sent as metadata of
+// every request
+const (
+	clusterIDMetadataKey = "gd2-cluster-id"
+	peerIDMetadataKey    = "gd2-peer-id"
+)
+
+var (
+	crossClusterMu      sync.RWMutex
+	crossClusterMethods = make(map[string]bool)
+)
+
+// AllowCrossCluster allows the method, given by its full gRPC name of the
+// form /<package>.<service>/<method>, to be called by peers of other
+// clusters. It is meant for requests like the one asking a peer to join
+// the cluster of the requester.
+func AllowCrossCluster(fullMethod string) {
+	crossClusterMu.Lock()
+	defer crossClusterMu.Unlock()
+	crossClusterMethods[fullMethod] = true
+}
+
+func isCrossClusterAllowed(fullMethod string) bool {
+	crossClusterMu.RLock()
+	defer crossClusterMu.RUnlock()
+	return crossClusterMethods[fullMethod]
+}
+
+// WithIdentity returns the dial option making the connection send the IDs of
+// this peer and its cluster with every request, which peers use to reject
+// requests from other clusters
+func WithIdentity() grpc.DialOption {
+	return grpc.WithUnaryInterceptor(sendIdentity)
+}
+
+func sendIdentity(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx = metadata.AppendToOutgoingContext(ctx,
+		clusterIDMetadataKey, gdctx.MyClusterID.String(),
+		peerIDMetadataKey, gdctx.MyUUID.String())
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// checkIdentity rejects requests from peers which don't belong to the
+// cluster of this peer, so that a peer of another cluster, or a peer which
+// has left the cluster, can't run steps on this peer
+func checkIdentity(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	if isCrossClusterAllowed(info.FullMethod) {
+		return handler(ctx, req)
+	}
+
+	var clusterID, peerID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md[clusterIDMetadataKey]; len(v) > 0 {
+			clusterID = v[0]
+		}
+		if v := md[peerIDMetadataKey]; len(v) > 0 {
+			peerID = v[0]
+		}
+	}
+
+	if clusterID != gdctx.MyClusterID.String() {
+		fields := log.Fields{
+			"method":        info.FullMethod,
+			"remotepeer":    peerID,
+			"remotecluster": clusterID,
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			fields["remote"] = p.Addr.String()
+		}
+		log.WithFields(fields).Warn("rejected request from peer of another cluster")
+		return nil, status.Errorf(codes.PermissionDenied,
+			"request from peer %s of cluster %s rejected by peer %s of cluster %s",
+			peerID, clusterID, gdctx.MyUUID, gdctx.MyClusterID)
+	}
+
+	return handler(ctx, req)
+}
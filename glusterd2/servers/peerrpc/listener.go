@@ -7,7 +7,6 @@ import (
 	log "github.com/sirupsen/logrus"
 	config "github.com/spf13/viper"
 	"go.opencensus.io/plugin/ocgrpc"
-	"go.opencensus.io/trace"
 	"google.golang.org/grpc"
 )
 
@@ -19,11 +18,14 @@ type Server struct {
 
 // New returns a new peerrpc.Server with registered gRPC services
 func New() *Server {
+	opts := []grpc.ServerOption{
+		// Requests of traces sampled by the requesting peer are
+		// traced, others are sampled as configured
+		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
+		grpc.UnaryInterceptor(checkIdentity),
+	}
 	s := &Server{
-		grpc.NewServer(grpc.StatsHandler(&ocgrpc.ServerHandler{
-			StartOptions: trace.StartOptions{
-				Sampler: trace.AlwaysSample(),
-			}})),
+		grpc.NewServer(append(opts, serverOptions()...)...),
 	}
 	registerServices(s.server)
 
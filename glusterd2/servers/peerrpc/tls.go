@@ -0,0 +1,73 @@
+package peerrpc
+
+import (
+	"sync"
+
+	"github.com/gluster/glusterd2/pkg/tlsreload"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	peerCertsOnce sync.Once
+	peerCerts     *tlsreload.Certs
+	peerCertsErr  error
+)
+
+// loadPeerCerts loads the certificates of the peer RPC once, to be reloaded
+// on SIGHUP. It returns nil if TLS isn't configured for the peer RPC.
+func loadPeerCerts() (*tlsreload.Certs, error) {
+	peerCertsOnce.Do(func() {
+		certfile := config.GetString("peer-cert-file")
+		keyfile := config.GetString("peer-key-file")
+		if certfile == "" || keyfile == "" {
+			return
+		}
+		peerCerts, peerCertsErr = tlsreload.Load(tlsreload.Files{
+			Cert: certfile,
+			Key:  keyfile,
+			CA:   config.GetString("peer-ca-file"),
+		})
+	})
+	return peerCerts, peerCertsErr
+}
+
+// serverOptions returns the server options serving the peer RPC over TLS, if
+// configured. Client certificates of peers are required if
+// peer-verify-client-cert is set.
+func serverOptions() []grpc.ServerOption {
+	certs, err := loadPeerCerts()
+	if err != nil {
+		// TODO: Don't use Fatal(), bubble up error till main()
+		log.WithError(err).WithFields(log.Fields{
+			"peer-cert-file": config.GetString("peer-cert-file"),
+			"peer-key-file":  config.GetString("peer-key-file"),
+			"peer-ca-file":   config.GetString("peer-ca-file"),
+		}).Fatal("Failed to load peer SSL/TLS certificates")
+	}
+	if certs == nil {
+		return nil
+	}
+
+	tlsConfig := certs.ServerConfig(config.GetBool("peer-verify-client-cert"))
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}
+}
+
+// WithTransportSecurity returns the dial option connecting to the peer RPC of
+// other peers over TLS if configured, presenting the certificate of this peer
+// as the client certificate, or without TLS otherwise. An error is returned
+// if TLS is configured but the certificates fail to load, so that peers are
+// never dialed without TLS in that case.
+func WithTransportSecurity() (grpc.DialOption, error) {
+	certs, err := loadPeerCerts()
+	if err != nil {
+		return nil, err
+	}
+	if certs == nil {
+		return grpc.WithInsecure(), nil
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(certs.ClientConfig())), nil
+}
@@ -3,7 +3,6 @@ package rest
 
 import (
 	"context"
-	"crypto/rand"
 	"crypto/tls"
 	"net"
 	"net/http"
@@ -11,9 +10,11 @@ import (
 
 	"github.com/gluster/glusterd2/glusterd2/middleware"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/startup"
 	gdutils "github.com/gluster/glusterd2/glusterd2/utils"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/tlsmatcher"
+	"github.com/gluster/glusterd2/pkg/tlsreload"
 
 	"github.com/cockroachdb/cmux"
 	"github.com/gorilla/mux"
@@ -37,20 +38,20 @@ type GDRest struct {
 	stopCh   chan struct{}
 }
 
-func tlsListener(l net.Listener, certfile, keyfile string) (net.Listener, error) {
-
-	certificate, err := tls.LoadX509KeyPair(certfile, keyfile)
+// tlsListener returns a listener serving TLS with the certificate and key,
+// which are reloaded on SIGHUP. Client certificates are verified against the
+// client CA, if given, to authenticate the clients presenting them.
+func tlsListener(l net.Listener, certfile, keyfile, cafile string) (net.Listener, error) {
+	certs, err := tlsreload.Load(tlsreload.Files{
+		Cert: certfile,
+		Key:  keyfile,
+		CA:   cafile,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	config := &tls.Config{
-		MinVersion:   tls.VersionTLS12, // force TLS 1.2
-		Certificates: []tls.Certificate{certificate},
-		Rand:         rand.Reader,
-	}
-
-	return tls.NewListener(l, config), nil
+	return tls.NewListener(l, certs.ServerConfig(false)), nil
 }
 
 // NewMuxed returns a GDRest object which listens on a CMux multiplexed connection
@@ -68,14 +69,16 @@ func NewMuxed(m cmux.CMux) *GDRest {
 
 	certfile := config.GetString("cert-file")
 	keyfile := config.GetString("key-file")
+	cafile := config.GetString("client-ca-file")
 
 	if certfile != "" && keyfile != "" {
-		if l, err := tlsListener(m.Match(tlsmatcher.TLS12, tlsmatcher.TLS11, tlsmatcher.TLS10), certfile, keyfile); err != nil {
+		if l, err := tlsListener(m.Match(tlsmatcher.TLS12, tlsmatcher.TLS11, tlsmatcher.TLS10), certfile, keyfile, cafile); err != nil {
 			// TODO: Don't use Fatal(), bubble up error till main()
 			// NOTE: Methods of suture.Service interface do not return error
 			log.WithError(err).WithFields(log.Fields{
-				"cert-file": certfile,
-				"key-file":  keyfile,
+				"cert-file":      certfile,
+				"key-file":       keyfile,
+				"client-ca-file": cafile,
 			}).Fatal("Failed to create SSL/TLS listener")
 		} else {
 			rest.listener = l
@@ -86,6 +89,17 @@ func NewMuxed(m cmux.CMux) *GDRest {
 
 	rest.registerRoutes()
 
+	// Route variables are available to middlewares used by the router
+	rest.Routes.Use(middleware.LogContext)
+	// Spans of requests are tagged with the volume set by LogContext
+	rest.Routes.Use(middleware.Trace)
+	// Forwarding needs the matched route to find the volume of the request
+	rest.Routes.Use(middleware.Forward)
+	// Request durations are recorded by the name of the matched route
+	rest.Routes.Use(middleware.Metrics)
+	// Requests are audited by the peer serving them, after forwarding
+	rest.Routes.Use(middleware.Audit)
+
 	//Enable go profiling
 	profiling := config.GetBool("profiling")
 	if profiling {
@@ -101,6 +115,8 @@ func NewMuxed(m cmux.CMux) *GDRest {
 			middleware.ReqIDGenerator,
 			middleware.LogRequest,
 			middleware.Auth,
+			middleware.RateLimit,
+			middleware.ReadinessGate,
 		).Then(rest.Routes),
 	}
 
@@ -157,6 +173,30 @@ func (r *GDRest) listEndpointsHandler() http.HandlerFunc {
 	})
 }
 
+// readinessHandler reports the readiness of the GD2 subsystems. Service
+// Unavailable is returned till all subsystems are ready.
+func (r *GDRest) readinessHandler() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		resp := api.ReadinessResp{Ready: startup.AllReady()}
+		for _, s := range startup.Statuses() {
+			resp.Subsystems = append(resp.Subsystems, api.SubsystemStatus{
+				Name:       s.Name,
+				Ready:      s.Ready,
+				Attempts:   s.Attempts,
+				LastError:  s.LastError,
+				ReadySince: s.ReadySince,
+			})
+		}
+
+		status := http.StatusOK
+		if !resp.Ready {
+			status = http.StatusServiceUnavailable
+		}
+		restutils.SendHTTPResponse(ctx, w, status, resp)
+	})
+}
+
 //Ping URL for glusterd2
 func (r *GDRest) Ping() http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -17,6 +17,73 @@ type Route struct {
 	RequestType  string
 	ResponseType string // Success
 	HandlerFunc  http.HandlerFunc
+	// RequestBody, if set, is a nil pointer of the type of the JSON
+	// request body, like (*api.VolCreateReq)(nil). The REST server
+	// decodes and validates the body of every request before calling
+	// HandlerFunc, which gets the body with utils.RequestBody. The `valid`
+	// tags of the fields of the type are used for validation. RequestType
+	// defaults to the name of the type.
+	RequestBody interface{}
+	// Permission is the permission the user of a request needs to call
+	// the route, when REST authentication is enabled. It defaults to
+	// PermRead for GET and HEAD requests and to PermAdmin for the others.
+	Permission Permission
+	// Heavy marks heavyweight operations, like volume create, snapshot and
+	// rebalance, of which at most max-heavy-ops are served at once in
+	// the cluster
+	Heavy bool
+	// AllowReadOnly marks routes which are served while the cluster is in
+	// read-only mode although their method may modify the cluster, like
+	// dry runs
+	AllowReadOnly bool
+}
+
+// Permission is a permission granted to the users by their role. Each
+// permission includes those before it.
+type Permission int
+
+// Permissions required by the routes
+const (
+	// PermDefault is resolved from the method of the route by
+	// RequiredPermission
+	PermDefault Permission = iota
+	// PermNone allows all requests, including unauthenticated requests
+	PermNone
+	// PermRead allows reading the state of the cluster
+	PermRead
+	// PermOperate allows operations on existing volumes and snapshots,
+	// like starting and stopping them, healing and rebalancing
+	PermOperate
+	// PermAdmin allows everything, including creating and deleting
+	// volumes, changing the cluster and managing users
+	PermAdmin
+)
+
+// RequiredPermission returns the permission needed to call the route
+func (r *Route) RequiredPermission() Permission {
+	if r.Permission != PermDefault {
+		return r.Permission
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		return PermRead
+	default:
+		return PermAdmin
+	}
+}
+
+// BlockedInReadOnlyMode returns true if requests to the route are rejected
+// while the cluster is in read-only mode
+func (r *Route) BlockedInReadOnlyMode() bool {
+	if r.AllowReadOnly {
+		return false
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
 }
 
 // Routes is a table of many Route's
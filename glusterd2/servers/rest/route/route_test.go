@@ -0,0 +1,23 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockedInReadOnlyMode(t *testing.T) {
+	for _, tc := range []struct {
+		route   Route
+		blocked bool
+	}{
+		{Route{Name: "VolumeList", Method: "GET"}, false},
+		{Route{Name: "VolumeStatus", Method: "HEAD"}, false},
+		{Route{Name: "VolumeCreate", Method: "POST"}, true},
+		{Route{Name: "VolumeACLSet", Method: "PUT"}, true},
+		{Route{Name: "DeletePeer", Method: "DELETE"}, true},
+		{Route{Name: "ClusterOptionsImpact", Method: "POST", AllowReadOnly: true}, false},
+	} {
+		assert.Equal(t, tc.blocked, tc.route.BlockedInReadOnlyMode(), tc.route.Name)
+	}
+}
@@ -5,12 +5,16 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/gluster/glusterd2/glusterd2/artifact"
 	"github.com/gluster/glusterd2/glusterd2/commands"
+	"github.com/gluster/glusterd2/glusterd2/middleware"
 	"github.com/gluster/glusterd2/glusterd2/plugin"
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/utils"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 	config "github.com/spf13/viper"
 )
@@ -22,6 +26,20 @@ var AllRoutes route.Routes
 func (r *GDRest) setRoutes(routes route.Routes) {
 	var urlPattern string
 	for _, route := range routes {
+		handler := route.HandlerFunc
+		if route.Heavy {
+			handler = middleware.LimitHeavyOps(handler)
+		}
+		if route.RequestBody != nil {
+			handler = restutils.DecodeRequest(route.RequestBody, handler)
+			if route.RequestType == "" {
+				route.RequestType = utils.GetTypeString(route.RequestBody)
+			}
+		}
+		if route.BlockedInReadOnlyMode() {
+			handler = middleware.ReadOnlyGate(handler)
+		}
+
 		// Set routes in mux.Routes
 		if route.Version == 0 {
 			urlPattern = route.Pattern
@@ -37,7 +55,7 @@ func (r *GDRest) setRoutes(routes route.Routes) {
 			Methods(route.Method).
 			Path(urlPattern).
 			Name(route.Name).
-			Handler(route.HandlerFunc)
+			Handler(middleware.Authorize(route.RequiredPermission(), handler))
 
 		// Set our global copy of all routes
 		AllRoutes = append(AllRoutes, route)
@@ -62,6 +80,9 @@ func (r *GDRest) registerRoutes() {
 		p.RegisterStepFuncs()
 	}
 
+	// Step functions used by commands and plugins to distribute files
+	artifact.RegisterStepFuncs()
+
 	// Expose /statedump and /endpoints handlers
 	var moreRoutes route.Routes
 
@@ -70,6 +91,7 @@ func (r *GDRest) registerRoutes() {
 			Name:        "Statedump",
 			Method:      "GET",
 			Pattern:     "/statedump",
+			Permission:  route.PermAdmin,
 			HandlerFunc: expvar.Handler().(http.HandlerFunc)})
 	}
 
@@ -78,12 +100,29 @@ func (r *GDRest) registerRoutes() {
 		Method:       "GET",
 		Pattern:      "/endpoints",
 		ResponseType: utils.GetTypeString((*api.ListEndpointsResp)(nil)),
+		Permission:   route.PermNone,
 		HandlerFunc:  r.listEndpointsHandler()})
 
 	moreRoutes = append(moreRoutes, route.Route{
 		Name:        "Glusterd2 service status",
 		Method:      "GET",
 		Pattern:     "/ping",
+		Permission:  route.PermNone,
 		HandlerFunc: r.Ping()})
+
+	moreRoutes = append(moreRoutes, route.Route{
+		Name:         "Glusterd2 readiness",
+		Method:       "GET",
+		Pattern:      "/ready",
+		ResponseType: utils.GetTypeString((*api.ReadinessResp)(nil)),
+		Permission:   route.PermNone,
+		HandlerFunc:  r.readinessHandler()})
+
+	moreRoutes = append(moreRoutes, route.Route{
+		Name:        "Metrics",
+		Method:      "GET",
+		Pattern:     "/metrics",
+		Permission:  route.PermNone,
+		HandlerFunc: promhttp.Handler().ServeHTTP})
 	r.setRoutes(moreRoutes)
 }
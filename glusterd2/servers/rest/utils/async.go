@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gluster/glusterd2/glusterd2/asyncjob"
+)
+
+// AsyncRequested returns true if the client asked for the operation to be run
+// as an async job, with the async query parameter
+func AsyncRequested(r *http.Request) bool {
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	return async
+}
+
+type heldKeyType int
+
+const heldKey heldKeyType = iota
+
+// held is something held by a request, like a heavyweight operation slot,
+// until the request has been served, or if it is run as an async job, until
+// the job finishes
+type held struct {
+	mu        sync.Mutex
+	release   func()
+	handedOff bool
+}
+
+// HoldUntilDone returns ctx carrying release, and the function to be called
+// once the request has been served. That function calls release, unless the
+// request has been run as an async job by SendAsyncJob, in which case release
+// is called when the job finishes.
+func HoldUntilDone(ctx context.Context, release func()) (context.Context, func()) {
+	h := &held{release: release}
+	done := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if !h.handedOff {
+			h.release()
+		}
+	}
+	return context.WithValue(ctx, heldKey, h), done
+}
+
+// TakeHeld hands over what is held by the request in ctx, to be released by
+// the caller with the returned function, instead of when the request has
+// been served. It returns a no-op function if nothing is held.
+func TakeHeld(ctx context.Context) func() {
+	h, ok := ctx.Value(heldKey).(*held)
+	if !ok {
+		return func() {}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handedOff = true
+	return h.release
+}
+
+// SendAsyncJob runs f in the background as an async job, and answers the
+// request with the job and 202 Accepted. Whatever the request holds, as given
+// to HoldUntilDone, is released when the job finishes. The errors returned by f are
+// recorded in the job with the status given by errStatus, or if nil, the
+// status they are mapped to by ErrToStatusCode.
+func SendAsyncJob(ctx context.Context, w http.ResponseWriter, op, target string, f asyncjob.Func, errStatus func(error) int) {
+	if errStatus == nil {
+		errStatus = func(err error) int {
+			status, _ := ErrToStatusCode(err)
+			return status
+		}
+	}
+
+	// Whatever the request holds is held by the job until it finishes
+	release := TakeHeld(ctx)
+	job, err := asyncjob.Start(ctx, op, target, func(ctx context.Context) (interface{}, error) {
+		defer release()
+		return f(ctx)
+	}, errStatus)
+	if err != nil {
+		release()
+		SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v1/jobs/"+job.ID.String())
+	SendHTTPResponse(ctx, w, http.StatusAccepted, job)
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/asaskevich/govalidator"
+)
+
+type ctxKeyType int
+
+const reqBodyKey ctxKeyType = iota
+
+// RequestValidator is implemented by request types which have to be
+// validated beyond what can be expressed by the `valid` tags of their fields
+type RequestValidator interface {
+	Validate() error
+}
+
+// ValidateRequest validates the decoded request v against the govalidator
+// `valid` tags of its fields, and with its Validate method if it implements
+// RequestValidator
+func ValidateRequest(v interface{}) error {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		if _, err := govalidator.ValidateStruct(v); err != nil {
+			return err
+		}
+	}
+
+	if rv, ok := v.(RequestValidator); ok {
+		return rv.Validate()
+	}
+	return nil
+}
+
+// DecodeRequest returns a handler which decodes the JSON body of the request
+// into a new value of the type of body, which is a nil pointer of the type
+// like (*api.VolCreateReq)(nil), and validates it with ValidateRequest before
+// calling next. Malformed and invalid requests are rejected with Bad Request.
+// next gets the decoded value, a pointer of the type of body, with
+// RequestBody.
+func DecodeRequest(body interface{}, next http.HandlerFunc) http.HandlerFunc {
+	t := reflect.TypeOf(body).Elem()
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		v := reflect.New(t).Interface()
+		if err := UnmarshalRequest(r, v); err != nil {
+			SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+			return
+		}
+		if err := ValidateRequest(v); err != nil {
+			SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(ctx, reqBodyKey, v)))
+	}
+}
+
+// RequestBody returns the request body decoded by DecodeRequest, a pointer of
+// the type declared by the route
+func RequestBody(ctx context.Context) interface{} {
+	return ctx.Value(reqBodyKey)
+}
@@ -43,7 +43,7 @@ func SendHTTPResponse(ctx context.Context, w http.ResponseWriter, statusCode int
 
 	if resp != nil {
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			logger := gdctx.GetReqLogger(ctx)
+			logger := gdctx.Logger(ctx)
 			logger.WithError(err).Error("Failed to send the response -", resp)
 		}
 	}
@@ -69,8 +69,14 @@ func SendHTTPError(ctx context.Context, w http.ResponseWriter, statusCode int,
 	} else {
 		errMsg := fmt.Sprint(err)
 		if errMsg != "" && errMsg != "<nil>" || len(errCodes) == 0 {
+			code := api.ErrCodeGeneric
+			if e, ok := err.(error); ok {
+				if _, c := transaction.ErrorStatus(e); c != 0 {
+					code = c
+				}
+			}
 			resp.Errors = append(resp.Errors, api.HTTPError{
-				Code:    int(api.ErrCodeGeneric),
+				Code:    int(code),
 				Message: errMsg})
 		} else {
 			for _, code := range errCodes {
@@ -84,7 +90,7 @@ func SendHTTPError(ctx context.Context, w http.ResponseWriter, statusCode int,
 	w.WriteHeader(statusCode)
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		logger := gdctx.GetReqLogger(ctx)
+		logger := gdctx.Logger(ctx)
 		logger.WithError(err).Error("Failed to send the response -", resp)
 	}
 }
@@ -100,10 +106,69 @@ func ErrToStatusCode(err error) (int, error) {
 		statuscode = http.StatusNotFound
 	case gderrors.ErrSnapNotFound:
 		statuscode = http.StatusNotFound
-	case transaction.ErrLockTimeout:
+	case gderrors.ErrOptionsHistoryNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrAutoExpandPolicyNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrUsageProtectPolicyNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrIOThrottlePolicyNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrBrickWipeJobNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrBrickWipeJobRunning:
+		statuscode = http.StatusConflict
+	case gderrors.ErrRevisionCompacted:
+		statuscode = http.StatusGone
+	case gderrors.ErrSubdirExportNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrSubdirExportExists:
+		statuscode = http.StatusConflict
+	case gderrors.ErrVolNameReserved:
+		statuscode = http.StatusConflict
+	case gderrors.ErrVolinfoConflict:
+		statuscode = http.StatusConflict
+	case gderrors.ErrDeletedVolNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrBrickNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrVolProfileNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrVolProfileExists:
 		statuscode = http.StatusConflict
+	case gderrors.ErrQuotaLimitNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrVolRevisionNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrFutureRevision:
+		statuscode = http.StatusBadRequest
+	case gderrors.ErrBitrotObjectNotCorrupted:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrBitrotNoGoodCopy:
+		statuscode = http.StatusConflict
+	case gderrors.ErrIOOpConflict:
+		statuscode = http.StatusConflict
+	case gderrors.ErrIOOpNotQueued:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrPeerEvacuationNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrPeerEvacuationExists:
+		statuscode = http.StatusConflict
+	case gderrors.ErrPeerEvacuationNotRunning:
+		statuscode = http.StatusConflict
+	case gderrors.ErrPeerEvacuationNotResumable:
+		statuscode = http.StatusConflict
+	case gderrors.ErrTxnNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrAsyncJobNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrUserNotFound:
+		statuscode = http.StatusNotFound
 	default:
-		statuscode = http.StatusInternalServerError
+		// Errors of transactions and their steps
+		if statuscode, _ = transaction.ErrorStatus(err); statuscode == 0 {
+			statuscode = http.StatusInternalServerError
+		}
 	}
 	return statuscode, err
 }
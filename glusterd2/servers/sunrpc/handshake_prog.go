@@ -10,6 +10,7 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
 	"github.com/gluster/glusterd2/glusterd2/snapshot"
 	"github.com/gluster/glusterd2/glusterd2/volgen"
@@ -114,13 +115,15 @@ const (
 // specified by the client
 func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) error {
 	var (
-		err      error
-		addrs    []string
-		respDict map[string]string
-		volinfo  *volume.Volinfo
+		err       error
+		addrs     []string
+		reqDict   map[string]string
+		respDict  = make(map[string]string)
+		volinfo   *volume.Volinfo
+		opVersion uint32
 	)
 
-	_, err = dict.Unserialize(args.Xdata)
+	reqDict, err = dict.Unserialize(args.Xdata)
 	if err != nil {
 		log.WithError(err).Error("ServerGetspec(): dict.Unserialize() failed")
 	}
@@ -185,9 +188,36 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 		}
 	}
 
+	// Clients which can't use the volfile are sent a clear error instead
+	// of a graph they would fail to load. The op-version required is only
+	// known for the volfiles generated from volinfo, which are the volfiles
+	// of clients mounting volumes.
+	if volinfo != nil {
+		opVersion = clientOpVersion(volinfo)
+	}
+	if cerr := checkClientCaps(parseClientCaps(reqDict), reply.Spec, opVersion, uint32(gdctx.OpVersion)); cerr != nil {
+		log.WithError(cerr).WithFields(log.Fields{
+			"client":     p.GetConn().RemoteAddr().String(),
+			"volfile-id": args.Key,
+		}).Error("client can't use volfile")
+		reply.Spec = ""
+		reply.OpRet = -1
+		reply.OpErrno = int(syscall.ENOTSUP)
+		respDict[getspecErrorKey] = cerr.Error()
+		if reply.Xdata, err = dict.Serialize(respDict); err != nil {
+			log.WithError(err).Error("failed to serialize dict")
+		}
+		return nil
+	}
+
 	reply.OpRet = len(reply.Spec)
 	reply.OpErrno = 0
 
+	// Clients aware of the checksum verify the volfile against it
+	respDict[getspecChecksumKey] = volfileChecksum(reply.Spec)
+	respDict[getspecChecksumTypeKey] = "sha256"
+	respDict[getspecSizeKey] = strconv.Itoa(len(reply.Spec))
+
 	if (args.Flags & gfGetspecFlagServersList) != 0 {
 
 		if volinfo == nil {
@@ -198,7 +228,7 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 				// a GETSPEC request from client vs request from daemon
 				// such as self-heal as self-heal is also a client.
 				err = nil
-				goto Out
+				goto Serialize
 			}
 		}
 
@@ -218,21 +248,26 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 		}
 
 		if len(addrs) > 0 {
-			respDict = make(map[string]string)
 			respDict["servers-list"] = strings.Join(addrs, " ")
-			reply.Xdata, err = dict.Serialize(respDict)
-			if err != nil {
-				log.WithError(err).Error("failed to serialize dict")
-			}
 		}
 	}
 
+Serialize:
+	reply.Xdata, err = dict.Serialize(respDict)
+	if err != nil {
+		log.WithError(err).Error("failed to serialize dict")
+	}
+
 Out:
 	if err != nil {
 		reply.OpRet = -1
 		reply.OpErrno = 0
 	}
 
+	if reply.OpRet > 0 {
+		volfileFetched(p.GetConn(), volfileID)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,120 @@
+package sunrpc
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/sunrpc"
+)
+
+var (
+	// ErrClientNotConnected is returned when no connected client has
+	// fetched the volfile to be reconfigured
+	ErrClientNotConnected = errors.New("no client connected for volfile")
+	// ErrReconfigureTimeout is returned when the client does not fetch
+	// the new volfile in time
+	ErrReconfigureTimeout = errors.New("timed out waiting for client to fetch volfile")
+)
+
+// volfileClients tracks the volfile fetched by each connected client and the
+// reconfigure requests waiting for a client to fetch a volfile
+var volfileClients = struct {
+	sync.Mutex
+	ids     map[net.Conn]string
+	waiters map[string][]chan struct{}
+}{
+	ids:     make(map[net.Conn]string),
+	waiters: make(map[string][]chan struct{}),
+}
+
+func normalizeVolfileID(volfileID string) string {
+	return strings.TrimPrefix(volfileID, "/")
+}
+
+// volfileFetched records that a client fetched a volfile and wakes up the
+// reconfigure requests waiting for it
+func volfileFetched(conn net.Conn, volfileID string) {
+	volfileID = normalizeVolfileID(volfileID)
+
+	volfileClients.Lock()
+	defer volfileClients.Unlock()
+
+	volfileClients.ids[conn] = volfileID
+	for _, ch := range volfileClients.waiters[volfileID] {
+		close(ch)
+	}
+	delete(volfileClients.waiters, volfileID)
+}
+
+// forgetClient removes a disconnected client
+func forgetClient(conn net.Conn) {
+	volfileClients.Lock()
+	delete(volfileClients.ids, conn)
+	volfileClients.Unlock()
+}
+
+// ReconfigureClient notifies the clients which fetched the given volfile,
+// usually a brick process, to fetch it again and apply the changed options
+// without restarting. It returns once a client has fetched the new volfile.
+func ReconfigureClient(volfileID string, timeout time.Duration) error {
+	volfileID = normalizeVolfileID(volfileID)
+
+	volfileClients.Lock()
+	var conns []net.Conn
+	for conn, id := range volfileClients.ids {
+		if id == volfileID {
+			conns = append(conns, conn)
+		}
+	}
+	if len(conns) == 0 {
+		volfileClients.Unlock()
+		return ErrClientNotConnected
+	}
+	fetched := make(chan struct{})
+	volfileClients.waiters[volfileID] = append(volfileClients.waiters[volfileID], fetched)
+	volfileClients.Unlock()
+
+	p := sunrpc.ProcedureID{
+		ProgramNumber:   glusterCbkProgram,
+		ProgramVersion:  glusterCbkVersion,
+		ProcedureNumber: uint32(gfCbkFetchSpec),
+	}
+
+	var sent bool
+	var err error
+	for _, conn := range conns {
+		if e := callbackClient(conn, p, nil); e != nil {
+			err = e
+			continue
+		}
+		sent = true
+	}
+
+	if sent {
+		select {
+		case <-fetched:
+			return nil
+		case <-time.After(timeout):
+			err = ErrReconfigureTimeout
+		}
+	}
+
+	// Stop waiting
+	volfileClients.Lock()
+	waiters := volfileClients.waiters[volfileID]
+	for i, ch := range waiters {
+		if ch == fetched {
+			volfileClients.waiters[volfileID] = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(volfileClients.waiters[volfileID]) == 0 {
+		delete(volfileClients.waiters, volfileID)
+	}
+	volfileClients.Unlock()
+
+	return err
+}
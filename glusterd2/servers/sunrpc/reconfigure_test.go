@@ -0,0 +1,40 @@
+package sunrpc
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconfigureClient(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+	defer forgetClient(server)
+
+	assert.Equal(t, ErrClientNotConnected, ReconfigureClient("vol1.brick1", time.Second))
+
+	volfileFetched(server, "/vol1.brick1")
+
+	// The client re-fetches the volfile on receiving the callback
+	r, w := io.Pipe()
+	go func() {
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(r, buf); err == nil {
+			volfileFetched(server, "vol1.brick1")
+		}
+		io.Copy(ioutil.Discard, r)
+	}()
+	go io.Copy(w, client)
+
+	assert.Nil(t, ReconfigureClient("vol1.brick1", 5*time.Second))
+	assert.Equal(t, ErrReconfigureTimeout, ReconfigureClient("vol1.brick1", 10*time.Millisecond))
+
+	volfileClients.Lock()
+	assert.Empty(t, volfileClients.waiters)
+	volfileClients.Unlock()
+}
@@ -112,6 +112,7 @@ func (s *SunRPC) pruneConn() {
 		delete(clientsList.c, conn)
 		pmap.ProcessDisconnect(conn)
 		clientsList.Unlock()
+		forgetClient(conn)
 
 		clientCount.Add(-1)
 	}
@@ -0,0 +1,123 @@
+package sunrpc
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+)
+
+// Keys of the dicts exchanged with clients fetching volfiles. The op-version
+// keys are sent by all glusterfs clients. The other keys are only used by
+// clients aware of them, and are ignored by the others.
+const (
+	getspecMinOpVersionKey = "min-op-version"
+	getspecMaxOpVersionKey = "max-op-version"
+	// getspecXlatorsKey carries the space separated types of the xlators
+	// supported by the client, like cluster/replicate
+	getspecXlatorsKey = "supported-xlators"
+
+	getspecChecksumKey     = "volfile-checksum"
+	getspecChecksumTypeKey = "volfile-checksum-type"
+	getspecSizeKey         = "volfile-size"
+	getspecErrorKey        = "error"
+)
+
+// clientCaps are the capabilities of a client fetching a volfile, as sent by
+// it in the request dict. Zero values mean the client didn't send them.
+type clientCaps struct {
+	minOpVersion uint32
+	maxOpVersion uint32
+	xlators      []string
+}
+
+func parseClientCaps(reqDict map[string]string) clientCaps {
+	var caps clientCaps
+	if v, err := strconv.ParseUint(reqDict[getspecMinOpVersionKey], 10, 32); err == nil {
+		caps.minOpVersion = uint32(v)
+	}
+	if v, err := strconv.ParseUint(reqDict[getspecMaxOpVersionKey], 10, 32); err == nil {
+		caps.maxOpVersion = uint32(v)
+	}
+	caps.xlators = strings.Fields(reqDict[getspecXlatorsKey])
+	return caps
+}
+
+// volfileChecksum returns the checksum of the volfile, with which clients can
+// detect truncated or corrupt volfiles
+func volfileChecksum(spec string) string {
+	sum := sha256.Sum256([]byte(spec))
+	return hex.EncodeToString(sum[:])
+}
+
+// volfileXlatorTypes returns the sorted types of the xlators in the volfile
+func volfileXlatorTypes(spec string) []string {
+	seen := make(map[string]bool)
+	var types []string
+
+	scanner := bufio.NewScanner(strings.NewReader(spec))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[0] != "type" || seen[fields[1]] {
+			continue
+		}
+		seen[fields[1]] = true
+		types = append(types, fields[1])
+	}
+	sort.Strings(types)
+	return types
+}
+
+// clientOpVersion returns the op-version clients must support to use the
+// volume, the highest op-version of the client options set on it
+func clientOpVersion(volinfo *volume.Volinfo) uint32 {
+	var opVersion uint32
+	for k := range volinfo.Options {
+		opt, err := xlator.FindOption(k)
+		if err != nil || !opt.IsClientOpt() || len(opt.OpVersion) == 0 {
+			continue
+		}
+		if opt.OpVersion[0] > opVersion {
+			opVersion = opt.OpVersion[0]
+		}
+	}
+	return opVersion
+}
+
+// checkClientCaps checks if the client can use the volfile, given the
+// op-version required by the volume and the op-version of the cluster
+func checkClientCaps(caps clientCaps, spec string, opVersion, clusterOpVersion uint32) error {
+	if caps.minOpVersion > clusterOpVersion {
+		return fmt.Errorf("client requires op-version %d, higher than op-version %d of the cluster",
+			caps.minOpVersion, clusterOpVersion)
+	}
+	if caps.maxOpVersion != 0 && caps.maxOpVersion < opVersion {
+		return fmt.Errorf("client op-version %d is lower than op-version %d required by the volume",
+			caps.maxOpVersion, opVersion)
+	}
+
+	if len(caps.xlators) == 0 {
+		return nil
+	}
+
+	supported := make(map[string]bool, len(caps.xlators))
+	for _, xl := range caps.xlators {
+		supported[xl] = true
+	}
+	var unsupported []string
+	for _, xl := range volfileXlatorTypes(spec) {
+		if !supported[xl] {
+			unsupported = append(unsupported, xl)
+		}
+	}
+	if len(unsupported) != 0 {
+		return fmt.Errorf("volfile uses xlators not supported by the client: %s", strings.Join(unsupported, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package sunrpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testVolfile = `volume vol1-client-0
+    type protocol/client
+    option remote-subvolume /bricks/b1
+end-volume
+
+volume vol1-client-1
+    type protocol/client
+    option remote-subvolume /bricks/b2
+end-volume
+
+volume vol1-replicate-0
+    type cluster/replicate
+    subvolumes vol1-client-0 vol1-client-1
+end-volume
+`
+
+func TestVolfileXlatorTypes(t *testing.T) {
+	assert.Equal(t, []string{"cluster/replicate", "protocol/client"}, volfileXlatorTypes(testVolfile))
+	assert.Empty(t, volfileXlatorTypes(""))
+}
+
+func TestVolfileChecksum(t *testing.T) {
+	sum := volfileChecksum(testVolfile)
+	assert.Len(t, sum, 64)
+	assert.Equal(t, sum, volfileChecksum(testVolfile))
+	assert.NotEqual(t, sum, volfileChecksum(testVolfile[:len(testVolfile)-1]))
+}
+
+func TestCheckClientCaps(t *testing.T) {
+	// Clients sending no capabilities are served any volfile
+	assert.Nil(t, checkClientCaps(parseClientCaps(nil), testVolfile, 40100, 50000))
+
+	caps := parseClientCaps(map[string]string{
+		"min-op-version":    "1",
+		"max-op-version":    "40000",
+		"supported-xlators": "protocol/client cluster/replicate",
+	})
+	assert.Equal(t, uint32(1), caps.minOpVersion)
+	assert.Equal(t, uint32(40000), caps.maxOpVersion)
+	assert.Nil(t, checkClientCaps(caps, testVolfile, 31000, 50000))
+
+	// The volume requires a newer client
+	assert.NotNil(t, checkClientCaps(caps, testVolfile, 40100, 50000))
+
+	// The client requires a newer cluster
+	assert.NotNil(t, checkClientCaps(clientCaps{minOpVersion: 60000}, testVolfile, 0, 50000))
+
+	caps.xlators = []string{"protocol/client"}
+	err := checkClientCaps(caps, testVolfile, 0, 50000)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cluster/replicate")
+}
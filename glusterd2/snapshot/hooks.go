@@ -0,0 +1,205 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	gdstore "github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	snapHookPrefix string = "snaphooks/"
+
+	// DefaultHookTimeout is the time a snapshot hook is given to complete,
+	// if the hook doesn't set a timeout
+	DefaultHookTimeout = 30 * time.Second
+	// MaxHookTimeout is the highest timeout a snapshot hook can set. The
+	// volume stays locked while the hooks are run.
+	MaxHookTimeout = 10 * time.Minute
+)
+
+// ErrSnapHookNotFound is returned when a snapshot hook isn't registered
+var ErrSnapHookNotFound = errors.New("snapshot hook not found")
+
+// HooksDir returns the directory holding the snapshot hook scripts
+func HooksDir() string {
+	return path.Join(config.GetString("hooksdir"), "snapshot")
+}
+
+// ValidateHook checks if the snapshot hook can be registered
+func ValidateHook(hook *api.SnapHook) error {
+	if hook.Name == "" {
+		return errors.New("hook name is empty")
+	}
+	if strings.Contains(hook.Name, "/") {
+		return errors.New("hook name can't contain '/'")
+	}
+	if hook.Phase != api.SnapHookPre && hook.Phase != api.SnapHookPost {
+		return fmt.Errorf("hook phase can only be either %s or %s", api.SnapHookPre, api.SnapHookPost)
+	}
+	if (hook.URL == "") == (hook.Script == "") {
+		return errors.New("hook needs either a url or a script")
+	}
+	if hook.URL != "" && !strings.HasPrefix(hook.URL, "http://") && !strings.HasPrefix(hook.URL, "https://") {
+		return errors.New("hook url must be a http or https url")
+	}
+	// Only scripts placed in the snapshot hooks directory can be run
+	if hook.Script != "" && (strings.Contains(hook.Script, "/") || strings.HasPrefix(hook.Script, ".")) {
+		return fmt.Errorf("hook script must be the name of a script in %s", HooksDir())
+	}
+	if hook.Timeout < 0 || time.Duration(hook.Timeout)*time.Second > MaxHookTimeout {
+		return fmt.Errorf("hook timeout must be between 0 and %d seconds", int(MaxHookTimeout.Seconds()))
+	}
+	return nil
+}
+
+// AddOrUpdateHook stores the snapshot hook
+func AddOrUpdateHook(hook *api.SnapHook) error {
+	b, err := json.Marshal(hook)
+	if err != nil {
+		return err
+	}
+	_, err = gdstore.Put(context.TODO(), snapHookPrefix+hook.Name, string(b))
+	return err
+}
+
+// DeleteHook removes the snapshot hook from the store
+func DeleteHook(name string) error {
+	resp, err := gdstore.Delete(context.TODO(), snapHookPrefix+name)
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return ErrSnapHookNotFound
+	}
+	return nil
+}
+
+// GetHooks returns the registered snapshot hooks, sorted by name
+func GetHooks() ([]api.SnapHook, error) {
+	resp, err := gdstore.Get(context.TODO(), snapHookPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := make([]api.SnapHook, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var hook api.SnapHook
+		if err := json.Unmarshal(kv.Value, &hook); err != nil {
+			log.WithError(err).WithField("hook", string(kv.Key)).Error("Failed to unmarshal snapshot hook")
+			continue
+		}
+		hooks = append(hooks, hook)
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].Name < hooks[j].Name })
+	return hooks, nil
+}
+
+// GetVolumeHooks returns the hooks of the phase to be run for the snapshots
+// of the volume, in the order they are to be run
+func GetVolumeHooks(volname, phase string) ([]api.SnapHook, error) {
+	hooks, err := GetHooks()
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []api.SnapHook
+	for _, hook := range hooks {
+		if hook.Phase == phase && (hook.Volume == "" || hook.Volume == volname) {
+			selected = append(selected, hook)
+		}
+	}
+	return selected, nil
+}
+
+// RunHook runs the snapshot hook for the snapshot of the volume, and returns
+// its outcome. Webhooks are sent the phase, volume and snapshot as JSON, and
+// scripts are passed them as arguments.
+func RunHook(hook api.SnapHook, volname, snapname string) api.SnapHookResult {
+	timeout := DefaultHookTimeout
+	if hook.Timeout > 0 {
+		timeout = time.Duration(hook.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	if hook.URL != "" {
+		err = runHookURL(ctx, hook, volname, snapname)
+	} else {
+		err = runHookScript(ctx, hook, volname, snapname)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("hook timed out after %s", timeout)
+	}
+
+	result := api.SnapHookResult{
+		Name:      hook.Name,
+		Phase:     hook.Phase,
+		Mandatory: hook.Mandatory,
+		Success:   err == nil,
+		Duration:  time.Since(start),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func runHookURL(ctx context.Context, hook api.SnapHook, volname, snapname string) error {
+	body, err := json.Marshal(map[string]string{
+		"phase":    hook.Phase,
+		"volume":   volname,
+		"snapshot": snapname,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("hook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func runHookScript(ctx context.Context, hook api.SnapHook, volname, snapname string) error {
+	cmd := exec.CommandContext(ctx, path.Join(HooksDir(), hook.Script),
+		"--phase="+hook.Phase, "--volname="+volname, "--snapname="+snapname)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
 )
 
 //Snapinfo is used to represent a snapshot
@@ -14,4 +15,5 @@ type Snapinfo struct {
 	Description  string
 	OptionChange map[string]string
 	CreatedAt    time.Time
+	HookResults  []api.SnapHookResult
 }
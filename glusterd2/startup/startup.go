@@ -0,0 +1,293 @@
+// Package startup starts the subsystems of GD2 in dependency order.
+//
+// Subsystems are registered along with the subsystems they require. They are
+// started one after the other once all the subsystems they require are ready.
+// A subsystem which fails to start is retried with an exponential backoff
+// instead of bringing down GD2, and the subsystems depending on it wait till
+// it is ready. The readiness of every subsystem can be queried while GD2 is
+// starting up.
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Names of the GD2 subsystems
+const (
+	Store     = "store"
+	Volumes   = "volumes"
+	TxnEngine = "txn-engine"
+	Events    = "events"
+	Exporter  = "exporter"
+	MsgBus    = "msgbus"
+	Peer      = "peer"
+	Servers   = "servers"
+	Daemons   = "daemons"
+	BrickMux  = "brickmux"
+	BrickWipe = "brick-wipe"
+	REST      = "rest"
+	Scheduler = "scheduler"
+	Discovery = "discovery"
+	Demo      = "demo"
+)
+
+var (
+	// minRetryInterval and maxRetryInterval bound the backoff between
+	// attempts to start a failed subsystem
+	minRetryInterval = time.Second
+	maxRetryInterval = time.Minute
+)
+
+var (
+	// ErrStarted is returned when subsystems are registered or started
+	// after startup has begun
+	ErrStarted = errors.New("subsystems have already been started")
+	// ErrSubsystemExists is returned when a subsystem with the same name is
+	// already registered
+	ErrSubsystemExists = errors.New("subsystem already registered")
+)
+
+// Subsystem is a component of GD2 which is started during startup
+type Subsystem struct {
+	Name string
+	// Requires lists the subsystems which must be ready before the
+	// subsystem is started
+	Requires []string
+	// Start starts the subsystem. It is called again after a backoff till
+	// it succeeds.
+	Start func() error
+	// Stop, if set, is called during shutdown if the subsystem was started
+	Stop func()
+}
+
+// Status represents the readiness of a subsystem
+type Status struct {
+	Name       string
+	Ready      bool
+	Attempts   int
+	LastError  string
+	ReadySince time.Time
+}
+
+type subsystem struct {
+	Subsystem
+	status Status
+}
+
+var (
+	mu         sync.RWMutex
+	subsystems []*subsystem
+	byName     = make(map[string]*subsystem)
+	started    bool
+	cancel     context.CancelFunc
+	done       chan struct{}
+)
+
+// Register registers a subsystem to be started during startup
+func Register(s Subsystem) error {
+	if s.Name == "" || s.Start == nil {
+		return errors.New("subsystem must have a name and a start function")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if started {
+		return ErrStarted
+	}
+	if _, ok := byName[s.Name]; ok {
+		return ErrSubsystemExists
+	}
+
+	sub := &subsystem{Subsystem: s, status: Status{Name: s.Name}}
+	subsystems = append(subsystems, sub)
+	byName[s.Name] = sub
+
+	return nil
+}
+
+// order returns the subsystems sorted such that every subsystem comes after
+// the subsystems it requires. The order is deterministic for a given
+// registration order.
+func order(subs []*subsystem) ([]*subsystem, error) {
+	names := make(map[string]bool, len(subs))
+	for _, s := range subs {
+		names[s.Name] = true
+	}
+
+	for _, s := range subs {
+		for _, r := range s.Requires {
+			if !names[r] {
+				return nil, fmt.Errorf("subsystem %s requires unknown subsystem %s", s.Name, r)
+			}
+		}
+	}
+
+	var sorted []*subsystem
+	placed := make(map[string]bool, len(subs))
+	for len(sorted) < len(subs) {
+		progress := false
+		for _, s := range subs {
+			if placed[s.Name] {
+				continue
+			}
+			ready := true
+			for _, r := range s.Requires {
+				if !placed[r] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				sorted = append(sorted, s)
+				placed[s.Name] = true
+				progress = true
+			}
+		}
+		if !progress {
+			return nil, errors.New("subsystems have cyclic dependencies")
+		}
+	}
+
+	return sorted, nil
+}
+
+// Start starts the registered subsystems in dependency order in the
+// background. An error is returned only if the dependencies of the
+// subsystems cannot be resolved.
+func Start() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if started {
+		return ErrStarted
+	}
+
+	sorted, err := order(subsystems)
+	if err != nil {
+		return err
+	}
+	subsystems = sorted
+	started = true
+
+	var ctx context.Context
+	ctx, cancel = context.WithCancel(context.Background())
+	done = make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for _, s := range sorted {
+			if !startSubsystem(ctx, s) {
+				return
+			}
+		}
+		log.Info("all subsystems are ready")
+	}()
+
+	return nil
+}
+
+// startSubsystem starts a subsystem, retrying with backoff till it succeeds.
+// It returns false if startup was cancelled.
+func startSubsystem(ctx context.Context, s *subsystem) bool {
+	logger := log.WithField("subsystem", s.Name)
+	interval := minRetryInterval
+
+	for {
+		err := s.Start()
+
+		mu.Lock()
+		s.status.Attempts++
+		if err == nil {
+			s.status.Ready = true
+			s.status.ReadySince = time.Now()
+			s.status.LastError = ""
+		} else {
+			s.status.LastError = err.Error()
+		}
+		mu.Unlock()
+
+		if err == nil {
+			logger.Debug("subsystem is ready")
+			return true
+		}
+
+		logger.WithError(err).WithField("retry-in", interval.String()).Error("failed to start subsystem")
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+	}
+}
+
+// Stop cancels a startup in progress and stops the started subsystems in the
+// reverse order in which they were started
+func Stop() {
+	mu.RLock()
+	if !started {
+		mu.RUnlock()
+		return
+	}
+	mu.RUnlock()
+
+	cancel()
+	<-done
+
+	mu.RLock()
+	defer mu.RUnlock()
+	for i := len(subsystems) - 1; i >= 0; i-- {
+		s := subsystems[i]
+		if s.status.Ready && s.Stop != nil {
+			log.WithField("subsystem", s.Name).Debug("stopping subsystem")
+			s.Stop()
+		}
+	}
+}
+
+// IsReady returns true if the named subsystem has been started
+func IsReady(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	s, ok := byName[name]
+	return ok && s.status.Ready
+}
+
+// AllReady returns true if all registered subsystems have been started
+func AllReady() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, s := range subsystems {
+		if !s.status.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// Statuses returns the readiness of all subsystems in the order in which
+// they are started
+func Statuses() []Status {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	statuses := make([]Status, 0, len(subsystems))
+	for _, s := range subsystems {
+		statuses = append(statuses, s.status)
+	}
+	return statuses
+}
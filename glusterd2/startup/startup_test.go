@@ -0,0 +1,109 @@
+package startup
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reset() {
+	subsystems = nil
+	byName = make(map[string]*subsystem)
+	started = false
+	cancel = nil
+	done = nil
+}
+
+func names(subs []*subsystem) []string {
+	var n []string
+	for _, s := range subs {
+		n = append(n, s.Name)
+	}
+	return n
+}
+
+func TestOrder(t *testing.T) {
+	noop := func() error { return nil }
+	subs := []*subsystem{
+		{Subsystem: Subsystem{Name: "rest", Requires: []string{"daemons"}, Start: noop}},
+		{Subsystem: Subsystem{Name: "store", Start: noop}},
+		{Subsystem: Subsystem{Name: "daemons", Requires: []string{"store", "peer"}, Start: noop}},
+		{Subsystem: Subsystem{Name: "peer", Requires: []string{"store"}, Start: noop}},
+		{Subsystem: Subsystem{Name: "events", Start: noop}},
+	}
+
+	sorted, err := order(subs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"store", "peer", "events", "daemons", "rest"}, names(sorted))
+
+	subs[1].Requires = []string{"rest"}
+	_, err = order(subs)
+	assert.Error(t, err)
+
+	subs[1].Requires = []string{"unknown"}
+	_, err = order(subs)
+	assert.Error(t, err)
+}
+
+func TestStartRetry(t *testing.T) {
+	defer reset()
+	minRetryInterval = time.Millisecond
+	maxRetryInterval = 4 * time.Millisecond
+
+	var failures int
+	var stopped []string
+	require.NoError(t, Register(Subsystem{
+		Name:     "flaky",
+		Requires: []string{"first"},
+		Start: func() error {
+			if failures < 3 {
+				failures++
+				return errors.New("not yet")
+			}
+			return nil
+		},
+		Stop: func() { stopped = append(stopped, "flaky") },
+	}))
+	require.NoError(t, Register(Subsystem{
+		Name:  "first",
+		Start: func() error { return nil },
+		Stop:  func() { stopped = append(stopped, "first") },
+	}))
+	assert.Equal(t, ErrSubsystemExists, Register(Subsystem{Name: "first", Start: func() error { return nil }}))
+
+	require.NoError(t, Start())
+	<-done
+
+	assert.True(t, AllReady())
+	assert.True(t, IsReady("flaky"))
+	statuses := Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "first", statuses[0].Name)
+	assert.Equal(t, 4, statuses[1].Attempts)
+	assert.Empty(t, statuses[1].LastError)
+
+	Stop()
+	assert.Equal(t, []string{"flaky", "first"}, stopped)
+}
+
+func TestStopCancelsStartup(t *testing.T) {
+	defer reset()
+	minRetryInterval = time.Hour
+	maxRetryInterval = time.Hour
+
+	stopCalled := false
+	require.NoError(t, Register(Subsystem{
+		Name:  "broken",
+		Start: func() error { return errors.New("broken") },
+		Stop:  func() { stopCalled = true },
+	}))
+	require.NoError(t, Start())
+
+	Stop()
+	assert.False(t, IsReady("broken"))
+	assert.False(t, stopCalled)
+	assert.Equal(t, "broken", Statuses()[0].LastError)
+}
@@ -5,9 +5,12 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/pkg/elasticetcd"
 
+	"github.com/coreos/etcd/pkg/types"
 	"github.com/pelletier/go-toml"
 	log "github.com/sirupsen/logrus"
 	flag "github.com/spf13/pflag"
@@ -28,6 +31,12 @@ const (
 	etcdPURLsOpt       = "etcdpurls"
 	etcdLogFileOpt     = "etcdlogfile"
 	defaultEtcdLogFile = "etcd.log"
+	leaveOnShutdownOpt = "etcd-leave-on-shutdown"
+	etcdStopTimeoutOpt = "etcd-stop-timeout"
+	etcdHealthIntvlOpt = "etcd-health-interval"
+	etcdAutoRestartOpt = "etcd-auto-restart"
+	etcdIdealSizeOpt   = "etcd-ideal-size"
+	managementOnlyOpt  = "management-only"
 
 	// TODO: Fix these too. Make elasticetcd support TLS if it doesn't
 	// already.
@@ -49,9 +58,56 @@ func InitFlags() {
 	flag.StringSlice(etcdCURLsOpt, nil, fmt.Sprintf("URLs which etcd server will use to receive etcd client requests. (Defaults to: %s)", elasticetcd.DefaultCURL))
 	flag.StringSlice(etcdPURLsOpt, nil, fmt.Sprintf("URLs which etcd server will use for peer to peer communication. (Defaults to: %s)", elasticetcd.DefaultPURL))
 
+	flag.Bool(leaveOnShutdownOpt, false, "Leave the etcd cluster membership when GD2 is stopped. Use this when retiring the node permanently.")
+	flag.Duration(etcdStopTimeoutOpt, elasticetcd.DefaultStopTimeout, "Time given to the embedded etcd server to stop gracefully, before it is stopped forcibly.")
+	flag.Duration(etcdHealthIntvlOpt, elasticetcd.DefaultHealthInterval, "Interval between health checks of the embedded etcd server.")
+	flag.Bool(etcdAutoRestartOpt, false, "Restart the embedded etcd server, with exponential backoff, when it becomes unresponsive.")
+	flag.Bool(managementOnlyOpt, false, "Run as a management node, serving the REST API and originating transactions without hosting bricks or being a member of the etcd cluster. The node only runs an etcd server till it joins a cluster.")
+	flag.Int(etcdIdealSizeOpt, elasticetcd.DefaultIdealSize, "Number of peers running etcd voting members when a new cluster is formed. Other peers only run etcd clients. The size of an existing cluster is changed with the REST API.")
+
 	flag.String(etcdClientCertFileOpt, "", "identify secure etcd client using this TLS certificate file")
 	flag.String(etcdClientKeyFileOpt, "", "identify secure etcd client using this TLS key file")
 	flag.String(etcdClientCAFileOpt, "", "verify certificates of TLS-enabled secure etcd servers using this CA bundle")
+
+	flag.String(encryptionKeyFileOpt, "", "File with the hex encoded 256 bit key encrypting sensitive values, like credentials, in the store. The same key has to be set on all peers. Sensitive values are stored in plaintext if not set.")
+}
+
+// LeaveOnShutdown returns true if the etcd cluster membership is to be left
+// when GD2 is stopped
+func LeaveOnShutdown() bool {
+	return config.GetBool(leaveOnShutdownOpt)
+}
+
+// ManagementOnly returns true if GD2 runs as a management node, which doesn't
+// host bricks and only connects to the etcd cluster as a client
+func ManagementOnly() bool {
+	return config.GetBool(managementOnlyOpt)
+}
+
+// stopTimeout returns the time given to the embedded etcd server to stop
+// gracefully
+func stopTimeout() time.Duration {
+	if t := config.GetDuration(etcdStopTimeoutOpt); t > 0 {
+		return t
+	}
+	return elasticetcd.DefaultStopTimeout
+}
+
+// healthInterval returns the interval between health checks of the embedded
+// etcd server
+func healthInterval() time.Duration {
+	if t := config.GetDuration(etcdHealthIntvlOpt); t > 0 {
+		return t
+	}
+	return elasticetcd.DefaultHealthInterval
+}
+
+// idealSize returns the ideal size of the etcd cluster of the embedded store
+func idealSize() int {
+	if i := config.GetInt(etcdIdealSizeOpt); i > 0 {
+		return i
+	}
+	return elasticetcd.DefaultIdealSize
 }
 
 // Config is the GD2 store configuration
@@ -123,6 +179,18 @@ func (c *Config) Save() error {
 // 	- Store config file
 // 	- Defaults
 func GetConfig() *Config {
+	conf := LoadConfig()
+
+	log.Debug("saving updated store config")
+	if err := conf.Save(); err != nil {
+		log.WithError(err).Warn("failed to save updated store config")
+	}
+
+	return conf
+}
+
+// LoadConfig returns a filled store config like GetConfig, without saving it
+func LoadConfig() *Config {
 	conf, err := readConfigFile()
 	if err != nil {
 		log.WithError(err).Warn("could not read store config file, continuing with defaults")
@@ -176,12 +244,60 @@ func GetConfig() *Config {
 		conf.UseTLS = config.GetBool(useTLSOpt)
 	}
 
-	log.Debug("saving updated store config")
-	if err := conf.Save(); err != nil {
-		log.WithError(err).Warn("failed to save updated store config")
+	return conf
+}
+
+// Validate checks the store config for errors
+func (c *Config) Validate() error {
+	var errs []string
+
+	checkURLs := func(name string, urls []string) {
+		if _, err := types.NewURLs(urls); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+	checkURLs(etcdEndpointsOpt, c.Endpoints)
+	if !c.NoEmbed {
+		checkURLs(etcdCURLsOpt, c.CURLs)
+		checkURLs(etcdPURLsOpt, c.PURLs)
 	}
 
-	return conf
+	checkFile := func(name, file string) {
+		if file == "" {
+			return
+		}
+		if _, err := os.Stat(file); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+	checkFile(certFileOpt, c.CertFile)
+	checkFile(keyFileOpt, c.KeyFile)
+	checkFile(caFileOpt, c.CAFile)
+	checkFile(etcdClientCertFileOpt, c.ClntCertFile)
+	checkFile(etcdClientKeyFileOpt, c.ClntKeyFile)
+	checkFile(etcdClientCAFileOpt, c.ClntCAFile)
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		errs = append(errs, fmt.Sprintf("%s and %s must be given together", certFileOpt, keyFileOpt))
+	}
+	if (c.ClntCertFile == "") != (c.ClntKeyFile == "") {
+		errs = append(errs, fmt.Sprintf("%s and %s must be given together", etcdClientCertFileOpt, etcdClientKeyFileOpt))
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("invalid store config: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// CheckConfig checks the saved store config file and the store config
+// filled from it for errors. A missing config file is not an error, the
+// defaults are used then.
+func CheckConfig() error {
+	if _, err := readConfigFile(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("invalid store config file %s: %s", storeConfFile, err)
+	}
+	return LoadConfig().Validate()
 }
 
 func readConfigFile() (*Config, error) {
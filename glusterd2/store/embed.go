@@ -59,6 +59,11 @@ func getElasticConfig(sconf *Config) (*elasticetcd.Config, error) {
 	econf.Name = gdctx.MyUUID.String()
 	econf.Dir = sconf.Dir
 	econf.LogDir = path.Join(config.GetString("logdir"), "store")
+	econf.StopTimeout = stopTimeout()
+	econf.HealthInterval = healthInterval()
+	econf.AutoRestart = config.GetBool(etcdAutoRestartOpt)
+	econf.IdealSize = idealSize()
+	econf.NoVolunteer = ManagementOnly()
 
 	endpoints, err := types.NewURLs(sconf.Endpoints)
 	if err != nil {
@@ -0,0 +1,252 @@
+package store
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	encryptionKeyFileOpt = "store-encryption-key-file"
+
+	// encryptedPrefix marks the values encrypted with the cluster key.
+	// Values without the prefix, like those stored before encryption was
+	// enabled, are read as they are.
+	encryptedPrefix = "gd2enc:v1:"
+
+	// encryptTag is the value of the `store` tag of the string fields
+	// encrypted by Marshal
+	encryptTag = "encrypt"
+
+	// encryptionCheckKey holds a known value encrypted with the cluster
+	// key, with which peers verify that they have the same key
+	encryptionCheckKey   = "config/store-encryption-check"
+	encryptionCheckValue = "glusterd2"
+)
+
+var (
+	// ErrEncryptionKeyMissing is returned when reading an encrypted value
+	// without the encryption key being configured
+	ErrEncryptionKeyMissing = errors.New("value is encrypted, but no store encryption key is configured")
+
+	encryptionMu   sync.RWMutex
+	encryptionAEAD cipher.AEAD
+)
+
+// loadEncryptionKey loads the cluster key from the configured key file. The
+// file holds a hex encoded 256 bit key. Encryption is disabled if no file is
+// configured.
+func loadEncryptionKey() error {
+	path := config.GetString(encryptionKeyFileOpt)
+	if path == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return errors.New("store encryption key must be hex encoded")
+	}
+	if len(key) != 32 {
+		return errors.New("store encryption key must be 256 bits long")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	encryptionMu.Lock()
+	encryptionAEAD = aead
+	encryptionMu.Unlock()
+	return nil
+}
+
+func getAEAD() cipher.AEAD {
+	encryptionMu.RLock()
+	defer encryptionMu.RUnlock()
+	return encryptionAEAD
+}
+
+// EncryptionEnabled returns true if sensitive values are encrypted in the
+// store
+func EncryptionEnabled() bool {
+	return getAEAD() != nil
+}
+
+// Encrypt encrypts the value with the cluster key using AES-GCM. The value is
+// returned as is if encryption is disabled.
+func Encrypt(value string) (string, error) {
+	aead := getAEAD()
+	if aead == nil || value == "" {
+		return value, nil
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value encrypted by Encrypt. Values which are not
+// encrypted are returned as they are.
+func Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+
+	aead := getAEAD()
+	if aead == nil {
+		return "", ErrEncryptionKeyMissing
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("encrypted value is too short")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt value, the store encryption key may differ from the key of the cluster")
+	}
+	return string(plain), nil
+}
+
+// Marshal returns the JSON encoding of v to be saved in the store, with the
+// string fields tagged `store:"encrypt"` encrypted with Encrypt. v itself is
+// not modified.
+func Marshal(v interface{}) ([]byte, error) {
+	if !EncryptionEnabled() {
+		return json.Marshal(v)
+	}
+
+	// The fields are encrypted in a copy of v
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	c := reflect.New(t)
+	if err := json.Unmarshal(data, c.Interface()); err != nil {
+		return nil, err
+	}
+	if err := walkEncrypted(c, Encrypt); err != nil {
+		return nil, err
+	}
+	return json.Marshal(c.Interface())
+}
+
+// Unmarshal decodes the JSON encoded data read from the store into v, and
+// decrypts the string fields tagged `store:"encrypt"`
+func Unmarshal(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	return walkEncrypted(reflect.ValueOf(v), Decrypt)
+}
+
+// walkEncrypted replaces the string fields tagged `store:"encrypt"` in v,
+// including those of nested structs, pointers and slices, with the result of
+// f
+func walkEncrypted(v reflect.Value, f func(string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return walkEncrypted(v.Elem(), f)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkEncrypted(v.Index(i), f); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			if t.Field(i).Tag.Get("store") == encryptTag && field.Kind() == reflect.String {
+				s, err := f(field.String())
+				if err != nil {
+					return err
+				}
+				field.SetString(s)
+				continue
+			}
+			if err := walkEncrypted(field, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkEncryptionKey verifies that the encryption key of this peer is the key
+// used by the other peers of the cluster. The first peer with a key saves a
+// known value encrypted with it, which the other peers have to be able to
+// decrypt.
+func checkEncryptionKey() {
+	resp, err := Get(context.TODO(), encryptionCheckKey)
+	if err != nil {
+		log.WithError(err).Warn("failed to get the store encryption check value")
+		return
+	}
+
+	if len(resp.Kvs) == 0 {
+		if !EncryptionEnabled() {
+			return
+		}
+		value, err := Encrypt(encryptionCheckValue)
+		if err != nil {
+			log.WithError(err).Warn("failed to encrypt the store encryption check value")
+			return
+		}
+		_, err = Txn(context.TODO()).
+			If(clientv3.Compare(clientv3.CreateRevision(encryptionCheckKey), "=", 0)).
+			Then(clientv3.OpPut(encryptionCheckKey, value)).
+			Commit()
+		if err != nil {
+			log.WithError(err).Warn("failed to save the store encryption check value")
+		}
+		return
+	}
+
+	if !EncryptionEnabled() {
+		log.Warn("store encryption is enabled in the cluster, but no key is configured on this peer, encrypted values can't be read")
+		return
+	}
+	if value, err := Decrypt(string(resp.Kvs[0].Value)); err != nil || value != encryptionCheckValue {
+		log.Error("store encryption key of this peer differs from the key of the cluster, encrypted values can't be read")
+	}
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testNested struct {
+	Password string `json:"password" store:"encrypt"`
+}
+
+type testRecord struct {
+	Name   string        `json:"name"`
+	Token  string        `json:"token" store:"encrypt"`
+	Nested *testNested   `json:"nested"`
+	List   []*testNested `json:"list"`
+}
+
+func setTestEncryptionKey(t *testing.T, key byte) {
+	k := make([]byte, 32)
+	for i := range k {
+		k[i] = key
+	}
+	block, err := aes.NewCipher(k)
+	assert.Nil(t, err)
+	aead, err := cipher.NewGCM(block)
+	assert.Nil(t, err)
+	encryptionAEAD = aead
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	defer func() { encryptionAEAD = nil }()
+
+	// Values are stored as they are without a key
+	v, err := Encrypt("secret")
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", v)
+
+	setTestEncryptionKey(t, 1)
+	v, err = Encrypt("secret")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(v, encryptedPrefix))
+	assert.NotContains(t, v, "secret")
+
+	d, err := Decrypt(v)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", d)
+
+	// Values stored before encryption was enabled are read as they are
+	d, err = Decrypt("plain")
+	assert.Nil(t, err)
+	assert.Equal(t, "plain", d)
+
+	setTestEncryptionKey(t, 2)
+	_, err = Decrypt(v)
+	assert.NotNil(t, err)
+
+	encryptionAEAD = nil
+	_, err = Decrypt(v)
+	assert.Equal(t, ErrEncryptionKeyMissing, err)
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	defer func() { encryptionAEAD = nil }()
+	setTestEncryptionKey(t, 1)
+
+	r := &testRecord{
+		Name:   "hook",
+		Token:  "tok3n",
+		Nested: &testNested{Password: "passw0rd"},
+		List:   []*testNested{{Password: "l1sted"}},
+	}
+	data, err := Marshal(r)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "hook")
+	assert.NotContains(t, string(data), "tok3n")
+	assert.NotContains(t, string(data), "passw0rd")
+	assert.NotContains(t, string(data), "l1sted")
+
+	// The marshalled value is not modified
+	assert.Equal(t, "tok3n", r.Token)
+	assert.Equal(t, "passw0rd", r.Nested.Password)
+
+	var u testRecord
+	assert.Nil(t, Unmarshal(data, &u))
+	assert.Equal(t, r, &u)
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// opDuration records the time taken by the operations on the store
+var opDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "glusterd2",
+		Subsystem: "store",
+		Name:      "op_duration_seconds",
+		Help:      "Time taken by operations on the etcd store.",
+		// 1ms to ~8s
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+	},
+	[]string{"op", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(opDuration)
+}
+
+func observeOp(op string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	opDuration.WithLabelValues(op, result).Observe(time.Since(start).Seconds())
+}
+
+// timedTxn records the duration of the commit of the wrapped store
+// transaction
+type timedTxn struct {
+	clientv3.Txn
+}
+
+func (t timedTxn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	return timedTxn{t.Txn.If(cs...)}
+}
+
+func (t timedTxn) Then(ops ...clientv3.Op) clientv3.Txn {
+	return timedTxn{t.Txn.Then(ops...)}
+}
+
+func (t timedTxn) Else(ops ...clientv3.Op) clientv3.Txn {
+	return timedTxn{t.Txn.Else(ops...)}
+}
+
+func (t timedTxn) Commit() (*clientv3.TxnResponse, error) {
+	start := time.Now()
+	resp, err := t.Txn.Commit()
+	observeOp("txn", start, err)
+	return resp, err
+}
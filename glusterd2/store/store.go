@@ -25,6 +25,11 @@ const (
 	getTimeout    = 5
 	putTimeout    = 5
 	deleteTimeout = 5
+
+	// DefaultSessionCheckInterval is the interval between checks of the
+	// session lease, and of the health of the store once the lease has
+	// expired, till a new session is created
+	DefaultSessionCheckInterval = 5 * time.Second
 )
 
 var storeCounters = expvar.NewMap("store")
@@ -36,6 +41,9 @@ var (
 
 	// ErrStoreInitedAlready is returned when the store is already intialized
 	ErrStoreInitedAlready = errors.New("store has been intialized already")
+	// ErrEtcdRolesUnsupported is returned when managing etcd roles with a
+	// remote store, whose etcd cluster isn't managed by GD2
+	ErrEtcdRolesUnsupported = errors.New("etcd roles can only be managed with the embedded store")
 )
 
 // GDStore is the GlusterD centralized store
@@ -55,7 +63,12 @@ type GDStore struct {
 	namespace       string
 	stop            chan struct{}
 	stopOnce        sync.Once
+	purge           bool
 	NamespaceClient *clientv3.Client
+
+	sessionCheckInterval        time.Duration
+	sessionCheckIntervalChanged chan struct{}
+	sessionCheckLock            sync.Mutex
 }
 
 // Init initializes the GD2 store
@@ -67,9 +80,17 @@ func Init(conf *Config) error {
 		return ErrStoreInitedAlready
 	}
 
+	if err := loadEncryptionKey(); err != nil {
+		return err
+	}
+
 	var err error
-	Store, err = New(conf)
-	return err
+	if Store, err = New(conf); err != nil {
+		return err
+	}
+
+	checkEncryptionKey()
+	return nil
 }
 
 // Close closes the GD2 store
@@ -78,6 +99,19 @@ func Close() {
 	defer lock.Unlock()
 
 	Store.Close()
+	if Store.purge {
+		Store.purgeLocalState()
+	}
+}
+
+// PurgeOnClose marks the local store data dir and the saved store config to be
+// deleted when the store is closed, so that the next start of GD2 begins with
+// a fresh store instead of the membership of the cluster it left
+func PurgeOnClose() {
+	lock.Lock()
+	defer lock.Unlock()
+
+	Store.purge = true
 }
 
 // Destroy closes the GD2 store and deletes the store data dir
@@ -97,6 +131,9 @@ func New(conf *Config) (*GDStore, error) {
 	if conf == nil {
 		conf = GetConfig()
 	}
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
 	var (
 		store *GDStore
 		err   error
@@ -116,6 +153,7 @@ func New(conf *Config) (*GDStore, error) {
 	}
 
 	store.stop = make(chan struct{})
+	store.sessionCheckIntervalChanged = make(chan struct{}, 1)
 
 	go store.keepSessionAlive()
 	return store, nil
@@ -128,15 +166,21 @@ func New(conf *Config) (*GDStore, error) {
 // reconnection with etcd server.
 func (s *GDStore) keepSessionAlive() {
 	var (
-		ticker         = time.NewTicker(time.Second * 5)
+		timer          = time.NewTimer(s.getSessionCheckInterval())
 		printedFailure bool
 	)
-	defer ticker.Stop()
+	defer timer.Stop()
 	for {
 		select {
 		case <-s.stop:
 			return
-		case <-ticker.C:
+		case <-s.sessionCheckIntervalChanged:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(s.getSessionCheckInterval())
+		case <-timer.C:
+			timer.Reset(s.getSessionCheckInterval())
 			// check if lease is orphaned, expires, or no longer being refreshed.
 			<-s.Session.Done()
 			if !printedFailure {
@@ -168,6 +212,29 @@ func (s *GDStore) keepSessionAlive() {
 	}
 }
 
+// SetSessionCheckInterval changes the interval between checks of the session
+// lease of the store. The default interval is used if it is 0.
+func (s *GDStore) SetSessionCheckInterval(d time.Duration) {
+	s.sessionCheckLock.Lock()
+	s.sessionCheckInterval = d
+	s.sessionCheckLock.Unlock()
+
+	select {
+	case s.sessionCheckIntervalChanged <- struct{}{}:
+	default:
+	}
+}
+
+func (s *GDStore) getSessionCheckInterval() time.Duration {
+	s.sessionCheckLock.Lock()
+	defer s.sessionCheckLock.Unlock()
+
+	if s.sessionCheckInterval <= 0 {
+		return DefaultSessionCheckInterval
+	}
+	return s.sessionCheckInterval
+}
+
 // isStorehealthy checks if store is reachable from the node.
 // Get a random key.If we get the response without an error,
 // the endpoint is healthy.
@@ -195,6 +262,94 @@ func (s *GDStore) Close() {
 	})
 }
 
+// Leave removes this node from the etcd cluster membership, so that a
+// retired node doesn't count towards the etcd quorum. Nothing is done for a
+// remote store, as this node isn't a member of the remote etcd cluster.
+func (s *GDStore) Leave() error {
+	if s.ee == nil {
+		return nil
+	}
+
+	log.Info("leaving etcd cluster membership")
+	return s.ee.Leave()
+}
+
+// RemoveEtcdMember removes the peer from the etcd cluster membership of the
+// embedded store. Nothing is done for a remote store, as the peers aren't
+// members of the remote etcd cluster.
+func (s *GDStore) RemoveEtcdMember(peerID string) error {
+	if s.ee == nil {
+		return nil
+	}
+
+	log.WithField("peer", peerID).Info("removing peer from etcd cluster membership")
+	return s.ee.RemoveMember(peerID)
+}
+
+// EtcdRoles returns the roles of the peers in the etcd cluster of the
+// embedded store
+func (s *GDStore) EtcdRoles() ([]elasticetcd.MemberRole, error) {
+	if s.ee == nil {
+		return nil, ErrEtcdRolesUnsupported
+	}
+	return s.ee.Roles()
+}
+
+// PinEtcdRole pins the role of the peer in the etcd cluster of the embedded
+// store
+func (s *GDStore) PinEtcdRole(peerID string, role elasticetcd.Role) error {
+	if s.ee == nil {
+		return ErrEtcdRolesUnsupported
+	}
+	return s.ee.PinRole(peerID, role)
+}
+
+// UnpinEtcdRole lets the embedded store decide the role of the peer in its
+// etcd cluster again
+func (s *GDStore) UnpinEtcdRole(peerID string) error {
+	if s.ee == nil {
+		return ErrEtcdRolesUnsupported
+	}
+	return s.ee.UnpinRole(peerID)
+}
+
+// EtcdIdealSize returns the number of voting members in the etcd cluster of
+// the embedded store
+func (s *GDStore) EtcdIdealSize() (int, error) {
+	if s.ee == nil {
+		return 0, ErrEtcdRolesUnsupported
+	}
+	return s.ee.IdealSize()
+}
+
+// SetEtcdIdealSize sets the number of voting members in the etcd cluster of
+// the embedded store
+func (s *GDStore) SetEtcdIdealSize(size int) error {
+	if s.ee == nil {
+		return ErrEtcdRolesUnsupported
+	}
+	return s.ee.SetIdealSize(size)
+}
+
+// SetEtcdHealthHandler sets the handler called when the health of the etcd
+// server of the embedded store changes. It does nothing with a remote store,
+// whose etcd servers aren't managed by GD2.
+func (s *GDStore) SetEtcdHealthHandler(h elasticetcd.HealthHandler) {
+	if s.ee == nil {
+		return
+	}
+	s.ee.SetHealthHandler(h)
+}
+
+// SetEtcdHealthPolicy changes the policy of the health monitor of the etcd
+// server of the embedded store. It does nothing with a remote store.
+func (s *GDStore) SetEtcdHealthPolicy(p elasticetcd.HealthPolicy) {
+	if s.ee == nil {
+		return
+	}
+	s.ee.SetHealthPolicy(p)
+}
+
 // Destroy closes the store and deletes the store data dir
 func (s *GDStore) Destroy(deleteNamespace bool) {
 	if s.ee != nil {
@@ -214,6 +369,17 @@ func (s *GDStore) Destroy(deleteNamespace bool) {
 	s.Close()
 }
 
+func (s *GDStore) purgeLocalState() {
+	if s.ee != nil {
+		if err := os.RemoveAll(s.conf.Dir); err != nil {
+			log.WithError(err).WithField("dir", s.conf.Dir).Error("failed to remove store data dir")
+		}
+	}
+	if err := os.Remove(s.conf.ConfFile); err != nil && !os.IsNotExist(err) {
+		log.WithError(err).WithField("file", s.conf.ConfFile).Error("failed to remove store config")
+	}
+}
+
 // UpdateEndpoints updates the configured endpoints and saves them
 func (s *GDStore) UpdateEndpoints() error {
 	if err := s.Sync(s.Ctx()); err != nil {
@@ -278,7 +444,10 @@ func Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.
 	}
 
 	defer storeCounters.Add("get", 1)
-	return Store.Get(ctx, key, opts...)
+	start := time.Now()
+	resp, err := Store.Get(ctx, key, opts...)
+	observeOp("get", start, err)
+	return resp, err
 }
 
 //Put is a wrapper function that calls clientv3.KV.Put with a default timeout if an empty context is passed
@@ -291,7 +460,10 @@ func Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clie
 	}
 
 	defer storeCounters.Add("put", 1)
-	return Store.Put(ctx, key, val, opts...)
+	start := time.Now()
+	resp, err := Store.Put(ctx, key, val, opts...)
+	observeOp("put", start, err)
+	return resp, err
 }
 
 //Delete is a wrapper function that calls clientv3.KV.Delete with a default timeout if an empty context is passed
@@ -304,7 +476,10 @@ func Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*client
 	}
 
 	defer storeCounters.Add("delete", 1)
-	return Store.Delete(ctx, key, opts...)
+	start := time.Now()
+	resp, err := Store.Delete(ctx, key, opts...)
+	observeOp("delete", start, err)
+	return resp, err
 }
 
 // Txn is a wrapper function that calls clientv3.KV.Txn which creates a transaction
@@ -312,5 +487,5 @@ func Txn(ctx context.Context) clientv3.Txn {
 	// can't cancel() here as caller will have to eventually call
 	// clientv3.Txn.Commit()
 	defer storeCounters.Add("txn", 1)
-	return Store.Txn(ctx)
+	return timedTxn{Store.Txn(ctx)}
 }
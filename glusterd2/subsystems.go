@@ -0,0 +1,218 @@
+package main
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/brickmux"
+	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/discovery"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/exporter"
+	"github.com/gluster/glusterd2/glusterd2/healthpolicy"
+	"github.com/gluster/glusterd2/glusterd2/msgbus"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/pmap"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	"github.com/gluster/glusterd2/glusterd2/servers"
+	"github.com/gluster/glusterd2/glusterd2/startup"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/transactionv2/cleanuphandler"
+	gdutils "github.com/gluster/glusterd2/glusterd2/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/firewalld"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+	"github.com/thejerf/suture"
+)
+
+// registerSubsystems registers the GD2 subsystems which are started in
+// dependency order once the config has been loaded:
+// store -> volumes -> peer RPC (servers) -> daemons -> brickmux reconcile ->
+// brick wipe -> REST
+func registerSubsystems(super *suture.Supervisor) error {
+	subsystems := []startup.Subsystem{
+		{
+			// Initialize etcd store (etcd client connection)
+			Name: startup.Store,
+			Start: func() error {
+				return store.Init(nil)
+			},
+			Stop: func() {
+				if store.LeaveOnShutdown() {
+					if err := peer.Retire(); err != nil {
+						log.WithError(err).Error("failed to leave etcd cluster")
+					}
+				}
+				store.Close()
+			},
+		},
+		{
+			// Bring the volumes in the store up to date. REST requests
+			// are not served till this succeeds.
+			Name:     startup.Volumes,
+			Requires: []string{startup.Store},
+			Start: func() error {
+				if err := volume.MigrateStore(); err != nil {
+					return err
+				}
+				if err := volume.SyncBrickIndex(); err != nil {
+					return err
+				}
+				if err := healthpolicy.Start(); err != nil {
+					return err
+				}
+				// Not safe to be retried, so started last
+				volume.StartVolinfoCache()
+				return nil
+			},
+			Stop: func() {
+				healthpolicy.Stop()
+				volume.StopVolinfoCache()
+			},
+		},
+		{
+			Name:     startup.TxnEngine,
+			Requires: []string{startup.Store},
+			Start: func() error {
+				transaction.StartTxnEngine()
+				cleanuphandler.StartCleanupLeader()
+				return nil
+			},
+			Stop: func() {
+				transaction.StopTxnEngine()
+				cleanuphandler.StopCleanupLeader()
+			},
+		},
+		{
+			// Start the events framework after store is up
+			Name:     startup.Events,
+			Requires: []string{startup.Store},
+			Start:    events.Start,
+			Stop: func() {
+				events.Stop()
+			},
+		},
+		{
+			Name:     startup.Exporter,
+			Requires: []string{startup.Events},
+			Start:    exporter.Start,
+			Stop:     exporter.Stop,
+		},
+		{
+			Name:     startup.MsgBus,
+			Requires: []string{startup.Events},
+			Start:    msgbus.Start,
+			Stop:     msgbus.Stop,
+		},
+		{
+			Name:     startup.Peer,
+			Requires: []string{startup.Store},
+			Start: func() error {
+				if err := peer.AddSelfDetails(); err != nil {
+					return err
+				}
+				// Load the default group option map into the store
+				return volumecommands.InitDefaultGroupOptions()
+			},
+		},
+		{
+			// Start all servers (rest, peerrpc, sunrpc) managed by suture
+			// supervisor. REST requests are rejected by the readiness
+			// gate till the REST subsystem is ready.
+			Name:     startup.Servers,
+			Requires: []string{startup.Volumes, startup.TxnEngine, startup.Exporter, startup.MsgBus, startup.Peer},
+			Start: func() error {
+				super.ServeBackground()
+				super.Add(servers.New())
+
+				// Start dbus connection (optional for notifying firewalld)
+				if err := firewalld.Init(); err != nil {
+					log.WithError(err).Warn("firewalld.Init() failed")
+				}
+
+				pmap.Init()
+				return nil
+			},
+			Stop: super.Stop,
+		},
+		{
+			Name:     startup.Daemons,
+			Requires: []string{startup.Servers},
+			Start: func() error {
+				// Remove pidfiles and sockets of daemons and bricks
+				// which died while GlusterD was down
+				daemon.SweepStaleFiles(config.GetString("rundir"), log.StandardLogger())
+
+				// Mount all Local Bricks
+				gdutils.MountLocalBricks()
+
+				// Restart previously running daemons
+				daemon.StartAllDaemons()
+				return nil
+			},
+		},
+		{
+			// Reconcile multiplexed bricks
+			Name:     startup.BrickMux,
+			Requires: []string{startup.Daemons},
+			Start:    brickmux.Reconcile,
+		},
+		{
+			// Resume wiping bricks of deleted volumes
+			Name:     startup.BrickWipe,
+			Requires: []string{startup.BrickMux},
+			Start: func() error {
+				volume.StartBrickWipeWorker()
+				return nil
+			},
+			Stop: volume.StopBrickWipeWorker,
+		},
+		{
+			// Opens the readiness gate of the REST server
+			Name:     startup.REST,
+			Requires: []string{startup.BrickWipe},
+			Start:    func() error { return nil },
+		},
+		{
+			// Start running periodic jobs registered by commands and
+			// plugins. This has to be done after the REST server has
+			// registered them.
+			Name:     startup.Scheduler,
+			Requires: []string{startup.REST},
+			Start: func() error {
+				scheduler.Start()
+				return nil
+			},
+			Stop: scheduler.Stop,
+		},
+		{
+			// Join an existing cluster if configured to discover one. The
+			// peer RPC server must be running for the cluster to be able
+			// to add us.
+			Name:     startup.Discovery,
+			Requires: []string{startup.Servers},
+			Start: func() error {
+				discovery.Bootstrap()
+				return nil
+			},
+		},
+	}
+
+	if demoMode {
+		// Seed the simulated peers and bricks once the REST API is up
+		subsystems = append(subsystems, startup.Subsystem{
+			Name:     startup.Demo,
+			Requires: []string{startup.REST},
+			Start:    seedDemo,
+		})
+	}
+
+	for _, s := range subsystems {
+		if err := startup.Register(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
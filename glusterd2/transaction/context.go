@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/store"
 
 	"github.com/coreos/etcd/clientv3"
@@ -61,7 +62,7 @@ type TxnCtxConfig struct {
 func newCtx(config *TxnCtxConfig) *Tctx {
 	return &Tctx{
 		config:         config,
-		logger:         log.StandardLogger().WithFields(config.LogFields),
+		logger:         log.StandardLogger().WithFields(config.LogFields).WithField("peerid", gdctx.MyUUID.String()),
 		readSet:        make(map[string][]byte),
 		writeSet:       make(map[string]string),
 		readCacheDirty: true,
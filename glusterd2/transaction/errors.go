@@ -0,0 +1,126 @@
+package transaction
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
+	"github.com/pborman/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// validationErrPrefix prefixes the messages of validation errors, so that
+// they can be recognized after being sent as strings from remote peers
+const validationErrPrefix = "validation failed: "
+
+// NodeOfflineError is returned when a node participating in a transaction
+// is offline
+type NodeOfflineError struct {
+	Node uuid.UUID
+}
+
+func (e *NodeOfflineError) Error() string {
+	return fmt.Sprintf("node %s is probably down", e.Node)
+}
+
+// Response implements api.ErrorResponse
+func (e *NodeOfflineError) Response() api.ErrorResp {
+	return api.ErrorResp{Errors: []api.HTTPError{{
+		Code:    int(api.ErrCodePeerOffline),
+		Message: e.Error(),
+		Fields:  map[string]string{"peer-id": e.Node.String()},
+	}}}
+}
+
+// Status implements api.ErrorResponse
+func (e *NodeOfflineError) Status() int {
+	return http.StatusServiceUnavailable
+}
+
+// ValidationError is returned by step functions when the request fails
+// validation
+type ValidationError struct {
+	Err error
+}
+
+// NewValidationError returns a ValidationError for err
+func NewValidationError(err error) error {
+	return &ValidationError{Err: err}
+}
+
+func (e *ValidationError) Error() string {
+	return validationErrPrefix + e.Err.Error()
+}
+
+// Response implements api.ErrorResponse
+func (e *ValidationError) Response() api.ErrorResp {
+	return api.ErrorResp{Errors: []api.HTTPError{{
+		Code:    int(api.ErrCodeValidationFailed),
+		Message: e.Error(),
+	}}}
+}
+
+// Status implements api.ErrorResponse
+func (e *ValidationError) Status() int {
+	return http.StatusBadRequest
+}
+
+// quorumLossErrors are the store errors returned when the etcd cluster has
+// lost quorum
+var quorumLossErrors = []error{
+	rpctypes.ErrNoLeader,
+	rpctypes.ErrTimeoutDueToLeaderFail,
+	rpctypes.ErrTimeoutDueToConnectionLost,
+}
+
+func isQuorumLoss(err error) bool {
+	desc := rpctypes.ErrorDesc(err)
+	for _, e := range quorumLossErrors {
+		if err == e || desc == e.Error() {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorStatus maps well-known transaction errors, including the errors of
+// failed steps, to an HTTP status and an API error code. A status of 0 is
+// returned for errors which aren't recognized.
+func ErrorStatus(err error) (int, api.ErrorCode) {
+	switch e := err.(type) {
+	case nil:
+		return 0, 0
+	case stepResp:
+		return e.status()
+	case *stepResp:
+		return e.status()
+	case api.ErrorResponse:
+		var code api.ErrorCode
+		if resp := e.Response(); len(resp.Errors) != 0 {
+			code = api.ErrorCode(resp.Errors[0].Code)
+		}
+		return e.Status(), code
+	}
+
+	switch {
+	case err == ErrLockTimeout:
+		return http.StatusConflict, api.ErrCodeLockTimeout
+	case isQuorumLoss(err):
+		return http.StatusServiceUnavailable, api.ErrCodeQuorumLost
+	case grpc.Code(err) == codes.Unavailable:
+		return http.StatusServiceUnavailable, api.ErrCodePeerOffline
+	case grpc.Code(err) == codes.PermissionDenied:
+		return http.StatusConflict, api.ErrCodeClusterIDMismatch
+	case strings.Contains(err.Error(), validationErrPrefix):
+		return http.StatusBadRequest, api.ErrCodeValidationFailed
+	case err.Error() == gderrors.ErrVolinfoConflict.Error():
+		return http.StatusConflict, api.ErrCodeConflict
+	}
+
+	return 0, 0
+}
@@ -0,0 +1,191 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// Failure points allow developers to force errors or delays at well known
+// places inside transaction steps to test rollback and resume logic. They are
+// only evaluated when glusterd2 runs with the `devmode` option enabled. Armed
+// failure points are saved in the store so that they can be armed from any
+// peer and triggered on any other peer.
+
+const (
+	failPointPrefix = "debug/failpoints/"
+
+	// FailPointBefore is the stage before a step function is run
+	FailPointBefore = "before"
+	// FailPointAfterCommit is the stage after a step function has run and
+	// its results have been committed to the store
+	FailPointAfterCommit = "after-commit"
+)
+
+var (
+	// ErrInvalidFailPoint is returned when a failure point being armed is invalid
+	ErrInvalidFailPoint = errors.New("invalid failure point")
+	// ErrFailPointNotFound is returned when a failure point is not armed
+	ErrFailPointNotFound = errors.New("failure point not found")
+)
+
+// FailPointsEnabled returns true if failure injection is enabled
+func FailPointsEnabled() bool {
+	return config.GetBool("devmode")
+}
+
+// FailPointName returns the name of the failure point for the given step
+// function and stage
+func FailPointName(stepName, stage string) string {
+	return stepName + "." + stage
+}
+
+// ValidateFailPoint checks if the given failure point can be armed
+func ValidateFailPoint(fp *api.FailPoint) error {
+	if fp.Name == "" {
+		return ErrInvalidFailPoint
+	}
+
+	switch fp.Action {
+	case api.FailPointActionError:
+	case api.FailPointActionDelay:
+		if _, err := time.ParseDuration(fp.Delay); err != nil {
+			return fmt.Errorf("invalid delay for failure point %s: %s", fp.Name, err)
+		}
+	default:
+		return fmt.Errorf("invalid action %q for failure point %s", fp.Action, fp.Name)
+	}
+
+	if fp.Count < 0 {
+		return fmt.Errorf("invalid count for failure point %s", fp.Name)
+	}
+
+	return nil
+}
+
+// ArmFailPoint saves the given failure point in the store, arming it on all
+// the peers it is targeted at
+func ArmFailPoint(fp *api.FailPoint) error {
+	if err := ValidateFailPoint(fp); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(fp)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), failPointPrefix+fp.Name, string(b))
+	return err
+}
+
+// DisarmFailPoint removes the named failure point from the store
+func DisarmFailPoint(name string) error {
+	resp, err := store.Delete(context.TODO(), failPointPrefix+name)
+	if err != nil {
+		return err
+	}
+
+	if resp.Deleted == 0 {
+		return ErrFailPointNotFound
+	}
+
+	return nil
+}
+
+// GetFailPoints returns all the armed failure points
+func GetFailPoints() ([]api.FailPoint, error) {
+	resp, err := store.Get(context.TODO(), failPointPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	fps := make([]api.FailPoint, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var fp api.FailPoint
+		if err := json.Unmarshal(kv.Value, &fp); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal failure point")
+			continue
+		}
+		fps = append(fps, fp)
+	}
+
+	return fps, nil
+}
+
+// appliesToPeer returns true if the failure point is active on this peer
+func appliesToPeer(fp *api.FailPoint) bool {
+	if len(fp.Peers) == 0 {
+		return true
+	}
+
+	for _, p := range fp.Peers {
+		if p == gdctx.MyUUID.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// triggerFailPoint checks if the named failure point is armed on this peer
+// and performs the configured action. An error is returned if the failure
+// point has been armed to fail.
+func triggerFailPoint(name string) error {
+	if !FailPointsEnabled() {
+		return nil
+	}
+
+	key := failPointPrefix + name
+	resp, err := store.Get(context.TODO(), key)
+	if err != nil || resp.Count != 1 {
+		return nil
+	}
+
+	var fp api.FailPoint
+	if err := json.Unmarshal(resp.Kvs[0].Value, &fp); err != nil {
+		return nil
+	}
+
+	if !appliesToPeer(&fp) {
+		return nil
+	}
+
+	logger := log.WithFields(log.Fields{
+		"failpoint": name,
+		"action":    fp.Action,
+	})
+	logger.Warn("failure point triggered")
+
+	if fp.Count > 0 {
+		fp.Count--
+		if fp.Count == 0 {
+			_, err = store.Delete(context.TODO(), key)
+		} else {
+			b, _ := json.Marshal(fp)
+			_, err = store.Put(context.TODO(), key, string(b))
+		}
+		if err != nil {
+			logger.WithError(err).Warn("failed to update failure point count")
+		}
+	}
+
+	switch fp.Action {
+	case api.FailPointActionDelay:
+		d, _ := time.ParseDuration(fp.Delay)
+		time.Sleep(d)
+	case api.FailPointActionError:
+		return fmt.Errorf("failure point %s triggered", name)
+	}
+
+	return nil
+}
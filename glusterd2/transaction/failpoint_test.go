@@ -0,0 +1,57 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/testutils"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFailPoint(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		fp    api.FailPoint
+		valid bool
+	}{
+		{"error", api.FailPoint{Name: "vol-create.Commit.before", Action: api.FailPointActionError}, true},
+		{"error with count", api.FailPoint{Name: "vol-create.Commit.before", Action: api.FailPointActionError, Count: 2}, true},
+		{"delay", api.FailPoint{Name: "vol-create.Commit.after-commit", Action: api.FailPointActionDelay, Delay: "10s"}, true},
+		{"no name", api.FailPoint{Action: api.FailPointActionError}, false},
+		{"no action", api.FailPoint{Name: "vol-create.Commit.before"}, false},
+		{"unknown action", api.FailPoint{Name: "vol-create.Commit.before", Action: "panic"}, false},
+		{"delay without duration", api.FailPoint{Name: "vol-create.Commit.before", Action: api.FailPointActionDelay}, false},
+		{"invalid delay", api.FailPoint{Name: "vol-create.Commit.before", Action: api.FailPointActionDelay, Delay: "10"}, false},
+		{"negative count", api.FailPoint{Name: "vol-create.Commit.before", Action: api.FailPointActionError, Count: -1}, false},
+	} {
+		err := ValidateFailPoint(&tc.fp)
+		if tc.valid {
+			assert.NoError(t, err, tc.name)
+		} else {
+			assert.Error(t, err, tc.name)
+		}
+	}
+}
+
+func TestAppliesToPeer(t *testing.T) {
+	myID := uuid.NewRandom()
+	defer testutils.Patch(&gdctx.MyUUID, myID).Restore()
+	otherID := uuid.NewRandom().String()
+
+	for _, tc := range []struct {
+		name    string
+		peers   []string
+		applies bool
+	}{
+		{"all peers", nil, true},
+		{"this peer", []string{myID.String()}, true},
+		{"this peer among others", []string{otherID, myID.String()}, true},
+		{"other peer", []string{otherID}, false},
+	} {
+		fp := api.FailPoint{Name: "vol-create.Commit.before", Action: api.FailPointActionError, Peers: tc.peers}
+		assert.Equal(t, tc.applies, appliesToPeer(&fp), tc.name)
+	}
+}
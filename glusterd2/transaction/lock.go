@@ -109,10 +109,7 @@ func CreateLockFuncs(key string) (LockUnlockFunc, LockUnlockFunc) {
 	// have registry either.
 
 	lockFunc := func(ctx context.Context) error {
-		logger := gdctx.GetReqLogger(ctx)
-		if logger == nil {
-			logger = log.StandardLogger()
-		}
+		logger := gdctx.Logger(ctx)
 
 		ctx, cancel := context.WithTimeout(ctx, lockObtainTimeout)
 		defer cancel()
@@ -132,10 +129,7 @@ func CreateLockFuncs(key string) (LockUnlockFunc, LockUnlockFunc) {
 	}
 
 	unlockFunc := func(ctx context.Context) error {
-		logger := gdctx.GetReqLogger(ctx)
-		if logger == nil {
-			logger = log.StandardLogger()
-		}
+		logger := gdctx.Logger(ctx)
 
 		logger.WithField("key", key).Debug("attempting to unlock")
 		if err := locker.Unlock(context.Background()); err != nil {
@@ -0,0 +1,101 @@
+package transaction
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+	"go.opencensus.io/trace"
+)
+
+const (
+	slowStepThresholdOpt = "txn-slow-step-threshold"
+	// defaultSlowStepThreshold is the duration beyond which a step is
+	// considered slow
+	defaultSlowStepThreshold = 10 * time.Second
+)
+
+// stepDuration records the time taken by the step functions run on this node
+var stepDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "glusterd2",
+		Subsystem: "txn",
+		Name:      "step_duration_seconds",
+		Help:      "Time taken by transaction step functions to run on this node.",
+		// 5ms to ~40s
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+	},
+	[]string{"step", "result"},
+)
+
+// txnDuration records the time taken by the transactions initiated on this
+// node
+var txnDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "glusterd2",
+		Subsystem: "txn",
+		Name:      "duration_seconds",
+		Help:      "Time taken by transactions initiated on this node.",
+		// 10ms to ~80s
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(stepDuration, txnDuration)
+}
+
+// InitFlags intializes the command line options for transactions
+func InitFlags() {
+	flag.Duration(slowStepThresholdOpt, defaultSlowStepThreshold, "Steps of transactions taking longer than this are logged as slow. Set to 0 to disable.")
+}
+
+func slowStepThreshold() time.Duration {
+	if !config.IsSet(slowStepThresholdOpt) {
+		return defaultSlowStepThreshold
+	}
+	return config.GetDuration(slowStepThresholdOpt)
+}
+
+// observeStep records the duration of a step function run on this node in
+// the step duration metrics and the trace span of the step, if any. Steps
+// exceeding the slow step threshold are flagged in the span and logged.
+func observeStep(span *trace.Span, logger log.FieldLogger, step string, d time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	stepDuration.WithLabelValues(step, result).Observe(d.Seconds())
+
+	threshold := slowStepThreshold()
+	slow := threshold > 0 && d > threshold
+
+	if span != nil {
+		span.AddAttributes(
+			trace.Int64Attribute("durationMs", int64(d/time.Millisecond)),
+			trace.BoolAttribute("slow", slow),
+		)
+	}
+
+	if slow {
+		expTxn.Add("slow_steps", 1)
+		logger.WithFields(log.Fields{
+			"step":      step,
+			"duration":  d.String(),
+			"threshold": threshold.String(),
+		}).Warn("transaction step was slow")
+	}
+}
+
+// observeTxn records the duration of a transaction initiated on this node.
+// The count of the histogram is the number of transactions.
+func observeTxn(start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	txnDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
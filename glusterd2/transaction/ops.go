@@ -0,0 +1,363 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// opPrefix holds a record of each transaction in progress. The
+	// records are attached to the store session of the peer running the
+	// transaction, so that they are dropped if the peer goes away.
+	opPrefix = "ops/"
+	// historyPrefix holds the records of the completed transactions,
+	// which are kept till they are pruned by PruneHistory
+	historyPrefix = "transaction-history/"
+)
+
+// States of the transactions and of their steps on each node
+const (
+	OpRunning   = "running"
+	OpSucceeded = "succeeded"
+	OpFailed    = "failed"
+	// OpPending is the state of a step yet to complete on a node
+	OpPending = "pending"
+)
+
+// Op is a record of a transaction in progress, kept in the store for the
+// cluster wide view of the transactions in progress
+type Op struct {
+	TxnID      uuid.UUID
+	ReqID      uuid.UUID
+	Originator uuid.UUID
+	User       string
+	// Locks are the IDs of the locks held by the transaction, the names
+	// of the volumes and other entities it operates on
+	Locks []string
+	// Operation is worked out from the names of the steps, and is empty
+	// till the steps are run
+	Operation string
+	Step      string
+	// StepIndex is the index of the step running, counted from 1, and 0
+	// till the steps are run
+	StepIndex     int
+	StepCount     int
+	StartedAt     time.Time
+	StepStartedAt time.Time
+	// RollingBack is set once a step has failed, and the steps run till
+	// then are being undone
+	RollingBack bool
+	// Rollback is the outcome of undoing the steps, in the order undone
+	Rollback []RollbackStep
+	// Steps are the steps of the transaction with their state on each
+	// node
+	Steps []OpStep
+	// State is running till the transaction is done, and then whether it
+	// succeeded or failed
+	State      string
+	Error      string
+	FinishedAt time.Time
+}
+
+// OpStep is the record of a step of a transaction
+type OpStep struct {
+	DoFunc     string
+	Skipped    bool
+	Nodes      []OpStepNode
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// OpStepNode is the state of a step on a node
+type OpStepNode struct {
+	Node  uuid.UUID
+	State string
+	Error string
+}
+
+// RollbackStep is the outcome of undoing a step on a node
+type RollbackStep struct {
+	UndoFunc string
+	Node     uuid.UUID
+	// Error is empty if the step was undone
+	Error string
+}
+
+func opKey(txnID uuid.UUID) string {
+	return opPrefix + txnID.String()
+}
+
+// NewOp returns a record of the transaction started with the context
+func NewOp(ctx context.Context, txnID uuid.UUID, locks []string) *Op {
+	return &Op{
+		TxnID:      txnID,
+		ReqID:      gdctx.GetReqID(ctx),
+		Originator: gdctx.MyUUID,
+		User:       gdctx.GetReqUser(ctx),
+		Locks:      locks,
+		StartedAt:  time.Now(),
+		State:      OpRunning,
+	}
+}
+
+// opName returns the name of the operation the steps are of, the prefix of
+// the name of the first step run, like vol-create for vol-create.StoreVolume
+func opName(steps []*Step) string {
+	for _, s := range steps {
+		if s.Skip {
+			continue
+		}
+		if i := strings.LastIndex(s.DoFunc, "."); i > 0 {
+			return s.DoFunc[:i]
+		}
+		return s.DoFunc
+	}
+	return ""
+}
+
+// Start records that the steps of the transaction are run
+func (o *Op) Start(steps []*Step) {
+	o.Operation = opName(steps)
+	o.StepCount = len(steps)
+	o.Steps = make([]OpStep, len(steps))
+	for i, s := range steps {
+		o.Steps[i] = OpStep{DoFunc: s.DoFunc, Skipped: s.Skip}
+		for _, node := range s.Nodes {
+			o.Steps[i].Nodes = append(o.Steps[i].Nodes, OpStepNode{Node: node, State: OpPending})
+		}
+	}
+	o.Save()
+}
+
+// SetStep records the step the transaction is running, given its index
+// counted from 0
+func (o *Op) SetStep(index int, s *Step) {
+	o.Step = s.DoFunc
+	o.StepIndex = index + 1
+	o.StepStartedAt = time.Now()
+	if index < len(o.Steps) {
+		o.Steps[index].StartedAt = o.StepStartedAt
+	}
+	o.Save()
+}
+
+// StepDone records the outcome of the step on each node, given its index
+// counted from 0 and the error returned by the step
+func (o *Op) StepDone(index int, err error) {
+	if index >= len(o.Steps) {
+		return
+	}
+
+	step := &o.Steps[index]
+	step.FinishedAt = time.Now()
+	errs := nodeErrors(err)
+	for i := range step.Nodes {
+		n := &step.Nodes[i]
+		if e, ok := errs[n.Node.String()]; ok {
+			n.State = OpFailed
+			n.Error = e
+		} else if err != nil && errs == nil {
+			n.State = OpFailed
+			n.Error = err.Error()
+		} else {
+			n.State = OpSucceeded
+		}
+	}
+	// Not saved till the next step is started or the transaction is
+	// done, which follows right away
+}
+
+// Finish records the outcome of the transaction
+func (o *Op) Finish(err error) {
+	o.FinishedAt = time.Now()
+	o.State = OpSucceeded
+	if err != nil {
+		o.State = OpFailed
+		o.Error = err.Error()
+	}
+	o.Save()
+}
+
+// nodeErrors returns the errors of a step on each node it failed on, keyed
+// by node ID, or nil if the error isn't of a step run on the nodes
+func nodeErrors(err error) map[string]string {
+	resp, ok := err.(stepResp)
+	if !ok {
+		return nil
+	}
+	errs := make(map[string]string)
+	for _, r := range resp.Resps {
+		if r.Error != nil {
+			errs[r.PeerID.String()] = r.Error.Error()
+		}
+	}
+	return errs
+}
+
+// StartRollback records that the transaction is being rolled back
+func (o *Op) StartRollback() {
+	o.RollingBack = true
+	o.Save()
+}
+
+// AddRollback records the outcome of undoing a step on the nodes, given the
+// error returned by the undo
+func (o *Op) AddRollback(undoFunc string, nodes []uuid.UUID, err error) {
+	errs := nodeErrors(err)
+	if errs == nil && err != nil {
+		errs = make(map[string]string)
+		for _, node := range nodes {
+			errs[node.String()] = err.Error()
+		}
+	}
+
+	for _, node := range nodes {
+		o.Rollback = append(o.Rollback, RollbackStep{
+			UndoFunc: undoFunc,
+			Node:     node,
+			Error:    errs[node.String()],
+		})
+	}
+	o.Save()
+}
+
+// Save saves the record in the store. Failures are only logged, as the
+// records are informational.
+func (o *Op) Save() {
+	if store.Store == nil || store.Store.Session == nil {
+		return
+	}
+
+	b, err := json.Marshal(o)
+	if err != nil {
+		log.WithError(err).WithField("txnid", o.TxnID.String()).Warn("failed to marshal transaction record")
+		return
+	}
+	_, err = store.Put(context.TODO(), opKey(o.TxnID), string(b), clientv3.WithLease(store.Store.Session.Lease()))
+	if err != nil {
+		log.WithError(err).WithField("txnid", o.TxnID.String()).Warn("failed to save transaction record")
+	}
+}
+
+// Remove removes the record from the store, once the transaction is done.
+// The records of the transactions which ran steps are moved to the history.
+func (o *Op) Remove() {
+	if store.Store == nil {
+		return
+	}
+
+	ops := []clientv3.Op{clientv3.OpDelete(opKey(o.TxnID))}
+	if o.StepCount != 0 {
+		if o.State == OpRunning {
+			// Done without the steps being run to the end
+			o.State = OpFailed
+			o.FinishedAt = time.Now()
+		}
+		b, err := json.Marshal(o)
+		if err != nil {
+			log.WithError(err).WithField("txnid", o.TxnID.String()).Warn("failed to marshal transaction record")
+		} else {
+			ops = append(ops, clientv3.OpPut(historyPrefix+o.TxnID.String(), string(b)))
+		}
+	}
+
+	if _, err := store.Txn(context.TODO()).Then(ops...).Commit(); err != nil {
+		log.WithError(err).WithField("txnid", o.TxnID.String()).Warn("failed to remove transaction record")
+	}
+}
+
+// GetOps returns the records of the transactions in progress in the cluster,
+// oldest first
+func GetOps() ([]*Op, error) {
+	resp, err := store.Get(context.TODO(), opPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]*Op, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var o Op
+		if err := json.Unmarshal(kv.Value, &o); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal transaction record")
+			continue
+		}
+		ops = append(ops, &o)
+	}
+
+	sort.Slice(ops, func(i, j int) bool { return ops[i].StartedAt.Before(ops[j].StartedAt) })
+	return ops, nil
+}
+
+// GetHistory returns the records of the completed transactions kept in the
+// history, newest first
+func GetHistory() ([]*Op, error) {
+	resp, err := store.Get(context.TODO(), historyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]*Op, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var o Op
+		if err := json.Unmarshal(kv.Value, &o); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal transaction record")
+			continue
+		}
+		ops = append(ops, &o)
+	}
+
+	sort.Slice(ops, func(i, j int) bool { return ops[i].StartedAt.After(ops[j].StartedAt) })
+	return ops, nil
+}
+
+// GetOp returns the record of the transaction, in progress or completed
+func GetOp(txnID uuid.UUID) (*Op, error) {
+	for _, key := range []string{opKey(txnID), historyPrefix + txnID.String()} {
+		resp, err := store.Get(context.TODO(), key)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Count != 1 {
+			continue
+		}
+		var o Op
+		if err := json.Unmarshal(resp.Kvs[0].Value, &o); err != nil {
+			return nil, err
+		}
+		return &o, nil
+	}
+	return nil, gderrors.ErrTxnNotFound
+}
+
+// PruneHistory deletes the records of the transactions which completed
+// before the given time, and the oldest records beyond max records. The
+// number of records deleted is returned.
+func PruneHistory(before time.Time, max int) (int, error) {
+	ops, err := GetHistory()
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for i, o := range ops {
+		if i < max && !o.FinishedAt.Before(before) {
+			continue
+		}
+		if _, err := store.Delete(context.TODO(), historyPrefix+o.TxnID.String()); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
@@ -11,6 +11,9 @@ import (
 var sfRegistry = struct {
 	sync.RWMutex
 	sfMap map[string]StepFunc
+	// undoMap maps the names of StepFuncs to the names of the StepFuncs
+	// undoing them
+	undoMap map[string]string
 }{}
 
 func registerStepFunc(s StepFunc, name string) {
@@ -41,3 +44,25 @@ func getStepFunc(name string) (StepFunc, bool) {
 	s, ok := sfRegistry.sfMap[name]
 	return s, ok
 }
+
+// RegisterUndoFunc declares the registered StepFunc undoName as the undo of
+// the StepFunc name. The steps running name without an UndoFunc of their own
+// are undone with it when their transaction fails.
+func RegisterUndoFunc(name, undoName string) {
+	sfRegistry.Lock()
+	defer sfRegistry.Unlock()
+
+	if sfRegistry.undoMap == nil {
+		sfRegistry.undoMap = make(map[string]string)
+	}
+	sfRegistry.undoMap[name] = undoName
+}
+
+// getUndoFunc returns the name of the StepFunc declared as the undo of the
+// named StepFunc, if any
+func getUndoFunc(name string) string {
+	sfRegistry.RLock()
+	defer sfRegistry.RUnlock()
+
+	return sfRegistry.undoMap[name]
+}
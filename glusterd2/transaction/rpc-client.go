@@ -6,12 +6,12 @@ import (
 	"errors"
 
 	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/servers/peerrpc"
 	"github.com/gluster/glusterd2/pkg/utils"
 
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 	"go.opencensus.io/plugin/ocgrpc"
-	"go.opencensus.io/trace"
 	"google.golang.org/grpc"
 )
 
@@ -34,12 +34,17 @@ func runStepOn(origCtx context.Context, step string, node uuid.UUID, c TxnCtx) e
 		return err
 	}
 
+	security, err := peerrpc.WithTransportSecurity()
+	if err != nil {
+		logger.WithError(err).Error("failed to load peer SSL/TLS certificates")
+		return err
+	}
+
 	conn, err = grpc.Dial(remote,
-		grpc.WithStatsHandler(&ocgrpc.ClientHandler{
-			StartOptions: trace.StartOptions{
-				Sampler: trace.AlwaysSample(),
-			}}),
-		grpc.WithInsecure(),
+		// Spans are sampled as configured with the tracing options
+		grpc.WithStatsHandler(&ocgrpc.ClientHandler{}),
+		security,
+		peerrpc.WithIdentity(),
 	)
 	if err == nil && conn != nil {
 		logger.WithFields(log.Fields{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/servers/peerrpc"
 
@@ -27,6 +28,8 @@ func (p *txnSvc) RunStep(rpcCtx context.Context, req *TxnStepReq) (*TxnStepResp,
 		err    error
 		ok     bool
 		logger log.FieldLogger
+		span   *trace.Span
+		start  time.Time
 	)
 
 	var ctx Tctx
@@ -39,11 +42,8 @@ func (p *txnSvc) RunStep(rpcCtx context.Context, req *TxnStepReq) (*TxnStepResp,
 	logger.Debug("RunStep request received")
 
 	if rpcCtx != nil {
-		_, span := trace.StartSpan(rpcCtx, req.StepFunc)
-		reqID := ctx.GetTxnReqID()
-		span.AddAttributes(
-			trace.StringAttribute("reqID", reqID),
-		)
+		_, span = trace.StartSpan(rpcCtx, req.StepFunc)
+		span.AddAttributes(spanAttributes(&ctx)...)
 		defer span.End()
 	}
 
@@ -53,15 +53,26 @@ func (p *txnSvc) RunStep(rpcCtx context.Context, req *TxnStepReq) (*TxnStepResp,
 		goto End
 	}
 
+	if err = triggerFailPoint(FailPointName(req.StepFunc, FailPointBefore)); err != nil {
+		goto End
+	}
+
 	logger.Debug("executing step function")
+	start = time.Now()
 	if err = f(&ctx); err != nil {
 		logger.WithError(err).Error("step function failed")
+		observeStep(span, logger, req.StepFunc, time.Since(start), err)
 		goto End
 	}
 
 	if err = ctx.Commit(); err != nil {
 		logger.WithError(err).Error("failed to commit txn context to store")
+		observeStep(span, logger, req.StepFunc, time.Since(start), err)
+		goto End
 	}
+	observeStep(span, logger, req.StepFunc, time.Since(start), nil)
+
+	err = triggerFailPoint(FailPointName(req.StepFunc, FailPointAfterCommit))
 
 End:
 	// Ensure RPC will always send a success reply. Error is stored in
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/pkg/api"
@@ -21,7 +22,8 @@ type StepFunc func(TxnCtx) error
 //
 // DoFunc and UndoFunc are names of StepFuncs registered in the registry
 // DoFunc performs does the action
-// UndoFunc undoes anything done by DoFunc
+// UndoFunc undoes anything done by DoFunc. If not set, the undo declared for
+// DoFunc with RegisterUndoFunc is used.
 type Step struct {
 	DoFunc   string
 	UndoFunc string
@@ -40,14 +42,42 @@ func (s *Step) do(origCtx context.Context, ctx TxnCtx) error {
 	return runStepFuncOnNodes(origCtx, s.DoFunc, ctx, s.Nodes)
 }
 
-// undo runs the UndoFunc on the nodes
-func (s *Step) undo(ctx TxnCtx) error {
+// undoFunc returns the name of the StepFunc undoing the step
+func (s *Step) undoFunc() string {
 	if s.UndoFunc != "" {
-		return runStepFuncOnNodes(context.TODO(), s.UndoFunc, ctx, s.Nodes)
+		return s.UndoFunc
+	}
+	return getUndoFunc(s.DoFunc)
+}
+
+// undo runs the UndoFunc on the given nodes, the nodes the DoFunc succeeded on
+func (s *Step) undo(ctx TxnCtx, nodes []uuid.UUID) error {
+	if undoFunc := s.undoFunc(); undoFunc != "" && len(nodes) != 0 {
+		return runStepFuncOnNodes(context.TODO(), undoFunc, ctx, nodes)
 	}
 	return nil
 }
 
+// committedNodes returns the nodes a step run on the nodes succeeded on,
+// given the error returned by the step
+func committedNodes(nodes []uuid.UUID, err error) []uuid.UUID {
+	if err == nil {
+		return nodes
+	}
+
+	resp, ok := err.(stepResp)
+	if !ok {
+		return nil
+	}
+	var committed []uuid.UUID
+	for _, r := range resp.Resps {
+		if r.Error == nil {
+			committed = append(committed, r.PeerID)
+		}
+	}
+	return committed
+}
+
 // stepPeerResp is response from a single peer that runs a step
 type stepPeerResp struct {
 	PeerID uuid.UUID
@@ -74,9 +104,13 @@ func (r stepResp) Response() api.ErrorResp {
 			continue
 		}
 
+		code := api.ErrTxnStepFailed
+		if _, c := ErrorStatus(resp.Error); c != 0 {
+			code = c
+		}
 		apiResp.Errors = append(apiResp.Errors, api.HTTPError{
-			Code:    int(api.ErrTxnStepFailed),
-			Message: api.ErrorCodeMap[api.ErrTxnStepFailed],
+			Code:    int(code),
+			Message: api.ErrorCodeMap[code],
 			Fields: map[string]string{
 				"peer-id": resp.PeerID.String(),
 				"step":    r.Step,
@@ -88,7 +122,33 @@ func (r stepResp) Response() api.ErrorResp {
 }
 
 func (r stepResp) Status() int {
-	return http.StatusInternalServerError
+	status, _ := r.status()
+	return status
+}
+
+// status returns the HTTP status and API error code of the failures of the
+// step. If the step failed on all nodes for the same well-known reason, like
+// the nodes being offline, the status for the reason is returned.
+func (r stepResp) status() (int, api.ErrorCode) {
+	var (
+		status int
+		code   api.ErrorCode
+	)
+	for _, resp := range r.Resps {
+		if resp.Error == nil {
+			continue
+		}
+		s, c := ErrorStatus(resp.Error)
+		if s == 0 || (status != 0 && s != status) {
+			return http.StatusInternalServerError, api.ErrTxnStepFailed
+		}
+		status, code = s, c
+	}
+
+	if status == 0 {
+		return http.StatusInternalServerError, api.ErrTxnStepFailed
+	}
+	return status, code
 }
 
 func runStepFuncOnNodes(origCtx context.Context, stepName string, ctx TxnCtx, nodes []uuid.UUID) error {
@@ -107,7 +167,7 @@ func runStepFuncOnNodes(origCtx context.Context, stepName string, ctx TxnCtx, no
 
 	resp := stepResp{
 		Step:  stepName,
-		Resps: make([]stepPeerResp, len(nodes)),
+		Resps: make([]stepPeerResp, 0, len(nodes)),
 	}
 
 	var peerResp stepPeerResp
@@ -151,22 +211,28 @@ func RunStepFuncLocally(origCtx context.Context, stepName string, ctx TxnCtx) er
 
 	var err error
 
-	if origCtx != nil {
-		_, span := trace.StartSpan(origCtx, stepName)
-		reqID := ctx.GetTxnReqID()
-		span.AddAttributes(
-			trace.StringAttribute("reqID", reqID),
-		)
-		defer span.End()
+	if origCtx == nil {
+		origCtx = context.Background()
 	}
+	_, span := trace.StartSpan(origCtx, stepName)
+	span.AddAttributes(spanAttributes(ctx)...)
+	defer span.End()
 
 	stepFunc, ok := getStepFunc(stepName)
 	if ok {
+		if err = triggerFailPoint(FailPointName(stepName, FailPointBefore)); err != nil {
+			return err
+		}
+		start := time.Now()
 		if err = stepFunc(ctx); err == nil {
 			// if step function executes successfully, commit the
 			// results to the store
 			err = ctx.Commit()
 		}
+		observeStep(span, ctx.Logger(), stepName, time.Since(start), err)
+		if err == nil {
+			err = triggerFailPoint(FailPointName(stepName, FailPointAfterCommit))
+		}
 	} else {
 		err = ErrStepFuncNotFound
 	}
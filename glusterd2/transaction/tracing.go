@@ -0,0 +1,29 @@
+package transaction
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	"go.opencensus.io/trace"
+)
+
+// spanAttributes returns the attributes identifying the request, transaction,
+// volume and peer of a step run on this peer, with which the spans of the
+// step are tagged
+func spanAttributes(c TxnCtx) []trace.Attribute {
+	attrs := []trace.Attribute{
+		trace.StringAttribute("reqID", c.GetTxnReqID()),
+		trace.StringAttribute("peerID", gdctx.MyUUID.String()),
+	}
+
+	t, ok := c.(*Tctx)
+	if !ok {
+		return attrs
+	}
+	if txnID, ok := t.config.LogFields["txnid"].(string); ok {
+		attrs = append(attrs, trace.StringAttribute("txnID", txnID))
+	}
+	if volname, ok := t.config.LogFields["volume"].(string); ok {
+		attrs = append(attrs, trace.StringAttribute("volume", volname))
+	}
+	return attrs
+}
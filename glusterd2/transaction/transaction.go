@@ -4,7 +4,7 @@ package transaction
 import (
 	"context"
 	"expvar"
-	"fmt"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/store"
@@ -12,7 +12,6 @@ import (
 	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/clientv3/concurrency"
 	"github.com/pborman/uuid"
-	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 )
@@ -29,6 +28,7 @@ type Txn struct {
 	locks       Locks
 	reqID       uuid.UUID
 	storePrefix string
+	op          *Op
 
 	Ctx             TxnCtx
 	Steps           []*Step
@@ -39,6 +39,10 @@ type Txn struct {
 	// nodes before running the transaction steps.
 	Nodes   []uuid.UUID
 	OrigCtx context.Context
+
+	// committed holds the nodes each step succeeded on, which are the
+	// nodes the step is undone on if the transaction fails
+	committed [][]uuid.UUID
 }
 
 // NewTxn returns an initialized Txn without any steps
@@ -47,18 +51,19 @@ func NewTxn(ctx context.Context) *Txn {
 
 	t.id = uuid.NewRandom()
 	t.reqID = gdctx.GetReqID(ctx)
+	gdctx.RecordTxnID(ctx, t.id)
 	t.locks = make(map[string]*concurrency.Mutex)
 	t.storePrefix = txnPrefix + t.id.String() + "/"
+	// The log fields are carried over to the other peers in the txn
+	ctx = gdctx.WithTxnID(ctx, t.id)
 	config := &TxnCtxConfig{
-		LogFields: log.Fields{
-			"txnid": t.id.String(),
-			"reqid": t.reqID.String(),
-		},
+		LogFields:   gdctx.LogFields(ctx),
 		StorePrefix: t.storePrefix,
 	}
 	t.Ctx = newCtx(config)
 
 	t.OrigCtx = ctx
+	t.op = NewOp(ctx, t.id, nil)
 	t.Ctx.Logger().Debug("new transaction created")
 	return t
 }
@@ -80,6 +85,11 @@ func NewTxnWithLocks(ctx context.Context, lockIDs ...string) (*Txn, error) {
 		logger.Debug("lock obtained")
 	}
 
+	// Transactions holding locks are recorded right away, as they can
+	// block others before running any step
+	t.op.Locks = lockIDs
+	t.op.Save()
+
 	return t, nil
 }
 
@@ -91,6 +101,8 @@ func (t *Txn) Done() {
 		locker.Unlock(context.Background())
 	}
 
+	t.op.Remove()
+
 	// Wipe txn namespace
 	if _, err := store.Delete(context.TODO(), t.storePrefix, clientv3.WithPrefix()); err != nil {
 		t.Ctx.Logger().WithError(err).WithField("key",
@@ -112,7 +124,7 @@ func (t *Txn) checkAlive() error {
 	for _, node := range t.Nodes {
 		// TODO: Using prefixed query, get all alive nodes in a single etcd query
 		if _, online := store.Store.IsNodeAlive(node); !online {
-			return fmt.Errorf("node %s is probably down", node.String())
+			return &NodeOfflineError{Node: node}
 		}
 	}
 
@@ -120,7 +132,10 @@ func (t *Txn) checkAlive() error {
 }
 
 // Do runs the transaction on the cluster
-func (t *Txn) Do() error {
+func (t *Txn) Do() (err error) {
+	start := time.Now()
+	defer func() { observeTxn(start, err) }()
+
 	if !t.DontCheckAlive {
 		if err := t.checkAlive(); err != nil {
 			return err
@@ -135,12 +150,20 @@ func (t *Txn) Do() error {
 		return err
 	}
 
+	t.op.Start(t.Steps)
+	defer func() { t.op.Finish(err) }()
+	t.committed = make([][]uuid.UUID, len(t.Steps))
 	for i, s := range t.Steps {
 		if s.Skip {
 			continue
 		}
 
-		if err := s.do(t.OrigCtx, t.Ctx); err != nil {
+		t.op.SetStep(i, s)
+
+		err := s.do(t.OrigCtx, t.Ctx)
+		t.committed[i] = committedNodes(s.Nodes, err)
+		t.op.StepDone(i, err)
+		if err != nil {
 			if t.DontCheckAlive && isNodeUnreachable(err) {
 				continue
 			}
@@ -159,9 +182,9 @@ func (t *Txn) Do() error {
 
 func isNodeUnreachable(err error) bool {
 	unreachable := true
-	if s, ok := err.(*stepResp); ok {
+	if s, ok := err.(stepResp); ok {
 		for _, e := range s.Resps {
-			if grpc.Code(e.Error) != codes.Unavailable {
+			if e.Error != nil && grpc.Code(e.Error) != codes.Unavailable {
 				unreachable = false
 			}
 		}
@@ -169,15 +192,31 @@ func isNodeUnreachable(err error) bool {
 	return unreachable
 }
 
-// undo undoes a transaction and will be automatically called by Perform if any step fails.
-// The Steps are undone in the reverse order, from the failed step.
+// undo undoes a transaction and will be automatically called by Do if any step fails.
+// The Steps are undone in the reverse order, from the failed step, on the
+// nodes they succeeded on. The outcome is recorded in the transaction record.
 func (t *Txn) undo(n int) {
+	t.op.StartRollback()
+
+	failed := 0
 	for i := n; i >= 0; i-- {
-		if t.Steps[i].Skip {
+		s := t.Steps[i]
+		if s.Skip || s.undoFunc() == "" || len(t.committed[i]) == 0 {
 			continue
 		}
-		t.Steps[i].undo(t.Ctx)
+
+		err := s.undo(t.Ctx, t.committed[i])
+		t.op.AddRollback(s.undoFunc(), t.committed[i], err)
+		if err != nil {
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		t.Ctx.Logger().WithField("failed-steps", failed).Error("Transaction rollback failed, changes may be left behind on the nodes")
+		return
 	}
+	t.Ctx.Logger().Info("Transaction rolled back")
 }
 
 // nodesUnion removes duplicate nodes
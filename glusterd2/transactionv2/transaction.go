@@ -41,6 +41,7 @@ type Txn struct {
 	success   chan struct{}
 	error     chan error
 	succeeded bool
+	op        *transaction.Op
 }
 
 // NewTxn returns an initialized Txn without any steps
@@ -51,14 +52,13 @@ func NewTxn(ctx context.Context) *Txn {
 	t.ReqID = gdctx.GetReqID(ctx)
 	t.locks = transaction.Locks{}
 	t.StorePrefix = txnPrefix + t.ID.String() + "/"
+	// The log fields are carried over to the other peers in the txn
 	config := &transaction.TxnCtxConfig{
-		LogFields: log.Fields{
-			"txnid": t.ID.String(),
-			"reqid": t.ReqID.String(),
-		},
+		LogFields:   gdctx.LogFields(gdctx.WithTxnID(ctx, t.ID)),
 		StorePrefix: t.StorePrefix,
 	}
 	t.Ctx = transaction.NewCtx(config)
+	t.op = transaction.NewOp(ctx, t.ID, nil)
 	t.Ctx.Logger().Debug("new transaction created")
 	return t
 }
@@ -67,8 +67,15 @@ func NewTxn(ctx context.Context) *Txn {
 func NewTxnWithLocks(ctx context.Context, lockIDs ...string) (*Txn, error) {
 	t := NewTxn(ctx)
 	t.locks = transaction.Locks{}
-	err := t.acquireClusterLocks(lockIDs...)
-	return t, err
+	if err := t.acquireClusterLocks(lockIDs...); err != nil {
+		return t, err
+	}
+
+	// Transactions holding locks are recorded right away, as they can
+	// block others before running any step
+	t.op.Locks = lockIDs
+	t.op.Save()
+	return t, nil
 }
 
 func (t *Txn) acquireClusterLocks(lockIDs ...string) error {
@@ -98,6 +105,7 @@ func (t *Txn) releaseLocks() {
 // Done must be called after a transaction ends
 func (t *Txn) Done() {
 	defer t.releaseLocks()
+	t.op.Remove()
 
 	if !t.succeeded {
 		return
@@ -119,7 +127,7 @@ func (t *Txn) checkAlive() error {
 	for _, node := range t.Nodes {
 		// TODO: Using prefixed query, get all alive nodes in a single etcd query
 		if _, online := store.Store.IsNodeAlive(node); !online {
-			return fmt.Errorf("node %s is probably down", node.String())
+			return &transaction.NodeOfflineError{Node: node}
 		}
 	}
 	return nil
@@ -165,6 +173,10 @@ func (t *Txn) Do() error {
 		return err
 	}
 
+	// The steps are run by the engines of the peers, which only record
+	// the last step executed on them
+	t.op.Start(t.Steps)
+
 	t.Ctx.Logger().Debug("adding txn to store")
 	if err := GlobalTxnManager.AddTxn(t); err != nil {
 		return err
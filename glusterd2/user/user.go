@@ -0,0 +1,123 @@
+// Package user maintains the users of the REST API and their roles
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const userPrefix = "config/users/"
+
+// userNameRE matches valid user names, which are used in store keys
+var userNameRE = regexp.MustCompile("^[a-zA-Z0-9_.@-]+$")
+
+// reservedNames are the token issuers used by glusterd2 itself
+var reservedNames = map[string]bool{
+	"glustercli":        true,
+	"glusterd2-forward": true,
+}
+
+// Validate checks the user
+func Validate(u *api.User) error {
+	if !userNameRE.MatchString(u.Name) {
+		return errors.New("invalid user name")
+	}
+	if reservedNames[u.Name] {
+		return fmt.Errorf("user name %s is reserved", u.Name)
+	}
+
+	switch u.Role {
+	case api.RoleAdmin, api.RoleOperator, api.RoleReadOnly:
+	default:
+		return fmt.Errorf("invalid role %s, must be one of %s, %s or %s",
+			u.Role, api.RoleAdmin, api.RoleOperator, api.RoleReadOnly)
+	}
+
+	if u.Role == api.RoleAdmin && len(u.Volumes) != 0 {
+		return errors.New("admin users can't be limited to volumes")
+	}
+	return nil
+}
+
+// Add adds the user to the store, replacing an existing user with the same
+// name
+func Add(u *api.User) error {
+	data, err := store.Marshal(u)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), userPrefix+u.Name, string(data))
+	return err
+}
+
+// Get returns the user
+func Get(name string) (*api.User, error) {
+	resp, err := store.Get(context.TODO(), userPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrUserNotFound
+	}
+
+	var u api.User
+	if err := store.Unmarshal(resp.Kvs[0].Value, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Delete deletes the user from the store
+func Delete(name string) error {
+	resp, err := store.Delete(context.TODO(), userPrefix+name)
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return gderrors.ErrUserNotFound
+	}
+	return nil
+}
+
+// List returns the users sorted by name
+func List() ([]*api.User, error) {
+	resp, err := store.Get(context.TODO(), userPrefix, clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*api.User, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var u api.User
+		if err := store.Unmarshal(kv.Value, &u); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal user")
+			continue
+		}
+		users = append(users, &u)
+	}
+	return users, nil
+}
+
+// CanAccessVolume returns true if the user isn't limited to some volumes, or
+// the volume is one of them
+func CanAccessVolume(u *api.User, volname string) bool {
+	if len(u.Volumes) == 0 {
+		return true
+	}
+	for _, v := range u.Volumes {
+		if v == volname {
+			return true
+		}
+	}
+	return false
+}
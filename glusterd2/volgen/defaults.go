@@ -53,17 +53,40 @@ func init() {
 		Xlators: []Xlator{
 			{
 				Type: "protocol/server",
+				// Client access control lists of the volume
+				Options: map[string]string{
+					"auth.addr.{{ brick.path }}.allow":      "{{ volume.acl.allow }}",
+					"auth.addr.{{ brick.path }}.reject":     "{{ volume.acl.reject }}",
+					"auth.login.{{ brick.path }}.ssl-allow": "{{ volume.acl.ssl-allow }}",
+				},
 			},
 			{
 				Type:     "debug/io-stats",
 				NameTmpl: "{{ brick.path }}",
 			},
+			{
+				// Enforces the quota limits. Quota is turned on
+				// and off with the server-quota option, without
+				// changing the graph.
+				Type: "features/quota",
+				Options: map[string]string{
+					"volume-uuid": "{{ volume.name }}",
+				},
+			},
 			{
 				Type: "features/index",
 			},
 			{
 				Type: "features/barrier",
 			},
+			{
+				// Accounts the usage of directories when the
+				// quota and inode-quota options are on
+				Type: "features/marker",
+				Options: map[string]string{
+					"volume-uuid": "{{ volume.id }}",
+				},
+			},
 			{
 				Type: "performance/io-threads",
 			},
@@ -163,6 +186,81 @@ func init() {
 		},
 	}
 
+	// default gfproxy daemon template. The gfproxy daemon loads the client
+	// graph of the volume and exports it to thin clients.
+	tmpls[utils.GfProxyVolfile] = Template{
+		Name:  utils.GfProxyVolfile,
+		Level: VolfileLevelVolume,
+		Xlators: []Xlator{
+			{
+				Type: "protocol/server",
+				Options: map[string]string{
+					"auth.addr.gfproxyd-{{ volume.name }}.allow":     "*",
+					"auth.login.gfproxyd-{{ volume.name }}.allow":    "{{ volume.auth.username }}",
+					"auth.login.{{ volume.auth.username }}.password": "{{ volume.auth.password }}",
+				},
+			},
+			{
+				Type:     "debug/io-stats",
+				NameTmpl: "gfproxyd-{{ volume.name }}",
+			},
+			{
+				Type: "performance/io-threads",
+			},
+			{
+				Type: "performance/md-cache",
+			},
+			{
+				Type: "performance/open-behind",
+			},
+			{
+				Type: "performance/quick-read",
+			},
+			{
+				Type: "performance/io-cache",
+			},
+			{
+				Type: "performance/readdir-ahead",
+			},
+			{
+				Type: "performance/read-ahead",
+			},
+			{
+				Type: "performance/write-behind",
+			},
+			{
+				Type:           "features/read-only",
+				Disabled:       true,
+				EnableByOption: true,
+			},
+			{
+				Type: "features/utime",
+			},
+			{
+				Type:     "features/shard",
+				Disabled: true,
+			},
+			{
+				Type: "cluster/distribute",
+			},
+		},
+		SubvolGraphXlators: []Xlator{
+			{
+				NameTmpl: "{{ subvol.name }}",
+				TypeTmpl: "cluster/{{ subvol.type }}",
+				Options: map[string]string{
+					"afr-pending-xattr": "{{ subvol.afr-pending-xattr }}",
+				},
+			},
+		},
+		BrickGraphXlators: []Xlator{
+			{
+				Type:     "protocol/client",
+				NameTmpl: "{{ subvol.name }}-client-{{ brick.index }}",
+			},
+		},
+	}
+
 	// default rebalance template
 	tmpls[utils.RebalanceVolfile] = Template{
 		Name:  utils.RebalanceVolfile,
@@ -288,5 +386,40 @@ func init() {
 		},
 	}
 
+	// The quota plugin generates the quotad volfile with only the volumes
+	// which have quota enabled
+	tmpls[utils.QuotadVolfile] = Template{
+		Name:  utils.QuotadVolfile,
+		Level: VolfileLevelCluster,
+		Xlators: []Xlator{
+			{
+				Type:     "features/quotad",
+				NameTmpl: "quotad",
+				VolumeOptions: map[string]string{
+					"{{ volume.name }}.volume-id": "{{ volume.name }}",
+				},
+			},
+		},
+		VolumeGraphXlators: []Xlator{
+			{
+				Type:     "cluster/distribute",
+				NameTmpl: "{{ volume.name }}",
+			},
+		},
+		SubvolGraphXlators: []Xlator{
+			{
+				TypeTmpl: "cluster/{{ subvol.type }}",
+				Options: map[string]string{
+					"afr-pending-xattr": "{{ subvol.afr-pending-xattr }}",
+				},
+			},
+		},
+		BrickGraphXlators: []Xlator{
+			{
+				Type: "protocol/client",
+			},
+		},
+	}
+
 	namespaces[DefaultTemplateNamespace] = tmpls
 }
@@ -82,6 +82,11 @@ type Xlator struct {
 	// generated Volfile. For example, bitd and scrubd both uses same xlator, if
 	// scrubber = on, then it becomes scrub daemon else it becomes bitd.
 	IgnoreOptions []string `json:"ignore-options"`
+	// VolumeOptions are added to the xlator once for every volume in a
+	// cluster level volfile, with the volume variables replaced. For
+	// example, quotad finds the graph of a volume by the
+	// "{{ volume.name }}.volume-id" option.
+	VolumeOptions map[string]string `json:"volume-options"`
 }
 
 // Templates represents collection of volfile templates
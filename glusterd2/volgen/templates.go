@@ -1,6 +1,8 @@
 package volgen
 
 import (
+	"sort"
+
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	gderrors "github.com/gluster/glusterd2/pkg/errors"
@@ -154,3 +156,44 @@ func (tmpl *Template) IsXlatorSupported(xlatorname string) bool {
 	}
 	return false
 }
+
+// EnabledXlatorTypes returns the types of all the xlators enabled in the
+// volfiles generated from the template for the volume, like
+// "cluster/replicate"
+func (tmpl *Template) EnabledXlatorTypes(volinfo *volume.Volinfo) ([]string, error) {
+	types := make(map[string]bool)
+	add := func(xlist []Xlator, err error) error {
+		if err != nil {
+			return err
+		}
+		for _, xl := range xlist {
+			types[xl.Type] = true
+		}
+		return nil
+	}
+
+	if err := add(tmpl.EnabledXlators(volinfo)); err != nil {
+		return nil, err
+	}
+	if err := add(tmpl.EnabledVolumeGraphXlators(volinfo)); err != nil {
+		return nil, err
+	}
+	for sidx := range volinfo.Subvols {
+		sv := &volinfo.Subvols[sidx]
+		if err := add(tmpl.EnabledSubvolGraphXlators(volinfo, sv)); err != nil {
+			return nil, err
+		}
+		for bidx := range sv.Bricks {
+			if err := add(tmpl.EnabledBrickGraphXlators(volinfo, sv, &sv.Bricks[bidx])); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	list := make([]string, 0, len(types))
+	for t := range types {
+		list = append(list, t)
+	}
+	sort.Strings(list)
+	return list, nil
+}
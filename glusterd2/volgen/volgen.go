@@ -368,6 +368,28 @@ func VolumeLevelVolfile(tmpl *Template, volinfo *volume.Volinfo) (string, error)
 	return volfile.Generate()
 }
 
+// addVolumeOptions adds the per volume options of the global xlators of a
+// cluster level volfile for a volume
+func addVolumeOptions(entries []*Entry, varStrData map[string]string) error {
+	for _, e := range entries {
+		if e.XlatorData.Options == nil {
+			e.XlatorData.Options = make(map[string]string)
+		}
+		for k, v := range e.XlatorData.VolumeOptions {
+			key, err := varStrReplace(k, varStrData)
+			if err != nil {
+				return err
+			}
+			val, err := varStrReplace(v, varStrData)
+			if err != nil {
+				return err
+			}
+			e.XlatorData.Options[key] = val
+		}
+	}
+	return nil
+}
+
 // ClusterLevelVolfile generates cluster level volfile
 func ClusterLevelVolfile(tmpl *Template, clusterinfo []*volume.Volinfo) (string, error) {
 	// Xlators list from template
@@ -379,8 +401,12 @@ func ClusterLevelVolfile(tmpl *Template, clusterinfo []*volume.Volinfo) (string,
 	entry := &volfile.RootEntry
 
 	// Global Xlators list
+	var volOptEntries []*Entry
 	for _, xl := range xlators {
 		entry = entry.Add(xl, nil)
+		if len(xl.VolumeOptions) > 0 {
+			volOptEntries = append(volOptEntries, entry)
+		}
 	}
 
 	for _, volinfo := range clusterinfo {
@@ -396,6 +422,10 @@ func ClusterLevelVolfile(tmpl *Template, clusterinfo []*volume.Volinfo) (string,
 			return "", err
 		}
 
+		if err := addVolumeOptions(volOptEntries, varStrData); err != nil {
+			return "", err
+		}
+
 		ventry := entry
 		for _, xl := range volumeXlators {
 			ventry = ventry.Add(xl, varStrData)
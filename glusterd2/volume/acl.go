@@ -0,0 +1,81 @@
+package volume
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+var (
+	// addrPatternRE matches IPv4 address patterns with wildcards as
+	// understood by the bricks, eg. 192.168.*.*
+	addrPatternRE = regexp.MustCompile(`^[0-9*]{1,3}(\.[0-9*]{1,3}){0,3}$`)
+	hostnameRE    = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+)
+
+// VolACL represents the access control lists for the clients of a volume
+type VolACL struct {
+	// Allow and Reject are lists of client addresses. An address may be
+	// an IP address, a CIDR network, an IPv4 address pattern with
+	// wildcards or a hostname.
+	Allow  []string
+	Reject []string
+	// SSLAllow is the list of TLS identities (certificate common names)
+	// allowed to connect when TLS is enabled for the volume
+	SSLAllow []string
+}
+
+// IsEmpty returns true if no ACL entries are set
+func (a *VolACL) IsEmpty() bool {
+	return len(a.Allow) == 0 && len(a.Reject) == 0 && len(a.SSLAllow) == 0
+}
+
+func validateACLAddr(addr string) error {
+	if addr == "*" || net.ParseIP(addr) != nil {
+		return nil
+	}
+	if _, _, err := net.ParseCIDR(addr); err == nil {
+		return nil
+	}
+	if addrPatternRE.MatchString(addr) || hostnameRE.MatchString(addr) {
+		return nil
+	}
+	return fmt.Errorf("invalid client address %q in ACL", addr)
+}
+
+// Validate validates the entries of the ACL
+func (a *VolACL) Validate() error {
+	for _, list := range [][]string{a.Allow, a.Reject} {
+		for _, addr := range list {
+			if err := validateACLAddr(addr); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, id := range a.SSLAllow {
+		if strings.TrimSpace(id) == "" || strings.ContainsAny(id, ",\n") {
+			return fmt.Errorf("invalid TLS identity %q in ACL", id)
+		}
+	}
+
+	return nil
+}
+
+// aclStringMap returns the ACL as options understood by the bricks. All
+// clients are allowed if no allow list is set.
+func (a *VolACL) aclStringMap() map[string]string {
+	m := map[string]string{
+		"volume.acl.allow":     "*",
+		"volume.acl.reject":    strings.Join(a.Reject, ","),
+		"volume.acl.ssl-allow": "*",
+	}
+	if len(a.Allow) != 0 {
+		m["volume.acl.allow"] = strings.Join(a.Allow, ",")
+	}
+	if len(a.SSLAllow) != 0 {
+		m["volume.acl.ssl-allow"] = strings.Join(a.SSLAllow, ",")
+	}
+	return m
+}
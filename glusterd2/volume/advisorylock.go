@@ -0,0 +1,155 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// advisoryLockPrefix holds the advisory locks on the volumes, keyed by the
+// volume name and lock name
+const advisoryLockPrefix = "volume-advisorylocks/"
+
+// AdvisoryLockHeldError is returned when an advisory lock on a volume is held
+// by a different holder
+type AdvisoryLockHeldError struct {
+	Volume string
+	Lock   api.VolAdvisoryLock
+}
+
+func (e *AdvisoryLockHeldError) Error() string {
+	return fmt.Sprintf("volume %s is locked by %s (lock %s, expires %s)",
+		e.Volume, e.Lock.Holder, e.Lock.Name, e.Lock.Expires.Format(time.RFC3339))
+}
+
+func advisoryLockKey(volname, name string) string {
+	return advisoryLockPrefix + volname + "/" + name
+}
+
+// AcquireAdvisoryLock takes the named advisory lock on the volume for the
+// holder. The lock is removed from the store once the ttl expires. Taking a
+// lock already held by the same holder renews it. An AdvisoryLockHeldError is
+// returned if the lock is held by a different holder.
+func AcquireAdvisoryLock(volname, name, holder string, ttl time.Duration) (*api.VolAdvisoryLock, error) {
+	key := advisoryLockKey(volname, name)
+
+	lease, err := store.Store.Grant(context.TODO(), int64(ttl/time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &api.VolAdvisoryLock{
+		Name:    name,
+		Holder:  holder,
+		Expires: time.Now().Add(ttl),
+	}
+	b, err := json.Marshal(lock)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(b), clientv3.WithLease(lease.ID))).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		return nil, err
+	}
+	if resp.Succeeded {
+		return lock, nil
+	}
+
+	// The lock exists, renew it if it is held by the same holder
+	kv := resp.Responses[0].GetResponseRange().Kvs[0]
+	var existing api.VolAdvisoryLock
+	if err := json.Unmarshal(kv.Value, &existing); err != nil {
+		return nil, err
+	}
+	if existing.Holder != holder {
+		store.Store.Revoke(context.TODO(), lease.ID)
+		return nil, &AdvisoryLockHeldError{Volume: volname, Lock: existing}
+	}
+
+	resp, err = store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", kv.ModRevision)).
+		Then(clientv3.OpPut(key, string(b), clientv3.WithLease(lease.ID))).
+		Commit()
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Succeeded {
+		store.Store.Revoke(context.TODO(), lease.ID)
+		return nil, fmt.Errorf("advisory lock %s on volume %s was changed concurrently", name, volname)
+	}
+	if kv.Lease != 0 {
+		store.Store.Revoke(context.TODO(), clientv3.LeaseID(kv.Lease))
+	}
+
+	return lock, nil
+}
+
+// GetAdvisoryLocks returns the advisory locks held on the volume, sorted by
+// name
+func GetAdvisoryLocks(volname string) ([]api.VolAdvisoryLock, error) {
+	resp, err := store.Get(context.TODO(), advisoryLockPrefix+volname+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	locks := make([]api.VolAdvisoryLock, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var l api.VolAdvisoryLock
+		if err := json.Unmarshal(kv.Value, &l); err != nil {
+			return nil, err
+		}
+		locks = append(locks, l)
+	}
+	sort.Slice(locks, func(i, j int) bool { return locks[i].Name < locks[j].Name })
+
+	return locks, nil
+}
+
+// ReleaseAdvisoryLock releases the named advisory lock on the volume. If
+// holder is not empty, the lock is released only if it is held by the
+// holder. Releasing a lock which isn't held is not an error.
+func ReleaseAdvisoryLock(volname, name, holder string) error {
+	key := advisoryLockKey(volname, name)
+
+	resp, err := store.Get(context.TODO(), key)
+	if err != nil || resp.Count != 1 {
+		return err
+	}
+
+	var l api.VolAdvisoryLock
+	if err := json.Unmarshal(resp.Kvs[0].Value, &l); err != nil {
+		return err
+	}
+	if holder != "" && l.Holder != holder {
+		return &AdvisoryLockHeldError{Volume: volname, Lock: l}
+	}
+
+	_, err = store.Delete(context.TODO(), key)
+	return err
+}
+
+// CheckAdvisoryLocks returns an AdvisoryLockHeldError if the volume has any
+// advisory lock. It is called before performing disruptive operations on the
+// volume.
+func CheckAdvisoryLocks(volname string) error {
+	locks, err := GetAdvisoryLocks(volname)
+	if err != nil {
+		return err
+	}
+	if len(locks) != 0 {
+		return &AdvisoryLockHeldError{Volume: volname, Lock: locks[0]}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+const (
+	// autoExpandPrefix holds the auto expansion policy and state of the
+	// volumes, keyed by the volume name
+	autoExpandPrefix = "volume-autoexpand/"
+	// MaxUsageSamples is the number of usage samples retained per volume
+	MaxUsageSamples = 12
+)
+
+// AddUsageSample appends a usage sample to the auto expansion state,
+// retaining only the latest MaxUsageSamples samples
+func AddUsageSample(s *api.VolAutoExpandResp, sample api.VolUsageSample) {
+	s.Samples = append(s.Samples, sample)
+	if len(s.Samples) > MaxUsageSamples {
+		s.Samples = s.Samples[len(s.Samples)-MaxUsageSamples:]
+	}
+}
+
+// SetAutoExpandPolicy saves the auto expansion policy and state of the volume
+func SetAutoExpandPolicy(volname string, s *api.VolAutoExpandResp) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), autoExpandPrefix+volname, string(b))
+	return err
+}
+
+// GetAutoExpandPolicy returns the auto expansion policy and state of the volume
+func GetAutoExpandPolicy(volname string) (*api.VolAutoExpandResp, error) {
+	resp, err := store.Get(context.TODO(), autoExpandPrefix+volname)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, gderrors.ErrAutoExpandPolicyNotFound
+	}
+
+	var s api.VolAutoExpandResp
+	if err := json.Unmarshal(resp.Kvs[0].Value, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// GetAutoExpandPolicies returns the auto expansion policies of all volumes,
+// keyed by volume name
+func GetAutoExpandPolicies() (map[string]*api.VolAutoExpandResp, error) {
+	resp, err := store.Get(context.TODO(), autoExpandPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]*api.VolAutoExpandResp, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var s api.VolAutoExpandResp
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			return nil, err
+		}
+		policies[string(kv.Key)[len(autoExpandPrefix):]] = &s
+	}
+
+	return policies, nil
+}
+
+// DeleteAutoExpandPolicy deletes the auto expansion policy of the volume
+func DeleteAutoExpandPolicy(volname string) error {
+	_, err := store.Delete(context.TODO(), autoExpandPrefix+volname)
+	return err
+}
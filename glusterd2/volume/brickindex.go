@@ -0,0 +1,284 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderror "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// brickIndexPrefix indexes the IDs of the volumes by their bricks, as
+	// bricks/<peer-id><brick-path>, brick paths being absolute. The index
+	// entries of the bricks of a volume are always updated together with
+	// its volinfo in store transactions, so bricks can be looked up without
+	// going through all the volinfos.
+	brickIndexPrefix = "bricks/"
+
+	// maxBrickIndexGets is the number of index lookups batched in a store
+	// transaction, well within the limit of operations etcd allows in one
+	maxBrickIndexGets = 64
+)
+
+func brickIndexKey(peerID uuid.UUID, path string) string {
+	return brickIndexPrefix + peerID.String() + path
+}
+
+// parseBrickIndexKey returns the peer ID and the brick path of the index key
+func parseBrickIndexKey(key string) (uuid.UUID, string) {
+	key = strings.TrimPrefix(key, brickIndexPrefix)
+	if len(key) < 36 {
+		return nil, ""
+	}
+	return uuid.Parse(key[:36]), key[36:]
+}
+
+// brickIndexChanges returns the compares and the operations to update the
+// brick index, for the bricks of the volume changing from old to new. The
+// compares fail if any of the bricks added is already indexed, which is the
+// case if it is a brick of another volume. Index entries of the bricks
+// removed are only deleted if they still are of the volume.
+func brickIndexChanges(volID uuid.UUID, old, new []brick.Brickinfo) ([]clientv3.Cmp, []clientv3.Op) {
+	oldKeys := make(map[string]bool, len(old))
+	for _, b := range old {
+		oldKeys[brickIndexKey(b.PeerID, b.Path)] = true
+	}
+	newKeys := make(map[string]bool, len(new))
+	for _, b := range new {
+		newKeys[brickIndexKey(b.PeerID, b.Path)] = true
+	}
+
+	var (
+		cmps []clientv3.Cmp
+		ops  []clientv3.Op
+	)
+	for _, b := range new {
+		key := brickIndexKey(b.PeerID, b.Path)
+		if oldKeys[key] {
+			continue
+		}
+		cmps = append(cmps, clientv3.Compare(clientv3.CreateRevision(key), "=", 0))
+		ops = append(ops, clientv3.OpPut(key, volID.String()))
+	}
+	for _, b := range old {
+		key := brickIndexKey(b.PeerID, b.Path)
+		if newKeys[key] {
+			continue
+		}
+		ops = append(ops, clientv3.OpTxn(
+			[]clientv3.Cmp{clientv3.Compare(clientv3.Value(key), "=", volID.String())},
+			[]clientv3.Op{clientv3.OpDelete(key)},
+			nil))
+	}
+	return cmps, ops
+}
+
+// getIndexedBricks returns the bricks in the index under the key, looking up
+// the volinfos of only the volumes the bricks are of
+func getIndexedBricks(key string, opts ...clientv3.OpOption) ([]brick.Brickinfo, error) {
+	resp, err := store.Get(context.TODO(), key, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[string]map[string]brick.Brickinfo)
+	var bricks []brick.Brickinfo
+	for _, kv := range resp.Kvs {
+		id := string(kv.Value)
+		volBricks, ok := volumes[id]
+		if !ok {
+			v, err := GetVolumeByID(uuid.Parse(id))
+			if err == gderror.ErrVolNotFound {
+				log.WithField("key", string(kv.Key)).Warn("brick index entry of a volume which does not exist")
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			volBricks = make(map[string]brick.Brickinfo)
+			for _, b := range v.GetBricks() {
+				volBricks[brickIndexKey(b.PeerID, b.Path)] = b
+			}
+			volumes[id] = volBricks
+		}
+		if b, ok := volBricks[string(kv.Key)]; ok {
+			bricks = append(bricks, b)
+		}
+	}
+	return bricks, nil
+}
+
+// GetBricksByPeer returns the bricks of all the volumes on the peer
+func GetBricksByPeer(peerID uuid.UUID) ([]brick.Brickinfo, error) {
+	return getIndexedBricks(brickIndexKey(peerID, ""), clientv3.WithPrefix())
+}
+
+// GetBricksByPathPrefix returns the bricks of all the volumes on the peer,
+// whose paths start with the prefix
+func GetBricksByPathPrefix(peerID uuid.UUID, prefix string) ([]brick.Brickinfo, error) {
+	return getIndexedBricks(brickIndexKey(peerID, prefix), clientv3.WithPrefix())
+}
+
+// pathsConflict returns true if the brick paths are the same, or one of them
+// is nested in the other
+func pathsConflict(a, b string) bool {
+	a, b = strings.TrimSuffix(a, "/"), strings.TrimSuffix(b, "/")
+	return a == b || strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
+
+// parentPaths returns the paths of the directories the path is nested in,
+// closest first
+func parentPaths(path string) []string {
+	var parents []string
+	path = strings.TrimSuffix(path, "/")
+	for i := strings.LastIndex(path, "/"); i > 0; i = strings.LastIndex(path, "/") {
+		path = path[:i]
+		parents = append(parents, path)
+	}
+	return parents
+}
+
+// CheckBrickPathConflicts makes sure none of the bricks is a brick of a
+// volume already, or is nested in or contains a brick of a volume, and that
+// the bricks don't conflict among themselves either. Bricks are looked up in
+// the brick index, without going through all the volinfos.
+func CheckBrickPathConflicts(bricks []brick.Brickinfo) error {
+	for i, a := range bricks {
+		for _, b := range bricks[i+1:] {
+			if uuid.Equal(a.PeerID, b.PeerID) && pathsConflict(a.Path, b.Path) {
+				return fmt.Errorf("brick path %s conflicts with brick path %s on the same peer", a.Path, b.Path)
+			}
+		}
+	}
+
+	var (
+		ops      []clientv3.Op
+		opBricks []brick.Brickinfo
+	)
+	for _, b := range bricks {
+		path := strings.TrimSuffix(b.Path, "/")
+		for _, p := range append([]string{path}, parentPaths(path)...) {
+			ops = append(ops, clientv3.OpGet(brickIndexKey(b.PeerID, p)))
+			opBricks = append(opBricks, b)
+		}
+		ops = append(ops, clientv3.OpGet(brickIndexKey(b.PeerID, path+"/"), clientv3.WithPrefix(), clientv3.WithLimit(1)))
+		opBricks = append(opBricks, b)
+	}
+
+	for len(ops) > 0 {
+		n := len(ops)
+		if n > maxBrickIndexGets {
+			n = maxBrickIndexGets
+		}
+		resp, err := store.Txn(context.TODO()).Then(ops[:n]...).Commit()
+		if err != nil {
+			return err
+		}
+		for i, r := range resp.Responses {
+			kvs := r.GetResponseRange().Kvs
+			if len(kvs) == 0 {
+				continue
+			}
+			_, path := parseBrickIndexKey(string(kvs[0].Key))
+			volname := string(kvs[0].Value)
+			if v, err := GetVolumeByID(uuid.Parse(volname)); err == nil {
+				volname = v.Name
+			}
+			return fmt.Errorf("brick path %s conflicts with brick path %s of volume %s on the same peer",
+				opBricks[i].Path, path, volname)
+		}
+		ops, opBricks = ops[n:], opBricks[n:]
+	}
+	return nil
+}
+
+// SyncBrickIndex brings the brick index in line with the volinfos, dropping
+// stale entries and indexing the bricks of volumes stored before there was
+// an index. Should be called after MigrateStore, and like it can be run by
+// several peers at once.
+func SyncBrickIndex() error {
+	resp, err := store.Get(context.TODO(), brickIndexPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	volumes, err := GetVolumes(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	owners := make(map[string][]string)
+	for _, v := range volumes {
+		for _, b := range v.GetBricks() {
+			key := brickIndexKey(b.PeerID, b.Path)
+			owners[key] = append(owners[key], v.ID.String())
+		}
+	}
+
+	indexed := make(map[string]bool, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		key := string(kv.Key)
+		if containsString(owners[key], string(kv.Value)) {
+			indexed[key] = true
+			continue
+		}
+
+		// Left alone if the entry was updated after it was read
+		_, err := store.Txn(context.TODO()).
+			If(clientv3.Compare(clientv3.ModRevision(key), "=", kv.ModRevision)).
+			Then(clientv3.OpDelete(key)).
+			Commit()
+		if err != nil {
+			return err
+		}
+		log.WithField("key", key).Info("deleted stale brick index entry")
+	}
+
+	for _, v := range volumes {
+		var (
+			cmps = []clientv3.Cmp{clientv3.Compare(clientv3.ModRevision(volinfoKey(v.ID)), "=", v.ModRevision)}
+			ops  []clientv3.Op
+		)
+		for _, b := range v.GetBricks() {
+			key := brickIndexKey(b.PeerID, b.Path)
+			if indexed[key] {
+				continue
+			}
+			cmps = append(cmps, clientv3.Compare(clientv3.CreateRevision(key), "=", 0))
+			ops = append(ops, clientv3.OpPut(key, v.ID.String()))
+			indexed[key] = true
+		}
+		if len(ops) == 0 {
+			continue
+		}
+
+		tresp, err := store.Txn(context.TODO()).If(cmps...).Then(ops...).Commit()
+		if err != nil {
+			return err
+		}
+		if !tresp.Succeeded {
+			// Modified concurrently, and indexed on the next run if
+			// it is still left
+			log.WithField("volume", v.Name).Warn("failed to index bricks of volume")
+			continue
+		}
+		log.WithField("volume", v.Name).Info("indexed bricks of volume")
+	}
+
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
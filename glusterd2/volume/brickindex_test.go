@@ -0,0 +1,32 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathsConflict(t *testing.T) {
+	assert.True(t, pathsConflict("/bricks/b1", "/bricks/b1"))
+	assert.True(t, pathsConflict("/bricks/b1/", "/bricks/b1"))
+	assert.True(t, pathsConflict("/bricks", "/bricks/b1"))
+	assert.True(t, pathsConflict("/bricks/b1/data", "/bricks/b1"))
+
+	assert.False(t, pathsConflict("/bricks/b1", "/bricks/b10"))
+	assert.False(t, pathsConflict("/bricks/b1", "/bricks/b2"))
+}
+
+func TestParentPaths(t *testing.T) {
+	assert.Equal(t, []string{"/bricks/b1", "/bricks"}, parentPaths("/bricks/b1/data"))
+	assert.Equal(t, []string{"/bricks"}, parentPaths("/bricks/b1/"))
+	assert.Empty(t, parentPaths("/bricks"))
+}
+
+func TestParseBrickIndexKey(t *testing.T) {
+	peerID := uuid.NewRandom()
+
+	id, path := parseBrickIndexKey(brickIndexKey(peerID, "/bricks/b1"))
+	assert.True(t, uuid.Equal(peerID, id))
+	assert.Equal(t, "/bricks/b1", path)
+}
@@ -0,0 +1,181 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+)
+
+// Naming schemes of the auto provisioned bricks. The scheme a volume was
+// created with is saved in its volinfo, so that the names of its bricks can
+// be worked out.
+const (
+	// BrickNamingLegacy names bricks after the volume name, so the names
+	// collide when a volume name is reused, and don't follow renames
+	BrickNamingLegacy = 0
+	// BrickNamingVolumeID names bricks after the volume ID, and the
+	// indexes of the subvolume and the brick
+	BrickNamingVolumeID = 1
+
+	// CurrentBrickNaming is the naming scheme new volumes are created with
+	CurrentBrickNaming = BrickNamingVolumeID
+)
+
+const (
+	brickLvPrefix = "brick_"
+	brickTpPrefix = "tp_"
+)
+
+func brickName(id string, subvol, brick int) string {
+	return fmt.Sprintf("%s_s%d_b%d", id, subvol, brick)
+}
+
+// NewBrickLvNames returns the names of the thin pool and the LV of an auto
+// provisioned brick of a new volume with the ID, given the indexes of the
+// subvolume and the brick, counted from 1
+func NewBrickLvNames(volID uuid.UUID, subvol, brick int) (string, string) {
+	name := brickName(volID.String(), subvol, brick)
+	return brickTpPrefix + name, brickLvPrefix + name
+}
+
+// NewBrickMountDir returns the directory under root which an auto provisioned
+// brick of a new volume with the ID is mounted on, given the indexes of the
+// subvolume and the brick, counted from 1
+func NewBrickMountDir(root string, volID uuid.UUID, subvol, brick int) string {
+	return path.Join(root, volID.String(), fmt.Sprintf("subvol%d", subvol), fmt.Sprintf("brick%d", brick))
+}
+
+// BrickLvNames returns the names of the thin pool and the LV of an auto
+// provisioned brick of the volume as per its naming scheme, given the indexes
+// of the subvolume and the brick, counted from 1
+func (v *Volinfo) BrickLvNames(subvol, brick int) (string, string) {
+	if v.BrickNaming >= BrickNamingVolumeID {
+		return NewBrickLvNames(v.ID, subvol, brick)
+	}
+	name := brickName(v.Name, subvol, brick)
+	return brickTpPrefix + name, brickLvPrefix + name
+}
+
+// ParseBrickLvName returns the volume ID and the indexes of the subvolume and
+// the brick encoded in the name of the LV or the thin pool of an auto
+// provisioned brick. ok is false if the name isn't of a brick named after
+// the volume ID.
+func ParseBrickLvName(name string) (volID uuid.UUID, subvol, brick int, ok bool) {
+	switch {
+	case strings.HasPrefix(name, brickLvPrefix):
+		name = strings.TrimPrefix(name, brickLvPrefix)
+	case strings.HasPrefix(name, brickTpPrefix):
+		name = strings.TrimPrefix(name, brickTpPrefix)
+	default:
+		return nil, 0, 0, false
+	}
+
+	if len(name) < 36 {
+		return nil, 0, 0, false
+	}
+	if volID = uuid.Parse(name[:36]); volID == nil {
+		return nil, 0, 0, false
+	}
+	if _, err := fmt.Sscanf(name[36:], "_s%d_b%d", &subvol, &brick); err != nil {
+		return nil, 0, 0, false
+	}
+	// Trailing characters are not matched by Sscanf
+	if brickName(volID.String(), subvol, brick) != name {
+		return nil, 0, 0, false
+	}
+	return volID, subvol, brick, true
+}
+
+// BrickPosition is a brick of a volume, with its position in the volume
+type BrickPosition struct {
+	Volinfo *Volinfo
+	Brick   brick.Brickinfo
+	// Subvol and Index are the indexes of the subvolume and of the brick
+	// in the subvolume, counted from 1
+	Subvol int
+	Index  int
+}
+
+// findBrick returns the position of the first brick of the volume matching
+func (v *Volinfo) findBrick(match func(b *brick.Brickinfo) bool) *BrickPosition {
+	for i, sv := range v.Subvols {
+		for j := range sv.Bricks {
+			if match(&sv.Bricks[j]) {
+				return &BrickPosition{Volinfo: v, Brick: sv.Bricks[j], Subvol: i + 1, Index: j + 1}
+			}
+		}
+	}
+	return nil
+}
+
+// FindBrickByPath returns the brick at the path on the peer, looked up in the
+// brick index. ErrBrickNotFound is returned if no volume has the brick.
+func FindBrickByPath(peerID uuid.UUID, brickPath string) (*BrickPosition, error) {
+	resp, err := store.Get(context.TODO(), brickIndexKey(peerID, brickPath))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, gderrors.ErrBrickNotFound
+	}
+
+	v, err := GetVolumeByID(uuid.Parse(string(resp.Kvs[0].Value)))
+	if err == gderrors.ErrVolNotFound {
+		return nil, gderrors.ErrBrickNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	pos := v.findBrick(func(b *brick.Brickinfo) bool {
+		return uuid.Equal(b.PeerID, peerID) && b.Path == brickPath
+	})
+	if pos == nil {
+		return nil, gderrors.ErrBrickNotFound
+	}
+	return pos, nil
+}
+
+// FindBrickByLv returns the auto provisioned brick on the LV. The volume
+// group and the peer are only matched if given. Bricks named after the volume
+// ID are looked up in their volume, others in all the volumes.
+// ErrBrickNotFound is returned if no volume has the brick.
+func FindBrickByLv(peerID uuid.UUID, vgName, lvName string) (*BrickPosition, error) {
+	var volumes []*Volinfo
+	if volID, _, _, ok := ParseBrickLvName(lvName); ok {
+		v, err := GetVolumeByID(volID)
+		if err == gderrors.ErrVolNotFound {
+			return nil, gderrors.ErrBrickNotFound
+		} else if err != nil {
+			return nil, err
+		}
+		volumes = []*Volinfo{v}
+	} else {
+		var err error
+		if volumes, err = GetVolumes(context.TODO()); err != nil {
+			return nil, err
+		}
+	}
+
+	match := func(b *brick.Brickinfo) bool {
+		if b.DevicePath == "" || path.Base(b.DevicePath) != lvName {
+			return false
+		}
+		if vgName != "" && b.VgName != vgName && path.Base(path.Dir(b.DevicePath)) != vgName {
+			return false
+		}
+		return peerID == nil || uuid.Equal(b.PeerID, peerID)
+	}
+	for _, v := range volumes {
+		if pos := v.findBrick(match); pos != nil {
+			return pos, nil
+		}
+	}
+	return nil, gderrors.ErrBrickNotFound
+}
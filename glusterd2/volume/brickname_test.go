@@ -0,0 +1,33 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBrickLvName(t *testing.T) {
+	volID := uuid.NewRandom()
+	tpName, lvName := NewBrickLvNames(volID, 2, 3)
+
+	for _, name := range []string{tpName, lvName} {
+		id, subvol, brick, ok := ParseBrickLvName(name)
+		assert.True(t, ok)
+		assert.True(t, uuid.Equal(volID, id))
+		assert.Equal(t, 2, subvol)
+		assert.Equal(t, 3, brick)
+	}
+
+	v := &Volinfo{ID: volID, Name: "vol1", BrickNaming: BrickNamingLegacy}
+	tpName, lvName = v.BrickLvNames(1, 2)
+	assert.Equal(t, "tp_vol1_s1_b2", tpName)
+	assert.Equal(t, "brick_vol1_s1_b2", lvName)
+	_, _, _, ok := ParseBrickLvName(lvName)
+	assert.False(t, ok)
+
+	_, _, _, ok = ParseBrickLvName(lvName + "_snap")
+	assert.False(t, ok)
+	_, _, _, ok = ParseBrickLvName("brick_" + volID.String() + "_s1_b2x")
+	assert.False(t, ok)
+}
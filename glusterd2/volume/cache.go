@@ -0,0 +1,217 @@
+package volume
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	volinfoCacheOpt = "volinfo-cache"
+
+	// volinfoCacheRetryInterval is the wait before reloading the cache
+	// when the watch fails
+	volinfoCacheRetryInterval = 5 * time.Second
+)
+
+// cachedVolinfo is a volinfo as marshalled in the store, along with the
+// store revision it was last modified at
+type cachedVolinfo struct {
+	value  []byte
+	modRev int64
+}
+
+// volinfoCache caches the volinfos in the store, kept up to date by watching
+// the store. The cache is only trusted for a volinfo when the revision of the
+// volinfo in the store matches the cached revision, so reads always get the
+// latest volinfo. Volinfos are unmarshalled on every read, so that callers
+// get their own copies to modify.
+type volinfoCache struct {
+	sync.RWMutex
+	running bool
+	// vols are keyed by the IDs of the volumes
+	vols   map[string]*cachedVolinfo
+	cancel context.CancelFunc
+}
+
+var volCache = &volinfoCache{}
+
+// StartVolinfoCache loads the volinfos into the cache and starts watching the
+// store for changes to them. Volinfos are got from the store for every read
+// if the cache is disabled or fails to start.
+func StartVolinfoCache() {
+	if !config.GetBool(volinfoCacheOpt) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rev, err := volCache.load(ctx)
+	if err != nil {
+		cancel()
+		log.WithError(err).Warn("failed to load volinfo cache, volinfo caching is disabled")
+		return
+	}
+
+	volCache.Lock()
+	volCache.running = true
+	volCache.cancel = cancel
+	volCache.Unlock()
+
+	go volCache.watch(ctx, rev)
+}
+
+// StopVolinfoCache stops the volinfo cache
+func StopVolinfoCache() {
+	volCache.Lock()
+	defer volCache.Unlock()
+
+	if volCache.cancel != nil {
+		volCache.cancel()
+	}
+	volCache.running = false
+	volCache.vols = nil
+}
+
+// load loads all volinfos from the store, and returns the store revision
+// they were loaded at
+func (c *volinfoCache) load(ctx context.Context) (int64, error) {
+	resp, err := store.Get(ctx, volinfoPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return 0, err
+	}
+
+	vols := make(map[string]*cachedVolinfo, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		vols[volinfoKeyID(kv.Key)] = &cachedVolinfo{value: kv.Value, modRev: kv.ModRevision}
+	}
+
+	c.Lock()
+	c.vols = vols
+	c.Unlock()
+
+	return resp.Header.Revision, nil
+}
+
+// watch applies the changes to volinfos in the store after rev to the cache,
+// reloading the cache if the watch fails
+func (c *volinfoCache) watch(ctx context.Context, rev int64) {
+	for {
+		wch := store.Store.Watch(ctx, volinfoPrefix, clientv3.WithPrefix(), clientv3.WithRev(rev+1))
+		for wresp := range wch {
+			if wresp.Canceled || wresp.Err() != nil {
+				break
+			}
+			for _, ev := range wresp.Events {
+				id := volinfoKeyID(ev.Kv.Key)
+				if ev.Type == mvccpb.PUT {
+					c.put(id, ev.Kv.Value, ev.Kv.ModRevision)
+				} else {
+					c.delete(id)
+				}
+			}
+			rev = wresp.Header.Revision
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(volinfoCacheRetryInterval):
+			}
+
+			// Changes may have been missed, so start over
+			var err error
+			if rev, err = c.load(ctx); err == nil {
+				break
+			}
+			log.WithError(err).Warn("failed to reload volinfo cache")
+		}
+	}
+}
+
+func (c *volinfoCache) isRunning() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.running
+}
+
+// get returns the cached volinfo if it is at the revision
+func (c *volinfoCache) get(id string, modRev int64) ([]byte, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	v, ok := c.vols[id]
+	if !ok || v.modRev != modRev {
+		return nil, false
+	}
+	return v.value, true
+}
+
+// put caches the volinfo, unless a later revision of it is already cached
+func (c *volinfoCache) put(id string, value []byte, modRev int64) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.vols == nil {
+		return
+	}
+	if v, ok := c.vols[id]; ok && v.modRev >= modRev {
+		return
+	}
+	c.vols[id] = &cachedVolinfo{value: value, modRev: modRev}
+}
+
+func (c *volinfoCache) delete(id string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.vols, id)
+}
+
+func volinfoKeyID(key []byte) string {
+	return strings.TrimPrefix(string(key), volinfoPrefix)
+}
+
+// getVolinfoKVs gets the volinfos under the key from the store. When the cache
+// is running, only the keys and revisions of the volinfos are got from the
+// store, and the volinfos are taken from the cache. If any of them is not in
+// the cache at its revision, all of them are got from the store.
+func getVolinfoKVs(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	if !volCache.isRunning() {
+		return store.Get(ctx, key, opts...)
+	}
+
+	resp, err := store.Get(ctx, key, append(opts, clientv3.WithKeysOnly())...)
+	if err != nil {
+		return nil, err
+	}
+
+	missed := false
+	for _, kv := range resp.Kvs {
+		value, ok := volCache.get(volinfoKeyID(kv.Key), kv.ModRevision)
+		if !ok {
+			missed = true
+			break
+		}
+		kv.Value = value
+	}
+	if !missed {
+		return resp, nil
+	}
+
+	resp, err = store.Get(ctx, key, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, kv := range resp.Kvs {
+		volCache.put(volinfoKeyID(kv.Key), kv.Value, kv.ModRevision)
+	}
+	return resp, nil
+}
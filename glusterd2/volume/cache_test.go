@@ -0,0 +1,36 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolinfoCache(t *testing.T) {
+	c := &volinfoCache{vols: make(map[string]*cachedVolinfo)}
+
+	c.put("vol1", []byte("v2"), 2)
+	value, ok := c.get("vol1", 2)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("v2"), value)
+
+	// Not trusted at another revision
+	_, ok = c.get("vol1", 3)
+	assert.False(t, ok)
+
+	// A late change does not replace a later one
+	c.put("vol1", []byte("v1"), 1)
+	value, ok = c.get("vol1", 2)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("v2"), value)
+
+	c.delete("vol1")
+	_, ok = c.get("vol1", 2)
+	assert.False(t, ok)
+
+	// Nothing is cached once stopped
+	c.vols = nil
+	c.put("vol1", []byte("v3"), 3)
+	_, ok = c.get("vol1", 3)
+	assert.False(t, ok)
+}
@@ -0,0 +1,63 @@
+package volume
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/brick"
+)
+
+// CalculateCapacity returns the usable capacity of the volume from the
+// capacities of its bricks, keyed by brick ID. Bricks missing from
+// brickCapacity are counted as having no capacity.
+func CalculateCapacity(v *Volinfo, brickCapacity map[string]uint64) uint64 {
+	var capacity uint64
+	for i := range v.Subvols {
+		capacity += subvolCapacity(&v.Subvols[i], brickCapacity)
+	}
+	return capacity
+}
+
+func subvolCapacity(sv *Subvol, brickCapacity map[string]uint64) uint64 {
+	var capacity uint64
+
+	switch sv.Type {
+	case SubvolReplicate:
+		// Arbiter bricks store only metadata, and don't limit the
+		// capacity of the replica set
+		capacity = minBrickCapacity(sv.Bricks, brickCapacity, true)
+	case SubvolDisperse:
+		dataCount := sv.DisperseCount - sv.RedundancyCount
+		if sv.DisperseCount == 0 {
+			dataCount = len(sv.Bricks) - sv.RedundancyCount
+		}
+		if dataCount > 0 {
+			capacity = minBrickCapacity(sv.Bricks, brickCapacity, false) * uint64(dataCount)
+		}
+	default:
+		for _, b := range sv.Bricks {
+			capacity += brickCapacity[b.ID.String()]
+		}
+	}
+
+	for i := range sv.Subvols {
+		capacity += subvolCapacity(&sv.Subvols[i], brickCapacity)
+	}
+
+	return capacity
+}
+
+func minBrickCapacity(bricks []brick.Brickinfo, brickCapacity map[string]uint64, skipArbiters bool) uint64 {
+	var (
+		min   uint64
+		found bool
+	)
+	for _, b := range bricks {
+		if skipArbiters && (b.Type == brick.Arbiter || b.Type == brick.ThinArbiter) {
+			continue
+		}
+		c := brickCapacity[b.ID.String()]
+		if !found || c < min {
+			min = c
+			found = true
+		}
+	}
+	return min
+}
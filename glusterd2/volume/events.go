@@ -11,16 +11,43 @@ type Event string
 const (
 	// EventVolumeCreated represents Volume Create event
 	EventVolumeCreated Event = "volume.created"
+	// EventVolumeCreateProgress represents provisioning of a brick of a Volume being created
+	EventVolumeCreateProgress Event = "volume.create-progress"
 	// EventVolumeExpanded represents Volume Expand event
-	EventVolumeExpanded = "volume.expanded"
+	EventVolumeExpanded Event = "volume.expanded"
+	// EventVolumeAutoExpanded represents Volume expansion by the auto expansion policy
+	EventVolumeAutoExpanded Event = "volume.autoexpanded"
+	// EventVolumeAutoExpandFailed represents failure of an expansion by the auto expansion policy
+	EventVolumeAutoExpandFailed Event = "volume.autoexpand-failed"
+	// EventVolumeCapacityChanged represents change of Volume capacity after its bricks are resized
+	EventVolumeCapacityChanged Event = "volume.capacity-changed"
+	// EventVolumeUsageProtectEngaged represents Volume made read-only by the usage protection policy
+	EventVolumeUsageProtectEngaged Event = "volume.usage-protect-engaged"
+	// EventVolumeUsageProtectReleased represents Volume made writable again by the usage protection policy
+	EventVolumeUsageProtectReleased Event = "volume.usage-protect-released"
 	// EventVolumeStarted represents Volume Start event
-	EventVolumeStarted = "volume.started"
+	EventVolumeStarted Event = "volume.started"
 	// EventVolumeStopped represents Volume Stop event
-	EventVolumeStopped = "volume.stopped"
+	EventVolumeStopped Event = "volume.stopped"
 	// EventVolumeDeleted represents Volume Delete event
-	EventVolumeDeleted = "volume.deleted"
+	EventVolumeDeleted Event = "volume.deleted"
+	// EventVolumeRenamed represents Volume Rename event
+	EventVolumeRenamed Event = "volume.renamed"
+	// EventVolumeRestored represents the restore of a deleted volume
+	EventVolumeRestored Event = "volume.restored"
+	// EventVolumePurged represents the purge of a deleted volume from the
+	// trash
+	EventVolumePurged Event = "volume.purged"
 )
 
+// lifecycleEvents are the events which carry the full volume info, for the
+// external provisioners tracking volumes
+var lifecycleEvents = map[Event]bool{
+	EventVolumeCreated: true,
+	EventVolumeStarted: true,
+	EventVolumeDeleted: true,
+}
+
 // NewEvent adds required details to event based on Volume info
 func NewEvent(e Event, v *Volinfo) *api.Event {
 	data := map[string]string{
@@ -28,5 +55,8 @@ func NewEvent(e Event, v *Volinfo) *api.Event {
 		"volume.id":   v.ID.String(),
 	}
 
+	if lifecycleEvents[e] {
+		return events.NewLifecycle(string(e), data, api.EventResourceVolume, CreateVolumeInfoResp(v))
+	}
 	return events.New(string(e), data, true)
 }
@@ -0,0 +1,107 @@
+package volume
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Volume list filters, along with the metadata "key" and "value" filters
+const (
+	// FilterState selects volumes in the state, eg. started
+	FilterState = "state"
+	// FilterType selects volumes of the type, eg. replicate
+	FilterType = "type"
+	// FilterName selects volumes with names matching the glob
+	FilterName = "name"
+	// FilterNameRegex selects volumes with names matching the regex
+	FilterNameRegex = "name-regex"
+)
+
+// FilterParams are all the filters volumes can be listed with
+var FilterParams = []string{"key", "value", FilterState, FilterType, FilterName, FilterNameRegex}
+
+var (
+	filterStates = []api.VolState{api.VolCreated, api.VolStarted, api.VolStopped}
+	filterTypes  = []api.VolType{api.Distribute, api.Replicate, api.Disperse, api.DistReplicate, api.DistDisperse}
+)
+
+// volumeFilter is a parsed volume list filter
+type volumeFilter struct {
+	params       map[string]string
+	metadataType metadataFilter
+	nameRegex    *regexp.Regexp
+}
+
+func newVolumeFilter(params map[string]string) (*volumeFilter, error) {
+	f := &volumeFilter{
+		params:       params,
+		metadataType: getFilterType(params),
+	}
+
+	if s, ok := params[FilterState]; ok && !validFilterState(s) {
+		return nil, fmt.Errorf("invalid volume state %q", s)
+	}
+	if t, ok := params[FilterType]; ok && !validFilterType(t) {
+		return nil, fmt.Errorf("invalid volume type %q", t)
+	}
+	if g, ok := params[FilterName]; ok {
+		if _, err := path.Match(g, ""); err != nil {
+			return nil, fmt.Errorf("invalid volume name glob %q", g)
+		}
+	}
+	if r, ok := params[FilterNameRegex]; ok {
+		var err error
+		if f.nameRegex, err = regexp.Compile(r); err != nil {
+			return nil, fmt.Errorf("invalid volume name regex %q: %s", r, err)
+		}
+	}
+
+	return f, nil
+}
+
+func validFilterState(s string) bool {
+	for _, state := range filterStates {
+		if strings.EqualFold(state.String(), s) {
+			return true
+		}
+	}
+	return false
+}
+
+func validFilterType(t string) bool {
+	for _, voltype := range filterTypes {
+		if strings.EqualFold(voltype.String(), t) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateFilter validates the filter volumes are to be listed with
+func ValidateFilter(params map[string]string) error {
+	_, err := newVolumeFilter(params)
+	return err
+}
+
+// match checks if a volume with the attributes is selected by the filter
+func (f *volumeFilter) match(name string, voltype VolType, state VolState, metadata map[string]string) bool {
+	if s, ok := f.params[FilterState]; ok && !strings.EqualFold(api.VolState(state).String(), s) {
+		return false
+	}
+	if t, ok := f.params[FilterType]; ok && !strings.EqualFold(api.VolType(voltype).String(), t) {
+		return false
+	}
+	if g, ok := f.params[FilterName]; ok {
+		if matched, _ := path.Match(g, name); !matched {
+			return false
+		}
+	}
+	if f.nameRegex != nil && !f.nameRegex.MatchString(name) {
+		return false
+	}
+	return matchMetadata(metadata, f.metadataType, f.params)
+}
@@ -0,0 +1,37 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeFilter(t *testing.T) {
+	metadata := map[string]string{"owner": "alice"}
+
+	f, err := newVolumeFilter(nil)
+	assert.Nil(t, err)
+	assert.True(t, f.match("vol1", Replicate, VolStarted, metadata))
+
+	f, err = newVolumeFilter(map[string]string{FilterState: "started", FilterType: "Replicate"})
+	assert.Nil(t, err)
+	assert.True(t, f.match("vol1", Replicate, VolStarted, metadata))
+	assert.False(t, f.match("vol1", Replicate, VolStopped, metadata))
+	assert.False(t, f.match("vol1", DistReplicate, VolStarted, metadata))
+
+	f, err = newVolumeFilter(map[string]string{FilterName: "web-*", "key": "owner"})
+	assert.Nil(t, err)
+	assert.True(t, f.match("web-1", Distribute, VolCreated, metadata))
+	assert.False(t, f.match("db-1", Distribute, VolCreated, metadata))
+	assert.False(t, f.match("web-1", Distribute, VolCreated, nil))
+
+	f, err = newVolumeFilter(map[string]string{FilterNameRegex: "^vol[0-9]+$"})
+	assert.Nil(t, err)
+	assert.True(t, f.match("vol12", Disperse, VolCreated, nil))
+	assert.False(t, f.match("vol1a", Disperse, VolCreated, nil))
+
+	assert.NotNil(t, ValidateFilter(map[string]string{FilterState: "running"}))
+	assert.NotNil(t, ValidateFilter(map[string]string{FilterType: "mirror"}))
+	assert.NotNil(t, ValidateFilter(map[string]string{FilterName: "[a"}))
+	assert.NotNil(t, ValidateFilter(map[string]string{FilterNameRegex: "(a"}))
+}
@@ -0,0 +1,82 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+// ioThrottlePrefix holds the IO limits of the volumes, keyed by the volume
+// name
+const ioThrottlePrefix = "volume-iothrottle/"
+
+func init() {
+	brick.GetIOLimitsFunc = GetBrickIOLimits
+}
+
+// SetIOThrottlePolicy saves the I/O limits of the bricks of the volume
+func SetIOThrottlePolicy(volname string, p *api.VolIOThrottleResp) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), ioThrottlePrefix+volname, string(b))
+	return err
+}
+
+// GetIOThrottlePolicy returns the I/O limits of the bricks of the volume
+func GetIOThrottlePolicy(volname string) (*api.VolIOThrottleResp, error) {
+	resp, err := store.Get(context.TODO(), ioThrottlePrefix+volname)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, gderrors.ErrIOThrottlePolicyNotFound
+	}
+
+	var p api.VolIOThrottleResp
+	if err := json.Unmarshal(resp.Kvs[0].Value, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// DeleteIOThrottlePolicy deletes the I/O limits of the bricks of the volume
+func DeleteIOThrottlePolicy(volname string) error {
+	_, err := store.Delete(context.TODO(), ioThrottlePrefix+volname)
+	return err
+}
+
+// BrickIOLimits returns the I/O limits of the brick as per the policy, which
+// are the limits of the volume unless overridden for the brick
+func BrickIOLimits(p *api.VolIOThrottleResp, brickID string) api.IOLimits {
+	if p == nil {
+		return api.IOLimits{}
+	}
+	if l, ok := p.Bricks[brickID]; ok {
+		return l
+	}
+	return p.IOLimits
+}
+
+// GetBrickIOLimits returns the I/O limits of the brick from the I/O
+// throttling policy of its volume. Nil is returned if the volume has no
+// policy.
+func GetBrickIOLimits(b brick.Brickinfo) (*api.IOLimits, error) {
+	p, err := GetIOThrottlePolicy(b.VolumeName)
+	if err == gderrors.ErrIOThrottlePolicyNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	l := BrickIOLimits(p, b.ID.String())
+	return &l, nil
+}
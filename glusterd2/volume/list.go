@@ -0,0 +1,150 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// ListOptions selects the volumes listed by GetVolumesPage and
+// GetVolumeSummaries
+type ListOptions struct {
+	// Limit is the maximum number of volumes listed, 0 for no limit
+	Limit int64
+	// Continue is the continue token returned with the previous page, to
+	// list the volumes after it
+	Continue string
+	// Filter is the filter, as for GetVolumes
+	Filter map[string]string
+}
+
+// VolumeSummary has the fields of a volinfo which can be listed without
+// unmarshalling the whole volinfo
+type VolumeSummary struct {
+	ID       uuid.UUID
+	Name     string
+	Type     VolType
+	State    VolState
+	Metadata map[string]string
+}
+
+// getVolumesPage gets the volinfos in the store after the continue token, in
+// the order of their names. The continue token for the next page is returned
+// if more volinfos are left. The page is read from the name index, and the
+// volinfos of the page are got at the same store revision.
+func getVolumesPage(ctx context.Context, opts ListOptions) ([]*mvccpb.KeyValue, string, error) {
+	start := volumeIndexPrefix
+	if opts.Continue != "" {
+		// Start right after the last volume of the previous page
+		start = volumeIndexPrefix + opts.Continue + "\x00"
+	}
+
+	getOpts := []clientv3.OpOption{
+		clientv3.WithRange(clientv3.GetPrefixRangeEnd(volumeIndexPrefix)),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend),
+	}
+	if opts.Limit > 0 {
+		getOpts = append(getOpts, clientv3.WithLimit(opts.Limit))
+	}
+
+	index, err := store.Get(ctx, start, getOpts...)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(index.Kvs) == 0 {
+		return nil, "", nil
+	}
+
+	resp, err := getVolinfoKVs(ctx, volinfoPrefix, clientv3.WithPrefix(), clientv3.WithRev(index.Header.Revision))
+	if err != nil {
+		return nil, "", err
+	}
+	volinfos := make(map[string]*mvccpb.KeyValue, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		volinfos[volinfoKeyID(kv.Key)] = kv
+	}
+
+	kvs := make([]*mvccpb.KeyValue, 0, len(index.Kvs))
+	for _, kv := range index.Kvs {
+		if v, ok := volinfos[string(kv.Value)]; ok {
+			kvs = append(kvs, v)
+		}
+	}
+
+	var next string
+	if index.More {
+		next = strings.TrimPrefix(string(index.Kvs[len(index.Kvs)-1].Key), volumeIndexPrefix)
+	}
+	return kvs, next, nil
+}
+
+// GetVolumesPage returns a page of volinfos, and the continue token for the
+// next page, which is empty on the last page. Volumes are filtered after
+// paging, so a page may have fewer volumes than the limit.
+func GetVolumesPage(ctx context.Context, opts ListOptions) ([]*Volinfo, string, error) {
+	ctx, span := trace.StartSpan(ctx, "volume.GetVolumesPage")
+	defer span.End()
+
+	kvs, next, err := getVolumesPage(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter, err := newVolumeFilter(opts.Filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	volumes := make([]*Volinfo, 0, len(kvs))
+	for _, kv := range kvs {
+		var vol Volinfo
+		if err := json.Unmarshal(kv.Value, &vol); err != nil {
+			log.WithError(err).WithField("volume", string(kv.Key)).Error("Failed to unmarshal volume")
+			continue
+		}
+		vol.ModRevision = kv.ModRevision
+		if filter.match(vol.Name, vol.Type, vol.State, vol.Metadata) {
+			volumes = append(volumes, &vol)
+		}
+	}
+
+	return volumes, next, nil
+}
+
+// GetVolumeSummaries returns a page of volume summaries like GetVolumesPage
+func GetVolumeSummaries(ctx context.Context, opts ListOptions) ([]*VolumeSummary, string, error) {
+	ctx, span := trace.StartSpan(ctx, "volume.GetVolumeSummaries")
+	defer span.End()
+
+	kvs, next, err := getVolumesPage(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter, err := newVolumeFilter(opts.Filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	volumes := make([]*VolumeSummary, 0, len(kvs))
+	for _, kv := range kvs {
+		var vol VolumeSummary
+		if err := json.Unmarshal(kv.Value, &vol); err != nil {
+			log.WithError(err).WithField("volume", string(kv.Key)).Error("Failed to unmarshal volume")
+			continue
+		}
+		if filter.match(vol.Name, vol.Type, vol.State, vol.Metadata) {
+			volumes = append(volumes, &vol)
+		}
+	}
+
+	return volumes, next, nil
+}
@@ -0,0 +1,82 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+const (
+	// metricsPrefix holds the metrics samples of the volumes, keyed by the
+	// volume name and sample bucket
+	metricsPrefix = "volume-metrics/"
+	// MetricsBucket is the granularity of the metrics samples. Only one
+	// sample is retained per bucket.
+	MetricsBucket = time.Minute
+	// MetricsRetention is the duration for which metrics samples are
+	// retained
+	MetricsRetention = 7 * 24 * time.Hour
+)
+
+func metricsVolPrefix(volname string) string {
+	return metricsPrefix + volname + "/"
+}
+
+// metricsKey returns the key of the bucket of the sample time. Keys are
+// zero padded so that they sort by time.
+func metricsKey(volname string, t time.Time) string {
+	return fmt.Sprintf("%s%020d", metricsVolPrefix(volname), t.Truncate(MetricsBucket).Unix())
+}
+
+// AddMetricsSample saves a metrics sample of the volume
+func AddMetricsSample(volname string, s *api.VolMetricsSample) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), metricsKey(volname, s.Time), string(b))
+	return err
+}
+
+// GetMetricsSamples returns the metrics samples of the volume recorded since
+// the given time, sorted by time
+func GetMetricsSamples(volname string, since time.Time) ([]api.VolMetricsSample, error) {
+	resp, err := store.Get(context.TODO(), metricsKey(volname, since),
+		clientv3.WithRange(clientv3.GetPrefixRangeEnd(metricsVolPrefix(volname))),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]api.VolMetricsSample, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var s api.VolMetricsSample
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+	}
+
+	return samples, nil
+}
+
+// PruneMetricsSamples deletes the metrics samples of the volume recorded
+// before the given time
+func PruneMetricsSamples(volname string, before time.Time) error {
+	_, err := store.Delete(context.TODO(), metricsVolPrefix(volname),
+		clientv3.WithRange(metricsKey(volname, before)))
+	return err
+}
+
+// DeleteMetricsSamples deletes all metrics samples of the volume
+func DeleteMetricsSamples(volname string) error {
+	_, err := store.Delete(context.TODO(), metricsVolPrefix(volname), clientv3.WithPrefix())
+	return err
+}
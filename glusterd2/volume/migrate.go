@@ -0,0 +1,74 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+// legacyVolumePrefix is the prefix the volinfos were stored under, keyed by
+// the names of the volumes, before they were keyed by their IDs
+const legacyVolumePrefix = "volumes/"
+
+// MigrateStore moves the volinfos stored under their names to be stored under
+// their IDs, adding them to the name index. Every volinfo is moved in its own
+// transaction, conditional on it not having been modified, so the migration
+// can be run by several peers at once, and volinfos which fail to be moved
+// are moved when the migration is run again. Volinfos whose name is already
+// indexed to another volume are left in place. Should be called after the store
+// is up, and before the volinfo cache is started.
+//
+// Peers which have not been upgraded don't see the migrated volumes, so all
+// the peers of the cluster should be upgraded together.
+func MigrateStore() error {
+	resp, err := store.Get(context.TODO(), legacyVolumePrefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	for _, kv := range resp.Kvs {
+		logger := log.WithField("key", string(kv.Key))
+
+		var v Volinfo
+		if err := json.Unmarshal(kv.Value, &v); err != nil {
+			logger.WithError(err).Error("failed to unmarshal volinfo, not migrating it")
+			continue
+		}
+
+		index := volumeIndexKey(v.Name)
+		tresp, err := store.Txn(context.TODO()).
+			If(
+				clientv3.Compare(clientv3.ModRevision(string(kv.Key)), "=", kv.ModRevision),
+				clientv3.Compare(clientv3.CreateRevision(index), "=", 0),
+			).
+			Then(
+				clientv3.OpPut(volinfoKey(v.ID), string(kv.Value)),
+				clientv3.OpPut(index, v.ID.String()),
+				clientv3.OpDelete(string(kv.Key)),
+			).
+			Else(clientv3.OpGet(index)).
+			Commit()
+		if err != nil {
+			return err
+		}
+		if !tresp.Succeeded {
+			if kvs := tresp.Responses[0].GetResponseRange().Kvs; len(kvs) != 0 && string(kvs[0].Value) != v.ID.String() {
+				// Left in place for the admin to resolve, as
+				// the name is taken by another volume
+				logger.WithField("volume-id", string(kvs[0].Value)).Error("volume name is indexed to another volume, not migrating volinfo")
+				continue
+			}
+			// Modified or migrated concurrently, and moved on the
+			// next run if it is still left
+			logger.Warn("volinfo was modified during migration, not migrating it")
+			continue
+		}
+		logger.WithField("volume", v.Name).Info("migrated volinfo to be stored by volume ID")
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/testutils"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/embed"
+	"github.com/coreos/etcd/etcdserver/api/v3client"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func localURL(t *testing.T) url.URL {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return url.URL{Scheme: "http", Host: l.Addr().String()}
+}
+
+// startTestStore starts an embedded etcd server to be used as the store, and
+// returns the function stopping it
+func startTestStore(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "gd2-store")
+	require.NoError(t, err)
+
+	conf := embed.NewConfig()
+	conf.Dir = dir
+	purl, curl := localURL(t), localURL(t)
+	conf.LPUrls, conf.APUrls = []url.URL{purl}, []url.URL{purl}
+	conf.LCUrls, conf.ACUrls = []url.URL{curl}, []url.URL{curl}
+	conf.InitialCluster = conf.InitialClusterFromName(conf.Name)
+
+	e, err := embed.StartEtcd(conf)
+	require.NoError(t, err)
+	<-e.Server.ReadyNotify()
+
+	client := v3client.New(e.Server)
+	restore := testutils.Patch(&store.Store, &store.GDStore{KV: client.KV})
+	return func() {
+		restore()
+		client.Close()
+		e.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func putVolinfo(t *testing.T, key string, v *Volinfo) {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	_, err = store.Put(context.TODO(), key, string(b))
+	require.NoError(t, err)
+}
+
+// storedKeys returns the keys and values in the store
+func storedKeys(t *testing.T) map[string]string {
+	resp, err := store.Get(context.TODO(), "", clientv3.WithPrefix())
+	require.NoError(t, err)
+
+	kvs := make(map[string]string)
+	for _, kv := range resp.Kvs {
+		kvs[string(kv.Key)] = string(kv.Value)
+	}
+	return kvs
+}
+
+// migrated returns the keys of a volume migrated to be stored by its ID
+func migrated(v *Volinfo) []string {
+	return []string{volinfoKey(v.ID), volumeIndexKey(v.Name)}
+}
+
+func TestMigrateStore(t *testing.T) {
+	defer startTestStore(t)()
+
+	vol1 := &Volinfo{ID: uuid.NewRandom(), Name: "vol1"}
+	vol2 := &Volinfo{ID: uuid.NewRandom(), Name: "vol2"}
+	putVolinfo(t, legacyVolumePrefix+vol1.Name, vol1)
+	putVolinfo(t, legacyVolumePrefix+vol2.Name, vol2)
+
+	require.NoError(t, MigrateStore())
+
+	kvs := storedKeys(t)
+	assert.Len(t, kvs, 4)
+	for _, v := range []*Volinfo{vol1, vol2} {
+		assert.Contains(t, kvs, volinfoKey(v.ID))
+		assert.Equal(t, v.ID.String(), kvs[volumeIndexKey(v.Name)])
+		assert.NotContains(t, kvs, legacyVolumePrefix+v.Name)
+	}
+
+	// Running the migration on a migrated store changes nothing
+	require.NoError(t, MigrateStore())
+	assert.Equal(t, kvs, storedKeys(t))
+}
+
+func TestMigrateStorePartial(t *testing.T) {
+	defer startTestStore(t)()
+
+	// vol1 was migrated by a run interrupted before moving vol2
+	vol1 := &Volinfo{ID: uuid.NewRandom(), Name: "vol1"}
+	vol2 := &Volinfo{ID: uuid.NewRandom(), Name: "vol2"}
+	putVolinfo(t, volinfoKey(vol1.ID), vol1)
+	_, err := store.Put(context.TODO(), volumeIndexKey(vol1.Name), vol1.ID.String())
+	require.NoError(t, err)
+	putVolinfo(t, legacyVolumePrefix+vol2.Name, vol2)
+
+	require.NoError(t, MigrateStore())
+
+	kvs := storedKeys(t)
+	assert.Len(t, kvs, 4)
+	for _, v := range []*Volinfo{vol1, vol2} {
+		assert.Contains(t, kvs, volinfoKey(v.ID))
+		assert.Equal(t, v.ID.String(), kvs[volumeIndexKey(v.Name)])
+	}
+	assert.NotContains(t, kvs, legacyVolumePrefix+vol2.Name)
+}
+
+func TestMigrateStoreNameCollision(t *testing.T) {
+	defer startTestStore(t)()
+
+	// The name of the legacy vol1 is taken by a volume stored by its ID
+	vol1 := &Volinfo{ID: uuid.NewRandom(), Name: "vol1"}
+	other := &Volinfo{ID: uuid.NewRandom(), Name: "vol1"}
+	putVolinfo(t, volinfoKey(other.ID), other)
+	_, err := store.Put(context.TODO(), volumeIndexKey(other.Name), other.ID.String())
+	require.NoError(t, err)
+	putVolinfo(t, legacyVolumePrefix+vol1.Name, vol1)
+	vol2 := &Volinfo{ID: uuid.NewRandom(), Name: "vol2"}
+	putVolinfo(t, legacyVolumePrefix+vol2.Name, vol2)
+
+	require.NoError(t, MigrateStore())
+
+	// vol1 is left in place, without touching the other volume, and the
+	// volumes after it are still migrated
+	kvs := storedKeys(t)
+	assert.Contains(t, kvs, legacyVolumePrefix+vol1.Name)
+	assert.NotContains(t, kvs, volinfoKey(vol1.ID))
+	assert.Equal(t, other.ID.String(), kvs[volumeIndexKey("vol1")])
+	assert.Contains(t, kvs, volinfoKey(other.ID))
+	for _, key := range migrated(vol2) {
+		assert.Contains(t, kvs, key)
+	}
+	assert.NotContains(t, kvs, legacyVolumePrefix+vol2.Name)
+}
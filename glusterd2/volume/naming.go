@@ -0,0 +1,114 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+	config "github.com/spf13/viper"
+)
+
+const (
+	volNamePatternOpt          = "volname-pattern"
+	volNameMaxLengthOpt        = "volname-max-length"
+	volNameReservedPrefixesOpt = "volname-reserved-prefixes"
+
+	defaultVolNameMaxLength = 128
+
+	// nameReservationPrefix holds the names reserved by the volume creates
+	// in progress
+	nameReservationPrefix = "volume-names/"
+	// nameReservationTTL bounds how long a name stays reserved if the GD2
+	// reserving it goes away without releasing it
+	nameReservationTTL = 10 * time.Minute
+)
+
+// InitFlags intializes the command line options for the volume naming policy
+// and the volinfo cache
+func InitFlags() {
+	flag.Bool(volinfoCacheOpt, true, "Cache volinfos in memory, kept up to date by watching the store.")
+	flag.String(volNamePatternOpt, "", "Regular expression which the names of new volumes must match, in addition to being made of letters, digits, '_' and '-'.")
+	flag.Int(volNameMaxLengthOpt, defaultVolNameMaxLength, "Maximum length of the names of new volumes. Set to 0 for no limit.")
+	flag.StringSlice(volNameReservedPrefixesOpt, nil, "Prefixes which the names of new volumes must not start with, for names reserved for internal use.")
+}
+
+// ValidateName checks the name of a new volume against the volume naming
+// policy. Existing volumes are not affected by changes to the policy.
+func ValidateName(name string) error {
+	if !IsValidName(name) {
+		return gderrors.ErrInvalidVolName
+	}
+
+	if max := config.GetInt(volNameMaxLengthOpt); max > 0 && len(name) > max {
+		return fmt.Errorf("%s: longer than %d characters", gderrors.ErrInvalidVolName, max)
+	}
+
+	for _, prefix := range config.GetStringSlice(volNameReservedPrefixesOpt) {
+		if prefix != "" && strings.HasPrefix(name, prefix) {
+			return fmt.Errorf("%s: prefix %s is reserved", gderrors.ErrInvalidVolName, prefix)
+		}
+	}
+
+	if pattern := config.GetString(volNamePatternOpt); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			log.WithError(err).WithField("pattern", pattern).Error("invalid volume name pattern")
+			return fmt.Errorf("invalid volume name pattern %s: %s", pattern, err)
+		}
+		if !re.MatchString(name) {
+			return fmt.Errorf("%s: does not match %s", gderrors.ErrInvalidVolName, pattern)
+		}
+	}
+
+	return nil
+}
+
+// ReserveName atomically reserves the name for a new volume, failing with
+// ErrVolExists if a volume with the name exists, or ErrVolNameReserved if the
+// name is reserved by another create. The reservation must be released by
+// calling the returned func once the volume has been stored, or the create
+// has failed.
+func ReserveName(name string) (func(), error) {
+	key := nameReservationPrefix + name
+
+	lease, err := store.Store.Grant(context.TODO(), int64(nameReservationTTL/time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := store.Txn(context.TODO()).
+		If(
+			clientv3.Compare(clientv3.CreateRevision(volumeIndexKey(name)), "=", 0),
+			clientv3.Compare(clientv3.CreateRevision(key), "=", 0),
+		).
+		Then(clientv3.OpPut(key, "", clientv3.WithLease(lease.ID))).
+		Else(clientv3.OpGet(volumeIndexKey(name), clientv3.WithCountOnly())).
+		Commit()
+	if err != nil {
+		store.Store.Revoke(context.TODO(), lease.ID)
+		return nil, err
+	}
+	if !resp.Succeeded {
+		store.Store.Revoke(context.TODO(), lease.ID)
+		if resp.Responses[0].GetResponseRange().Count != 0 {
+			return nil, gderrors.ErrVolExists
+		}
+		return nil, gderrors.ErrVolNameReserved
+	}
+
+	release := func() {
+		// Revoking the lease removes the reservation
+		if _, err := store.Store.Revoke(context.TODO(), lease.ID); err != nil {
+			log.WithError(err).WithField("volume", name).Warn("failed to release volume name reservation")
+		}
+	}
+	return release, nil
+}
@@ -0,0 +1,34 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateName(t *testing.T) {
+	defer config.Reset()
+
+	assert.Nil(t, ValidateName("vol-1_a"))
+	assert.Equal(t, errors.ErrInvalidVolName, ValidateName("vol/1"))
+	assert.Equal(t, errors.ErrInvalidVolName, ValidateName(""))
+
+	config.Set(volNameMaxLengthOpt, 5)
+	assert.Nil(t, ValidateName("vol1"))
+	assert.NotNil(t, ValidateName("volume1"))
+
+	config.Set(volNameMaxLengthOpt, 0)
+	config.Set(volNameReservedPrefixesOpt, []string{"gluster_"})
+	assert.Nil(t, ValidateName("glustervol"))
+	assert.NotNil(t, ValidateName("gluster_shared_storage"))
+
+	config.Set(volNamePatternOpt, "^prod-")
+	assert.Nil(t, ValidateName("prod-vol1"))
+	assert.NotNil(t, ValidateName("test-vol1"))
+
+	config.Set(volNamePatternOpt, "(")
+	assert.NotNil(t, ValidateName("prod-vol1"))
+}
@@ -0,0 +1,144 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+const (
+	// optionsHistoryPrefix holds the option changes of the volumes, keyed
+	// by the volume name and change ID
+	optionsHistoryPrefix = "volume-options-history/"
+	// MaxOptionsHistory is the number of option changes retained per volume
+	MaxOptionsHistory = 50
+)
+
+// Volume option operations recorded in the options history
+const (
+	OptionsSet      = "set"
+	OptionsReset    = "reset"
+	OptionsRollback = "rollback"
+)
+
+// OptionsDiff returns the changes between two sets of volume options
+func OptionsDiff(oldOpts, newOpts map[string]string) map[string]api.VolOptionChange {
+	changes := make(map[string]api.VolOptionChange)
+
+	for k, o := range oldOpts {
+		if n, ok := newOpts[k]; !ok || n != o {
+			changes[k] = api.VolOptionChange{Old: o, New: n}
+		}
+	}
+
+	for k, n := range newOpts {
+		if _, ok := oldOpts[k]; !ok {
+			changes[k] = api.VolOptionChange{New: n}
+		}
+	}
+
+	return changes
+}
+
+func optionsHistoryKey(volname, id string) string {
+	return optionsHistoryPrefix + volname + "/" + id
+}
+
+// AddOptionsHistory records a change to the options of the volume. Only the
+// latest MaxOptionsHistory changes are retained. Nothing is recorded if the
+// options were not changed.
+func AddOptionsHistory(volname, operation, user, reqID string, oldOpts, newOpts map[string]string) error {
+	changes := OptionsDiff(oldOpts, newOpts)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	entry := api.VolOptionsHistoryEntry{
+		// zero padded so that entries sort in order of time
+		ID:        fmt.Sprintf("%020d", now.UnixNano()),
+		Time:      now,
+		User:      user,
+		ReqID:     reqID,
+		Operation: operation,
+		Changes:   changes,
+		Options:   newOpts,
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err := store.Put(context.TODO(), optionsHistoryKey(volname, entry.ID), string(b)); err != nil {
+		return err
+	}
+
+	resp, err := store.Get(context.TODO(), optionsHistoryPrefix+volname+"/",
+		clientv3.WithPrefix(), clientv3.WithKeysOnly(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(resp.Kvs)-MaxOptionsHistory; i++ {
+		if _, err := store.Delete(context.TODO(), string(resp.Kvs[i].Key)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetOptionsHistory returns the recorded option changes of the volume, the
+// latest one first
+func GetOptionsHistory(volname string) ([]api.VolOptionsHistoryEntry, error) {
+	resp, err := store.Get(context.TODO(), optionsHistoryPrefix+volname+"/",
+		clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]api.VolOptionsHistoryEntry, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var entry api.VolOptionsHistoryEntry
+		if err := json.Unmarshal(kv.Value, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GetOptionsHistoryEntry returns the option change of the volume with the given ID
+func GetOptionsHistoryEntry(volname, id string) (*api.VolOptionsHistoryEntry, error) {
+	resp, err := store.Get(context.TODO(), optionsHistoryKey(volname, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, gderrors.ErrOptionsHistoryNotFound
+	}
+
+	var entry api.VolOptionsHistoryEntry
+	if err := json.Unmarshal(resp.Kvs[0].Value, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// DeleteOptionsHistory deletes the options history of the volume
+func DeleteOptionsHistory(volname string) error {
+	_, err := store.Delete(context.TODO(), optionsHistoryPrefix+volname+"/", clientv3.WithPrefix())
+	return err
+}
@@ -0,0 +1,134 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+// profilePrefix holds the volume profiles, keyed by the profile name
+const profilePrefix = "volume-profiles/"
+
+// AddVolumeProfile saves the volume profile. ErrVolProfileExists is returned
+// if a profile with the name exists.
+func AddVolumeProfile(p *api.VolumeProfile) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	key := profilePrefix + p.Name
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(b))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return gderrors.ErrVolProfileExists
+	}
+	return nil
+}
+
+// GetVolumeProfile returns the volume profile with the name
+func GetVolumeProfile(name string) (*api.VolumeProfile, error) {
+	resp, err := store.Get(context.TODO(), profilePrefix+name)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, gderrors.ErrVolProfileNotFound
+	}
+
+	var p api.VolumeProfile
+	if err := json.Unmarshal(resp.Kvs[0].Value, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// GetVolumeProfiles returns all the volume profiles, sorted by name
+func GetVolumeProfiles() ([]*api.VolumeProfile, error) {
+	resp, err := store.Get(context.TODO(), profilePrefix, clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]*api.VolumeProfile, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var p api.VolumeProfile
+		if err := json.Unmarshal(kv.Value, &p); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal volume profile")
+			continue
+		}
+		profiles = append(profiles, &p)
+	}
+
+	return profiles, nil
+}
+
+// DeleteVolumeProfile deletes the volume profile with the name. Volumes
+// created with the profile are not affected.
+func DeleteVolumeProfile(name string) error {
+	resp, err := store.Delete(context.TODO(), profilePrefix+name)
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return gderrors.ErrVolProfileNotFound
+	}
+	return nil
+}
+
+// ApplyVolumeProfile fills the options and the layout hints of the volume
+// create request left unset with those of the profile. The option flags of the
+// profile are allowed for the request too, as its options were validated
+// with them.
+func ApplyVolumeProfile(req *api.VolCreateReq, p *api.VolumeProfile) {
+	if req.Options == nil {
+		req.Options = make(map[string]string)
+	}
+	for k, v := range p.Options {
+		if _, ok := req.Options[k]; !ok {
+			req.Options[k] = v
+		}
+	}
+	req.AllowAdvanced = req.AllowAdvanced || p.AllowAdvanced
+	req.AllowExperimental = req.AllowExperimental || p.AllowExperimental
+	req.AllowDeprecated = req.AllowDeprecated || p.AllowDeprecated
+
+	// The subvolume layout of the profile is only used as a whole, if the
+	// request doesn't ask for a layout
+	if req.ReplicaCount == 0 && req.ArbiterCount == 0 && req.DisperseCount == 0 &&
+		req.DisperseDataCount == 0 && req.DisperseRedundancyCount == 0 {
+		req.ReplicaCount = p.ReplicaCount
+		req.ArbiterCount = p.ArbiterCount
+		req.DisperseCount = p.DisperseCount
+		req.DisperseRedundancyCount = p.DisperseRedundancyCount
+	}
+	if req.MaxBrickSize == 0 {
+		req.MaxBrickSize = p.MaxBrickSize
+	}
+	if req.AverageFileSize == 0 {
+		req.AverageFileSize = p.AverageFileSize
+	}
+	if !req.SnapshotEnabled {
+		req.SnapshotEnabled = p.SnapshotEnabled
+	}
+	if req.SnapshotReserveFactor == 0 {
+		req.SnapshotReserveFactor = p.SnapshotReserveFactor
+	}
+	if req.Transport == "" {
+		req.Transport = p.Transport
+	}
+}
@@ -0,0 +1,83 @@
+package volume
+
+import (
+	"context"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	volumesDesc = prometheus.NewDesc(
+		"glusterd2_volumes",
+		"Number of volumes in the cluster by state.",
+		[]string{"state"}, nil,
+	)
+	brickUpDesc = prometheus.NewDesc(
+		"glusterd2_brick_up",
+		"Whether the process of a brick of a started volume on this node is running.",
+		[]string{"volume", "brick"}, nil,
+	)
+)
+
+// promCollector exports the number of volumes by state, and the state of
+// the processes of the local bricks of started volumes. They are read from
+// the store when the metrics are scraped.
+type promCollector struct{}
+
+func init() {
+	prometheus.MustRegister(promCollector{})
+}
+
+// Describe implements prometheus.Collector
+func (promCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- volumesDesc
+	ch <- brickUpDesc
+}
+
+// Collect implements prometheus.Collector
+func (promCollector) Collect(ch chan<- prometheus.Metric) {
+	if store.Store == nil {
+		return
+	}
+
+	volumes, err := GetVolumes(context.TODO())
+	if err != nil {
+		log.WithError(err).Error("failed to get volumes for metrics")
+		return
+	}
+
+	counts := make(map[VolState]int)
+	for _, v := range volumes {
+		counts[v.State]++
+		if v.State != VolStarted {
+			continue
+		}
+		for _, b := range v.GetLocalBricks() {
+			up := 0.0
+			if brickRunning(b) {
+				up = 1
+			}
+			ch <- prometheus.MustNewConstMetric(brickUpDesc, prometheus.GaugeValue, up, v.Name, b.String())
+		}
+	}
+
+	for _, s := range []VolState{VolCreated, VolStarted, VolStopped} {
+		ch <- prometheus.MustNewConstMetric(volumesDesc, prometheus.GaugeValue,
+			float64(counts[s]), api.VolState(s).String())
+	}
+}
+
+func brickRunning(b brick.Brickinfo) bool {
+	d, err := brick.NewGlusterfsd(b)
+	if err != nil {
+		return false
+	}
+	running, _ := daemon.IsRunning(d)
+	return running
+}
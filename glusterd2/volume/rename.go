@@ -0,0 +1,154 @@
+package volume
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// settingsPrefixes lists the prefixes of the keys holding the settings of a
+// volume under its name, which are moved along with the volinfo when the
+// volume is renamed, and deleted when the volume is deleted
+var settingsPrefixes = []string{usageProtectPrefix, ioThrottlePrefix, autoExpandPrefix}
+
+// RegisterSettingsPrefix registers a prefix under which settings of a volume
+// are stored keyed by the volume name, so that the settings are renamed and
+// deleted along with the volume. It is meant to be called from init
+// functions of plugins.
+func RegisterSettingsPrefix(prefix string) {
+	settingsPrefixes = append(settingsPrefixes, prefix)
+}
+
+// RenamedVolinfo returns a copy of the volinfo renamed to newName. The names
+// of the subvolumes and the volume names recorded in the bricks are updated
+// along with it.
+func RenamedVolinfo(v *Volinfo, newName string) *Volinfo {
+	renamed := *v
+	renamed.Name = newName
+	if v.VolfileID == v.Name {
+		renamed.VolfileID = newName
+	}
+	renamed.Subvols = renameSubvols(v.Subvols, v.Name, newName)
+	return &renamed
+}
+
+func renameSubvols(subvols []Subvol, oldName, newName string) []Subvol {
+	if subvols == nil {
+		return nil
+	}
+
+	renamed := make([]Subvol, len(subvols))
+	for i, sv := range subvols {
+		if strings.HasPrefix(sv.Name, oldName+"-") {
+			sv.Name = newName + sv.Name[len(oldName):]
+		}
+
+		bricks := make([]brick.Brickinfo, len(sv.Bricks))
+		for j, b := range sv.Bricks {
+			b.VolumeName = newName
+			if b.VolfileID == oldName {
+				b.VolfileID = newName
+			}
+			bricks[j] = b
+		}
+		sv.Bricks = bricks
+		sv.Subvols = renameSubvols(sv.Subvols, oldName, newName)
+
+		renamed[i] = sv
+	}
+	return renamed
+}
+
+// RenameVolume atomically moves the volume from oldName to the name of v in the
+// name index, along with the settings and the options history of the volume,
+// and stores the volinfo. The
+// rename fails with ErrVolinfoConflict if the volinfo was modified after the
+// revision in v, and with ErrVolExists if a volume with the new name exists.
+// The metrics samples of the volume are not moved.
+func RenameVolume(oldName string, v *Volinfo) error {
+	value, err := marshalVolinfo(v)
+	if err != nil {
+		return err
+	}
+
+	key := volinfoKey(v.ID)
+	oldIndex, newIndex := volumeIndexKey(oldName), volumeIndexKey(v.Name)
+	cmps := []clientv3.Cmp{
+		clientv3.Compare(clientv3.ModRevision(key), "=", v.ModRevision),
+		clientv3.Compare(clientv3.Value(oldIndex), "=", v.ID.String()),
+		clientv3.Compare(clientv3.CreateRevision(newIndex), "=", 0),
+	}
+	ops := []clientv3.Op{
+		clientv3.OpPut(key, string(value)),
+		clientv3.OpDelete(oldIndex),
+		clientv3.OpPut(newIndex, v.ID.String()),
+	}
+
+	// The keys moved are compared on their revisions too, so that changes
+	// made to them concurrently are not lost
+	move := func(kvs []*mvccKV, from, to string) {
+		for _, kv := range kvs {
+			key := to + strings.TrimPrefix(kv.key, from)
+			cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(kv.key), "=", kv.modRev))
+			ops = append(ops, clientv3.OpDelete(kv.key), clientv3.OpPut(key, kv.value))
+		}
+	}
+
+	for _, prefix := range settingsPrefixes {
+		kvs, err := getKVs(prefix+oldName, false)
+		if err != nil {
+			return err
+		}
+		move(kvs, prefix+oldName, prefix+v.Name)
+	}
+
+	kvs, err := getKVs(optionsHistoryPrefix+oldName+"/", true)
+	if err != nil {
+		return err
+	}
+	move(kvs, optionsHistoryPrefix+oldName+"/", optionsHistoryPrefix+v.Name+"/")
+
+	resp, err := store.Txn(context.TODO()).If(cmps...).Then(ops...).Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		if Exists(v.Name) {
+			return gderrors.ErrVolExists
+		}
+		return gderrors.ErrVolinfoConflict
+	}
+
+	volCache.put(v.ID.String(), value, resp.Header.Revision)
+	v.ModRevision = resp.Header.Revision
+	return nil
+}
+
+type mvccKV struct {
+	key    string
+	value  string
+	modRev int64
+}
+
+func getKVs(key string, prefix bool) ([]*mvccKV, error) {
+	var opts []clientv3.OpOption
+	if prefix {
+		opts = append(opts, clientv3.WithPrefix())
+	}
+
+	resp, err := store.Get(context.TODO(), key, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	kvs := make([]*mvccKV, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		kvs = append(kvs, &mvccKV{key: string(kv.Key), value: string(kv.Value), modRev: kv.ModRevision})
+	}
+	return kvs, nil
+}
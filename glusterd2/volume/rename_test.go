@@ -0,0 +1,38 @@
+package volume
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/testutils"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisteredSettingsPrefix(t *testing.T) {
+	defer startTestStore(t)()
+	defer testutils.Patch(&settingsPrefixes, append([]string(nil), settingsPrefixes...)).Restore()
+
+	const prefix = "volume-test-settings/"
+	RegisterSettingsPrefix(prefix)
+
+	v := &Volinfo{ID: uuid.NewRandom(), Name: "vol1"}
+	require.NoError(t, AddOrUpdateVolume(v))
+	_, err := store.Put(context.TODO(), prefix+"vol1", "settings")
+	require.NoError(t, err)
+
+	// The settings are moved along with the volume when it is renamed
+	v, err = GetVolume("vol1")
+	require.NoError(t, err)
+	require.NoError(t, RenameVolume("vol1", RenamedVolinfo(v, "vol2")))
+	kvs := storedKeys(t)
+	assert.NotContains(t, kvs, prefix+"vol1")
+	assert.Equal(t, "settings", kvs[prefix+"vol2"])
+
+	// and deleted when it is deleted
+	require.NoError(t, DeleteVolume("vol2"))
+	assert.NotContains(t, storedKeys(t), prefix+"vol2")
+}
@@ -0,0 +1,175 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// revisionLogPrefix holds the store revisions at which the volinfo of
+	// a volume was modified, keyed by the volume ID and the time of the
+	// modification, so that the volinfo at a given time can be got from
+	// the store history.
+	revisionLogPrefix = "volume-revisions/"
+	// MaxRevisionLog is the number of volinfo modifications recorded per
+	// volume
+	MaxRevisionLog = 200
+)
+
+func revisionLogKey(id uuid.UUID, t time.Time) string {
+	// zero padded so that entries sort in order of time
+	return fmt.Sprintf("%s%s/%020d", revisionLogPrefix, id, t.UnixNano())
+}
+
+// recordRevision records the store revision at which the volinfo of the
+// volume was modified. Only the latest MaxRevisionLog modifications are
+// retained.
+func recordRevision(id uuid.UUID, rev int64) {
+	logger := log.WithField("volume-id", id.String())
+
+	if _, err := store.Put(context.TODO(), revisionLogKey(id, time.Now()), strconv.FormatInt(rev, 10)); err != nil {
+		logger.WithError(err).Warn("failed to record volinfo revision")
+		return
+	}
+
+	resp, err := store.Get(context.TODO(), revisionLogPrefix+id.String()+"/",
+		clientv3.WithPrefix(), clientv3.WithKeysOnly(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		logger.WithError(err).Warn("failed to get volinfo revisions")
+		return
+	}
+
+	for i := 0; i < len(resp.Kvs)-MaxRevisionLog; i++ {
+		if _, err := store.Delete(context.TODO(), string(resp.Kvs[i].Key)); err != nil {
+			logger.WithError(err).Warn("failed to delete volinfo revision")
+			return
+		}
+	}
+}
+
+// deleteRevisionLog deletes the recorded revisions of the volume
+func deleteRevisionLog(id uuid.UUID) error {
+	_, err := store.Delete(context.TODO(), revisionLogPrefix+id.String()+"/", clientv3.WithPrefix())
+	return err
+}
+
+// RevisionAt returns the store revision of the volinfo of the volume as it
+// was at the given time. ErrVolRevisionNotFound is returned if no
+// modification of the volinfo was recorded at or before the time.
+func RevisionAt(name string, t time.Time) (int64, error) {
+	id, err := getVolumeID(context.TODO(), name)
+	if err != nil {
+		return 0, err
+	}
+
+	// The latest modification at or before t
+	resp, err := store.Get(context.TODO(), revisionLogPrefix+id.String()+"/",
+		clientv3.WithRange(revisionLogKey(id, t.Add(time.Nanosecond))),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend),
+		clientv3.WithLimit(1))
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return 0, gderrors.ErrVolRevisionNotFound
+	}
+
+	return strconv.ParseInt(string(resp.Kvs[0].Value), 10, 64)
+}
+
+// GetVolumeAtRevision returns the volinfo of the volume as it was at the
+// given store revision. The volume is looked up by its current name.
+// ErrRevisionCompacted is returned if the revision is no longer available in
+// the store, and ErrVolNotFound if the volume didn't exist at the revision.
+func GetVolumeAtRevision(name string, rev int64) (*Volinfo, error) {
+	id, err := getVolumeID(context.TODO(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := store.Get(context.TODO(), volinfoKey(id), clientv3.WithRev(rev))
+	switch err {
+	case nil:
+	case rpctypes.ErrCompacted:
+		return nil, gderrors.ErrRevisionCompacted
+	case rpctypes.ErrFutureRev:
+		return nil, gderrors.ErrFutureRevision
+	default:
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, gderrors.ErrVolNotFound
+	}
+
+	var v Volinfo
+	if err := json.Unmarshal(resp.Kvs[0].Value, &v); err != nil {
+		return nil, err
+	}
+	v.ModRevision = resp.Kvs[0].ModRevision
+	return &v, nil
+}
+
+// Diff returns the changes made to the volume from the volinfo old to the
+// volinfo new
+func Diff(old, new *Volinfo) api.VolDiffResp {
+	diff := api.VolDiffResp{
+		FromRevision: old.ModRevision,
+		ToRevision:   new.ModRevision,
+		Fields:       OptionsDiff(volinfoFields(old), volinfoFields(new)),
+		Options:      OptionsDiff(old.Options, new.Options),
+		Metadata:     OptionsDiff(old.Metadata, new.Metadata),
+	}
+
+	// Bricks are matched by their peer and path, as replaced bricks get
+	// new IDs
+	brickKey := func(b *brick.Brickinfo) string {
+		return b.PeerID.String() + ":" + b.Path
+	}
+	oldBricks := make(map[string]bool)
+	for _, b := range old.GetBricks() {
+		oldBricks[brickKey(&b)] = true
+	}
+	newBricks := make(map[string]bool)
+	for _, b := range new.GetBricks() {
+		newBricks[brickKey(&b)] = true
+		if !oldBricks[brickKey(&b)] {
+			diff.BricksAdded = append(diff.BricksAdded, brick.CreateBrickInfo(&b))
+		}
+	}
+	for _, b := range old.GetBricks() {
+		if !newBricks[brickKey(&b)] {
+			diff.BricksRemoved = append(diff.BricksRemoved, brick.CreateBrickInfo(&b))
+		}
+	}
+
+	return diff
+}
+
+// volinfoFields returns the attributes of the volume compared by Diff, other
+// than its options, metadata and bricks
+func volinfoFields(v *Volinfo) map[string]string {
+	return map[string]string{
+		"name":                    v.Name,
+		"state":                   api.VolState(v.State).String(),
+		"type":                    v.Type.String(),
+		"transport":               v.Transport,
+		"distribute-count":        strconv.Itoa(v.DistCount),
+		"subvol-count":            strconv.Itoa(len(v.Subvols)),
+		"snapshot-reserve-factor": strconv.FormatFloat(v.SnapshotReserveFactor, 'f', -1, 64),
+		"capacity":                strconv.FormatUint(v.Capacity, 10),
+	}
+}
@@ -0,0 +1,60 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	peerID := uuid.NewRandom()
+	b1 := brick.Brickinfo{ID: uuid.NewRandom(), PeerID: peerID, Path: "/bricks/b1"}
+	b2 := brick.Brickinfo{ID: uuid.NewRandom(), PeerID: peerID, Path: "/bricks/b2"}
+	b3 := brick.Brickinfo{ID: uuid.NewRandom(), PeerID: peerID, Path: "/bricks/b3"}
+
+	old := &Volinfo{
+		Name:        "vol1",
+		State:       VolCreated,
+		Options:     map[string]string{"io-stats.count-fop-hits": "on", "afr.eager-lock": "on"},
+		Subvols:     []Subvol{{Bricks: []brick.Brickinfo{b1, b2}}},
+		ModRevision: 10,
+	}
+	new := &Volinfo{
+		Name:        "vol1",
+		State:       VolStarted,
+		Options:     map[string]string{"afr.eager-lock": "off", "dht.readdir-optimize": "on"},
+		Subvols:     []Subvol{{Bricks: []brick.Brickinfo{b1, b3}}},
+		ModRevision: 20,
+	}
+	// A replaced brick at the same path isn't a change
+	new.Subvols[0].Bricks[0].ID = uuid.NewRandom()
+
+	diff := Diff(old, new)
+	assert.Equal(t, int64(10), diff.FromRevision)
+	assert.Equal(t, int64(20), diff.ToRevision)
+	assert.Equal(t, map[string]api.VolOptionChange{
+		"state": {Old: "Created", New: "Started"},
+	}, diff.Fields)
+	assert.Equal(t, map[string]api.VolOptionChange{
+		"io-stats.count-fop-hits": {Old: "on"},
+		"afr.eager-lock":          {Old: "on", New: "off"},
+		"dht.readdir-optimize":    {New: "on"},
+	}, diff.Options)
+	assert.Empty(t, diff.Metadata)
+
+	assert.Len(t, diff.BricksAdded, 1)
+	assert.Equal(t, "/bricks/b3", diff.BricksAdded[0].Path)
+	assert.Len(t, diff.BricksRemoved, 1)
+	assert.Equal(t, "/bricks/b2", diff.BricksRemoved[0].Path)
+
+	// No changes
+	diff = Diff(new, new)
+	assert.Empty(t, diff.Fields)
+	assert.Empty(t, diff.Options)
+	assert.Empty(t, diff.BricksAdded)
+	assert.Empty(t, diff.BricksRemoved)
+}
@@ -3,19 +3,33 @@ package volume
 import (
 	"context"
 	"encoding/json"
+	"sort"
+	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	gderror "github.com/gluster/glusterd2/pkg/errors"
 
 	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 )
 
 const (
-	volumePrefix string = "volumes/"
+	// volinfoPrefix holds the volinfos keyed by the IDs of the volumes.
+	// Everything under it is expected to be a volinfo, so other volume
+	// data is stored under prefixes of its own, like the volume-* ones.
+	volinfoPrefix = "volinfos/"
+	// volumeIndexPrefix indexes the IDs of the volumes by their names.
+	// Volinfos and their index entries are always updated together in
+	// store transactions.
+	volumeIndexPrefix = "volume-index/"
+
+	// maxVolinfoUpdateRetries is the number of times UpdateVolume retries
+	// an update of the latest volinfo on conflicts
+	maxVolinfoUpdateRetries = 5
 )
 
 // metadataFilter is a filter type
@@ -34,27 +48,197 @@ var (
 	AddOrUpdateVolumeFunc = AddOrUpdateVolume
 )
 
-// AddOrUpdateVolume marshals to volume object and passes to store to add/update
+func volinfoKey(id uuid.UUID) string {
+	return volinfoPrefix + id.String()
+}
+
+func volumeIndexKey(name string) string {
+	return volumeIndexPrefix + name
+}
+
+// getVolumeID looks up the ID of the volume in the name index
+func getVolumeID(ctx context.Context, name string) (uuid.UUID, error) {
+	resp, err := store.Get(ctx, volumeIndexKey(name))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderror.ErrVolNotFound
+	}
+	return uuid.Parse(string(resp.Kvs[0].Value)), nil
+}
+
+// AddOrUpdateVolume marshals to volume object and passes to store to add/update.
+// A new volume is added to the name index, and ErrVolExists is returned if
+// the name is taken by another volume. The bricks of the volume are updated
+// in the brick index, and ErrBrickPathAlreadyInUse is returned if any of the
+// bricks added is a brick of another volume.
 func AddOrUpdateVolume(v *Volinfo) error {
-	json, e := json.Marshal(v)
+	value, e := marshalVolinfo(v)
 	if e != nil {
 		log.WithError(e).Error("Failed to marshal the volinfo object")
 		return e
 	}
 
-	_, e = store.Put(context.TODO(), volumePrefix+v.Name, string(json))
-	if e != nil {
-		log.WithError(e).Error("Couldn't add volume to store")
-		return e
+	key, index := volinfoKey(v.ID), volumeIndexKey(v.Name)
+	for i := 0; ; i++ {
+		// The brick index is updated from the bricks of the stored
+		// volinfo
+		var (
+			oldBricks []brick.Brickinfo
+			rev       int64
+		)
+		old, e := GetVolumeByID(v.ID)
+		if e == nil {
+			oldBricks, rev = old.GetBricks(), old.ModRevision
+		} else if e != gderror.ErrVolNotFound {
+			return e
+		}
+
+		cmps, ops := brickIndexChanges(v.ID, oldBricks, v.GetBricks())
+		resp, e := store.Txn(context.TODO()).
+			If(append([]clientv3.Cmp{clientv3.Compare(clientv3.ModRevision(key), "=", rev)}, cmps...)...).
+			Then(clientv3.OpTxn(
+				[]clientv3.Cmp{clientv3.Compare(clientv3.CreateRevision(index), "=", 0)},
+				append([]clientv3.Op{clientv3.OpPut(key, string(value)), clientv3.OpPut(index, v.ID.String())}, ops...),
+				[]clientv3.Op{clientv3.OpTxn(
+					[]clientv3.Cmp{clientv3.Compare(clientv3.Value(index), "=", v.ID.String())},
+					append([]clientv3.Op{clientv3.OpPut(key, string(value))}, ops...),
+					nil)})).
+			Else(clientv3.OpGet(key, clientv3.WithKeysOnly())).
+			Commit()
+		if e != nil {
+			log.WithError(e).Error("Couldn't add volume to store")
+			return e
+		}
+
+		if !resp.Succeeded {
+			if volinfoRevision(resp.Responses[0].GetResponseRange()) == rev {
+				return gderror.ErrBrickPathAlreadyInUse
+			}
+			if i >= maxVolinfoUpdateRetries {
+				return gderror.ErrVolinfoConflict
+			}
+			log.WithField("volume", v.Name).Debug("volinfo was modified concurrently, retrying update")
+			continue
+		}
+
+		txnResp := resp.Responses[0].GetResponseTxn()
+		if !txnResp.Succeeded && !txnResp.Responses[0].GetResponseTxn().Succeeded {
+			return gderror.ErrVolExists
+		}
+		volCache.put(v.ID.String(), value, resp.Header.Revision)
+		v.ModRevision = resp.Header.Revision
+		recordRevision(v.ID, resp.Header.Revision)
+		return nil
 	}
-	return nil
+}
+
+// volinfoRevision returns the mod revision of the volinfo got, 0 if there
+// was no volinfo
+func volinfoRevision(resp *etcdserverpb.RangeResponse) int64 {
+	if len(resp.Kvs) == 0 {
+		return 0
+	}
+	return resp.Kvs[0].ModRevision
+}
+
+// UpdateVolume updates the volinfo of the volume in the store with fn, using
+// etcd transactions on the mod revision of the volinfo so that concurrent
+// updates are not lost.
+//
+// If modRevision isn't 0, it is the revision of the volinfo the update was
+// made from, and ErrVolinfoConflict is returned if the volinfo has been
+// modified after it. Otherwise fn is applied to the latest volinfo, and is
+// retried on the latest volinfo again if it is modified concurrently.
+func UpdateVolume(name string, modRevision int64, fn func(*Volinfo) error) (*Volinfo, error) {
+	for i := 0; ; i++ {
+		v, err := GetVolume(name)
+		if err != nil {
+			return nil, err
+		}
+		if modRevision != 0 && v.ModRevision != modRevision {
+			return nil, gderror.ErrVolinfoConflict
+		}
+
+		rev, oldBricks := v.ModRevision, v.GetBricks()
+		if err := fn(v); err != nil {
+			return nil, err
+		}
+
+		value, err := marshalVolinfo(v)
+		if err != nil {
+			return nil, err
+		}
+
+		// The volume must not have been renamed either
+		key := volinfoKey(v.ID)
+		cmps, ops := brickIndexChanges(v.ID, oldBricks, v.GetBricks())
+		resp, err := store.Txn(context.TODO()).
+			If(append([]clientv3.Cmp{
+				clientv3.Compare(clientv3.ModRevision(key), "=", rev),
+				clientv3.Compare(clientv3.Value(volumeIndexKey(name)), "=", v.ID.String()),
+			}, cmps...)...).
+			Then(append([]clientv3.Op{clientv3.OpPut(key, string(value))}, ops...)...).
+			Else(clientv3.OpGet(key, clientv3.WithKeysOnly()), clientv3.OpGet(volumeIndexKey(name))).
+			Commit()
+		if err != nil {
+			return nil, err
+		}
+		if resp.Succeeded {
+			volCache.put(v.ID.String(), value, resp.Header.Revision)
+			v.ModRevision = resp.Header.Revision
+			recordRevision(v.ID, resp.Header.Revision)
+			return v, nil
+		}
+
+		indexResp := resp.Responses[1].GetResponseRange()
+		if volinfoRevision(resp.Responses[0].GetResponseRange()) == rev &&
+			len(indexResp.Kvs) == 1 && string(indexResp.Kvs[0].Value) == v.ID.String() {
+			return nil, gderror.ErrBrickPathAlreadyInUse
+		}
+
+		if modRevision != 0 || i >= maxVolinfoUpdateRetries {
+			return nil, gderror.ErrVolinfoConflict
+		}
+		log.WithField("volume", name).Debug("volinfo was modified concurrently, retrying update")
+	}
+}
+
+// marshalVolinfo marshals the volinfo to be saved in the store, without its
+// mod revision
+func marshalVolinfo(v *Volinfo) ([]byte, error) {
+	stored := *v
+	stored.ModRevision = 0
+	return json.Marshal(&stored)
 }
 
 // GetVolume fetches the json object from the store and unmarshalls it into
 // volinfo object
 func GetVolume(name string) (*Volinfo, error) {
+	id, e := getVolumeID(context.TODO(), name)
+	if e != nil {
+		if e != gderror.ErrVolNotFound {
+			log.WithError(e).Error("Couldn't retrive volume from store")
+		}
+		return nil, e
+	}
+
+	v, e := GetVolumeByID(id)
+	if e != nil {
+		return nil, e
+	}
+	// Renamed after its ID was looked up
+	if v.Name != name {
+		return nil, gderror.ErrVolNotFound
+	}
+	return v, nil
+}
+
+// GetVolumeByID fetches the volinfo of the volume with the ID
+func GetVolumeByID(id uuid.UUID) (*Volinfo, error) {
 	var v Volinfo
-	resp, e := store.Get(context.TODO(), volumePrefix+name)
+	resp, e := getVolinfoKVs(context.TODO(), volinfoKey(id))
 	if e != nil {
 		log.WithError(e).Error("Couldn't retrive volume from store")
 		return nil, e
@@ -68,18 +252,51 @@ func GetVolume(name string) (*Volinfo, error) {
 		log.WithError(e).Error("Failed to unmarshal the data into volinfo object")
 		return nil, e
 	}
+	v.ModRevision = resp.Kvs[0].ModRevision
 	return &v, nil
 }
 
 //DeleteVolume passes the volname to store to delete the volume object
 func DeleteVolume(name string) error {
-	_, e := store.Delete(context.TODO(), volumePrefix+name)
-	return e
+	for i := 0; ; i++ {
+		v, e := GetVolume(name)
+		if e == gderror.ErrVolNotFound {
+			return nil
+		} else if e != nil {
+			return e
+		}
+
+		// The index entry is only deleted if it still is of the volume
+		key, index := volinfoKey(v.ID), volumeIndexKey(name)
+		_, ops := brickIndexChanges(v.ID, v.GetBricks(), nil)
+		resp, e := store.Txn(context.TODO()).
+			If(
+				clientv3.Compare(clientv3.ModRevision(key), "=", v.ModRevision),
+				clientv3.Compare(clientv3.Value(index), "=", v.ID.String()),
+			).
+			Then(append([]clientv3.Op{clientv3.OpDelete(key), clientv3.OpDelete(index)}, ops...)...).
+			Commit()
+		if e != nil {
+			return e
+		}
+		if resp.Succeeded {
+			volCache.delete(v.ID.String())
+			if err := deleteRevisionLog(v.ID); err != nil {
+				return err
+			}
+			return deleteVolumeSettings(name)
+		}
+
+		if i >= maxVolinfoUpdateRetries {
+			return gderror.ErrVolinfoConflict
+		}
+		log.WithField("volume", name).Debug("volinfo was modified concurrently, retrying delete")
+	}
 }
 
 // GetVolumesList returns a map of volume names to their UUIDs
 func GetVolumesList() (map[string]uuid.UUID, error) {
-	resp, e := store.Get(context.TODO(), volumePrefix, clientv3.WithPrefix())
+	resp, e := store.Get(context.TODO(), volumeIndexPrefix, clientv3.WithPrefix())
 	if e != nil {
 		return nil, e
 	}
@@ -87,14 +304,7 @@ func GetVolumesList() (map[string]uuid.UUID, error) {
 	volumes := make(map[string]uuid.UUID)
 
 	for _, kv := range resp.Kvs {
-		var vol Volinfo
-
-		if err := json.Unmarshal(kv.Value, &vol); err != nil {
-			log.WithError(err).WithField("volume", string(kv.Key)).Error("Failed to unmarshal volume")
-			continue
-		}
-
-		volumes[vol.Name] = vol.ID
+		volumes[strings.TrimPrefix(string(kv.Key), volumeIndexPrefix)] = uuid.Parse(string(kv.Value))
 	}
 
 	return volumes, nil
@@ -114,6 +324,27 @@ func getFilterType(filterParams map[string]string) metadataFilter {
 	return noKeyAndValue
 }
 
+// matchMetadata checks if the volume metadata matches the filter
+func matchMetadata(metadata map[string]string, filterType metadataFilter, filterParams map[string]string) bool {
+	switch filterType {
+	case onlyKey:
+		_, keyFound := metadata[filterParams["key"]]
+		return keyFound
+	case onlyValue:
+		for _, value := range metadata {
+			if value == filterParams["value"] {
+				return true
+			}
+		}
+		return false
+	case keyAndValue:
+		value, keyFound := metadata[filterParams["key"]]
+		return keyFound && value == filterParams["value"]
+	default:
+		return true
+	}
+}
+
 //GetVolumes retrives the json objects from the store and converts them into
 //respective volinfo objects
 func GetVolumes(ctx context.Context, filterParams ...map[string]string) ([]*Volinfo, error) {
@@ -123,16 +354,18 @@ func GetVolumes(ctx context.Context, filterParams ...map[string]string) ([]*Voli
 		defer span.End()
 	}
 
-	resp, e := store.Get(ctx, volumePrefix, clientv3.WithPrefix())
+	resp, e := getVolinfoKVs(ctx, volinfoPrefix, clientv3.WithPrefix())
 	if e != nil {
 		return nil, e
 	}
 
-	var filterType metadataFilter
-	if len(filterParams) == 0 {
-		filterType = noKeyAndValue
-	} else {
-		filterType = getFilterType(filterParams[0])
+	var params map[string]string
+	if len(filterParams) != 0 {
+		params = filterParams[0]
+	}
+	filter, err := newVolumeFilter(params)
+	if err != nil {
+		return nil, err
 	}
 
 	var volumes []*Volinfo
@@ -144,48 +377,16 @@ func GetVolumes(ctx context.Context, filterParams ...map[string]string) ([]*Voli
 			log.WithError(err).WithField("volume", string(kv.Key)).Error("Failed to unmarshal volume")
 			continue
 		}
-		switch filterType {
-
-		case onlyKey:
-			if _, keyFound := vol.Metadata[filterParams[0]["key"]]; keyFound {
-				volumes = append(volumes, &vol)
-			}
-		case onlyValue:
-			for _, value := range vol.Metadata {
-				if value == filterParams[0]["value"] {
-					volumes = append(volumes, &vol)
-				}
-			}
-		case keyAndValue:
-			if value, keyFound := vol.Metadata[filterParams[0]["key"]]; keyFound {
-				if value == filterParams[0]["value"] {
-					volumes = append(volumes, &vol)
-				}
-			}
-		default:
+		vol.ModRevision = kv.ModRevision
+		if filter.match(vol.Name, vol.Type, vol.State, vol.Metadata) {
 			volumes = append(volumes, &vol)
-
 		}
 	}
 
-	return volumes, nil
-}
-
-// GetAllBricksInCluster returns all bricks in the cluster. These bricks
-// belong to different volumes.
-func GetAllBricksInCluster() ([]brick.Brickinfo, error) {
-
-	volumes, err := GetVolumes(context.TODO())
-	if err != nil {
-		return nil, err
-	}
-
-	var bricks []brick.Brickinfo
-	for _, volinfo := range volumes {
-		bricks = append(bricks, volinfo.GetBricks()...)
-	}
+	// Volinfos are keyed by ID, but are listed in the order of their names
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
 
-	return bricks, nil
+	return volumes, nil
 }
 
 // AreReplicateVolumesRunning checks if all replicate and disperse volumes are stopped.
@@ -210,7 +411,7 @@ func AreReplicateVolumesRunning(skipVolID uuid.UUID) (bool, error) {
 
 //Exists check whether a given volume exist or not
 func Exists(name string) bool {
-	resp, e := store.Get(context.TODO(), volumePrefix+name)
+	resp, e := store.Get(context.TODO(), volumeIndexKey(name), clientv3.WithCountOnly())
 	if e != nil {
 		return false
 	}
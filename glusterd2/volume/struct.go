@@ -94,11 +94,20 @@ type Volinfo struct {
 	Version               uint64
 	Subvols               []Subvol
 	Auth                  VolAuth
+	ACL                   VolACL
+	SubdirExports         []SubdirExport
 	GraphMap              map[string]string
 	Metadata              map[string]string
 	SnapList              []string
 	SnapshotReserveFactor float64
 	Capacity              uint64
+	// BrickNaming is the naming scheme of the auto provisioned bricks of
+	// the volume
+	BrickNaming int `json:",omitempty"`
+	// ModRevision is the store revision the volinfo was last modified at.
+	// It isn't saved in the store, but is kept in transaction contexts so
+	// that transaction steps can detect concurrent updates of the volinfo.
+	ModRevision int64 `json:",omitempty"`
 }
 
 // VolAuth represents username and password used by trusted/internal clients
@@ -122,6 +131,12 @@ func (v *Volinfo) StringMap() map[string]string {
 	m["volume.transport"] = v.Transport
 	m["volume.auth.username"] = v.Auth.Username
 	m["volume.auth.password"] = v.Auth.Password
+	for k, val := range v.ACL.aclStringMap() {
+		m[k] = val
+	}
+	if entries := v.subdirAuthEntries(); len(entries) != 0 {
+		m["volume.acl.allow"] += "," + strings.Join(entries, ",")
+	}
 
 	return m
 }
@@ -151,6 +166,9 @@ func NewBrickEntries(bricks []api.BrickReq, volName, volfileID string, volID uui
 		if e != nil {
 			return nil, e
 		}
+		if p.IsManagementOnly() {
+			return nil, fmt.Errorf("peer %s is a management node and cannot host bricks", b.PeerID)
+		}
 
 		binfo.PeerID = u
 		// TODO: Have a better way to select peer address here
@@ -0,0 +1,100 @@
+package volume
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+// SubdirExport represents a subdirectory of a volume which is exported to
+// be mounted on its own by clients
+type SubdirExport struct {
+	// Path is the path of the subdirectory relative to the root of the
+	// volume, eg. /tenant1
+	Path string
+	// Clients is the list of client addresses allowed to mount the
+	// subdirectory. All clients are allowed if it is empty.
+	Clients []string
+}
+
+// CleanSubdirPath returns the normalized form of a subdirectory path
+func CleanSubdirPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// Validate validates the subdirectory export
+func (s *SubdirExport) Validate() error {
+	if s.Path == "" || s.Path == "/" || s.Path != CleanSubdirPath(s.Path) {
+		return fmt.Errorf("invalid subdirectory path %q", s.Path)
+	}
+	// These characters are used as separators in the auth options of the
+	// bricks
+	if strings.ContainsAny(s.Path, "(),| \t\n") {
+		return fmt.Errorf("subdirectory path %q contains invalid characters", s.Path)
+	}
+
+	for _, addr := range s.Clients {
+		if err := validateACLAddr(addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// authEntry returns the subdirectory export in the format understood by the
+// address authentication module of the bricks, eg. /tenant1(10.0.0.1|10.0.0.2)
+func (s *SubdirExport) authEntry() string {
+	clients := "*"
+	if len(s.Clients) != 0 {
+		clients = strings.Join(s.Clients, "|")
+	}
+	return fmt.Sprintf("%s(%s)", s.Path, clients)
+}
+
+// FindSubdirExport returns the index of the subdirectory export with the
+// given path, or -1 if it is not exported
+func (v *Volinfo) FindSubdirExport(p string) int {
+	p = CleanSubdirPath(p)
+	for i, s := range v.SubdirExports {
+		if s.Path == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddSubdirExport adds a new subdirectory export to the volume
+func (v *Volinfo) AddSubdirExport(s SubdirExport) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if v.FindSubdirExport(s.Path) != -1 {
+		return gderrors.ErrSubdirExportExists
+	}
+	v.SubdirExports = append(v.SubdirExports, s)
+	return nil
+}
+
+// RemoveSubdirExport removes the subdirectory export with the given path
+// from the volume
+func (v *Volinfo) RemoveSubdirExport(p string) error {
+	i := v.FindSubdirExport(p)
+	if i == -1 {
+		return gderrors.ErrSubdirExportNotFound
+	}
+	v.SubdirExports = append(v.SubdirExports[:i], v.SubdirExports[i+1:]...)
+	return nil
+}
+
+// subdirAuthEntries returns the subdirectory exports as a list of entries of
+// the allow option of the bricks
+func (v *Volinfo) subdirAuthEntries() []string {
+	var entries []string
+	for i := range v.SubdirExports {
+		entries = append(entries, v.SubdirExports[i].authEntry())
+	}
+	return entries
+}
@@ -0,0 +1,220 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// trashPrefix holds the deleted volumes kept in the trash, keyed by the IDs of
+// the volumes, as a volume name can be reused while a volume with the name is
+// in the trash
+const trashPrefix = "volume-trash/"
+
+// TrashedVolume is a deleted volume kept in the trash until it is purged
+type TrashedVolume struct {
+	Volinfo   *Volinfo
+	DeletedAt time.Time
+	PurgeAt   time.Time
+	// Wipe is true if the bricks are to be wiped when the volume is purged
+	Wipe bool
+
+	modRevision int64
+}
+
+func trashKey(id uuid.UUID) string {
+	return trashPrefix + id.String()
+}
+
+// deleteVolumeSettings deletes the settings of the volume stored under its
+// name
+func deleteVolumeSettings(name string) error {
+	if err := DeleteOptionsHistory(name); err != nil {
+		return err
+	}
+	for _, prefix := range settingsPrefixes {
+		if _, err := store.Delete(context.TODO(), prefix+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TrashVolume deletes the volume, keeping it in the trash to be purged after
+// the retention period. The volume name is freed right away, and the
+// settings of the volume stored under its name are deleted, as with
+// DeleteVolume. ErrVolinfoConflict is returned if the volinfo was modified
+// after the revision in v.
+func TrashVolume(v *Volinfo, retention time.Duration, wipe bool) (*TrashedVolume, error) {
+	now := time.Now()
+	t := &TrashedVolume{
+		Volinfo:   v,
+		DeletedAt: now,
+		PurgeAt:   now.Add(retention),
+		Wipe:      wipe,
+	}
+	value, err := marshalTrashedVolume(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// The bricks are dropped from the brick index, so they can be used for
+	// other volumes once the volume is purged
+	key, index := volinfoKey(v.ID), volumeIndexKey(v.Name)
+	_, ops := brickIndexChanges(v.ID, v.GetBricks(), nil)
+	resp, err := store.Txn(context.TODO()).
+		If(
+			clientv3.Compare(clientv3.ModRevision(key), "=", v.ModRevision),
+			clientv3.Compare(clientv3.Value(index), "=", v.ID.String()),
+		).
+		Then(append([]clientv3.Op{
+			clientv3.OpDelete(key),
+			clientv3.OpDelete(index),
+			clientv3.OpPut(trashKey(v.ID), string(value)),
+		}, ops...)...).
+		Commit()
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Succeeded {
+		return nil, gderrors.ErrVolinfoConflict
+	}
+	volCache.delete(v.ID.String())
+	t.modRevision = resp.Header.Revision
+
+	return t, deleteVolumeSettings(v.Name)
+}
+
+func marshalTrashedVolume(t *TrashedVolume) ([]byte, error) {
+	stored := *t
+	v := *t.Volinfo
+	v.ModRevision = 0
+	stored.Volinfo = &v
+	return json.Marshal(&stored)
+}
+
+// GetTrashedVolumes returns the volumes in the trash, in the order they were
+// deleted
+func GetTrashedVolumes() ([]*TrashedVolume, error) {
+	resp, err := store.Get(context.TODO(), trashPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	trashed := make([]*TrashedVolume, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var t TrashedVolume
+		if err := json.Unmarshal(kv.Value, &t); err != nil || t.Volinfo == nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal deleted volume")
+			continue
+		}
+		t.modRevision = kv.ModRevision
+		trashed = append(trashed, &t)
+	}
+
+	sort.Slice(trashed, func(i, j int) bool { return trashed[i].DeletedAt.Before(trashed[j].DeletedAt) })
+	return trashed, nil
+}
+
+// GetTrashedVolume returns the volume with the name in the trash. If the
+// trash has several volumes with the name, the last one deleted is
+// returned.
+func GetTrashedVolume(name string) (*TrashedVolume, error) {
+	trashed, err := GetTrashedVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(trashed) - 1; i >= 0; i-- {
+		if trashed[i].Volinfo.Name == name {
+			return trashed[i], nil
+		}
+	}
+	return nil, gderrors.ErrDeletedVolNotFound
+}
+
+// RestoreVolume restores the volume from the trash under its name.
+// ErrVolExists is returned if the name has been taken by another volume, and
+// an error is returned too if any of its bricks has been used for another
+// volume.
+func RestoreVolume(t *TrashedVolume) (*Volinfo, error) {
+	v := t.Volinfo
+	value, err := marshalVolinfo(v)
+	if err != nil {
+		return nil, err
+	}
+
+	key, index := volinfoKey(v.ID), volumeIndexKey(v.Name)
+	cmps, ops := brickIndexChanges(v.ID, nil, v.GetBricks())
+	resp, err := store.Txn(context.TODO()).
+		If(append([]clientv3.Cmp{
+			clientv3.Compare(clientv3.ModRevision(trashKey(v.ID)), "=", t.modRevision),
+			clientv3.Compare(clientv3.CreateRevision(index), "=", 0),
+		}, cmps...)...).
+		Then(append([]clientv3.Op{
+			clientv3.OpPut(key, string(value)),
+			clientv3.OpPut(index, v.ID.String()),
+			clientv3.OpDelete(trashKey(v.ID)),
+		}, ops...)...).
+		Commit()
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Succeeded {
+		if Exists(v.Name) {
+			return nil, gderrors.ErrVolExists
+		}
+		if err := CheckBricksNotInUse(v); err != nil {
+			return nil, err
+		}
+		return nil, gderrors.ErrDeletedVolNotFound
+	}
+
+	volCache.put(v.ID.String(), value, resp.Header.Revision)
+	v.ModRevision = resp.Header.Revision
+	return v, nil
+}
+
+// PurgeTrashedVolume removes the volume from the trash. The bricks of the
+// volume are expected to have been cleaned up.
+func PurgeTrashedVolume(t *TrashedVolume) error {
+	key := trashKey(t.Volinfo.ID)
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", t.modRevision)).
+		Then(clientv3.OpDelete(key)).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return gderrors.ErrDeletedVolNotFound
+	}
+	// The revisions are kept while the volume is in the trash, as it can
+	// be restored
+	return deleteRevisionLog(t.Volinfo.ID)
+}
+
+// CheckBricksNotInUse makes sure none of the bricks of the volume have been
+// used for a brick of another volume
+func CheckBricksNotInUse(v *Volinfo) error {
+	return CheckBrickPathConflicts(v.GetBricks())
+}
+
+// CreateDeletedVolumeResp returns the deleted volume for responses
+func CreateDeletedVolumeResp(t *TrashedVolume) *api.DeletedVolume {
+	return &api.DeletedVolume{
+		VolumeInfo: *CreateVolumeInfoResp(t.Volinfo),
+		DeletedAt:  t.DeletedAt,
+		PurgeAt:    t.PurgeAt,
+		Wipe:       t.Wipe,
+	}
+}
@@ -0,0 +1,74 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// usageProtectPrefix holds the usage protection policy and state of the
+// volumes, keyed by the volume name
+const usageProtectPrefix = "volume-usageprotect/"
+
+// SetUsageProtectPolicy saves the usage protection policy and state of the
+// volume
+func SetUsageProtectPolicy(volname string, s *api.VolUsageProtectResp) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), usageProtectPrefix+volname, string(b))
+	return err
+}
+
+// GetUsageProtectPolicy returns the usage protection policy and state of the
+// volume
+func GetUsageProtectPolicy(volname string) (*api.VolUsageProtectResp, error) {
+	resp, err := store.Get(context.TODO(), usageProtectPrefix+volname)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, gderrors.ErrUsageProtectPolicyNotFound
+	}
+
+	var s api.VolUsageProtectResp
+	if err := json.Unmarshal(resp.Kvs[0].Value, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// GetUsageProtectPolicies returns the usage protection policies of all
+// volumes, keyed by volume name
+func GetUsageProtectPolicies() (map[string]*api.VolUsageProtectResp, error) {
+	resp, err := store.Get(context.TODO(), usageProtectPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]*api.VolUsageProtectResp, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var s api.VolUsageProtectResp
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			return nil, err
+		}
+		policies[string(kv.Key)[len(usageProtectPrefix):]] = &s
+	}
+
+	return policies, nil
+}
+
+// DeleteUsageProtectPolicy deletes the usage protection policy of the volume
+func DeleteUsageProtectPolicy(volname string) error {
+	_, err := store.Delete(context.TODO(), usageProtectPrefix+volname)
+	return err
+}
@@ -0,0 +1,83 @@
+package volume
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ValidationOp is the volume operation for which a proposed volinfo is
+// validated
+type ValidationOp string
+
+const (
+	// ValidateCreate validates the volinfo of a volume being created
+	ValidateCreate ValidationOp = "create"
+	// ValidateExpand validates the volinfo of a volume after expansion
+	ValidateExpand ValidationOp = "expand"
+	// ValidateOptionSet validates the volinfo with the volume options being set
+	ValidateOptionSet ValidationOp = "option-set"
+	// ValidateRename validates the volinfo of a volume after it is renamed
+	ValidateRename ValidationOp = "rename"
+)
+
+// ValidatorFunc validates the proposed volinfo for a volume operation. A
+// non-nil error vetoes the operation, and is reported as the reason for it.
+type ValidatorFunc func(op ValidationOp, v *Volinfo) error
+
+// VetoError is returned when a validator vetoes a volume operation
+type VetoError struct {
+	Validator string
+	Op        ValidationOp
+	Reason    string
+}
+
+func (e *VetoError) Error() string {
+	return fmt.Sprintf("volume %s rejected by validator %s: %s", e.Op, e.Validator, e.Reason)
+}
+
+var validators = struct {
+	sync.RWMutex
+	m map[string]ValidatorFunc
+}{m: make(map[string]ValidatorFunc)}
+
+// RegisterValidator registers a validator which is called with the proposed
+// volinfo before a volume is created, expanded or renamed, or before volume
+// options are set. Registering a validator with the name of an existing one replaces
+// it.
+func RegisterValidator(name string, fn ValidatorFunc) {
+	validators.Lock()
+	defer validators.Unlock()
+
+	validators.m[name] = fn
+}
+
+// UnregisterValidator removes the validator with the given name
+func UnregisterValidator(name string) {
+	validators.Lock()
+	defer validators.Unlock()
+
+	delete(validators.m, name)
+}
+
+// RunValidators calls the registered validators, in the order of their names,
+// with the proposed volinfo. A *VetoError for the first validator vetoing the
+// operation is returned.
+func RunValidators(op ValidationOp, v *Volinfo) error {
+	validators.RLock()
+	names := make([]string, 0, len(validators.m))
+	fns := make(map[string]ValidatorFunc, len(validators.m))
+	for name, fn := range validators.m {
+		names = append(names, name)
+		fns[name] = fn
+	}
+	validators.RUnlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		if err := fns[name](op, v); err != nil {
+			return &VetoError{Validator: name, Op: op, Reason: err.Error()}
+		}
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/pmap"
+	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/pkg/api"
 	gderrors "github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/lvmutils"
@@ -49,20 +50,14 @@ func GetRedundancy(disperse uint) int {
 // isBrickPathAvailable validates whether the brick is consumed by other
 // volume
 func isBrickPathAvailable(peerID uuid.UUID, brickPath string) error {
-	volumes, e := GetVolumes(context.TODO())
-	if e != nil || volumes == nil {
-		// In case cluster doesn't have any volumes configured yet,
-		// treat this as success
-		log.Debug("Failed to retrieve volumes")
+	resp, e := store.Get(context.TODO(), brickIndexKey(peerID, brickPath))
+	if e != nil {
+		log.WithError(e).Debug("Failed to look up brick in the brick index")
 		return nil
 	}
-	for _, v := range volumes {
-		for _, b := range v.GetBricks() {
-			if uuid.Equal(b.PeerID, peerID) && b.Path == brickPath {
-				log.Error("Brick is already used by ", v.Name)
-				return gderrors.ErrBrickPathAlreadyInUse
-			}
-		}
+	if resp.Count != 0 {
+		log.Error("Brick is already used by volume ", string(resp.Kvs[0].Value))
+		return gderrors.ErrBrickPathAlreadyInUse
 	}
 	return nil
 }
@@ -115,14 +110,29 @@ func BrickStatus(binfo brick.Brickinfo, mtabEntries []*Mntent) (brick.Brickstatu
 		s.Size = *(brick.CreateSizeInfo(&fstat))
 	}
 
+	if m := brickMountEntry(binfo.Path, mtabEntries); m != nil {
+		s.MountOpts = m.MntOpts
+		s.Device = m.FsName
+		s.FS = m.MntType
+	}
+	return s, nil
+}
+
+// brickMountEntry returns the mount entry of the filesystem containing the
+// brick path, which is the entry with the longest mount point that is a
+// parent of the path
+func brickMountEntry(brickPath string, mtabEntries []*Mntent) *Mntent {
+	var entry *Mntent
 	for _, m := range mtabEntries {
-		if strings.HasPrefix(binfo.Path, m.MntDir) {
-			s.MountOpts = m.MntOpts
-			s.Device = m.FsName
-			s.FS = m.MntType
+		if m.MntDir != "/" && brickPath != m.MntDir &&
+			!strings.HasPrefix(brickPath, strings.TrimSuffix(m.MntDir, "/")+"/") {
+			continue
+		}
+		if entry == nil || len(m.MntDir) >= len(entry.MntDir) {
+			entry = m
 		}
 	}
-	return s, nil
+	return entry
 }
 
 //GetBrickMountRoot return root of a brick mount
@@ -262,82 +272,98 @@ func (v *Volinfo) GetProvisionType() brick.ProvisionType {
 //CleanBricks will Unmount the bricks and delete lv, thinpool
 func CleanBricks(volinfo *Volinfo) error {
 	for _, b := range volinfo.GetLocalBricks() {
-		// UnMount the Brick if mounted
-		mountRoot := strings.TrimSuffix(b.Path, b.MountInfo.BrickDirSuffix)
-		_, err := GetBrickMountInfo(mountRoot)
-		if err != nil {
-			if !IsMountNotFoundError(err) {
-				log.WithError(err).WithField("path", mountRoot).
-					Error("unable to get mount info")
-				return err
-			}
-		} else {
-			err := lvmutils.UnmountLV(mountRoot)
-			if err != nil {
-				log.WithError(err).WithField("path", mountRoot).
-					Error("brick unmount failed")
-				return err
-			}
+		if err := cleanBrick(b, nil); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		parts := strings.Split(b.MountInfo.DevicePath, "/")
-		if len(parts) != 4 {
-			return errors.New("unable to parse device path")
+// cleanBrick unmounts the brick and deletes its lv, and the thinpool if no
+// other lv is left in it. If discard is given, it is called with the device
+// of the lv once the brick is unmounted, before the lv is deleted.
+func cleanBrick(b brick.Brickinfo, discard func(device string) error) error {
+	// UnMount the Brick if mounted
+	mountRoot := strings.TrimSuffix(b.Path, b.MountInfo.BrickDirSuffix)
+	_, err := GetBrickMountInfo(mountRoot)
+	if err != nil {
+		if !IsMountNotFoundError(err) {
+			log.WithError(err).WithField("path", mountRoot).
+				Error("unable to get mount info")
+			return err
 		}
-		vgname := parts[2]
-		lvname := parts[3]
-		tpname, err := lvmutils.GetThinpoolName(vgname, lvname)
+	} else {
+		err := lvmutils.UnmountLV(mountRoot)
 		if err != nil {
-			log.WithError(err).WithFields(log.Fields{
-				"vg-name": vgname,
-				"lv-name": lvname,
-			}).Error("failed to get thinpool name")
+			log.WithError(err).WithField("path", mountRoot).
+				Error("brick unmount failed")
 			return err
 		}
+	}
 
-		// Remove LV
-		err = lvmutils.RemoveLV(vgname, lvname)
-		if err != nil {
-			log.WithError(err).WithFields(log.Fields{
-				"vg-name": vgname,
-				"lv-name": lvname,
-			}).Error("lv remove failed")
+	if discard != nil {
+		if err := discard(b.MountInfo.DevicePath); err != nil {
 			return err
 		}
+	}
 
-		if !deviceutils.IsVgExist(vgname) {
-			continue
-		}
+	parts := strings.Split(b.MountInfo.DevicePath, "/")
+	if len(parts) != 4 {
+		return errors.New("unable to parse device path")
+	}
+	vgname := parts[2]
+	lvname := parts[3]
+	tpname, err := lvmutils.GetThinpoolName(vgname, lvname)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"vg-name": vgname,
+			"lv-name": lvname,
+		}).Error("failed to get thinpool name")
+		return err
+	}
+
+	// Remove LV
+	err = lvmutils.RemoveLV(vgname, lvname)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"vg-name": vgname,
+			"lv-name": lvname,
+		}).Error("lv remove failed")
+		return err
+	}
+
+	if !deviceutils.IsVgExist(vgname) {
+		return nil
+	}
+
+	// Remove Thin Pool if LV count is zero, Thinpool will
+	// have more LVs in case of snapshots and clones
+	numLvs, err := lvmutils.NumberOfLvs(vgname, tpname)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"vg-name": vgname,
+			"tp-name": tpname,
+		}).Error("failed to get number of lvs")
+		return err
+	}
 
-		// Remove Thin Pool if LV count is zero, Thinpool will
-		// have more LVs in case of snapshots and clones
-		numLvs, err := lvmutils.NumberOfLvs(vgname, tpname)
+	if numLvs == 0 {
+		err = lvmutils.RemoveLV(vgname, tpname)
 		if err != nil {
 			log.WithError(err).WithFields(log.Fields{
 				"vg-name": vgname,
 				"tp-name": tpname,
-			}).Error("failed to get number of lvs")
+			}).Error("thinpool remove failed")
 			return err
 		}
+	}
 
-		if numLvs == 0 {
-			err = lvmutils.RemoveLV(vgname, tpname)
-			if err != nil {
-				log.WithError(err).WithFields(log.Fields{
-					"vg-name": vgname,
-					"tp-name": tpname,
-				}).Error("thinpool remove failed")
-				return err
-			}
-		}
-
-		// Update current Vg free size
-		err = deviceutils.UpdateDeviceFreeSizeByVg(gdctx.MyUUID.String(), vgname)
-		if err != nil {
-			log.WithError(err).WithField("vg-name", vgname).
-				Error("failed to update available size of a device")
-			return err
-		}
+	// Update current Vg free size
+	err = deviceutils.UpdateDeviceFreeSizeByVg(gdctx.MyUUID.String(), vgname)
+	if err != nil {
+		log.WithError(err).WithField("vg-name", vgname).
+			Error("failed to update available size of a device")
+		return err
 	}
 	return nil
 }
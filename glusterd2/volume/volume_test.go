@@ -1,8 +1,11 @@
 package volume
 
 import (
+	"encoding/json"
+	stderrors "errors"
 	"testing"
 
+	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/errors"
@@ -66,3 +69,209 @@ func TestNewBrickEntry(t *testing.T) {
 	assert.Equal(t, errors.ErrBrickPathConvertFail, err)
 
 }
+
+// TestOptionsDiff tests that added, changed and removed options are reported
+func TestOptionsDiff(t *testing.T) {
+	oldOpts := map[string]string{"a": "1", "b": "2", "c": "3"}
+	newOpts := map[string]string{"a": "1", "b": "4", "d": "5"}
+
+	changes := OptionsDiff(oldOpts, newOpts)
+	assert.Len(t, changes, 3)
+	assert.Equal(t, api.VolOptionChange{Old: "2", New: "4"}, changes["b"])
+	assert.Equal(t, api.VolOptionChange{Old: "3"}, changes["c"])
+	assert.Equal(t, api.VolOptionChange{New: "5"}, changes["d"])
+
+	assert.Empty(t, OptionsDiff(oldOpts, oldOpts))
+}
+
+func TestVolACLValidate(t *testing.T) {
+	acl := VolACL{
+		Allow:    []string{"*", "192.168.1.10", "10.0.0.0/8", "172.16.*.*", "client1.example.com", "fe80::1"},
+		Reject:   []string{"192.168.1.11"},
+		SSLAllow: []string{"client1"},
+	}
+	assert.Nil(t, acl.Validate())
+
+	m := acl.aclStringMap()
+	assert.Equal(t, "*,192.168.1.10,10.0.0.0/8,172.16.*.*,client1.example.com,fe80::1", m["volume.acl.allow"])
+	assert.Equal(t, "192.168.1.11", m["volume.acl.reject"])
+	assert.Equal(t, "client1", m["volume.acl.ssl-allow"])
+
+	acl = VolACL{}
+	assert.True(t, acl.IsEmpty())
+	assert.Equal(t, "*", acl.aclStringMap()["volume.acl.allow"])
+	assert.Equal(t, "", acl.aclStringMap()["volume.acl.reject"])
+
+	assert.NotNil(t, (&VolACL{Allow: []string{"bad host"}}).Validate())
+	assert.NotNil(t, (&VolACL{Reject: []string{"10.0.0.0/40"}}).Validate())
+	assert.NotNil(t, (&VolACL{SSLAllow: []string{"a,b"}}).Validate())
+	assert.NotNil(t, (&VolACL{SSLAllow: []string{" "}}).Validate())
+}
+
+func TestSubdirExports(t *testing.T) {
+	v := &Volinfo{ACL: VolACL{Allow: []string{"10.0.0.0/8"}}}
+
+	assert.Nil(t, v.AddSubdirExport(SubdirExport{Path: "/tenant1", Clients: []string{"10.0.0.1", "10.0.0.2"}}))
+	assert.Nil(t, v.AddSubdirExport(SubdirExport{Path: "/tenant2"}))
+	assert.Equal(t, errors.ErrSubdirExportExists, v.AddSubdirExport(SubdirExport{Path: "/tenant2"}))
+	assert.NotNil(t, v.AddSubdirExport(SubdirExport{Path: "/"}))
+	assert.NotNil(t, v.AddSubdirExport(SubdirExport{Path: "/a(b)"}))
+	assert.NotNil(t, v.AddSubdirExport(SubdirExport{Path: "/a/../b"}))
+
+	assert.Equal(t, "10.0.0.0/8,/tenant1(10.0.0.1|10.0.0.2),/tenant2(*)", v.StringMap()["volume.acl.allow"])
+
+	assert.Equal(t, 1, v.FindSubdirExport("tenant2/"))
+	assert.Nil(t, v.RemoveSubdirExport("tenant1"))
+	assert.Equal(t, errors.ErrSubdirExportNotFound, v.RemoveSubdirExport("/tenant1"))
+	assert.Equal(t, "10.0.0.0/8,/tenant2(*)", v.StringMap()["volume.acl.allow"])
+}
+
+func TestRunValidators(t *testing.T) {
+	defer UnregisterValidator("a")
+	defer UnregisterValidator("b")
+
+	var called []string
+	RegisterValidator("b", func(op ValidationOp, v *Volinfo) error {
+		called = append(called, "b")
+		if op == ValidateExpand {
+			return stderrors.New("too many bricks")
+		}
+		return nil
+	})
+	RegisterValidator("a", func(op ValidationOp, v *Volinfo) error {
+		called = append(called, "a")
+		if v.Name == "forbidden" {
+			return stderrors.New("forbidden name")
+		}
+		return nil
+	})
+
+	v := &Volinfo{Name: "testvol"}
+	assert.NoError(t, RunValidators(ValidateCreate, v))
+	assert.Equal(t, []string{"a", "b"}, called)
+
+	called = nil
+	err := RunValidators(ValidateExpand, v)
+	assert.Error(t, err)
+	veto, ok := err.(*VetoError)
+	assert.True(t, ok)
+	assert.Equal(t, "b", veto.Validator)
+	assert.Equal(t, "too many bricks", veto.Reason)
+
+	called = nil
+	v.Name = "forbidden"
+	err = RunValidators(ValidateOptionSet, v)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"a"}, called)
+
+	UnregisterValidator("a")
+	UnregisterValidator("b")
+	assert.NoError(t, RunValidators(ValidateExpand, v))
+}
+
+func TestBrickMountEntry(t *testing.T) {
+	mtab := []*Mntent{
+		{FsName: "/dev/root", MntDir: "/"},
+		{FsName: "/dev/vg/brick1", MntDir: "/bricks/brick1"},
+		{FsName: "/dev/vg/bricks", MntDir: "/bricks"},
+	}
+
+	m := brickMountEntry("/bricks/brick1/data", mtab)
+	assert.NotNil(t, m)
+	assert.Equal(t, "/dev/vg/brick1", m.FsName)
+
+	m = brickMountEntry("/bricks/brick10/data", mtab)
+	assert.NotNil(t, m)
+	assert.Equal(t, "/dev/vg/bricks", m.FsName)
+
+	m = brickMountEntry("/export/data", mtab)
+	assert.NotNil(t, m)
+	assert.Equal(t, "/dev/root", m.FsName)
+
+	assert.Nil(t, brickMountEntry("/export/data", mtab[1:]))
+}
+
+func TestCalculateCapacity(t *testing.T) {
+	ids := make([]uuid.UUID, 7)
+	for i := range ids {
+		ids[i] = uuid.NewRandom()
+	}
+	capacity := map[string]uint64{
+		ids[0].String(): 100,
+		ids[1].String(): 120,
+		ids[2].String(): 10,
+		ids[3].String(): 50,
+		ids[4].String(): 60,
+		ids[5].String(): 70,
+		ids[6].String(): 30,
+	}
+
+	v := &Volinfo{
+		Subvols: []Subvol{
+			{
+				// arbiter doesn't limit the replica set
+				Type: SubvolReplicate,
+				Bricks: []brick.Brickinfo{
+					{ID: ids[0]}, {ID: ids[1]}, {ID: ids[2], Type: brick.Arbiter},
+				},
+			},
+			{
+				Type:            SubvolDisperse,
+				DisperseCount:   3,
+				RedundancyCount: 1,
+				Bricks: []brick.Brickinfo{
+					{ID: ids[3]}, {ID: ids[4]}, {ID: ids[5]},
+				},
+			},
+			{
+				Type:   SubvolDistribute,
+				Bricks: []brick.Brickinfo{{ID: ids[6]}},
+			},
+		},
+	}
+	assert.Equal(t, uint64(100+50*2+30), CalculateCapacity(v, capacity))
+
+	// Unknown bricks have no capacity
+	delete(capacity, ids[6].String())
+	assert.Equal(t, uint64(100+50*2), CalculateCapacity(v, capacity))
+}
+
+func TestMarshalVolinfo(t *testing.T) {
+	v := &Volinfo{Name: "vol1", Capacity: 100, ModRevision: 42}
+
+	b, err := marshalVolinfo(v)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "ModRevision")
+	assert.Equal(t, int64(42), v.ModRevision)
+
+	var stored Volinfo
+	assert.Nil(t, json.Unmarshal(b, &stored))
+	assert.Equal(t, uint64(100), stored.Capacity)
+	assert.Equal(t, int64(0), stored.ModRevision)
+}
+
+func TestRenamedVolinfo(t *testing.T) {
+	v := &Volinfo{
+		Name:      "vol1",
+		VolfileID: "vol1",
+		Subvols: []Subvol{
+			{
+				Name: "vol1-replicate-0",
+				Bricks: []brick.Brickinfo{
+					{VolumeName: "vol1", VolfileID: "vol1"},
+				},
+			},
+		},
+	}
+
+	r := RenamedVolinfo(v, "vol2")
+	assert.Equal(t, "vol2", r.Name)
+	assert.Equal(t, "vol2", r.VolfileID)
+	assert.Equal(t, "vol2-replicate-0", r.Subvols[0].Name)
+	assert.Equal(t, "vol2", r.Subvols[0].Bricks[0].VolumeName)
+	assert.Equal(t, "vol2", r.Subvols[0].Bricks[0].VolfileID)
+
+	// The original volinfo is not modified
+	assert.Equal(t, "vol1-replicate-0", v.Subvols[0].Name)
+	assert.Equal(t, "vol1", v.Subvols[0].Bricks[0].VolumeName)
+}
@@ -0,0 +1,96 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+)
+
+// Change is a change to a volume in the store. Volinfo is nil if the volume
+// was deleted.
+type Change struct {
+	Name     string
+	Revision int64
+	Volinfo  *Volinfo
+}
+
+// CurrentRevision returns the current revision of the store. Volumes listed
+// after getting the revision reflect all changes up to the revision, so the
+// revision can be used to watch for further changes with WatchVolumes.
+func CurrentRevision(ctx context.Context) (int64, error) {
+	resp, err := store.Get(ctx, volinfoPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+	return resp.Header.Revision, nil
+}
+
+// WatchVolumes returns the changes made to volumes after the given store
+// revision, waiting for a change till ctx is done. The returned revision is
+// the revision of the last change, or fromRev if there were no changes.
+// ErrRevisionCompacted is returned if the changes after fromRev are no
+// longer available, in which case the volumes must be listed again. A renamed
+// volume is seen as the deletion of the volume with its old name, followed by
+// the volume with its new name.
+func WatchVolumes(ctx context.Context, fromRev int64) ([]Change, int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wch := store.Store.Watch(ctx, volinfoPrefix, clientv3.WithPrefix(), clientv3.WithRev(fromRev+1), clientv3.WithPrevKV())
+
+	var wresp clientv3.WatchResponse
+	select {
+	case <-ctx.Done():
+		return nil, fromRev, nil
+	case wresp = <-wch:
+	}
+
+	if wresp.CompactRevision != 0 {
+		return nil, fromRev, gderrors.ErrRevisionCompacted
+	}
+	if err := wresp.Err(); err != nil {
+		return nil, fromRev, err
+	}
+
+	rev := fromRev
+	changes := make([]Change, 0, len(wresp.Events))
+	for _, ev := range wresp.Events {
+		// Volinfos are keyed by ID, so the names are taken from the
+		// volinfos
+		var prevName string
+		if ev.PrevKv != nil {
+			var prev Volinfo
+			if err := json.Unmarshal(ev.PrevKv.Value, &prev); err != nil {
+				return nil, fromRev, err
+			}
+			prevName = prev.Name
+		}
+
+		c := Change{
+			Name:     prevName,
+			Revision: ev.Kv.ModRevision,
+		}
+		if ev.Type == mvccpb.PUT {
+			var v Volinfo
+			if err := json.Unmarshal(ev.Kv.Value, &v); err != nil {
+				return nil, fromRev, err
+			}
+			if prevName != "" && prevName != v.Name {
+				changes = append(changes, Change{Name: prevName, Revision: ev.Kv.ModRevision})
+			}
+			c.Name = v.Name
+			c.Volinfo = &v
+		}
+		if c.Name != "" {
+			changes = append(changes, c)
+		}
+		rev = ev.Kv.ModRevision
+	}
+
+	return changes, rev, nil
+}
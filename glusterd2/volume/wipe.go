@@ -0,0 +1,465 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// brickWipePrefix has the brick wipe jobs of every peer under
+	// brickWipePrefix/<peer ID>/<job ID>
+	brickWipePrefix = "brick-wipe/"
+
+	// brickWipeSaveInterval is how often the progress of a running job is
+	// saved to the store
+	brickWipeSaveInterval = 10 * time.Second
+	// blkdiscardChunkSize is the size of the range discarded at a time,
+	// so that the progress of the discard can be tracked
+	blkdiscardChunkSize = 1 << 30
+	// readDirBatchSize is the number of entries removed at a time from a
+	// directory being wiped
+	readDirBatchSize = 1024
+)
+
+// brickWipe is a brick wipe job as saved in the store, along with the brick
+// to be wiped
+type brickWipe struct {
+	api.BrickWipeJob
+	Brick brick.Brickinfo
+}
+
+var (
+	brickWipeKick = make(chan struct{}, 1)
+
+	brickWipeWorkerMu   sync.Mutex
+	stopBrickWipeWorker context.CancelFunc
+)
+
+func brickWipeKey(peerID, id string) string {
+	return brickWipePrefix + peerID + "/" + id
+}
+
+func saveBrickWipe(w *brickWipe) error {
+	b, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), brickWipeKey(w.PeerID, w.ID), string(b))
+	return err
+}
+
+// getBrickWipes returns the brick wipe jobs under the prefix, in the order
+// they were created
+func getBrickWipes(prefix string) ([]*brickWipe, error) {
+	resp, err := store.Get(context.TODO(), prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	wipes := make([]*brickWipe, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var w brickWipe
+		if err := json.Unmarshal(kv.Value, &w); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal brick wipe job")
+			continue
+		}
+		wipes = append(wipes, &w)
+	}
+
+	sort.Slice(wipes, func(i, j int) bool {
+		return wipes[i].Created.Before(wipes[j].Created)
+	})
+	return wipes, nil
+}
+
+// ScheduleBrickWipes schedules jobs wiping the data of the bricks of a deleted
+// volume, which are run in the background by the peers hosting the bricks.
+// The LVs of bricks provisioned by GD2 are discarded and removed, and the
+// directories of other bricks are removed.
+func ScheduleBrickWipes(volinfo *Volinfo) ([]api.BrickWipeJob, error) {
+	method := api.BrickWipeRm
+	if volinfo.IsAutoProvisioned() || volinfo.IsSnapshotProvisioned() {
+		method = api.BrickWipeBlkdiscard
+	}
+
+	var jobs []api.BrickWipeJob
+	for _, b := range volinfo.GetBricks() {
+		w := &brickWipe{
+			BrickWipeJob: api.BrickWipeJob{
+				ID:         uuid.NewRandom().String(),
+				VolumeName: volinfo.Name,
+				VolumeID:   volinfo.ID.String(),
+				PeerID:     b.PeerID.String(),
+				Path:       b.Path,
+				Method:     method,
+				State:      api.BrickWipePending,
+				Created:    time.Now(),
+			},
+			Brick: b,
+		}
+		if err := saveBrickWipe(w); err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, w.BrickWipeJob)
+	}
+
+	return jobs, nil
+}
+
+// GetBrickWipeJobs returns the brick wipe jobs of all peers
+func GetBrickWipeJobs() ([]api.BrickWipeJob, error) {
+	wipes, err := getBrickWipes(brickWipePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]api.BrickWipeJob, 0, len(wipes))
+	for _, w := range wipes {
+		jobs = append(jobs, w.BrickWipeJob)
+	}
+	return jobs, nil
+}
+
+// GetBrickWipeJob returns the brick wipe job with the ID
+func GetBrickWipeJob(id string) (*api.BrickWipeJob, error) {
+	jobs, err := GetBrickWipeJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range jobs {
+		if jobs[i].ID == id {
+			return &jobs[i], nil
+		}
+	}
+	return nil, gderrors.ErrBrickWipeJobNotFound
+}
+
+// DeleteBrickWipeJob deletes the brick wipe job from the store
+func DeleteBrickWipeJob(job *api.BrickWipeJob) error {
+	_, err := store.Delete(context.TODO(), brickWipeKey(job.PeerID, job.ID))
+	return err
+}
+
+// StartBrickWipeWorker starts running the brick wipe jobs of this peer in the
+// background, one at a time. Jobs interrupted by a restart are resumed.
+// Calling it while the worker is running does nothing.
+func StartBrickWipeWorker() {
+	brickWipeWorkerMu.Lock()
+	defer brickWipeWorkerMu.Unlock()
+
+	if stopBrickWipeWorker != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopBrickWipeWorker = cancel
+
+	go watchBrickWipes(ctx)
+	go runBrickWipes(ctx)
+}
+
+// StopBrickWipeWorker stops the brick wipe worker. A running job is left to
+// be resumed when the worker is started again.
+func StopBrickWipeWorker() {
+	brickWipeWorkerMu.Lock()
+	defer brickWipeWorkerMu.Unlock()
+
+	if stopBrickWipeWorker != nil {
+		stopBrickWipeWorker()
+		stopBrickWipeWorker = nil
+	}
+}
+
+func kickBrickWipes() {
+	select {
+	case brickWipeKick <- struct{}{}:
+	default:
+	}
+}
+
+// watchBrickWipes kicks the worker when jobs are scheduled for this peer
+func watchBrickWipes(ctx context.Context) {
+	prefix := brickWipePrefix + gdctx.MyUUID.String() + "/"
+	for {
+		// Jobs scheduled while not watching are picked up by this kick
+		kickBrickWipes()
+
+		for wresp := range store.Store.Watch(ctx, prefix, clientv3.WithPrefix()) {
+			for _, ev := range wresp.Events {
+				if ev.Type == mvccpb.PUT && ev.IsCreate() {
+					kickBrickWipes()
+					break
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(brickWipeSaveInterval):
+		}
+	}
+}
+
+func runBrickWipes(ctx context.Context) {
+	prefix := brickWipePrefix + gdctx.MyUUID.String() + "/"
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-brickWipeKick:
+		}
+
+		wipes, err := getBrickWipes(prefix)
+		if err != nil {
+			log.WithError(err).Error("failed to get brick wipe jobs")
+			continue
+		}
+
+		for _, w := range wipes {
+			if w.State != api.BrickWipePending && w.State != api.BrickWipeRunning {
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			runBrickWipe(ctx, w)
+		}
+	}
+}
+
+// wipeProgress tracks the progress of a running brick wipe job, saving it to
+// the store from time to time
+type wipeProgress struct {
+	w     *brickWipe
+	saved time.Time
+	// inPlace is true if entries are removed under the brick path itself,
+	// which is checked not to be in use before each batch is removed
+	inPlace bool
+}
+
+func (p *wipeProgress) add(n uint64) {
+	p.w.Done += n
+	if time.Since(p.saved) < brickWipeSaveInterval {
+		return
+	}
+	if err := saveBrickWipe(p.w); err != nil {
+		log.WithError(err).WithField("job", p.w.ID).Warn("failed to save progress of brick wipe job")
+	}
+	p.saved = time.Now()
+}
+
+func runBrickWipe(ctx context.Context, w *brickWipe) {
+	logger := log.WithFields(log.Fields{
+		"job":    w.ID,
+		"volume": w.VolumeName,
+		"brick":  w.Path,
+	})
+
+	w.State = api.BrickWipeRunning
+	if err := saveBrickWipe(w); err != nil {
+		logger.WithError(err).Error("failed to save brick wipe job")
+		return
+	}
+	logger.WithField("method", w.Method).Info("wiping brick of deleted volume")
+
+	err := checkBrickNotInUse(w)
+	if err == nil {
+		p := &wipeProgress{w: w, saved: time.Now()}
+		switch w.Method {
+		case api.BrickWipeRm:
+			err = wipeBrickDir(ctx, p)
+		case api.BrickWipeBlkdiscard:
+			err = cleanBrick(w.Brick, func(device string) error {
+				return discardDevice(ctx, p, device)
+			})
+		default:
+			err = fmt.Errorf("unknown brick wipe method %q", w.Method)
+		}
+	}
+
+	if ctx.Err() != nil {
+		// Resumed when the worker is started again
+		return
+	}
+
+	w.Finished = time.Now()
+	if err != nil {
+		w.State = api.BrickWipeFailed
+		w.Error = err.Error()
+		logger.WithError(err).Error("brick wipe failed")
+	} else {
+		w.State = api.BrickWipeCompleted
+		w.Done = w.Total
+		logger.Info("brick wiped")
+	}
+	if err := saveBrickWipe(w); err != nil {
+		logger.WithError(err).Error("failed to save brick wipe job")
+	}
+}
+
+// checkBrickNotInUse makes sure the brick path has not been used for a brick
+// of another volume since the job was scheduled, looking it up in the brick
+// index as volume create and expand do
+func checkBrickNotInUse(w *brickWipe) error {
+	b := w.Brick
+	b.Path = w.Path
+	return CheckBrickPathConflicts([]brick.Brickinfo{b})
+}
+
+// wipeBrickDir removes the brick directory. The directory is renamed first so
+// that the brick path can be reused right away, unless it is a mount point.
+// A mount point is wiped in place, stopping as soon as the brick path is used
+// for a brick of a volume.
+func wipeBrickDir(ctx context.Context, p *wipeProgress) error {
+	dir := p.w.Path
+	trash := fmt.Sprintf("%s.wipe-%s", p.w.Path, p.w.ID)
+	if _, err := os.Lstat(trash); err == nil {
+		// Renamed before the job was interrupted
+		dir = trash
+	} else if err := os.Rename(p.w.Path, trash); err == nil {
+		dir = trash
+	} else if os.IsNotExist(err) {
+		return nil
+	} else {
+		p.inPlace = true
+	}
+
+	if p.w.Total == 0 {
+		total, err := countEntries(dir)
+		if err != nil {
+			return err
+		}
+		p.w.Total = total
+	}
+
+	if err := removeDirEntries(ctx, dir, p); err != nil {
+		return err
+	}
+
+	// A brick path which is a mount point can't be removed, and is left
+	// empty
+	if err := os.Remove(dir); err != nil && dir == trash {
+		return err
+	}
+	return nil
+}
+
+// countEntries returns the number of entries under the directory
+func countEntries(dir string) (uint64, error) {
+	var n uint64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir {
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// removeDirEntries removes everything under the directory, in batches so that
+// large directories need not be read at once
+func removeDirEntries(ctx context.Context, dir string, p *wipeProgress) error {
+	for {
+		f, err := os.Open(dir)
+		if err != nil {
+			return err
+		}
+		names, err := f.Readdirnames(readDirBatchSize)
+		f.Close()
+		if len(names) == 0 {
+			// Readdirnames returns io.EOF for an empty directory
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if p.inPlace {
+			if err := checkBrickNotInUse(p.w); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range names {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			path := filepath.Join(dir, name)
+			info, err := os.Lstat(path)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return err
+			}
+			if info.IsDir() {
+				if err := removeDirEntries(ctx, path, p); err != nil {
+					return err
+				}
+			}
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			p.add(1)
+		}
+	}
+}
+
+// discardDevice discards the whole device, a chunk at a time. A discard
+// interrupted by a restart is resumed from the last chunk saved as done.
+func discardDevice(ctx context.Context, p *wipeProgress, device string) error {
+	out, err := utils.ExecuteCommandOutput("blockdev", "--getsize64", device)
+	if err != nil {
+		return err
+	}
+	size, err := strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return errors.New("unable to get size of device " + device)
+	}
+	p.w.Total = size
+
+	for offset := p.w.Done; offset < size; offset += blkdiscardChunkSize {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		length := size - offset
+		if length > blkdiscardChunkSize {
+			length = blkdiscardChunkSize
+		}
+		err := utils.ExecuteCommandRun("blkdiscard",
+			"-o", strconv.FormatUint(offset, 10),
+			"-l", strconv.FormatUint(length, 10), device)
+		if err != nil {
+			return err
+		}
+		p.add(length)
+	}
+	return nil
+}
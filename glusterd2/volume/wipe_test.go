@@ -0,0 +1,70 @@
+package volume
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWipeBrickDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "brickwipe")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	brickPath := path.Join(dir, "brick")
+	assert.Nil(t, os.MkdirAll(path.Join(brickPath, ".glusterfs", "ab", "cd"), 0755))
+	assert.Nil(t, ioutil.WriteFile(path.Join(brickPath, "f1"), []byte("data"), 0644))
+	assert.Nil(t, ioutil.WriteFile(path.Join(brickPath, ".glusterfs", "ab", "cd", "f2"), []byte("data"), 0644))
+
+	w := &brickWipe{BrickWipeJob: api.BrickWipeJob{ID: "job1", Path: brickPath}}
+	p := &wipeProgress{w: w, saved: time.Now()}
+	assert.Nil(t, wipeBrickDir(context.Background(), p))
+
+	// .glusterfs, ab, cd, f1 and f2
+	assert.Equal(t, uint64(5), w.Total)
+	assert.Equal(t, uint64(5), w.Done)
+
+	entries, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	// Nothing left to wipe
+	assert.Nil(t, wipeBrickDir(context.Background(), p))
+}
+
+func TestRemoveDirEntriesInPlace(t *testing.T) {
+	defer startTestStore(t)()
+
+	dir, err := ioutil.TempDir("", "brickwipe")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(path.Join(dir, "f1"), []byte("data"), 0644))
+
+	b := brick.Brickinfo{PeerID: uuid.NewRandom(), Path: dir}
+	w := &brickWipe{BrickWipeJob: api.BrickWipeJob{ID: "job1", Path: dir}, Brick: b}
+	p := &wipeProgress{w: w, saved: time.Now(), inPlace: true}
+
+	// The brick path has been used for a brick of a new volume
+	v := &Volinfo{ID: uuid.NewRandom(), Name: "vol1", Subvols: []Subvol{{Bricks: []brick.Brickinfo{b}}}}
+	require.NoError(t, AddOrUpdateVolume(v))
+
+	assert.Error(t, removeDirEntries(context.Background(), dir, p))
+	_, err = os.Stat(path.Join(dir, "f1"))
+	assert.NoError(t, err)
+
+	require.NoError(t, DeleteVolume("vol1"))
+	assert.NoError(t, removeDirEntries(context.Background(), dir, p))
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -36,6 +36,7 @@ func Load() (err error) {
 	xlMap = xls
 
 	injectTransportOptions()
+	markReconfigurableOptions()
 	loadOptions()
 	return
 }
@@ -0,0 +1,50 @@
+package xlator
+
+import (
+	"sort"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// CreateOptionDoc returns the documentation of the option with the name
+func CreateOptionDoc(name string, opt *options.Option) api.OptionDoc {
+	doc := api.OptionDoc{
+		Name:            name,
+		Type:            opt.Type.String(),
+		Description:     opt.Description,
+		DefaultValue:    opt.DefaultValue,
+		AllowedValues:   opt.Value,
+		Min:             opt.Min,
+		Max:             opt.Max,
+		Tags:            opt.Tags,
+		Level:           opt.Level.String(),
+		Settable:        opt.IsSettable(),
+		Reconfigurable:  opt.IsReconfigurable(),
+		ForceRequired:   opt.IsForceRequired(),
+		RestartRequired: opt.IsSettable() && !opt.IsClientOpt() && !opt.IsReconfigurable(),
+	}
+	if len(opt.OpVersion) != 0 {
+		doc.OpVersion = opt.OpVersion[0]
+	}
+	return doc
+}
+
+// OptionDocs returns the documentation of the options of all the xlators,
+// sorted by name. Only the options which can be set by users are returned if
+// settableOnly is true.
+func OptionDocs(settableOnly bool) api.OptionDocListResp {
+	docs := api.OptionDocListResp{}
+	for _, xl := range Xlators() {
+		for _, opt := range xl.Options {
+			if settableOnly && !opt.IsSettable() {
+				continue
+			}
+			for _, k := range opt.Key {
+				docs = append(docs, CreateOptionDoc(xl.ID+"."+k, opt))
+			}
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Name < docs[j].Name })
+	return docs
+}
@@ -0,0 +1,61 @@
+package xlator
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/options"
+)
+
+// reconfigurableOptions lists the brick side tunables, indexed by xlator ID,
+// which the xlators apply in their reconfigure() without needing the brick
+// process to be restarted
+var reconfigurableOptions = map[string][]string{
+	"io-threads": {
+		"thread-count", "high-prio-threads", "normal-prio-threads",
+		"low-prio-threads", "least-prio-threads", "idle-time",
+		"enable-least-priority",
+	},
+	"io-cache": {
+		"cache-size", "cache-timeout", "min-file-size", "max-file-size",
+		"priority",
+	},
+	"quick-read": {"cache-size", "cache-timeout", "max-file-size"},
+	"read-ahead": {"page-count"},
+	"write-behind": {
+		"cache-size", "flush-behind", "strict-O_DIRECT",
+		"strict-write-ordering", "trickling-writes",
+	},
+	"md-cache":  {"md-cache-timeout", "cache-xattrs", "cache-posix-acl"},
+	"io-stats":  {"latency-measurement", "count-fop-hits", "log-level"},
+	"posix":     {"health-check-interval", "batch-fsync-delay-usec"},
+	"changelog": {"rollover-time", "fsync-interval"},
+}
+
+// markReconfigurableOptions sets the reconfigurable flag on the options
+// listed in reconfigurableOptions
+func markReconfigurableOptions() {
+	for id, keys := range reconfigurableOptions {
+		xl, ok := xlMap[id]
+		if !ok {
+			continue
+		}
+		for _, opt := range xl.Options {
+			for _, k := range opt.Key {
+				if Contains(k, keys) {
+					opt.Flags = opt.Flags | options.OptionFlagReconfigurable
+					break
+				}
+			}
+		}
+	}
+}
+
+// AllReconfigurable returns true if all the given options can be changed on
+// running brick processes without restarting them
+func AllReconfigurable(keys []string) bool {
+	for _, k := range keys {
+		opt, err := FindOption(k)
+		if err != nil || !opt.IsReconfigurable() {
+			return false
+		}
+	}
+	return true
+}
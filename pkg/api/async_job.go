@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// States of the async jobs
+const (
+	AsyncJobRunning   = "running"
+	AsyncJobSucceeded = "succeeded"
+	AsyncJobFailed    = "failed"
+)
+
+// AsyncJob is a long running operation requested to be run in the
+// background. The request is answered with the job, and the client polls the
+// job, or waits for the job.completed or job.failed event, for the result.
+type AsyncJob struct {
+	ID uuid.UUID `json:"id"`
+	// Op is the name of the operation, like snapshot-clone
+	Op string `json:"op"`
+	// Target is the name of the entity operated on
+	Target     string    `json:"target"`
+	State      string    `json:"state"`
+	Originator uuid.UUID `json:"originator"`
+	ReqID      uuid.UUID `json:"request-id"`
+	User       string    `json:"user,omitempty"`
+	StartedAt  time.Time `json:"started-at"`
+	FinishedAt time.Time `json:"finished-at,omitempty"`
+	// Result is the response the operation would have been answered with
+	// if run synchronously
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	// ErrorStatus is the HTTP status the operation would have failed with
+	// if run synchronously
+	ErrorStatus int `json:"error-status,omitempty"`
+}
+
+// AsyncJobListResp is the response sent for a list of the async jobs
+type AsyncJobListResp []AsyncJob
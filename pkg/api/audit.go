@@ -0,0 +1,33 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// AuditRecord is the record of a REST request which may have changed the
+// state of the cluster
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	// Peer is the peer which served the request
+	Peer  uuid.UUID `json:"peer"`
+	ReqID uuid.UUID `json:"request-id"`
+	// User is the authenticated user of the request, empty if REST
+	// authentication is disabled
+	User     string `json:"user,omitempty"`
+	SourceIP string `json:"source-ip"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Route    string `json:"route"`
+	// BodyDigest is the SHA-256 digest of the request body, which isn't
+	// recorded as it may carry secrets
+	BodyDigest string `json:"body-digest,omitempty"`
+	Status     int    `json:"status"`
+	// TxnIDs are the IDs of the transactions run for the request
+	TxnIDs []uuid.UUID `json:"txn-ids,omitempty"`
+}
+
+// AuditListResp is the response sent for a request to list the audit
+// records
+type AuditListResp []AuditRecord
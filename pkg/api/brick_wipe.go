@@ -0,0 +1,43 @@
+package api
+
+import "time"
+
+// Methods used to wipe the data of a brick
+const (
+	// BrickWipeRm removes the brick directory
+	BrickWipeRm = "rm"
+	// BrickWipeBlkdiscard discards the LV provisioned for the brick, and
+	// removes the LV
+	BrickWipeBlkdiscard = "blkdiscard"
+)
+
+// States of a brick wipe job
+const (
+	BrickWipePending   = "pending"
+	BrickWipeRunning   = "running"
+	BrickWipeCompleted = "completed"
+	BrickWipeFailed    = "failed"
+)
+
+// BrickWipeJob is a background job wiping the data of a brick of a deleted
+// volume, run by the peer hosting the brick
+type BrickWipeJob struct {
+	ID         string `json:"id"`
+	VolumeName string `json:"volume-name"`
+	VolumeID   string `json:"volume-id"`
+	PeerID     string `json:"peer-id"`
+	Path       string `json:"path"`
+	Method     string `json:"method"`
+	State      string `json:"state"`
+	// Done and Total are the progress of the job, in entries removed for
+	// rm and in bytes discarded for blkdiscard
+	Done     uint64    `json:"done"`
+	Total    uint64    `json:"total"`
+	Error    string    `json:"error,omitempty"`
+	Created  time.Time `json:"created"`
+	Finished time.Time `json:"finished,omitempty"`
+}
+
+// BrickWipeJobsResp is the response sent for a brick wipe jobs list request,
+// and for a volume delete request which scheduled brick wipe jobs
+type BrickWipeJobsResp []BrickWipeJob
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"time"
+)
+
+// ClusterBackupReq represents a request to back up the cluster configuration
+type ClusterBackupReq struct {
+	// Save saves the backup in the backup dir of the peer serving the
+	// request, instead of sending the backup in the response
+	Save bool `json:"save,omitempty"`
+}
+
+// ClusterBackupKV is a key and its value in the store of glusterd2
+type ClusterBackupKV struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// ClusterBackup is a backup of the cluster configuration held in the store of
+// glusterd2. It is sent in the response to a backup request, unless the
+// backup is saved on the peer.
+type ClusterBackup struct {
+	ClusterID string            `json:"cluster-id"`
+	OpVersion int               `json:"op-version"`
+	CreatedAt time.Time         `json:"created-at"`
+	KVs       []ClusterBackupKV `json:"kvs"`
+}
+
+// ClusterBackupResp is the response sent for a backup request which saved
+// the backup on the peer
+type ClusterBackupResp struct {
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	Keys      int       `json:"keys"`
+	CreatedAt time.Time `json:"created-at"`
+}
+
+// ClusterRestoreReq represents a request to restore the cluster configuration
+// from a backup. Either the name of a backup saved on the peer serving the
+// request, or the backup itself must be given.
+type ClusterRestoreReq struct {
+	Name   string         `json:"name,omitempty"`
+	Backup *ClusterBackup `json:"backup,omitempty"`
+	// Force allows restoring a backup taken from a different cluster
+	Force bool `json:"force,omitempty"`
+}
+
+// ClusterRestoreResp is the response sent for a restore request
+type ClusterRestoreResp struct {
+	Keys int `json:"keys"`
+}
@@ -0,0 +1,19 @@
+package api
+
+import "time"
+
+// ReadOnlyModeReq represents an incoming request to enable or disable the
+// read-only mode of the cluster
+type ReadOnlyModeReq struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// ReadOnlyModeResp contains the read-only mode of the cluster. Requests
+// modifying the cluster are rejected while the read-only mode is enabled.
+type ReadOnlyModeResp struct {
+	Enabled bool      `json:"enabled"`
+	Reason  string    `json:"reason,omitempty"`
+	User    string    `json:"user,omitempty"`
+	Since   time.Time `json:"since,omitempty"`
+}
@@ -21,12 +21,33 @@ const (
 	ErrCodeGeneric ErrorCode = iota + 1
 	// ErrTxnStepFailed represents failure of a txn step
 	ErrTxnStepFailed
+	// ErrCodePeerOffline represents a peer being offline or unreachable
+	ErrCodePeerOffline
+	// ErrCodeLockTimeout represents failure to obtain a cluster wide lock
+	ErrCodeLockTimeout
+	// ErrCodeValidationFailed represents failure of request validation
+	ErrCodeValidationFailed
+	// ErrCodeQuorumLost represents the store having lost quorum
+	ErrCodeQuorumLost
+	// ErrCodePreflightFailed represents failure of a peer preflight check
+	ErrCodePreflightFailed
+	// ErrCodeConflict represents an update failing due to a concurrent update
+	ErrCodeConflict
+	// ErrCodeClusterIDMismatch represents a peer belonging to another cluster
+	ErrCodeClusterIDMismatch
 )
 
 // ErrorCodeMap maps error code to it's textual message
 var ErrorCodeMap = map[ErrorCode]string{
-	ErrCodeGeneric:   "generic error",
-	ErrTxnStepFailed: "a txn step failed",
+	ErrCodeGeneric:           "generic error",
+	ErrTxnStepFailed:         "a txn step failed",
+	ErrCodePeerOffline:       "peer is offline",
+	ErrCodeLockTimeout:       "could not obtain lock",
+	ErrCodeValidationFailed:  "validation failed",
+	ErrCodeQuorumLost:        "store has lost quorum",
+	ErrCodePreflightFailed:   "peer preflight check failed",
+	ErrCodeConflict:          "concurrent update conflict",
+	ErrCodeClusterIDMismatch: "peer belongs to another cluster",
 }
 
 // ErrorResponse is an interface that types can implement on custom errors.
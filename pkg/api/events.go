@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/pborman/uuid"
@@ -22,4 +23,27 @@ type Event struct {
 	Origin uuid.UUID `json:"origin"`
 	// Timestamp is the time when the event was created
 	Timestamp time.Time `json:"timestamp"`
+	// ResourceType is set for the lifecycle events of resources, like
+	// volume.created, and names the type of Resource
+	ResourceType string `json:"resource-type,omitempty"`
+	// Resource is the full state of the resource at the time of a
+	// lifecycle event, in the format of the REST API responses for the
+	// resource type. Consumers like external provisioners can act on it
+	// without querying the API.
+	Resource json.RawMessage `json:"resource,omitempty"`
+	// SchemaVersion is the version of the format of the lifecycle events,
+	// which is bumped only on incompatible changes to it
+	SchemaVersion int `json:"schema-version,omitempty"`
 }
+
+// Resource types of lifecycle events
+const (
+	// EventResourceVolume is a volume, with Resource being a VolumeInfo
+	EventResourceVolume = "volume"
+	// EventResourceSnapshot is a snapshot, with Resource being a SnapInfo
+	EventResourceSnapshot = "snapshot"
+)
+
+// EventSchemaVersion is the current version of the format of the lifecycle
+// events
+const EventSchemaVersion = 1
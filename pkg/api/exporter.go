@@ -0,0 +1,90 @@
+package api
+
+// Exporter types supported by glusterd
+const (
+	// ExporterSyslog sends records to a syslog server as RFC 5424 messages
+	ExporterSyslog = "syslog"
+	// ExporterHTTP posts batches of records to an HTTP bulk endpoint
+	ExporterHTTP = "http"
+)
+
+// Formats of the records sent by exporters
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCEF  = "cef"
+)
+
+// Sources of the records sent by exporters
+const (
+	ExportSourceEvents = "events"
+	ExportSourceAudit  = "audit"
+)
+
+// ExporterSyslogConfig is the configuration of an exporter sending records to
+// a syslog server
+type ExporterSyslogConfig struct {
+	// Network is one of udp, tcp or tls. Defaults to udp.
+	Network string `json:"network,omitempty"`
+	// Address is the host:port of the syslog server
+	Address string `json:"address"`
+	// Facility is the name of the syslog facility, like daemon, auth or
+	// local0. Defaults to daemon.
+	Facility string `json:"facility,omitempty"`
+	// AppName is the APP-NAME of the messages. Defaults to glusterd2.
+	AppName string `json:"app-name,omitempty"`
+}
+
+// ExporterHTTPConfig is the configuration of an exporter posting records to an
+// HTTP bulk endpoint. Records are posted as a JSON array in the JSON format,
+// and as newline separated lines in the CEF format.
+type ExporterHTTPConfig struct {
+	URL string `json:"url"`
+	// Token, if set, is sent as a bearer token
+	Token   string            `json:"token,omitempty" store:"encrypt"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Exporter represents an exporter shipping records of the cluster events and
+// the audit log to an external collector. Every peer exports the events it
+// originates and its own audit log.
+type Exporter struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Format string `json:"format"`
+	// Sources are the sources of the records to export. All sources are
+	// exported if empty.
+	Sources []string              `json:"sources,omitempty"`
+	Syslog  *ExporterSyslogConfig `json:"syslog,omitempty"`
+	HTTP    *ExporterHTTPConfig   `json:"http,omitempty"`
+	// BatchSize is the maximum number of records sent together. Defaults
+	// to 100.
+	BatchSize int `json:"batch-size,omitempty"`
+	// FlushInterval is the maximum time, in seconds, records wait to be
+	// batched before they are sent. Defaults to 5.
+	FlushInterval int `json:"flush-interval,omitempty"`
+	// MaxRetries is the number of times sending a batch is retried before
+	// its records are dropped. Defaults to 5.
+	MaxRetries int `json:"max-retries,omitempty"`
+}
+
+// ExporterStats are the statistics of an exporter on a peer
+type ExporterStats struct {
+	// Sent is the number of records sent to the collector
+	Sent uint64 `json:"sent"`
+	// Dropped is the number of records dropped because the exporter fell
+	// behind, or because sending them failed after all the retries
+	Dropped uint64 `json:"dropped"`
+	// Queued is the number of records waiting to be sent
+	Queued    int    `json:"queued"`
+	LastError string `json:"last-error,omitempty"`
+}
+
+// ExporterInfo represents an exporter along with its statistics on the peer
+// serving the request
+type ExporterInfo struct {
+	Exporter
+	Stats *ExporterStats `json:"stats,omitempty"`
+}
+
+// ExporterListResp is the response sent for a request to list the exporters
+type ExporterListResp []ExporterInfo
@@ -0,0 +1,31 @@
+package api
+
+// Actions that can be taken when an armed failure point is hit
+const (
+	// FailPointActionError makes the transaction step fail
+	FailPointActionError = "error"
+	// FailPointActionDelay delays the transaction step by the given duration
+	FailPointActionDelay = "delay"
+)
+
+// FailPoint represents a named failure injection point in the transaction
+// framework. Failure points are named as "<stepfunc>.<stage>", where stage is
+// one of "before" or "after-commit". For example,
+// "vol-create.StoreVolume.after-commit".
+type FailPoint struct {
+	Name string `json:"name"`
+	// Action is one of "error" or "delay"
+	Action string `json:"action"`
+	// Delay is a duration string (eg. "10s"), used with the "delay" action
+	Delay string `json:"delay,omitempty"`
+	// Peers restricts the failure point to the given peer IDs. The failure
+	// point is active on all peers if empty.
+	Peers []string `json:"peers,omitempty"`
+	// Count is the number of times the failure point triggers before
+	// disarming itself. A count of 0 keeps the failure point armed until
+	// it is explicitly removed.
+	Count int `json:"count,omitempty"`
+}
+
+// FailPointListResp is the response sent for a failure point list request
+type FailPointListResp []FailPoint
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"github.com/pborman/uuid"
+)
+
+// Health of the volumes of federated clusters
+const (
+	// FederatedVolUp means all the bricks of the volume are online
+	FederatedVolUp = "up"
+	// FederatedVolDegraded means some of the bricks of the volume are
+	// offline
+	FederatedVolDegraded = "degraded"
+	// FederatedVolDown means none of the bricks of the volume are online
+	FederatedVolDown = "down"
+	// FederatedVolStopped means the volume is not started
+	FederatedVolStopped = "stopped"
+	// FederatedVolUnknown means the status of the bricks of the volume
+	// could not be got
+	FederatedVolUnknown = "unknown"
+)
+
+// FederatedCluster is another glusterd2 cluster whose volumes are shown in
+// the federated view
+type FederatedCluster struct {
+	Name string `json:"name"`
+	// Endpoints are the REST endpoints of the peers of the cluster, which
+	// are failed over between
+	Endpoints []string `json:"endpoints"`
+	// Username and Secret authenticate the requests to the cluster, if
+	// it has REST authentication enabled
+	Username string `json:"username,omitempty"`
+	Secret   string `json:"secret,omitempty" store:"encrypt"`
+	// CACert is the PEM encoded CA certificate verifying the endpoints
+	CACert   string `json:"ca-cert,omitempty"`
+	Insecure bool   `json:"insecure,omitempty"`
+}
+
+// FederatedClusterListResp is the response sent for a request to list the
+// federated clusters
+type FederatedClusterListResp []FederatedCluster
+
+// FederatedClusterStatus is the status of a federated cluster
+type FederatedClusterStatus struct {
+	Name      string `json:"name"`
+	Reachable bool   `json:"reachable"`
+	// Error is the reason the cluster, or the status of some of its
+	// volumes, could not be got
+	Error       string `json:"error,omitempty"`
+	PeersTotal  int    `json:"peers-total"`
+	PeersOnline int    `json:"peers-online"`
+	Volumes     int    `json:"volumes"`
+}
+
+// FederatedVolume is a volume of a federated cluster
+type FederatedVolume struct {
+	Cluster      string    `json:"cluster"`
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Type         VolType   `json:"type"`
+	State        VolState  `json:"state"`
+	Capacity     uint64    `json:"capacity,omitempty"`
+	BricksTotal  int       `json:"bricks-total"`
+	BricksOnline int       `json:"bricks-online"`
+	Health       string    `json:"health"`
+}
+
+// FederatedVolumesResp is the response sent for a request of the federated
+// view of volumes
+type FederatedVolumesResp struct {
+	Clusters []FederatedClusterStatus `json:"clusters"`
+	Volumes  []FederatedVolume        `json:"volumes"`
+}
@@ -0,0 +1,22 @@
+package api
+
+// HealthPolicy holds the intervals and thresholds of the periodic health
+// checks run by every peer, with durations in seconds. Fields which are 0
+// leave the defaults in effect, or for the interval of the health checks of
+// the embedded etcd server, the interval the peer is configured with.
+type HealthPolicy struct {
+	// EtcdHealthInterval is the interval between health checks of the
+	// embedded etcd server
+	EtcdHealthInterval int `json:"etcd-health-interval,omitempty"`
+	// EtcdHealthTimeout is the time the embedded etcd server is given to
+	// respond to a health check
+	EtcdHealthTimeout int `json:"etcd-health-timeout,omitempty"`
+	// EtcdUnresponsiveThreshold is the number of consecutive failed health
+	// checks after which the embedded etcd server is restarted, when it is
+	// set to be restarted
+	EtcdUnresponsiveThreshold int `json:"etcd-unresponsive-threshold,omitempty"`
+	// StoreHealthInterval is the interval between checks of the session
+	// of the peer with the store, which keeps the peer online, and of the
+	// health of the store once the session has been lost
+	StoreHealthInterval int `json:"store-health-interval,omitempty"`
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"time"
+)
+
+// States of the IO intensive operations coordinated on the bricks
+const (
+	// IOOpRunning means the operation is running on its bricks
+	IOOpRunning = "running"
+	// IOOpThrottled means the operation is running, but has been throttled
+	// for an operation of higher priority on the same bricks
+	IOOpThrottled = "throttled"
+	// IOOpQueued means the operation is waiting for the conflicting
+	// operations on its bricks to complete before it is started
+	IOOpQueued = "queued"
+)
+
+// IOOp is an IO intensive operation, like scrub, rebalance or full heal, on
+// the bricks of a volume. Such operations are coordinated across the cluster
+// so that they don't run together on the same bricks.
+type IOOp struct {
+	Op     string `json:"op"`
+	Volume string `json:"volume"`
+	State  string `json:"state"`
+	// Priority is the position of the operation in the cluster option
+	// cluster.io-ops-priority, 0 being the highest
+	Priority int `json:"priority"`
+	// Resources are the bricks, or the devices of the bricks, the
+	// operation runs on, as <peer-id>:<path>
+	Resources []string  `json:"resources"`
+	Since     time.Time `json:"since"`
+}
+
+// IOOpListResp is the response sent for a request to list the coordinated
+// IO intensive operations
+type IOOpListResp []IOOp
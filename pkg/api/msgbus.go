@@ -0,0 +1,26 @@
+package api
+
+import (
+	"time"
+)
+
+// MsgBusSubscription represents a subscription of this peer to a topic of
+// the message bus
+type MsgBusSubscription struct {
+	ID    string `json:"id"`
+	Topic string `json:"topic"`
+	// Subscriber is the name of the component which subscribed
+	Subscriber string    `json:"subscriber"`
+	Created    time.Time `json:"created"`
+	// Delivered is the number of messages delivered to the subscriber
+	Delivered uint64 `json:"delivered"`
+	// Dropped is the number of messages dropped because the subscriber
+	// fell behind
+	Dropped uint64 `json:"dropped"`
+	// Queued is the number of messages waiting to be delivered
+	Queued int `json:"queued"`
+}
+
+// MsgBusSubscriptionsResp is the response sent for a request to list the
+// message bus subscriptions of a peer
+type MsgBusSubscriptionsResp []MsgBusSubscription
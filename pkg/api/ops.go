@@ -0,0 +1,76 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// Op is a transaction in progress in the cluster
+type Op struct {
+	TxnID uuid.UUID `json:"txn-id"`
+	ReqID uuid.UUID `json:"request-id"`
+	// Operation is the name of the operation, like vol-create, which is
+	// empty till the transaction starts running its steps
+	Operation string `json:"operation,omitempty"`
+	// Targets are the names of the volumes, peers and other entities
+	// the transaction holds locks on
+	Targets    []string  `json:"targets"`
+	Originator uuid.UUID `json:"originator"`
+	User       string    `json:"user,omitempty"`
+	Step       string    `json:"step,omitempty"`
+	// StepIndex is the index of the step running, counted from 1
+	StepIndex int       `json:"step-index"`
+	StepCount int       `json:"step-count"`
+	StartedAt time.Time `json:"started-at"`
+	// Elapsed is the time since the transaction started, like 1m30s
+	Elapsed string `json:"elapsed"`
+	// RollingBack is set once a step has failed, and the steps run till
+	// then are being undone
+	RollingBack bool             `json:"rolling-back,omitempty"`
+	Rollback    []OpRollbackStep `json:"rollback,omitempty"`
+}
+
+// OpRollbackStep is the outcome of undoing a step of a failed transaction on
+// a node
+type OpRollbackStep struct {
+	UndoFunc string    `json:"undo-func"`
+	Node     uuid.UUID `json:"node"`
+	// Error is empty if the step was undone
+	Error string `json:"error,omitempty"`
+}
+
+// OpListResp is the response sent for a list of the transactions in progress
+type OpListResp []Op
+
+// TxnStepNode is the state of a step of a transaction on a node
+type TxnStepNode struct {
+	Node uuid.UUID `json:"node"`
+	// State is pending, succeeded or failed
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+}
+
+// TxnStep is a step of a transaction
+type TxnStep struct {
+	Step       string        `json:"step"`
+	Skipped    bool          `json:"skipped,omitempty"`
+	Nodes      []TxnStepNode `json:"nodes"`
+	StartedAt  time.Time     `json:"started-at,omitempty"`
+	FinishedAt time.Time     `json:"finished-at,omitempty"`
+}
+
+// Txn is a transaction in progress or completed, with the state of each of
+// its steps on each node
+type Txn struct {
+	Op
+	// State is running, succeeded or failed
+	State      string    `json:"state"`
+	Error      string    `json:"error,omitempty"`
+	FinishedAt time.Time `json:"finished-at,omitempty"`
+	Steps      []TxnStep `json:"steps"`
+}
+
+// TxnListResp is the response sent for a list of the transactions in
+// progress and in the history of completed transactions
+type TxnListResp []Txn
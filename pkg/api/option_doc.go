@@ -0,0 +1,30 @@
+package api
+
+// OptionDoc describes a volume option, to present help text and validation
+// hints to users
+type OptionDoc struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	DefaultValue string `json:"default,omitempty"`
+	// AllowedValues are the values the option can be set to, empty if
+	// the option can be set to any value of its type
+	AllowedValues []string `json:"allowed-values,omitempty"`
+	// Min and Max are the range of numeric options, both are zero if the
+	// range isn't restricted
+	Min            float64  `json:"min,omitempty"`
+	Max            float64  `json:"max,omitempty"`
+	OpVersion      uint32   `json:"op-version,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Level          string   `json:"level"`
+	Settable       bool     `json:"settable"`
+	Reconfigurable bool     `json:"reconfigurable"`
+	ForceRequired  bool     `json:"force-required"`
+	// RestartRequired is true if brick processes have to be restarted
+	// for a change of the option to take effect
+	RestartRequired bool `json:"restart-required"`
+}
+
+// OptionDocListResp is the response sent for a request to list the option
+// catalogue
+type OptionDocListResp []OptionDoc
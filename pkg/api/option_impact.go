@@ -0,0 +1,52 @@
+package api
+
+// Actions a process takes for an option change to apply
+const (
+	// ImpactReconfigure means the running process applies the change
+	// after fetching its regenerated volfile
+	ImpactReconfigure = "reconfigure"
+	// ImpactRestart means the process has to be restarted for the change
+	// to apply
+	ImpactRestart = "restart"
+	// ImpactNextStart means the change applies when the process is next
+	// started
+	ImpactNextStart = "next-start"
+)
+
+// OptionImpact describes where a changed option applies
+type OptionImpact struct {
+	Option string `json:"option"`
+	Value  string `json:"value"`
+	// Graphs lists the volfiles which change, like brick or client
+	Graphs []string `json:"graphs,omitempty"`
+	// Reconfigurable is true if running bricks apply the option without
+	// a restart
+	Reconfigurable bool `json:"reconfigurable"`
+}
+
+// ProcessImpact describes how a process is affected by an option change
+type ProcessImpact struct {
+	PeerID  string `json:"peer-id"`
+	Process string `json:"process"`
+	// Name identifies the process among those of its kind, like the
+	// path of a brick
+	Name   string `json:"name,omitempty"`
+	Volume string `json:"volume,omitempty"`
+	Action string `json:"action"`
+}
+
+// OptionImpactResp is the response sent for a request to analyse the impact
+// of an option change. Nothing is changed by such requests.
+type OptionImpactResp struct {
+	Volume    string          `json:"volume,omitempty"`
+	Options   []OptionImpact  `json:"options"`
+	Processes []ProcessImpact `json:"processes,omitempty"`
+	// ClientGraphChange is true if mounted clients get a new graph
+	ClientGraphChange bool `json:"client-graph-change"`
+	// QuorumAffected is true if the change affects client or server
+	// quorum, and with it the availability of the volume
+	QuorumAffected bool `json:"quorum-affected"`
+	// HealAffected is true if the change affects self-heal
+	HealAffected bool     `json:"heal-affected"`
+	Warnings     []string `json:"warnings,omitempty"`
+}
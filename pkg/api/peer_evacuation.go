@@ -0,0 +1,68 @@
+package api
+
+import "time"
+
+// States of a peer evacuation
+const (
+	// EvacuationRunning means the bricks of the peer are being replaced
+	EvacuationRunning = "running"
+	// EvacuationPaused means no more bricks are replaced until the
+	// evacuation is resumed
+	EvacuationPaused = "paused"
+	// EvacuationCompleted means all bricks of the peer have been replaced
+	// and healed, and the peer has been detached if requested
+	EvacuationCompleted = "completed"
+	// EvacuationFailed means some bricks could not be replaced, or the
+	// peer could not be detached. The evacuation can be resumed to retry.
+	EvacuationFailed = "failed"
+)
+
+// States of a brick being evacuated
+const (
+	// EvacBrickPending means the brick is yet to be replaced
+	EvacBrickPending = "pending"
+	// EvacBrickReplaced means the brick has been replaced, and the new
+	// brick is being healed
+	EvacBrickReplaced = "replaced"
+	// EvacBrickHealed means the new brick has been healed
+	EvacBrickHealed = "healed"
+	// EvacBrickFailed means the brick could not be replaced
+	EvacBrickFailed = "failed"
+)
+
+// PeerEvacuateReq represents a request to evacuate all bricks of a peer. The
+// new bricks are chosen by the bricks planner, limited by the same options as
+// for a replace brick request. The evacuated peer is always excluded.
+type PeerEvacuateReq struct {
+	// Detach removes the peer from the cluster once all its bricks have
+	// been replaced and healed
+	Detach       bool     `json:"detach,omitempty"`
+	LimitPeers   []string `json:"limit-peers,omitempty"`
+	LimitZones   []string `json:"limit-zones,omitempty"`
+	ExcludePeers []string `json:"exclude-peers,omitempty"`
+	ExcludeZones []string `json:"exclude-zones,omitempty"`
+	PeerSelector string   `json:"peer-selector,omitempty"`
+}
+
+// EvacuatedBrick is a brick of the evacuated peer and its replacement
+type EvacuatedBrick struct {
+	Volume    string `json:"volume"`
+	Path      string `json:"path"`
+	State     string `json:"state"`
+	NewPeerID string `json:"new-peer-id,omitempty"`
+	NewPath   string `json:"new-path,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PeerEvacuationResp is the response sent for a peer evacuation request. It
+// is the state of the evacuation, which is carried out in the background.
+type PeerEvacuationResp struct {
+	PeerEvacuateReq
+	PeerID    string           `json:"peer-id"`
+	State     string           `json:"state"`
+	Bricks    []EvacuatedBrick `json:"bricks"`
+	Detached  bool             `json:"detached"`
+	Error     string           `json:"error,omitempty"`
+	StartedAt time.Time        `json:"started-at"`
+	UpdatedAt time.Time        `json:"updated-at"`
+}
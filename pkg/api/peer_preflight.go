@@ -0,0 +1,44 @@
+package api
+
+// Results of peer preflight checks
+const (
+	PreflightPass = "pass"
+	PreflightWarn = "warn"
+	PreflightFail = "fail"
+)
+
+// PeerPreflightReq represents a request to run the preflight checks for
+// adding a peer, without adding it
+type PeerPreflightReq struct {
+	Addresses []string `json:"addresses"`
+}
+
+// PreflightCheck is the result of a preflight check run from an existing
+// peer against the peer being added
+type PreflightCheck struct {
+	Name string `json:"name"`
+	// PeerID is the ID of the peer which ran the check
+	PeerID  string `json:"peer-id"`
+	Result  string `json:"result"`
+	Message string `json:"message,omitempty"`
+}
+
+// PeerPreflightReport is the report of the preflight checks run from the
+// existing peers against a peer being added. Peer add is blocked by failed
+// checks, unless forced. Warnings do not block it.
+type PeerPreflightReport struct {
+	Address string           `json:"address"`
+	Passed  bool             `json:"passed"`
+	Checks  []PreflightCheck `json:"checks"`
+}
+
+// Failures returns the failed checks of the report
+func (r *PeerPreflightReport) Failures() []PreflightCheck {
+	var failed []PreflightCheck
+	for _, c := range r.Checks {
+		if c.Result == PreflightFail {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
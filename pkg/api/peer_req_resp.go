@@ -13,6 +13,15 @@ type Peer struct {
 	Online          bool              `json:"online"`
 	PID             int               `json:"pid,omitempty"`
 	Metadata        map[string]string `json:"metadata"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	// EtcdRole is the role of the peer in the etcd cluster of the store,
+	// one of voter, joining or proxy. It is empty with a remote store.
+	EtcdRole string `json:"etcd-role,omitempty"`
+	// EtcdRolePinned is the etcd role pinned for the peer by the admin
+	EtcdRolePinned string `json:"etcd-role-pinned,omitempty"`
+	// ManagementOnly is true if the peer is a management node, which
+	// doesn't host bricks and isn't a member of the etcd cluster
+	ManagementOnly bool `json:"management-only,omitempty"`
 }
 
 // PeerAddReq represents an incoming request to add a peer to the cluster
@@ -20,6 +29,8 @@ type PeerAddReq struct {
 	Addresses []string          `json:"addresses"`
 	Zone      string            `json:"zone,omitempty"`
 	Metadata  map[string]string `json:"metadata,omitempty"`
+	// Force adds the peer even if preflight checks fail
+	Force bool `json:"force,omitempty"`
 }
 
 // PeerEditReq represents an incoming request to edit metadata of peer
@@ -28,6 +39,29 @@ type PeerEditReq struct {
 	Metadata map[string]string `json:"metadata"`
 }
 
+// PeerMetadataReq represents an incoming request to replace the metadata of a
+// peer. Reserved metadata, with keys starting with _, is kept. Zone is the
+// failure domain of the peer, like its rack, and is kept if empty.
+type PeerMetadataReq struct {
+	Zone     string            `json:"zone,omitempty"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// EtcdSizeReq represents an incoming request to set the number of peers
+// running etcd voting members
+type EtcdSizeReq struct {
+	IdealSize int `json:"ideal-size"`
+}
+
+// EtcdSizeResp is the response sent for the etcd cluster size of the store
+type EtcdSizeResp struct {
+	// IdealSize is the number of peers which should run etcd voting
+	// members. The other peers run etcd clients proxying to the members.
+	IdealSize int `json:"ideal-size"`
+	// Voters is the number of peers currently running etcd voting members
+	Voters int `json:"voters"`
+}
+
 // PeerAddResp is the success response sent to a PeerAddReq request
 type PeerAddResp Peer
 
@@ -44,6 +78,7 @@ Example:
 	- GET http://localhost:24007/v1/peers?key={keyname}&value={value}
 	- GET http://localhost:24007/v1/peers?key={keyname}
 	- GET http://localhost:24007/v1/peers?value={value}
+	- GET http://localhost:24007/v1/peers?selector={label selector}
 Note - Cannot use query parameters if peerid is also supplied.
 */
 type PeerListResp []PeerGetResp
@@ -57,3 +92,31 @@ func (p *PeerAddReq) MetadataSize() int {
 func (p *PeerEditReq) MetadataSize() int {
 	return mapSize(p.Metadata)
 }
+
+// PeerOptionsReq represents an incoming request to override cluster options
+// for a peer
+type PeerOptionsReq struct {
+	Options map[string]string `json:"options"`
+}
+
+// PeerOptionsResp is the response sent for requests on the cluster options
+// overridden for a peer. It has the overridden options along with their
+// values.
+type PeerOptionsResp struct {
+	Options map[string]string `json:"options"`
+}
+
+// PeerLabelsReq represents an incoming request to set labels on a peer
+type PeerLabelsReq struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// PeerLabelsResp is the response sent for requests on the labels of a peer
+type PeerLabelsResp struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// PeerBricksResp is the response sent for a request to list the bricks of
+// all the volumes on a peer, with GET /peers/{peerid}/bricks. The bricks can
+// be filtered by their paths with ?path-prefix=
+type PeerBricksResp []BrickInfo
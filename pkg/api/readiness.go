@@ -0,0 +1,18 @@
+package api
+
+import "time"
+
+// SubsystemStatus represents the readiness of a GD2 subsystem
+type SubsystemStatus struct {
+	Name       string    `json:"name"`
+	Ready      bool      `json:"ready"`
+	Attempts   int       `json:"attempts"`
+	LastError  string    `json:"last-error,omitempty"`
+	ReadySince time.Time `json:"ready-since,omitempty"`
+}
+
+// ReadinessResp is the response sent to client for a readiness request
+type ReadinessResp struct {
+	Ready      bool              `json:"ready"`
+	Subsystems []SubsystemStatus `json:"subsystems"`
+}
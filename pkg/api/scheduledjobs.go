@@ -0,0 +1,35 @@
+package api
+
+import (
+	"time"
+)
+
+// ScheduledJobRun represents a single run of a scheduled job
+type ScheduledJobRun struct {
+	StartTime time.Time `json:"start-time"`
+	EndTime   time.Time `json:"end-time"`
+	PeerID    string    `json:"peer-id"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// ScheduledJob represents a periodic job run by the scheduler
+type ScheduledJob struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Schedule    string           `json:"schedule"`
+	Enabled     bool             `json:"enabled"`
+	NextRun     time.Time        `json:"next-run,omitempty"`
+	LastRun     *ScheduledJobRun `json:"last-run,omitempty"`
+}
+
+// ScheduledJobEnableReq represents a request to enable a scheduled job
+type ScheduledJobEnableReq struct {
+	// Schedule optionally overrides the schedule of the job
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// ScheduledJobListResp is the response sent for a scheduled job list request
+type ScheduledJobListResp []ScheduledJob
+
+// ScheduledJobHistoryResp is the response sent for a scheduled job history request
+type ScheduledJobHistoryResp []ScheduledJobRun
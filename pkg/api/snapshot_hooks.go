@@ -0,0 +1,46 @@
+package api
+
+import "time"
+
+// Phases of a snapshot create at which snapshot hooks are run
+const (
+	// SnapHookPre hooks are run before the bricks are barriered, and can be
+	// used to quiesce applications
+	SnapHookPre = "pre"
+	// SnapHookPost hooks are run once the bricks are snapshotted, and also
+	// when the snapshot fails after the pre hooks were run
+	SnapHookPost = "post"
+)
+
+// SnapHook is a hook run when creating snapshots. A hook either calls a
+// webhook, or runs a script from the snapshot hooks directory.
+type SnapHook struct {
+	Name string `json:"name"`
+	// Volume limits the hook to the snapshots of the volume. Hooks without
+	// a volume are run for the snapshots of all volumes.
+	Volume string `json:"volume,omitempty"`
+	Phase  string `json:"phase"`
+	URL    string `json:"url,omitempty"`
+	Script string `json:"script,omitempty"`
+	// Timeout is in seconds
+	Timeout int `json:"timeout,omitempty"`
+	// Mandatory hooks fail the snapshot if they fail, optional hooks only
+	// have their failure recorded
+	Mandatory bool `json:"mandatory"`
+}
+
+// SnapHookReq represents a request to register a snapshot hook
+type SnapHookReq SnapHook
+
+// SnapHookListResp is the response sent for a snapshot hook list request
+type SnapHookListResp []SnapHook
+
+// SnapHookResult is the outcome of running a snapshot hook
+type SnapHookResult struct {
+	Name      string        `json:"name"`
+	Phase     string        `json:"phase"`
+	Mandatory bool          `json:"mandatory"`
+	Success   bool          `json:"success"`
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+}
@@ -13,6 +13,9 @@ type SnapInfo struct {
 	ParentVolName string     `json:"parentname"`
 	Description   string     `json:"description"`
 	CreatedAt     time.Time  `json:"created-at"`
+	// HookResults are the outcomes of the hooks run when creating the
+	// snapshot
+	HookResults []SnapHookResult `json:"hook-results,omitempty"`
 }
 
 //SnapList contains snapshots information of a volume.
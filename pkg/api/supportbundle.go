@@ -0,0 +1,34 @@
+package api
+
+import (
+	"time"
+)
+
+// SupportBundleReq represents a request to generate a support bundle
+type SupportBundleReq struct {
+	// Peers limits the collection of data to the given peer IDs. Data is
+	// collected from all online peers if empty.
+	Peers []string `json:"peers,omitempty"`
+	// LogSize is the maximum number of bytes of the glusterd2 log to be
+	// collected from each peer
+	LogSize int64 `json:"log-size,omitempty"`
+	// PeerSelector limits the collection of data to the online peers with
+	// matching labels. It can't be used along with Peers.
+	PeerSelector string `json:"peer-selector,omitempty"`
+}
+
+// SupportBundleResp represents a support bundle. A bundle is generated by a
+// support-bundle async job, and has its ID and state, one of the AsyncJob
+// states. The peers and path are known once the job has succeeded.
+type SupportBundleResp struct {
+	ID        string    `json:"id"`
+	State     string    `json:"state"`
+	Peers     []string  `json:"peers"`
+	Path      string    `json:"path,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	StartTime time.Time `json:"start-time"`
+	EndTime   time.Time `json:"end-time,omitempty"`
+}
+
+// SupportBundleListResp is the response sent for a support bundle list request
+type SupportBundleListResp []SupportBundleResp
@@ -0,0 +1,28 @@
+package api
+
+// Roles of the users of the REST API
+const (
+	// RoleAdmin allows everything
+	RoleAdmin = "admin"
+	// RoleOperator allows reading the state of the cluster, and operating
+	// existing volumes and snapshots, like starting and stopping them,
+	// healing and rebalancing
+	RoleOperator = "operator"
+	// RoleReadOnly allows only reading the state of the cluster
+	RoleReadOnly = "read-only"
+)
+
+// User is a user of the REST API. Users authenticate with a token signed
+// with their secret and issued by their name, or with a client certificate
+// with their name as the common name.
+type User struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+	// Volumes, if set, limits the user to the requests for these volumes
+	Volumes []string `json:"volumes,omitempty"`
+	// Secret signs the tokens of the user. It is never sent in responses.
+	Secret string `json:"secret,omitempty" store:"encrypt"`
+}
+
+// UserListResp is the response sent for a request to list the users
+type UserListResp []User
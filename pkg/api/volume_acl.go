@@ -0,0 +1,15 @@
+package api
+
+// VolACLReq represents a request to set the client access control lists of
+// a volume. Allow and Reject are lists of client addresses, which may be IP
+// addresses, CIDR networks, IPv4 address patterns with wildcards or
+// hostnames. SSLAllow is the list of TLS identities allowed to connect when
+// TLS is enabled for the volume. All clients are allowed if Allow is empty.
+type VolACLReq struct {
+	Allow    []string `json:"allow,omitempty"`
+	Reject   []string `json:"reject,omitempty"`
+	SSLAllow []string `json:"ssl-allow,omitempty"`
+}
+
+// VolACLResp is the response sent for a volume ACL request
+type VolACLResp VolACLReq
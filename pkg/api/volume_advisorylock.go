@@ -0,0 +1,24 @@
+package api
+
+import "time"
+
+// VolAdvisoryLockReq represents a request to take an advisory lock on a
+// volume. Disruptive operations, like deleting the volume or restoring a
+// snapshot over it, are refused while the volume has an advisory lock. The
+// lock expires after TTL seconds unless it is taken again by the same holder.
+type VolAdvisoryLockReq struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder"`
+	TTL    int    `json:"ttl"`
+}
+
+// VolAdvisoryLock is an advisory lock held on a volume
+type VolAdvisoryLock struct {
+	Name    string    `json:"name"`
+	Holder  string    `json:"holder"`
+	Expires time.Time `json:"expires"`
+}
+
+// VolAdvisoryLockListResp is the response sent for a request to list the
+// advisory locks of a volume
+type VolAdvisoryLockListResp []VolAdvisoryLock
@@ -0,0 +1,31 @@
+package api
+
+import "time"
+
+// VolAutoExpandReq represents a request to set the auto expansion policy of
+// a volume. When the used capacity of the volume crosses Threshold percent,
+// the volume is expanded by Increment bytes as long as the size of the volume
+// stays within MaxSize bytes. A MaxSize of 0 does not limit the size.
+type VolAutoExpandReq struct {
+	Threshold int    `json:"threshold"`
+	Increment uint64 `json:"increment"`
+	MaxSize   uint64 `json:"max-size,omitempty"`
+}
+
+// VolUsageSample is a usage measurement of a volume
+type VolUsageSample struct {
+	Time     time.Time `json:"time"`
+	Capacity uint64    `json:"capacity"`
+	Used     uint64    `json:"used"`
+}
+
+// VolAutoExpandResp is the response sent for a volume auto expansion policy
+// request. Along with the policy, it contains the recent usage samples and
+// the result of the last expansion.
+type VolAutoExpandResp struct {
+	VolAutoExpandReq
+	Samples      []VolUsageSample `json:"samples"`
+	Expansions   int              `json:"expansions"`
+	LastExpanded time.Time        `json:"last-expanded,omitempty"`
+	LastError    string           `json:"last-error,omitempty"`
+}
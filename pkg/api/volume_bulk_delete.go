@@ -0,0 +1,52 @@
+package api
+
+import "time"
+
+// States of a bulk volume delete job, and of the deletion of each of its
+// volumes
+const (
+	VolumeDeletePending  = "pending"
+	VolumeDeleteRunning  = "running"
+	VolumeDeleteDone     = "done"
+	VolumeDeleteFailed   = "failed"
+	VolumeDeleteComplete = "complete"
+)
+
+// VolumeDeleteResult is the result of deleting a single volume of a bulk
+// volume delete job
+type VolumeDeleteResult struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	// Trashed is set if the volume was moved to the trash instead of
+	// being deleted
+	Trashed bool `json:"trashed,omitempty"`
+	// BrickWipeJobs are the IDs of the brick wipe jobs scheduled for the
+	// bricks of the volume
+	BrickWipeJobs []string `json:"brick-wipe-jobs,omitempty"`
+	// StatusCode is the HTTP status code a delete request for the volume
+	// alone would have failed with
+	StatusCode int    `json:"status-code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Attempts   int    `json:"attempts"`
+}
+
+// VolumeBulkDeleteJob is a job deleting many volumes, run in the background
+// by the peer which received the request. The job is complete once every
+// volume has been deleted or failed to be.
+type VolumeBulkDeleteJob struct {
+	ID          string               `json:"id"`
+	State       string               `json:"state"`
+	Selector    string               `json:"selector,omitempty"`
+	Wipe        bool                 `json:"wipe,omitempty"`
+	Purge       bool                 `json:"purge,omitempty"`
+	Concurrency int                  `json:"concurrency"`
+	Deleted     int                  `json:"deleted"`
+	Failed      int                  `json:"failed"`
+	Volumes     []VolumeDeleteResult `json:"volumes"`
+	StartTime   time.Time            `json:"start-time"`
+	EndTime     time.Time            `json:"end-time,omitempty"`
+}
+
+// VolumeBulkDeleteJobListResp is the response sent for a bulk volume delete
+// job list request
+type VolumeBulkDeleteJobListResp []VolumeBulkDeleteJob
@@ -0,0 +1,22 @@
+package api
+
+// IOLimits are the limits on the rate of I/O done by a brick process to the
+// device of its brick. A zero limit means the I/O is not limited.
+type IOLimits struct {
+	ReadIOPS  uint64 `json:"read-iops,omitempty"`
+	WriteIOPS uint64 `json:"write-iops,omitempty"`
+	// ReadBPS and WriteBPS are in bytes per second
+	ReadBPS  uint64 `json:"read-bps,omitempty"`
+	WriteBPS uint64 `json:"write-bps,omitempty"`
+}
+
+// VolIOThrottleReq represents a request to set the I/O limits of the bricks
+// of a volume. The limits apply to every brick of the volume, unless
+// overridden for the brick in Bricks, which is keyed by brick ID.
+type VolIOThrottleReq struct {
+	IOLimits
+	Bricks map[string]IOLimits `json:"bricks,omitempty"`
+}
+
+// VolIOThrottleResp is the response sent for a volume I/O throttling request
+type VolIOThrottleResp VolIOThrottleReq
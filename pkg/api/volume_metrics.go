@@ -0,0 +1,26 @@
+package api
+
+import (
+	"time"
+)
+
+// VolMetricsSample is a sample of the metrics of a volume recorded by the
+// metrics sampler
+type VolMetricsSample struct {
+	Time         time.Time `json:"time"`
+	Capacity     uint64    `json:"capacity"`
+	Used         uint64    `json:"used"`
+	BricksTotal  int       `json:"bricks-total"`
+	BricksOnline int       `json:"bricks-online"`
+	// HealBacklog is the number of entries pending heal, it is not set
+	// for volumes which are not replicated
+	HealBacklog *int64 `json:"heal-backlog,omitempty"`
+}
+
+// VolMetricsResp is the response sent for a volume metrics request. Samples
+// are sorted by time.
+type VolMetricsResp struct {
+	Volume  string             `json:"volume"`
+	From    time.Time          `json:"from"`
+	Samples []VolMetricsSample `json:"samples"`
+}
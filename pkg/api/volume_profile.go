@@ -0,0 +1,29 @@
+package api
+
+// VolumeProfile is a named bundle of volume options and layout hints, which
+// volumes can be created with to standardize deployments. The options and
+// hints are only used where the volume create request leaves them unset.
+type VolumeProfile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Options can include option groups, and are validated with the
+	// option flags of the profile when the profile is created
+	Options map[string]string `json:"options,omitempty"`
+	VolOptionFlags
+
+	// Layout hints, used for the bricks planned for volumes created by
+	// size
+	ReplicaCount            int     `json:"replica,omitempty"`
+	ArbiterCount            int     `json:"arbiter,omitempty"`
+	DisperseCount           int     `json:"disperse,omitempty"`
+	DisperseRedundancyCount int     `json:"disperse-redundancy,omitempty"`
+	MaxBrickSize            uint64  `json:"max-brick-size,omitempty"`
+	AverageFileSize         uint64  `json:"average-file-size,omitempty"`
+	SnapshotEnabled         bool    `json:"snapshot,omitempty"`
+	SnapshotReserveFactor   float64 `json:"snapshot-reserve-factor,omitempty"`
+	Transport               string  `json:"transport,omitempty"`
+}
+
+// VolumeProfileListResp is the response sent for a request to list the
+// volume profiles
+type VolumeProfileListResp []VolumeProfile
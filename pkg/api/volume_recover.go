@@ -0,0 +1,39 @@
+package api
+
+// RecoveredBrick is a brick found on a peer when restoring volumes from
+// bricks, from the brick volfiles left on the peer
+type RecoveredBrick struct {
+	PeerID     string `json:"peer-id"`
+	Path       string `json:"path"`
+	VolumeName string `json:"volume-name"`
+	// VolumeID is the volume ID read from the brick, empty if it could
+	// not be read
+	VolumeID string `json:"volume-id,omitempty"`
+	Arbiter  bool   `json:"arbiter,omitempty"`
+	// Layout is the DHT hash range of the brick. Bricks of the same
+	// subvolume have the same hash range.
+	Layout string `json:"layout,omitempty"`
+	// SubvolType is the type of the subvolume of the brick, replicate or
+	// disperse, as guessed from the brick. It is empty if not known.
+	SubvolType string `json:"subvol-type,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// VolRecoveryProposal is a volume which can be recreated from the bricks
+// found when restoring volumes from bricks. The proposal has to be reviewed,
+// and the volume is recreated by sending the request as a volume create
+// request.
+type VolRecoveryProposal struct {
+	Name     string           `json:"name"`
+	ID       string           `json:"id"`
+	Bricks   []RecoveredBrick `json:"bricks"`
+	Req      VolCreateReq     `json:"request"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+// RestoreFromBricksResp is the response sent for a restore from bricks
+// request. Volumes already in the store are not proposed.
+type RestoreFromBricksResp struct {
+	Volumes  []VolRecoveryProposal `json:"volumes"`
+	Warnings []string              `json:"warnings,omitempty"`
+}
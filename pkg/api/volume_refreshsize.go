@@ -0,0 +1,21 @@
+package api
+
+import "github.com/pborman/uuid"
+
+// BrickCapacity is the capacity of the filesystem of a brick
+type BrickCapacity struct {
+	ID       uuid.UUID `json:"id"`
+	Hostname string    `json:"host"`
+	Path     string    `json:"path"`
+	Capacity uint64    `json:"capacity"`
+}
+
+// VolumeRefreshSizeResp is the response sent for a request to refresh the
+// size of a volume after its bricks are resized. Capacity is the usable
+// capacity of the volume calculated from the current brick capacities.
+type VolumeRefreshSizeResp struct {
+	Volume      string          `json:"volume"`
+	OldCapacity uint64          `json:"old-capacity"`
+	Capacity    uint64          `json:"capacity"`
+	Bricks      []BrickCapacity `json:"bricks"`
+}
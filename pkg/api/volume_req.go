@@ -37,6 +37,10 @@ type SubvolReq struct {
 "allow-root-dir" : allow root directory to create brick
 "allow-mount-as-brick" : reuse if its already mountpoint
 "create-brick-dir" : if brick dir is not present, create it
+
+If Adopt is set, the bricks are expected to already contain the data of a
+volume (with matching volume-id xattrs), which is then recreated with the same
+volume ID. This is used to recover volumes when only the brick data survives.
 */
 type VolCreateReq struct {
 	Name                    string            `json:"name"`
@@ -62,6 +66,16 @@ type VolCreateReq struct {
 	ExcludeZones            []string          `json:"exclude-zones,omitempty"`
 	SubvolZonesOverlap      bool              `json:"subvolume-zones-overlap,omitempty"`
 	SubvolType              string            `json:"subvolume-type,omitempty"`
+	Adopt                   bool              `json:"adopt,omitempty"`
+	// JobID tags the progress events of the request, defaults to the
+	// request ID
+	JobID string `json:"job-id,omitempty"`
+	// PeerSelector limits choosing the bricks to peers with matching
+	// labels, like "disk=ssd,rack!=r1"
+	PeerSelector string `json:"peer-selector,omitempty"`
+	// Profile is the name of a volume profile, whose options and layout
+	// hints are used where the request leaves them unset
+	Profile string `json:"profile,omitempty"`
 	VolOptionReq
 }
 
@@ -94,12 +108,20 @@ type VolOptionResetReq struct {
 "create-brick-dir" : if brick dir is not present, create it
 */
 type VolExpandReq struct {
-	ReplicaCount    int             `json:"replica,omitempty"`
-	Bricks          []BrickReq      `json:"bricks,omitempty"`
-	Force           bool            `json:"force,omitempty"`
-	Flags           map[string]bool `json:"flags,omitempty"`
-	Size            uint64          `json:"size,omitempty"`
-	DistributeCount int             `json:"distribute,omitempty"`
+	ReplicaCount int             `json:"replica,omitempty"`
+	Bricks       []BrickReq      `json:"bricks,omitempty"`
+	Force        bool            `json:"force,omitempty"`
+	Flags        map[string]bool `json:"flags,omitempty"`
+	// Size expands the volume by the given number of bytes, without
+	// giving bricks. If the distribute count is the number of subvolumes
+	// of the volume, its auto provisioned bricks are grown in place.
+	// Otherwise subvolumes are added up to the distribute count, or one
+	// subvolume if it isn't given, with bricks chosen from the devices of
+	// the peers and provisioned like those of a volume created by size.
+	Size            uint64 `json:"size,omitempty"`
+	DistributeCount int    `json:"distribute,omitempty"`
+	// Rebalance starts rebalancing the volume once it has been expanded
+	Rebalance bool `json:"rebalance,omitempty"`
 }
 
 // VolumeOption represents an option that is part of a profile
@@ -142,6 +164,14 @@ type VolEditReq struct {
 	DeleteMetadata bool              `json:"delete-metadata"`
 }
 
+// VolRenameReq represents a volume rename request. Force is needed to rename
+// replicate volumes, as the pending self-heals recorded on their bricks refer
+// to the volume by its name.
+type VolRenameReq struct {
+	NewName string `json:"new-name"`
+	Force   bool   `json:"force,omitempty"`
+}
+
 // ReplaceBrickReq represents replace brick request
 type ReplaceBrickReq struct {
 	SrcPeerID          string          `json:"src-peerid"`
@@ -153,6 +183,9 @@ type ReplaceBrickReq struct {
 	SubvolZonesOverlap bool            `json:"subvolume-zones-overlap,omitempty"`
 	Force              bool            `json:"force,omitempty"`
 	Flags              map[string]bool `json:"flags,omitempty"`
+	// PeerSelector limits choosing the new brick to peers with matching
+	// labels
+	PeerSelector string `json:"peer-selector,omitempty"`
 }
 
 // VolumeStartReq represents a request to start volume
@@ -169,3 +202,9 @@ func (v *VolCreateReq) MetadataSize() int {
 func (v *VolEditReq) MetadataSize() int {
 	return mapSize(v.Metadata)
 }
+
+// VolOptionsRollbackReq represents a request to revert the options of a
+// volume to an entry in its options history
+type VolOptionsRollbackReq struct {
+	ID string `json:"id"`
+}
@@ -1,6 +1,10 @@
 package api
 
-import "github.com/pborman/uuid"
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
 
 // BrickInfo contains the static information about the brick.
 // Clients should NOT use this struct directly.
@@ -14,6 +18,17 @@ type BrickInfo struct {
 	Type       BrickType `json:"type"`
 }
 
+// BrickLookupResp is the response sent for a lookup of the brick at a path,
+// or on an LV
+type BrickLookupResp struct {
+	BrickInfo
+	// SubvolIndex and BrickIndex are the positions of the subvolume in the
+	// volume and of the brick in the subvolume, counted from 1
+	SubvolIndex int    `json:"subvol-index"`
+	BrickIndex  int    `json:"brick-index"`
+	DevicePath  string `json:"device-path,omitempty"`
+}
+
 // Subvol contains static information about sub volume
 type Subvol struct {
 	Name                    string      `json:"name"`
@@ -30,9 +45,12 @@ type Subvol struct {
 // SizeInfo represents sizing information.
 // Clients should NOT use this struct directly.
 type SizeInfo struct {
-	Capacity uint64 `json:"capacity"`
-	Used     uint64 `json:"used"`
-	Free     uint64 `json:"free"`
+	Capacity    uint64 `json:"capacity"`
+	Used        uint64 `json:"used"`
+	Free        uint64 `json:"free"`
+	BlockSize   uint64 `json:"block-size"`
+	InodesTotal uint64 `json:"inodes-total"`
+	InodesFree  uint64 `json:"inodes-free"`
 }
 
 // BrickStatus contains the runtime information about the brick.
@@ -89,8 +107,31 @@ type VolumeOptionGetResp struct {
 	OptionLevel  string `json:"option-level"`
 }
 
+// BrickPlacement is the peer and zone a brick of a new volume was placed on
+type BrickPlacement struct {
+	Subvol string    `json:"subvol"`
+	Path   string    `json:"path"`
+	PeerID uuid.UUID `json:"peer-id"`
+	Zone   string    `json:"zone"`
+}
+
+// VolumePlacement is the placement of the bricks of a new volume, for
+// auditing how its replicas are spread across failure domains
+type VolumePlacement struct {
+	// Planned is true if the bricks were chosen by the bricks planner for
+	// a volume created by size, rather than given in the request
+	Planned bool             `json:"planned"`
+	Bricks  []BrickPlacement `json:"bricks"`
+	// ZonesSpread is true if the bricks of each replicate and disperse
+	// subvolume are all in different zones
+	ZonesSpread bool `json:"zones-spread"`
+}
+
 // VolumeCreateResp is the response sent for a volume create request.
-type VolumeCreateResp VolumeInfo
+type VolumeCreateResp struct {
+	VolumeInfo
+	Placement *VolumePlacement `json:"placement,omitempty"`
+}
 
 // VolumeGetResp is the response sent for a volume get request.
 /*
@@ -117,8 +158,34 @@ type VolumeStartResp VolumeInfo
 // VolumeStopResp is the response sent for a volume stop request.
 type VolumeStopResp VolumeInfo
 
+// Statuses of a brick after changing volume options
+const (
+	// BrickReconfigured means the running brick applied the new options
+	// without a restart
+	BrickReconfigured = "reconfigured"
+	// BrickRestartRequired means some of the changed options are applied
+	// only when the brick is restarted
+	BrickRestartRequired = "restart-required"
+	// BrickReconfigureFailed means the running brick could not be
+	// reconfigured
+	BrickReconfigureFailed = "failed"
+)
+
+// BrickReconfigureStatus reports whether a running brick applied the changed
+// volume options
+type BrickReconfigureStatus struct {
+	Info   BrickInfo `json:"info"`
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+}
+
 // VolumeOptionResp is the response sent for a volume option request.
-type VolumeOptionResp VolumeInfo
+// Reconfigure lists the running bricks and whether they applied the changed
+// options; it is empty if the volume is not started.
+type VolumeOptionResp struct {
+	VolumeInfo
+	Reconfigure []BrickReconfigureStatus `json:"reconfigure,omitempty"`
+}
 
 // VolumeListResp is the response sent for a volume list request.
 /*VolumeListResp can also be filtered based on query parameters
@@ -129,14 +196,64 @@ Example of API request
         - GET http://localhost:24007/v1/volumes?key={keyname}
         - GET http://localhost:24007/v1/volumes?value={value}
 Note - Cannot use query parameters if volname is also supplied.
+
+Volumes can be listed in pages of at most limit volumes, in the order of
+their names. The token for the next page is sent in the X-Gluster-Continue
+response header, and is not sent with the last page.
+        - GET http://localhost:24007/v1/volumes?limit={limit}
+        - GET http://localhost:24007/v1/volumes?limit={limit}&continue={token}
 */
 type VolumeListResp []VolumeGetResp
 
+// VolumeListFieldsResp is the response sent for a volume list request
+// selecting only some fields of the volumes, with the fields query parameter.
+// Only id, name, type, state and metadata can be selected.
+//   - GET http://localhost:24007/v1/volumes?fields=name,state
+type VolumeListFieldsResp []map[string]interface{}
+
 // OptionGroupListResp is the response sent for a group list request.
 type OptionGroupListResp []OptionGroup
 
 // VolumeEditResp is the response sent for a edit volume request
 type VolumeEditResp VolumeInfo
 
+// VolumeRenameResp is the response sent for a volume rename request
+type VolumeRenameResp VolumeInfo
+
 // VolumeOptionsGetResp is the response sent for a volume get request for all options
 type VolumeOptionsGetResp []VolumeOptionGetResp
+
+// VolOptionChange represents the change of a single volume option
+type VolOptionChange struct {
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// VolOptionsHistoryEntry represents a change to the options of a volume,
+// along with the complete set of options after the change
+type VolOptionsHistoryEntry struct {
+	ID        string                     `json:"id"`
+	Time      time.Time                  `json:"time"`
+	User      string                     `json:"user,omitempty"`
+	ReqID     string                     `json:"req-id,omitempty"`
+	Operation string                     `json:"operation"`
+	Changes   map[string]VolOptionChange `json:"changes"`
+	Options   map[string]string          `json:"options"`
+}
+
+// VolOptionsHistoryResp is the response sent for a volume options history request
+type VolOptionsHistoryResp []VolOptionsHistoryEntry
+
+// VolDiffResp is the response sent for a volume diff request. It has the
+// changes made to the volume between two store revisions.
+type VolDiffResp struct {
+	FromRevision int64 `json:"from-revision"`
+	ToRevision   int64 `json:"to-revision"`
+	// Fields has the changes of the volume attributes like the name, state
+	// and type, keyed by the attribute
+	Fields        map[string]VolOptionChange `json:"fields,omitempty"`
+	Options       map[string]VolOptionChange `json:"options,omitempty"`
+	Metadata      map[string]VolOptionChange `json:"metadata,omitempty"`
+	BricksAdded   []BrickInfo                `json:"bricks-added,omitempty"`
+	BricksRemoved []BrickInfo                `json:"bricks-removed,omitempty"`
+}
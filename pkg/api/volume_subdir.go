@@ -0,0 +1,23 @@
+package api
+
+// SubdirExportReq represents a request to export a subdirectory of a volume.
+// Clients is the list of client addresses allowed to mount the
+// subdirectory; all clients are allowed if it is empty. The subdirectory
+// must be created on the volume before clients can mount it.
+type SubdirExportReq struct {
+	Path    string   `json:"path"`
+	Clients []string `json:"clients,omitempty"`
+}
+
+// SubdirExportInfo contains information about a subdirectory export
+type SubdirExportInfo struct {
+	Path    string   `json:"path"`
+	Clients []string `json:"clients,omitempty"`
+}
+
+// SubdirExportResp is the response sent for a subdirectory export request
+type SubdirExportResp SubdirExportInfo
+
+// SubdirExportListResp is the response sent for a request to list the
+// subdirectory exports of a volume
+type SubdirExportListResp []SubdirExportInfo
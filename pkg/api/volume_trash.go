@@ -0,0 +1,23 @@
+package api
+
+import "time"
+
+// DeletedVolume represents a deleted volume kept in the trash until it is
+// purged. The bricks of the volume are left untouched until then, so the
+// volume can be restored.
+type DeletedVolume struct {
+	VolumeInfo
+	DeletedAt time.Time `json:"deleted-at"`
+	// PurgeAt is when the volume is purged, cleaning up its bricks
+	PurgeAt time.Time `json:"purge-at"`
+	// Wipe is true if the bricks are wiped when the volume is purged
+	Wipe bool `json:"wipe,omitempty"`
+}
+
+// DeletedVolumeListResp is the response sent for a request to list the deleted
+// volumes, with GET /volumes?deleted=true
+type DeletedVolumeListResp []DeletedVolume
+
+// VolumeRestoreResp is the response sent for a request to restore a deleted
+// volume
+type VolumeRestoreResp VolumeInfo
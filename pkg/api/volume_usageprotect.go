@@ -0,0 +1,23 @@
+package api
+
+import "time"
+
+// VolUsageProtectReq represents a request to set the usage protection policy
+// of a volume. When the used capacity of the volume crosses CriticalThreshold
+// percent, the volume is made read-only. It is made writable again once the
+// used capacity drops below ResumeThreshold percent.
+type VolUsageProtectReq struct {
+	CriticalThreshold int `json:"critical-threshold"`
+	ResumeThreshold   int `json:"resume-threshold"`
+}
+
+// VolUsageProtectResp is the response sent for a volume usage protection
+// policy request. Along with the policy, it contains the last usage sample
+// and whether the policy has made the volume read-only.
+type VolUsageProtectResp struct {
+	VolUsageProtectReq
+	Engaged        bool            `json:"engaged"`
+	LastSample     *VolUsageSample `json:"last-sample,omitempty"`
+	LastTransition time.Time       `json:"last-transition,omitempty"`
+	LastError      string          `json:"last-error,omitempty"`
+}
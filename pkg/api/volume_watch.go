@@ -0,0 +1,24 @@
+package api
+
+// Types of volume watch events
+const (
+	VolumeWatchPut    = "put"
+	VolumeWatchDelete = "delete"
+)
+
+// VolumeWatchEvent is a change to a volume. Volume is set only for put
+// events, which represent both creation and update of a volume.
+type VolumeWatchEvent struct {
+	Type     string         `json:"type"`
+	Name     string         `json:"name"`
+	Revision int64          `json:"revision"`
+	Volume   *VolumeGetResp `json:"volume,omitempty"`
+}
+
+// VolumeWatchResp is the response sent for a volume watch request. The next
+// watch request should be made from Revision to receive the changes made
+// after the events in this response.
+type VolumeWatchResp struct {
+	Revision int64              `json:"revision"`
+	Events   []VolumeWatchEvent `json:"events"`
+}
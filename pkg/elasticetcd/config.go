@@ -3,6 +3,7 @@ package elasticetcd
 import (
 	"net"
 	"path"
+	"time"
 
 	"github.com/coreos/etcd/pkg/types"
 )
@@ -15,6 +16,12 @@ const (
 	DefaultName      = "elasticetcd"
 	DefaultIdealSize = 3
 	DefaultDir       = "."
+	// DefaultStopTimeout is the time the embedded etcd server is given to
+	// stop gracefully, before it is stopped forcibly
+	DefaultStopTimeout = 30 * time.Second
+	// DefaultHealthInterval is the interval between health checks of the
+	// embedded etcd server
+	DefaultHealthInterval = 10 * time.Second
 )
 
 var (
@@ -68,6 +75,9 @@ type Config struct {
 	Name, Dir, LogDir       string
 	Endpoints, CURLs, PURLs types.URLs
 	IdealSize               int
+	StopTimeout             time.Duration
+	HealthInterval          time.Duration
+	AutoRestart             bool
 	DisableLogging          bool
 	UseTLS                  bool
 	CAFile, TrustedCAFile   string
@@ -75,18 +85,25 @@ type Config struct {
 	KeyFile                 string
 	ClntCertFile            string
 	ClntKeyFile             string
+
+	// NoVolunteer keeps the instance from volunteering to be a server, so
+	// that it is never nominated and only connects to the etcd cluster as
+	// a client
+	NoVolunteer bool
 }
 
 // NewConfig returns an ElasticEtcd config with defaults filled
 func NewConfig() *Config {
 	return &Config{
-		Name:      DefaultName,
-		Dir:       DefaultDir,
-		LogDir:    path.Join(DefaultDir, "log"),
-		Endpoints: defaultEndpoints,
-		CURLs:     defaultCURLs,
-		PURLs:     defaultPURLs,
-		IdealSize: DefaultIdealSize,
+		Name:           DefaultName,
+		Dir:            DefaultDir,
+		LogDir:         path.Join(DefaultDir, "log"),
+		Endpoints:      defaultEndpoints,
+		CURLs:          defaultCURLs,
+		PURLs:          defaultPURLs,
+		IdealSize:      DefaultIdealSize,
+		StopTimeout:    DefaultStopTimeout,
+		HealthInterval: DefaultHealthInterval,
 	}
 }
 
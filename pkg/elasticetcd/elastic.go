@@ -1,11 +1,13 @@
 package elasticetcd
 
 import (
+	"context"
 	"io"
 	"sync"
 
 	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/clientv3/concurrency"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
 
 	"github.com/sirupsen/logrus"
 )
@@ -24,10 +26,18 @@ type ElasticEtcd struct {
 	logFile io.WriteCloser
 
 	stopping bool
+	left     bool
 
 	stopwatching chan struct{}
 	watchers     sync.WaitGroup
 
+	stopmonitor         chan struct{}
+	monitor             sync.WaitGroup
+	healthHandler       HealthHandler
+	healthPolicy        HealthPolicy
+	healthPolicyChanged chan struct{}
+	healthLock          sync.Mutex
+
 	lock sync.RWMutex
 }
 
@@ -38,6 +48,7 @@ func New(conf *Config) (*ElasticEtcd, error) {
 	ee := new(ElasticEtcd)
 	ee.conf = conf
 	ee.stopwatching = make(chan struct{})
+	ee.healthPolicyChanged = make(chan struct{}, 1)
 	ee.initLogging()
 
 	// If no endpoints are given or if the default endpoint is set, assume that there is no existing server
@@ -66,25 +77,150 @@ func New(conf *Config) (*ElasticEtcd, error) {
 		ee.addToNominees(ee.conf.Name, ee.server.srv.Config().APUrls)
 	}
 
-	// Volunteer self and start watching for your nomination
-	if err := ee.volunteerSelf(); err != nil {
-		ee.Stop()
-		return nil, err
+	// Volunteer self and start watching for your nomination. An instance
+	// which started its own server still volunteers even with NoVolunteer,
+	// as it is the only server of its cluster.
+	if serverStarted || !ee.conf.NoVolunteer {
+		if err := ee.volunteerSelf(); err != nil {
+			ee.Stop()
+			return nil, err
+		}
 	}
 
 	// Start campaign to become the leader
 	ee.startCampaign()
 
+	ee.startHealthMonitor()
+
 	return ee, nil
 }
 
-// Stop stops the ElasticEtcd instance
+// Stop stops the ElasticEtcd instance, giving the embedded etcd server the
+// configured stop timeout to stop gracefully
 func (ee *ElasticEtcd) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), ee.stopTimeout())
+	defer cancel()
+	ee.StopGraceful(ctx)
+}
+
+// StopGraceful stops the ElasticEtcd instance. The embedded etcd server, if
+// running, is stopped gracefully, and is stopped forcibly only if it hasn't
+// stopped by the time the context is done.
+func (ee *ElasticEtcd) StopGraceful(ctx context.Context) error {
+	ee.stopHealthMonitor()
+
 	ee.lock.Lock()
 	defer ee.lock.Unlock()
 
 	ee.stopping = true
 	ee.stopClient()
-	ee.stopServer()
+	var err error
+	if ee.server.srv != nil {
+		err = ee.stopServerGraceful(ctx)
+	}
 	ee.logFile.Close()
+
+	return err
+}
+
+// Leave removes the ElasticEtcd instance from the elastic cluster. The
+// instance withdraws its nomination and volunteering, and its embedded etcd
+// server, if running, is removed from the etcd cluster membership and
+// stopped. The last member of the etcd cluster cannot leave.
+func (ee *ElasticEtcd) Leave() error {
+	ee.lock.Lock()
+	defer ee.lock.Unlock()
+
+	if ee.left {
+		return nil
+	}
+
+	if ee.cli == nil {
+		return ErrClientNotAvailable
+	}
+
+	var member *etcdserverpb.Member
+	if ee.server.srv != nil {
+		memlist, err := ee.cli.MemberList(ee.cli.Ctx())
+		if err != nil {
+			ee.log.WithError(err).Error("failed to get memberlist while leaving cluster")
+			return err
+		}
+		for _, m := range memlist.Members {
+			if m.Name == ee.conf.Name {
+				member = m
+				break
+			}
+		}
+		if member != nil && len(memlist.Members) == 1 {
+			return ErrLastMember
+		}
+	}
+
+	// Withdraw the nomination first, so that the leader doesn't try to
+	// remove the membership on its own. The nomination handler waits for
+	// the lock, by which time the server has been stopped already.
+	if err := ee.removeFromNominees(ee.conf.Name); err != nil {
+		return err
+	}
+
+	if member != nil {
+		if _, err := ee.cli.MemberRemove(ee.cli.Ctx(), member.ID); err != nil {
+			ee.log.WithError(err).Error("failed to remove self as etcd cluster member")
+			return err
+		}
+		ee.stopServer()
+	}
+
+	// The volunteer key is attached to the session lease and goes away
+	// when the session is closed anyway
+	key := volunteerPrefix + ee.conf.Name
+	if _, err := ee.cli.Delete(ee.cli.Ctx(), key); err != nil {
+		ee.log.WithError(err).Warn("failed to remove self from volunteer list")
+	}
+	if _, err := ee.cli.Delete(ee.cli.Ctx(), pinsPrefix+ee.conf.Name); err != nil {
+		ee.log.WithError(err).Warn("failed to remove own pinned role")
+	}
+
+	ee.left = true
+	ee.log.Debug("left the elastic cluster")
+
+	return nil
+}
+
+// RemoveMember removes the named instance from the elastic cluster. Its
+// volunteering, nomination and pinned role are withdrawn, and its etcd server,
+// if any, is removed from the etcd cluster membership, so that it doesn't
+// count towards the etcd quorum. Use this for instances which have been
+// removed from the cluster, but may not have left the elastic cluster on their
+// own. The last member of the etcd cluster cannot be removed.
+func (ee *ElasticEtcd) RemoveMember(name string) error {
+	if name == ee.conf.Name {
+		return ErrRemoveSelf
+	}
+
+	ee.lock.Lock()
+	defer ee.lock.Unlock()
+
+	if ee.cli == nil {
+		return ErrClientNotAvailable
+	}
+
+	logger := ee.log.WithField("host", name)
+
+	// Withdraw the volunteering first, so that the leader doesn't nominate
+	// the instance again
+	if _, err := ee.cli.Delete(ee.cli.Ctx(), volunteerPrefix+name); err != nil {
+		logger.WithError(err).Error("failed to remove host from volunteer list")
+		return err
+	}
+	if err := ee.removeNomination(name); err != nil {
+		return err
+	}
+	if _, err := ee.cli.Delete(ee.cli.Ctx(), pinsPrefix+name); err != nil {
+		logger.WithError(err).Warn("failed to remove pinned role of host")
+	}
+
+	logger.Debug("removed host from the elastic cluster")
+	return nil
 }
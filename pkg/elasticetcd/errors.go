@@ -9,4 +9,14 @@ var (
 	ErrClientNotAvailable = errors.New("etcd client not available")
 	// ErrAddingSelfToServerList is returned when an ElasticEtcd instance fails to add itself to the nominated servers list
 	ErrAddingSelfToServerList = errors.New("failed to add self to server list")
+	// ErrLastMember is returned when the last member of the etcd cluster tries to leave it
+	ErrLastMember = errors.New("cannot leave, this is the last member of the etcd cluster")
+	// ErrServerStopTimeout is returned when the embedded etcd server doesn't stop gracefully in time, and had to be stopped forcibly
+	ErrServerStopTimeout = errors.New("etcd server did not stop gracefully in time, stopped forcibly")
+	// ErrRemoveSelf is returned when an ElasticEtcd instance tries to remove itself as a member instead of leaving
+	ErrRemoveSelf = errors.New("cannot remove self as a member, leave the cluster instead")
+	// ErrRemoveLastMember is returned when removing the last member of the etcd cluster
+	ErrRemoveLastMember = errors.New("cannot remove the last member of the etcd cluster")
+	// ErrInvalidIdealSize is returned when setting an ideal size of less than one for the etcd cluster
+	ErrInvalidIdealSize = errors.New("ideal size of the etcd cluster must be at least 1")
 )
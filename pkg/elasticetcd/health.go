@@ -0,0 +1,273 @@
+package elasticetcd
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// HealthState is the health of the embedded etcd server of an ElasticEtcd
+// instance, as seen by the health monitor
+type HealthState string
+
+// Health states reported to the HealthHandler
+const (
+	// HealthDegraded is reported when the embedded server fails a health
+	// check after being healthy
+	HealthDegraded HealthState = "degraded"
+	// HealthRecovered is reported when the embedded server passes a health
+	// check after being degraded
+	HealthRecovered HealthState = "recovered"
+	// HealthRestarted is reported when an unresponsive embedded server has
+	// been restarted
+	HealthRestarted HealthState = "restarted"
+	// HealthRestartFailed is reported when an unresponsive embedded server
+	// couldn't be restarted
+	HealthRestartFailed HealthState = "restart-failed"
+)
+
+// HealthHandler is called by the health monitor when the health of the
+// embedded etcd server changes
+type HealthHandler func(state HealthState, err error)
+
+// HealthPolicy holds the interval and the thresholds of the health monitor.
+// Zero values use the defaults, and the interval in the config for Interval.
+type HealthPolicy struct {
+	// Interval is the interval between health checks
+	Interval time.Duration
+	// Timeout is the time given to the embedded server to respond to a
+	// health check
+	Timeout time.Duration
+	// UnresponsiveThreshold is the number of consecutive failed health
+	// checks after which the embedded server is considered unresponsive
+	UnresponsiveThreshold int
+}
+
+const (
+	// DefaultHealthCheckTimeout is the time given to the embedded server to
+	// respond to a health check
+	DefaultHealthCheckTimeout = 5 * time.Second
+	// DefaultUnresponsiveThreshold is the number of consecutive failed
+	// health checks after which the embedded server is considered
+	// unresponsive
+	DefaultUnresponsiveThreshold = 3
+
+	minRestartBackoff = 5 * time.Second
+	maxRestartBackoff = 5 * time.Minute
+)
+
+var errServerNoLeader = errors.New("embedded etcd server has no leader")
+
+// backoff is an exponential backoff between min and max
+type backoff struct {
+	min, max time.Duration
+	cur      time.Duration
+}
+
+// next returns the duration to wait before the next attempt, and doubles it
+// for the attempt after
+func (b *backoff) next() time.Duration {
+	if b.cur < b.min {
+		b.cur = b.min
+	}
+	d := b.cur
+	b.cur *= 2
+	if b.cur > b.max {
+		b.cur = b.max
+	}
+	return d
+}
+
+func (b *backoff) reset() {
+	b.cur = 0
+}
+
+// SetHealthHandler sets the handler called by the health monitor when the
+// health of the embedded etcd server changes. A nil handler clears it.
+func (ee *ElasticEtcd) SetHealthHandler(h HealthHandler) {
+	ee.healthLock.Lock()
+	defer ee.healthLock.Unlock()
+	ee.healthHandler = h
+}
+
+func (ee *ElasticEtcd) notifyHealth(state HealthState, err error) {
+	ee.healthLock.Lock()
+	h := ee.healthHandler
+	ee.healthLock.Unlock()
+
+	if h != nil {
+		h(state, err)
+	}
+}
+
+// SetHealthPolicy changes the policy of the health monitor. A running
+// monitor waits the new interval from when the policy is changed.
+func (ee *ElasticEtcd) SetHealthPolicy(p HealthPolicy) {
+	ee.healthLock.Lock()
+	ee.healthPolicy = p
+	ee.healthLock.Unlock()
+
+	select {
+	case ee.healthPolicyChanged <- struct{}{}:
+	default:
+	}
+}
+
+// currentHealthPolicy returns the policy of the health monitor, with the
+// defaults filled in
+func (ee *ElasticEtcd) currentHealthPolicy() HealthPolicy {
+	ee.healthLock.Lock()
+	p := ee.healthPolicy
+	ee.healthLock.Unlock()
+
+	if p.Interval <= 0 {
+		p.Interval = ee.conf.HealthInterval
+	}
+	if p.Interval <= 0 {
+		p.Interval = DefaultHealthInterval
+	}
+	if p.Timeout <= 0 {
+		p.Timeout = DefaultHealthCheckTimeout
+	}
+	if p.UnresponsiveThreshold <= 0 {
+		p.UnresponsiveThreshold = DefaultUnresponsiveThreshold
+	}
+	return p
+}
+
+// startHealthMonitor starts monitoring the health of the embedded etcd server
+// in the background, till stopHealthMonitor is called. The embedded server
+// may be started or stopped by nominations at any time, so it is checked only
+// when it is running.
+func (ee *ElasticEtcd) startHealthMonitor() {
+	ee.stopmonitor = make(chan struct{})
+	ee.monitor.Add(1)
+	go ee.monitorHealth()
+}
+
+// stopHealthMonitor stops the health monitor and waits for it to exit.
+// This must not be called with ee.lock held, as the monitor takes the lock
+// to restart the embedded server.
+func (ee *ElasticEtcd) stopHealthMonitor() {
+	if ee.stopmonitor == nil {
+		return
+	}
+	close(ee.stopmonitor)
+	ee.monitor.Wait()
+	ee.stopmonitor = nil
+}
+
+func (ee *ElasticEtcd) monitorHealth() {
+	defer ee.monitor.Done()
+
+	policy := ee.currentHealthPolicy()
+	timer := time.NewTimer(policy.Interval)
+	defer timer.Stop()
+
+	var (
+		failures    int
+		degraded    bool
+		nextRestart time.Time
+		restarts    = backoff{min: minRestartBackoff, max: maxRestartBackoff}
+	)
+
+	for {
+		select {
+		case <-ee.stopmonitor:
+			return
+		case <-ee.healthPolicyChanged:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			policy = ee.currentHealthPolicy()
+			timer.Reset(policy.Interval)
+			continue
+		case <-timer.C:
+		}
+
+		policy = ee.currentHealthPolicy()
+		timer.Reset(policy.Interval)
+
+		running, err := ee.checkServerHealth(policy.Timeout)
+		if !running {
+			failures, degraded = 0, false
+			continue
+		}
+
+		if err == nil {
+			if degraded {
+				ee.log.Info("embedded etcd server recovered")
+				ee.notifyHealth(HealthRecovered, nil)
+			}
+			failures, degraded = 0, false
+			restarts.reset()
+			continue
+		}
+
+		failures++
+		if !degraded {
+			ee.log.WithError(err).Warn("embedded etcd server is degraded")
+			ee.notifyHealth(HealthDegraded, err)
+			degraded = true
+		}
+
+		if !ee.conf.AutoRestart || failures < policy.UnresponsiveThreshold || time.Now().Before(nextRestart) {
+			continue
+		}
+
+		nextRestart = time.Now().Add(restarts.next())
+		ee.log.WithError(err).WithField("failures", failures).Warn("embedded etcd server is unresponsive, restarting it")
+		if rerr := ee.restartServer(); rerr != nil {
+			ee.log.WithError(rerr).Error("failed to restart embedded etcd server")
+			ee.notifyHealth(HealthRestartFailed, rerr)
+			continue
+		}
+		ee.log.Info("restarted embedded etcd server")
+		ee.notifyHealth(HealthRestarted, err)
+		failures = 0
+	}
+}
+
+// checkServerHealth checks if the embedded etcd server is running, and if it
+// is, that it responds to requests and is part of a cluster with a leader
+func (ee *ElasticEtcd) checkServerHealth(timeout time.Duration) (bool, error) {
+	ee.lock.RLock()
+	if ee.stopping || ee.server.srv == nil || ee.cli == nil {
+		ee.lock.RUnlock()
+		return false, nil
+	}
+	cli := ee.cli
+	endpoint := ee.server.srv.Config().ACUrls[0].String()
+	ee.lock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	resp, err := cli.Status(ctx, endpoint)
+	if err != nil {
+		return true, err
+	}
+	if resp.Leader == 0 {
+		return true, errServerNoLeader
+	}
+	return true, nil
+}
+
+// restartServer stops the embedded etcd server and starts it again from its
+// existing data. The server rejoins the etcd cluster using the membership
+// recorded in its data dir.
+func (ee *ElasticEtcd) restartServer() error {
+	ee.lock.Lock()
+	defer ee.lock.Unlock()
+
+	if ee.stopping || ee.server.srv == nil {
+		return nil
+	}
+
+	// Try starting the server again even if it didn't stop cleanly, as
+	// it is of no use as it is
+	if err := ee.stopServer(); err != nil {
+		ee.log.WithError(err).Warn("embedded etcd server did not stop cleanly")
+	}
+	return ee.startServer("")
+}
@@ -0,0 +1,42 @@
+package elasticetcd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	b := backoff{min: time.Second, max: 5 * time.Second}
+
+	assert.Equal(t, time.Second, b.next())
+	assert.Equal(t, 2*time.Second, b.next())
+	assert.Equal(t, 4*time.Second, b.next())
+	assert.Equal(t, 5*time.Second, b.next())
+	assert.Equal(t, 5*time.Second, b.next())
+
+	b.reset()
+	assert.Equal(t, time.Second, b.next())
+}
+
+func TestCurrentHealthPolicy(t *testing.T) {
+	ee := &ElasticEtcd{conf: &Config{HealthInterval: 20 * time.Second}}
+
+	assert.Equal(t, HealthPolicy{
+		Interval:              20 * time.Second,
+		Timeout:               DefaultHealthCheckTimeout,
+		UnresponsiveThreshold: DefaultUnresponsiveThreshold,
+	}, ee.currentHealthPolicy())
+
+	ee.healthPolicy = HealthPolicy{Interval: time.Minute, UnresponsiveThreshold: 5}
+	assert.Equal(t, HealthPolicy{
+		Interval:              time.Minute,
+		Timeout:               DefaultHealthCheckTimeout,
+		UnresponsiveThreshold: 5,
+	}, ee.currentHealthPolicy())
+
+	ee.conf.HealthInterval = 0
+	ee.healthPolicy = HealthPolicy{}
+	assert.Equal(t, DefaultHealthInterval, ee.currentHealthPolicy().Interval)
+}
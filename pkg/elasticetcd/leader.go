@@ -52,8 +52,12 @@ func (ee *ElasticEtcd) startCampaign() {
 }
 
 func (ee *ElasticEtcd) startLeader() error {
+	if err := ee.loadIdealSize(); err != nil {
+		return err
+	}
 	ee.watchVolunteers()
 	ee.watchIdealSize()
+	ee.watchPins()
 
 	return nil
 }
@@ -89,6 +93,76 @@ func (ee *ElasticEtcd) watchIdealSize() {
 	ee.watch(idealSizeKey, f)
 }
 
+// loadIdealSize makes the new leader use the ideal size saved in the store
+// by an earlier leader, or saves its own ideal size if none was saved, so
+// that the ideal size set for the cluster survives changes of leader
+func (ee *ElasticEtcd) loadIdealSize() error {
+	resp, err := ee.cli.Get(ee.cli.Ctx(), idealSizeKey)
+	if err != nil {
+		ee.log.WithError(err).Error("could not get idealsize")
+		return err
+	}
+
+	if resp.Count == 0 {
+		_, err = ee.cli.Put(ee.cli.Ctx(), idealSizeKey, strconv.Itoa(ee.conf.IdealSize))
+		if err != nil {
+			ee.log.WithError(err).Error("could not save idealsize")
+		}
+		return err
+	}
+
+	i, err := strconv.Atoi(string(resp.Kvs[0].Value))
+	if err != nil {
+		ee.log.WithError(err).Error("could not parse idealsize value, ignoring saved value")
+		return nil
+	}
+	ee.conf.IdealSize = i
+	return nil
+}
+
+// IdealSize returns the ideal size of the etcd cluster, which is the number of
+// voting members the elastic leader maintains. Instances beyond the ideal
+// size run only as etcd clients, proxying their requests to the members.
+func (ee *ElasticEtcd) IdealSize() (int, error) {
+	ee.lock.RLock()
+	defer ee.lock.RUnlock()
+
+	if ee.cli == nil {
+		return 0, ErrClientNotAvailable
+	}
+
+	resp, err := ee.cli.Get(ee.cli.Ctx(), idealSizeKey)
+	if err != nil {
+		return 0, err
+	}
+	if resp.Count == 0 {
+		return ee.conf.IdealSize, nil
+	}
+	return strconv.Atoi(string(resp.Kvs[0].Value))
+}
+
+// SetIdealSize sets the ideal size of the etcd cluster. The elastic leader
+// nominates more instances or removes nominations to reach the new size,
+// leaving the pinned roles as they are.
+func (ee *ElasticEtcd) SetIdealSize(size int) error {
+	if size < 1 {
+		return ErrInvalidIdealSize
+	}
+
+	ee.lock.RLock()
+	defer ee.lock.RUnlock()
+
+	if ee.cli == nil {
+		return ErrClientNotAvailable
+	}
+
+	_, err := ee.cli.Put(ee.cli.Ctx(), idealSizeKey, strconv.Itoa(size))
+	if err != nil {
+		ee.log.WithError(err).WithField("idealsize", size).Error("failed to set idealsize")
+	}
+	return err
+}
+
 func (ee *ElasticEtcd) doNominations() {
 	ee.lock.Lock()
 	defer ee.lock.Unlock()
@@ -141,18 +215,62 @@ func (ee *ElasticEtcd) doNominations() {
 		return
 	}
 
+	pins, err := ee.getPins()
+	if err != nil {
+		ee.log.WithError(err).Error("could not get pinned roles")
+		return
+	}
+
+	// Honour the pinned roles first. Instances pinned as proxies lose their
+	// nominations and instances pinned as voters are nominated, irrespective
+	// of the ideal size.
+	var demoted []string
+	for _, h := range nominees {
+		if pins[h] != RoleProxy {
+			continue
+		}
+		if h == ee.conf.Name {
+			ee.log.Warn("cannot remove own nomination as the leader, ignoring pinned proxy role")
+			continue
+		}
+		if err := ee.removeNomination(h); err != nil {
+			ee.log.WithError(err).WithField("host", h).Warn("could not remove nomination for host pinned as proxy")
+			continue
+		}
+		demoted = append(demoted, h)
+	}
+	nominees = diffStringSlices(nominees, demoted)
+
+	for _, h := range diffStringSlices(volunteers, nominees) {
+		if pins[h] != RoleVoter {
+			continue
+		}
+		if err := ee.nominate(h, volunteersMap[h]); err != nil {
+			ee.log.WithError(err).WithField("host", h).Error("failed to nominate host pinned as voter")
+			continue
+		}
+		ee.log.WithField("host", h).Debug("nominated host pinned as voter")
+		nominees = append(nominees, h)
+	}
+	nomineeCount = len(nominees)
+
 	switch {
 	// If idealSize is not met, nominate more servers till the size is met
 	case nomineeCount < ee.conf.IdealSize:
+		// Filter out already nominated servers and servers pinned as proxies
+		var available []string
+		for _, h := range diffStringSlices(volunteers, nominees) {
+			if pins[h] != RoleProxy {
+				available = append(available, h)
+			}
+		}
+
 		// You cannot do nominations if all volunteers have been nominated
-		if compareStringSlices(nominees, volunteers) {
+		if len(available) == 0 {
 			ee.log.Debug("all available volunteers have been nominated")
 			return
 		}
 
-		// Filter out already nominated servers
-		available := diffStringSlices(volunteers, nominees)
-
 		// Keep nominating in a round-robin fashion till the required nominations are done
 		for _, h := range available {
 			err := ee.nominate(h, volunteersMap[h])
@@ -171,8 +289,8 @@ func (ee *ElasticEtcd) doNominations() {
 	case nomineeCount > ee.conf.IdealSize:
 		// Remove nominations in a round-robin fashion till the required nominations are removed
 		for _, h := range nominees {
-			if h == ee.conf.Name {
-				// skip yourself
+			if h == ee.conf.Name || pins[h] == RoleVoter {
+				// skip yourself and hosts pinned as voters
 				continue
 			}
 			if err := ee.removeNomination(h); err != nil {
@@ -246,11 +364,20 @@ func (ee *ElasticEtcd) removeNomination(host string) error {
 		return err
 	}
 	var m *etcdserverpb.Member
-	for _, m = range memlist.Members {
-		if m.Name == host {
+	for _, mem := range memlist.Members {
+		if mem.Name == host {
+			m = mem
 			break
 		}
 	}
+	if m == nil {
+		// The host never joined the etcd cluster or was removed already
+		logger.Debug("host is not an etcd cluster member")
+		return nil
+	}
+	if len(memlist.Members) == 1 {
+		return ErrRemoveLastMember
+	}
 	_, err = ee.cli.MemberRemove(ee.cli.Ctx(), m.ID)
 	if err != nil {
 		logger.WithError(err).Error("failed to remove host as etcd cluster member")
@@ -0,0 +1,199 @@
+package elasticetcd
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
+)
+
+// pinsPrefix holds the roles pinned by the admin for ElasticEtcd instances.
+// The leader honours the pinned roles when doing nominations.
+const pinsPrefix = eePrefix + "/pins/"
+
+// Role is the role of an ElasticEtcd instance in the etcd cluster
+type Role string
+
+// Roles of ElasticEtcd instances.
+// The vendored etcd doesn't support learner members, so a nominated instance
+// is a voting member as soon as it is added to the etcd cluster. Till its
+// embedded server starts and joins the cluster, it is reported as joining.
+const (
+	// RoleVoter is an instance running an etcd server which is a voting
+	// member of the etcd cluster
+	RoleVoter Role = "voter"
+	// RoleJoining is an instance which has been nominated, but whose etcd
+	// server hasn't joined the etcd cluster yet
+	RoleJoining Role = "joining"
+	// RoleProxy is an instance which only connects to the etcd cluster as a
+	// client
+	RoleProxy Role = "proxy"
+)
+
+var (
+	// ErrInvalidRole is returned when a role other than RoleVoter or
+	// RoleProxy is pinned
+	ErrInvalidRole = errors.New("only the voter and proxy roles can be pinned")
+	// ErrLastVoter is returned when the last voter of the etcd cluster is
+	// pinned as a proxy
+	ErrLastVoter = errors.New("cannot demote the last voting member of the etcd cluster")
+)
+
+// MemberRole describes the role of an ElasticEtcd instance
+type MemberRole struct {
+	Name string
+	Role Role
+	// Pinned is the role pinned for the instance, empty if the role of the
+	// instance is decided by the elastic leader
+	Pinned Role
+	// Volunteer is true if the instance is running and has volunteered to
+	// be a server
+	Volunteer bool
+}
+
+// Roles returns the roles of the ElasticEtcd instances in the elastic cluster,
+// sorted by name
+func (ee *ElasticEtcd) Roles() ([]MemberRole, error) {
+	ee.lock.RLock()
+	defer ee.lock.RUnlock()
+
+	if ee.cli == nil {
+		return nil, ErrClientNotAvailable
+	}
+
+	volunteersResp, err := ee.cli.Get(ee.cli.Ctx(), volunteerPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	nomineesResp, err := ee.cli.Get(ee.cli.Ctx(), nomineePrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	memlist, err := ee.cli.MemberList(ee.cli.Ctx())
+	if err != nil {
+		return nil, err
+	}
+	pins, err := ee.getPins()
+	if err != nil {
+		return nil, err
+	}
+
+	return computeRoles(keysFromGetResp(volunteersResp, volunteerPrefix),
+		keysFromGetResp(nomineesResp, nomineePrefix), memlist.Members, pins), nil
+}
+
+// computeRoles prepares the roles of the instances from the volunteers,
+// nominees, etcd cluster members and pinned roles
+func computeRoles(volunteers, nominees []string, members []*etcdserverpb.Member, pins map[string]Role) []MemberRole {
+	roles := make(map[string]*MemberRole)
+	get := func(name string) *MemberRole {
+		r, ok := roles[name]
+		if !ok {
+			r = &MemberRole{Name: name, Role: RoleProxy, Pinned: pins[name]}
+			roles[name] = r
+		}
+		return r
+	}
+
+	for _, v := range volunteers {
+		get(v).Volunteer = true
+	}
+	for _, n := range nominees {
+		get(n).Role = RoleJoining
+	}
+	// Members which haven't started yet don't have a name
+	for _, m := range members {
+		if m.Name != "" {
+			get(m.Name).Role = RoleVoter
+		}
+	}
+
+	list := make([]MemberRole, 0, len(roles))
+	for _, r := range roles {
+		list = append(list, *r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list
+}
+
+func (ee *ElasticEtcd) getPins() (map[string]Role, error) {
+	resp, err := ee.cli.Get(ee.cli.Ctx(), pinsPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	pins := make(map[string]Role)
+	for _, kv := range resp.Kvs {
+		pins[string(kv.Key)[len(pinsPrefix):]] = Role(kv.Value)
+	}
+	return pins, nil
+}
+
+// PinRole pins the role of the named instance. An instance pinned as a voter
+// is always nominated, even beyond the ideal size of the etcd cluster, and an
+// instance pinned as a proxy is never nominated. The elastic leader applies
+// the change asynchronously.
+func (ee *ElasticEtcd) PinRole(name string, role Role) error {
+	if role != RoleVoter && role != RoleProxy {
+		return ErrInvalidRole
+	}
+
+	roles, err := ee.Roles()
+	if err != nil {
+		return err
+	}
+
+	if role == RoleProxy {
+		voters := 0
+		for _, r := range roles {
+			if r.Name != name && r.Role != RoleProxy && r.Pinned != RoleProxy {
+				voters++
+			}
+		}
+		if voters == 0 {
+			return ErrLastVoter
+		}
+	}
+
+	ee.lock.RLock()
+	defer ee.lock.RUnlock()
+	if ee.cli == nil {
+		return ErrClientNotAvailable
+	}
+
+	_, err = ee.cli.Put(ee.cli.Ctx(), pinsPrefix+name, string(role))
+	if err != nil {
+		ee.log.WithError(err).WithField("host", name).Error("failed to pin role")
+	}
+	return err
+}
+
+// UnpinRole removes the pinned role of the named instance, leaving the role to
+// be decided by the elastic leader
+func (ee *ElasticEtcd) UnpinRole(name string) error {
+	ee.lock.RLock()
+	defer ee.lock.RUnlock()
+
+	if ee.cli == nil {
+		return ErrClientNotAvailable
+	}
+
+	_, err := ee.cli.Delete(ee.cli.Ctx(), pinsPrefix+name)
+	if err != nil {
+		ee.log.WithError(err).WithField("host", name).Error("failed to unpin role")
+	}
+	return err
+}
+
+func (ee *ElasticEtcd) watchPins() {
+	ee.log.Debug("watching for changes to pinned roles")
+
+	f := func(_ clientv3.WatchResponse) {
+		ee.log.Debug("pinned roles changed, doing nominations again")
+		ee.doNominations()
+	}
+
+	ee.watch(pinsPrefix, f, clientv3.WithPrefix())
+}
@@ -0,0 +1,31 @@
+package elasticetcd
+
+import (
+	"testing"
+
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeRoles(t *testing.T) {
+	volunteers := []string{"a", "b", "c", "d"}
+	nominees := []string{"a", "b", "c"}
+	members := []*etcdserverpb.Member{
+		{ID: 1, Name: "a"},
+		{ID: 2, Name: "b"},
+		// c has been added as a member, but its server hasn't started
+		{ID: 3},
+		// e is down, but still a member
+		{ID: 4, Name: "e"},
+	}
+	pins := map[string]Role{"d": RoleProxy, "e": RoleVoter}
+
+	roles := computeRoles(volunteers, nominees, members, pins)
+	assert.Equal(t, []MemberRole{
+		{Name: "a", Role: RoleVoter, Volunteer: true},
+		{Name: "b", Role: RoleVoter, Volunteer: true},
+		{Name: "c", Role: RoleJoining, Volunteer: true},
+		{Name: "d", Role: RoleProxy, Pinned: RoleProxy, Volunteer: true},
+		{Name: "e", Role: RoleVoter, Pinned: RoleVoter},
+	}, roles)
+}
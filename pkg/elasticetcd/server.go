@@ -1,6 +1,7 @@
 package elasticetcd
 
 import (
+	"context"
 	"errors"
 	"io"
 	"os"
@@ -64,6 +65,7 @@ func (ee *ElasticEtcd) startServer(initialCluster string) error {
 	case <-etcd.Server.ReadyNotify():
 		ee.log.Debug("embedded server ready")
 		ee.server.srv = etcd
+		go ee.watchServerErrors(etcd)
 		return nil
 	case <-time.After(42 * time.Second):
 		ee.log.Debug("timedout trying to start embedded server")
@@ -74,17 +76,83 @@ func (ee *ElasticEtcd) startServer(initialCluster string) error {
 	}
 }
 
-// stopServer stops the embedded etcd server.
+// watchServerErrors logs the errors returned by the embedded etcd server
+// after it has started, till the server is stopped. The errors need to be
+// received for the server to continue serving.
+func (ee *ElasticEtcd) watchServerErrors(etcd *embed.Etcd) {
+	for {
+		select {
+		case err, ok := <-etcd.Err():
+			if !ok {
+				return
+			}
+			ee.log.WithError(err).Error("embedded etcd server error")
+		case <-etcd.Server.StopNotify():
+			return
+		}
+	}
+}
+
+// stopServer stops the embedded etcd server, waiting for it to stop
+// gracefully for the configured stop timeout.
 // Ensure this is only called with ee.lock held
 func (ee *ElasticEtcd) stopServer() error {
+	ctx, cancel := context.WithTimeout(context.Background(), ee.stopTimeout())
+	defer cancel()
+	return ee.stopServerGraceful(ctx)
+}
+
+// stopServerGraceful stops the embedded etcd server gracefully, letting it
+// transfer leadership and flush its WAL. If the server doesn't stop before the
+// context is done, it is stopped forcibly, as a last resort.
+// Ensure this is only called with ee.lock held
+func (ee *ElasticEtcd) stopServerGraceful(ctx context.Context) error {
 	if ee.server.srv == nil {
 		return errors.New("etcd server not running")
 	}
-	ee.server.srv.Close()
+	etcd := ee.server.srv
 	ee.server.srv = nil
-	ee.server.logFile.Close()
+	defer ee.server.logFile.Close()
+
+	done := make(chan struct{})
+	go func() {
+		etcd.Close()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		ee.log.Warn("embedded etcd server did not stop in time, stopping forcibly")
+		etcd.Server.HardStop()
+		err = ErrServerStopTimeout
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			ee.log.Error("embedded etcd server listeners did not close after forced stop")
+		}
+	}
+
+	// The server is stopped cleanly only once its run loop has exited
+	select {
+	case <-etcd.Server.StopNotify():
+		ee.log.Debug("embedded etcd server stopped")
+	default:
+		ee.log.Error("embedded etcd server did not stop")
+		if err == nil {
+			err = errors.New("etcd server did not stop")
+		}
+	}
 
-	return nil
+	return err
+}
+
+func (ee *ElasticEtcd) stopTimeout() time.Duration {
+	if ee.conf.StopTimeout <= 0 {
+		return DefaultStopTimeout
+	}
+	return ee.conf.StopTimeout
 }
 
 // newEmbedConfig returns a filled embed.Config based on the ElasticEtcd Config and the passed InitialCluster.
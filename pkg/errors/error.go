@@ -72,4 +72,37 @@ var (
 	ErrReservedGroupProfile            = errors.New("reserved group profile")
 	ErrInvalidIntValue                 = errors.New("error parsing the value. Make sure the value is a valid integer")
 	ErrConnectingHost                  = errors.New("could not connect to host. Make sure host address is valid, network connection is active and gd2 is up and running")
+	ErrOptionsHistoryNotFound          = errors.New("volume options history entry not found")
+	ErrNotEnoughDeviceSpace            = errors.New("space not sufficient on device")
+	ErrAutoExpandPolicyNotFound        = errors.New("volume auto expansion policy not found")
+	ErrUsageProtectPolicyNotFound      = errors.New("volume usage protection policy not found")
+	ErrRevisionCompacted               = errors.New("requested revision has been compacted")
+	ErrSubdirExportNotFound            = errors.New("subdirectory is not exported")
+	ErrSubdirExportExists              = errors.New("subdirectory is already exported")
+	ErrVolNameReserved                 = errors.New("volume name is reserved by another volume create in progress")
+	ErrIOThrottlePolicyNotFound        = errors.New("volume I/O throttling policy not found")
+	ErrBrickWipeJobNotFound            = errors.New("brick wipe job not found")
+	ErrBrickWipeJobRunning             = errors.New("brick wipe job has not finished")
+	ErrVolinfoConflict                 = errors.New("volume was modified concurrently, retry the operation")
+	ErrDeletedVolNotFound              = errors.New("deleted volume not found in the trash")
+	ErrBrickNotFound                   = errors.New("brick not found")
+	ErrVolProfileNotFound              = errors.New("volume profile not found")
+	ErrVolProfileExists                = errors.New("volume profile already exists")
+	ErrQuotaAlreadyEnabled             = errors.New("quota is already enabled")
+	ErrQuotaNotEnabled                 = errors.New("quota is not enabled")
+	ErrQuotaLimitNotFound              = errors.New("quota limit is not set on the directory")
+	ErrQuotaPathNotDir                 = errors.New("quota limits can only be set on directories")
+	ErrVolRevisionNotFound             = errors.New("no volume revision recorded at the requested time")
+	ErrFutureRevision                  = errors.New("requested revision is newer than the current revision")
+	ErrBitrotObjectNotCorrupted        = errors.New("object is not marked corrupted on any brick")
+	ErrBitrotNoGoodCopy                = errors.New("no good copy of the object is available to repair from")
+	ErrIOOpConflict                    = errors.New("a conflicting IO intensive operation is running on the bricks of the volume")
+	ErrIOOpNotQueued                   = errors.New("operation is not queued on the volume")
+	ErrPeerEvacuationNotFound          = errors.New("peer evacuation not found")
+	ErrPeerEvacuationExists            = errors.New("peer evacuation is already in progress")
+	ErrPeerEvacuationNotRunning        = errors.New("peer evacuation is not running")
+	ErrPeerEvacuationNotResumable      = errors.New("only a paused or failed peer evacuation can be resumed")
+	ErrTxnNotFound                     = errors.New("transaction not found")
+	ErrAsyncJobNotFound                = errors.New("job not found")
+	ErrUserNotFound                    = errors.New("user not found")
 )
@@ -19,10 +19,11 @@ func (c *Client) BitrotDisable(volname string) error {
 	return c.post(url, nil, http.StatusOK, nil)
 }
 
-// BitrotScrubOndemand starts bitrot scrubber on demand for a volume
+// BitrotScrubOndemand starts bitrot scrubber on demand for a volume.
+// ErrIOOpQueued is returned if the scrub has been queued.
 func (c *Client) BitrotScrubOndemand(volname string) error {
 	url := fmt.Sprintf("/v1/volumes/%s/bitrot/scrubondemand", volname)
-	return c.post(url, nil, http.StatusOK, nil)
+	return c.queued(c.post(url, nil, http.StatusOK, nil))
 }
 
 // BitrotScrubStatus returns bitrot scrub status of a volume
@@ -32,3 +33,38 @@ func (c *Client) BitrotScrubStatus(volname string) (bitrotapi.ScrubStatus, error
 	err := c.get(url, nil, http.StatusOK, &scrubStatus)
 	return scrubStatus, err
 }
+
+// BitrotScrubConfig sets the scrub frequency and/or throttle of a volume
+func (c *Client) BitrotScrubConfig(volname string, req bitrotapi.ScrubConfigReq) error {
+	url := fmt.Sprintf("/v1/volumes/%s/bitrot/scrubconfig", volname)
+	return c.post(url, req, http.StatusOK, nil)
+}
+
+// BitrotScrubPause pauses the bitrot scrubber of a volume
+func (c *Client) BitrotScrubPause(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/bitrot/scrubpause", volname)
+	return c.post(url, nil, http.StatusOK, nil)
+}
+
+// BitrotScrubResume resumes the bitrot scrubber of a volume
+func (c *Client) BitrotScrubResume(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/bitrot/scrubresume", volname)
+	return c.post(url, nil, http.StatusOK, nil)
+}
+
+// BitrotCorrupted returns the objects of a volume found corrupted by the
+// scrubbers
+func (c *Client) BitrotCorrupted(volname string) (bitrotapi.CorruptedObjects, error) {
+	var objects bitrotapi.CorruptedObjects
+	url := fmt.Sprintf("/v1/volumes/%s/bitrot/corrupted", volname)
+	err := c.get(url, nil, http.StatusOK, &objects)
+	return objects, err
+}
+
+// BitrotRepair replaces the corrupted copies of an object with a good copy
+func (c *Client) BitrotRepair(volname, gfid string) (bitrotapi.RepairResp, error) {
+	var resp bitrotapi.RepairResp
+	url := fmt.Sprintf("/v1/volumes/%s/bitrot/repair/%s", volname, gfid)
+	err := c.post(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
@@ -0,0 +1,89 @@
+package restclient
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// ClusterBackup returns a backup of the cluster configuration
+func (c *Client) ClusterBackup() (api.ClusterBackup, error) {
+	var resp api.ClusterBackup
+	err := c.post("/v1/cluster/backup", api.ClusterBackupReq{}, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ClusterBackupSave saves a backup of the cluster configuration on the peer
+func (c *Client) ClusterBackupSave() (api.ClusterBackupResp, error) {
+	var resp api.ClusterBackupResp
+	err := c.post("/v1/cluster/backup", api.ClusterBackupReq{Save: true}, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// ClusterRestore restores the cluster configuration from a backup
+func (c *Client) ClusterRestore(req api.ClusterRestoreReq) (api.ClusterRestoreResp, error) {
+	var resp api.ClusterRestoreResp
+	err := c.post("/v1/cluster/restore", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// HealthPolicy returns the policy of the health checks run by the peers
+func (c *Client) HealthPolicy() (api.HealthPolicy, error) {
+	var resp api.HealthPolicy
+	err := c.get("/v1/cluster/health-policy", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// HealthPolicySet sets the policy of the health checks run by the peers
+func (c *Client) HealthPolicySet(req api.HealthPolicy) (api.HealthPolicy, error) {
+	var resp api.HealthPolicy
+	err := c.post("/v1/cluster/health-policy", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// Ops lists the transactions in progress in the cluster
+func (c *Client) Ops() (api.OpListResp, error) {
+	var resp api.OpListResp
+	err := c.get("/v1/ops", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// Transactions lists the transactions in progress and the recently completed
+// ones. state, if not empty, filters them by state.
+func (c *Client) Transactions(state string) (api.TxnListResp, error) {
+	var resp api.TxnListResp
+	url := "/v1/transactions"
+	if state != "" {
+		url += "?state=" + state
+	}
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// Transaction returns a transaction in progress or recently completed
+func (c *Client) Transaction(txnID string) (api.Txn, error) {
+	var resp api.Txn
+	err := c.get("/v1/transactions/"+txnID, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// AuditRecords returns the audit records saved in the store since the given
+// time, of the given user if not empty
+func (c *Client) AuditRecords(since time.Time, user string) (api.AuditListResp, error) {
+	var resp api.AuditListResp
+	query := url.Values{}
+	if !since.IsZero() {
+		query.Set("since", since.Format(time.RFC3339))
+	}
+	if user != "" {
+		query.Set("user", user)
+	}
+	u := "/v1/audit"
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+	err := c.get(u, nil, http.StatusOK, &resp)
+	return resp, err
+}
@@ -96,6 +96,8 @@ type Client struct {
 	timeout     time.Duration
 	httpClient  *http.Client
 	lastRespErr *http.Response
+	endpoints   *endpointSet
+	retry       RetryPolicy
 }
 
 // NewClientWithOpts initializes a default Glusterd2 REST Client.
@@ -190,12 +192,7 @@ func (c *Client) del(url string, data interface{}, expectStatusCode int, output
 }
 
 func (c *Client) do(method string, url string, input interface{}, expectStatusCode int, output interface{}) error {
-	req, err := c.buildRequest(method, url, input)
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(method, url, input)
 	if err != nil {
 		return err
 	}
@@ -224,7 +221,11 @@ func (c *Client) do(method string, url string, input interface{}, expectStatusCo
 }
 
 func (c *Client) buildRequest(method string, url string, input interface{}) (*http.Request, error) {
-	url = fmt.Sprintf("%s%s", c.baseURL, url)
+	return c.buildRequestTo(c.baseURL, method, url, input)
+}
+
+func (c *Client) buildRequestTo(baseURL string, method string, url string, input interface{}) (*http.Request, error) {
+	url = fmt.Sprintf("%s%s", baseURL, url)
 	var body io.Reader
 	if input != nil {
 		reqBody, err := json.Marshal(input)
@@ -254,6 +255,13 @@ func (c *Client) Ping() error {
 	return c.get("/ping", nil, http.StatusOK, nil)
 }
 
+// ListEndpoints returns the REST endpoints served by glusterd2
+func (c *Client) ListEndpoints() (api.ListEndpointsResp, error) {
+	var resp api.ListEndpointsResp
+	err := c.get("/endpoints", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
 //Version returns the glusterd2 version
 func (c *Client) Version() (api.VersionResp, error) {
 	var resp api.VersionResp
@@ -0,0 +1,32 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// FailPointArm arms a failure injection point. glusterd2 must be running in
+// developer mode for this to work.
+func (c *Client) FailPointArm(req api.FailPoint) error {
+	return c.post("/v1/debug/failpoints", req, http.StatusCreated, nil)
+}
+
+// FailPointDisarm disarms the named failure injection point
+func (c *Client) FailPointDisarm(name string) error {
+	return c.del("/v1/debug/failpoints/"+name, nil, http.StatusNoContent, nil)
+}
+
+// FailPoints returns the list of armed failure injection points
+func (c *Client) FailPoints() (api.FailPointListResp, error) {
+	var resp api.FailPointListResp
+	err := c.get("/v1/debug/failpoints", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// MsgBusSubscriptions lists the message bus subscriptions of the peer
+func (c *Client) MsgBusSubscriptions() (api.MsgBusSubscriptionsResp, error) {
+	var resp api.MsgBusSubscriptionsResp
+	err := c.get("/v1/debug/msgbus/subscriptions", nil, http.StatusOK, &resp)
+	return resp, err
+}
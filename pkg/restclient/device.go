@@ -14,7 +14,7 @@ func (c *Client) DeviceAdd(peerid, device string) (deviceapi.AddDeviceResp, erro
 	req := deviceapi.AddDeviceReq{
 		Device: device,
 	}
-	err := c.post("/v1/devices/"+peerid, req, http.StatusCreated, &deviceinfo)
+	err := c.post("/v1/peers/"+peerid+"/devices", req, http.StatusCreated, &deviceinfo)
 	return deviceinfo, err
 }
 
@@ -23,9 +23,10 @@ func (c *Client) DeviceList(peerid, device string) ([]deviceapi.Info, error) {
 	var deviceList deviceapi.ListDeviceResp
 	url := "/v1/devices"
 	if peerid != "" {
-		url = fmt.Sprintf("%s/%s", url, peerid)
 		if device != "" {
-			url = fmt.Sprintf("%s/%s", url, strings.TrimPrefix(device, "/"))
+			url = fmt.Sprintf("%s/%s/%s", url, peerid, strings.TrimPrefix(device, "/"))
+		} else {
+			url = fmt.Sprintf("/v1/peers/%s/devices", peerid)
 		}
 	}
 
@@ -42,6 +42,10 @@ func newHTTPErrorResponse(resp *http.Response) error {
 			buffer.WriteString(fmt.Sprintf(
 				"Transaction step %s failed on peer %s with error: %s\n",
 				apiErr.Fields["step"], apiErr.Fields["peer-id"], apiErr.Fields["error"]))
+		case api.ErrCodePreflightFailed:
+			buffer.WriteString(fmt.Sprintf(
+				"Preflight check %s failed on peer %s: %s\n",
+				apiErr.Fields["check"], apiErr.Fields["peer-id"], apiErr.Message))
 		default:
 			buffer.WriteString(apiErr.Message)
 		}
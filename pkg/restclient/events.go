@@ -49,3 +49,23 @@ func (c *Client) WebhookTest(url string, token string, secret string) error {
 	}
 	return c.post("/v1/events/webhook/test", req, http.StatusOK, nil)
 }
+
+// SinkAdd configures a notification sink to which Gluster Events are sent
+func (c *Client) SinkAdd(sink eventsapi.Sink) error {
+	return c.post("/v1/events/sinks", sink, http.StatusOK, nil)
+}
+
+// SinkDelete deletes the notification sink
+func (c *Client) SinkDelete(name string) error {
+	req := &eventsapi.SinkDel{
+		Name: name,
+	}
+	return c.del("/v1/events/sinks", req, http.StatusNoContent, nil)
+}
+
+// Sinks returns the list of notification sinks
+func (c *Client) Sinks() (eventsapi.SinkList, error) {
+	var resp eventsapi.SinkList
+	err := c.get("/v1/events/sinks", nil, http.StatusOK, &resp)
+	return resp, err
+}
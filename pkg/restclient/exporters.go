@@ -0,0 +1,28 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// ExporterSet adds or updates an exporter of the cluster events and the
+// audit log
+func (c *Client) ExporterSet(req api.Exporter) (api.Exporter, error) {
+	var resp api.Exporter
+	err := c.post("/v1/exporters", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// Exporters returns the list of exporters, along with their statistics on the
+// peer serving the request
+func (c *Client) Exporters() (api.ExporterListResp, error) {
+	var resp api.ExporterListResp
+	err := c.get("/v1/exporters", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ExporterDelete deletes the exporter
+func (c *Client) ExporterDelete(name string) error {
+	return c.del("/v1/exporters/"+name, nil, http.StatusNoContent, nil)
+}
@@ -0,0 +1,204 @@
+package restclient
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetryBackoff    = 500 * time.Millisecond
+	defaultMaxRetryBackoff = 5 * time.Second
+	// endpointCooldown is the time for which an endpoint which failed is
+	// skipped, in favour of the other endpoints
+	endpointCooldown = 30 * time.Second
+)
+
+// RetryPolicy describes how many times a request is attempted before giving
+// up, and how long to wait between the attempts. The wait doubles after every
+// attempt, upto MaxBackoff.
+type RetryPolicy struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// WithEndpoints sets the glusterd2 endpoints which the Client fails over
+// between. Requests are sent to the first healthy endpoint, and are retried on
+// the other endpoints when an endpoint is unreachable or unavailable. Unless
+// changed with WithRetry, each request is attempted once on every endpoint.
+func WithEndpoints(urls ...string) ClientFunc {
+	return func(client *Client) error {
+		if len(urls) == 0 {
+			return nil
+		}
+		client.endpoints = newEndpointSet(urls)
+		client.baseURL = urls[0]
+		if client.retry.Attempts == 0 {
+			client.retry.Attempts = len(urls)
+		}
+		return nil
+	}
+}
+
+// WithRetry sets the retry budget of the requests sent by the Client
+func WithRetry(policy RetryPolicy) ClientFunc {
+	return func(client *Client) error {
+		client.retry = policy
+		return nil
+	}
+}
+
+type endpoint struct {
+	url       string
+	downUntil time.Time
+}
+
+// endpointSet tracks the health of the endpoints of a Client
+type endpointSet struct {
+	sync.Mutex
+	list   []*endpoint
+	active int
+}
+
+func newEndpointSet(urls []string) *endpointSet {
+	s := &endpointSet{}
+	for _, u := range urls {
+		s.list = append(s.list, &endpoint{url: u})
+	}
+	return s
+}
+
+// pick returns the index and URL of the endpoint to send the next request
+// to. Endpoints not yet tried for the request are preferred, healthy ones
+// first, starting from the endpoint which last succeeded.
+func (s *endpointSet) pick(tried map[int]bool) (int, string) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	fallback := -1
+	for n := 0; n < len(s.list); n++ {
+		i := (s.active + n) % len(s.list)
+		if tried[i] {
+			continue
+		}
+		if now.After(s.list[i].downUntil) {
+			return i, s.list[i].url
+		}
+		if fallback == -1 {
+			fallback = i
+		}
+	}
+	if fallback == -1 {
+		// Every endpoint has been tried, start over
+		fallback = s.active
+	}
+	return fallback, s.list[fallback].url
+}
+
+func (s *endpointSet) markDown(i int) {
+	s.Lock()
+	defer s.Unlock()
+	s.list[i].downUntil = time.Now().Add(endpointCooldown)
+}
+
+func (s *endpointSet) markUp(i int) {
+	s.Lock()
+	defer s.Unlock()
+	s.list[i].downUntil = time.Time{}
+	s.active = i
+}
+
+// isDialError returns true if the request could not be sent as the connection
+// to the endpoint could not be established
+func isDialError(err error) bool {
+	if uerr, ok := err.(*url.Error); ok {
+		err = uerr.Err
+	}
+	operr, ok := err.(*net.OpError)
+	return ok && operr.Op == "dial"
+}
+
+// shouldRetry returns true if the request should be retried on another
+// endpoint. Requests which may modify the cluster are retried only if they
+// could not have been processed by the endpoint.
+func shouldRetry(method string, resp *http.Response, err error) bool {
+	idempotent := method == http.MethodGet || method == http.MethodHead
+	if err != nil {
+		return idempotent || isDialError(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// send sends the request to the endpoints of the Client as allowed by its
+// retry budget, and returns the response of the last attempt
+func (c *Client) send(method string, url string, input interface{}) (*http.Response, error) {
+	if c.endpoints == nil {
+		req, err := c.buildRequest(method, url, input)
+		if err != nil {
+			return nil, err
+		}
+		return c.httpClient.Do(req)
+	}
+
+	var (
+		resp    *http.Response
+		err     error
+		backoff = c.retry.Backoff
+		tried   = make(map[int]bool)
+	)
+	if backoff == 0 {
+		backoff = defaultRetryBackoff
+	}
+	maxBackoff := c.retry.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = defaultMaxRetryBackoff
+	}
+	attempts := c.retry.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+		if len(tried) == len(c.endpoints.list) {
+			tried = make(map[int]bool)
+		}
+
+		idx, base := c.endpoints.pick(tried)
+		req, rerr := c.buildRequestTo(base, method, url, input)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		resp, err = c.httpClient.Do(req)
+		if !shouldRetry(method, resp, err) {
+			c.endpoints.markUp(idx)
+			return resp, err
+		}
+
+		c.endpoints.markDown(idx)
+		tried[idx] = true
+		if err == nil && attempt < attempts-1 {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}
+	return resp, err
+}
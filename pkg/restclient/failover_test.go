@@ -0,0 +1,73 @@
+package restclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendFailover(t *testing.T) {
+	r := require.New(t)
+
+	var hits int
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	downURL := down.URL
+	down.Close()
+
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+
+	client, err := NewClientWithOpts(
+		WithHTTPClient(&http.Client{}),
+		WithEndpoints(downURL, unavailable.URL, up.URL),
+		WithRetry(RetryPolicy{Attempts: 3, Backoff: time.Millisecond}),
+	)
+	r.Nil(err)
+
+	// Mutating requests are retried when the endpoint is unreachable or
+	// unavailable
+	resp, err := client.send("POST", "/v1/volumes", nil)
+	r.Nil(err)
+	r.Equal(http.StatusOK, resp.StatusCode)
+	r.Equal(1, hits)
+
+	// The endpoint which succeeded is used for the following requests
+	resp, err = client.send("GET", "/v1/volumes", nil)
+	r.Nil(err)
+	r.Equal(http.StatusOK, resp.StatusCode)
+	r.Equal(2, hits)
+	r.Equal(2, client.endpoints.active)
+
+	// The retry budget is honoured
+	client, err = NewClientWithOpts(
+		WithHTTPClient(&http.Client{}),
+		WithRetry(RetryPolicy{Attempts: 1}),
+		WithEndpoints(downURL, up.URL),
+	)
+	r.Nil(err)
+	_, err = client.send("GET", "/v1/volumes", nil)
+	r.NotNil(err)
+}
+
+func TestShouldRetry(t *testing.T) {
+	r := require.New(t)
+
+	resp := func(code int) *http.Response { return &http.Response{StatusCode: code} }
+
+	r.True(shouldRetry("POST", resp(http.StatusServiceUnavailable), nil))
+	r.True(shouldRetry("GET", resp(http.StatusBadGateway), nil))
+	r.False(shouldRetry("POST", resp(http.StatusBadGateway), nil))
+	r.False(shouldRetry("GET", resp(http.StatusNotFound), nil))
+	r.False(shouldRetry("DELETE", resp(http.StatusOK), nil))
+}
@@ -0,0 +1,40 @@
+package restclient
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// FederatedClusterAdd registers or updates another cluster in the federated
+// view
+func (c *Client) FederatedClusterAdd(req api.FederatedCluster) (api.FederatedCluster, error) {
+	var resp api.FederatedCluster
+	err := c.post("/v1/federation/clusters", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// FederatedClusters returns the clusters registered in the federated view
+func (c *Client) FederatedClusters() (api.FederatedClusterListResp, error) {
+	var resp api.FederatedClusterListResp
+	err := c.get("/v1/federation/clusters", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// FederatedClusterDelete removes a cluster from the federated view
+func (c *Client) FederatedClusterDelete(name string) error {
+	return c.del("/v1/federation/clusters/"+name, nil, http.StatusNoContent, nil)
+}
+
+// FederatedVolumes returns the volumes and their health across the
+// registered clusters, or across the given clusters only
+func (c *Client) FederatedVolumes(clusters ...string) (api.FederatedVolumesResp, error) {
+	var resp api.FederatedVolumesResp
+	u := "/v1/federation/volumes"
+	if len(clusters) > 0 {
+		u += "?" + url.Values{"cluster": clusters}.Encode()
+	}
+	err := c.get(u, nil, http.StatusOK, &resp)
+	return resp, err
+}
@@ -51,6 +51,34 @@ func (c *Client) GeorepStop(mastervolid string, slavevolid string, force bool) (
 	return session, err
 }
 
+// GeorepCheckpointSet sets a checkpoint for a Geo-replication session, at
+// the given time in seconds since the epoch, or at the current time if 0
+func (c *Client) GeorepCheckpointSet(mastervolid string, slavevolid string, checkpoint int64) (georepapi.GeorepCheckpointStatus, error) {
+	var status georepapi.GeorepCheckpointStatus
+	req := georepapi.GeorepCheckpointReq{Time: checkpoint}
+	url := fmt.Sprintf("/v1/geo-replication/%s/%s/checkpoint", mastervolid, slavevolid)
+	err := c.post(url, &req, http.StatusOK, &status)
+	return status, err
+}
+
+// GeorepCheckpointStatus gets the completion status of the checkpoint of a
+// Geo-replication session
+func (c *Client) GeorepCheckpointStatus(mastervolid string, slavevolid string) (georepapi.GeorepCheckpointStatus, error) {
+	var status georepapi.GeorepCheckpointStatus
+	url := fmt.Sprintf("/v1/geo-replication/%s/%s/checkpoint", mastervolid, slavevolid)
+	err := c.get(url, nil, http.StatusOK, &status)
+	return status, err
+}
+
+// GeorepResync syncs all the data of the master volume of a Geo-replication
+// session again
+func (c *Client) GeorepResync(mastervolid string, slavevolid string) (georepapi.GeorepSession, error) {
+	var session georepapi.GeorepSession
+	url := fmt.Sprintf("/v1/geo-replication/%s/%s/resync", mastervolid, slavevolid)
+	err := c.post(url, nil, http.StatusOK, &session)
+	return session, err
+}
+
 // GeorepDelete deletes Geo-replication session
 func (c *Client) GeorepDelete(mastervolid string, slavevolid string, force bool) error {
 	opts := georepapi.GeorepCommandsReq{Force: force}
@@ -0,0 +1,28 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+
+	gfproxyapi "github.com/gluster/glusterd2/plugins/gfproxy/api"
+)
+
+// GfproxyEnable enables gfproxy for a volume
+func (c *Client) GfproxyEnable(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/gfproxy/enable", volname)
+	return c.post(url, nil, http.StatusOK, nil)
+}
+
+// GfproxyDisable disables gfproxy for a volume
+func (c *Client) GfproxyDisable(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/gfproxy/disable", volname)
+	return c.post(url, nil, http.StatusOK, nil)
+}
+
+// GfproxyStatus returns the status of the gfproxy daemons of a volume
+func (c *Client) GfproxyStatus(volname string) (gfproxyapi.GfproxyStatusResp, error) {
+	var status gfproxyapi.GfproxyStatusResp
+	url := fmt.Sprintf("/v1/volumes/%s/gfproxy", volname)
+	err := c.get(url, nil, http.StatusOK, &status)
+	return status, err
+}
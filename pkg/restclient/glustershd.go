@@ -36,7 +36,23 @@ func (c *Client) SelfHeal(volname string, healType string) error {
 		return errors.New("invalid parameters")
 	}
 
-	return c.post(url, nil, http.StatusOK, nil)
+	return c.queued(c.post(url, nil, http.StatusOK, nil))
+}
+
+// SelfHealFull sends request to start a full heal on the specified volname.
+// ErrIOOpQueued is returned if the full heal has been queued.
+func (c *Client) SelfHealFull(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/heal/full", volname)
+	return c.queued(c.post(url, nil, http.StatusOK, nil))
+}
+
+// SelfHealCount sends request to get the number of entries pending heal on
+// each brick of the volume
+func (c *Client) SelfHealCount(volname string) (shdapi.HealCountResp, error) {
+	var resp shdapi.HealCountResp
+	url := fmt.Sprintf("/v1/volumes/%s/heal-info?count=true", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
 }
 
 // SelfHealSplitBrain sends request to start split-brain operations on a volume
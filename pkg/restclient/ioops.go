@@ -0,0 +1,37 @@
+package restclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// ErrIOOpQueued is returned when the requested scrub, rebalance or full heal
+// has been queued, to be started once the conflicting operations on the
+// bricks of the volume complete
+var ErrIOOpQueued = errors.New("operation queued until the conflicting operations on its bricks complete")
+
+// queued returns ErrIOOpQueued if the request failed as it was accepted to
+// be queued instead
+func (c *Client) queued(err error) error {
+	if err != nil && c.lastRespErr != nil && c.lastRespErr.StatusCode == http.StatusAccepted {
+		return ErrIOOpQueued
+	}
+	return err
+}
+
+// IOOps returns the running, throttled and queued scrub, rebalance and full
+// heal operations
+func (c *Client) IOOps() (api.IOOpListResp, error) {
+	var resp api.IOOpListResp
+	err := c.get("/v1/io-ops", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// IOOpCancel removes the queued operation on the volume from the queue
+func (c *Client) IOOpCancel(op, volname string) error {
+	url := fmt.Sprintf("/v1/io-ops/%s/%s", op, volname)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
@@ -0,0 +1,21 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// AsyncJobs returns the operations run as async jobs
+func (c *Client) AsyncJobs() (api.AsyncJobListResp, error) {
+	var resp api.AsyncJobListResp
+	err := c.get("/v1/jobs", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// AsyncJob returns the state of an async job, and its result once finished
+func (c *Client) AsyncJob(jobID string) (api.AsyncJob, error) {
+	var resp api.AsyncJob
+	err := c.get("/v1/jobs/"+jobID, nil, http.StatusOK, &resp)
+	return resp, err
+}
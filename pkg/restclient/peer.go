@@ -3,6 +3,7 @@ package restclient
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/gluster/glusterd2/pkg/api"
 )
@@ -14,12 +15,34 @@ func (c *Client) PeerAdd(peerAddReq api.PeerAddReq) (api.PeerAddResp, error) {
 	return resp, err
 }
 
+// PeerPreflight runs the preflight checks for adding a peer, without adding
+// it
+func (c *Client) PeerPreflight(req api.PeerPreflightReq) (api.PeerPreflightReport, error) {
+	var report api.PeerPreflightReport
+	err := c.post("/v1/peers/preflight", req, http.StatusOK, &report)
+	return report, err
+}
+
 // PeerRemove removes a peer from the Cluster
 func (c *Client) PeerRemove(peerid string) error {
 	delURL := fmt.Sprintf("/v1/peers/%s", peerid)
 	return c.del(delURL, nil, http.StatusNoContent, nil)
 }
 
+// PeerRemoveForce removes a peer from the Cluster even if detach checks fail.
+// A peer which can't be reached is only removed from the cluster.
+func (c *Client) PeerRemoveForce(peerid string) error {
+	delURL := fmt.Sprintf("/v1/peers/%s?force=true", peerid)
+	return c.del(delURL, nil, http.StatusNoContent, nil)
+}
+
+// SetPeerMetadata replaces the metadata of a peer, and its zone if one is given
+func (c *Client) SetPeerMetadata(peerid string, req api.PeerMetadataReq) (api.PeerEditResp, error) {
+	var peer api.PeerEditResp
+	err := c.put("/v1/peers/"+peerid+"/metadata", req, http.StatusOK, &peer)
+	return peer, err
+}
+
 // GetPeer returns information about a peer
 func (c *Client) GetPeer(peerid string) (api.PeerGetResp, error) {
 	var peer api.PeerGetResp
@@ -37,3 +60,163 @@ func (c *Client) Peers(filterParams ...map[string]string) (api.PeerListResp, err
 	err := c.get("/v1/peers"+queryString, nil, http.StatusOK, &peers)
 	return peers, err
 }
+
+// PeerDecommission retires a peer permanently. The request has to be sent to
+// the peer being decommissioned, which stops after leaving the cluster.
+func (c *Client) PeerDecommission(peerid string) error {
+	url := fmt.Sprintf("/v1/peers/%s/decommission", peerid)
+	return c.post(url, nil, http.StatusOK, nil)
+}
+
+// PeerPromote pins the peer as a voting member of the etcd cluster of the
+// store
+func (c *Client) PeerPromote(peerid string) (api.PeerGetResp, error) {
+	var peer api.PeerGetResp
+	err := c.post(fmt.Sprintf("/v1/peers/%s/promote", peerid), nil, http.StatusOK, &peer)
+	return peer, err
+}
+
+// PeerDemote pins the peer as a proxy, which only connects to the etcd
+// cluster of the store as a client
+func (c *Client) PeerDemote(peerid string) (api.PeerGetResp, error) {
+	var peer api.PeerGetResp
+	err := c.post(fmt.Sprintf("/v1/peers/%s/demote", peerid), nil, http.StatusOK, &peer)
+	return peer, err
+}
+
+// PeerEtcdRoleReset lets the store decide the etcd role of the peer again
+func (c *Client) PeerEtcdRoleReset(peerid string) (api.PeerGetResp, error) {
+	var peer api.PeerGetResp
+	err := c.del(fmt.Sprintf("/v1/peers/%s/etcd-role", peerid), nil, http.StatusOK, &peer)
+	return peer, err
+}
+
+// EtcdSize returns the number of peers running etcd voting members
+func (c *Client) EtcdSize() (api.EtcdSizeResp, error) {
+	var resp api.EtcdSizeResp
+	err := c.get("/v1/etcd/size", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// EtcdSizeSet sets the number of peers running etcd voting members
+func (c *Client) EtcdSizeSet(size int) (api.EtcdSizeResp, error) {
+	var resp api.EtcdSizeResp
+	err := c.post("/v1/etcd/size", api.EtcdSizeReq{IdealSize: size}, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerOptions returns the cluster options overridden for the peer
+func (c *Client) PeerOptions(peerid string) (api.PeerOptionsResp, error) {
+	var resp api.PeerOptionsResp
+	err := c.get(fmt.Sprintf("/v1/peers/%s/options", peerid), nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerOptionsSet overrides cluster options for the peer
+func (c *Client) PeerOptionsSet(peerid string, req api.PeerOptionsReq) (api.PeerOptionsResp, error) {
+	var resp api.PeerOptionsResp
+	err := c.post(fmt.Sprintf("/v1/peers/%s/options", peerid), req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerOptionReset removes the override of a cluster option for the peer
+func (c *Client) PeerOptionReset(peerid, optname string) (api.PeerOptionsResp, error) {
+	var resp api.PeerOptionsResp
+	err := c.del(fmt.Sprintf("/v1/peers/%s/options/%s", peerid, optname), nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerLabels returns the labels of the peer
+func (c *Client) PeerLabels(peerid string) (api.PeerLabelsResp, error) {
+	var resp api.PeerLabelsResp
+	err := c.get(fmt.Sprintf("/v1/peers/%s/labels", peerid), nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerLabelsSet sets labels on the peer, keeping its other labels
+func (c *Client) PeerLabelsSet(peerid string, req api.PeerLabelsReq) (api.PeerLabelsResp, error) {
+	var resp api.PeerLabelsResp
+	err := c.post(fmt.Sprintf("/v1/peers/%s/labels", peerid), req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerLabelDelete removes the label from the peer
+func (c *Client) PeerLabelDelete(peerid, label string) (api.PeerLabelsResp, error) {
+	var resp api.PeerLabelsResp
+	err := c.del(fmt.Sprintf("/v1/peers/%s/labels/%s", peerid, label), nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerBricks returns the bricks of all the volumes on the peer. Only the
+// bricks with paths starting with pathPrefix are returned if it isn't empty.
+func (c *Client) PeerBricks(peerid, pathPrefix string) (api.PeerBricksResp, error) {
+	var resp api.PeerBricksResp
+	queryString := ""
+	if pathPrefix != "" {
+		queryString = "?path-prefix=" + url.QueryEscape(pathPrefix)
+	}
+	err := c.get(fmt.Sprintf("/v1/peers/%s/bricks", peerid)+queryString, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// BrickLookupByPath returns the brick at the path on the peer, with the
+// volume it is of
+func (c *Client) BrickLookupByPath(peerid, path string) (api.BrickLookupResp, error) {
+	var resp api.BrickLookupResp
+	q := url.Values{}
+	q.Set("peer-id", peerid)
+	q.Set("path", path)
+	err := c.get("/v1/bricks/lookup?"+q.Encode(), nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// BrickLookupByLv returns the auto provisioned brick on the LV, with the
+// volume it is of. The peer and the volume group are optional.
+func (c *Client) BrickLookupByLv(peerid, vgName, lvName string) (api.BrickLookupResp, error) {
+	var resp api.BrickLookupResp
+	q := url.Values{}
+	q.Set("lv", lvName)
+	if peerid != "" {
+		q.Set("peer-id", peerid)
+	}
+	if vgName != "" {
+		q.Set("vg", vgName)
+	}
+	err := c.get("/v1/bricks/lookup?"+q.Encode(), nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerEvacuate starts evacuating all bricks of the peer, and optionally
+// detaching it once the new bricks are healed
+func (c *Client) PeerEvacuate(peerid string, req api.PeerEvacuateReq) (api.PeerEvacuationResp, error) {
+	var resp api.PeerEvacuationResp
+	err := c.post(fmt.Sprintf("/v1/peers/%s/evacuate", peerid), req, http.StatusAccepted, &resp)
+	return resp, err
+}
+
+// PeerEvacuation returns the progress of the evacuation of the peer
+func (c *Client) PeerEvacuation(peerid string) (api.PeerEvacuationResp, error) {
+	var resp api.PeerEvacuationResp
+	err := c.get(fmt.Sprintf("/v1/peers/%s/evacuate", peerid), nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerEvacuationPause stops replacing more bricks of the evacuated peer
+func (c *Client) PeerEvacuationPause(peerid string) (api.PeerEvacuationResp, error) {
+	var resp api.PeerEvacuationResp
+	err := c.post(fmt.Sprintf("/v1/peers/%s/evacuate/pause", peerid), nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerEvacuationResume resumes a paused or failed evacuation of the peer
+func (c *Client) PeerEvacuationResume(peerid string) (api.PeerEvacuationResp, error) {
+	var resp api.PeerEvacuationResp
+	err := c.post(fmt.Sprintf("/v1/peers/%s/evacuate/resume", peerid), nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// PeerEvacuationCancel stops the evacuation of the peer. Bricks already
+// replaced are not restored.
+func (c *Client) PeerEvacuationCancel(peerid string) error {
+	return c.del(fmt.Sprintf("/v1/peers/%s/evacuate", peerid), nil, http.StatusNoContent, nil)
+}
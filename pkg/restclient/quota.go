@@ -3,10 +3,40 @@ package restclient
 import (
 	"fmt"
 	"net/http"
+
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
 )
 
-// QuotaEnable starts a Gluster Volume
+// QuotaEnable enables quota for a volume
 func (c *Client) QuotaEnable(volname string) error {
-	url := fmt.Sprintf("/v1/quota/%s", volname)
+	url := fmt.Sprintf("/v1/volumes/%s/quota/enable", volname)
 	return c.post(url, nil, http.StatusOK, nil)
 }
+
+// QuotaDisable disables quota for a volume, removing the limits set on its
+// directories
+func (c *Client) QuotaDisable(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/quota/disable", volname)
+	return c.post(url, nil, http.StatusOK, nil)
+}
+
+// QuotaList returns the limits set on the directories of a volume, and their
+// usage
+func (c *Client) QuotaList(volname string) (quotaapi.ListResp, error) {
+	var resp quotaapi.ListResp
+	url := fmt.Sprintf("/v1/volumes/%s/quota", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// QuotaLimitSet sets limits on a directory of a volume
+func (c *Client) QuotaLimitSet(volname string, req quotaapi.SetLimitReq) error {
+	url := fmt.Sprintf("/v1/volumes/%s/quota/limit", volname)
+	return c.post(url, req, http.StatusOK, nil)
+}
+
+// QuotaLimitRemove removes the limits set on a directory of a volume
+func (c *Client) QuotaLimitRemove(volname string, path string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/quota/limit", volname)
+	return c.del(url, quotaapi.RemoveLimitReq{Path: path}, http.StatusNoContent, nil)
+}
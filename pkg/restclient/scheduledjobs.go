@@ -0,0 +1,44 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// ScheduledJobs returns the list of periodic jobs registered with the scheduler
+func (c *Client) ScheduledJobs() (api.ScheduledJobListResp, error) {
+	var resp api.ScheduledJobListResp
+	err := c.get("/v1/scheduled-jobs", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ScheduledJobInfo returns the state of the named periodic job
+func (c *Client) ScheduledJobInfo(name string) (api.ScheduledJob, error) {
+	var resp api.ScheduledJob
+	err := c.get("/v1/scheduled-jobs/"+name, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ScheduledJobHistory returns the recent runs of the named periodic job
+func (c *Client) ScheduledJobHistory(name string) (api.ScheduledJobHistoryResp, error) {
+	var resp api.ScheduledJobHistoryResp
+	err := c.get("/v1/scheduled-jobs/"+name+"/history", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ScheduledJobEnable enables the named periodic job. If schedule is not empty,
+// it replaces the schedule of the job.
+func (c *Client) ScheduledJobEnable(name, schedule string) (api.ScheduledJob, error) {
+	var resp api.ScheduledJob
+	req := api.ScheduledJobEnableReq{Schedule: schedule}
+	err := c.post("/v1/scheduled-jobs/"+name+"/enable", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ScheduledJobDisable disables the named periodic job
+func (c *Client) ScheduledJobDisable(name string) (api.ScheduledJob, error) {
+	var resp api.ScheduledJob
+	err := c.post("/v1/scheduled-jobs/"+name+"/disable", nil, http.StatusOK, &resp)
+	return resp, err
+}
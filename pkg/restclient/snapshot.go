@@ -14,6 +14,27 @@ func (c *Client) SnapshotCreate(req api.SnapCreateReq) (api.SnapCreateResp, erro
 	return snap, err
 }
 
+// SnapshotHookAdd registers a snapshot hook, replacing any hook of the
+// same name
+func (c *Client) SnapshotHookAdd(req api.SnapHookReq) (api.SnapHook, error) {
+	var hook api.SnapHook
+	err := c.post("/v1/snapshot-hooks", req, http.StatusCreated, &hook)
+	return hook, err
+}
+
+// SnapshotHooks returns the registered snapshot hooks
+func (c *Client) SnapshotHooks() (api.SnapHookListResp, error) {
+	var hooks api.SnapHookListResp
+	err := c.get("/v1/snapshot-hooks", nil, http.StatusOK, &hooks)
+	return hooks, err
+}
+
+// SnapshotHookDelete unregisters a snapshot hook
+func (c *Client) SnapshotHookDelete(name string) error {
+	url := fmt.Sprintf("/v1/snapshot-hooks/%s", name)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
 //SnapshotActivate activate a Gluster snapshot
 func (c *Client) SnapshotActivate(req api.SnapActivateReq, snapname string) error {
 	url := fmt.Sprintf("/v1/snapshots/%s/activate", snapname)
@@ -79,3 +100,12 @@ func (c *Client) SnapshotClone(snapname string, req api.SnapCloneReq) (api.Volum
 	err := c.post(url, req, http.StatusCreated, &vol)
 	return vol, err
 }
+
+// SnapshotCloneAsync clones a snapshot in the background, and returns the
+// async job to poll for the cloned volume
+func (c *Client) SnapshotCloneAsync(snapname string, req api.SnapCloneReq) (api.AsyncJob, error) {
+	var job api.AsyncJob
+	url := fmt.Sprintf("/v1/snapshots/%s/clone?async=true", snapname)
+	err := c.post(url, req, http.StatusAccepted, &job)
+	return job, err
+}
@@ -0,0 +1,47 @@
+package restclient
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// SupportBundleCreate starts generating a support bundle, and returns the
+// async job generating it. The ID of the job is the ID of the bundle.
+func (c *Client) SupportBundleCreate(req api.SupportBundleReq) (api.AsyncJob, error) {
+	var job api.AsyncJob
+	err := c.post("/v1/support-bundle", req, http.StatusAccepted, &job)
+	return job, err
+}
+
+// SupportBundleStatus returns the state of the given support bundle
+func (c *Client) SupportBundleStatus(id string) (api.SupportBundleResp, error) {
+	var resp api.SupportBundleResp
+	err := c.get("/v1/support-bundle/"+id, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// SupportBundleList returns the support bundles generated in the cluster
+func (c *Client) SupportBundleList() (api.SupportBundleListResp, error) {
+	var resp api.SupportBundleListResp
+	err := c.get("/v1/support-bundle", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// SupportBundleDownload writes the archive of the given support bundle to w
+func (c *Client) SupportBundleDownload(id string, w io.Writer) error {
+	resp, err := c.send("GET", "/v1/support-bundle/"+id+"/archive", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.lastRespErr = resp
+		return newHTTPErrorResponse(resp)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
@@ -0,0 +1,33 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// UserAdd adds or updates a user of the REST API
+func (c *Client) UserAdd(req api.User) (api.User, error) {
+	var resp api.User
+	err := c.post("/v1/users", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// Users returns the users of the REST API
+func (c *Client) Users() (api.UserListResp, error) {
+	var resp api.UserListResp
+	err := c.get("/v1/users", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// User returns a user of the REST API
+func (c *Client) User(name string) (api.User, error) {
+	var resp api.User
+	err := c.get("/v1/users/"+name, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// UserDelete deletes a user of the REST API
+func (c *Client) UserDelete(name string) error {
+	return c.del("/v1/users/"+name, nil, http.StatusNoContent, nil)
+}
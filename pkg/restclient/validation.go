@@ -0,0 +1,33 @@
+package restclient
+
+import (
+	"net/http"
+
+	validationapi "github.com/gluster/glusterd2/plugins/validation/api"
+)
+
+// ValidationWebhookAdd registers a webhook to validate volume operations. The
+// webhook is called for all volume operations if ops is empty.
+func (c *Client) ValidationWebhookAdd(url string, token string, ops []string) error {
+	req := &validationapi.Webhook{
+		URL:   url,
+		Token: token,
+		Ops:   ops,
+	}
+	return c.post("/v1/validation/webhook", req, http.StatusOK, nil)
+}
+
+// ValidationWebhookDelete deletes the validation webhook
+func (c *Client) ValidationWebhookDelete(url string) error {
+	req := &validationapi.WebhookDel{
+		URL: url,
+	}
+	return c.del("/v1/validation/webhook", req, http.StatusNoContent, nil)
+}
+
+// ValidationWebhooks returns the list of webhooks validating volume operations
+func (c *Client) ValidationWebhooks() (validationapi.WebhookList, error) {
+	var resp validationapi.WebhookList
+	err := c.get("/v1/validation/webhook", nil, http.StatusOK, &resp)
+	return resp, err
+}
@@ -1,24 +1,17 @@
 package restclient
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/pkg/api"
 )
 
-// metadataFilter is a filter type
-type metadataFilter uint32
-
-// GetVolumes Filter Types
-const (
-	noKeyAndValue metadataFilter = iota
-	onlyKey
-	onlyValue
-	keyAndValue
-)
-
 // VolumeCreate creates Gluster Volume
 func (c *Client) VolumeCreate(req api.VolCreateReq) (api.VolumeCreateResp, error) {
 	var vol api.VolumeCreateResp
@@ -26,33 +19,17 @@ func (c *Client) VolumeCreate(req api.VolCreateReq) (api.VolumeCreateResp, error
 	return vol, err
 }
 
-// getFilterType return the filter type for volume list/info
-func getFilterType(filterParams map[string]string) metadataFilter {
-	_, key := filterParams["key"]
-	_, value := filterParams["value"]
-	if key && !value {
-		return onlyKey
-	} else if value && !key {
-		return onlyValue
-	} else if value && key {
-		return keyAndValue
-	}
-	return noKeyAndValue
-}
-
 // getQueryString returns the query string for filtering volumes
 func getQueryString(filterParam map[string]string) string {
-	filterType := getFilterType(filterParam)
-	var queryString string
-	switch filterType {
-	case onlyKey:
-		queryString = fmt.Sprintf("?key=%s", url.QueryEscape(filterParam["key"]))
-	case onlyValue:
-		queryString = fmt.Sprintf("?value=%s", url.QueryEscape(filterParam["value"]))
-	case keyAndValue:
-		queryString = fmt.Sprintf("?key=%s&value=%s", url.QueryEscape(filterParam["key"]), url.QueryEscape(filterParam["value"]))
+	if len(filterParam) == 0 {
+		return ""
 	}
-	return queryString
+
+	query := url.Values{}
+	for k, v := range filterParam {
+		query.Set(k, v)
+	}
+	return "?" + query.Encode()
 }
 
 // Volumes returns list of all volumes
@@ -73,6 +50,90 @@ func (c *Client) Volumes(volname string, filterParams ...map[string]string) (api
 	return []api.VolumeGetResp{vol}, err
 }
 
+// VolumesWithRevision lists all Gluster volumes along with the store revision
+// of the listing. Changes made to volumes after the listing can be fetched
+// with VolumeWatch from the revision.
+func (c *Client) VolumesWithRevision() (api.VolumeListResp, int64, error) {
+	resp, err := c.send("GET", "/v1/volumes", nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.lastRespErr = resp
+		return nil, 0, newHTTPErrorResponse(resp)
+	}
+
+	var vols api.VolumeListResp
+	if err := json.NewDecoder(resp.Body).Decode(&vols); err != nil {
+		return nil, 0, err
+	}
+
+	rev, err := strconv.ParseInt(resp.Header.Get("X-Gluster-Store-Revision"), 10, 64)
+	if err != nil {
+		return nil, 0, err
+	}
+	return vols, rev, nil
+}
+
+// VolumeRestoreFromBricks scans the bricks of all peers for volumes missing
+// from the store, and returns proposals to recreate them. Nothing is changed
+// until the proposed requests are sent with VolumeCreate.
+func (c *Client) VolumeRestoreFromBricks() (api.RestoreFromBricksResp, error) {
+	var resp api.RestoreFromBricksResp
+	err := c.post("/v1/volumes/restore-from-bricks", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumesPage returns a page of at most limit volumes, starting after the
+// given continue token, and the continue token for the next page. The
+// continue token is empty for the first page, and is returned empty with the
+// last page.
+func (c *Client) VolumesPage(limit int64, continueToken string) (api.VolumeListResp, string, error) {
+	q := url.Values{}
+	q.Set("limit", strconv.FormatInt(limit, 10))
+	if continueToken != "" {
+		q.Set("continue", continueToken)
+	}
+
+	resp, err := c.send("GET", "/v1/volumes?"+q.Encode(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.lastRespErr = resp
+		return nil, "", newHTTPErrorResponse(resp)
+	}
+
+	var vols api.VolumeListResp
+	if err := json.NewDecoder(resp.Body).Decode(&vols); err != nil {
+		return nil, "", err
+	}
+	return vols, resp.Header.Get("X-Gluster-Continue"), nil
+}
+
+// VolumesFields returns all volumes with only the given fields
+func (c *Client) VolumesFields(fields ...string) (api.VolumeListFieldsResp, error) {
+	var vols api.VolumeListFieldsResp
+	q := url.QueryEscape(strings.Join(fields, ","))
+	err := c.get("/v1/volumes?fields="+q, nil, http.StatusOK, &vols)
+	return vols, err
+}
+
+// VolumeWatch waits for changes to Gluster volumes made after the given
+// store revision. An empty list of events is returned if no change is made
+// within the timeout. If the revision has been compacted, an error with the
+// status Gone is returned and the volumes must be listed again.
+func (c *Client) VolumeWatch(fromRevision int64, timeout time.Duration) (api.VolumeWatchResp, error) {
+	var resp api.VolumeWatchResp
+	url := fmt.Sprintf("/v1/volumes?watch=true&from-revision=%d&timeout=%d", fromRevision, int(timeout/time.Second))
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
 // BricksStatus returns the status of bricks that form a Gluster volume
 func (c *Client) BricksStatus(volname string) (api.BricksStatusResp, error) {
 	url := fmt.Sprintf("/v1/volumes/%s/bricks", volname)
@@ -110,6 +171,99 @@ func (c *Client) VolumeDelete(volname string) error {
 	return c.del(url, nil, http.StatusNoContent, nil)
 }
 
+// VolumeDeleteWipe deletes a Gluster Volume and schedules jobs wiping the data
+// of its bricks in the background
+func (c *Client) VolumeDeleteWipe(volname string) (api.BrickWipeJobsResp, error) {
+	var jobs api.BrickWipeJobsResp
+	url := fmt.Sprintf("/v1/volumes/%s?wipe=true", volname)
+	err := c.del(url, nil, http.StatusAccepted, &jobs)
+	return jobs, err
+}
+
+// VolumePurge deletes a Gluster Volume without keeping it in the trash
+func (c *Client) VolumePurge(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s?purge=true", volname)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
+// VolumeBulkDelete starts a job deleting the given volumes, or the volumes
+// with metadata matching the selector if no volumes are given. Concurrency
+// limits the number of volumes deleted at a time, with 0 for the default.
+func (c *Client) VolumeBulkDelete(volnames []string, selector string, wipe, purge bool, concurrency int) (api.VolumeBulkDeleteJob, error) {
+	var job api.VolumeBulkDeleteJob
+	query := url.Values{}
+	if len(volnames) != 0 {
+		query.Set("volumes", strings.Join(volnames, ","))
+	}
+	if selector != "" {
+		query.Set("selector", selector)
+	}
+	if wipe {
+		query.Set("wipe", "true")
+	}
+	if purge {
+		query.Set("purge", "true")
+	}
+	if concurrency != 0 {
+		query.Set("concurrency", strconv.Itoa(concurrency))
+	}
+	err := c.del("/v1/volumes?"+query.Encode(), nil, http.StatusAccepted, &job)
+	return job, err
+}
+
+// VolumeBulkDeleteJobs lists the bulk volume delete jobs run by the peer
+func (c *Client) VolumeBulkDeleteJobs() (api.VolumeBulkDeleteJobListResp, error) {
+	var jobs api.VolumeBulkDeleteJobListResp
+	err := c.get("/v1/volume-delete-jobs", nil, http.StatusOK, &jobs)
+	return jobs, err
+}
+
+// VolumeBulkDeleteJob returns the state of a bulk volume delete job, with
+// the result of deleting each of its volumes
+func (c *Client) VolumeBulkDeleteJob(id string) (api.VolumeBulkDeleteJob, error) {
+	var job api.VolumeBulkDeleteJob
+	url := fmt.Sprintf("/v1/volume-delete-jobs/%s", id)
+	err := c.get(url, nil, http.StatusOK, &job)
+	return job, err
+}
+
+// VolumePurgeWipe deletes a Gluster Volume without keeping it in the trash,
+// and schedules jobs wiping the data of its bricks in the background
+func (c *Client) VolumePurgeWipe(volname string) (api.BrickWipeJobsResp, error) {
+	var jobs api.BrickWipeJobsResp
+	url := fmt.Sprintf("/v1/volumes/%s?purge=true&wipe=true", volname)
+	err := c.del(url, nil, http.StatusAccepted, &jobs)
+	return jobs, err
+}
+
+// DeletedVolumes lists the deleted volumes kept in the trash
+func (c *Client) DeletedVolumes() (api.DeletedVolumeListResp, error) {
+	var vols api.DeletedVolumeListResp
+	err := c.get("/v1/volumes?deleted=true", nil, http.StatusOK, &vols)
+	return vols, err
+}
+
+// VolumeRestore restores a deleted volume from the trash
+func (c *Client) VolumeRestore(volname string) (api.VolumeRestoreResp, error) {
+	var vol api.VolumeRestoreResp
+	url := fmt.Sprintf("/v1/volumes/%s/restore", volname)
+	err := c.post(url, nil, http.StatusOK, &vol)
+	return vol, err
+}
+
+// BrickWipeJobs lists the jobs wiping the bricks of deleted volumes
+func (c *Client) BrickWipeJobs() (api.BrickWipeJobsResp, error) {
+	var jobs api.BrickWipeJobsResp
+	err := c.get("/v1/brick-wipe-jobs", nil, http.StatusOK, &jobs)
+	return jobs, err
+}
+
+// BrickWipeJobDelete removes a finished brick wipe job
+func (c *Client) BrickWipeJobDelete(id string) error {
+	url := fmt.Sprintf("/v1/brick-wipe-jobs/%s", id)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
 // VolumeSet sets an option for a Gluster Volume
 func (c *Client) VolumeSet(volname string, req api.VolOptionReq) error {
 	url := fmt.Sprintf("/v1/volumes/%s/options", volname)
@@ -117,12 +271,65 @@ func (c *Client) VolumeSet(volname string, req api.VolOptionReq) error {
 	return err
 }
 
+// VolumeSetImpact reports the impact of setting options on a Gluster Volume,
+// without setting them
+func (c *Client) VolumeSetImpact(volname string, req api.VolOptionReq) (api.OptionImpactResp, error) {
+	var resp api.OptionImpactResp
+	url := fmt.Sprintf("/v1/volumes/%s/options/impact", volname)
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
 // ClusterOptionSet sets cluster level options
 func (c *Client) ClusterOptionSet(req api.ClusterOptionReq) error {
 	url := fmt.Sprintf("/v1/cluster/options")
 	return c.post(url, req, http.StatusOK, nil)
 }
 
+// ClusterOptionSetImpact reports the impact of setting cluster level options,
+// without setting them
+func (c *Client) ClusterOptionSetImpact(req api.ClusterOptionReq) (api.OptionImpactResp, error) {
+	var resp api.OptionImpactResp
+	err := c.post("/v1/cluster/options/impact", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ReadOnlyModeSet enables or disables the read-only mode of the cluster
+func (c *Client) ReadOnlyModeSet(req api.ReadOnlyModeReq) (api.ReadOnlyModeResp, error) {
+	var resp api.ReadOnlyModeResp
+	err := c.put("/v1/cluster/readonly", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ReadOnlyMode returns the read-only mode of the cluster
+func (c *Client) ReadOnlyMode() (api.ReadOnlyModeResp, error) {
+	var resp api.ReadOnlyModeResp
+	err := c.get("/v1/cluster/readonly", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// OptionDocs returns the documentation of all volume options
+func (c *Client) OptionDocs() (api.OptionDocListResp, error) {
+	var resp api.OptionDocListResp
+	err := c.get("/v1/options", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// OptionDoc returns the documentation of a volume option
+func (c *Client) OptionDoc(optname string) (api.OptionDoc, error) {
+	var resp api.OptionDoc
+	err := c.get("/v1/options/"+optname, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeOptionsSchema returns the documentation of the options which can be
+// set on volumes
+func (c *Client) VolumeOptionsSchema() (api.OptionDocListResp, error) {
+	var resp api.OptionDocListResp
+	err := c.get("/v1/volumes/options", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
 // VolumeGet gets volume options for a Gluster Volume
 func (c *Client) VolumeGet(volname string, optname string) (api.VolumeOptionsGetResp, error) {
 	if optname == "all" {
@@ -164,6 +371,32 @@ func (c *Client) OptionGroupCreate(req api.OptionGroupReq) error {
 	return c.post("/v1/volumes/options-group", req, http.StatusOK, nil)
 }
 
+// VolumeProfileCreate creates a volume profile
+func (c *Client) VolumeProfileCreate(req api.VolumeProfile) (api.VolumeProfile, error) {
+	var resp api.VolumeProfile
+	err := c.post("/v1/volume-profiles", req, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// VolumeProfileList returns all the volume profiles
+func (c *Client) VolumeProfileList() (api.VolumeProfileListResp, error) {
+	var resp api.VolumeProfileListResp
+	err := c.get("/v1/volume-profiles", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeProfileGet returns the volume profile with the name
+func (c *Client) VolumeProfileGet(name string) (api.VolumeProfile, error) {
+	var resp api.VolumeProfile
+	err := c.get("/v1/volume-profiles/"+name, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeProfileDelete deletes the volume profile with the name
+func (c *Client) VolumeProfileDelete(name string) error {
+	return c.del("/v1/volume-profiles/"+name, nil, http.StatusNoContent, nil)
+}
+
 // OptionGroupList returns a list of all option groups
 func (c *Client) OptionGroupList() (api.OptionGroupListResp, error) {
 	var l api.OptionGroupListResp
@@ -186,12 +419,213 @@ func (c *Client) EditVolume(volname string, req api.VolEditReq) (api.VolumeEditR
 	return resp, err
 }
 
+// VolumeRename renames a volume
+func (c *Client) VolumeRename(volname string, req api.VolRenameReq) (api.VolumeRenameResp, error) {
+	var resp api.VolumeRenameResp
+	url := fmt.Sprintf("/v1/volumes/%s/rename", volname)
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
 // VolumeReset resets volume options to their default values
 func (c *Client) VolumeReset(volname string, req api.VolOptionResetReq) error {
 	url := fmt.Sprintf("/v1/volumes/%s/options", volname)
 	return c.del(url, req, http.StatusOK, nil)
 }
 
+// VolumeOptionsHistory returns the recorded option changes of a Gluster volume
+func (c *Client) VolumeOptionsHistory(volname string) (api.VolOptionsHistoryResp, error) {
+	var resp api.VolOptionsHistoryResp
+	url := fmt.Sprintf("/v1/volumes/%s/options/history", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeDiff returns the changes made to a Gluster volume between two points
+// in its history. from and to are either store revisions or RFC3339
+// timestamps, and the current volume is compared to if to is empty.
+func (c *Client) VolumeDiff(volname, from, to string) (api.VolDiffResp, error) {
+	var resp api.VolDiffResp
+	q := url.Values{}
+	q.Set("from", from)
+	if to != "" {
+		q.Set("to", to)
+	}
+	err := c.get(fmt.Sprintf("/v1/volumes/%s/diff?%s", volname, q.Encode()), nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeOptionsRollback restores the volume options recorded in the options history entry
+func (c *Client) VolumeOptionsRollback(volname string, req api.VolOptionsRollbackReq) (api.VolumeOptionResp, error) {
+	var resp api.VolumeOptionResp
+	url := fmt.Sprintf("/v1/volumes/%s/options/rollback", volname)
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeRefreshSize refreshes the capacity of a Gluster volume after its
+// bricks have been resized
+func (c *Client) VolumeRefreshSize(volname string) (api.VolumeRefreshSizeResp, error) {
+	var resp api.VolumeRefreshSizeResp
+	url := fmt.Sprintf("/v1/volumes/%s/refresh-size", volname)
+	err := c.post(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeAutoExpandSet sets the auto expansion policy of a Gluster volume
+func (c *Client) VolumeAutoExpandSet(volname string, req api.VolAutoExpandReq) (api.VolAutoExpandResp, error) {
+	var resp api.VolAutoExpandResp
+	url := fmt.Sprintf("/v1/volumes/%s/autoexpand", volname)
+	err := c.put(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeAutoExpandGet returns the auto expansion policy of a Gluster volume
+func (c *Client) VolumeAutoExpandGet(volname string) (api.VolAutoExpandResp, error) {
+	var resp api.VolAutoExpandResp
+	url := fmt.Sprintf("/v1/volumes/%s/autoexpand", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeAutoExpandDelete deletes the auto expansion policy of a Gluster volume
+func (c *Client) VolumeAutoExpandDelete(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/autoexpand", volname)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
+// VolumeUsageProtectSet sets the usage protection policy of a Gluster volume
+func (c *Client) VolumeUsageProtectSet(volname string, req api.VolUsageProtectReq) (api.VolUsageProtectResp, error) {
+	var resp api.VolUsageProtectResp
+	url := fmt.Sprintf("/v1/volumes/%s/usage-protect", volname)
+	err := c.put(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeUsageProtectGet returns the usage protection policy of a Gluster volume
+func (c *Client) VolumeUsageProtectGet(volname string) (api.VolUsageProtectResp, error) {
+	var resp api.VolUsageProtectResp
+	url := fmt.Sprintf("/v1/volumes/%s/usage-protect", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeUsageProtectDelete deletes the usage protection policy of a Gluster
+// volume, making the volume writable if the policy had made it read-only
+func (c *Client) VolumeUsageProtectDelete(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/usage-protect", volname)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
+// VolumeIOThrottleSet sets the I/O limits of the bricks of a Gluster volume
+func (c *Client) VolumeIOThrottleSet(volname string, req api.VolIOThrottleReq) (api.VolIOThrottleResp, error) {
+	var resp api.VolIOThrottleResp
+	url := fmt.Sprintf("/v1/volumes/%s/io-throttle", volname)
+	err := c.put(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeIOThrottleGet returns the I/O limits of the bricks of a Gluster volume
+func (c *Client) VolumeIOThrottleGet(volname string) (api.VolIOThrottleResp, error) {
+	var resp api.VolIOThrottleResp
+	url := fmt.Sprintf("/v1/volumes/%s/io-throttle", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeIOThrottleDelete removes the I/O limits of the bricks of a Gluster
+// volume
+func (c *Client) VolumeIOThrottleDelete(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/io-throttle", volname)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
+// VolumeAdvisoryLockAcquire takes an advisory lock on a Gluster volume,
+// preventing disruptive operations on it till the lock is released or expires
+func (c *Client) VolumeAdvisoryLockAcquire(volname string, req api.VolAdvisoryLockReq) (api.VolAdvisoryLock, error) {
+	var resp api.VolAdvisoryLock
+	url := fmt.Sprintf("/v1/volumes/%s/locks", volname)
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeAdvisoryLocks lists the advisory locks held on a Gluster volume
+func (c *Client) VolumeAdvisoryLocks(volname string) (api.VolAdvisoryLockListResp, error) {
+	var resp api.VolAdvisoryLockListResp
+	url := fmt.Sprintf("/v1/volumes/%s/locks", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeAdvisoryLockRelease releases an advisory lock on a Gluster volume. If
+// holder is not empty, the lock is released only if it is held by the holder.
+func (c *Client) VolumeAdvisoryLockRelease(volname, lockname, holder string) error {
+	queryString := ""
+	if holder != "" {
+		queryString = "?holder=" + url.QueryEscape(holder)
+	}
+	return c.del(fmt.Sprintf("/v1/volumes/%s/locks/%s%s", volname, lockname, queryString), nil, http.StatusNoContent, nil)
+}
+
+// VolumeACLSet sets the client access control lists of a Gluster volume
+func (c *Client) VolumeACLSet(volname string, req api.VolACLReq) (api.VolACLResp, error) {
+	var resp api.VolACLResp
+	url := fmt.Sprintf("/v1/volumes/%s/acl", volname)
+	err := c.put(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeACLGet returns the client access control lists of a Gluster volume
+func (c *Client) VolumeACLGet(volname string) (api.VolACLResp, error) {
+	var resp api.VolACLResp
+	url := fmt.Sprintf("/v1/volumes/%s/acl", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeACLReset resets the client access control lists of a Gluster volume,
+// allowing all clients to connect
+func (c *Client) VolumeACLReset(volname string) (api.VolACLResp, error) {
+	var resp api.VolACLResp
+	url := fmt.Sprintf("/v1/volumes/%s/acl", volname)
+	err := c.del(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeSubdirExport exports a subdirectory of a Gluster volume
+func (c *Client) VolumeSubdirExport(volname string, req api.SubdirExportReq) (api.SubdirExportResp, error) {
+	var resp api.SubdirExportResp
+	url := fmt.Sprintf("/v1/volumes/%s/subdirs", volname)
+	err := c.post(url, req, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// VolumeSubdirList lists the subdirectory exports of a Gluster volume
+func (c *Client) VolumeSubdirList(volname string) (api.SubdirExportListResp, error) {
+	var resp api.SubdirExportListResp
+	url := fmt.Sprintf("/v1/volumes/%s/subdirs", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeSubdirUnexport removes a subdirectory export of a Gluster volume
+func (c *Client) VolumeSubdirUnexport(volname string, subdir string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/subdirs/%s", volname, strings.TrimPrefix(subdir, "/"))
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
+// VolumeMetrics returns the metrics samples of a Gluster volume recorded in
+// the given range, eg. 1h or 7d. The default range is used if it is empty.
+func (c *Client) VolumeMetrics(volname string, timeRange string) (api.VolMetricsResp, error) {
+	var resp api.VolMetricsResp
+	url := fmt.Sprintf("/v1/volumes/%s/metrics", volname)
+	if timeRange != "" {
+		url += "?range=" + timeRange
+	}
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
 //VolumeProfileInfo retrieves the stats about different file operations performed on a volume
 func (c *Client) VolumeProfileInfo(volname string, option string) ([]api.BrickProfileInfo, error) {
 	var volumeProfileInfo []api.BrickProfileInfo
@@ -0,0 +1,147 @@
+// Package tlsreload provides TLS configurations whose certificates can be
+// reloaded from their files, like on SIGHUP, without restarting the servers
+// and clients using them
+package tlsreload
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Files are the PEM encoded files of a TLS configuration. CA is optional.
+type Files struct {
+	Cert string
+	Key  string
+	CA   string
+}
+
+// Certs is a certificate and CA pool loaded from Files. The configurations
+// returned by ServerConfig and ClientConfig use the certificates last loaded.
+type Certs struct {
+	files Files
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []*Certs
+)
+
+// Load loads the certificates from the files, and registers them to be
+// reloaded by ReloadAll
+func Load(files Files) (*Certs, error) {
+	c := &Certs{files: files}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+
+	registryMu.Lock()
+	registry = append(registry, c)
+	registryMu.Unlock()
+	return c, nil
+}
+
+// Reload reloads the certificates from the files. The certificates in use are
+// kept if the files can't be loaded.
+func (c *Certs) Reload() error {
+	cert, err := tls.LoadX509KeyPair(c.files.Cert, c.files.Key)
+	if err != nil {
+		return err
+	}
+
+	var pool *x509.CertPool
+	if c.files.CA != "" {
+		data, err := ioutil.ReadFile(c.files.CA)
+		if err != nil {
+			return err
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("no certificates found in %s", c.files.CA)
+		}
+	}
+
+	c.mu.Lock()
+	c.cert = &cert
+	c.pool = pool
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Certs) get() (*tls.Certificate, *x509.CertPool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert, c.pool
+}
+
+// ServerConfig returns a server configuration. Client certificates are
+// verified against the CA, if one is given, and are required if
+// requireClientCert is set.
+func (c *Certs) ServerConfig(requireClientCert bool) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12, // force TLS 1.2
+		Rand:       rand.Reader,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, _ := c.get()
+			return cert, nil
+		},
+		// Each connection gets the certificates last loaded
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := c.get()
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				Rand:         rand.Reader,
+			}
+			if pool != nil {
+				config.ClientCAs = pool
+				config.ClientAuth = tls.VerifyClientCertIfGiven
+				if requireClientCert {
+					config.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+			}
+			return config, nil
+		},
+	}
+}
+
+// ClientConfig returns a client configuration presenting the certificate, and
+// verifying servers against the CA, or the system CAs if no CA is given. The
+// configuration is of the certificates currently loaded, so a new one has to
+// be got for new connections.
+func (c *Certs) ClientConfig() *tls.Config {
+	cert, pool := c.get()
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{*cert},
+		RootCAs:      pool,
+	}
+}
+
+// ReloadAll reloads all the loaded certificates
+func ReloadAll() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, c := range registry {
+		logger := log.WithFields(log.Fields{
+			"cert-file": c.files.Cert,
+			"key-file":  c.files.Key,
+			"ca-file":   c.files.CA,
+		})
+		if err := c.Reload(); err != nil {
+			logger.WithError(err).Error("failed to reload TLS certificates, keeping the current certificates")
+			continue
+		}
+		logger.Info("reloaded TLS certificates")
+	}
+}
@@ -0,0 +1,83 @@
+package tlsreload
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCert writes a self signed certificate with the common name and its key
+// to the files
+func writeCert(t *testing.T, certFile, keyFile, cn string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	assert.Nil(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+}
+
+func commonName(t *testing.T, cert *tls.Certificate) string {
+	c, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.Nil(t, err)
+	return c.Subject.CommonName
+}
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tlsreload")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	files := Files{
+		Cert: filepath.Join(dir, "cert.pem"),
+		Key:  filepath.Join(dir, "key.pem"),
+		CA:   filepath.Join(dir, "cert.pem"),
+	}
+	writeCert(t, files.Cert, files.Key, "first")
+
+	c, err := Load(files)
+	assert.Nil(t, err)
+
+	config, err := c.ServerConfig(true).GetConfigForClient(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "first", commonName(t, &config.Certificates[0]))
+	assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+
+	writeCert(t, files.Cert, files.Key, "second")
+	ReloadAll()
+	config, err = c.ServerConfig(false).GetConfigForClient(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "second", commonName(t, &config.Certificates[0]))
+	assert.Equal(t, tls.VerifyClientCertIfGiven, config.ClientAuth)
+	assert.Equal(t, "second", commonName(t, &c.ClientConfig().Certificates[0]))
+
+	// The loaded certificates are kept if the files are broken
+	assert.Nil(t, ioutil.WriteFile(files.Key, []byte("broken"), 0600))
+	assert.NotNil(t, c.Reload())
+	assert.Equal(t, "second", commonName(t, &c.ClientConfig().Certificates[0]))
+}
@@ -15,16 +15,73 @@ import (
 const (
 	jaegerEndpointOpt      = "jaeger-endpoint"
 	jaegerAgentEndpointOpt = "jaeger-agent-endpoint"
+	zipkinEndpointOpt      = "zipkin-endpoint"
+	sampleFractionOpt      = "tracing-sample-fraction"
 )
 
 // InitFlags initializes the command line options for GD2 tracing endpoints
 func InitFlags() {
 	flag.String(jaegerEndpointOpt, "", "Jaeger collector endpoint that accepts spans from Jaeger agent.")
 	flag.String(jaegerAgentEndpointOpt, "", "Jaeger agent endpoint that the Jaeger client sends spans to.")
+	flag.String(zipkinEndpointOpt, "", "Zipkin collector endpoint that accepts spans, like http://zipkin:9411/api/v2/spans.")
+	flag.Float64(sampleFractionOpt, 1, "Fraction of the operations traced when a tracing endpoint is set. Operations continuing a trace of another peer follow its decision.")
+}
+
+// Init registers the exporters of the configured tracing endpoints, and
+// applies the sampling fraction if any exporter is registered. The returned
+// function flushes the spans buffered by the exporters, and should be called
+// before the process exits.
+func Init() func() {
+	var flushers []func()
+	if exporter := InitJaegerExporter(); exporter != nil {
+		flushers = append(flushers, exporter.Flush)
+	}
+	if exporter := initZipkinExporter(); exporter != nil {
+		flushers = append(flushers, exporter.Flush)
+	}
+
+	if len(flushers) == 0 {
+		return func() {}
+	}
+
+	trace.ApplyConfig(trace.Config{DefaultSampler: sampler()})
+	return func() {
+		for _, flush := range flushers {
+			flush()
+		}
+	}
+}
+
+// sampler returns the sampler of the configured sampling fraction
+func sampler() trace.Sampler {
+	fraction := config.GetFloat64(sampleFractionOpt)
+	switch {
+	case !config.IsSet(sampleFractionOpt) || fraction >= 1:
+		return trace.AlwaysSample()
+	case fraction <= 0:
+		return trace.NeverSample()
+	default:
+		return trace.ProbabilitySampler(fraction)
+	}
+}
+
+// initZipkinExporter registers an exporter sending spans to the configured
+// Zipkin collector. A nil exporter is returned if no collector is configured.
+func initZipkinExporter() *zipkinExporter {
+	endpoint := config.GetString(zipkinEndpointOpt)
+	if endpoint == "" {
+		return nil
+	}
+
+	exporter := newZipkinExporter(endpoint, gdctx.HostName)
+	trace.RegisterExporter(exporter)
+	log.WithField("zipkinEndpoint", endpoint).Info("tracing: Registered opencensus zipkin exporter for traces")
+
+	return exporter
 }
 
 // InitJaegerExporter initializes the jaeger exporter as the tracing endpoint
-// This should be called early when a process starts.
+// This should be called early when a process starts, and is called by Init.
 // This creates and returns an exporter if successful.
 // Otherwise, a warning is logged and a 'nil' exporter is returned.
 func InitJaegerExporter() *jaeger.Exporter {
@@ -33,6 +90,9 @@ func InitJaegerExporter() *jaeger.Exporter {
 	jaegerAgentEndpoint := config.GetString(jaegerAgentEndpointOpt)
 
 	// Return nil exporter if either endpoints are not specified
+	if jaegerEndpoint == "" && jaegerAgentEndpoint == "" {
+		return nil
+	}
 	if jaegerEndpoint == "" || jaegerAgentEndpoint == "" {
 		log.WithFields(log.Fields{
 			"jaegerEndpoint":      jaegerEndpoint,
@@ -59,8 +119,6 @@ func InitJaegerExporter() *jaeger.Exporter {
 	// Register the Jaeger exporter
 	// Register the passed exporter using opencensus API
 	trace.RegisterExporter(exporter)
-	// TODO: Change to probability sampler. Use "always sample" for now.
-	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
 	log.WithFields(log.Fields{
 		"jaegerEndpoint":      jaegerEndpoint,
 		"jaegerAgentEndpoint": jaegerAgentEndpoint,
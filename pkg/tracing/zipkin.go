@@ -0,0 +1,180 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// zipkinBatchSize is the number of spans sent to Zipkin in one request
+	zipkinBatchSize = 100
+	// zipkinFlushInterval is the interval at which spans are sent to
+	// Zipkin if a batch isn't full
+	zipkinFlushInterval = time.Second
+	// zipkinQueueSize is the number of spans queued for sending, beyond
+	// which spans are dropped so that operations aren't held up by an
+	// unreachable Zipkin collector
+	zipkinQueueSize = 1000
+)
+
+// zipkinEndpoint is the service a Zipkin span is recorded by
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+// zipkinAnnotation is an event recorded in a Zipkin span
+type zipkinAnnotation struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+// zipkinSpan is a span in the Zipkin v2 JSON format. Times are in
+// microseconds.
+type zipkinSpan struct {
+	TraceID       string             `json:"traceId"`
+	ID            string             `json:"id"`
+	ParentID      string             `json:"parentId,omitempty"`
+	Name          string             `json:"name"`
+	Timestamp     int64              `json:"timestamp"`
+	Duration      int64              `json:"duration"`
+	LocalEndpoint zipkinEndpoint     `json:"localEndpoint"`
+	Tags          map[string]string  `json:"tags,omitempty"`
+	Annotations   []zipkinAnnotation `json:"annotations,omitempty"`
+}
+
+func toMicroseconds(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Microsecond)
+}
+
+// toZipkinSpan converts an OpenCensus span to the Zipkin format
+func toZipkinSpan(s *trace.SpanData, serviceName string) *zipkinSpan {
+	z := &zipkinSpan{
+		TraceID:       hex.EncodeToString(s.TraceID[:]),
+		ID:            hex.EncodeToString(s.SpanID[:]),
+		Name:          s.Name,
+		Timestamp:     toMicroseconds(s.StartTime),
+		Duration:      int64(s.EndTime.Sub(s.StartTime) / time.Microsecond),
+		LocalEndpoint: zipkinEndpoint{ServiceName: serviceName},
+		Tags:          make(map[string]string),
+	}
+	if s.ParentSpanID != (trace.SpanID{}) {
+		z.ParentID = hex.EncodeToString(s.ParentSpanID[:])
+	}
+
+	for k, v := range s.Attributes {
+		z.Tags[k] = fmt.Sprint(v)
+	}
+	if s.Status.Code != 0 {
+		z.Tags["error"] = s.Status.Message
+	}
+
+	for _, a := range s.Annotations {
+		z.Annotations = append(z.Annotations, zipkinAnnotation{
+			Timestamp: toMicroseconds(a.Time),
+			Value:     a.Message,
+		})
+	}
+	return z
+}
+
+// zipkinExporter is an OpenCensus trace exporter sending spans to the HTTP
+// API of a Zipkin collector. Spans are sent in batches in the background.
+type zipkinExporter struct {
+	url         string
+	serviceName string
+	client      *http.Client
+
+	spans chan *zipkinSpan
+	flush chan chan struct{}
+	once  sync.Once
+}
+
+func newZipkinExporter(endpoint, serviceName string) *zipkinExporter {
+	e := &zipkinExporter{
+		url:         endpoint,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		spans:       make(chan *zipkinSpan, zipkinQueueSize),
+		flush:       make(chan chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// ExportSpan implements trace.Exporter
+func (e *zipkinExporter) ExportSpan(s *trace.SpanData) {
+	select {
+	case e.spans <- toZipkinSpan(s, e.serviceName):
+	default:
+		e.once.Do(func() {
+			log.WithField("zipkinEndpoint", e.url).Warning("tracing: Zipkin span queue is full, dropping spans")
+		})
+	}
+}
+
+// Flush sends the queued spans and waits for them to be sent
+func (e *zipkinExporter) Flush() {
+	done := make(chan struct{})
+	e.flush <- done
+	<-done
+}
+
+func (e *zipkinExporter) run() {
+	ticker := time.NewTicker(zipkinFlushInterval)
+	defer ticker.Stop()
+
+	var batch []*zipkinSpan
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			log.WithError(err).WithField("zipkinEndpoint", e.url).Warning("tracing: Failed to send spans to Zipkin")
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case s := <-e.spans:
+			batch = append(batch, s)
+			if len(batch) >= zipkinBatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case done := <-e.flush:
+			for n := len(e.spans); n > 0; n-- {
+				batch = append(batch, <-e.spans)
+			}
+			send()
+			close(done)
+		}
+	}
+}
+
+func (e *zipkinExporter) send(spans []*zipkinSpan) error {
+	body, err := json.Marshal(spans)
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("zipkin collector returned %s", resp.Status)
+	}
+	return nil
+}
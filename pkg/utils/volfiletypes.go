@@ -17,6 +17,8 @@ const (
 	GfProxyVolfile = "gfproxy"
 	// NFSVolfile is a name of nfs volfile template
 	NFSVolfile = "nfs"
+	// QuotadVolfile is a name of quotad volfile template
+	QuotadVolfile = "quotad"
 )
 
 // ValidVolfiles represents list of valid volfile names
@@ -29,4 +31,5 @@ var ValidVolfiles = [...]string{
 	ScrubdVolfile,
 	GfProxyVolfile,
 	NFSVolfile,
+	QuotadVolfile,
 }
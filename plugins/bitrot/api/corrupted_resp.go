@@ -0,0 +1,25 @@
+package api
+
+// CorruptedObject is an object of the volume which the scrubbers have found
+// to be corrupted
+type CorruptedObject struct {
+	GFID string `json:"gfid"`
+	// Nodes are the peers whose scrubber found the object corrupted
+	Nodes []string `json:"nodes"`
+}
+
+// CorruptedObjects is the list of corrupted objects of a volume
+type CorruptedObjects struct {
+	Volume  string            `json:"volume"`
+	Objects []CorruptedObject `json:"objects"`
+}
+
+// RepairResp is the response of the repair of a corrupted object
+type RepairResp struct {
+	GFID string `json:"gfid"`
+	// Bricks are the bricks from which the corrupted copy of the
+	// object was removed, to be healed from a good copy
+	Bricks []string `json:"bricks"`
+	// Paths are the paths of the object in the volume
+	Paths []string `json:"paths"`
+}
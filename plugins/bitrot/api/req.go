@@ -0,0 +1,8 @@
+package api
+
+// ScrubConfigReq represents a request to configure the scrubber of a volume.
+// Fields which are left empty are not changed.
+type ScrubConfigReq struct {
+	Frequency string `json:"frequency,omitempty"`
+	Throttle  string `json:"throttle,omitempty"`
+}
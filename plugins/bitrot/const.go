@@ -9,4 +9,6 @@ const (
 	keyScrubFrequency = "bit-rot.scrub-freq"
 	// keyScrubThrottle is the key for controls scrubber throttle
 	keyScrubThrottle = "bit-rot.scrub-throttle"
+	// keyScrubState is the key which pauses/resumes the scrubber
+	keyScrubState = "bit-rot.scrub-state"
 )
@@ -3,6 +3,8 @@ package bitrot
 import (
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/utils"
+	bitrotapi "github.com/gluster/glusterd2/plugins/bitrot/api"
 )
 
 const name = "bitrot"
@@ -36,6 +38,7 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Method:      "POST",
 			Pattern:     "/volumes/{volname}/bitrot/scrubondemand",
 			Version:     1,
+			Permission:  route.PermOperate,
 			HandlerFunc: bitrotScrubOndemandHandler},
 		route.Route{
 			Name:        "BitrotScrubStatus",
@@ -43,6 +46,41 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Pattern:     "/volumes/{volname}/bitrot/scrubstatus",
 			Version:     1,
 			HandlerFunc: bitrotScrubStatusHandler},
+		route.Route{
+			Name:        "BitrotScrubConfig",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/bitrot/scrubconfig",
+			Version:     1,
+			RequestType: utils.GetTypeString((*bitrotapi.ScrubConfigReq)(nil)),
+			HandlerFunc: bitrotScrubConfigHandler},
+		route.Route{
+			Name:        "BitrotScrubPause",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/bitrot/scrubpause",
+			Version:     1,
+			Permission:  route.PermOperate,
+			HandlerFunc: bitrotScrubPauseHandler},
+		route.Route{
+			Name:        "BitrotScrubResume",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/bitrot/scrubresume",
+			Version:     1,
+			Permission:  route.PermOperate,
+			HandlerFunc: bitrotScrubResumeHandler},
+		route.Route{
+			Name:         "BitrotCorrupted",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/bitrot/corrupted",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*bitrotapi.CorruptedObjects)(nil)),
+			HandlerFunc:  bitrotCorruptedHandler},
+		route.Route{
+			Name:         "BitrotRepair",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/bitrot/repair/{gfid}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*bitrotapi.RepairResp)(nil)),
+			HandlerFunc:  bitrotRepairHandler},
 	}
 }
 
@@ -53,5 +91,11 @@ func (p *Plugin) RegisterStepFuncs() {
 	transaction.RegisterStepFunc(txnBitrotEnableDisable, "bitrot-disable.Commit")
 	transaction.RegisterStepFunc(txnBitrotScrubOndemand, "bitrot-scrubondemand.Commit")
 	transaction.RegisterStepFunc(txnBitrotScrubStatus, "bitrot-scrubstatus.Commit")
+	transaction.RegisterStepFunc(txnBitrotScrubConfig, "bitrot-scrubconfig.Commit")
+	transaction.RegisterStepFunc(txnBitrotScrubConfigUndo, "bitrot-scrubconfig.Undo")
+	transaction.RegisterStepFunc(txnBitrotRepairFind, "bitrot-repair.Find")
+	transaction.RegisterStepFunc(txnBitrotRepairRemove, "bitrot-repair.Remove")
+	transaction.RegisterStepFunc(txnBitrotRepairHeal, "bitrot-repair.Heal")
+	registerIOOpHandler()
 	return
 }
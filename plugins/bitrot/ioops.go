@@ -0,0 +1,75 @@
+package bitrot
+
+import (
+	"context"
+
+	"github.com/gluster/glusterd2/glusterd2/ioops"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	bitrotapi "github.com/gluster/glusterd2/plugins/bitrot/api"
+)
+
+func registerIOOpHandler() {
+	ioops.Register(ioops.OpScrub, &ioops.Handler{
+		Start:    startQueuedScrub,
+		Running:  scrubRunning,
+		Throttle: throttleScrub,
+	})
+}
+
+func startQueuedScrub(ctx context.Context, c *ioops.Claim) error {
+	return startScrub(ctx, c.Volume)
+}
+
+// scrubRunning returns true if the scrubber of any of the peers of the
+// volume reports the scrub to be in progress
+func scrubRunning(ctx context.Context, c *ioops.Claim) (bool, error) {
+	volinfo, err := volume.GetVolume(c.Volume)
+	if err != nil {
+		return false, err
+	}
+	if volinfo.State != volume.VolStarted || !isBitrotEnabled(volinfo) {
+		return false, nil
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	// Some nodes may not be up, which is okay.
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "bitrot-scrubstatus.Commit",
+			Nodes:  volinfo.Nodes(),
+		},
+	}
+	if err := txn.Ctx.Set("volname", volinfo.Name); err != nil {
+		return false, err
+	}
+
+	if err := txn.Do(); err != nil {
+		return false, err
+	}
+
+	for _, node := range volinfo.Nodes() {
+		var info bitrotapi.ScrubNodeInfo
+		if err := txn.Ctx.GetNodeResult(node, scrubStatusTxnKey, &info); err != nil {
+			continue
+		}
+		if info.ScrubRunning == "1" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// throttleScrub pauses the scrub, and resumes it when restored
+func throttleScrub(ctx context.Context, c *ioops.Claim, throttled bool) error {
+	state := "resume"
+	if throttled {
+		state = "pause"
+	}
+	return setScrubOptions(ctx, c.Volume, map[string]string{keyScrubState: state})
+}
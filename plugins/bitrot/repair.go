@@ -0,0 +1,290 @@
+package bitrot
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	config "github.com/spf13/viper"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// badFileXattrKey is set by the scrubber on the objects it finds
+	// corrupted
+	badFileXattrKey = "trusted.bit-rot.bad-file"
+	// gfid2PathXattrPrefix is the prefix of the xattrs having the parent
+	// GFID and name of each link of an object
+	gfid2PathXattrPrefix = "trusted.gfid2path."
+	rootGFID             = "00000000-0000-0000-0000-000000000001"
+
+	badCopiesTxnKey = "badcopies"
+)
+
+// gfidHandle returns the path of the GFID handle of the object in the brick
+func gfidHandle(brickPath, gfid string) string {
+	return path.Join(brickPath, ".glusterfs", gfid[0:2], gfid[2:4], gfid)
+}
+
+// objectPaths returns the paths, relative to the brick root, of the links
+// of the object with the given GFID. The paths are got from the gfid2path
+// xattrs of the object, and if those aren't enabled, by looking for the
+// inode of the object in the brick.
+func objectPaths(brickPath, gfid string) ([]string, error) {
+	handle := gfidHandle(brickPath, gfid)
+
+	paths, err := gfid2Paths(brickPath, handle)
+	if err != nil || len(paths) > 0 {
+		return paths, err
+	}
+
+	var handleStat syscall.Stat_t
+	if err := syscall.Stat(handle, &handleStat); err != nil {
+		return nil, err
+	}
+
+	err = filepath.Walk(brickPath, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if p == path.Join(brickPath, ".glusterfs") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		st, ok := info.Sys().(*syscall.Stat_t)
+		if !ok || st.Ino != handleStat.Ino || st.Dev != handleStat.Dev {
+			return nil
+		}
+		rel, err := filepath.Rel(brickPath, p)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, "/"+rel)
+		return nil
+	})
+	return paths, err
+}
+
+// gfid2Paths returns the paths of the links of the object from its gfid2path
+// xattrs, whose values are of the form "<parent gfid>/<name>"
+func gfid2Paths(brickPath, handle string) ([]string, error) {
+	size, err := unix.Llistxattr(handle, nil)
+	if err != nil || size == 0 {
+		return nil, err
+	}
+	names := make([]byte, size)
+	if size, err = unix.Llistxattr(handle, names); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, name := range bytes.Split(names[:size], []byte{0}) {
+		if !bytes.HasPrefix(name, []byte(gfid2PathXattrPrefix)) {
+			continue
+		}
+		value := make([]byte, 512)
+		n, err := unix.Lgetxattr(handle, string(name), value)
+		if err != nil {
+			return nil, err
+		}
+		sep := bytes.IndexByte(value[:n], '/')
+		if sep < 0 {
+			continue
+		}
+		pgfid, base := string(value[:sep]), string(bytes.TrimRight(value[sep+1:n], "\x00"))
+
+		// The GFID handles of directories are symlinks into their
+		// parent directories
+		dir := brickPath
+		if pgfid != rootGFID {
+			if dir, err = filepath.EvalSymlinks(gfidHandle(brickPath, pgfid)); err != nil {
+				return nil, err
+			}
+		}
+		rel, err := filepath.Rel(brickPath, path.Join(dir, base))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, "/"+rel)
+	}
+	return paths, nil
+}
+
+// txnBitrotRepairFind finds the local bricks of the volume on which the
+// object is marked corrupted, and saves the paths of the object on them as
+// the result of this node
+func txnBitrotRepairFind(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var gfid string
+	if err := c.Get("gfid", &gfid); err != nil {
+		return err
+	}
+
+	badCopies := make(map[string][]string)
+	for _, b := range volinfo.GetLocalBricks() {
+		handle := gfidHandle(b.Path, gfid)
+		if _, err := unix.Lgetxattr(handle, badFileXattrKey, nil); err != nil {
+			// Not present, or not corrupted on this brick
+			continue
+		}
+		paths, err := objectPaths(b.Path, gfid)
+		if err != nil {
+			c.Logger().WithError(err).WithField("brick", b.String()).Error("failed to find paths of corrupted object")
+			return err
+		}
+		badCopies[b.ID.String()] = paths
+	}
+
+	return c.SetNodeResult(gdctx.MyUUID, badCopiesTxnKey, badCopies)
+}
+
+// txnBitrotRepairRemove removes the corrupted copies of the object from the
+// local bricks, so that they are healed from a good copy
+func txnBitrotRepairRemove(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var gfid string
+	if err := c.Get("gfid", &gfid); err != nil {
+		return err
+	}
+
+	var badCopies map[string][]string
+	if err := c.Get(badCopiesTxnKey, &badCopies); err != nil {
+		return err
+	}
+
+	for _, b := range volinfo.GetLocalBricks() {
+		paths, ok := badCopies[b.ID.String()]
+		if !ok {
+			continue
+		}
+
+		toRemove := []string{
+			gfidHandle(b.Path, gfid),
+			// The scrubber links the corrupted objects here
+			path.Join(b.Path, ".glusterfs", "quarantine", gfid),
+		}
+		for _, p := range paths {
+			toRemove = append(toRemove, path.Join(b.Path, p))
+		}
+
+		for _, p := range toRemove {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				c.Logger().WithError(err).WithField("path", p).Error("failed to remove corrupted object")
+				return err
+			}
+		}
+		c.Logger().WithField("brick", b.String()).WithField("gfid", gfid).Info("removed corrupted object from brick")
+	}
+	return nil
+}
+
+// txnBitrotRepairHeal looks up the paths of the object through a mount of
+// the volume, which makes the replicate or disperse xlator heal the removed
+// copies from a good copy
+func txnBitrotRepairHeal(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var paths []string
+	if err := c.Get("paths", &paths); err != nil {
+		return err
+	}
+
+	tempDir, err := ioutil.TempDir(config.GetString("rundir"), "bitrot-repair")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempDir)
+
+	logfile := path.Join(config.GetString("logdir"), "glusterfs", "bitrot-repair.log")
+	if err := mountVolume(volinfo.Name, tempDir, logfile); err != nil {
+		c.Logger().WithError(err).WithField("volume", volinfo.Name).Error("failed to mount volume")
+		return err
+	}
+	defer syscall.Unmount(tempDir, syscall.MNT_FORCE)
+
+	for _, p := range paths {
+		if _, err := os.Lstat(path.Join(tempDir, p)); err != nil {
+			c.Logger().WithError(err).WithField("path", p).Error("failed to lookup corrupted object")
+			return err
+		}
+	}
+	return nil
+}
+
+func mountVolume(volname, mountpoint, logfile string) error {
+	shost, sport, err := net.SplitHostPort(config.GetString("clientaddress"))
+	if err != nil {
+		return err
+	}
+
+	if shost == "" {
+		shost = "127.0.0.1"
+	}
+
+	cmd := exec.Command("glusterfs",
+		"--volfile-server", shost,
+		"--volfile-server-port", sport,
+		"--volfile-id", volname,
+		"--log-file", logfile,
+		mountpoint)
+	return cmd.Run() // glusterfs daemonizes itself
+}
+
+// hasGoodCopy returns true if every subvolume of the volume having corrupted
+// copies of an object has enough good copies to heal them from. badCopies
+// is keyed by the IDs of the bricks having the corrupted copies.
+func hasGoodCopy(volinfo *volume.Volinfo, badCopies map[string][]string) bool {
+	for _, subvol := range volinfo.Subvols {
+		var data, bad int
+		for _, b := range subvol.Bricks {
+			// Arbiter bricks don't have the data of objects
+			if b.Type != brick.Brick {
+				continue
+			}
+			data++
+			if _, ok := badCopies[b.ID.String()]; ok {
+				bad++
+			}
+		}
+		if bad == 0 {
+			continue
+		}
+
+		switch subvol.Type {
+		case volume.SubvolReplicate:
+			if bad >= data {
+				return false
+			}
+		case volume.SubvolDisperse:
+			if bad > subvol.RedundancyCount {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
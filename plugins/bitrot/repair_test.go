@@ -0,0 +1,68 @@
+package bitrot
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasGoodCopy(t *testing.T) {
+	newBricks := func(types ...brick.Type) []brick.Brickinfo {
+		var bricks []brick.Brickinfo
+		for _, typ := range types {
+			bricks = append(bricks, brick.Brickinfo{ID: uuid.NewRandom(), Type: typ})
+		}
+		return bricks
+	}
+	replica := volume.Subvol{Type: volume.SubvolReplicate, Bricks: newBricks(brick.Brick, brick.Brick, brick.Arbiter)}
+	disperse := volume.Subvol{Type: volume.SubvolDisperse, RedundancyCount: 1, Bricks: newBricks(brick.Brick, brick.Brick, brick.Brick)}
+	distribute := volume.Subvol{Type: volume.SubvolDistribute, Bricks: newBricks(brick.Brick)}
+	volinfo := &volume.Volinfo{Subvols: []volume.Subvol{replica, disperse, distribute}}
+
+	bad := func(bricks ...brick.Brickinfo) map[string][]string {
+		badCopies := make(map[string][]string)
+		for _, b := range bricks {
+			badCopies[b.ID.String()] = []string{"/file"}
+		}
+		return badCopies
+	}
+
+	assert.True(t, hasGoodCopy(volinfo, bad()))
+	assert.True(t, hasGoodCopy(volinfo, bad(replica.Bricks[0])))
+	assert.False(t, hasGoodCopy(volinfo, bad(replica.Bricks[0], replica.Bricks[1])))
+	assert.True(t, hasGoodCopy(volinfo, bad(disperse.Bricks[2])))
+	assert.False(t, hasGoodCopy(volinfo, bad(disperse.Bricks[0], disperse.Bricks[2])))
+	assert.False(t, hasGoodCopy(volinfo, bad(distribute.Bricks[0])))
+}
+
+func TestObjectPaths(t *testing.T) {
+	brickPath, err := ioutil.TempDir("", "gd2-bitrot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(brickPath)
+
+	gfid := uuid.NewRandom().String()
+	handle := gfidHandle(brickPath, gfid)
+	assert.NoError(t, os.MkdirAll(path.Dir(handle), 0755))
+	assert.NoError(t, os.MkdirAll(path.Join(brickPath, "dir"), 0755))
+
+	file := path.Join(brickPath, "dir", "file")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("data"), 0644))
+	assert.NoError(t, os.Link(file, handle))
+	assert.NoError(t, os.Link(file, path.Join(brickPath, "link")))
+	assert.NoError(t, ioutil.WriteFile(path.Join(brickPath, "other"), []byte("data"), 0644))
+
+	// Without gfid2path xattrs, the links are found by the inode
+	paths, err := objectPaths(brickPath, gfid)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/dir/file", "/link"}, paths)
+
+	_, err = objectPaths(brickPath, uuid.NewRandom().String())
+	assert.True(t, os.IsNotExist(err))
+}
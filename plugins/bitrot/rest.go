@@ -1,10 +1,13 @@
 package bitrot
 
 import (
+	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/ioops"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volume"
@@ -21,7 +24,7 @@ func bitrotEnableHandler(w http.ResponseWriter, r *http.Request) {
 	volname := mux.Vars(r)["volname"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
@@ -105,7 +108,7 @@ func bitrotDisableHandler(w http.ResponseWriter, r *http.Request) {
 	volname := mux.Vars(r)["volname"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
@@ -171,34 +174,46 @@ func bitrotScrubOndemandHandler(w http.ResponseWriter, r *http.Request) {
 	volname := mux.Vars(r)["volname"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
 
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
-	if err != nil {
+	switch err := startScrub(ctx, volname); err {
+	case nil:
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+	case ioops.ErrQueued:
+		restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, nil)
+	case errors.ErrVolNotStarted, errors.ErrBitrotNotEnabled:
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+	default:
 		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
-		return
+	}
+}
+
+// startScrub starts scrubbing the volume on demand. The scrub isn't started
+// together with the other IO intensive operations on the bricks of the
+// volume, and ioops.ErrQueued is returned if it has been queued behind them.
+func startScrub(ctx context.Context, volname string) error {
+	logger := gdctx.Logger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return err
 	}
 	defer txn.Done()
 
 	// Validate volume existence
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return err
 	}
 
 	// Check if volume is started
 	if volinfo.State != volume.VolStarted {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
-		return
+		return errors.ErrVolNotStarted
 	}
 
 	// Check if bitrot is disabled
 	if !isBitrotEnabled(volinfo) {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrBitrotNotEnabled)
-		return
+		return errors.ErrBitrotNotEnabled
 	}
 
 	txn.Nodes = volinfo.Nodes()
@@ -209,17 +224,22 @@ func bitrotScrubOndemandHandler(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 	if err = txn.Ctx.Set("volname", volname); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return err
+	}
+
+	if err := ioops.Acquire(ctx, ioops.OpScrub, volinfo, nil); err != nil {
+		return err
 	}
 
 	if err = txn.Do(); err != nil {
 		logger.WithError(err).WithField("volname",
 			volinfo.Name).Error("failed to start scrubber")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		if err := ioops.Release(ioops.OpScrub, volname); err != nil {
+			logger.WithError(err).Warn("failed to release bricks claimed for scrub")
+		}
+		return err
 	}
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+	return nil
 }
 
 func bitrotScrubStatusHandler(w http.ResponseWriter, r *http.Request) {
@@ -227,7 +247,7 @@ func bitrotScrubStatusHandler(w http.ResponseWriter, r *http.Request) {
 	volname := mux.Vars(r)["volname"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
@@ -293,6 +313,353 @@ func bitrotScrubStatusHandler(w http.ResponseWriter, r *http.Request) {
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, result)
 }
 
+func bitrotScrubConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req bitrotapi.ScrubConfigReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	options := make(map[string]string)
+	if req.Frequency != "" {
+		options[keyScrubFrequency] = req.Frequency
+	}
+	if req.Throttle != "" {
+		options[keyScrubThrottle] = req.Throttle
+	}
+	if len(options) == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "no scrub frequency or throttle specified")
+		return
+	}
+
+	sendScrubOptions(w, r, options)
+}
+
+func bitrotScrubPauseHandler(w http.ResponseWriter, r *http.Request) {
+	sendScrubOptions(w, r, map[string]string{keyScrubState: "pause"})
+}
+
+func bitrotScrubResumeHandler(w http.ResponseWriter, r *http.Request) {
+	sendScrubOptions(w, r, map[string]string{keyScrubState: "resume"})
+}
+
+// sendScrubOptions validates and sets the scrubber options on the volume
+// of the request
+func sendScrubOptions(w http.ResponseWriter, r *http.Request, options map[string]string) {
+	// Collect inputs from URL
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+
+	for key, value := range options {
+		// The validation function takes the option name without the
+		// xlator prefix
+		if err := validateOptions(nil, strings.TrimPrefix(key, "bit-rot."), value); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	switch err := setScrubOptions(ctx, volname, options); err {
+	case nil:
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+	case errors.ErrBitrotNotEnabled:
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+	default:
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+	}
+}
+
+// setScrubOptions sets the scrubber options on the volume and regenerates
+// the bitd and scrubd volfiles, which the daemons are notified to refetch
+func setScrubOptions(ctx context.Context, volname string, options map[string]string) error {
+	logger := gdctx.Logger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	// Validate volume existence
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return err
+	}
+
+	// Check if bitrot is disabled
+	if !isBitrotEnabled(volinfo) {
+		return errors.ErrBitrotNotEnabled
+	}
+
+	//save volume information for transaction failure scenario
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		return err
+	}
+
+	for key, value := range options {
+		volinfo.Options[key] = value
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return err
+	}
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+		},
+		{
+			DoFunc:   "bitrot-scrubconfig.Commit",
+			UndoFunc: "bitrot-scrubconfig.Undo",
+			Nodes:    txn.Nodes,
+			// Volinfo needs to be updated before the volfiles are
+			// generated
+			Sync: true,
+		},
+		{
+			DoFunc: "vol-option.NotifyVolfileChange",
+			Nodes:  txn.Nodes,
+			Sync:   true,
+		},
+	}
+
+	if err = txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname",
+			volinfo.Name).Error("failed to configure scrubber")
+		return err
+	}
+	return nil
+}
+
+func bitrotCorruptedHandler(w http.ResponseWriter, r *http.Request) {
+	// Collect inputs from URL
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	// Validate volume existence
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// Check if volume is started
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	// Check if bitrot is disabled
+	if !isBitrotEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrBitrotNotEnabled)
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	// Some nodes may not be up, which is okay.
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	// The corrupted objects are got from the scrub status of the
+	// scrubbers
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "bitrot-scrubstatus.Commit",
+			Nodes:  txn.Nodes,
+		},
+	}
+	if err = txn.Ctx.Set("volname", volname); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname",
+			volinfo.Name).Error("failed to get corrupted objects")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := bitrotapi.CorruptedObjects{
+		Volume:  volinfo.Name,
+		Objects: []bitrotapi.CorruptedObject{},
+	}
+	index := make(map[string]int)
+	for _, node := range txn.Nodes {
+		var nodeInfo bitrotapi.ScrubNodeInfo
+		if err := txn.Ctx.GetNodeResult(node, scrubStatusTxnKey, &nodeInfo); err != nil {
+			// skip if we do not have information
+			continue
+		}
+		for _, gfid := range nodeInfo.CorruptedObjects {
+			i, ok := index[gfid]
+			if !ok {
+				i = len(resp.Objects)
+				index[gfid] = i
+				resp.Objects = append(resp.Objects, bitrotapi.CorruptedObject{GFID: gfid})
+			}
+			resp.Objects[i].Nodes = append(resp.Objects[i].Nodes, nodeInfo.Node)
+		}
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func bitrotRepairHandler(w http.ResponseWriter, r *http.Request) {
+	// Collect inputs from URL
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	id := uuid.Parse(mux.Vars(r)["gfid"])
+	if id == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid gfid")
+		return
+	}
+	gfid := id.String()
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	// Validate volume existence
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// Check if volume is started
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	// Check if bitrot is disabled
+	if !isBitrotEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrBitrotNotEnabled)
+		return
+	}
+
+	// Find the bricks having the corrupted copies of the object
+	findTxn := transaction.NewTxn(ctx)
+	defer findTxn.Done()
+
+	for key, value := range map[string]interface{}{"volinfo": volinfo, "gfid": gfid} {
+		if err := findTxn.Ctx.Set(key, value); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	findTxn.Nodes = volinfo.Nodes()
+	findTxn.Steps = []*transaction.Step{
+		{
+			DoFunc: "bitrot-repair.Find",
+			Nodes:  findTxn.Nodes,
+		},
+	}
+
+	if err := findTxn.Do(); err != nil {
+		logger.WithError(err).WithField("gfid", gfid).Error("failed to find corrupted object")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	badCopies := make(map[string][]string)
+	var badNodes []uuid.UUID
+	for _, node := range findTxn.Nodes {
+		var nodeCopies map[string][]string
+		if err := findTxn.Ctx.GetNodeResult(node, badCopiesTxnKey, &nodeCopies); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		if len(nodeCopies) > 0 {
+			badNodes = append(badNodes, node)
+		}
+		for id, paths := range nodeCopies {
+			badCopies[id] = paths
+		}
+	}
+
+	if len(badCopies) == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrBitrotObjectNotCorrupted)
+		return
+	}
+	if !hasGoodCopy(volinfo, badCopies) {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, errors.ErrBitrotNoGoodCopy)
+		return
+	}
+
+	resp := bitrotapi.RepairResp{GFID: gfid}
+	seen := make(map[string]bool)
+	for _, b := range volinfo.GetBricks() {
+		paths, ok := badCopies[b.ID.String()]
+		if !ok {
+			continue
+		}
+		resp.Bricks = append(resp.Bricks, b.String())
+		for _, p := range paths {
+			if !seen[p] {
+				seen[p] = true
+				resp.Paths = append(resp.Paths, p)
+			}
+		}
+	}
+
+	for key, value := range map[string]interface{}{
+		"volinfo":       volinfo,
+		"gfid":          gfid,
+		badCopiesTxnKey: badCopies,
+		"paths":         resp.Paths,
+	} {
+		if err := txn.Ctx.Set(key, value); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "bitrot-repair.Remove",
+			Nodes:  badNodes,
+			Sync:   true,
+		},
+		{
+			DoFunc: "bitrot-repair.Heal",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+		},
+	}
+
+	if err = txn.Do(); err != nil {
+		logger.WithError(err).WithField("gfid", gfid).Error("failed to repair corrupted object")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
 func createScrubStatusResp(ctx transaction.TxnCtx, volinfo *volume.Volinfo) (*bitrotapi.ScrubStatus, error) {
 
 	var resp bitrotapi.ScrubStatus
@@ -300,6 +667,9 @@ func createScrubStatusResp(ctx transaction.TxnCtx, volinfo *volume.Volinfo) (*bi
 	// Fill generic info which are same for each node
 	resp.Volume = volinfo.Name
 	resp.State = "Active (Idle)"
+	if volinfo.Options[keyScrubState] == "pause" {
+		resp.State = "Paused"
+	}
 	resp.Frequency, exists = volinfo.Options[keyScrubFrequency]
 	if !exists {
 		// If not available in Options, it's not set. Use default value
@@ -248,3 +248,41 @@ func txnBitrotScrubStatus(c transaction.TxnCtx) error {
 	c.SetNodeResult(gdctx.MyUUID, scrubStatusTxnKey, scrubNodeInfo)
 	return nil
 }
+
+// regenerateVolfiles regenerates the bitd and scrubd volfiles with the
+// volinfo stored in the transaction context under key
+func regenerateVolfiles(c transaction.TxnCtx, key string) error {
+	var volinfo volume.Volinfo
+	if err := c.Get(key, &volinfo); err != nil {
+		c.Logger().WithError(err).WithField(
+			"key", key).Error("failed to get value for key from context")
+		return err
+	}
+
+	// The daemons aren't running on this node
+	if !IsBitrotAffectedNode(&volinfo) {
+		return nil
+	}
+
+	bitrotDaemon, err := newBitd()
+	if err != nil {
+		return err
+	}
+	if err := volgen.ClusterVolfileToFile(&volinfo, bitrotDaemon.VolfileID, "bitd"); err != nil {
+		return err
+	}
+
+	scrubDaemon, err := newScrubd()
+	if err != nil {
+		return err
+	}
+	return volgen.ClusterVolfileToFile(&volinfo, scrubDaemon.VolfileID, "scrubd")
+}
+
+func txnBitrotScrubConfig(c transaction.TxnCtx) error {
+	return regenerateVolfiles(c, "volinfo")
+}
+
+func txnBitrotScrubConfigUndo(c transaction.TxnCtx) error {
+	return regenerateVolfiles(c, "oldvolinfo")
+}
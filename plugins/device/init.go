@@ -19,8 +19,12 @@ func (p *Plugin) Name() string {
 // RestRoutes returns list of REST API routes to register with Glusterd.
 func (p *Plugin) RestRoutes() route.Routes {
 	return route.Routes{
+		// DeviceAdd and DevicesInPeer are deprecated in favour of
+		// PeerDeviceAdd and PeerDevices, and are kept for the clients of
+		// the v1 API
 		route.Route{
 			Name:         "DeviceAdd",
+			Description:  "Deprecated, use PeerDeviceAdd",
 			Method:       "POST",
 			Pattern:      "/devices/{peerid}",
 			Version:      1,
@@ -36,6 +40,7 @@ func (p *Plugin) RestRoutes() route.Routes {
 			HandlerFunc:  deviceListHandler},
 		route.Route{
 			Name:         "DevicesInPeer",
+			Description:  "Deprecated, use PeerDevices",
 			Method:       "GET",
 			Pattern:      "/devices/{peerid}",
 			Version:      1,
@@ -55,6 +60,22 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Version:      1,
 			ResponseType: utils.GetTypeString((*deviceapi.ListDeviceResp)(nil)),
 			HandlerFunc:  deviceListHandler},
+		// The devices of a peer are also served under the peer
+		route.Route{
+			Name:         "PeerDeviceAdd",
+			Method:       "POST",
+			Pattern:      "/peers/{peerid}/devices",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*deviceapi.AddDeviceReq)(nil)),
+			ResponseType: utils.GetTypeString((*deviceapi.AddDeviceResp)(nil)),
+			HandlerFunc:  deviceAddHandler},
+		route.Route{
+			Name:         "PeerDevices",
+			Method:       "GET",
+			Pattern:      "/peers/{peerid}/devices",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*deviceapi.ListDeviceResp)(nil)),
+			HandlerFunc:  deviceListHandler},
 	}
 }
 
@@ -20,7 +20,7 @@ import (
 func deviceAddHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	peerID := mux.Vars(r)["peerid"]
 	if uuid.Parse(peerID) == nil {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peer-id passed in url")
@@ -65,6 +65,11 @@ func deviceAddHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if peerInfo.IsManagementOnly() {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "devices cannot be added to management nodes")
+		return
+	}
+
 	txn.Nodes = []uuid.UUID{peerInfo.ID}
 	txn.Steps = []*transaction.Step{
 		{
@@ -107,7 +112,7 @@ func deviceAddHandler(w http.ResponseWriter, r *http.Request) {
 func deviceListHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	peerID := mux.Vars(r)["peerid"]
 	if peerID != "" && uuid.Parse(peerID) == nil {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peer-id passed in url")
@@ -146,7 +151,7 @@ func deviceListHandler(w http.ResponseWriter, r *http.Request) {
 func deviceEditHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 	peerID := mux.Vars(r)["peerid"]
 	if uuid.Parse(peerID) == nil {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peer-id passed in url")
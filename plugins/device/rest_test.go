@@ -0,0 +1,62 @@
+package device
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter() *mux.Router {
+	router := mux.NewRouter()
+	for _, route := range new(Plugin).RestRoutes() {
+		router.Methods(route.Method).Path("/v1" + route.Pattern).Name(route.Name).HandlerFunc(route.HandlerFunc)
+	}
+	return router
+}
+
+// TestPeerDeviceRoutes checks that the devices of a peer are served the same
+// way under /peers/{peerid}/devices and the deprecated /devices/{peerid}
+func TestPeerDeviceRoutes(t *testing.T) {
+	router := newTestRouter()
+	peerID := uuid.NewRandom().String()
+
+	for _, tc := range []struct {
+		method, oldPath, newPath string
+	}{
+		{"POST", "/v1/devices/" + peerID, "/v1/peers/" + peerID + "/devices"},
+		{"GET", "/v1/devices/" + peerID, "/v1/peers/" + peerID + "/devices"},
+	} {
+		var oldMatch, newMatch mux.RouteMatch
+		require.True(t, router.Match(httptest.NewRequest(tc.method, tc.oldPath, nil), &oldMatch), tc.oldPath)
+		require.True(t, router.Match(httptest.NewRequest(tc.method, tc.newPath, nil), &newMatch), tc.newPath)
+
+		assert.Equal(t, map[string]string{"peerid": peerID}, oldMatch.Vars)
+		assert.Equal(t, oldMatch.Vars, newMatch.Vars)
+		assert.Equal(t,
+			reflect.ValueOf(oldMatch.Handler).Pointer(),
+			reflect.ValueOf(newMatch.Handler).Pointer(),
+			tc.method+" "+tc.newPath)
+	}
+
+	// Invalid peer IDs are rejected by both
+	for _, tc := range []struct {
+		method, path string
+	}{
+		{"POST", "/v1/devices/not-a-peer"},
+		{"POST", "/v1/peers/not-a-peer/devices"},
+		{"GET", "/v1/devices/not-a-peer"},
+		{"GET", "/v1/peers/not-a-peer/devices"},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"device": "/dev/sdb"}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, tc.method+" "+tc.path)
+		assert.Contains(t, w.Body.String(), "invalid peer-id", tc.method+" "+tc.path)
+	}
+}
@@ -3,13 +3,70 @@ package api
 // Webhook is Structure to represent a webhook that will be used
 // for posting events
 type Webhook struct {
-	URL    string `json:"url"`
-	Token  string `json:"token"`
-	Secret string `json:"secret"`
+	URL    string `json:"url" valid:"required"`
+	Token  string `json:"token" store:"encrypt"`
+	Secret string `json:"secret" store:"encrypt"`
 }
 
 // WebhookDel is Structure to represent a webhook that will be used
 // for deleting webhook
 type WebhookDel struct {
-	URL string `json:"url"`
+	URL string `json:"url" valid:"required"`
+}
+
+// Sink types supported by glusterd
+const (
+	SinkSMTP       = "smtp"
+	SinkSlack      = "slack"
+	SinkMattermost = "mattermost"
+	SinkSyslog     = "syslog"
+)
+
+// Event severities, in increasing order
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// SMTPConfig is the configuration of a sink sending events as mail
+type SMTPConfig struct {
+	// Server is the host:port of the SMTP server
+	Server   string   `json:"server"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty" store:"encrypt"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// SyslogConfig is the configuration of a sink forwarding events to syslog
+type SyslogConfig struct {
+	// Network and Address of the syslog server. The local syslog
+	// daemon is used if Network is empty.
+	Network string `json:"network,omitempty"`
+	Address string `json:"address,omitempty"`
+	Tag     string `json:"tag,omitempty"`
+}
+
+// Sink is Structure to represent a built-in notification sink to which
+// events are sent
+type Sink struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Severity is the minimum severity of the events sent to the sink,
+	// defaults to SeverityInfo
+	Severity string `json:"severity,omitempty"`
+	// Events, if set, restricts the sink to the events with these names
+	Events []string `json:"events,omitempty"`
+	// URL is the incoming webhook URL of Slack and Mattermost sinks
+	URL     string        `json:"url,omitempty" store:"encrypt"`
+	Channel string        `json:"channel,omitempty"`
+	SMTP    *SMTPConfig   `json:"smtp,omitempty"`
+	Syslog  *SyslogConfig `json:"syslog,omitempty"`
+}
+
+// SinkDel is Structure to represent a sink that will be used for deleting
+// the sink
+type SinkDel struct {
+	Name string `json:"name" valid:"required"`
 }
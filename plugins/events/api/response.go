@@ -9,3 +9,6 @@ type WebhookList []string
 
 // EventList holds list of events happened in last 10 mins(configurable)
 type EventList []api.Event
+
+// SinkList holds list of notification sinks, without their credentials
+type SinkList []Sink
@@ -0,0 +1,41 @@
+package events
+
+import (
+	gd2events "github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/exporter"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+)
+
+// exportersNotifier feeds the events to the exporters
+type exportersNotifier struct{}
+
+func (x *exportersNotifier) Handle(e *api.Event) {
+	// Every peer exports the events it originates
+	if !uuid.Equal(e.Origin, gdctx.MyUUID) {
+		return
+	}
+
+	exporter.Export(&exporter.Record{
+		Source: api.ExportSourceEvents,
+		ID:     e.ID.String(),
+		Name:   e.Name,
+		// The severities of events and exported records have the
+		// same names
+		Severity: eventSeverity(e.Name),
+		Time:     e.Timestamp,
+		Origin:   e.Origin,
+		Host:     gdctx.HostName,
+		Data:     e.Data,
+	})
+}
+
+func (x *exportersNotifier) Events() []string {
+	return []string{}
+}
+
+func init() {
+	gd2events.Register(new(exportersNotifier))
+}
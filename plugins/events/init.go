@@ -24,21 +24,21 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Method:      "POST",
 			Pattern:     "/events/webhook",
 			Version:     1,
-			RequestType: utils.GetTypeString((*eventsapi.Webhook)(nil)),
+			RequestBody: (*eventsapi.Webhook)(nil),
 			HandlerFunc: webhookAddHandler},
 		route.Route{
 			Name:        "EventsWebhookTest",
 			Method:      "POST",
 			Pattern:     "/events/webhook/test",
 			Version:     1,
-			RequestType: utils.GetTypeString((*eventsapi.Webhook)(nil)),
+			RequestBody: (*eventsapi.Webhook)(nil),
 			HandlerFunc: webhookTestHandler},
 		route.Route{
 			Name:        "EventsWebhookDelete",
 			Method:      "DELETE",
 			Pattern:     "/events/webhook",
 			Version:     1,
-			RequestType: utils.GetTypeString((*eventsapi.WebhookDel)(nil)),
+			RequestBody: (*eventsapi.WebhookDel)(nil),
 			HandlerFunc: webhookDeleteHandler},
 		route.Route{
 			Name:         "EventsWebhookList",
@@ -47,6 +47,27 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Version:      1,
 			ResponseType: utils.GetTypeString((*eventsapi.WebhookList)(nil)),
 			HandlerFunc:  webhookListHandler},
+		route.Route{
+			Name:        "EventsSinkAdd",
+			Method:      "POST",
+			Pattern:     "/events/sinks",
+			Version:     1,
+			RequestBody: (*eventsapi.Sink)(nil),
+			HandlerFunc: sinkAddHandler},
+		route.Route{
+			Name:        "EventsSinkDelete",
+			Method:      "DELETE",
+			Pattern:     "/events/sinks",
+			Version:     1,
+			RequestBody: (*eventsapi.SinkDel)(nil),
+			HandlerFunc: sinkDeleteHandler},
+		route.Route{
+			Name:         "EventsSinkList",
+			Method:       "GET",
+			Pattern:      "/events/sinks",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*eventsapi.SinkList)(nil)),
+			HandlerFunc:  sinkListHandler},
 		route.Route{
 			Name:    "EventsList",
 			Method:  "GET",
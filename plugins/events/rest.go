@@ -8,7 +8,6 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
-	"github.com/gluster/glusterd2/pkg/errors"
 	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
 )
 
@@ -19,18 +18,7 @@ const (
 func webhookAddHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	var req eventsapi.Webhook
-	if err := restutils.UnmarshalRequest(r, &req); err != nil {
-		restutils.SendHTTPError(
-			ctx, w, http.StatusBadRequest,
-			errors.ErrJSONParsingFailed)
-		return
-	}
-
-	if req.URL == "" {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Webhook Url is required field")
-		return
-	}
+	req := restutils.RequestBody(ctx).(*eventsapi.Webhook)
 
 	// Check if the webhook already exists
 	exists, err := webhookExists(req.URL)
@@ -45,7 +33,7 @@ func webhookAddHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := addWebhook(req); err != nil {
+	if err := addWebhook(*req); err != nil {
 		restutils.SendHTTPError(
 			ctx, w, http.StatusInternalServerError,
 			"Could not add webhook")
@@ -58,17 +46,7 @@ func webhookAddHandler(w http.ResponseWriter, r *http.Request) {
 func webhookDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	var req eventsapi.WebhookDel
-	if err := restutils.UnmarshalRequest(r, &req); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
-			errors.ErrJSONParsingFailed)
-		return
-	}
-
-	if req.URL == "" {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Webhook Url is required field")
-		return
-	}
+	req := restutils.RequestBody(ctx).(*eventsapi.WebhookDel)
 
 	// Check if the webhook already exists
 	exists, err := webhookExists(req.URL)
@@ -150,20 +128,9 @@ func registerWebhookTestStepFuncs() {
 
 func webhookTestHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
-	var req eventsapi.Webhook
+	logger := gdctx.Logger(ctx)
+	req := restutils.RequestBody(ctx).(*eventsapi.Webhook)
 
-	if err := restutils.UnmarshalRequest(r, &req); err != nil {
-		restutils.SendHTTPError(
-			ctx, w, http.StatusBadRequest,
-			errors.ErrJSONParsingFailed)
-		return
-	}
-
-	if req.URL == "" {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "webhook URL is required field")
-		return
-	}
 	allNodes, err := peer.GetPeerIDs()
 	if err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
@@ -180,7 +147,7 @@ func webhookTestHandler(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	if err := txn.Ctx.Set("req", &req); err != nil {
+	if err := txn.Ctx.Set("req", req); err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
@@ -194,3 +161,85 @@ func webhookTestHandler(w http.ResponseWriter, r *http.Request) {
 
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
 }
+
+func sinkAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := restutils.RequestBody(ctx).(*eventsapi.Sink)
+	if err := validateSink(req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	exists, err := sinkExists(req.Name)
+	if err != nil {
+		restutils.SendHTTPError(
+			ctx, w, http.StatusInternalServerError,
+			"Could not check if sink already exists")
+		return
+	}
+	if exists {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, "Sink already exists")
+		return
+	}
+
+	if err := addSink(*req); err != nil {
+		restutils.SendHTTPError(
+			ctx, w, http.StatusInternalServerError,
+			"Could not add sink")
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
+func sinkDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := restutils.RequestBody(ctx).(*eventsapi.SinkDel)
+
+	exists, err := sinkExists(req.Name)
+	if err != nil {
+		restutils.SendHTTPError(
+			ctx, w, http.StatusInternalServerError,
+			"Could not check if sink exists")
+		return
+	}
+	if !exists {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, "Sink does not exist")
+		return
+	}
+
+	if err := deleteSink(req.Name); err != nil {
+		restutils.SendHTTPError(
+			ctx, w, http.StatusInternalServerError,
+			"Could not delete sink")
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+func sinkListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sinks, err := GetSinkList()
+	if err != nil {
+		restutils.SendHTTPError(
+			ctx, w, http.StatusInternalServerError,
+			"Could not retrive sink list")
+		return
+	}
+
+	resp := eventsapi.SinkList{}
+	for _, sink := range sinks {
+		// Don't leak the credentials of the SMTP server
+		if sink.SMTP != nil {
+			smtp := *sink.SMTP
+			smtp.Password = ""
+			sink.SMTP = &smtp
+		}
+		resp = append(resp, *sink)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,252 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/syslog"
+	"net"
+	"net/http"
+	"net/smtp"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	gd2events "github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// sinkNameRE matches valid sink names, which are used in store keys
+var sinkNameRE = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+var severityLevels = map[string]int{
+	eventsapi.SeverityInfo:     0,
+	eventsapi.SeverityWarning:  1,
+	eventsapi.SeverityCritical: 2,
+}
+
+// Events don't carry a severity, it is derived from the words in their names.
+// Names of events from glusterfs are in upper case with words separated by
+// underscores, names of events from glusterd2 are in lower case with words
+// separated by dots and hyphens.
+var (
+	criticalKeywords = []string{"fail", "disconnect", "faulty", "lost", "reject",
+		"not-up", "bad-file", "split-brain", "engaged", "corrupt", "offline"}
+	warningKeywords = []string{"stop", "remove", "delete", "decommission", "pause",
+		"limit-reached", "crossed", "down", "degraded", "restarted"}
+)
+
+// eventSeverity returns the severity of the event with the given name
+func eventSeverity(name string) string {
+	name = strings.Replace(strings.ToLower(name), "_", "-", -1)
+	for _, k := range criticalKeywords {
+		if strings.Contains(name, k) {
+			return eventsapi.SeverityCritical
+		}
+	}
+	for _, k := range warningKeywords {
+		if strings.Contains(name, k) {
+			return eventsapi.SeverityWarning
+		}
+	}
+	return eventsapi.SeverityInfo
+}
+
+// sinkWants returns true if the event passes the filters of the sink
+func sinkWants(sink *eventsapi.Sink, e *api.Event) bool {
+	if len(sink.Events) != 0 {
+		found := false
+		for _, name := range sink.Events {
+			if name == e.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	min := sink.Severity
+	if min == "" {
+		min = eventsapi.SeverityInfo
+	}
+	return severityLevels[eventSeverity(e.Name)] >= severityLevels[min]
+}
+
+func validateSink(sink *eventsapi.Sink) error {
+	if !sinkNameRE.MatchString(sink.Name) {
+		return errors.New("invalid sink name")
+	}
+
+	if _, ok := severityLevels[sink.Severity]; sink.Severity != "" && !ok {
+		return fmt.Errorf("invalid severity %s", sink.Severity)
+	}
+
+	switch sink.Type {
+	case eventsapi.SinkSMTP:
+		if sink.SMTP == nil || sink.SMTP.Server == "" || sink.SMTP.From == "" || len(sink.SMTP.To) == 0 {
+			return errors.New("smtp server, from and to addresses are required")
+		}
+		if _, _, err := net.SplitHostPort(sink.SMTP.Server); err != nil {
+			return fmt.Errorf("invalid smtp server: %s", err)
+		}
+	case eventsapi.SinkSlack, eventsapi.SinkMattermost:
+		if sink.URL == "" {
+			return errors.New("webhook URL is required")
+		}
+	case eventsapi.SinkSyslog:
+		if sink.Syslog != nil && sink.Syslog.Network != "" && sink.Syslog.Address == "" {
+			return errors.New("syslog address is required along with network")
+		}
+	default:
+		return fmt.Errorf("unsupported sink type %s", sink.Type)
+	}
+
+	return nil
+}
+
+// formatEvent returns a single line description of the event
+func formatEvent(e *api.Event) string {
+	keys := make([]string, 0, len(e.Data))
+	for k := range e.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "[%s] %s", strings.ToUpper(eventSeverity(e.Name)), e.Name)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%s", k, e.Data[k])
+	}
+	return b.String()
+}
+
+func smtpPublish(conf *eventsapi.SMTPConfig, e *api.Event) error {
+	var auth smtp.Auth
+	if conf.Username != "" {
+		host, _, err := net.SplitHostPort(conf.Server)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", conf.Username, conf.Password, host)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", conf.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(conf.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [gluster] %s\r\n", e.Name)
+	fmt.Fprintf(&msg, "Date: %s\r\n\r\n", e.Timestamp.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "%s\r\n\r\nEvent ID: %s\r\nOrigin: %s\r\n", formatEvent(e), e.ID, e.Origin)
+
+	return smtp.SendMail(conf.Server, auth, conf.From, conf.To, msg.Bytes())
+}
+
+// chatPublish posts the event to the incoming webhook of Slack or Mattermost,
+// both of which accept the same message format
+func chatPublish(sink *eventsapi.Sink, e *api.Event) error {
+	message := map[string]string{
+		"username": "glusterd2",
+		"text":     formatEvent(e),
+	}
+	if sink.Channel != "" {
+		message["channel"] = sink.Channel
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Post(sink.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s webhook returned %s", sink.Type, resp.Status)
+	}
+	return nil
+}
+
+func syslogPublish(conf *eventsapi.SyslogConfig, e *api.Event) error {
+	var network, address string
+	tag := "glusterd2"
+	if conf != nil {
+		network, address = conf.Network, conf.Address
+		if conf.Tag != "" {
+			tag = conf.Tag
+		}
+	}
+
+	w, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	msg := formatEvent(e)
+	switch eventSeverity(e.Name) {
+	case eventsapi.SeverityCritical:
+		return w.Crit(msg)
+	case eventsapi.SeverityWarning:
+		return w.Warning(msg)
+	}
+	return w.Info(msg)
+}
+
+// SinkPublish sends the event to the sink
+func SinkPublish(sink *eventsapi.Sink, e *api.Event) error {
+	switch sink.Type {
+	case eventsapi.SinkSMTP:
+		return smtpPublish(sink.SMTP, e)
+	case eventsapi.SinkSlack, eventsapi.SinkMattermost:
+		return chatPublish(sink, e)
+	case eventsapi.SinkSyslog:
+		return syslogPublish(sink.Syslog, e)
+	}
+	return fmt.Errorf("unsupported sink type %s", sink.Type)
+}
+
+type sinksNotifier struct{}
+
+func (s *sinksNotifier) Handle(e *api.Event) {
+	//send events only from originator node
+	if !uuid.Equal(e.Origin, gdctx.MyUUID) {
+		return
+	}
+
+	sinks, err := GetSinkList()
+	if err != nil {
+		log.WithError(err).Error("error retriving sink list from etcd")
+		return
+	}
+
+	for _, sink := range sinks {
+		if !sinkWants(sink, e) {
+			continue
+		}
+		go func(e *api.Event, sink *eventsapi.Sink) {
+			if err := SinkPublish(sink, e); err != nil {
+				log.WithError(err).WithField("sink", sink.Name).Error("error in sending event to sink")
+			}
+		}(e, sink)
+	}
+}
+
+func (s *sinksNotifier) Events() []string {
+	return []string{}
+}
+
+func init() {
+	gd2events.Register(new(sinksNotifier))
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventSeverity(t *testing.T) {
+	assert.Equal(t, eventsapi.SeverityCritical, eventSeverity("volume.autoexpand-failed"))
+	assert.Equal(t, eventsapi.SeverityCritical, eventSeverity("BRICK_DISCONNECTED"))
+	assert.Equal(t, eventsapi.SeverityCritical, eventSeverity("EC_MIN_BRICKS_NOT_UP"))
+	assert.Equal(t, eventsapi.SeverityWarning, eventSeverity("volume.stopped"))
+	assert.Equal(t, eventsapi.SeverityWarning, eventSeverity("SNAPSHOT_SOFT_LIMIT_REACHED"))
+	assert.Equal(t, eventsapi.SeverityInfo, eventSeverity("volume.created"))
+	assert.Equal(t, eventsapi.SeverityInfo, eventSeverity("EC_MIN_BRICKS_UP"))
+}
+
+func TestSinkWants(t *testing.T) {
+	sink := &eventsapi.Sink{Name: "alerts", Type: eventsapi.SinkSyslog}
+	assert.True(t, sinkWants(sink, &api.Event{Name: "volume.created"}))
+
+	sink.Severity = eventsapi.SeverityWarning
+	assert.False(t, sinkWants(sink, &api.Event{Name: "volume.created"}))
+	assert.True(t, sinkWants(sink, &api.Event{Name: "volume.stopped"}))
+	assert.True(t, sinkWants(sink, &api.Event{Name: "QUORUM_LOST"}))
+
+	sink.Events = []string{"QUORUM_LOST"}
+	assert.False(t, sinkWants(sink, &api.Event{Name: "volume.stopped"}))
+	assert.True(t, sinkWants(sink, &api.Event{Name: "QUORUM_LOST"}))
+}
+
+func TestValidateSink(t *testing.T) {
+	assert.Error(t, validateSink(&eventsapi.Sink{Name: "a/b", Type: eventsapi.SinkSyslog}))
+	assert.Error(t, validateSink(&eventsapi.Sink{Name: "s", Type: "pager"}))
+	assert.Error(t, validateSink(&eventsapi.Sink{Name: "s", Type: eventsapi.SinkSyslog, Severity: "fatal"}))
+	assert.NoError(t, validateSink(&eventsapi.Sink{Name: "s", Type: eventsapi.SinkSyslog}))
+
+	assert.Error(t, validateSink(&eventsapi.Sink{Name: "s", Type: eventsapi.SinkSlack}))
+	assert.NoError(t, validateSink(&eventsapi.Sink{Name: "s", Type: eventsapi.SinkMattermost, URL: "http://chat/hooks/x"}))
+
+	smtp := &eventsapi.SMTPConfig{Server: "mail", From: "gd2@example.com", To: []string{"admin@example.com"}}
+	assert.Error(t, validateSink(&eventsapi.Sink{Name: "s", Type: eventsapi.SinkSMTP, SMTP: smtp}))
+	smtp.Server = "mail:25"
+	assert.NoError(t, validateSink(&eventsapi.Sink{Name: "s", Type: eventsapi.SinkSMTP, SMTP: smtp}))
+}
@@ -16,6 +16,7 @@ import (
 
 const (
 	webhookPrefix string = "config/events/webhooks/"
+	sinkPrefix           = "config/events/sinks/"
 	eventsPrefix         = "events/"
 )
 
@@ -43,7 +44,7 @@ func GetWebhookList() ([]*eventsapi.Webhook, error) {
 	for i, kv := range resp.Kvs {
 		var wh eventsapi.Webhook
 
-		if err := json.Unmarshal(kv.Value, &wh); err != nil {
+		if err := store.Unmarshal(kv.Value, &wh); err != nil {
 			log.WithError(err).WithField("webhook", string(kv.Key)).Error("Failed to unmarshal webhook")
 			continue
 		}
@@ -55,7 +56,7 @@ func GetWebhookList() ([]*eventsapi.Webhook, error) {
 }
 
 func addWebhook(webhook eventsapi.Webhook) error {
-	wh, e := json.Marshal(webhook)
+	wh, e := store.Marshal(webhook)
 	if e != nil {
 		log.WithError(e).Error("Failed to marshal the webhook object")
 		return e
@@ -74,6 +75,57 @@ func deleteWebhook(webhookURL string) error {
 	return e
 }
 
+func sinkExists(name string) (bool, error) {
+	resp, e := store.Get(context.TODO(), sinkPrefix+name)
+	if e != nil {
+		log.WithError(e).Error("Couldn't retrive sink from store")
+		return false, e
+	}
+	return resp.Count == 1, nil
+}
+
+// GetSinkList returns list of all notification sinks configured in glusterd
+func GetSinkList() ([]*eventsapi.Sink, error) {
+	resp, e := store.Get(context.TODO(), sinkPrefix, clientv3.WithPrefix())
+	if e != nil {
+		return nil, e
+	}
+
+	sinks := make([]*eventsapi.Sink, 0, len(resp.Kvs))
+
+	for _, kv := range resp.Kvs {
+		var sink eventsapi.Sink
+
+		if err := store.Unmarshal(kv.Value, &sink); err != nil {
+			log.WithError(err).WithField("sink", string(kv.Key)).Error("Failed to unmarshal sink")
+			continue
+		}
+
+		sinks = append(sinks, &sink)
+	}
+
+	return sinks, nil
+}
+
+func addSink(sink eventsapi.Sink) error {
+	data, e := store.Marshal(sink)
+	if e != nil {
+		log.WithError(e).Error("Failed to marshal the sink object")
+		return e
+	}
+
+	if _, err := store.Put(context.TODO(), sinkPrefix+sink.Name, string(data)); err != nil {
+		log.WithError(err).Error("Couldn't add sink to store")
+		return err
+	}
+	return nil
+}
+
+func deleteSink(name string) error {
+	_, e := store.Delete(context.TODO(), sinkPrefix+name)
+	return e
+}
+
 // GetEventsList returns list of Events recorded in last few minutes
 func GetEventsList() ([]*api.Event, error) {
 	resp, e := store.Get(context.TODO(), eventsPrefix, clientv3.WithPrefix())
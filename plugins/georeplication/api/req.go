@@ -19,3 +19,11 @@ type GeorepCreateReq struct {
 type GeorepCommandsReq struct {
 	Force bool `json:"force"`
 }
+
+// GeorepCheckpointReq represents REST API request to set a checkpoint for
+// a Geo-rep session
+type GeorepCheckpointReq struct {
+	// Time is the checkpoint in seconds since the epoch. The checkpoint
+	// is set to the current time if it is 0.
+	Time int64 `json:"time,omitempty"`
+}
@@ -93,3 +93,15 @@ type GeorepOption struct {
 	Configurable bool   `json:"configurable"`
 	Modified     bool   `json:"modified"`
 }
+
+// GeorepCheckpointStatus represents the completion of the checkpoint of a
+// Geo-replication session
+type GeorepCheckpointStatus struct {
+	// Checkpoint is the checkpoint in seconds since the epoch, 0 if no
+	// checkpoint is set
+	Checkpoint int64 `json:"checkpoint"`
+	// Completed is set once all the active workers have synced the
+	// changes made till the checkpoint
+	Completed bool           `json:"completed"`
+	Workers   []GeorepWorker `json:"workers"`
+}
@@ -17,6 +17,8 @@ const (
 	eventGeorepResumed                 = "georep.resumed"
 	eventGeorepConfigSet               = "georep.config.set"
 	eventGeorepConfigReset             = "georep.config.reset"
+	eventGeorepCheckpoint              = "georep.checkpoint.set"
+	eventGeorepResynced                = "georep.resynced"
 )
 
 func newGeorepEvent(e georepEvent, session *georepapi.GeorepSession, extra *map[string]string) *api.Event {
@@ -96,6 +96,28 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Version:     1,
 			HandlerFunc: georepConfigResetHandler,
 		},
+		route.Route{
+			Name:         "GeoReplicationCheckpointSet",
+			Method:       "POST",
+			Pattern:      "/geo-replication/{mastervolid}/{remotevolid}/checkpoint",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*georepapi.GeorepCheckpointReq)(nil)),
+			ResponseType: utils.GetTypeString((*georepapi.GeorepCheckpointStatus)(nil)),
+			HandlerFunc:  georepCheckpointSetHandler},
+		route.Route{
+			Name:         "GeoReplicationCheckpointStatus",
+			Method:       "GET",
+			Pattern:      "/geo-replication/{mastervolid}/{remotevolid}/checkpoint",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*georepapi.GeorepCheckpointStatus)(nil)),
+			HandlerFunc:  georepCheckpointStatusHandler},
+		route.Route{
+			Name:         "GeoReplicationResync",
+			Method:       "POST",
+			Pattern:      "/geo-replication/{mastervolid}/{remotevolid}/resync",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*georepapi.GeorepSession)(nil)),
+			HandlerFunc:  georepResyncHandler},
 		route.Route{
 			Name:         "GeoReplicationStatusList",
 			Method:       "GET",
@@ -137,6 +159,7 @@ func (p *Plugin) RegisterStepFuncs() {
 	transaction.RegisterStepFunc(txnGeorepPause, "georeplication-pause.Commit")
 	transaction.RegisterStepFunc(txnGeorepResume, "georeplication-resume.Commit")
 	transaction.RegisterStepFunc(txnGeorepStatus, "georeplication-status.Commit")
+	transaction.RegisterStepFunc(txnGeorepResync, "georeplication-resync.Commit")
 	transaction.RegisterStepFunc(txnGeorepConfigSet, "georeplication-configset.Commit")
 	transaction.RegisterStepFunc(txnGeorepConfigFilegen, "georeplication-configfilegen.Commit")
 	transaction.RegisterStepFunc(txnSSHKeysGenerate, "georeplication-ssh-keygen.Commit")
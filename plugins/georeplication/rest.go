@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
@@ -74,7 +76,7 @@ func georepCreateHandler(w http.ResponseWriter, r *http.Request) {
 	remoteidRaw := p["remotevolid"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	// Validate UUID format of Master and Remote Volume ID
 	masterid, remoteid, err := validateMasterAndRemoteIDFormat(ctx, w, masteridRaw, remoteidRaw)
@@ -238,7 +240,7 @@ func georepActionHandler(w http.ResponseWriter, r *http.Request, action actionTy
 	remoteidRaw := p["remotevolid"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	// Validate UUID format of Master and Remote Volume ID
 	masterid, remoteid, err := validateMasterAndRemoteIDFormat(ctx, w, masteridRaw, remoteidRaw)
@@ -395,7 +397,7 @@ func georepDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	remoteidRaw := p["remotevolid"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	// Validate UUID format of Master and Remote Volume ID
 	masterid, remoteid, err := validateMasterAndRemoteIDFormat(ctx, w, masteridRaw, remoteidRaw)
@@ -471,7 +473,6 @@ func georepStatusHandler(w http.ResponseWriter, r *http.Request) {
 	remoteidRaw := p["remotevolid"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
 
 	// Validate UUID format of Master and Remote Volume ID
 	masterid, remoteid, err := validateMasterAndRemoteIDFormat(ctx, w, masteridRaw, remoteidRaw)
@@ -504,6 +505,23 @@ func georepStatusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	geoSession.Workers, err = getWorkersStatus(ctx, geoSession, vol)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Send aggregated result back to the client
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, geoSession)
+}
+
+// getWorkersStatus collects the status of the gsyncd workers of the session
+// from the peers hosting the bricks of the master volume. The workers are in
+// the order of the bricks of the master volume.
+func getWorkersStatus(ctx context.Context, geoSession *georepapi.GeorepSession, vol *volume.Volinfo) ([]georepapi.GeorepWorker, error) {
+	logger := gdctx.Logger(ctx)
+	masterid, remoteid := geoSession.MasterID, geoSession.RemoteID
+
 	// Status Transaction
 	txn := transaction.NewTxn(ctx)
 	defer txn.Done()
@@ -516,27 +534,23 @@ func georepStatusHandler(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	if err = txn.Ctx.Set("mastervolid", masterid.String()); err != nil {
+	if err := txn.Ctx.Set("mastervolid", masterid.String()); err != nil {
 		logger.WithError(err).Error("failed to set mastervolid in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
-	if err = txn.Ctx.Set("remotevolid", remoteid.String()); err != nil {
+	if err := txn.Ctx.Set("remotevolid", remoteid.String()); err != nil {
 		logger.WithError(err).Error("failed to set remotevolid in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
-	err = txn.Do()
-	if err != nil {
+	if err := txn.Do(); err != nil {
 		// TODO: Handle partial failure if a few glusterd's down
 		logger.WithError(err).WithFields(log.Fields{
 			"mastervolid": masterid,
 			"remotevolid": remoteid,
 		}).Error("failed to get status of geo-replication session")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
 	// Aggregate the results
@@ -544,18 +558,17 @@ func georepStatusHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		errMsg := "Failed to aggregate gsyncd status results from multiple nodes."
 		logger.WithError(err).Error("gsyncdStatusHandler:" + errMsg)
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, errMsg)
-		return
+		return nil, errs.New(errMsg)
 	}
 
 	bricks := vol.GetBricks()
-	geoSession.Workers = make([]georepapi.GeorepWorker, 0, len(bricks))
+	workers := make([]georepapi.GeorepWorker, 0, len(bricks))
 
 	for _, b := range bricks {
 
 		// Set default values to all status fields, If a node or worker is down and
 		// status not available these default values will be sent back in response
-		geoSession.Workers = append(geoSession.Workers, georepapi.GeorepWorker{
+		workers = append(workers, georepapi.GeorepWorker{
 			MasterPeerHostname:         b.Hostname,
 			MasterPeerID:               b.PeerID.String(),
 			MasterBrickPath:            b.Path,
@@ -581,27 +594,26 @@ func georepStatusHandler(w http.ResponseWriter, r *http.Request) {
 	// Iterating and assigning status of each brick and not doing direct
 	// assignment. So that order of the workers will be maintained similar
 	// to order of bricks in Master Volume
-	for idx, w := range geoSession.Workers {
+	for idx, w := range workers {
 		statusData := (*result)[w.MasterPeerID+":"+w.MasterBrickPath]
-		geoSession.Workers[idx].Status = statusData.Status
-		geoSession.Workers[idx].LastSyncedTime = statusData.LastSyncedTime
-		geoSession.Workers[idx].LastSyncedTimeUTC = statusData.LastSyncedTimeUTC
-		geoSession.Workers[idx].LastEntrySyncedTime = statusData.LastEntrySyncedTime
-		geoSession.Workers[idx].RemotePeerHostname = statusData.RemotePeerHostname
-		geoSession.Workers[idx].CheckpointTime = statusData.CheckpointTime
-		geoSession.Workers[idx].CheckpointTimeUTC = statusData.CheckpointTimeUTC
-		geoSession.Workers[idx].CheckpointCompleted = statusData.CheckpointCompleted
-		geoSession.Workers[idx].CheckpointCompletedTime = statusData.CheckpointCompletedTime
-		geoSession.Workers[idx].CheckpointCompletedTimeUTC = statusData.CheckpointCompletedTimeUTC
-		geoSession.Workers[idx].MetaOps = statusData.MetaOps
-		geoSession.Workers[idx].EntryOps = statusData.EntryOps
-		geoSession.Workers[idx].DataOps = statusData.DataOps
-		geoSession.Workers[idx].FailedOps = statusData.FailedOps
-		geoSession.Workers[idx].CrawlStatus = statusData.CrawlStatus
-	}
-
-	// Send aggregated result back to the client
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, geoSession)
+		workers[idx].Status = statusData.Status
+		workers[idx].LastSyncedTime = statusData.LastSyncedTime
+		workers[idx].LastSyncedTimeUTC = statusData.LastSyncedTimeUTC
+		workers[idx].LastEntrySyncedTime = statusData.LastEntrySyncedTime
+		workers[idx].RemotePeerHostname = statusData.RemotePeerHostname
+		workers[idx].CheckpointTime = statusData.CheckpointTime
+		workers[idx].CheckpointTimeUTC = statusData.CheckpointTimeUTC
+		workers[idx].CheckpointCompleted = statusData.CheckpointCompleted
+		workers[idx].CheckpointCompletedTime = statusData.CheckpointCompletedTime
+		workers[idx].CheckpointCompletedTimeUTC = statusData.CheckpointCompletedTimeUTC
+		workers[idx].MetaOps = statusData.MetaOps
+		workers[idx].EntryOps = statusData.EntryOps
+		workers[idx].DataOps = statusData.DataOps
+		workers[idx].FailedOps = statusData.FailedOps
+		workers[idx].CrawlStatus = statusData.CrawlStatus
+	}
+
+	return workers, nil
 }
 
 func restartRequiredOnConfigChange(name string) bool {
@@ -627,7 +639,7 @@ func georepConfigGetHandler(w http.ResponseWriter, r *http.Request) {
 	remoteidRaw := p["remotevolid"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	// Validate UUID format of Master and Remote Volume ID
 	masterid, remoteid, err := validateMasterAndRemoteIDFormat(ctx, w, masteridRaw, remoteidRaw)
@@ -705,7 +717,7 @@ func georepConfigSetHandler(w http.ResponseWriter, r *http.Request) {
 	remoteidRaw := p["remotevolid"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	// Validate UUID format of Master and Remote Volume ID
 	masterid, remoteid, err := validateMasterAndRemoteIDFormat(ctx, w, masteridRaw, remoteidRaw)
@@ -846,7 +858,7 @@ func georepConfigResetHandler(w http.ResponseWriter, r *http.Request) {
 	remoteidRaw := p["remotevolid"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	// Validate UUID format of Master and Remote Volume ID
 	masterid, remoteid, err := validateMasterAndRemoteIDFormat(ctx, w, masteridRaw, remoteidRaw)
@@ -987,7 +999,7 @@ func georepSSHKeyGenerateHandler(w http.ResponseWriter, r *http.Request) {
 	volname := p["volname"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
@@ -1041,7 +1053,7 @@ func georepSSHKeyGetHandler(w http.ResponseWriter, r *http.Request) {
 	volname := p["volname"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	sshkeys, err := getSSHPublicKeys(volname)
 	if err != nil {
@@ -1062,7 +1074,7 @@ func georepSSHKeyPushHandler(w http.ResponseWriter, r *http.Request) {
 	user := "root"
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
@@ -1116,3 +1128,300 @@ func georepSSHKeyPushHandler(w http.ResponseWriter, r *http.Request) {
 
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
 }
+
+// checkpointOpt is the session configuration holding the checkpoint, which
+// gsyncd picks up from the config file without being restarted
+const checkpointOpt = "checkpoint"
+
+func georepCheckpointSetHandler(w http.ResponseWriter, r *http.Request) {
+	p := mux.Vars(r)
+	masteridRaw := p["mastervolid"]
+	remoteidRaw := p["remotevolid"]
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	// Validate UUID format of Master and Remote Volume ID
+	masterid, remoteid, err := validateMasterAndRemoteIDFormat(ctx, w, masteridRaw, remoteidRaw)
+	if err != nil {
+		return
+	}
+
+	// Parse the JSON body to get additional details of request
+	var req georepapi.GeorepCheckpointReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	now := time.Now().Unix()
+	if req.Time == 0 {
+		req.Time = now
+	}
+	if req.Time < 0 || req.Time > now {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "checkpoint can't be in the future")
+		return
+	}
+
+	// Fetch existing session details from Store, error if not exists
+	geoSession, err := getSession(masterid.String(), remoteid.String())
+	if err != nil {
+		if _, ok := err.(*ErrGeorepSessionNotFound); !ok {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "geo-replication session not found")
+		return
+	}
+
+	// Completion of checkpoints is only tracked by running workers
+	if geoSession.Status != georepapi.GeorepStatusStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, "session is not in started state")
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, geoSession.MasterVol)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	vol, err := volume.GetVolume(geoSession.MasterVol)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	geoSession.Options[checkpointOpt] = strconv.FormatInt(req.Time, 10)
+
+	txn.Nodes = vol.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "georeplication-configset.Commit",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+		},
+		{
+			DoFunc: "georeplication-configfilegen.Commit",
+			Nodes:  txn.Nodes,
+			// Config needs to be set before config file can be generated
+			Sync: true,
+		},
+	}
+
+	if err = txn.Ctx.Set("mastervolid", masterid.String()); err != nil {
+		logger.WithError(err).Error("failed to set mastervolid in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = txn.Ctx.Set("remotevolid", remoteid.String()); err != nil {
+		logger.WithError(err).Error("failed to set remotevolid in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = txn.Ctx.Set("session", geoSession); err != nil {
+		logger.WithError(err).Error("failed to set geosession in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = txn.Ctx.Set("restartRequired", false); err != nil {
+		logger.WithError(err).Error("failed to set restartrequired in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = txn.Do()
+	if err != nil {
+		logger.WithError(err).WithFields(log.Fields{
+			"mastervolid": masterid,
+			"remotevolid": remoteid,
+		}).Error("failed to set checkpoint of geo-replication session")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	events.Broadcast(newGeorepEvent(eventGeorepCheckpoint, geoSession,
+		&map[string]string{"checkpoint": geoSession.Options[checkpointOpt]},
+	))
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, georepapi.GeorepCheckpointStatus{
+		Checkpoint: req.Time,
+		Workers:    []georepapi.GeorepWorker{},
+	})
+}
+
+func georepCheckpointStatusHandler(w http.ResponseWriter, r *http.Request) {
+	p := mux.Vars(r)
+	masteridRaw := p["mastervolid"]
+	remoteidRaw := p["remotevolid"]
+
+	ctx := r.Context()
+
+	// Validate UUID format of Master and Remote Volume ID
+	masterid, remoteid, err := validateMasterAndRemoteIDFormat(ctx, w, masteridRaw, remoteidRaw)
+	if err != nil {
+		return
+	}
+
+	geoSession, err := getSession(masterid.String(), remoteid.String())
+	if err != nil {
+		if _, ok := err.(*ErrGeorepSessionNotFound); !ok {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "geo-replication session not found")
+		return
+	}
+
+	resp := georepapi.GeorepCheckpointStatus{Workers: []georepapi.GeorepWorker{}}
+	if v, ok := geoSession.Options[checkpointOpt]; ok {
+		resp.Checkpoint, _ = strconv.ParseInt(v, 10, 64)
+	}
+
+	// Reach brick nodes only if a checkpoint is set and the session is
+	// running
+	if resp.Checkpoint == 0 || geoSession.Status != georepapi.GeorepStatusStarted {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+		return
+	}
+
+	vol, err := volume.GetVolume(geoSession.MasterVol)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp.Workers, err = getWorkersStatus(ctx, geoSession, vol)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// The checkpoint is complete once all the active workers have
+	// completed it. Passive workers don't sync any changes.
+	active := 0
+	resp.Completed = true
+	for _, worker := range resp.Workers {
+		if worker.Status != georepapi.GeorepStatusActive {
+			continue
+		}
+		active++
+		if worker.CheckpointCompleted != "Yes" {
+			resp.Completed = false
+		}
+	}
+	if active == 0 {
+		resp.Completed = false
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// georepResyncHandler makes the session sync all the data of the master
+// volume again, by resetting the time till which each brick was synced. A
+// running session is stopped while doing so, and started again.
+func georepResyncHandler(w http.ResponseWriter, r *http.Request) {
+	p := mux.Vars(r)
+	masteridRaw := p["mastervolid"]
+	remoteidRaw := p["remotevolid"]
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	// Validate UUID format of Master and Remote Volume ID
+	masterid, remoteid, err := validateMasterAndRemoteIDFormat(ctx, w, masteridRaw, remoteidRaw)
+	if err != nil {
+		return
+	}
+
+	// Fetch existing session details from Store, error if not exists
+	geoSession, err := getSession(masterid.String(), remoteid.String())
+	if err != nil {
+		if _, ok := err.(*ErrGeorepSessionNotFound); !ok {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "geo-replication session not found")
+		return
+	}
+
+	if geoSession.Status == georepapi.GeorepStatusPaused {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, "session is paused, resume it before resyncing")
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, geoSession.MasterVol)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	vol, err := volume.GetVolume(geoSession.MasterVol)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	started := geoSession.Status == georepapi.GeorepStatusStarted
+	if started && vol.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "master volume not started")
+		return
+	}
+
+	txn.Nodes = vol.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "georeplication-stop.Commit",
+			UndoFunc: "georeplication-start.Commit",
+			Nodes:    txn.Nodes,
+			Skip:     !started,
+		},
+		{
+			DoFunc: "georeplication-resync.Commit",
+			Nodes:  txn.Nodes,
+			// Workers need to be stopped before the sync time is reset
+			Sync: true,
+		},
+		{
+			DoFunc: "georeplication-start.Commit",
+			Nodes:  txn.Nodes,
+			Sync:   true,
+			Skip:   !started,
+		},
+	}
+
+	if err = txn.Ctx.Set("mastervolid", masterid.String()); err != nil {
+		logger.WithError(err).Error("failed to set mastervolid in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = txn.Ctx.Set("remotevolid", remoteid.String()); err != nil {
+		logger.WithError(err).Error("failed to set remotevolid in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = txn.Do()
+	if err != nil {
+		logger.WithError(err).WithFields(log.Fields{
+			"mastervolid": masterid,
+			"remotevolid": remoteid,
+		}).Error("failed to resync geo-replication session")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	events.Broadcast(newGeorepEvent(eventGeorepResynced, geoSession, nil))
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, geoSession)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 	config "github.com/spf13/viper"
+	"golang.org/x/sys/unix"
 )
 
 const (
@@ -125,6 +126,48 @@ func txnGeorepDelete(c transaction.TxnCtx) error {
 	return nil
 }
 
+// stimeXattrKey returns the xattr on the root of the bricks holding the
+// time till which the changes on the brick have been synced by the session
+func stimeXattrKey(masterid, remoteid string) string {
+	return "trusted.glusterfs." + masterid + "." + remoteid + ".stime"
+}
+
+// txnGeorepResync resets the sync time of the local bricks of the master
+// volume, so that the workers crawl and sync all the data of the bricks when
+// they are started
+func txnGeorepResync(c transaction.TxnCtx) error {
+	var masterid string
+	var remoteid string
+	if err := c.Get("mastervolid", &masterid); err != nil {
+		return err
+	}
+	if err := c.Get("remotevolid", &remoteid); err != nil {
+		return err
+	}
+
+	sessioninfo, err := getSession(masterid, remoteid)
+	if err != nil {
+		return err
+	}
+
+	volinfo, err := volume.GetVolume(sessioninfo.MasterVol)
+	if err != nil {
+		return err
+	}
+
+	key := stimeXattrKey(masterid, remoteid)
+	for _, b := range volinfo.GetLocalBricks() {
+		err := unix.Removexattr(b.Path, key)
+		if err != nil && err != unix.ENODATA {
+			c.Logger().WithError(err).WithField(
+				"brick", b.Path).Error("failed to reset sync time of brick")
+			return err
+		}
+	}
+
+	return nil
+}
+
 func txnGeorepPause(c transaction.TxnCtx) error {
 	return gsyncdAction(c, actionPause)
 }
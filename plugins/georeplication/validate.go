@@ -0,0 +1,33 @@
+package georeplication
+
+import (
+	"fmt"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+)
+
+// validateVolumeOp vetoes renaming volumes which are the masters of
+// geo-replication sessions, as the sessions and their workers refer to the
+// master volume by its name
+func validateVolumeOp(op volume.ValidationOp, v *volume.Volinfo) error {
+	if op != volume.ValidateRename {
+		return nil
+	}
+
+	sessions, err := getSessionList()
+	if err != nil {
+		return err
+	}
+	for _, s := range *sessions {
+		if uuid.Equal(s.MasterID, v.ID) {
+			return fmt.Errorf("volume is the master of the geo-replication session to %s, delete the session first", s.RemoteVol)
+		}
+	}
+	return nil
+}
+
+func init() {
+	volume.RegisterValidator("georeplication", validateVolumeOp)
+}
@@ -0,0 +1,170 @@
+package gfproxy
+
+import (
+	"errors"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// gfproxyKey is the volinfo metadata key used to mark gfproxy as enabled
+const gfproxyKey = "_gfproxy"
+
+func isGfproxyEnabled(v *volume.Volinfo) bool {
+	return v.Metadata[gfproxyKey] == "on"
+}
+
+func volfilePath(volfileID string) string {
+	return path.Join(config.GetString("localstatedir"), "volfiles", volfileID)
+}
+
+// clientHost returns the address of this peer which thin clients connect to
+func clientHost() (string, error) {
+	self, err := peer.GetPeer(gdctx.MyUUID.String())
+	if err != nil {
+		return "", err
+	}
+
+	for _, addr := range self.ClientAddresses {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			continue
+		}
+		if !strings.HasPrefix(host, "127.") && host != "localhost" {
+			return host, nil
+		}
+	}
+
+	return "", errors.New("no client address found for gfproxy")
+}
+
+// generateClientVolfile generates the volfile served to thin clients. The
+// thin client connects to the gfproxy daemon running on this peer.
+func generateClientVolfile(v *volume.Volinfo) error {
+	host, err := clientHost()
+	if err != nil {
+		return err
+	}
+
+	data := utils.MergeStringMaps(v.StringMap(), map[string]string{
+		"gfproxy.remote-host":      host,
+		"gfproxy.remote-subvolume": exportName(v.Name),
+	})
+
+	volfile := volgen.NewVolfile("gfproxy-client")
+	volfile.RootEntry.Add(volgen.Xlator{
+		Type:     "debug/io-stats",
+		NameTmpl: "{{ volume.name }}",
+	}, data).Add(volgen.Xlator{
+		Type: "protocol/client",
+		Options: map[string]string{
+			"remote-host":      "{{ gfproxy.remote-host }}",
+			"remote-subvolume": "{{ gfproxy.remote-subvolume }}",
+			"transport-type":   "{{ volume.transport }}",
+			"username":         "{{ volume.auth.username }}",
+			"password":         "{{ volume.auth.password }}",
+		},
+	}, data)
+
+	content, err := volfile.Generate()
+	if err != nil {
+		return err
+	}
+
+	return volgen.SaveToFile(volfilePath(ClientVolfileID(v.Name))+".vol", content)
+}
+
+// startGfproxyd generates the gfproxy volfiles of the volume and starts the
+// gfproxy daemon on this peer
+func startGfproxyd(v *volume.Volinfo, logger log.FieldLogger) error {
+	gfproxyd, err := newGfproxyd(v.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := volgen.VolumeVolfileToFile(v, gfproxyd.VolfileID, utils.GfProxyVolfile); err != nil {
+		return err
+	}
+
+	if err := generateClientVolfile(v); err != nil {
+		return err
+	}
+
+	err = daemon.Start(gfproxyd, true, logger)
+	if err != nil && err != gderrors.ErrProcessAlreadyRunning {
+		return err
+	}
+
+	return nil
+}
+
+// stopGfproxyd stops the gfproxy daemon of the volume on this peer and
+// removes the gfproxy volfiles
+func stopGfproxyd(v *volume.Volinfo, logger log.FieldLogger) error {
+	gfproxyd, err := newGfproxyd(v.Name)
+	if err != nil {
+		return err
+	}
+
+	err = daemon.Stop(gfproxyd, true, logger)
+	if err != nil && err != gderrors.ErrPidFileNotFound {
+		return err
+	}
+
+	for _, volfileID := range []string{gfproxyd.VolfileID, ClientVolfileID(v.Name)} {
+		if err := volgen.DeleteFile(volfilePath(volfileID)); err != nil {
+			logger.WithError(err).WithField("volfile", volfileID).Warn("failed to delete gfproxy volfile")
+		}
+	}
+
+	return nil
+}
+
+// gfproxyActor starts and stops the gfproxy daemon along with the volume
+type gfproxyActor struct{}
+
+func (actor *gfproxyActor) Do(v *volume.Volinfo, key string, value string, volOp xlator.VolumeOpType, logger log.FieldLogger) error {
+	if !isGfproxyEnabled(v) {
+		return nil
+	}
+
+	switch volOp {
+	case xlator.VolumeStart:
+		return startGfproxyd(v, logger)
+	case xlator.VolumeStop:
+		return stopGfproxyd(v, logger)
+	}
+
+	return nil
+}
+
+func (actor *gfproxyActor) Undo(v *volume.Volinfo, key string, value string, volOp xlator.VolumeOpType, logger log.FieldLogger) error {
+	if !isGfproxyEnabled(v) {
+		return nil
+	}
+
+	switch volOp {
+	case xlator.VolumeStart:
+		return stopGfproxyd(v, logger)
+	case xlator.VolumeStop:
+		return startGfproxyd(v, logger)
+	}
+
+	return nil
+}
+
+func init() {
+	xlator.RegisterOptionActor("gfproxy", &gfproxyActor{})
+}
@@ -0,0 +1,19 @@
+package api
+
+// GfproxydStatus represents the status of the gfproxy daemon of a volume on a peer
+type GfproxydStatus struct {
+	PeerID string `json:"peer-id"`
+	Online bool   `json:"online"`
+	Pid    int    `json:"pid"`
+	Port   int    `json:"port"`
+}
+
+// GfproxyStatusResp is the response sent for a gfproxy status request
+type GfproxyStatusResp struct {
+	Volume  string `json:"volume"`
+	Enabled bool   `json:"enabled"`
+	// ClientVolfileID is the volfile ID to be used by thin clients to
+	// mount the volume through gfproxy
+	ClientVolfileID string           `json:"client-volfile-id"`
+	Daemons         []GfproxydStatus `json:"daemons"`
+}
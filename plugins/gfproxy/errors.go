@@ -0,0 +1,12 @@
+package gfproxy
+
+import (
+	"errors"
+)
+
+var (
+	// ErrGfproxyAlreadyEnabled : gfproxy is already enabled on the volume
+	ErrGfproxyAlreadyEnabled = errors.New("gfproxy is already enabled")
+	// ErrGfproxyAlreadyDisabled : gfproxy is already disabled on the volume
+	ErrGfproxyAlreadyDisabled = errors.New("gfproxy is already disabled")
+)
@@ -0,0 +1,131 @@
+package gfproxy
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path"
+
+	"github.com/cespare/xxhash"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	config "github.com/spf13/viper"
+)
+
+const (
+	gfproxydBin = "glusterfsd"
+)
+
+// Gfproxyd type represents information about the gfproxy daemon of a volume
+type Gfproxyd struct {
+	binarypath     string
+	args           []string
+	socketfilepath string
+	pidfilepath    string
+	volname        string
+	VolfileID      string
+}
+
+// Name returns human-friendly name of the gfproxy daemon. This is used for
+// logging.
+func (g *Gfproxyd) Name() string {
+	return "gfproxyd"
+}
+
+// Path returns absolute path to the binary of the gfproxy daemon
+func (g *Gfproxyd) Path() string {
+	return g.binarypath
+}
+
+// Args returns arguments to be passed to the gfproxy daemon during spawn.
+func (g *Gfproxyd) Args() []string {
+	if g.args != nil {
+		return g.args
+	}
+
+	shost, sport, _ := net.SplitHostPort(config.GetString("clientaddress"))
+	if shost == "" {
+		shost = "localhost"
+	}
+
+	logFile := path.Join(config.GetString("logdir"), "glusterfs", "gfproxyd", g.volname+".log")
+
+	g.args = []string{}
+	g.args = append(g.args, "-s", shost)
+	g.args = append(g.args, "--volfile-server-port", sport)
+	g.args = append(g.args, "--volfile-id", g.VolfileID)
+	g.args = append(g.args, "-p", g.PidFile())
+	g.args = append(g.args, "-S", g.SocketFile())
+	// Thin clients look up the port of the daemon in the portmap
+	// registry using the name of the exported subvolume
+	g.args = append(g.args, "--brick-name", exportName(g.volname))
+	g.args = append(g.args, "-l", logFile)
+
+	return g.args
+}
+
+// SocketFile returns path to the socket file used for IPC.
+func (g *Gfproxyd) SocketFile() string {
+	if g.socketfilepath != "" {
+		return g.socketfilepath
+	}
+
+	key := gdctx.MyUUID.String() + "-" + g.volname
+	g.socketfilepath = fmt.Sprintf("%s/gfproxyd-%x.socket", config.GetString("rundir"), xxhash.Sum64String(key))
+
+	return g.socketfilepath
+}
+
+// PidFile returns path to the pid file of the gfproxy daemon
+func (g *Gfproxyd) PidFile() string {
+	return g.pidfilepath
+}
+
+// ID returns the unique identifier of the gfproxy daemon. There is one
+// daemon per volume on a node.
+func (g *Gfproxyd) ID() string {
+	return "gfproxyd-" + g.volname
+}
+
+// newGfproxyd returns a new instance of Gfproxyd type which implements the
+// Daemon interface
+func newGfproxyd(volname string) (*Gfproxyd, error) {
+	binarypath, e := exec.LookPath(gfproxydBin)
+	if e != nil {
+		return nil, e
+	}
+
+	pidFileDir := path.Join(config.GetString("rundir"), "gfproxyd")
+	if e = os.MkdirAll(pidFileDir, os.ModeDir|os.ModePerm); e != nil {
+		return nil, e
+	}
+
+	logDir := path.Join(config.GetString("logdir"), "glusterfs", "gfproxyd")
+	if e = os.MkdirAll(logDir, os.ModeDir|os.ModePerm); e != nil {
+		return nil, e
+	}
+
+	return &Gfproxyd{
+		binarypath:  binarypath,
+		volname:     volname,
+		VolfileID:   daemonVolfileID(volname),
+		pidfilepath: path.Join(pidFileDir, volname+".pid"),
+	}, nil
+}
+
+// exportName returns the name of the subvolume exported by the gfproxy daemon
+func exportName(volname string) string {
+	return "gfproxyd-" + volname
+}
+
+// daemonVolfileID returns the volfile ID of the gfproxy daemon of a volume
+func daemonVolfileID(volname string) string {
+	return "gfproxyd/" + volname
+}
+
+// ClientVolfileID returns the volfile ID to be used by thin clients to mount
+// the volume through gfproxy
+func ClientVolfileID(volname string) string {
+	return "gfproxy-client/" + volname
+}
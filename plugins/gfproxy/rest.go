@@ -0,0 +1,218 @@
+package gfproxy
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gfproxyapi "github.com/gluster/glusterd2/plugins/gfproxy/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+func gfproxyEnableHandler(w http.ResponseWriter, r *http.Request) {
+	// Collect inputs from URL
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	// Validate volume existence
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if isGfproxyEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, ErrGfproxyAlreadyEnabled)
+		return
+	}
+
+	// save volume information for transaction failure scenario
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if volinfo.Metadata == nil {
+		volinfo.Metadata = make(map[string]string)
+	}
+	volinfo.Metadata[gfproxyKey] = "on"
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+		},
+		{
+			DoFunc:   "gfproxy-enable.Commit",
+			UndoFunc: "gfproxy-disable.Commit",
+			Nodes:    txn.Nodes,
+			// Volinfo needs to be updated before starting the daemons
+			Sync: true,
+		},
+	}
+
+	if err = txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to enable gfproxy")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
+func gfproxyDisableHandler(w http.ResponseWriter, r *http.Request) {
+	// Collect inputs from URL
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	// Validate volume existence
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if !isGfproxyEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, ErrGfproxyAlreadyDisabled)
+		return
+	}
+
+	// save volume information for transaction failure scenario
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	delete(volinfo.Metadata, gfproxyKey)
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+		},
+		{
+			DoFunc: "gfproxy-disable.Commit",
+			Nodes:  txn.Nodes,
+			Sync:   true,
+		},
+	}
+
+	if err = txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to disable gfproxy")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
+func gfproxyStatusHandler(w http.ResponseWriter, r *http.Request) {
+	// Collect inputs from URL
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	// Validate volume existence
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := gfproxyapi.GfproxyStatusResp{
+		Volume:  volname,
+		Enabled: isGfproxyEnabled(volinfo),
+		Daemons: []gfproxyapi.GfproxydStatus{},
+	}
+	if !resp.Enabled {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+		return
+	}
+	resp.ClientVolfileID = ClientVolfileID(volname)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	// Some nodes may not be up, which is okay.
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "gfproxy.Status",
+			Nodes:  txn.Nodes,
+		},
+	}
+	if err := txn.Ctx.Set("volname", volname); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to get gfproxy status")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	for _, node := range txn.Nodes {
+		var tmp gfproxyapi.GfproxydStatus
+		if err := txn.Ctx.GetNodeResult(node, gfproxyStatusTxnKey, &tmp); err != nil {
+			// skip if we do not have information
+			continue
+		}
+		resp.Daemons = append(resp.Daemons, tmp)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,60 @@
+package gfproxy
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/pmap"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gfproxyapi "github.com/gluster/glusterd2/plugins/gfproxy/api"
+)
+
+const gfproxyStatusTxnKey = "gfproxydstatus"
+
+func txnGfproxyEnable(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	// The daemon is started along with the volume if it is not started yet
+	if volinfo.State != volume.VolStarted {
+		return nil
+	}
+
+	return startGfproxyd(&volinfo, c.Logger())
+}
+
+func txnGfproxyDisable(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	return stopGfproxyd(&volinfo, c.Logger())
+}
+
+func txnGfproxyStatus(c transaction.TxnCtx) error {
+	var volname string
+	if err := c.Get("volname", &volname); err != nil {
+		return err
+	}
+
+	gfproxyd, err := newGfproxyd(volname)
+	if err != nil {
+		return err
+	}
+
+	status := gfproxyapi.GfproxydStatus{PeerID: gdctx.MyUUID.String()}
+	if running, pid := daemon.IsRunning(gfproxyd); running {
+		status.Online = true
+		status.Pid = pid
+		if port, err := pmap.RegistrySearch(exportName(volname)); err == nil {
+			status.Port = port
+		}
+	}
+
+	// Store the results in transaction context. This will be consumed by
+	// the node that initiated the transaction.
+	return c.SetNodeResult(gdctx.MyUUID, gfproxyStatusTxnKey, status)
+}
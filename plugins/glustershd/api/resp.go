@@ -33,3 +33,23 @@ type HealInfo struct {
 	XMLNAME xml.Name        `xml:"cliOutput"`
 	Bricks  []BrickHealInfo `xml:"healInfo>bricks>brick"`
 }
+
+// BrickHealCount represents the number of entries pending heal on a brick,
+// as counted by the self-heal daemon of the peer hosting the brick
+type BrickHealCount struct {
+	HostID string `json:"host-id"`
+	Name   string `json:"name"`
+	// Status is Connected if the count was got from the self-heal daemon,
+	// and the reason it couldn't be got otherwise
+	Status               string `json:"status"`
+	EntriesInHealPending *int64 `json:"entries-in-heal-pending,omitempty"`
+}
+
+// HealCountResp is the response sent for a heal-info request for the counts
+// of entries pending heal on the bricks of a volume
+type HealCountResp struct {
+	Bricks []BrickHealCount `json:"bricks"`
+	// TotalEntries is the number of entries pending heal on all the bricks
+	// the counts were got for
+	TotalEntries int64 `json:"total-entries"`
+}
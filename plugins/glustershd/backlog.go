@@ -0,0 +1,41 @@
+package glustershd
+
+import (
+	"encoding/xml"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	glustershdapi "github.com/gluster/glusterd2/plugins/glustershd/api"
+)
+
+// HealBacklog returns the number of entries pending heal on the bricks of
+// the volume. ok is false if the volume is not a replicated or dispersed
+// volume, or is not started.
+func HealBacklog(volinfo *volume.Volinfo) (backlog int64, ok bool, err error) {
+	if !isVolReplicate(volinfo.Type) || volinfo.State != volume.VolStarted {
+		return 0, false, nil
+	}
+
+	out, err := getHealInfo(volinfo.Name, "info-summary")
+	if err != nil {
+		return 0, false, err
+	}
+
+	var info glustershdapi.HealInfo
+	if err := xml.Unmarshal([]byte(out), &info); err != nil {
+		return 0, false, err
+	}
+
+	info, err = filterHealInfo(info)
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, b := range info.Bricks {
+		// Entries of bricks which are down are reported as -1
+		if b.TotalEntries != nil && *b.TotalEntries > 0 {
+			backlog += *b.TotalEntries
+		}
+	}
+
+	return backlog, true, nil
+}
@@ -38,7 +38,15 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Method:      "POST",
 			Pattern:     "/volumes/{volname}/heal",
 			Version:     1,
+			Permission:  route.PermOperate,
 			HandlerFunc: selfHealHandler},
+		route.Route{
+			Name:        "SelfHealFull",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/heal/full",
+			Version:     1,
+			Permission:  route.PermOperate,
+			HandlerFunc: selfHealFullHandler},
 		route.Route{
 			Name:        "Split-Brain-Operations",
 			Method:      "POST",
@@ -53,4 +61,6 @@ func (p *Plugin) RestRoutes() route.Routes {
 // Glusterd Transaction framework
 func (p *Plugin) RegisterStepFuncs() {
 	transaction.RegisterStepFunc(txnSelfHeal, "selfheal.Heal")
+	transaction.RegisterStepFunc(txnHealCount, "selfheal.HealCount")
+	registerIOOpHandler()
 }
@@ -0,0 +1,45 @@
+package glustershd
+
+import (
+	"context"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/ioops"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+)
+
+// fullHealMinDuration is how long a full heal is taken to be running
+// regardless of the entries pending heal, as the self-heal daemons don't
+// report the progress of the crawl
+const fullHealMinDuration = 10 * time.Minute
+
+func registerIOOpHandler() {
+	// A full heal can't be throttled
+	ioops.Register(ioops.OpFullHeal, &ioops.Handler{
+		Start:   startQueuedFullHeal,
+		Running: fullHealRunning,
+	})
+}
+
+func startQueuedFullHeal(ctx context.Context, c *ioops.Claim) error {
+	return heal(ctx, c.Volume, fullHeal)
+}
+
+// fullHealRunning returns true while the full heal of the volume is in its
+// first fullHealMinDuration, or entries are pending heal on its bricks
+func fullHealRunning(ctx context.Context, c *ioops.Claim) (bool, error) {
+	if time.Since(c.Since) < fullHealMinDuration {
+		return true, nil
+	}
+
+	volinfo, err := volume.GetVolume(c.Volume)
+	if err != nil {
+		return false, err
+	}
+
+	backlog, ok, err := HealBacklog(volinfo)
+	if err != nil {
+		return false, err
+	}
+	return ok && backlog > 0, nil
+}
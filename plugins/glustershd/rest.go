@@ -2,7 +2,9 @@ package glustershd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
 	"os/exec"
@@ -11,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/ioops"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
@@ -30,6 +33,14 @@ const (
 	fullHeal
 )
 
+// statisticsHealCount is the heal op counting the entries pending heal, as
+// numbered in gf_xl_afr_op_t
+const statisticsHealCount healTypes = 8
+
+const healCountTxnKey = "heal-count"
+
+var errHealDisabled = errors.New("self heal option is disabled for this volume")
+
 func runGlfshealBin(volname string, args []string) (string, error) {
 	var out bytes.Buffer
 	var buffer bytes.Buffer
@@ -132,8 +143,13 @@ func selfhealInfoHandler(w http.ResponseWriter, r *http.Request) {
 		option = val
 	}
 
+	if option == "" && r.URL.Query().Get("count") == "true" {
+		healCountHandler(w, r)
+		return
+	}
+
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
@@ -195,12 +211,94 @@ func selfhealInfoHandler(w http.ResponseWriter, r *http.Request) {
 
 }
 
-func selfHealHandler(w http.ResponseWriter, r *http.Request) {
-	// Collect inputs from URL
+// healCountHandler sends the number of entries pending heal on each brick of
+// the volume, as counted by the self-heal daemons of the peers hosting them
+func healCountHandler(w http.ResponseWriter, r *http.Request) {
 	volname := mux.Vars(r)["volname"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// Only the replicate xlator counts the entries pending heal
+	if volinfo.Type != volume.Replicate && volinfo.Type != volume.DistReplicate {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "heal counts are only available for replicated volumes")
+		return
+	}
+
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrVolNotStarted)
+		return
+	}
+
+	if !isHealEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "self heal option is disabled for this volume")
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "selfheal.HealCount",
+			Nodes:  txn.Nodes,
+		},
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to get heal counts")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	counts := make(map[string]glustershdapi.BrickHealCount)
+	for _, node := range txn.Nodes {
+		var nodeCounts map[string]glustershdapi.BrickHealCount
+		if err := txn.Ctx.GetNodeResult(node, healCountTxnKey, &nodeCounts); err != nil {
+			logger.WithError(err).WithField("peer", node).Warn("failed to get heal counts of peer")
+			continue
+		}
+		for k, v := range nodeCounts {
+			counts[k] = v
+		}
+	}
+
+	// Bricks are listed in the order of the volume
+	resp := glustershdapi.HealCountResp{Bricks: []glustershdapi.BrickHealCount{}}
+	for _, b := range volinfo.GetBricks() {
+		count, ok := counts[b.PeerID.String()+":"+b.Path]
+		if !ok {
+			count = glustershdapi.BrickHealCount{
+				HostID: b.PeerID.String(),
+				Name:   b.Hostname + ":" + b.Path,
+				Status: "Unknown",
+			}
+		}
+		if count.EntriesInHealPending != nil {
+			resp.TotalEntries += *count.EntriesInHealPending
+		}
+		resp.Bricks = append(resp.Bricks, count)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, &resp)
+}
+
+func selfHealHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
 	healType := indexHeal
 	if heal, ok := r.URL.Query()["type"]; ok {
@@ -214,42 +312,68 @@ func selfHealHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
-	if err != nil {
+	startHeal(w, r, healType)
+}
+
+func selfHealFullHandler(w http.ResponseWriter, r *http.Request) {
+	startHeal(w, r, fullHeal)
+}
+
+// startHeal makes the self-heal daemons of the peers hosting the bricks of
+// the volume start healing it
+func startHeal(w http.ResponseWriter, r *http.Request, healType healTypes) {
+	// Collect inputs from URL
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+
+	switch err := heal(ctx, volname, healType); err {
+	case nil:
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+	case ioops.ErrQueued:
+		restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, nil)
+	case gderrors.ErrVolNotStarted, errHealDisabled:
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+	default:
 		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
-		return
+	}
+}
+
+// heal starts healing the volume. A full heal isn't started together with
+// the other IO intensive operations on the bricks of the volume, and
+// ioops.ErrQueued is returned if it has been queued behind them.
+func heal(ctx context.Context, volname string, healType healTypes) error {
+	logger := gdctx.Logger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return err
 	}
 	defer txn.Done()
 
 	// Validate volume existence
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return err
 	}
 
 	// Check if volume is started
 	if volinfo.State != volume.VolStarted {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrVolNotStarted)
-		return
+		return gderrors.ErrVolNotStarted
 	}
 
 	// Check if self heal is already enabled
 	if !isHealEnabled(volinfo) {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "self heal option is disabled for this volume")
-		return
+		return errHealDisabled
 	}
 
 	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return err
 	}
 
 	if err := txn.Ctx.Set("healType", healType); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return err
 	}
 
 	txn.Steps = []*transaction.Step{
@@ -259,13 +383,22 @@ func selfHealHandler(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	if healType == fullHeal {
+		if err := ioops.Acquire(ctx, ioops.OpFullHeal, volinfo, nil); err != nil {
+			return err
+		}
+	}
+
 	if err = txn.Do(); err != nil {
 		logger.WithError(err).Error("failed to start healing process")
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		if healType == fullHeal {
+			if err := ioops.Release(ioops.OpFullHeal, volname); err != nil {
+				logger.WithError(err).Warn("failed to release bricks claimed for full heal")
+			}
+		}
+		return err
 	}
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+	return nil
 }
 
 func splitBrainOperationHandler(w http.ResponseWriter, r *http.Request) {
@@ -274,7 +407,7 @@ func splitBrainOperationHandler(w http.ResponseWriter, r *http.Request) {
 	operation := mux.Vars(r)["operation"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	var req glustershdapi.SplitBrainReq
 	if err := restutils.UnmarshalRequest(r, &req); err != nil {
@@ -11,6 +11,9 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	glustershdapi "github.com/gluster/glusterd2/plugins/glustershd/api"
+
+	"github.com/pborman/uuid"
 )
 
 func getHxlChildrenCount(volinfo *volume.Volinfo) (int, string) {
@@ -61,53 +64,117 @@ func selectHxlatorsWithBricks(volinfo *volume.Volinfo, healType int) map[string]
 	return reqDict
 }
 
-func txnSelfHeal(c transaction.TxnCtx) error {
-
-	var volinfo volume.Volinfo
-	if err := c.Get("volinfo", &volinfo); err != nil {
-		return err
-	}
-
-	var healType int
-	if err := c.Get("healType", &healType); err != nil {
-		return err
-	}
-
+// sendHealOp sends the heal op to the local glustershd, over its unix socket,
+// for the heal xlators of the volume with local bricks. The output dict of
+// glustershd is returned.
+func sendHealOp(c transaction.TxnCtx, volinfo *volume.Volinfo, healType int) (map[string]string, error) {
 	volname := volinfo.Name
 
 	glustershDaemon, err := newGlustershd()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	c.Logger().WithField("volume", volname).Info("Starting Heal")
-
 	client, err := daemon.GetRPCClient(glustershDaemon)
 	if err != nil {
 		c.Logger().WithError(err).WithField(
 			"volume", volname).Error("failed to connect to glustershd")
-		return err
+		return nil, err
 	}
 
 	req := &brick.GfBrickOpReq{
 		Name: "",
 		Op:   int(brick.OpBrickXlatorOp),
 	}
-	reqDict := selectHxlatorsWithBricks(&volinfo, healType)
+	reqDict := selectHxlatorsWithBricks(volinfo, healType)
 	req.Input, err = dict.Serialize(reqDict)
 	if err != nil {
 		c.Logger().WithError(err).WithField(
-			"volume", volname).Error("failed to serialize dict for index heal")
-		return err
+			"volume", volname).Error("failed to serialize dict for heal op")
+		return nil, err
 	}
 
 	var rsp brick.GfBrickOpRsp
 	err = client.Call("Brick.OpBrickXlatorOp", req, &rsp)
 	if err != nil || rsp.OpRet != 0 {
 		c.Logger().WithError(err).WithField(
-			"volume", volname).Error("failed to send index heal RPC")
+			"volume", volname).Error("failed to send heal op RPC")
+		return nil, err
+	}
+
+	if len(rsp.Output) == 0 {
+		return map[string]string{}, nil
+	}
+	return dict.Unserialize(rsp.Output)
+}
+
+func txnSelfHeal(c transaction.TxnCtx) error {
+
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
 		return err
 	}
 
-	return nil
+	var healType int
+	if err := c.Get("healType", &healType); err != nil {
+		return err
+	}
+
+	c.Logger().WithField("volume", volinfo.Name).Info("Starting Heal")
+
+	_, err := sendHealOp(c, &volinfo, healType)
+	return err
+}
+
+// txnHealCount gets the number of entries pending heal on the local bricks
+// of the volume from glustershd, and saves them as the result of this node
+func txnHealCount(c transaction.TxnCtx) error {
+
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	output, err := sendHealOp(c, &volinfo, int(statisticsHealCount))
+	if err != nil {
+		return err
+	}
+
+	counts := parseHealCounts(&volinfo, output)
+	return c.SetNodeResult(gdctx.MyUUID, healCountTxnKey, counts)
+}
+
+// parseHealCounts returns the heal counts of the local bricks of the volume
+// from the output of glustershd, keyed by the brick. glustershd reports the
+// count of each brick as <subvol>-<child>-hardlinks, and the bricks it
+// couldn't count as <subvol>-<child>-status.
+func parseHealCounts(volinfo *volume.Volinfo, output map[string]string) map[string]glustershdapi.BrickHealCount {
+	counts := make(map[string]glustershdapi.BrickHealCount)
+	for subvolIdx, subvol := range volinfo.Subvols {
+		for child, b := range subvol.Bricks {
+			if !uuid.Equal(b.PeerID, gdctx.MyUUID) {
+				continue
+			}
+
+			count := glustershdapi.BrickHealCount{
+				HostID: b.PeerID.String(),
+				Name:   b.Hostname + ":" + b.Path,
+				Status: "Connected",
+			}
+			prefix := fmt.Sprintf("%d-%d-", subvolIdx, child)
+			if v, ok := output[prefix+"hardlinks"]; ok {
+				n, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					continue
+				}
+				count.EntriesInHealPending = &n
+			} else if status, ok := output[prefix+"status"]; ok {
+				count.Status = status
+			} else {
+				count.Status = "Not counted"
+			}
+			counts[b.PeerID.String()+":"+b.Path] = count
+		}
+	}
+	return counts
 }
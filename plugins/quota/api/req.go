@@ -5,6 +5,10 @@ type SetLimitReq struct {
 	Path             string `json:"path"`
 	SizeUsageLimit   int    `json:"size-usage-limit,omitempty"`
 	ObjectCountLimit int    `json:"object-count-limit,omitempty"`
+	// SoftLimitPercent is the percentage of the limits above which the
+	// usage is reported as exceeding the soft limit. The default-soft-limit
+	// quota option is used if it isn't set.
+	SoftLimitPercent int `json:"soft-limit-percent,omitempty"`
 }
 
 // RemoveLimitReq represents REST API request to Remove Usage/objects of a directory
@@ -1,11 +1,18 @@
 package api
 
-type crawlInfo struct {
-	CrawlPid int `json:"crawl-pid"`
-	MountPid int `json:"crawl-mount-pid"`
-}
+// Types of limits
+const (
+	// LimitTypeUsage limits the size of a directory
+	LimitTypeUsage int32 = iota + 1
+	// LimitTypeObjects limits the number of files and directories under a
+	// directory
+	LimitTypeObjects
+)
 
-type list struct {
+// Limit represents a limit set on a directory, and the usage of the
+// directory against it. The usage of a LimitTypeObjects limit is a count of
+// files and directories.
+type Limit struct {
 	Path      string `json:"path"`
 	HardLimit int64  `json:"hard-limit"`
 	SoftLimit int64  `json:"soft-limit"`
@@ -22,10 +29,4 @@ type list struct {
 }
 
 //ListResp is an array of structs representing individual limits.
-type ListResp []list
-
-//DisableResp gives the information of disable crawler on success
-type DisableResp crawlInfo
-
-//EnableResp gives the information of enable crawler on success
-type EnableResp crawlInfo
+type ListResp []Limit
@@ -0,0 +1,28 @@
+package quota
+
+const (
+	// keyServerQuota is the key which turns on the enforcement of the
+	// limits by the quota xlator on the bricks
+	keyServerQuota = "quota.server-quota"
+	// keyMarkerQuota is the key which turns on the accounting of the size
+	// of directories by the marker xlator
+	keyMarkerQuota = "marker.quota"
+	// keyMarkerInodeQuota is the key which turns on the accounting of the
+	// number of files and directories under directories
+	keyMarkerInodeQuota = "marker.inode-quota"
+	// keyDefaultSoftLimit is the key for the soft limit used by the limits
+	// set without one, as a percentage of the hard limit
+	keyDefaultSoftLimit = "quota.default-soft-limit"
+
+	// defaultSoftLimitPercent is used if default-soft-limit isn't set
+	defaultSoftLimitPercent = 80
+
+	// reconfigureKeysTxnKey holds the keys of the options changed by the
+	// transaction, for the vol-option.ReconfigureBricks step
+	reconfigureKeysTxnKey = "reconfigurekeys"
+	// quotaUsageTxnKey holds the usage of the directories on the bricks
+	quotaUsageTxnKey = "quotausage"
+)
+
+// quotaKeys are the volume options set on quota enable and disable
+var quotaKeys = []string{keyServerQuota, keyMarkerQuota, keyMarkerInodeQuota}
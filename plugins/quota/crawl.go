@@ -0,0 +1,88 @@
+package quota
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// crawlClientPid is the client pid of the crawler mount. The marker xlator
+// updates the usage of the directories looked up by clients with this pid.
+const crawlClientPid = "-5"
+
+func mountForCrawl(volname, mountpoint, logfile string) error {
+	shost, sport, err := net.SplitHostPort(config.GetString("clientaddress"))
+	if err != nil {
+		return err
+	}
+
+	if shost == "" {
+		shost = "127.0.0.1"
+	}
+
+	cmd := exec.Command("glusterfs",
+		"--volfile-server", shost,
+		"--volfile-server-port", sport,
+		"--volfile-id", volname,
+		"--client-pid", crawlClientPid,
+		"--log-file", logfile,
+		mountpoint)
+	return cmd.Run() // glusterfs daemonizes itself
+}
+
+// crawlBricks looks up every directory and file on the local bricks of the
+// volume through a mount of the volume, so that the marker xlator accounts
+// the usage existing before quota was enabled.
+func crawlBricks(volinfo *volume.Volinfo, logger log.FieldLogger) error {
+	bricks := volinfo.GetLocalBricks()
+	if len(bricks) == 0 {
+		return nil
+	}
+
+	tempDir, err := ioutil.TempDir(config.GetString("rundir"), "quota-crawl")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempDir)
+
+	logfile := path.Join(config.GetString("logdir"), "glusterfs", "quota-crawl.log")
+	if err := mountForCrawl(volinfo.Name, tempDir, logfile); err != nil {
+		return err
+	}
+	defer syscall.Unmount(tempDir, syscall.MNT_FORCE)
+
+	for _, b := range bricks {
+		err := filepath.Walk(b.Path, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				// Entries can be removed while crawling
+				return nil
+			}
+			rel, err := filepath.Rel(b.Path, p)
+			if err != nil {
+				return err
+			}
+			if rel == ".glusterfs" && info.IsDir() {
+				return filepath.SkipDir
+			}
+			// The lookup of the entry through the mount is
+			// what matters, its result doesn't
+			os.Lstat(path.Join(tempDir, rel))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		logger.WithField("brick", b.String()).Info("quota crawl of brick completed")
+	}
+
+	return nil
+}
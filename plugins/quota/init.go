@@ -2,6 +2,9 @@ package quota
 
 import (
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/utils"
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
 )
 
 const name = "quota"
@@ -19,22 +22,37 @@ func (p *Plugin) Name() string {
 func (p *Plugin) RestRoutes() route.Routes {
 	return route.Routes{
 		route.Route{
-			Name:        "QuotaList",
-			Method:      "GET",
-			Pattern:     "/quota/{volname}/limit",
+			Name:        "QuotaEnable",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/quota/enable",
 			Version:     1,
-			HandlerFunc: quotaListHandler},
+			HandlerFunc: quotaEnableHandler},
+		route.Route{
+			Name:        "QuotaDisable",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/quota/disable",
+			Version:     1,
+			HandlerFunc: quotaDisableHandler},
+		route.Route{
+			Name:         "QuotaList",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/quota",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*quotaapi.ListResp)(nil)),
+			HandlerFunc:  quotaListHandler},
 		route.Route{
 			Name:        "QuotaLimit",
 			Method:      "POST",
-			Pattern:     "/quota/{volname}/limit",
+			Pattern:     "/volumes/{volname}/quota/limit",
 			Version:     1,
+			RequestType: utils.GetTypeString((*quotaapi.SetLimitReq)(nil)),
 			HandlerFunc: quotaLimitHandler},
 		route.Route{
 			Name:        "QuotaRemove",
 			Method:      "DELETE",
-			Pattern:     "/quota/{volname}/limit",
+			Pattern:     "/volumes/{volname}/quota/limit",
 			Version:     1,
+			RequestType: utils.GetTypeString((*quotaapi.RemoveLimitReq)(nil)),
 			HandlerFunc: quotaRemoveHandler},
 	}
 }
@@ -42,5 +60,12 @@ func (p *Plugin) RestRoutes() route.Routes {
 // RegisterStepFuncs registers transaction step functions with
 // Glusterd Transaction framework
 func (p *Plugin) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnQuotaEnableDisable, "quota-enable.Commit")
+	transaction.RegisterStepFunc(txnQuotaEnableDisable, "quota-disable.Commit")
+	transaction.RegisterStepFunc(txnQuotaCrawl, "quota-enable.Crawl")
+	transaction.RegisterStepFunc(txnQuotaLimitSet, "quota-limit.Set")
+	transaction.RegisterStepFunc(txnQuotaLimitSetUndo, "quota-limit.Set.Undo")
+	transaction.RegisterStepFunc(txnQuotaLimitRemove, "quota-limit.Remove")
+	transaction.RegisterStepFunc(txnQuotaUsage, "quota-list.Usage")
 	return
 }
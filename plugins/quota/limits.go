@@ -0,0 +1,63 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+)
+
+// quotaLimitsPrefix holds the directory quota limits of the volumes, keyed by
+// the volume name. The limits are renamed and deleted along with the volume.
+const quotaLimitsPrefix = "volume-quota/"
+
+func init() {
+	volume.RegisterSettingsPrefix(quotaLimitsPrefix)
+}
+
+// dirLimit is the limit set on a directory. The limits are also set as xattrs
+// on the directory in the bricks, the store keeps them to be listed.
+type dirLimit struct {
+	SizeLimit   int64 `json:"size-limit,omitempty"`
+	ObjectLimit int64 `json:"object-limit,omitempty"`
+	// SoftLimitPercent is 0 if the default soft limit of the volume is
+	// to be used
+	SoftLimitPercent int `json:"soft-limit-percent,omitempty"`
+}
+
+// getLimits returns the limits set on the directories of the volume, keyed
+// by the path of the directory
+func getLimits(volname string) (map[string]dirLimit, error) {
+	resp, err := store.Get(context.TODO(), quotaLimitsPrefix+volname)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make(map[string]dirLimit)
+	if resp.Count != 1 {
+		return limits, nil
+	}
+
+	if err := json.Unmarshal(resp.Kvs[0].Value, &limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// setLimits saves the limits set on the directories of the volume
+func setLimits(volname string, limits map[string]dirLimit) error {
+	b, err := json.Marshal(limits)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), quotaLimitsPrefix+volname, string(b))
+	return err
+}
+
+// deleteLimits deletes the limits of the volume
+func deleteLimits(volname string) error {
+	_, err := store.Delete(context.TODO(), quotaLimitsPrefix+volname)
+	return err
+}
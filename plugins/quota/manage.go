@@ -0,0 +1,135 @@
+package quota
+
+import (
+	"context"
+	"os"
+	"path"
+
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// isQuotadStopRequired checks if the quota daemon has to be stopped.
+// The quotad process needs to be stopped on a peer only when that peer
+// does not have bricks belonging to a quota enabled volume.
+func isQuotadStopRequired(volumes []*volume.Volinfo) bool {
+	for _, v := range volumes {
+		if !isQuotaEnabled(v) {
+			continue
+		} else if v.State != volume.VolStarted {
+			continue
+		} else {
+			bricks := v.GetLocalBricks()
+			if len(bricks) > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// getVolumesWith returns the volumes in the cluster, with the temporary
+// volinfo of the volume being changed in place of its stored volinfo
+func getVolumesWith(volinfo *volume.Volinfo) ([]*volume.Volinfo, error) {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	for idx, v := range volumes {
+		if v.Name == volinfo.Name {
+			volumes[idx] = volinfo
+			break
+		}
+	}
+	return volumes, nil
+}
+
+// generateQuotadVolfile generates the quotad volfile with the started
+// volumes which have quota enabled
+func generateQuotadVolfile(quotadDaemon *Quotad, volinfo *volume.Volinfo, volumes []*volume.Volinfo) error {
+	var quotaVolumes []*volume.Volinfo
+	for _, v := range volumes {
+		if v.State == volume.VolStarted && isQuotaEnabled(v) {
+			quotaVolumes = append(quotaVolumes, v)
+		}
+	}
+
+	tmpl, err := volgen.GetTemplateFromVolinfo(volinfo, utils.QuotadVolfile)
+	if err != nil {
+		return err
+	}
+
+	volfile, err := volgen.ClusterLevelVolfile(tmpl, quotaVolumes)
+	if err != nil {
+		return err
+	}
+
+	filename := path.Join(config.GetString("localstatedir"), "volfiles", quotadDaemon.VolfileID+".vol")
+	return volgen.SaveToFile(filename, volfile)
+}
+
+// manageQuotad stops quotad if no quota enabled volume has bricks on this
+// peer, otherwise restarts quotad with a volfile having the quota enabled
+// volumes. volinfo is the volinfo of the volume on which quota is being
+// enabled or disabled.
+func manageQuotad(volinfo *volume.Volinfo, logger log.FieldLogger) error {
+	quotadDaemon, err := NewQuotad()
+	if err != nil {
+		return err
+	}
+	// Create pidfile dir if not exists
+	if err := os.MkdirAll(path.Dir(quotadDaemon.pidfilepath),
+		os.ModeDir|os.ModePerm); err != nil {
+		return err
+	}
+
+	// Create logFiledir dir
+	if err := os.MkdirAll(path.Dir(quotadDaemon.logfilepath),
+		os.ModeDir|os.ModePerm); err != nil {
+		return err
+	}
+
+	volumes, err := getVolumesWith(volinfo)
+	if err != nil {
+		logger.WithError(err).Error("failed to get volumes")
+		return err
+	}
+
+	if isQuotadStopRequired(volumes) {
+		// This condition is for disabling quotad
+		err = daemon.Stop(quotadDaemon, true, logger)
+		if err == errors.ErrPidFileNotFound {
+			return nil
+		} else if err != nil {
+			logger.WithError(err).Error("quotad stop failed")
+		}
+		return err
+	}
+
+	// Quotad must be restarted whenever quota is enabled or disabled
+	// for a volume.
+	err = daemon.Stop(quotadDaemon, true, logger)
+	if err == errors.ErrPidFileNotFound {
+		logger.Info("quotad stop failed as pidfile missing")
+	} else if err != nil {
+		logger.WithError(err).Warn("quotad stop failed")
+	} else {
+		logger.Info("quotad stopped for restart")
+	}
+
+	if err = generateQuotadVolfile(quotadDaemon, volinfo, volumes); err != nil {
+		logger.WithError(err).Error("failed to generate quotad volfile")
+		return err
+	}
+
+	if err = daemon.Start(quotadDaemon, true, logger); err != nil {
+		logger.WithError(err).Error("quotad start failed")
+	}
+	return err
+}
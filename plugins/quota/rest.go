@@ -2,24 +2,583 @@ package quota
 
 import (
 	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
 )
 
+// setQuotaOptions sets the quota options of the volume, and the keys of
+// the changed options in the transaction context
+func setQuotaOptions(txn *transaction.Txn, volinfo *volume.Volinfo, value string) error {
+	for _, key := range quotaKeys {
+		volinfo.Options[key] = value
+	}
+
+	if err := txn.Ctx.Set(reconfigureKeysTxnKey, quotaKeys); err != nil {
+		return err
+	}
+	return txn.Ctx.Set("volinfo", volinfo)
+}
+
+func quotaEnableHandler(w http.ResponseWriter, r *http.Request) {
+	// Collect inputs from URL
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	// Validate volume existence
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// Check if volume is started
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	if isQuotaEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrQuotaAlreadyEnabled)
+		return
+	}
+
+	//save volume information for transaction failure scenario
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Turn on enforcement in the quota xlator and accounting in the marker
+	// xlator
+	if err := setQuotaOptions(txn, volinfo, "on"); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+		},
+		{
+			DoFunc:   "vol-option.GenerateBrickVolfiles",
+			UndoFunc: "vol-option.GenerateBrickvolfiles.Undo",
+			Nodes:    txn.Nodes,
+		},
+		{
+			DoFunc: "vol-option.ReconfigureBricks",
+			Nodes:  txn.Nodes,
+		},
+		{
+			DoFunc: "vol-option.NotifyVolfileChange",
+			Nodes:  txn.Nodes,
+			// Volinfo needs to be updated before sending notifications
+			Sync: true,
+		},
+		{
+			DoFunc: "quota-enable.Commit",
+			Nodes:  txn.Nodes,
+		},
+		{
+			// The crawl runs in the background, the usage existing
+			// before quota was enabled shows up as it progresses
+			DoFunc: "quota-enable.Crawl",
+			Nodes:  txn.Nodes,
+		},
+	}
+
+	if err = txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname",
+			volinfo.Name).Error("failed to enable quota")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
+func quotaDisableHandler(w http.ResponseWriter, r *http.Request) {
+	// Collect inputs from URL
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	// Validate volume existence
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if !isQuotaEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrQuotaNotEnabled)
+		return
+	}
+
+	limits, err := getLimits(volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	paths := make([]string, 0, len(limits))
+	for p := range limits {
+		paths = append(paths, p)
+	}
+
+	//save volume information for transaction failure scenario
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := setQuotaOptions(txn, volinfo, "off"); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// The limits are removed from the bricks, so that they aren't enforced
+	// again if quota is enabled later
+	if err := txn.Ctx.Set("paths", paths); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "quota-limit.Remove",
+			Nodes:  txn.Nodes,
+			Skip:   len(paths) == 0,
+		},
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+		},
+		{
+			DoFunc:   "vol-option.GenerateBrickVolfiles",
+			UndoFunc: "vol-option.GenerateBrickvolfiles.Undo",
+			Nodes:    txn.Nodes,
+		},
+		{
+			DoFunc: "vol-option.ReconfigureBricks",
+			Nodes:  txn.Nodes,
+		},
+		{
+			DoFunc: "vol-option.NotifyVolfileChange",
+			Nodes:  txn.Nodes,
+			// Volinfo needs to be updated before sending notifications
+			Sync: true,
+		},
+		{
+			DoFunc: "quota-disable.Commit",
+			Nodes:  txn.Nodes,
+		},
+	}
+
+	if err = txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname",
+			volinfo.Name).Error("failed to disable quota")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := deleteLimits(volname); err != nil {
+		logger.WithError(err).WithField("volname", volname).Warn("failed to delete quota limits")
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
 func quotaListHandler(w http.ResponseWriter, r *http.Request) {
-	// implement the help logic and send response back as below
+	volname := mux.Vars(r)["volname"]
+
 	ctx := r.Context()
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, "todo: quota list")
+	logger := gdctx.Logger(ctx)
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if !isQuotaEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrQuotaNotEnabled)
+		return
+	}
+
+	limits, err := getLimits(volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(limits) == 0 {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, quotaapi.ListResp{})
+		return
+	}
+
+	paths := make([]string, 0, len(limits))
+	for p := range limits {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("paths", paths); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "quota-list.Usage",
+			Nodes:  txn.Nodes,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to get quota usage")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	brickUsage := make(map[string]map[string]usage)
+	for _, node := range txn.Nodes {
+		var nodeUsage map[string]map[string]usage
+		if err := txn.Ctx.GetNodeResult(node, quotaUsageTxnKey, &nodeUsage); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		for id, u := range nodeUsage {
+			brickUsage[id] = u
+		}
+	}
+
+	resp := quotaapi.ListResp{}
+	defaultSoftLimit := getDefaultSoftLimit(volinfo)
+	for _, p := range paths {
+		u := volumeUsage(volinfo, brickUsage, p)
+		resp = append(resp, createLimits(p, limits[p], u, defaultSoftLimit)...)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// volumeUsage aggregates the usage of the directory on the bricks of the
+// volume. The bricks of a replicate subvolume have the same usage, while
+// those of a disperse subvolume have a fragment of the data each.
+func volumeUsage(volinfo *volume.Volinfo, brickUsage map[string]map[string]usage, dir string) usage {
+	var total usage
+	for _, subvol := range volinfo.Subvols {
+		var sv usage
+		for _, b := range subvol.Bricks {
+			u := brickUsage[b.ID.String()][dir]
+			if u.Size > sv.Size {
+				sv.Size = u.Size
+			}
+			if u.Files > sv.Files {
+				sv.Files = u.Files
+			}
+			if u.Dirs > sv.Dirs {
+				sv.Dirs = u.Dirs
+			}
+		}
+		if subvol.Type == volume.SubvolDisperse {
+			sv.Size *= int64(subvol.DisperseCount - subvol.RedundancyCount)
+		}
+		total.Size += sv.Size
+		total.Files += sv.Files
+		total.Dirs += sv.Dirs
+	}
+	return total
+}
+
+// getDefaultSoftLimit returns the soft limit percentage used by the limits
+// set without one
+func getDefaultSoftLimit(volinfo *volume.Volinfo) int {
+	val, ok := volinfo.Options[keyDefaultSoftLimit]
+	if !ok {
+		return defaultSoftLimitPercent
+	}
+	pct, err := strconv.Atoi(strings.TrimSuffix(val, "%"))
+	if err != nil {
+		return defaultSoftLimitPercent
+	}
+	return pct
+}
+
+// createLimits returns the entries to be listed for the limits set on the
+// directory, one for each type of limit
+func createLimits(dir string, limit dirLimit, u usage, defaultSoftLimit int) []quotaapi.Limit {
+	softPercent := limit.SoftLimitPercent
+	if softPercent == 0 {
+		softPercent = defaultSoftLimit
+	}
+
+	newLimit := func(limitType int32, hard, used int64) quotaapi.Limit {
+		l := quotaapi.Limit{
+			Path:      dir,
+			HardLimit: hard,
+			SoftLimit: hard * int64(softPercent) / 100,
+			Used:      used,
+			LimitType: limitType,
+		}
+		if used < hard {
+			l.Available = hard - used
+		}
+		l.SoftLimitExceeded = used > l.SoftLimit
+		l.HardLimitExceeded = used >= hard
+		return l
+	}
+
+	var limits []quotaapi.Limit
+	if limit.SizeLimit > 0 {
+		limits = append(limits, newLimit(quotaapi.LimitTypeUsage, limit.SizeLimit, u.Size))
+	}
+	if limit.ObjectLimit > 0 {
+		limits = append(limits, newLimit(quotaapi.LimitTypeObjects, limit.ObjectLimit, u.Files+u.Dirs))
+	}
+	return limits
+}
+
+// cleanLimitPath returns the path of the directory relative to the volume
+// root, in the form the limits are stored
+func cleanLimitPath(p string) (string, bool) {
+	if !strings.HasPrefix(p, "/") {
+		return "", false
+	}
+	return path.Clean(p), true
 }
 
 func quotaLimitHandler(w http.ResponseWriter, r *http.Request) {
-	// implement the help logic and send response back as below
+	volname := mux.Vars(r)["volname"]
+
 	ctx := r.Context()
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, "Todo: limitusage")
+	logger := gdctx.Logger(ctx)
+
+	var req quotaapi.SetLimitReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	dir, ok := cleanLimitPath(req.Path)
+	if !ok {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "path must be an absolute path in the volume")
+		return
+	}
+	if req.SizeUsageLimit < 0 || req.ObjectCountLimit < 0 ||
+		(req.SizeUsageLimit == 0 && req.ObjectCountLimit == 0) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "a positive size usage limit or object count limit is required")
+		return
+	}
+	if req.SoftLimitPercent < 0 || req.SoftLimitPercent > 100 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "soft limit percent must be between 0 and 100")
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	if !isQuotaEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrQuotaNotEnabled)
+		return
+	}
+
+	limits, err := getLimits(volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// A limit of a type not in the request is left as it is
+	oldLimit := limits[dir]
+	limit := oldLimit
+	if req.SizeUsageLimit > 0 {
+		limit.SizeLimit = int64(req.SizeUsageLimit)
+	}
+	if req.ObjectCountLimit > 0 {
+		limit.ObjectLimit = int64(req.ObjectCountLimit)
+	}
+	if req.SoftLimitPercent > 0 {
+		limit.SoftLimitPercent = req.SoftLimitPercent
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("path", dir); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("limit", limit); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("oldlimit", oldLimit); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "quota-limit.Set",
+			UndoFunc: "quota-limit.Set.Undo",
+			Nodes:    txn.Nodes,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to set quota limit")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	limits[dir] = limit
+	if err := setLimits(volname, limits); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
 }
 
 func quotaRemoveHandler(w http.ResponseWriter, r *http.Request) {
-	// implement the help logic and send response back as below
+	volname := mux.Vars(r)["volname"]
+
 	ctx := r.Context()
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, "Todo: quota Remove")
+	logger := gdctx.Logger(ctx)
+
+	var req quotaapi.RemoveLimitReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	dir, ok := cleanLimitPath(req.Path)
+	if !ok {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "path must be an absolute path in the volume")
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	limits, err := getLimits(volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if _, ok := limits[dir]; !ok {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrQuotaLimitNotFound)
+		return
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("paths", []string{dir}); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "quota-limit.Remove",
+			Nodes:  txn.Nodes,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to remove quota limit")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	delete(limits, dir)
+	if err := setLimits(volname, limits); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
 }
@@ -0,0 +1,193 @@
+package quota
+
+import (
+	"os"
+	"path"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"golang.org/x/sys/unix"
+)
+
+func txnQuotaEnableDisable(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		c.Logger().WithError(err).WithField(
+			"key", "volinfo").Error("failed to get value for key from context")
+		return err
+	}
+
+	return manageQuotad(&volinfo, c.Logger())
+}
+
+func txnQuotaCrawl(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		c.Logger().WithError(err).WithField(
+			"key", "volinfo").Error("failed to get value for key from context")
+		return err
+	}
+
+	// Crawling can take long on large bricks, the usage of directories
+	// shows up in the quota list as the crawl progresses
+	logger := c.Logger().WithField("volume", volinfo.Name)
+	go func() {
+		if err := crawlBricks(&volinfo, logger); err != nil {
+			logger.WithError(err).Error("quota crawl failed")
+		}
+	}()
+	return nil
+}
+
+// applyLimit sets the limit xattrs on the directory in the local bricks. The
+// limits which aren't set are removed.
+func applyLimit(volinfo *volume.Volinfo, dir string, limit dirLimit) error {
+	for _, b := range volinfo.GetLocalBricks() {
+		p := path.Join(b.Path, dir)
+
+		var err error
+		if limit.SizeLimit > 0 {
+			err = unix.Setxattr(p, limitSizeXattrKey, encodeLimit(limit.SizeLimit, limit.SoftLimitPercent), 0)
+		} else {
+			err = removeLimitXattr(p, limitSizeXattrKey)
+		}
+		if err != nil {
+			return err
+		}
+
+		if limit.ObjectLimit > 0 {
+			err = unix.Setxattr(p, limitObjectsXattrKey, encodeLimit(limit.ObjectLimit, limit.SoftLimitPercent), 0)
+		} else {
+			err = removeLimitXattr(p, limitObjectsXattrKey)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeLimitXattr removes the limit xattr, if it is set
+func removeLimitXattr(p, key string) error {
+	if err := unix.Removexattr(p, key); err != nil && err != unix.ENODATA {
+		return err
+	}
+	return nil
+}
+
+func txnQuotaLimitSet(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var dir string
+	if err := c.Get("path", &dir); err != nil {
+		return err
+	}
+
+	var limit dirLimit
+	if err := c.Get("limit", &limit); err != nil {
+		return err
+	}
+
+	for _, b := range volinfo.GetLocalBricks() {
+		p := path.Join(b.Path, dir)
+		info, err := os.Lstat(p)
+		if err != nil {
+			c.Logger().WithError(err).WithField("path", p).Error("failed to find directory on brick")
+			return err
+		}
+		if !info.IsDir() {
+			return errors.ErrQuotaPathNotDir
+		}
+	}
+
+	return applyLimit(&volinfo, dir, limit)
+}
+
+// txnQuotaLimitSetUndo restores the limit which was set on the directory
+// before, or removes the limit if there was none
+func txnQuotaLimitSetUndo(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var dir string
+	if err := c.Get("path", &dir); err != nil {
+		return err
+	}
+
+	var oldLimit dirLimit
+	if err := c.Get("oldlimit", &oldLimit); err != nil {
+		return err
+	}
+
+	err := applyLimit(&volinfo, dir, oldLimit)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// txnQuotaLimitRemove removes the limits set on the directories
+func txnQuotaLimitRemove(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var dirs []string
+	if err := c.Get("paths", &dirs); err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		// The directory may have been deleted after the limit was set
+		if err := applyLimit(&volinfo, dir, dirLimit{}); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// txnQuotaUsage reads the usage of the directories having limits from the
+// local bricks. The usage is keyed by the brick ID and the directory path.
+func txnQuotaUsage(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var dirs []string
+	if err := c.Get("paths", &dirs); err != nil {
+		return err
+	}
+
+	result := make(map[string]map[string]usage)
+	for _, b := range volinfo.GetLocalBricks() {
+		brickUsage := make(map[string]usage)
+		for _, dir := range dirs {
+			p := path.Join(b.Path, dir)
+			buf := make([]byte, 24)
+			size, err := unix.Getxattr(p, sizeXattrKey, buf)
+			if err != nil {
+				// The directory isn't accounted yet
+				continue
+			}
+			u, err := decodeSize(buf[:size])
+			if err != nil {
+				c.Logger().WithError(err).WithField("path", p).Warn("failed to decode quota size")
+				continue
+			}
+			brickUsage[dir] = u
+		}
+		result[b.ID.String()] = brickUsage
+	}
+
+	return c.SetNodeResult(gdctx.MyUUID, quotaUsageTxnKey, result)
+}
@@ -3,16 +3,11 @@ package quota
 import (
 	"fmt"
 
-	daemon "github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/glusterd2/xlator"
 	"github.com/gluster/glusterd2/pkg/errors"
 )
 
-const (
-	quotaEnabledKey = "quota.enable"
-)
-
 func validateOptions(v *volume.Volinfo, key, value string) error {
 
 	if v.State != volume.VolStarted {
@@ -20,27 +15,10 @@ func validateOptions(v *volume.Volinfo, key, value string) error {
 	}
 
 	switch key {
-	case "enable":
-		switch value {
-		case "on":
-			// As quotad for various process share a
-			// single pid, it is necessary to check the
-			// options too.
-			if isQuotadRunning() && isQuotaEnabled(v) {
-				return errors.ErrProcessAlreadyRunning
-			}
-		case "off":
-			// As quotad for various process share a
-			// single pid, it is necessary to check the
-			// options too.
-			if !isQuotaEnabled(v) {
-				return errors.ErrQuotadNotEnabled
-			}
-			if !isQuotadRunning() {
-				return errors.ErrQuotadNotRunning
-			}
-		}
-		return nil
+	case "enable", "server-quota":
+		// Enabling quota also needs the marker options to be set,
+		// quotad to be started and the bricks to be crawled
+		return fmt.Errorf("quota must be enabled or disabled using the volume quota API, not with '%s' option", key)
 	case "deem-statfs":
 		fallthrough
 	case "hard-timeout":
@@ -63,32 +41,13 @@ func validateOptions(v *volume.Volinfo, key, value string) error {
 // isQuotaEnabled is used to check if the quota option is enabled for
 // that particular volume.
 func isQuotaEnabled(v *volume.Volinfo) bool {
-	val, exists := v.Options[quotaEnabledKey]
+	val, exists := v.Options[keyServerQuota]
 	if exists && val == "on" {
 		return true
 	}
 	return false
 }
 
-// isQuotadRunning returns true for running and false for
-// failures and not running on that machine.
-// It is not volume based.
-func isQuotadRunning() bool {
-	quotadDaemon, err := NewQuotad()
-	if err != nil {
-		return false
-	}
-	pid, err := daemon.ReadPidFromFile(quotadDaemon.PidFile())
-	if err != nil {
-		return false
-	}
-
-	if _, err = daemon.GetProcess(pid); err != nil {
-		return false
-	}
-	return true
-}
-
 func init() {
 	xlator.RegisterValidationFunc(name, validateOptions)
 }
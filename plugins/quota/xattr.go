@@ -0,0 +1,58 @@
+package quota
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// limitSizeXattrKey holds the size limit of a directory, set on the
+	// directory in every brick
+	limitSizeXattrKey = "trusted.glusterfs.quota.limit-set"
+	// limitObjectsXattrKey holds the limit of the number of files and
+	// directories under a directory
+	limitObjectsXattrKey = "trusted.glusterfs.quota.limit-objects"
+	// sizeXattrKey holds the usage of a directory in a brick, accounted
+	// by the marker xlator
+	sizeXattrKey = "trusted.glusterfs.quota.size"
+)
+
+// usage is the usage of a directory
+type usage struct {
+	Size  int64 `json:"size"`
+	Files int64 `json:"files"`
+	Dirs  int64 `json:"dirs"`
+}
+
+// encodeLimit encodes a limit in the format of the limit xattrs, the hard
+// limit followed by the soft limit percentage as big endian 64 bit integers.
+// A soft limit percentage of -1 makes the quota xlator use the default soft
+// limit.
+func encodeLimit(hard int64, softPercent int) []byte {
+	if softPercent == 0 {
+		softPercent = -1
+	}
+
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b[:8], uint64(hard))
+	binary.BigEndian.PutUint64(b[8:], uint64(int64(softPercent)))
+	return b
+}
+
+// decodeSize decodes the size xattr of a directory. The xattr has the size
+// followed by the number of files and directories, as big endian 64 bit
+// integers. Older bricks have only the size.
+func decodeSize(b []byte) (usage, error) {
+	var u usage
+	switch len(b) {
+	case 24:
+		u.Files = int64(binary.BigEndian.Uint64(b[8:16]))
+		u.Dirs = int64(binary.BigEndian.Uint64(b[16:]))
+		fallthrough
+	case 8:
+		u.Size = int64(binary.BigEndian.Uint64(b[:8]))
+	default:
+		return u, fmt.Errorf("invalid length %d of quota size xattr", len(b))
+	}
+	return u, nil
+}
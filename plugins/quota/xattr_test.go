@@ -0,0 +1,38 @@
+package quota
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeLimit(t *testing.T) {
+	b := encodeLimit(1024, 50)
+	assert.Len(t, b, 16)
+	assert.Equal(t, uint64(1024), binary.BigEndian.Uint64(b[:8]))
+	assert.Equal(t, uint64(50), binary.BigEndian.Uint64(b[8:]))
+
+	// The default soft limit is used if it isn't set
+	b = encodeLimit(1024, 0)
+	assert.Equal(t, int64(-1), int64(binary.BigEndian.Uint64(b[8:])))
+}
+
+func TestDecodeSize(t *testing.T) {
+	b := make([]byte, 24)
+	binary.BigEndian.PutUint64(b[:8], 4096)
+	binary.BigEndian.PutUint64(b[8:16], 3)
+	binary.BigEndian.PutUint64(b[16:], 2)
+
+	u, err := decodeSize(b)
+	assert.Nil(t, err)
+	assert.Equal(t, usage{Size: 4096, Files: 3, Dirs: 2}, u)
+
+	// Older bricks have only the size
+	u, err = decodeSize(b[:8])
+	assert.Nil(t, err)
+	assert.Equal(t, usage{Size: 4096}, u)
+
+	_, err = decodeSize(b[:10])
+	assert.NotNil(t, err)
+}
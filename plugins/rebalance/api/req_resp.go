@@ -18,6 +18,17 @@ const (
 	Complete
 	// Failed should be set only for a node that are failed to run rebalance process
 	Failed
+	// Paused should be set only for a rebalance whose processes were stopped
+	// to be resumed later
+	Paused
+)
+
+// Rebalance throttles, which set the number of files migrated in parallel by
+// each rebalance process
+const (
+	ThrottleLazy       = "lazy"
+	ThrottleNormal     = "normal"
+	ThrottleAggressive = "aggressive"
 )
 
 // Command represents Rebalance Commands
@@ -49,6 +60,35 @@ type RebalNodeStatus struct {
 	RebalanceFailures string    `json:"failed"`
 	ElapsedTime       string    `json:"run-time"`
 	TimeLeft          string    `json:"time-left"`
+	// EstimatedTimeLeft is the time in seconds the node is estimated to
+	// take to complete, from the files it has looked up so far
+	EstimatedTimeLeft int64 `json:"estimated-time-left"`
+}
+
+// Mode returns the rebalance mode started by the command
+func (c Command) Mode() string {
+	switch c {
+	case CmdFixLayoutStart:
+		return "fix-layout"
+	case CmdStartForce:
+		return "force"
+	case CmdStart:
+		return "migrate-data"
+	}
+	return ""
+}
+
+// SkipRules exclude files from being migrated by the rebalance. Files are
+// skipped if they are under any of the paths, or if their size is outside the
+// size thresholds. Skip rules don't apply to a fix-layout rebalance.
+type SkipRules struct {
+	// Paths are the directories, absolute from the volume root, whose
+	// files are not migrated
+	Paths []string `json:"paths,omitempty"`
+	// MinFileSize is the size in bytes below which files are not migrated
+	MinFileSize uint64 `json:"min-file-size,omitempty"`
+	// MaxFileSize is the size in bytes above which files are not migrated
+	MaxFileSize uint64 `json:"max-file-size,omitempty"`
 }
 
 // RebalInfo represents the rebalance operation information
@@ -58,17 +98,36 @@ type RebalInfo struct {
 	Cmd         Command
 	RebalanceID uuid.UUID
 	CommitHash  uint64
-	RebalStats  []RebalNodeStatus
+	// Mode is the mode the rebalance was started in, retained when
+	// the rebalance is stopped
+	Mode       string
+	SkipRules  *SkipRules
+	Throttle   string
+	RebalStats []RebalNodeStatus
 }
 
 // RebalStatus represents the rebalance status response
 type RebalStatus struct {
 	Volname     string            `json:"volume"`
 	RebalanceID uuid.UUID         `json:"rebalance-id"`
+	Mode        string            `json:"mode"`
+	SkipRules   *SkipRules        `json:"skip-rules,omitempty"`
+	Paused      bool              `json:"paused"`
+	Throttle    string            `json:"throttle,omitempty"`
 	Nodes       []RebalNodeStatus `json:"nodes-status"`
+	// EstimatedTimeLeft is the time in seconds the slowest node is
+	// estimated to take to complete
+	EstimatedTimeLeft int64 `json:"estimated-time-left"`
 }
 
 // StartReq contains the options passed to the Rebalance Start Request
 type StartReq struct {
-	Option string `json:"option,omitempty"`
+	Option    string     `json:"option,omitempty"`
+	SkipRules *SkipRules `json:"skip-rules,omitempty"`
+	Throttle  string     `json:"throttle,omitempty"`
+}
+
+// ThrottleReq contains the throttle to be set on a rebalance
+type ThrottleReq struct {
+	Throttle string `json:"throttle"`
 }
@@ -11,4 +11,19 @@ var (
 	ErrRebalanceNotStarted = errors.New("rebalance not started")
 	// ErrRebalanceInvalidOption : Invalid option provided to the rebalance start command
 	ErrRebalanceInvalidOption = errors.New("invalid Rebalance start option")
+	// ErrSkipRulesWithFixLayout : Skip rules cannot be used with a fix-layout rebalance, which doesn't migrate files
+	ErrSkipRulesWithFixLayout = errors.New("skip rules cannot be used with fix-layout")
+	// ErrRebalanceNotPaused : Rebalance not paused on the volume
+	ErrRebalanceNotPaused = errors.New("rebalance not paused")
+	// ErrRebalanceInvalidThrottle : Invalid throttle provided for the rebalance
+	ErrRebalanceInvalidThrottle = errors.New("rebalance throttle can only be lazy, normal or aggressive")
 )
+
+// skipRulesError is returned when the skip rules of a rebalance are invalid
+type skipRulesError struct {
+	reason string
+}
+
+func (e *skipRulesError) Error() string {
+	return "invalid skip rules: " + e.reason
+}
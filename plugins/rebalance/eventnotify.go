@@ -63,7 +63,9 @@ func HandleEventNotify(status map[string]string) error {
 	rebalNodeStatus.TimeLeft = status["time-left"]
 
 	rebalinfo.RebalStats = append(rebalinfo.RebalStats, rebalNodeStatus)
-	if len(rebalinfo.RebalStats) == len(vol.Nodes()) {
+	// The processes of a paused rebalance exit when it is paused, and are
+	// started again when it is resumed
+	if len(rebalinfo.RebalStats) == len(vol.Nodes()) && rebalinfo.State != rebalanceapi.Paused {
 		rebalinfo.State = rebalanceapi.Complete
 	}
 
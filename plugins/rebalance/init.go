@@ -26,6 +26,8 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Version:     1,
 			RequestType: utils.GetTypeString((*rebalanceapi.StartReq)(nil)),
 			//			ResponseType: utils.GetTypeString((*rebalanceapi.RebalInfo)(nil)),
+			Permission:  route.PermOperate,
+			Heavy:       true,
 			HandlerFunc: rebalanceStartHandler},
 		route.Route{
 			Name:    "RebalanceStop",
@@ -33,7 +35,29 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Pattern: "/volumes/{volname}/rebalance/stop",
 			Version: 1,
 			//			ResponseType: utils.GetTypeString((*rebalanceapi.RebalInfo)(nil)),
+			Permission:  route.PermOperate,
 			HandlerFunc: rebalanceStopHandler},
+		route.Route{
+			Name:        "RebalancePause",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/rebalance/pause",
+			Version:     1,
+			Permission:  route.PermOperate,
+			HandlerFunc: rebalancePauseHandler},
+		route.Route{
+			Name:        "RebalanceResume",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/rebalance/resume",
+			Version:     1,
+			Permission:  route.PermOperate,
+			HandlerFunc: rebalanceResumeHandler},
+		route.Route{
+			Name:        "RebalanceThrottle",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/rebalance/throttle",
+			Version:     1,
+			RequestType: utils.GetTypeString((*rebalanceapi.ThrottleReq)(nil)),
+			HandlerFunc: rebalanceThrottleHandler},
 		route.Route{
 			Name:    "RebalanceStatus",
 			Method:  "GET",
@@ -51,4 +75,6 @@ func (p *Plugin) RegisterStepFuncs() {
 	transaction.RegisterStepFunc(txnRebalanceStop, "rebalance-stop")
 	transaction.RegisterStepFunc(txnRebalanceStatus, "rebalance-status")
 	transaction.RegisterStepFunc(txnRebalanceStoreDetails, "rebalance-store")
+	transaction.RegisterStepFunc(txnRebalanceThrottle, "rebalance-throttle")
+	registerIOOpHandler()
 }
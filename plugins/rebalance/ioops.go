@@ -0,0 +1,59 @@
+package rebalance
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/ioops"
+
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
+)
+
+func registerIOOpHandler() {
+	ioops.Register(ioops.OpRebalance, &ioops.Handler{
+		Start:    startQueuedRebalance,
+		Running:  rebalanceRunning,
+		Throttle: throttleRebalance,
+	})
+}
+
+func startQueuedRebalance(ctx context.Context, c *ioops.Claim) error {
+	var req rebalanceapi.StartReq
+	if len(c.Args) > 0 {
+		if err := json.Unmarshal(c.Args, &req); err != nil {
+			return err
+		}
+	}
+	_, err := StartRebalance(ctx, c.Volume, &req)
+	return err
+}
+
+// rebalanceRunning returns true if the rebalance of the volume is running. A
+// paused rebalance keeps its claim on the bricks, as it is to be resumed.
+func rebalanceRunning(ctx context.Context, c *ioops.Claim) (bool, error) {
+	rebalinfo, err := GetRebalanceInfo(c.Volume)
+	if err != nil {
+		return false, err
+	}
+	return rebalinfo.State == rebalanceapi.Started || rebalinfo.State == rebalanceapi.Paused, nil
+}
+
+// throttleRebalance sets the rebalance to the lazy throttle, and restores
+// the throttle it was started with
+func throttleRebalance(ctx context.Context, c *ioops.Claim, throttled bool) error {
+	throttle := rebalanceapi.ThrottleLazy
+	if !throttled {
+		var req rebalanceapi.StartReq
+		if len(c.Args) > 0 {
+			if err := json.Unmarshal(c.Args, &req); err != nil {
+				return err
+			}
+		}
+		throttle = req.Throttle
+		if throttle == "" {
+			throttle = rebalanceapi.ThrottleNormal
+		}
+	}
+	_, err := setThrottle(ctx, c.Volume, throttle)
+	return err
+}
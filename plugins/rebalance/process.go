@@ -6,6 +6,7 @@ import (
 	"net"
 	"os/exec"
 	"path"
+	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
@@ -69,6 +70,17 @@ func (r *Process) Args() []string {
 	r.args = append(r.args, "--xlator-option", fmt.Sprintf("*distribute.rebalance-cmd=%d", cmd))
 	r.args = append(r.args, "--xlator-option", fmt.Sprintf("*distribute.node-uuid=%s", gdctx.MyUUID))
 	r.args = append(r.args, "--xlator-option", fmt.Sprintf("*distribute.commit-hash=%d", commithash))
+	if rules := r.rInfo.SkipRules; rules != nil {
+		if len(rules.Paths) != 0 {
+			r.args = append(r.args, "--xlator-option", fmt.Sprintf("*distribute.rebalance-skip-paths=%s", strings.Join(rules.Paths, ",")))
+		}
+		if rules.MinFileSize != 0 {
+			r.args = append(r.args, "--xlator-option", fmt.Sprintf("*distribute.rebalance-skip-min-size=%d", rules.MinFileSize))
+		}
+		if rules.MaxFileSize != 0 {
+			r.args = append(r.args, "--xlator-option", fmt.Sprintf("*distribute.rebalance-skip-max-size=%d", rules.MaxFileSize))
+		}
+	}
 	r.args = append(r.args, "-p", r.PidFile())
 	r.args = append(r.args, "--socket-file", r.SocketFile())
 	r.args = append(r.args, "-l", logFile)
@@ -1,10 +1,12 @@
 package rebalance
 
 import (
+	"context"
 	"io"
 	"net/http"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/ioops"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volume"
@@ -18,12 +20,16 @@ import (
 )
 
 func createRebalanceInfo(volname string, req *rebalanceapi.StartReq) *rebalanceapi.RebalInfo {
+	cmd := getCmd(req)
 	return &rebalanceapi.RebalInfo{
 		Volname:     volname,
 		RebalanceID: uuid.NewRandom(),
 		State:       rebalanceapi.Started,
-		Cmd:         getCmd(req),
+		Cmd:         cmd,
 		CommitHash:  setCommitHash(),
+		Mode:        cmd.Mode(),
+		SkipRules:   req.SkipRules,
+		Throttle:    req.Throttle,
 		RebalStats:  []rebalanceapi.RebalNodeStatus{},
 	}
 }
@@ -31,7 +37,6 @@ func createRebalanceInfo(volname string, req *rebalanceapi.StartReq) *rebalancea
 func rebalanceStartHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
 
 	// collect inputs from url
 	volname := mux.Vars(r)["volname"]
@@ -45,41 +50,93 @@ func rebalanceStartHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rebalinfo := createRebalanceInfo(volname, &req)
-	if rebalinfo == nil {
-		logger.WithError(err).Error("failed to create Rebalance info")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+	if restutils.AsyncRequested(r) {
+		restutils.SendAsyncJob(ctx, w, "rebalance-start", volname, func(ctx context.Context) (interface{}, error) {
+			rebalinfo, err := StartRebalance(ctx, volname, &req)
+			if err == ioops.ErrQueued {
+				// The rebalance starts once the conflicting
+				// operations finish, there is no result yet
+				return nil, nil
+			} else if err != nil {
+				return nil, err
+			}
+			return rebalinfo.RebalanceID, nil
+		}, startErrStatus)
 		return
 	}
 
-	if rebalinfo.Cmd == rebalanceapi.CmdNone {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, ErrRebalanceInvalidOption)
+	rebalinfo, err := StartRebalance(ctx, volname, &req)
+	if err == ioops.ErrQueued {
+		restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, nil)
+		return
+	} else if err != nil {
+		status := startErrStatus(err)
+		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
 
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, rebalinfo.RebalanceID)
+}
+
+// startErrStatus returns the HTTP status of an error returned by
+// StartRebalance
+func startErrStatus(err error) int {
+	switch err {
+	case ErrRebalanceInvalidOption, ErrSkipRulesWithFixLayout, ErrVolNotDistribute, ErrRebalanceInvalidThrottle, errors.ErrVolNotStarted:
+		return http.StatusBadRequest
+	}
+	if _, ok := err.(*skipRulesError); ok {
+		return http.StatusBadRequest
+	}
+	status, _ := restutils.ErrToStatusCode(err)
+	return status
+}
+
+// StartRebalance starts rebalance on the volume with the options in req and
+// returns the resulting rebalance info. It is used by the rebalance start
+// REST API and by GD2 components which need to kick off a rebalance.
+func StartRebalance(ctx context.Context, volname string, req *rebalanceapi.StartReq) (*rebalanceapi.RebalInfo, error) {
+
+	logger := gdctx.Logger(ctx)
+
+	rebalinfo := createRebalanceInfo(volname, req)
+	if rebalinfo.Cmd == rebalanceapi.CmdNone {
+		return nil, ErrRebalanceInvalidOption
+	}
+
+	if err := validateSkipRules(rebalinfo.Cmd, rebalinfo.SkipRules); err != nil {
+		return nil, err
+	}
+
+	if rebalinfo.Throttle != "" {
+		if err := validateThrottle(rebalinfo.Throttle); err != nil {
+			return nil, err
+		}
+	}
+
 	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, err
 	}
 	defer txn.Done()
 
 	vol, err := volume.GetVolume(volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, err
 	}
 
 	if vol.State != volume.VolStarted {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
-		return
+		return nil, errors.ErrVolNotStarted
 	}
 
 	if vol.DistCount == 1 {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, ErrVolNotDistribute)
-		return
+		return nil, ErrVolNotDistribute
+	}
+
+	// Rebalance isn't run together with the other IO intensive operations
+	// on the bricks of the volume
+	if err := ioops.Acquire(ctx, ioops.OpRebalance, vol, req); err != nil {
+		return nil, err
 	}
 
 	// TODO: Check for remove-brick
@@ -103,22 +160,19 @@ func rebalanceStartHandler(w http.ResponseWriter, r *http.Request) {
 	err = txn.Ctx.Set("volname", volname)
 	if err != nil {
 		logger.WithError(err).Error("failed to set volname in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
 	err = txn.Ctx.Set("volinfo", vol)
 	if err != nil {
 		logger.WithError(err).Error("failed to set volinfo in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
 	err = txn.Ctx.Set("rinfo", rebalinfo)
 	if err != nil {
 		logger.WithError(err).Error("failed to set rebalance info in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
 	err = txn.Do()
@@ -128,24 +182,28 @@ func rebalanceStartHandler(w http.ResponseWriter, r *http.Request) {
 		 * Need to handle scenarios where process is started in
 		 * few nodes and failed in few others */
 		logger.WithError(err).WithField("volname", volname).Error("failed to start rebalance on volume")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		if err := ioops.Release(ioops.OpRebalance, volname); err != nil {
+			logger.WithError(err).WithField("volname", volname).Warn("failed to release bricks claimed for rebalance")
+		}
+		return nil, err
 	}
 
-	rebalinfo, err = GetRebalanceInfo(volname)
+	stored, err := GetRebalanceInfo(volname)
 	if err != nil {
 		logger.WithError(err).WithField(
 			"volname", volname).Error("failed to get the rebalance info for volume")
+	} else {
+		rebalinfo = stored
 	}
 
 	logger.WithField("volname", rebalinfo.Volname).Info("rebalance started")
 
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, rebalinfo.RebalanceID)
+	return rebalinfo, nil
 }
 
 func rebalanceStopHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	// collect inputs from url
 	volname := mux.Vars(r)["volname"]
@@ -216,13 +274,250 @@ func rebalanceStopHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := ioops.Release(ioops.OpRebalance, volname); err != nil {
+		logger.WithError(err).WithField("volname", volname).Warn("failed to release bricks claimed for rebalance")
+	}
+
 	logger.WithField("volname", rebalinfo.Volname).Info("rebalance stopped")
 	restutils.SendHTTPResponse(r.Context(), w, http.StatusOK, rebalinfo)
 }
 
+// rebalancePauseHandler stops the rebalance processes of the volume, keeping
+// the rebalance info so that the rebalance can be resumed with the same
+// rebalance ID and commit hash
+func rebalancePauseHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	// collect inputs from url
+	volname := mux.Vars(r)["volname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	rebalinfo, err := GetRebalanceInfo(volname)
+	if err != nil || rebalinfo.State != rebalanceapi.Started {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, ErrRebalanceNotStarted)
+		return
+	}
+
+	txn.Nodes = vol.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "rebalance-stop",
+			Nodes:  txn.Nodes,
+		},
+		{
+			DoFunc: "rebalance-store",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+			Sync:   true,
+		},
+	}
+
+	if err := txn.Ctx.Set("volname", volname); err != nil {
+		logger.WithError(err).Error("failed to set volname in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	rebalinfo.State = rebalanceapi.Paused
+	if err := txn.Ctx.Set("rinfo", rebalinfo); err != nil {
+		logger.WithError(err).Error("failed to set rebalance info in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to pause rebalance on volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("volname", volname).Info("rebalance paused")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, rebalinfo)
+}
+
+// rebalanceResumeHandler starts the rebalance processes of a paused
+// rebalance again. Files already migrated are in place, and are skipped.
+func rebalanceResumeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.Logger(ctx)
+
+	// collect inputs from url
+	volname := mux.Vars(r)["volname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if vol.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	rebalinfo, err := GetRebalanceInfo(volname)
+	if err != nil || rebalinfo.State != rebalanceapi.Paused {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, ErrRebalanceNotPaused)
+		return
+	}
+
+	txn.Nodes = vol.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "rebalance-start",
+			Nodes:  txn.Nodes,
+		},
+		{
+			DoFunc: "rebalance-store",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+			Sync:   true,
+		},
+	}
+
+	if err := txn.Ctx.Set("volinfo", vol); err != nil {
+		logger.WithError(err).Error("failed to set volinfo in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// The status of the paused processes is dropped, the resumed processes
+	// report their status afresh
+	rebalinfo.State = rebalanceapi.Started
+	rebalinfo.RebalStats = []rebalanceapi.RebalNodeStatus{}
+	if err := txn.Ctx.Set("rinfo", rebalinfo); err != nil {
+		logger.WithError(err).Error("failed to set rebalance info in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to resume rebalance on volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("volname", volname).Info("rebalance resumed")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, rebalinfo)
+}
+
+// rebalanceThrottleHandler sets the throttle of the rebalance of the volume.
+// The throttle is applied to the running rebalance processes, and is kept
+// when a paused rebalance is resumed.
+func rebalanceThrottleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// collect inputs from url
+	volname := mux.Vars(r)["volname"]
+
+	var req rebalanceapi.ThrottleReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if err := validateThrottle(req.Throttle); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	rebalinfo, err := setThrottle(ctx, volname, req.Throttle)
+	if err == ErrRebalanceNotStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, rebalinfo)
+}
+
+// setThrottle sets the throttle of the rebalance of the volume and returns
+// the updated rebalance info
+func setThrottle(ctx context.Context, volname, throttle string) (*rebalanceapi.RebalInfo, error) {
+	logger := gdctx.Logger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Done()
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		return nil, err
+	}
+
+	rebalinfo, err := GetRebalanceInfo(volname)
+	if err != nil {
+		return nil, ErrRebalanceNotStarted
+	}
+
+	txn.Nodes = vol.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "rebalance-throttle",
+			Nodes:  txn.Nodes,
+		},
+		{
+			DoFunc: "rebalance-store",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+			Sync:   true,
+		},
+	}
+
+	if err := txn.Ctx.Set("volinfo", vol); err != nil {
+		logger.WithError(err).Error("failed to set volinfo in transaction context")
+		return nil, err
+	}
+
+	rebalinfo.Throttle = throttle
+	if err := txn.Ctx.Set("rinfo", rebalinfo); err != nil {
+		logger.WithError(err).Error("failed to set rebalance info in transaction context")
+		return nil, err
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to set rebalance throttle on volume")
+		return nil, err
+	}
+
+	logger.WithFields(log.Fields{
+		"volname":  volname,
+		"throttle": throttle,
+	}).Info("rebalance throttle set")
+	return rebalinfo, nil
+}
+
 func rebalanceStatusHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
+	logger := gdctx.Logger(ctx)
 
 	// collect inputs from url
 	volname := mux.Vars(r)["volname"]
@@ -308,6 +603,10 @@ func createRebalanceStatusResp(ctx transaction.TxnCtx, volinfo *volume.Volinfo)
 	// Fill common info
 	resp.Volname = volinfo.Name
 	resp.RebalanceID = rebalinfo.RebalanceID
+	resp.Mode = rebalinfo.Mode
+	resp.SkipRules = rebalinfo.SkipRules
+	resp.Paused = rebalinfo.State == rebalanceapi.Paused
+	resp.Throttle = rebalinfo.Throttle
 
 	// Get the status for the completed processes first
 	for _, tmp := range rebalinfo.RebalStats {
@@ -324,5 +623,12 @@ func createRebalanceStatusResp(ctx transaction.TxnCtx, volinfo *volume.Volinfo)
 
 		resp.Nodes = append(resp.Nodes, tmp)
 	}
+
+	// The rebalance completes when the slowest node completes
+	for _, n := range resp.Nodes {
+		if n.EstimatedTimeLeft > resp.EstimatedTimeLeft {
+			resp.EstimatedTimeLeft = n.EstimatedTimeLeft
+		}
+	}
 	return &resp, nil
 }
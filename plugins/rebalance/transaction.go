@@ -2,6 +2,8 @@ package rebalance
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/daemon"
@@ -13,6 +15,7 @@ import (
 	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
 type actionType uint16
@@ -46,7 +49,7 @@ func txnRebalanceStart(c transaction.TxnCtx) error {
 	if err != nil {
 		return err
 	}
-	err = volgen.VolumeVolfileToFile(&volinfo, rebalanceProcess.VolfileID, "rebalance")
+	err = generateRebalanceVolfile(&volinfo, &rinfo, rebalanceProcess.VolfileID)
 	if err != nil {
 		c.Logger().WithError(err).WithField(
 			"volfile", rebalanceProcess.VolfileID).Error("failed to generate volfile")
@@ -63,6 +66,70 @@ func txnRebalanceStart(c transaction.TxnCtx) error {
 	return nil
 }
 
+// generateRebalanceVolfile generates the rebalance volfile of the volume, with
+// the throttle of the rebalance
+func generateRebalanceVolfile(volinfo *volume.Volinfo, rinfo *rebalanceapi.RebalInfo, volfileID string) error {
+	if rinfo.Throttle != "" {
+		opts := make(map[string]string, len(volinfo.Options)+1)
+		for k, v := range volinfo.Options {
+			opts[k] = v
+		}
+		opts[throttleOptKey] = rinfo.Throttle
+		volinfo.Options = opts
+	}
+	return volgen.VolumeVolfileToFile(volinfo, volfileID, "rebalance")
+}
+
+// txnRebalanceThrottle regenerates the rebalance volfile with the new
+// throttle, and makes the rebalance process running on this node, if any,
+// fetch it again. glusterfs processes fetch their volfile again on SIGHUP.
+func txnRebalanceThrottle(c transaction.TxnCtx) error {
+	var rinfo rebalanceapi.RebalInfo
+	if err := c.Get("rinfo", &rinfo); err != nil {
+		return err
+	}
+
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	rebalanceProcess, err := NewRebalanceProcess(rinfo)
+	if err != nil {
+		return err
+	}
+
+	if err := generateRebalanceVolfile(&volinfo, &rinfo, rebalanceProcess.VolfileID); err != nil {
+		c.Logger().WithError(err).WithField(
+			"volfile", rebalanceProcess.VolfileID).Error("failed to generate volfile")
+		return err
+	}
+
+	if _, err := os.Stat(rebalanceProcess.PidFile()); os.IsNotExist(err) {
+		// The rebalance is not running on this node, the throttle
+		// is applied when it is started
+		return nil
+	}
+
+	return daemon.Signal(rebalanceProcess, unix.SIGHUP, c.Logger())
+}
+
+// localFileCount returns the number of files on the local bricks of the
+// volume, counting the inodes used on the filesystems of the bricks
+func localFileCount(volinfo *volume.Volinfo) uint64 {
+	var total uint64
+	seen := make(map[unix.Fsid]bool)
+	for _, b := range volinfo.GetLocalBricks() {
+		var st unix.Statfs_t
+		if err := unix.Statfs(b.Path, &st); err != nil || seen[st.Fsid] {
+			continue
+		}
+		seen[st.Fsid] = true
+		total += st.Files - st.Ffree
+	}
+	return total
+}
+
 func txnRebalanceStop(c transaction.TxnCtx) error {
 	var rebalinfo rebalanceapi.RebalInfo
 	err := c.Get("rinfo", &rebalinfo)
@@ -223,6 +290,12 @@ func txnRebalanceStatus(c transaction.TxnCtx) error {
 	rebalNodeStatus.ElapsedTime = rspDict["run-time"]
 	rebalNodeStatus.TimeLeft = rspDict["time-left"]
 
+	if volinfo, err := volume.GetVolume(volname); err == nil {
+		lookedup, _ := strconv.ParseUint(rebalNodeStatus.LookedupFiles, 10, 64)
+		elapsed, _ := strconv.ParseFloat(rebalNodeStatus.ElapsedTime, 64)
+		rebalNodeStatus.EstimatedTimeLeft = estimateTimeLeft(lookedup, elapsed, localFileCount(volinfo))
+	}
+
 	c.SetNodeResult(gdctx.MyUUID, rebalStatusTxnKey, rebalNodeStatus)
 	return nil
 
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/store"
@@ -14,6 +17,9 @@ import (
 
 const (
 	rebalancePrefix string = "rebalance/"
+	// throttleOptKey sets the throttle in the rebalance volfile only, so
+	// that it doesn't change the volfiles of the other clients
+	throttleOptKey = "rebalance.cluster/distribute.rebal-throttle"
 )
 
 var (
@@ -104,3 +110,53 @@ func getCmd(req *rebalanceapi.StartReq) rebalanceapi.Command {
 		return rebalanceapi.CmdNone
 	}
 }
+
+// validateSkipRules validates the skip rules of a rebalance started with the
+// given command. The paths are cleaned, as they are matched as prefixes of
+// file paths by the rebalance process.
+func validateSkipRules(cmd rebalanceapi.Command, rules *rebalanceapi.SkipRules) error {
+	if rules == nil {
+		return nil
+	}
+
+	if cmd == rebalanceapi.CmdFixLayoutStart {
+		return ErrSkipRulesWithFixLayout
+	}
+
+	for i, p := range rules.Paths {
+		if !path.IsAbs(p) {
+			return &skipRulesError{fmt.Sprintf("path %s must be absolute from the volume root", p)}
+		}
+		// The paths are passed to the rebalance process as a comma
+		// separated xlator option value
+		if strings.ContainsAny(p, ",=") {
+			return &skipRulesError{fmt.Sprintf("path %s cannot have ',' or '='", p)}
+		}
+		rules.Paths[i] = path.Clean(p)
+	}
+
+	if rules.MaxFileSize != 0 && rules.MinFileSize > rules.MaxFileSize {
+		return &skipRulesError{"minimum file size is more than the maximum file size"}
+	}
+
+	return nil
+}
+
+func validateThrottle(throttle string) error {
+	switch throttle {
+	case rebalanceapi.ThrottleLazy, rebalanceapi.ThrottleNormal, rebalanceapi.ThrottleAggressive:
+		return nil
+	}
+	return ErrRebalanceInvalidThrottle
+}
+
+// estimateTimeLeft estimates the time in seconds a rebalance process takes to
+// look up the rest of the files it has to, assuming it continues at the rate
+// it has looked them up so far. total is the number of files on the bricks
+// of the node. 0 is returned if no estimate can be made yet.
+func estimateTimeLeft(lookedup uint64, elapsed float64, total uint64) int64 {
+	if lookedup == 0 || elapsed <= 0 || lookedup >= total {
+		return 0
+	}
+	return int64(float64(total-lookedup) * elapsed / float64(lookedup))
+}
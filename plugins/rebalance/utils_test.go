@@ -0,0 +1,29 @@
+package rebalance
+
+import (
+	"testing"
+
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateThrottle(t *testing.T) {
+	for _, throttle := range []string{rebalanceapi.ThrottleLazy, rebalanceapi.ThrottleNormal, rebalanceapi.ThrottleAggressive} {
+		assert.Nil(t, validateThrottle(throttle))
+	}
+	assert.Equal(t, ErrRebalanceInvalidThrottle, validateThrottle(""))
+	assert.Equal(t, ErrRebalanceInvalidThrottle, validateThrottle("fast"))
+}
+
+func TestEstimateTimeLeft(t *testing.T) {
+	// 100 of 400 files in 60s leaves 300 files at the same rate
+	assert.Equal(t, int64(180), estimateTimeLeft(100, 60, 400))
+
+	// No estimate before any progress
+	assert.Equal(t, int64(0), estimateTimeLeft(0, 60, 400))
+	assert.Equal(t, int64(0), estimateTimeLeft(100, 0, 400))
+
+	// More files looked up than counted on the bricks
+	assert.Equal(t, int64(0), estimateTimeLeft(500, 60, 400))
+}
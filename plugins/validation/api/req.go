@@ -0,0 +1,28 @@
+package api
+
+import (
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Webhook is Structure to represent a webhook that will be called to
+// validate volume operations
+type Webhook struct {
+	URL   string `json:"url" valid:"required"`
+	Token string `json:"token,omitempty" store:"encrypt"`
+	// Ops are the volume operations (create, expand and option-set) for
+	// which the webhook is called. All operations are validated if empty.
+	Ops []string `json:"ops,omitempty"`
+}
+
+// WebhookDel is Structure to represent a webhook that will be used
+// for deleting webhook
+type WebhookDel struct {
+	URL string `json:"url" valid:"required"`
+}
+
+// ValidationReq is sent to a validation webhook with the proposed volume
+// information of a volume operation
+type ValidationReq struct {
+	Op     string         `json:"op"`
+	Volume api.VolumeInfo `json:"volume"`
+}
@@ -0,0 +1,11 @@
+package api
+
+// WebhookList holds list of registered validation webhooks
+type WebhookList []Webhook
+
+// ValidationResp is the response expected from a validation webhook. The
+// volume operation is vetoed if Allowed is false.
+type ValidationResp struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
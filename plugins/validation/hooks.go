@@ -0,0 +1,105 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	validationapi "github.com/gluster/glusterd2/plugins/validation/api"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const webhookTimeout = 10 * time.Second
+
+func webhookWants(w *validationapi.Webhook, op volume.ValidationOp) bool {
+	if len(w.Ops) == 0 {
+		return true
+	}
+	for _, o := range w.Ops {
+		if volume.ValidationOp(o) == op {
+			return true
+		}
+	}
+	return false
+}
+
+// callWebhook sends the proposed volume information to the webhook. An error
+// is returned if the webhook disallows the operation or could not be reached,
+// as a policy that can't be checked must not be bypassed.
+func callWebhook(w *validationapi.Webhook, req *validationapi.ValidationReq) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequest("POST", w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if w.Token != "" {
+		httpReq.Header.Set("Authorization", "bearer "+w.Token)
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("webhook %s could not be reached: %s", w.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("webhook %s responded with status %d", w.URL, resp.StatusCode)
+	}
+
+	var vresp validationapi.ValidationResp
+	if err := json.NewDecoder(resp.Body).Decode(&vresp); err != nil {
+		return fmt.Errorf("invalid response from webhook %s: %s", w.URL, err)
+	}
+	if !vresp.Allowed {
+		if vresp.Reason == "" {
+			return errors.New("no reason given")
+		}
+		return errors.New(vresp.Reason)
+	}
+	return nil
+}
+
+// validateWithWebhooks calls the registered validation webhooks for the volume
+// operation in turn, until one of them vetoes it
+func validateWithWebhooks(op volume.ValidationOp, v *volume.Volinfo) error {
+	webhooks, err := GetWebhookList()
+	if err != nil {
+		log.WithError(err).Error("error retrieving validation webhook list from store")
+		return err
+	}
+
+	req := &validationapi.ValidationReq{
+		Op:     string(op),
+		Volume: *volume.CreateVolumeInfoResp(v),
+	}
+
+	for _, w := range webhooks {
+		if !webhookWants(w, op) {
+			continue
+		}
+		if err := callWebhook(w, req); err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"webhook": w.URL,
+				"volume":  v.Name,
+				"op":      op,
+			}).Info("volume operation vetoed by validation webhook")
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	volume.RegisterValidator("webhooks", validateWithWebhooks)
+}
@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/utils"
+	validationapi "github.com/gluster/glusterd2/plugins/validation/api"
+)
+
+// Plugin is a structure which implements GlusterdPlugin interface
+type Plugin struct {
+}
+
+// Name returns name of plugin
+func (p *Plugin) Name() string {
+	return "validation"
+}
+
+// RestRoutes returns list of REST API routes to register with Glusterd
+func (p *Plugin) RestRoutes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:        "ValidationWebhookAdd",
+			Method:      "POST",
+			Pattern:     "/validation/webhook",
+			Version:     1,
+			RequestBody: (*validationapi.Webhook)(nil),
+			HandlerFunc: webhookAddHandler},
+		route.Route{
+			Name:        "ValidationWebhookDelete",
+			Method:      "DELETE",
+			Pattern:     "/validation/webhook",
+			Version:     1,
+			RequestBody: (*validationapi.WebhookDel)(nil),
+			HandlerFunc: webhookDeleteHandler},
+		route.Route{
+			Name:         "ValidationWebhookList",
+			Method:       "GET",
+			Pattern:      "/validation/webhook",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*validationapi.WebhookList)(nil)),
+			HandlerFunc:  webhookListHandler},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// Glusterd Transaction framework
+func (p *Plugin) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,89 @@
+package validation
+
+import (
+	"fmt"
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	validationapi "github.com/gluster/glusterd2/plugins/validation/api"
+)
+
+func validateWebhookOps(ops []string) error {
+	for _, op := range ops {
+		switch volume.ValidationOp(op) {
+		case volume.ValidateCreate, volume.ValidateExpand, volume.ValidateOptionSet:
+		default:
+			return fmt.Errorf("invalid volume operation %q", op)
+		}
+	}
+	return nil
+}
+
+func webhookAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := restutils.RequestBody(ctx).(*validationapi.Webhook)
+
+	if err := validateWebhookOps(req.Ops); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	exists, err := webhookExists(req.URL)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "could not check if webhook already exists")
+		return
+	}
+	if exists {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, "webhook already exists")
+		return
+	}
+
+	if err := addWebhook(*req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "could not add webhook")
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
+func webhookDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := restutils.RequestBody(ctx).(*validationapi.WebhookDel)
+
+	exists, err := webhookExists(req.URL)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "could not check if webhook exists")
+		return
+	}
+	if !exists {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, "webhook does not exist")
+		return
+	}
+
+	if err := deleteWebhook(req.URL); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "could not delete webhook")
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+func webhookListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	webhooks, err := GetWebhookList()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "could not retrieve webhook list")
+		return
+	}
+
+	resp := make(validationapi.WebhookList, 0, len(webhooks))
+	for _, wh := range webhooks {
+		// Do not expose the tokens used to authenticate with the webhooks
+		resp = append(resp, validationapi.Webhook{URL: wh.URL, Ops: wh.Ops})
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,72 @@
+package validation
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	validationapi "github.com/gluster/glusterd2/plugins/validation/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	webhookPrefix string = "config/validation/webhooks/"
+)
+
+func webhookKey(webhookURL string) string {
+	return webhookPrefix + strings.Replace(webhookURL, "/", "|", -1)
+}
+
+func webhookExists(webhookURL string) (bool, error) {
+	resp, e := store.Get(context.TODO(), webhookKey(webhookURL))
+	if e != nil {
+		log.WithError(e).Error("Couldn't retrive validation webhook from store")
+		return false, e
+	}
+	return resp.Count == 1, nil
+}
+
+// GetWebhookList returns list of all validation webhooks registered to
+// glusterd
+func GetWebhookList() ([]*validationapi.Webhook, error) {
+	resp, e := store.Get(context.TODO(), webhookPrefix, clientv3.WithPrefix())
+	if e != nil {
+		return nil, e
+	}
+
+	webhooks := make([]*validationapi.Webhook, 0, len(resp.Kvs))
+
+	for _, kv := range resp.Kvs {
+		var wh validationapi.Webhook
+
+		if err := store.Unmarshal(kv.Value, &wh); err != nil {
+			log.WithError(err).WithField("webhook", string(kv.Key)).Error("Failed to unmarshal validation webhook")
+			continue
+		}
+
+		webhooks = append(webhooks, &wh)
+	}
+
+	return webhooks, nil
+}
+
+func addWebhook(webhook validationapi.Webhook) error {
+	wh, e := store.Marshal(webhook)
+	if e != nil {
+		log.WithError(e).Error("Failed to marshal the validation webhook object")
+		return e
+	}
+
+	if _, err := store.Put(context.TODO(), webhookKey(webhook.URL), string(wh)); err != nil {
+		log.WithError(err).Error("Couldn't add validation webhook to store")
+		return err
+	}
+	return nil
+}
+
+func deleteWebhook(webhookURL string) error {
+	_, e := store.Delete(context.TODO(), webhookKey(webhookURL))
+	return e
+}